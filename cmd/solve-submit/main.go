@@ -0,0 +1,285 @@
+// Command solve-submit is a reference command-line client which submits
+// solutions to contests using /v0/cli endpoints.
+//
+// Usage:
+//
+//	solve-submit login --login user
+//	export SOLVE_TOKEN=...
+//	solve-submit contests
+//	solve-submit problems 1
+//	solve-submit submit 1 A solution.cpp
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/udovin/solve/internal/api"
+	"github.com/udovin/solve/internal/managers"
+)
+
+type submitContext struct {
+	Cmd    *cobra.Command
+	Args   []string
+	Client *api.Client
+}
+
+// getEndpoint returns endpoint from '--url' flag or SOLVE_URL variable.
+func getEndpoint(cmd *cobra.Command) (string, error) {
+	endpoint, err := cmd.Flags().GetString("url")
+	if err != nil {
+		return "", err
+	}
+	if endpoint == "" {
+		endpoint = os.Getenv("SOLVE_URL")
+	}
+	if endpoint == "" {
+		return "", fmt.Errorf("endpoint is not specified")
+	}
+	return strings.TrimSuffix(endpoint, "/") + "/api", nil
+}
+
+func wrapMain(
+	fn func(context.Context, *submitContext) error, requireToken bool,
+) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		endpoint, err := getEndpoint(cmd)
+		if err != nil {
+			return err
+		}
+		var options []api.ClientOption
+		token := must(cmd.Flags().GetString("token"))
+		if token == "" {
+			token = os.Getenv("SOLVE_TOKEN")
+		}
+		if token != "" {
+			options = append(options, api.WithToken(token))
+		} else if requireToken {
+			return fmt.Errorf("token is not specified, use login command")
+		}
+		ctx, cancel := signal.NotifyContext(
+			context.Background(), os.Interrupt, syscall.SIGTERM,
+		)
+		defer cancel()
+		return fn(ctx, &submitContext{
+			Cmd:    cmd,
+			Args:   args,
+			Client: api.NewClient(endpoint, options...),
+		})
+	}
+}
+
+func loginMain(ctx context.Context, c *submitContext) error {
+	login := must(c.Cmd.Flags().GetString("login"))
+	scopeID := must(c.Cmd.Flags().GetInt64("scope"))
+	password := os.Getenv("SOLVE_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	token, err := c.Client.CreateCLIToken(ctx, api.CLITokenForm{
+		ScopeID:  scopeID,
+		Login:    login,
+		Password: password,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to login: %w", err)
+	}
+	fmt.Println(token.Token)
+	return nil
+}
+
+func contestsMain(ctx context.Context, c *submitContext) error {
+	contests, err := c.Client.ObserveCLIContests(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to observe contests: %w", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, contest := range contests.Contests {
+		fmt.Fprintf(w, "%d\t%s\n", contest.ID, contest.Title)
+	}
+	return w.Flush()
+}
+
+func parseContestID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid contest ID: %q", arg)
+	}
+	return id, nil
+}
+
+func problemsMain(ctx context.Context, c *submitContext) error {
+	contestID, err := parseContestID(c.Args[0])
+	if err != nil {
+		return err
+	}
+	contest, err := c.Client.ObserveCLIContest(ctx, contestID)
+	if err != nil {
+		return fmt.Errorf("unable to observe contest: %w", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Problems:")
+	for _, problem := range contest.Problems {
+		fmt.Fprintf(w, "  %s\t%s\n", problem.Code, problem.Title)
+	}
+	fmt.Fprintln(w, "Compilers:")
+	for _, compiler := range contest.Compilers {
+		fmt.Fprintf(
+			w, "  %d\t%s\t%s\n", compiler.ID, compiler.Name,
+			strings.Join(compiler.Extensions, ","),
+		)
+	}
+	return w.Flush()
+}
+
+func printVerdict(verdict api.CLIVerdict) {
+	status := verdict.Verdict
+	if status == "" {
+		status = "queued"
+	}
+	if verdict.Stage != "" {
+		status += " (" + verdict.Stage + ")"
+	}
+	if verdict.TestNumber > 0 {
+		status += fmt.Sprintf(" on test %d", verdict.TestNumber)
+	}
+	if verdict.Final {
+		if verdict.Points != nil {
+			status += fmt.Sprintf(", points: %g", *verdict.Points)
+		}
+		if verdict.UsedTime > 0 || verdict.UsedMemory > 0 {
+			status += fmt.Sprintf(
+				", time: %dms, memory: %dKiB",
+				verdict.UsedTime, verdict.UsedMemory/1024,
+			)
+		}
+	}
+	fmt.Println(status)
+	if verdict.Final && verdict.CompileLog != "" {
+		fmt.Println(verdict.CompileLog)
+	}
+}
+
+func submitMain(ctx context.Context, c *submitContext) error {
+	contestID, err := parseContestID(c.Args[0])
+	if err != nil {
+		return err
+	}
+	problem, path := c.Args[1], c.Args[2]
+	compilerID := must(c.Cmd.Flags().GetInt64("compiler"))
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	solution, err := c.Client.SubmitContestSolution(
+		ctx, contestID, problem, api.SubmitSolutionForm{
+			CompilerID: compilerID,
+			ContentFile: &managers.FileReader{
+				Name:   filepath.Base(path),
+				Size:   stat.Size(),
+				Reader: file,
+			},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to submit solution: %w", err)
+	}
+	fmt.Printf("Submitted solution %d\n", solution.ID)
+	if must(c.Cmd.Flags().GetBool("no-wait")) {
+		return nil
+	}
+	// Verdict stream is closed by server after timeout, so reconnect
+	// until judgement is finished.
+	for {
+		final := false
+		if err := c.Client.WatchContestSolution(
+			ctx, contestID, solution.ID, func(verdict api.CLIVerdict) error {
+				printVerdict(verdict)
+				final = verdict.Final
+				return nil
+			},
+		); err != nil {
+			return fmt.Errorf("unable to watch solution: %w", err)
+		}
+		if final {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func main() {
+	rootCmd := cobra.Command{Use: os.Args[0], SilenceUsage: true}
+	rootCmd.PersistentFlags().String("url", "", "URL of Solve instance (SOLVE_URL)")
+	rootCmd.PersistentFlags().String("token", "", "Authorization token (SOLVE_TOKEN)")
+	// login.
+	loginCmd := cobra.Command{
+		Use:   "login",
+		RunE:  wrapMain(loginMain, false),
+		Args:  cobra.NoArgs,
+		Short: "Prints new authorization token",
+	}
+	loginCmd.Flags().String("login", "", "Login of account")
+	loginCmd.Flags().Int64("scope", 0, "Scope of account")
+	loginCmd.MarkFlagRequired("login")
+	rootCmd.AddCommand(&loginCmd)
+	// contests.
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "contests",
+		RunE:  wrapMain(contestsMain, true),
+		Args:  cobra.NoArgs,
+		Short: "Lists contests available for submission",
+	})
+	// problems.
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "problems contest",
+		RunE:  wrapMain(problemsMain, true),
+		Args:  cobra.ExactArgs(1),
+		Short: "Lists problems and compilers of contest",
+	})
+	// submit.
+	submitCmd := cobra.Command{
+		Use:   "submit contest problem file",
+		RunE:  wrapMain(submitMain, true),
+		Args:  cobra.ExactArgs(3),
+		Short: "Submits solution and waits for verdict",
+	}
+	submitCmd.Flags().Int64("compiler", 0, "Compiler ID (detected by default)")
+	submitCmd.Flags().Bool("no-wait", false, "Do not wait for verdict")
+	rootCmd.AddCommand(&submitCmd)
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -3,10 +3,15 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -14,6 +19,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/acme/autocert"
 
 	"github.com/udovin/solve/internal/api"
 	"github.com/udovin/solve/internal/api/ccs"
@@ -21,8 +27,11 @@ import (
 	"github.com/udovin/solve/internal/core"
 	"github.com/udovin/solve/internal/db"
 	"github.com/udovin/solve/internal/invoker"
+	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/pkg/logs"
+	"github.com/udovin/solve/internal/rpc"
 )
 
 var testCtx, testCancel = context.WithCancel(context.Background())
@@ -68,13 +77,122 @@ func newServer(logger *logs.Logger) *echo.Echo {
 	return srv
 }
 
+// newServerMiddlewares returns middlewares that add CORS and security
+// headers configured in server config.
+func newServerMiddlewares(cfg config.Server) []echo.MiddlewareFunc {
+	var result []echo.MiddlewareFunc
+	if cfg.CORS != nil {
+		result = append(result, middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:     cfg.CORS.AllowOrigins,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		}))
+	}
+	if cfg.Headers != nil {
+		frameOptions := cfg.Headers.FrameOptions
+		if frameOptions == "" {
+			frameOptions = "SAMEORIGIN"
+		}
+		result = append(result, middleware.SecureWithConfig(middleware.SecureConfig{
+			XSSProtection:         "1; mode=block",
+			ContentTypeNosniff:    "nosniff",
+			XFrameOptions:         frameOptions,
+			HSTSMaxAge:            cfg.Headers.HSTSMaxAge,
+			HSTSExcludeSubdomains: !cfg.Headers.HSTSIncludeSubdomains,
+			ContentSecurityPolicy: cfg.Headers.ContentSecurityPolicy,
+		}))
+	}
+	return result
+}
+
+// staticCacheControl contains Cache-Control header for frontend files
+// except index.html that should be always revalidated.
+const staticCacheControl = "public, max-age=86400"
+
+// newStaticHandler returns handler that serves frontend files from
+// specified directory.
+//
+// Unknown paths are served with index.html, so client-side routing
+// works after page reload.
+func newStaticHandler(dir string) echo.HandlerFunc {
+	root := http.Dir(dir)
+	return func(c echo.Context) error {
+		name := path.Clean("/" + c.Param("*"))
+		if name == "/api" || strings.HasPrefix(name, "/api/") {
+			return echo.ErrNotFound
+		}
+		if name != "/" && name != "/index.html" {
+			if file, err := root.Open(name); err == nil {
+				stat, err := file.Stat()
+				_ = file.Close()
+				if err == nil && !stat.IsDir() {
+					c.Response().Header().Set(echo.HeaderCacheControl, staticCacheControl)
+					return c.File(filepath.Join(dir, filepath.FromSlash(name)))
+				}
+			}
+		}
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+		return c.File(filepath.Join(dir, "index.html"))
+	}
+}
+
+// setupACME configures automatic certificate provisioning and returns
+// server that handles HTTP-01 challenges.
+func setupACME(srv *echo.Echo, cfg config.ACME) *http.Server {
+	srv.AutoTLSManager.Prompt = autocert.AcceptTOS
+	srv.AutoTLSManager.HostPolicy = autocert.HostWhitelist(cfg.Hosts...)
+	srv.AutoTLSManager.Email = cfg.Email
+	if cfg.CacheDir != "" {
+		srv.AutoTLSManager.Cache = autocert.DirCache(cfg.CacheDir)
+	}
+	address := cfg.HTTPAddress
+	if address == "" {
+		address = ":80"
+	}
+	return &http.Server{
+		Addr:              address,
+		Handler:           srv.AutoTLSManager.HTTPHandler(nil),
+		ReadHeaderTimeout: time.Minute,
+	}
+}
+
+// defaultReadHeaderTimeout contains duration that client has to send
+// request headers if it is not configured.
+const defaultReadHeaderTimeout = 30 * time.Second
+
+// setupServerTimeouts configures timeouts of reading requests, so slow
+// clients can not hold connections forever.
+//
+// Timeouts of reading request body are configured per route in API.
+func setupServerTimeouts(srv *echo.Echo, cfg config.Server) {
+	timeout := defaultReadHeaderTimeout
+	if cfg.Limits != nil && cfg.Limits.ReadHeaderTimeout > 0 {
+		timeout = time.Duration(cfg.Limits.ReadHeaderTimeout) * time.Second
+	}
+	srv.Server.ReadHeaderTimeout = timeout
+	srv.TLSServer.ReadHeaderTimeout = timeout
+}
+
+// startServer starts server with HTTPS if TLS is configured.
+func startServer(srv *echo.Echo, cfg config.Server) error {
+	switch {
+	case cfg.TLS == nil:
+		return srv.Start(cfg.Address())
+	case cfg.TLS.ACME != nil:
+		return srv.StartAutoTLS(cfg.Address())
+	default:
+		return srv.StartTLS(cfg.Address(), cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	}
+}
+
 // serverMain starts Solve server.
 //
 // Simply speaking this function does following things:
 //  1. Setup Core instance (with all managers).
 //  2. Setup Echo server instance (HTTP + unix socket).
 //  3. Register API View to Echo server.
-//  4. Start Invoker server.
+//  4. Start gRPC service for invokers.
+//  5. Start Invoker server.
 func serverMain(cmd *cobra.Command, _ []string) {
 	cfg, err := getConfig(cmd)
 	if err != nil {
@@ -103,6 +221,24 @@ func serverMain(cmd *cobra.Command, _ []string) {
 		testCtx, os.Interrupt, syscall.SIGTERM,
 	)
 	defer cancel()
+	// SIGUSR1 enables read-only mode and SIGUSR2 disables it.
+	readOnlySignals := make(chan os.Signal, 1)
+	signal.Notify(readOnlySignals, syscall.SIGUSR1, syscall.SIGUSR2)
+	defer signal.Stop(readOnlySignals)
+	waiter.Add(1)
+	go func() {
+		defer waiter.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-readOnlySignals:
+				readOnly := sig == syscall.SIGUSR1
+				v.SetReadOnly(readOnly)
+				c.Logger().Info("Read-only mode changed", logs.Any("read_only", readOnly))
+			}
+		}
+	}()
 	if file := cfg.SocketFile; file != "" {
 		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
 			panic(err)
@@ -128,17 +264,44 @@ func serverMain(cmd *cobra.Command, _ []string) {
 	}
 	if cfg.Server != nil {
 		srv := newServer(c.Logger())
+		setupServerTimeouts(srv, *cfg.Server)
+		srv.Use(newServerMiddlewares(*cfg.Server)...)
 		v.Register(srv.Group("/api"))
 		v.StartDaemons()
 		ccs.NewView(c).Register(srv.Group("/api/ccs"))
+		if dir := cfg.Server.StaticDir; dir != "" {
+			srv.Match(
+				[]string{http.MethodGet, http.MethodHead}, "/*",
+				newStaticHandler(dir),
+			)
+		}
+		var challengeSrv *http.Server
+		if tls := cfg.Server.TLS; tls != nil && tls.ACME != nil {
+			challengeSrv = setupACME(srv, *tls.ACME)
+		}
 		waiter.Add(1)
 		go func() {
 			defer waiter.Done()
 			defer cancel()
-			if err := srv.Start(cfg.Server.Address()); isServerError(err) {
+			if err := startServer(srv, *cfg.Server); isServerError(err) {
 				c.Logger().Error(err)
 			}
 		}()
+		if challengeSrv != nil {
+			waiter.Add(1)
+			go func() {
+				defer waiter.Done()
+				defer cancel()
+				if err := challengeSrv.ListenAndServe(); isServerError(err) {
+					c.Logger().Error(err)
+				}
+			}()
+			defer func() {
+				if err := challengeSrv.Shutdown(context.Background()); err != nil {
+					c.Logger().Error(err)
+				}
+			}()
+		}
 		defer func() {
 			ctx, cancel := context.WithTimeout(
 				context.Background(), time.Minute,
@@ -148,6 +311,22 @@ func serverMain(cmd *cobra.Command, _ []string) {
 				c.Logger().Error(err)
 			}
 		}()
+		if serviceCfg := cfg.Server.InvokerService; serviceCfg != nil {
+			listener, err := net.Listen("tcp", serviceCfg.Address)
+			if err != nil {
+				panic(err)
+			}
+			rpcSrv := rpc.NewServer(c, *serviceCfg)
+			waiter.Add(1)
+			go func() {
+				defer waiter.Done()
+				defer cancel()
+				if err := rpcSrv.Serve(listener); err != nil {
+					c.Logger().Error(err)
+				}
+			}()
+			defer rpcSrv.GracefulStop()
+		}
 	}
 	if cfg.Invoker != nil {
 		if err := invoker.New(c).Start(); err != nil {
@@ -194,6 +373,51 @@ func migrateMain(cmd *cobra.Command, args []string) {
 	); err != nil {
 		panic(err)
 	}
+	if len(args) > 0 {
+		return
+	}
+	// After full migration instance is bootstrapped, so it is usable
+	// immediately.
+	if err := db.ApplyMigrations(
+		context.Background(), c.DB, "solve_data", migrations.Data,
+	); err != nil {
+		panic(err)
+	}
+	if err := c.Start(); err != nil {
+		panic(err)
+	}
+	defer c.Stop()
+	if err := managers.NewBootstrapManager(c).Bootstrap(
+		context.Background(), getBootstrapConfig(cfg),
+	); err != nil {
+		panic(err)
+	}
+}
+
+// getBootstrapConfig returns bootstrap config with admin credentials
+// overridden by environment variables.
+func getBootstrapConfig(cfg config.Config) config.Bootstrap {
+	var result config.Bootstrap
+	if cfg.Bootstrap != nil {
+		result = *cfg.Bootstrap
+	}
+	admin := config.BootstrapAdmin{}
+	if result.Admin != nil {
+		admin = *result.Admin
+	}
+	if login := os.Getenv("SOLVE_ADMIN_LOGIN"); login != "" {
+		admin.Login = login
+	}
+	if password := os.Getenv("SOLVE_ADMIN_PASSWORD"); password != "" {
+		admin.Password = password
+	}
+	if email := os.Getenv("SOLVE_ADMIN_EMAIL"); email != "" {
+		admin.Email = email
+	}
+	if admin.Login != "" {
+		result.Admin = &admin
+	}
+	return result
 }
 
 func migrateDataMain(cmd *cobra.Command, args []string) {
@@ -232,6 +456,139 @@ func migrateDataMain(cmd *cobra.Command, args []string) {
 	}
 }
 
+func backupMain(cmd *cobra.Command, args []string) {
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		panic(err)
+	}
+	c, err := core.NewCore(cfg)
+	if err != nil {
+		panic(err)
+	}
+	c.SetupAllStores()
+	backups := managers.NewBackupManager(c)
+	if len(args) > 0 {
+		file, err := os.Create(args[0])
+		if err != nil {
+			panic(err)
+		}
+		defer func() { _ = file.Close() }()
+		if err := backups.Backup(context.Background(), file); err != nil {
+			panic(err)
+		}
+		return
+	}
+	if cfg.Backup == nil {
+		panic("section 'backup' should be configured")
+	}
+	path, err := backups.BackupToDir(
+		context.Background(), cfg.Backup.Dir, cfg.Backup.KeepLast,
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "backup created:", path)
+}
+
+func restoreMain(cmd *cobra.Command, args []string) {
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		panic(err)
+	}
+	if !force {
+		panic("Trying to restore backup without '--force'")
+	}
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		panic(err)
+	}
+	c, err := core.NewCore(cfg)
+	if err != nil {
+		panic(err)
+	}
+	c.SetupAllStores()
+	file, err := os.Open(args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = file.Close() }()
+	stat, err := file.Stat()
+	if err != nil {
+		panic(err)
+	}
+	if err := managers.NewBackupManager(c).Restore(
+		context.Background(), file, stat.Size(),
+	); err != nil {
+		panic(err)
+	}
+}
+
+// newContestExportManager creates started core and manager for
+// exporting contests.
+func newContestExportManager(cmd *cobra.Command) (*core.Core, *managers.ContestExportManager) {
+	cfg, err := getConfig(cmd)
+	if err != nil {
+		panic(err)
+	}
+	c, err := core.NewCore(cfg)
+	if err != nil {
+		panic(err)
+	}
+	c.SetupAllStores()
+	if err := c.Start(); err != nil {
+		panic(err)
+	}
+	var files *managers.FileManager
+	if cfg.Storage != nil {
+		files = managers.NewFileManager(c)
+	}
+	return c, managers.NewContestExportManager(c, files)
+}
+
+func exportContestMain(cmd *cobra.Command, args []string) {
+	contestID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	c, exports := newContestExportManager(cmd)
+	defer c.Stop()
+	file, err := os.Create(args[1])
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = file.Close() }()
+	if err := exports.ExportContest(context.Background(), contestID, file); err != nil {
+		panic(err)
+	}
+}
+
+func importContestMain(cmd *cobra.Command, args []string) {
+	owner, err := cmd.Flags().GetString("owner")
+	if err != nil {
+		panic(err)
+	}
+	c, exports := newContestExportManager(cmd)
+	defer c.Stop()
+	var ownerID models.NInt64
+	if owner != "" {
+		user, err := c.Users.GetByLogin(context.Background(), owner)
+		if err != nil {
+			panic(err)
+		}
+		ownerID = models.NInt64(user.ID)
+	}
+	file, err := os.Open(args[0])
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = file.Close() }()
+	contest, err := exports.ImportContest(context.Background(), file, ownerID)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "contest imported:", contest.ID)
+}
+
 func versionMain(cmd *cobra.Command, _ []string) {
 	println("solve version:", config.Version)
 }
@@ -275,6 +632,38 @@ func main() {
 	migrateDataCmd.Flags().String("from", "", "Repeat migrations from specified name")
 	migrateDataCmd.Flags().Bool("force", false, "Force dangerous migration")
 	rootCmd.AddCommand(&migrateDataCmd)
+	// backup.
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "backup [file]",
+		Run:   backupMain,
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Creates backup of database",
+	})
+	// restore.
+	restoreCmd := cobra.Command{
+		Use:   "restore file",
+		Run:   restoreMain,
+		Args:  cobra.ExactArgs(1),
+		Short: "Restores database from backup",
+	}
+	restoreCmd.Flags().Bool("force", false, "Confirm replacing of all data")
+	rootCmd.AddCommand(&restoreCmd)
+	// export-contest.
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "export-contest id file",
+		Run:   exportContestMain,
+		Args:  cobra.ExactArgs(2),
+		Short: "Exports contest into archive",
+	})
+	// import-contest.
+	importContestCmd := cobra.Command{
+		Use:   "import-contest file",
+		Run:   importContestMain,
+		Args:  cobra.ExactArgs(1),
+		Short: "Imports contest from archive",
+	}
+	importContestCmd.Flags().String("owner", "", "Login of contest owner")
+	rootCmd.AddCommand(&importContestCmd)
 	// version.
 	rootCmd.AddCommand(&cobra.Command{
 		Use:   "version",
@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/spf13/cobra"
 	"github.com/udovin/solve/internal/config"
 	"github.com/udovin/solve/internal/core"
@@ -136,3 +140,144 @@ func TestCommand(t *testing.T) {
 	main()
 	os.Args = args
 }
+
+func TestBackupRestoreMain(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	backupFile := filepath.Join(t.TempDir(), "backup.zip")
+	cmd := cobra.Command{}
+	cmd.Flags().String("config", "", "")
+	cmd.Flags().Bool("force", false, "")
+	cmd.Flags().Set("config", testConfigFile.Name())
+	backupMain(&cmd, []string{backupFile})
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("Expected panic")
+			}
+		}()
+		restoreMain(&cmd, []string{backupFile})
+	}()
+	cmd.Flags().Set("force", "true")
+	restoreMain(&cmd, []string{backupFile})
+}
+
+func TestServerMiddlewares(t *testing.T) {
+	srv := echo.New()
+	srv.Use(newServerMiddlewares(config.Server{
+		CORS: &config.CORS{
+			AllowOrigins:     []string{"https://example.com"},
+			AllowCredentials: true,
+		},
+		Headers: &config.SecurityHeaders{
+			ContentSecurityPolicy: "default-src 'self'",
+		},
+	})...)
+	srv.GET("/ping", func(c echo.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://example.com")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if v := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); v != "https://example.com" {
+		t.Fatalf("Unexpected allowed origin: %q", v)
+	}
+	if v := rec.Header().Get(echo.HeaderAccessControlAllowCredentials); v != "true" {
+		t.Fatalf("Unexpected allow credentials: %q", v)
+	}
+	if v := rec.Header().Get(echo.HeaderXFrameOptions); v != "SAMEORIGIN" {
+		t.Fatalf("Unexpected frame options: %q", v)
+	}
+	if v := rec.Header().Get(echo.HeaderContentSecurityPolicy); v != "default-src 'self'" {
+		t.Fatalf("Unexpected content security policy: %q", v)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://evil.com")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if v := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); v != "" {
+		t.Fatalf("Unexpected allowed origin: %q", v)
+	}
+}
+
+func TestSetupACME(t *testing.T) {
+	srv := echo.New()
+	challengeSrv := setupACME(srv, config.ACME{
+		Hosts:    []string{"example.com"},
+		CacheDir: t.TempDir(),
+	})
+	if challengeSrv.Addr != ":80" {
+		t.Fatalf("Unexpected address: %q", challengeSrv.Addr)
+	}
+	if err := srv.AutoTLSManager.HostPolicy(context.Background(), "example.com"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := srv.AutoTLSManager.HostPolicy(context.Background(), "evil.com"); err == nil {
+		t.Fatal("Expected error")
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/index.html", nil)
+	rec := httptest.NewRecorder()
+	challengeSrv.Handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusFound {
+		t.Fatalf("Unexpected status: %d", rec.Code)
+	}
+	if v := rec.Header().Get(echo.HeaderLocation); v != "https://example.com/index.html" {
+		t.Fatalf("Unexpected location: %q", v)
+	}
+}
+
+func TestSetupServerTimeouts(t *testing.T) {
+	srv := echo.New()
+	setupServerTimeouts(srv, config.Server{})
+	if srv.Server.ReadHeaderTimeout != defaultReadHeaderTimeout {
+		t.Fatalf("Unexpected timeout: %v", srv.Server.ReadHeaderTimeout)
+	}
+	setupServerTimeouts(srv, config.Server{
+		Limits: &config.ServerLimits{ReadHeaderTimeout: 5},
+	})
+	if srv.TLSServer.ReadHeaderTimeout != 5*time.Second {
+		t.Fatalf("Unexpected timeout: %v", srv.TLSServer.ReadHeaderTimeout)
+	}
+}
+
+func TestStaticHandler(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("index"), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.js"), []byte("app"), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	srv := echo.New()
+	srv.GET("/api/ping", func(c echo.Context) error {
+		return c.String(http.StatusOK, "pong")
+	})
+	srv.Match([]string{http.MethodGet, http.MethodHead}, "/*", newStaticHandler(dir))
+	for _, test := range []struct {
+		Path         string
+		Status       int
+		Body         string
+		CacheControl string
+	}{
+		{"/", http.StatusOK, "index", "no-cache"},
+		{"/app.js", http.StatusOK, "app", staticCacheControl},
+		{"/contests/1", http.StatusOK, "index", "no-cache"},
+		{"/../main.go", http.StatusOK, "index", "no-cache"},
+		{"/api/ping", http.StatusOK, "pong", ""},
+		{"/api/unknown", http.StatusNotFound, "", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != test.Status {
+			t.Fatalf("Unexpected status for %q: %d", test.Path, rec.Code)
+		}
+		if test.Body != "" && rec.Body.String() != test.Body {
+			t.Fatalf("Unexpected body for %q: %q", test.Path, rec.Body.String())
+		}
+		if v := rec.Header().Get(echo.HeaderCacheControl); v != test.CacheControl {
+			t.Fatalf("Unexpected cache control for %q: %q", test.Path, v)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerAccountMergeHandlers(g *echo.Group) {
+	if v.core.Users == nil {
+		return
+	}
+	g.POST(
+		"/v0/accounts/merge", v.mergeAccounts,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.MergeAccountsRole),
+	)
+}
+
+// MergeAccountsForm represents form for merging duplicate accounts.
+type MergeAccountsForm struct {
+	// SourceID contains ID of account that will be merged and blocked.
+	SourceID int64 `json:"source_id"`
+	// TargetID contains ID of account that receives all objects.
+	TargetID int64 `json:"target_id"`
+}
+
+// AccountMerge represents result of accounts merge.
+type AccountMerge struct {
+	ID       int64                      `json:"id"`
+	Time     int64                      `json:"time"`
+	SourceID int64                      `json:"source_id"`
+	TargetID int64                      `json:"target_id"`
+	Summary  models.AccountMergeSummary `json:"summary"`
+}
+
+func (v *View) getMergeUser(
+	c echo.Context, id int64, field string, errors errorFields,
+) (models.User, error) {
+	user, err := v.core.Users.Get(getContext(c), id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return models.User{}, err
+		}
+		errors[field] = errorField{
+			Message: localize(c, "User {id} not found.", replaceField("id", id)),
+		}
+	}
+	return user, nil
+}
+
+// mergeAccounts moves solutions, participants, sessions, roles and group
+// memberships of source user to target user and blocks source user.
+//
+// Conflicts are resolved in favor of target account: solutions of
+// duplicate participants are moved to participant of target account,
+// duplicate roles and group memberships are removed.
+func (v *View) mergeAccounts(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("auth not extracted")
+	}
+	var form MergeAccountsForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := syncStore(c, v.core.Users); err != nil {
+		return err
+	}
+	errors := errorFields{}
+	source, err := v.getMergeUser(c, form.SourceID, "source_id", errors)
+	if err != nil {
+		return err
+	}
+	target, err := v.getMergeUser(c, form.TargetID, "target_id", errors)
+	if err != nil {
+		return err
+	}
+	if len(errors) == 0 && source.ID == target.ID {
+		errors["target_id"] = errorField{
+			Message: localize(c, "Account cannot be merged with itself."),
+		}
+	}
+	if len(errors) == 0 && source.Status == models.BlockedUser {
+		errors["source_id"] = errorField{
+			Message: localize(c, "User is blocked."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	for _, store := range []models.CachedStore{
+		v.core.Solutions, v.core.ContestParticipants, v.core.ContestSolutions,
+		v.core.Sessions, v.core.AccountRoles, v.core.GroupMembers,
+	} {
+		if err := syncStore(c, store); err != nil {
+			return err
+		}
+	}
+	merge := models.AccountMerge{
+		Time:     getNow(c).Unix(),
+		SourceID: source.ID,
+		TargetID: target.ID,
+		AuthorID: models.NInt64(accountCtx.Account.ID),
+	}
+	var summary models.AccountMergeSummary
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		summary = models.AccountMergeSummary{}
+		if err := v.mergeAccountObjects(ctx, source.ID, target.ID, &summary); err != nil {
+			return err
+		}
+		source.Status = models.BlockedUser
+		if err := v.core.Users.Update(ctx, source); err != nil {
+			return err
+		}
+		if err := merge.SetSummary(summary); err != nil {
+			return err
+		}
+		return v.core.AccountMerges.Create(ctx, &merge)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	v.core.Logger().Info(
+		"Accounts merged",
+		logs.Any("source_id", source.ID),
+		logs.Any("target_id", target.ID),
+		logs.Any("author_id", accountCtx.Account.ID),
+	)
+	return c.JSON(http.StatusOK, AccountMerge{
+		ID:       merge.ID,
+		Time:     merge.Time,
+		SourceID: merge.SourceID,
+		TargetID: merge.TargetID,
+		Summary:  summary,
+	})
+}
+
+func (v *View) mergeAccountObjects(
+	ctx context.Context, sourceID, targetID int64,
+	summary *models.AccountMergeSummary,
+) error {
+	solutions, err := collectRows(v.core.Solutions.FindByAuthor(ctx, sourceID))
+	if err != nil {
+		return err
+	}
+	for _, solution := range solutions {
+		solution.AuthorID = targetID
+		if err := v.core.Solutions.Update(ctx, solution); err != nil {
+			return err
+		}
+		summary.Solutions++
+	}
+	participants, err := collectRows(v.core.ContestParticipants.FindByAccount(ctx, sourceID))
+	if err != nil {
+		return err
+	}
+	for _, participant := range participants {
+		if err := v.mergeContestParticipant(ctx, participant, targetID); err != nil {
+			return err
+		}
+		summary.Participants++
+	}
+	sessions, err := collectRows(v.core.Sessions.FindByAccount(sourceID))
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		session.AccountID = targetID
+		if err := v.core.Sessions.Update(ctx, session); err != nil {
+			return err
+		}
+		summary.Sessions++
+	}
+	targetRoles, err := collectRows(v.core.AccountRoles.FindByAccount(ctx, targetID))
+	if err != nil {
+		return err
+	}
+	hasRole := map[int64]struct{}{}
+	for _, role := range targetRoles {
+		hasRole[role.RoleID] = struct{}{}
+	}
+	roles, err := collectRows(v.core.AccountRoles.FindByAccount(ctx, sourceID))
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if _, ok := hasRole[role.RoleID]; ok {
+			if err := v.core.AccountRoles.Delete(ctx, role.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		role.AccountID = targetID
+		if err := v.core.AccountRoles.Update(ctx, role); err != nil {
+			return err
+		}
+		summary.Roles++
+	}
+	targetMembers, err := collectRows(v.core.GroupMembers.FindByAccount(ctx, targetID))
+	if err != nil {
+		return err
+	}
+	hasGroup := map[int64]struct{}{}
+	for _, member := range targetMembers {
+		hasGroup[member.GroupID] = struct{}{}
+	}
+	members, err := collectRows(v.core.GroupMembers.FindByAccount(ctx, sourceID))
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if _, ok := hasGroup[member.GroupID]; ok {
+			if err := v.core.GroupMembers.Delete(ctx, member.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		member.AccountID = targetID
+		if err := v.core.GroupMembers.Update(ctx, member); err != nil {
+			return err
+		}
+		summary.GroupMembers++
+	}
+	return nil
+}
+
+// mergeContestParticipant moves participant to target account.
+//
+// If target account already has participant of the same kind, solutions
+// are moved to existing participant and source participant is removed.
+func (v *View) mergeContestParticipant(
+	ctx context.Context, participant models.ContestParticipant, targetID int64,
+) error {
+	existing, err := collectRows(v.core.ContestParticipants.FindByContestAccount(
+		ctx, participant.ContestID, targetID,
+	))
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.Kind != participant.Kind {
+			continue
+		}
+		solutions, err := collectRows(v.core.ContestSolutions.FindByParticipant(
+			ctx, participant.ID,
+		))
+		if err != nil {
+			return err
+		}
+		for _, solution := range solutions {
+			solution.ParticipantID = other.ID
+			if err := v.core.ContestSolutions.Update(ctx, solution); err != nil {
+				return err
+			}
+		}
+		return v.core.ContestParticipants.Delete(ctx, participant.ID)
+	}
+	participant.AccountID = targetID
+	return v.core.ContestParticipants.Update(ctx, participant)
+}
+
+func collectRows[T any](rows db.Rows[T], err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return db.CollectRows(rows)
+}
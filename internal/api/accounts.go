@@ -37,8 +37,9 @@ type accountFilter struct {
 func (f *accountFilter) Parse(c echo.Context) error {
 	if err := c.Bind(f); err != nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid filter."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
 		}
 	}
 	if f.BeginID < 0 || f.BeginID == math.MaxInt64 {
@@ -146,8 +147,9 @@ func (v *View) observeAccounts(c echo.Context) error {
 	if err := filter.Parse(c); err != nil {
 		c.Logger().Warn(err)
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 		}
 	}
 	var resp Accounts
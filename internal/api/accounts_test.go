@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"database/sql"
+	"net/http"
 	"testing"
 
+	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/perms"
 )
 
@@ -21,3 +24,106 @@ func TestAccountsSimpleScenario(t *testing.T) {
 		e.Check(accounts)
 	}
 }
+
+func TestMergeAccounts(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	admin := NewTestUser(e)
+	admin.AddRoles(perms.MergeAccountsRole, "create_compiler", "create_setting")
+	source := NewTestUser(e)
+	source.AddRoles("create_contest")
+	target := NewTestUser(e)
+	target.AddRoles("create_contest")
+	admin.LoginClient()
+	compiler := NewTestCompiler(e)
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest := models.Contest{Title: "Test contest"}
+	if err := e.Core.Contests.Create(ctx, &contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	var participants []models.ContestParticipant
+	for _, id := range []int64{source.ID, target.ID} {
+		participant := models.ContestParticipant{
+			ContestID: contest.ID,
+			AccountID: id,
+			Kind:      models.RegularParticipant,
+		}
+		if err := e.Core.ContestParticipants.Create(ctx, &participant); err != nil {
+			t.Fatal("Error:", err)
+		}
+		participants = append(participants, participant)
+	}
+	solution := models.Solution{
+		Kind:       models.ContestSolutionKind,
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   source.ID,
+		CreateTime: e.Now.Unix(),
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     contest.ID,
+		ParticipantID: participants[0].ID,
+		ProblemID:     contestProblem.ID,
+	}
+	contestSolution.ID = solution.ID
+	if err := e.Core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.MergeAccounts(ctx, MergeAccountsForm{
+		SourceID: source.ID, TargetID: source.ID,
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	merge, err := e.Client.MergeAccounts(ctx, MergeAccountsForm{
+		SourceID: source.ID, TargetID: target.ID,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	admin.LogoutClient()
+	if merge.Summary.Solutions != 1 || merge.Summary.Participants != 1 {
+		t.Fatalf("Unexpected summary: %+v", merge.Summary)
+	}
+	e.SyncStores()
+	if err := e.Core.Users.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if solution, err := e.Core.Solutions.Get(ctx, solution.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if solution.AuthorID != target.ID {
+		t.Fatalf("Expected author %d, got %d", target.ID, solution.AuthorID)
+	}
+	if solution, err := e.Core.ContestSolutions.Get(ctx, solution.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if solution.ParticipantID != participants[1].ID {
+		t.Fatalf("Expected participant %d, got %d", participants[1].ID, solution.ParticipantID)
+	}
+	if _, err := e.Core.ContestParticipants.Get(ctx, participants[0].ID); err != sql.ErrNoRows {
+		t.Fatal("Expected deleted participant:", err)
+	}
+	if user, err := e.Core.Users.Get(ctx, source.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if user.Status != models.BlockedUser {
+		t.Fatalf("Expected blocked user, got %v", user.Status)
+	}
+}
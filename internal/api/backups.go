@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerBackupHandlers registers handlers for backups monitoring.
+func (v *View) registerBackupHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/backups/status", v.observeBackupStatus,
+		v.extractAuth(v.sessionAuth, v.guestAuth),
+		v.requirePermission(perms.ObserveSettingsRole),
+	)
+}
+
+type BackupStatus struct {
+	// Enabled contains true if scheduled backups are configured.
+	Enabled bool `json:"enabled"`
+	// LastSuccessTime contains time of last successful backup.
+	LastSuccessTime int64 `json:"last_success_time,omitempty"`
+	// Age contains amount of seconds since last successful backup.
+	Age *int64 `json:"age,omitempty"`
+}
+
+func (v *View) observeBackupStatus(c echo.Context) error {
+	if err := syncStore(c, v.core.Settings); err != nil {
+		return err
+	}
+	resp := BackupStatus{
+		Enabled: v.core.Config.Backup != nil,
+	}
+	lastTime, err := v.backups.GetLastBackupTime()
+	if err != nil {
+		return err
+	}
+	if !lastTime.Empty {
+		resp.LastSuccessTime = lastTime.Value.Unix()
+		resp.Age = getPtr(int64(getNow(c).Sub(lastTime.Value).Seconds()))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"sort"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -69,9 +68,7 @@ func (v *View) getEventFeed(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	sortFunc(contestProblems, func(lhs, rhs models.ContestProblem) bool {
-		return lhs.Code < rhs.Code
-	})
+	models.SortContestProblems(contestProblems)
 	for i, contestProblem := range contestProblems {
 		problem, err := v.core.Problems.Get(
 			c.Request().Context(), contestProblem.ProblemID,
@@ -644,25 +641,3 @@ func (e State) ObjectID() any {
 func (e State) Token() string {
 	return fmt.Sprint(time.Now().UnixNano())
 }
-
-func sortFunc[T any](a []T, less func(T, T) bool) {
-	impl := sortFuncImpl[T]{data: a, less: less}
-	sort.Sort(&impl)
-}
-
-type sortFuncImpl[T any] struct {
-	data []T
-	less func(T, T) bool
-}
-
-func (s *sortFuncImpl[T]) Len() int {
-	return len(s.data)
-}
-
-func (s *sortFuncImpl[T]) Swap(i, j int) {
-	s.data[i], s.data[j] = s.data[j], s.data[i]
-}
-
-func (s *sortFuncImpl[T]) Less(i, j int) bool {
-	return s.less(s.data[i], s.data[j])
-}
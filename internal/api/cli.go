@@ -0,0 +1,319 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerCLIHandlers registers handlers for command-line clients.
+//
+// Token returned by /v0/cli/token should be passed in Authorization
+// header with Bearer scheme. Solutions are submitted using regular
+// contest submit endpoint.
+func (v *View) registerCLIHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/cli/token", v.createCLIToken,
+		v.extractAuth(v.scopeUserAuth, v.userAuth),
+		v.requirePermission(perms.LoginRole),
+	)
+	g.GET(
+		"/v0/cli/contests", v.observeCLIContests,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.ObserveContestsRole),
+	)
+	g.GET(
+		"/v0/cli/contests/:contest", v.observeCLIContest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestProblemsRole),
+	)
+	g.GET(
+		"/v0/cli/contests/:contest/solutions/:solution/watch",
+		v.watchCLIContestSolution,
+		v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestSolution,
+		v.requirePermission(perms.ObserveContestSolutionRole),
+	)
+}
+
+// CLITokenForm represents form for obtaining token of command-line client.
+//
+// ScopeID should be specified for scope users.
+type CLITokenForm struct {
+	ScopeID  int64  `json:"scope_id,omitempty"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// CLIToken represents token for command-line client.
+type CLIToken struct {
+	Token      string `json:"token"`
+	ExpireTime int64  `json:"expire_time"`
+}
+
+const cliTokenDuration = 30 * 24 * time.Hour
+
+func (v *View) createCLIToken(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("auth not extracted")
+	}
+	now := getNow(c)
+	session := models.Session{
+		AccountID:  accountCtx.Account.ID,
+		CreateTime: now.Unix(),
+		ExpireTime: now.Add(cliTokenDuration).Unix(),
+		RealIP:     c.RealIP(),
+		UserAgent:  c.Request().UserAgent(),
+	}
+	if err := session.GenerateSecret(); err != nil {
+		return err
+	}
+	if err := v.core.Sessions.Create(getContext(c), &session); err != nil {
+		return err
+	}
+	cookie := session.Cookie()
+	return c.JSON(http.StatusCreated, CLIToken{
+		Token:      cookie.Value,
+		ExpireTime: session.ExpireTime,
+	})
+}
+
+// CLIContest represents compact contest for command-line client.
+type CLIContest struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	BeginTime int64  `json:"begin_time,omitempty"`
+	Duration  int    `json:"duration,omitempty"`
+}
+
+// CLIContests represents list of compact contests.
+type CLIContests struct {
+	Contests []CLIContest `json:"contests"`
+}
+
+func makeCLIContest(contestCtx *managers.ContestContext) CLIContest {
+	return CLIContest{
+		ID:        contestCtx.Contest.ID,
+		Title:     contestCtx.Contest.Title,
+		BeginTime: int64(contestCtx.ContestConfig.BeginTime),
+		Duration:  contestCtx.ContestConfig.Duration,
+	}
+}
+
+// observeCLIContests returns contests where account can submit
+// solutions.
+func (v *View) observeCLIContests(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	if err := syncStore(c, v.core.Contests); err != nil {
+		return err
+	}
+	contests, err := v.core.Contests.ReverseAll(getContext(c), 0, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = contests.Close() }()
+	resp := CLIContests{}
+	for contests.Next() {
+		contest := contests.Row()
+		contestCtx, err := v.contests.BuildContext(accountCtx, contest)
+		if err != nil {
+			return err
+		}
+		if contestCtx.HasPermission(perms.SubmitContestSolutionRole) {
+			resp.Contests = append(resp.Contests, makeCLIContest(contestCtx))
+		}
+	}
+	if err := contests.Err(); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CLIProblem represents compact contest problem.
+type CLIProblem struct {
+	Code  string `json:"code"`
+	Title string `json:"title"`
+}
+
+// CLICompiler represents compact compiler.
+type CLICompiler struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Language   string   `json:"language,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// CLIContestInfo represents contest with problems and compilers.
+type CLIContestInfo struct {
+	CLIContest
+	Problems  []CLIProblem  `json:"problems"`
+	Compilers []CLICompiler `json:"compilers"`
+}
+
+func (v *View) observeCLIContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	resp := CLIContestInfo{CLIContest: makeCLIContest(contestCtx)}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	problemRows, err := v.core.ContestProblems.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	problems, err := db.CollectRows(problemRows)
+	if err != nil {
+		return err
+	}
+	models.SortContestProblems(problems)
+	for _, contestProblem := range problems {
+		problem := v.makeContestProblem(c, contestProblem, false)
+		title := problem.Problem.Title
+		if problem.Title != nil {
+			title = *problem.Title
+		}
+		resp.Problems = append(resp.Problems, CLIProblem{
+			Code:  problem.Code,
+			Title: title,
+		})
+	}
+	if err := syncStore(c, v.core.Compilers); err != nil {
+		return err
+	}
+	compilers, err := v.getDetectableCompilers(c)
+	if err != nil {
+		return err
+	}
+	for _, compiler := range compilers {
+		permissions := v.getCompilerPermissions(
+			contestCtx.AccountContext, compiler.Compiler,
+		)
+		if !permissions.HasPermission(perms.ObserveCompilerRole) {
+			continue
+		}
+		resp.Compilers = append(resp.Compilers, CLICompiler{
+			ID:         compiler.Compiler.ID,
+			Name:       compiler.Compiler.Name,
+			Language:   compiler.Language,
+			Extensions: getCompilerExtensions(compiler.Compiler),
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func getCompilerExtensions(compiler models.Compiler) []string {
+	config, err := compiler.GetConfig()
+	if err != nil {
+		return nil
+	}
+	return config.Extensions
+}
+
+// CLIVerdict represents state of solution judgement for command-line
+// client.
+type CLIVerdict struct {
+	ID         int64    `json:"id"`
+	Verdict    string   `json:"verdict"`
+	Final      bool     `json:"final,omitempty"`
+	Points     *float64 `json:"points,omitempty"`
+	UsedTime   int64    `json:"used_time,omitempty"`
+	UsedMemory int64    `json:"used_memory,omitempty"`
+	TestNumber int      `json:"test_number,omitempty"`
+	Stage      string   `json:"stage,omitempty"`
+	CompileLog string   `json:"compile_log,omitempty"`
+}
+
+const (
+	cliWatchInterval = time.Second
+	cliWatchTimeout  = 10 * time.Minute
+)
+
+func (v *View) getCLIVerdict(c echo.Context, id int64) (CLIVerdict, error) {
+	solution, err := v.core.Solutions.Get(getContext(c), id)
+	if err != nil {
+		return CLIVerdict{}, err
+	}
+	resp := CLIVerdict{ID: id}
+	report := v.makeSolutionReport(c, solution, true)
+	if report != nil {
+		resp.Verdict = report.Verdict
+		resp.Points = report.Points
+		resp.UsedTime = report.UsedTime
+		resp.UsedMemory = report.UsedMemory
+		resp.TestNumber = report.TestNumber
+		resp.Stage = report.Stage
+		resp.CompileLog = report.CompileLog
+	}
+	if report, err := solution.GetReport(); err == nil && report != nil {
+		resp.Final = true
+	}
+	return resp, nil
+}
+
+// watchCLIContestSolution streams verdict of solution using server-sent
+// events until judgement is finished.
+//
+// Every event contains CLIVerdict and is sent only when state changes.
+func (v *View) watchCLIContestSolution(c echo.Context) error {
+	solution, ok := c.Get(contestSolutionKey).(models.ContestSolution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+	resp.WriteHeader(http.StatusOK)
+	ctx := c.Request().Context()
+	timeout := time.NewTimer(cliWatchTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(cliWatchInterval)
+	defer ticker.Stop()
+	var last []byte
+	for {
+		if err := syncStore(c, v.core.Solutions); err != nil {
+			return err
+		}
+		verdict, err := v.getCLIVerdict(c, solution.ID)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(verdict)
+		if err != nil {
+			return err
+		}
+		if string(data) != string(last) {
+			if _, err := fmt.Fprintf(
+				resp, "event: verdict\ndata: %s\n\n", data,
+			); err != nil {
+				return err
+			}
+			resp.Flush()
+			last = data
+		}
+		if verdict.Final {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timeout.C:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestCLISubmitter(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting", "observe_contest", "create_contest")
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(context.Background(), &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	ctx := context.Background()
+	if _, err := e.Client.CreateCLIToken(ctx, CLITokenForm{
+		Login:    owner.User.Login,
+		Password: "invalid",
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusUnauthorized, resp.StatusCode())
+	}
+	token, err := e.Client.CreateCLIToken(ctx, CLITokenForm{
+		Login:    owner.User.Login,
+		Password: owner.Password,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	client := NewClient(e.Server.URL+"/api", WithToken(token.Token))
+	client.Headers["X-Solve-Sync"] = "1"
+	if _, err := NewClient(
+		e.Server.URL+"/api", WithToken("invalid"),
+	).ObserveCLIContests(ctx); err == nil {
+		t.Fatal("Expected error")
+	}
+	contests, err := client.ObserveCLIContests(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(contests.Contests) != 1 || contests.Contests[0].ID != contest.ID {
+		t.Fatalf("Unexpected contests: %v", contests.Contests)
+	}
+	info, err := client.ObserveCLIContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(info.Problems) != 1 || info.Problems[0].Code != "A" {
+		t.Fatalf("Unexpected problems: %v", info.Problems)
+	}
+	if len(info.Compilers) != 1 || info.Compilers[0].ID != compiler.ID {
+		t.Fatalf("Unexpected compilers: %v", info.Compilers)
+	}
+	solution, err := client.SubmitContestSolution(ctx, contest.ID, "A", SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	solutionModel, err := e.Core.Solutions.Get(models.WithSync(ctx), solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solutionModel.SetReport(&models.SolutionReport{
+		Verdict: models.Accepted,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(ctx, solutionModel); err != nil {
+		t.Fatal("Error:", err)
+	}
+	var verdicts []CLIVerdict
+	if err := client.WatchContestSolution(
+		ctx, contest.ID, solution.ID, func(verdict CLIVerdict) error {
+			verdicts = append(verdicts, verdict)
+			return nil
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(verdicts) != 1 {
+		t.Fatalf("Expected 1 verdict, got %d", len(verdicts))
+	}
+	if !verdicts[0].Final || verdicts[0].Verdict != "accepted" {
+		t.Fatalf("Unexpected verdict: %v", verdicts[0])
+	}
+}
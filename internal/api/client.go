@@ -1,8 +1,11 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +13,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/udovin/solve/api/schema"
@@ -36,6 +40,16 @@ func WithSessionCookie(value string) ClientOption {
 	}
 }
 
+// WithToken sets token that is passed in Authorization header.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		if c.Headers == nil {
+			c.Headers = map[string]string{}
+		}
+		c.Headers["Authorization"] = "Bearer " + token
+	}
+}
+
 func WithTransport(transport *http.Transport) ClientOption {
 	return func(c *Client) {
 		c.client.Transport = transport
@@ -250,6 +264,76 @@ func (c *Client) DeleteCompiler(ctx context.Context, id int64) (Compiler, error)
 	return respData, err
 }
 
+func (c *Client) BuildCompilerImage(
+	ctx context.Context, compiler int64, form BuildCompilerImageForm,
+) (CompilerImageBuild, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return CompilerImageBuild{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/compilers/%d/build", compiler),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return CompilerImageBuild{}, err
+	}
+	var respData CompilerImageBuild
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveCompilerImageBuild(
+	ctx context.Context, compiler int64, task int64,
+) (CompilerImageBuild, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/compilers/%d/build/%d", compiler, task), nil,
+	)
+	if err != nil {
+		return CompilerImageBuild{}, err
+	}
+	var respData CompilerImageBuild
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateInvokerSelfTest(
+	ctx context.Context, compiler int64, form CreateInvokerSelfTestForm,
+) (InvokerSelfTest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return InvokerSelfTest{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/compilers/%d/self-test", compiler),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return InvokerSelfTest{}, err
+	}
+	var respData InvokerSelfTest
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveInvokerSelfTest(
+	ctx context.Context, compiler int64, task int64,
+) (InvokerSelfTest, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/compilers/%d/self-test/%d", compiler, task), nil,
+	)
+	if err != nil {
+		return InvokerSelfTest{}, err
+	}
+	var respData InvokerSelfTest
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) CreateProblem(ctx context.Context, form CreateProblemForm) (Problem, error) {
 	defer func() { _ = form.Close() }()
 	buf := bytes.Buffer{}
@@ -266,6 +350,11 @@ func (c *Client) CreateProblem(ctx context.Context, form CreateProblemForm) (Pro
 			return Problem{}, err
 		}
 	}
+	if form.UploadID != nil {
+		if err := w.WriteField("upload_id", fmt.Sprint(*form.UploadID)); err != nil {
+			return Problem{}, err
+		}
+	}
 	if err := w.Close(); err != nil {
 		return Problem{}, err
 	}
@@ -281,6 +370,34 @@ func (c *Client) CreateProblem(ctx context.Context, form CreateProblemForm) (Pro
 	return respData, err
 }
 
+func (c *Client) PreviewProblem(
+	ctx context.Context, id int64, form PreviewProblemForm,
+) (ProblemPreview, error) {
+	defer func() { _ = form.Close() }()
+	buf := bytes.Buffer{}
+	w := multipart.NewWriter(&buf)
+	if form.PackageFile != nil {
+		if fw, err := w.CreateFormFile("file", form.PackageFile.Name); err != nil {
+			return ProblemPreview{}, err
+		} else if _, err := io.Copy(fw, form.PackageFile.Reader); err != nil {
+			return ProblemPreview{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return ProblemPreview{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/problems/%d/preview", id), &buf,
+	)
+	if err != nil {
+		return ProblemPreview{}, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	var respData ProblemPreview
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) UpdateProblem(ctx context.Context, id int64, form UpdateProblemForm) (Problem, error) {
 	defer func() { _ = form.Close() }()
 	buf := bytes.Buffer{}
@@ -290,6 +407,11 @@ func (c *Client) UpdateProblem(ctx context.Context, id int64, form UpdateProblem
 			return Problem{}, err
 		}
 	}
+	if form.Rejudge {
+		if err := w.WriteField("rejudge", "true"); err != nil {
+			return Problem{}, err
+		}
+	}
 	if form.PackageFile != nil {
 		if fw, err := w.CreateFormFile("file", form.PackageFile.Name); err != nil {
 			return Problem{}, err
@@ -297,6 +419,11 @@ func (c *Client) UpdateProblem(ctx context.Context, id int64, form UpdateProblem
 			return Problem{}, err
 		}
 	}
+	if form.UploadID != nil {
+		if err := w.WriteField("upload_id", fmt.Sprint(*form.UploadID)); err != nil {
+			return Problem{}, err
+		}
+	}
 	if err := w.Close(); err != nil {
 		return Problem{}, err
 	}
@@ -324,291 +451,1810 @@ func (c *Client) DeleteProblem(ctx context.Context, id int64) (Problem, error) {
 	return respData, err
 }
 
-func (c *Client) ObserveRoles(ctx context.Context) (Roles, error) {
+func (c *Client) ObserveProblem(ctx context.Context, id int64) (Problem, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/roles"), nil,
+		ctx, http.MethodGet, c.getURL("/v0/problems/%d", id), nil,
 	)
 	if err != nil {
-		return Roles{}, err
+		return Problem{}, err
 	}
-	var respData Roles
+	var respData Problem
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) SubmitContestSolution(
-	ctx context.Context, contest int64, problem string, form SubmitSolutionForm,
-) (ContestSolution, error) {
-	if form.ContentFile != nil {
-		return c.submitContestSolutionFile(ctx, contest, problem, form)
+func (c *Client) ObserveProblemGrants(
+	ctx context.Context, problem int64,
+) (ProblemGrants, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/problems/%d/grants", problem), nil,
+	)
+	if err != nil {
+		return ProblemGrants{}, err
 	}
+	var respData ProblemGrants
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateProblemGrant(
+	ctx context.Context, problem int64, form CreateProblemGrantForm,
+) (ProblemGrant, error) {
 	data, err := json.Marshal(form)
 	if err != nil {
-		return ContestSolution{}, err
+		return ProblemGrant{}, err
 	}
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodPost,
-		c.getURL("/v0/contests/%d/problems/%s/submit", contest, problem),
+		c.getURL("/v0/problems/%d/grants", problem),
 		bytes.NewReader(data),
 	)
 	if err != nil {
-		return ContestSolution{}, err
+		return ProblemGrant{}, err
 	}
-	var respData ContestSolution
+	var respData ProblemGrant
 	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
-func (c *Client) submitContestSolutionFile(
-	ctx context.Context, contest int64, problem string, form SubmitSolutionForm,
-) (ContestSolution, error) {
-	defer func() { _ = form.ContentFile.Close() }()
-	buf := bytes.Buffer{}
-	w := multipart.NewWriter(&buf)
-	if err := w.WriteField("config", fmt.Sprint(form.CompilerID)); err != nil {
-		return ContestSolution{}, err
-	}
-	if fw, err := w.CreateFormFile("file", form.ContentFile.Name); err != nil {
-		return ContestSolution{}, err
-	} else if _, err := io.Copy(fw, form.ContentFile.Reader); err != nil {
-		return ContestSolution{}, err
-	}
-	if err := w.Close(); err != nil {
-		return ContestSolution{}, err
+func (c *Client) CreateProblemStress(
+	ctx context.Context, problem int64, form CreateProblemStressForm,
+) (ProblemStress, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ProblemStress{}, err
 	}
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodPost,
-		c.getURL("/v0/contests/%d/problems/%s/submit", contest, problem),
-		&buf,
+		c.getURL("/v0/problems/%d/stress", problem),
+		bytes.NewReader(data),
 	)
 	if err != nil {
-		return ContestSolution{}, err
+		return ProblemStress{}, err
 	}
-	req.Header.Set("Content-Type", w.FormDataContentType())
-	var respData ContestSolution
+	var respData ProblemStress
 	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
-func (c *Client) CreateRole(
-	ctx context.Context, name string,
-) (Role, error) {
-	data, err := json.Marshal(createRoleForm{
-		Name: name,
-	})
+func (c *Client) ObserveProblemStress(
+	ctx context.Context, problem int64, task int64,
+) (ProblemStress, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/problems/%d/stress/%d", problem, task), nil,
+	)
 	if err != nil {
-		return Role{}, err
+		return ProblemStress{}, err
+	}
+	var respData ProblemStress
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ValidateProblemTest(
+	ctx context.Context, problem int64, form ValidateProblemTestForm,
+) (ProblemTestValidation, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ProblemTestValidation{}, err
 	}
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodPost, c.getURL("/v0/roles"), bytes.NewReader(data),
+		ctx, http.MethodPost,
+		c.getURL("/v0/problems/%d/validate", problem),
+		bytes.NewReader(data),
 	)
 	if err != nil {
-		return Role{}, err
+		return ProblemTestValidation{}, err
 	}
-	var respData Role
+	var respData ProblemTestValidation
 	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
-func (c *Client) DeleteRole(
-	ctx context.Context, name any,
-) (Role, error) {
+func (c *Client) ObserveProblemTestValidation(
+	ctx context.Context, problem int64, task int64,
+) (ProblemTestValidation, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodDelete, c.getURL("/v0/roles/%v", name), nil,
+		ctx, http.MethodGet,
+		c.getURL("/v0/problems/%d/validate/%d", problem, task), nil,
 	)
 	if err != nil {
-		return Role{}, err
+		return ProblemTestValidation{}, err
 	}
-	var respData Role
+	var respData ProblemTestValidation
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveUserRoles(
-	ctx context.Context, login string,
-) (Roles, error) {
+func (c *Client) ObserveProblemExtraTests(
+	ctx context.Context, problem int64,
+) (ProblemExtraTests, error) {
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodGet,
-		c.getURL("/v0/users/%s/roles", login), nil,
+		c.getURL("/v0/problems/%d/tests", problem), nil,
 	)
 	if err != nil {
-		return Roles{}, err
+		return ProblemExtraTests{}, err
 	}
-	var respData Roles
+	var respData ProblemExtraTests
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) CreateUserRole(
-	ctx context.Context, login string, role string,
-) (Role, error) {
+func (c *Client) CreateProblemExtraTest(
+	ctx context.Context, problem int64, form CreateProblemExtraTestForm,
+) (ProblemExtraTest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ProblemExtraTest{}, err
+	}
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodPost,
-		c.getURL("/v0/users/%s/roles/%s", login, role), nil,
+		c.getURL("/v0/problems/%d/tests", problem),
+		bytes.NewReader(data),
 	)
 	if err != nil {
-		return Role{}, err
+		return ProblemExtraTest{}, err
 	}
-	var respData Role
+	var respData ProblemExtraTest
 	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
-func (c *Client) DeleteUserRole(
-	ctx context.Context, login string, role string,
-) (Role, error) {
+func (c *Client) DeleteProblemExtraTest(
+	ctx context.Context, problem int64, test int64,
+) (ProblemExtraTest, error) {
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodDelete,
-		c.getURL("/v0/users/%s/roles/%s", login, role), nil,
+		c.getURL("/v0/problems/%d/tests/%d", problem, test), nil,
 	)
 	if err != nil {
-		return Role{}, err
+		return ProblemExtraTest{}, err
 	}
-	var respData Role
+	var respData ProblemExtraTest
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveContest(
-	ctx context.Context, id int64,
-) (Contest, error) {
+func (c *Client) ObserveProblemUsages(
+	ctx context.Context, problem int64,
+) (ProblemUsages, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/contests/%d", id), nil,
+		ctx, http.MethodGet,
+		c.getURL("/v0/problems/%d/usages", problem), nil,
 	)
 	if err != nil {
-		return Contest{}, err
+		return ProblemUsages{}, err
 	}
-	var respData Contest
+	var respData ProblemUsages
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveContestStandings(
-	ctx context.Context, id int64,
-) (ContestStandings, error) {
-	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/contests/%d/standings", id), nil,
-	)
+func (c *Client) CloneProblem(
+	ctx context.Context, problem int64, form CloneProblemForm,
+) (Problem, error) {
+	data, err := json.Marshal(form)
 	if err != nil {
-		return ContestStandings{}, err
+		return Problem{}, err
 	}
-	var respData ContestStandings
-	_, err = c.doRequest(req, http.StatusOK, &respData)
-	return respData, err
-}
-
-func (c *Client) ObserveContestSolutions(
-	ctx context.Context, id int64,
-) (ContestSolutions, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/contests/%d/solutions", id), nil,
+		ctx, http.MethodPost,
+		c.getURL("/v0/problems/%d/clone", problem),
+		bytes.NewReader(data),
 	)
 	if err != nil {
-		return ContestSolutions{}, err
+		return Problem{}, err
 	}
-	var respData ContestSolutions
-	_, err = c.doRequest(req, http.StatusOK, &respData)
+	var respData Problem
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveContestSolution(
-	ctx context.Context, id int64, solutionID int64,
-) (ContestSolution, error) {
+func (c *Client) ObserveProblemCheckers(
+	ctx context.Context, problem int64,
+) (ProblemCheckers, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/contests/%d/solutions/%d", id, solutionID), nil,
+		ctx, http.MethodGet,
+		c.getURL("/v0/problems/%d/checkers", problem), nil,
 	)
 	if err != nil {
-		return ContestSolution{}, err
+		return ProblemCheckers{}, err
 	}
-	var respData ContestSolution
+	var respData ProblemCheckers
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) CreateContestParticipant(
-	ctx context.Context,
-	contest int64,
-	form CreateContestParticipantForm,
-) (ContestParticipant, error) {
+func (c *Client) CreateProblemChecker(
+	ctx context.Context, problem int64, form CreateProblemCheckerForm,
+) (ProblemChecker, error) {
 	data, err := json.Marshal(form)
 	if err != nil {
-		return ContestParticipant{}, err
+		return ProblemChecker{}, err
 	}
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodPost,
-		c.getURL("/v0/contests/%d/participants", contest),
+		c.getURL("/v0/problems/%d/checkers", problem),
 		bytes.NewReader(data),
 	)
 	if err != nil {
-		return ContestParticipant{}, err
+		return ProblemChecker{}, err
 	}
-	var respData ContestParticipant
+	var respData ProblemChecker
 	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveSettings(ctx context.Context) (Settings, error) {
+func (c *Client) DeleteProblemChecker(
+	ctx context.Context, problem int64, checker int64,
+) (ProblemChecker, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/settings"), nil,
+		ctx, http.MethodDelete,
+		c.getURL("/v0/problems/%d/checkers/%d", problem, checker), nil,
 	)
 	if err != nil {
-		return Settings{}, err
+		return ProblemChecker{}, err
 	}
-	var respData Settings
+	var respData ProblemChecker
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveScopeUsers(ctx context.Context, scope int64) (ScopeUsers, error) {
+func (c *Client) DeleteProblemGrant(
+	ctx context.Context, problem int64, grant int64,
+) (ProblemGrant, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/scopes/%d/users", scope), nil,
+		ctx, http.MethodDelete,
+		c.getURL("/v0/problems/%d/grants/%d", problem, grant), nil,
 	)
 	if err != nil {
-		return ScopeUsers{}, err
+		return ProblemGrant{}, err
 	}
-	var respData ScopeUsers
+	var respData ProblemGrant
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveGroups(ctx context.Context) (Groups, error) {
+func (c *Client) ObserveRoles(ctx context.Context) (Roles, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/groups"), nil,
+		ctx, http.MethodGet, c.getURL("/v0/roles"), nil,
 	)
 	if err != nil {
-		return Groups{}, err
+		return Roles{}, err
 	}
-	var respData Groups
+	var respData Roles
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) ObserveGroup(ctx context.Context, group int64) (Group, error) {
+func (c *Client) ObserveRolePermissions(ctx context.Context) (RolePermissionsMatrix, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodGet, c.getURL("/v0/groups/%d", group), nil,
+		ctx, http.MethodGet, c.getURL("/v0/roles/permissions"), nil,
 	)
 	if err != nil {
-		return Group{}, err
+		return RolePermissionsMatrix{}, err
 	}
-	var respData Group
+	var respData RolePermissionsMatrix
 	_, err = c.doRequest(req, http.StatusOK, &respData)
 	return respData, err
 }
 
-func (c *Client) CreateGroup(ctx context.Context, form CreateGroupForm) (Group, error) {
-	data, err := json.Marshal(form)
-	if err != nil {
-		return Group{}, err
-	}
+func (c *Client) ObserveAccountPermissions(
+	ctx context.Context, id int64,
+) (AccountPermissions, error) {
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodPost, c.getURL("/v0/groups"),
-		bytes.NewReader(data),
+		ctx, http.MethodGet, c.getURL("/v0/accounts/%d/permissions", id), nil,
 	)
 	if err != nil {
-		return Group{}, err
+		return AccountPermissions{}, err
 	}
-	var respData Group
-	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	var respData AccountPermissions
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) SubmitContestSolution(
+	ctx context.Context, contest int64, problem string, form SubmitSolutionForm,
+) (ContestSolution, error) {
+	if form.ContentFile != nil {
+		return c.submitContestSolutionFile(ctx, contest, problem, form)
+	}
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestSolution{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/problems/%s/submit", contest, problem),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestSolution{}, err
+	}
+	var respData ContestSolution
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+// SubmitContestSolutionAsync submits solution that will be created
+// in background.
+func (c *Client) SubmitContestSolutionAsync(
+	ctx context.Context, contest int64, problem string, form SubmitSolutionForm,
+) (ContestSubmission, error) {
+	form.Async = true
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestSubmission{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/problems/%s/submit", contest, problem),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestSubmission{}, err
+	}
+	var respData ContestSubmission
+	_, err = c.doRequest(req, http.StatusAccepted, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestSubmission(
+	ctx context.Context, contest int64, submission string,
+) (ContestSubmission, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/submissions/%s", contest, submission),
+		nil,
+	)
+	if err != nil {
+		return ContestSubmission{}, err
+	}
+	var respData ContestSubmission
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) submitContestSolutionFile(
+	ctx context.Context, contest int64, problem string, form SubmitSolutionForm,
+) (ContestSolution, error) {
+	defer func() { _ = form.ContentFile.Close() }()
+	buf := bytes.Buffer{}
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("compiler_id", fmt.Sprint(form.CompilerID)); err != nil {
+		return ContestSolution{}, err
+	}
+	if fw, err := w.CreateFormFile("file", form.ContentFile.Name); err != nil {
+		return ContestSolution{}, err
+	} else if _, err := io.Copy(fw, form.ContentFile.Reader); err != nil {
+		return ContestSolution{}, err
+	}
+	if err := w.Close(); err != nil {
+		return ContestSolution{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/problems/%s/submit", contest, problem),
+		&buf,
+	)
+	if err != nil {
+		return ContestSolution{}, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	var respData ContestSolution
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveSolution(ctx context.Context, id int64) (Solution, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/solutions/%d", id), nil,
+	)
+	if err != nil {
+		return Solution{}, err
+	}
+	var respData Solution
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveSolutionDiff(
+	ctx context.Context, r ObserveSolutionDiffRequest,
+) (SolutionDiff, error) {
+	query := url.Values{}
+	if r.Normalize {
+		query.Add("normalize", "t")
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/solutions/%d/diff/%d?%s", r.ID, r.OtherID, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return SolutionDiff{}, err
+	}
+	var respData SolutionDiff
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveSolutionTestArtifact returns compressed artifact of
+// solution test.
+func (c *Client) ObserveSolutionTestArtifact(
+	ctx context.Context, solution int64, test int, name string,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/solutions/%d/tests/%d/artifacts/%s", solution, test, name),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return io.ReadAll(resp.Body)
+}
+
+func (c *Client) CreateRole(
+	ctx context.Context, name string,
+) (Role, error) {
+	data, err := json.Marshal(createRoleForm{
+		Name: name,
+	})
+	if err != nil {
+		return Role{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/roles"), bytes.NewReader(data),
+	)
+	if err != nil {
+		return Role{}, err
+	}
+	var respData Role
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteRole(
+	ctx context.Context, name any,
+) (Role, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete, c.getURL("/v0/roles/%v", name), nil,
+	)
+	if err != nil {
+		return Role{}, err
+	}
+	var respData Role
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveUserRoles(
+	ctx context.Context, login string,
+) (Roles, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/users/%s/roles", login), nil,
+	)
+	if err != nil {
+		return Roles{}, err
+	}
+	var respData Roles
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateUserRole(
+	ctx context.Context, login string, role string,
+) (Role, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/users/%s/roles/%s", login, role), nil,
+	)
+	if err != nil {
+		return Role{}, err
+	}
+	var respData Role
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteUserRole(
+	ctx context.Context, login string, role string,
+) (Role, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/users/%s/roles/%s", login, role), nil,
+	)
+	if err != nil {
+		return Role{}, err
+	}
+	var respData Role
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveGroupRoles(
+	ctx context.Context, group int64,
+) (Roles, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/groups/%d/roles", group), nil,
+	)
+	if err != nil {
+		return Roles{}, err
+	}
+	var respData Roles
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateGroupRole(
+	ctx context.Context, group int64, role string,
+) (Role, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/groups/%d/roles/%s", group, role), nil,
+	)
+	if err != nil {
+		return Role{}, err
+	}
+	var respData Role
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteGroupRole(
+	ctx context.Context, group int64, role string,
+) (Role, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/groups/%d/roles/%s", group, role), nil,
+	)
+	if err != nil {
+		return Role{}, err
+	}
+	var respData Role
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveUserLock(
+	ctx context.Context, login string,
+) (UserLock, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/users/%s/lock", login), nil,
+	)
+	if err != nil {
+		return UserLock{}, err
+	}
+	var respData UserLock
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) UnlockUser(
+	ctx context.Context, login string,
+) (UserLock, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/users/%s/lock", login), nil,
+	)
+	if err != nil {
+		return UserLock{}, err
+	}
+	var respData UserLock
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContest(
+	ctx context.Context, id int64,
+) (Contest, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d", id), nil,
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+	var respData Contest
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestStandings(
+	ctx context.Context, id int64,
+) (ContestStandings, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/standings", id), nil,
+	)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+	var respData ContestStandings
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestTokens(
+	ctx context.Context, contest int64,
+) (ContestTokens, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/tokens", contest), nil,
+	)
+	if err != nil {
+		return ContestTokens{}, err
+	}
+	var respData ContestTokens
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestToken(
+	ctx context.Context, contest int64, form CreateContestTokenForm,
+) (ContestToken, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestToken{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/tokens", contest),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestToken{}, err
+	}
+	var respData ContestToken
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteContestToken(
+	ctx context.Context, contest int64, token int64,
+) (ContestToken, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/contests/%d/tokens/%d", contest, token), nil,
+	)
+	if err != nil {
+		return ContestToken{}, err
+	}
+	var respData ContestToken
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveLabelContestStandings returns standings of participants with
+// label in format "name:value".
+func (c *Client) ObserveLabelContestStandings(
+	ctx context.Context, id int64, label string,
+) (ContestStandings, error) {
+	query := url.Values{}
+	query.Add("label", label)
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/standings?%s", id, query.Encode()), nil,
+	)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+	var respData ContestStandings
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveParticipantContestStandings returns standings row of current
+// participant.
+func (c *Client) ObserveParticipantContestStandings(
+	ctx context.Context, id int64,
+) (ContestStandings, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/standings/me", id), nil,
+	)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+	var respData ContestStandings
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveGuestContestStandings returns guest standings and ETag.
+//
+// If etag is not empty and standings are not modified, nil
+// standings are returned.
+func (c *Client) ObserveGuestContestStandings(
+	ctx context.Context, id int64, etag string,
+) (*ContestStandings, string, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/standings/guest", id), nil,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	code := http.StatusOK
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+		code = http.StatusNotModified
+	}
+	resp, err := c.doRequest(req, code, nil)
+	if err != nil {
+		if resp, ok := err.(*errorResponse); ok && resp.Code == http.StatusOK {
+			return nil, "", fmt.Errorf("standings are modified")
+		}
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if code == http.StatusNotModified {
+		return nil, resp.Header.Get("ETag"), nil
+	}
+	var respData ContestStandings
+	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
+		return nil, "", err
+	}
+	return &respData, resp.Header.Get("ETag"), nil
+}
+
+// ObserveContestWidgetRequest represents request for contest widget.
+//
+// Public widget endpoint is used when signature is specified.
+type ObserveContestWidgetRequest struct {
+	ContestID  int64
+	Limit      int
+	ScopeID    int64
+	ExpireTime int64
+	Signature  string
+}
+
+func (r ObserveContestWidgetRequest) getPath(widget string) string {
+	query := url.Values{}
+	if r.Limit != 0 {
+		query.Set("limit", fmt.Sprint(r.Limit))
+	}
+	if r.ScopeID != 0 {
+		query.Set("scope_id", fmt.Sprint(r.ScopeID))
+	}
+	prefix := "/v0"
+	if r.Signature != "" {
+		prefix = "/v0/public"
+		query.Set("expire", fmt.Sprint(r.ExpireTime))
+		query.Set("signature", r.Signature)
+	}
+	return fmt.Sprintf(
+		"%s/contests/%d/widgets/%s?%s",
+		prefix, r.ContestID, widget, query.Encode(),
+	)
+}
+
+func (c *Client) ObserveContestWidgetTop(
+	ctx context.Context, r ObserveContestWidgetRequest,
+) (ContestWidgetTop, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("%s", r.getPath("top")), nil,
+	)
+	if err != nil {
+		return ContestWidgetTop{}, err
+	}
+	var respData ContestWidgetTop
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestWidgetTicker(
+	ctx context.Context, r ObserveContestWidgetRequest,
+) (ContestWidgetTicker, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("%s", r.getPath("ticker")), nil,
+	)
+	if err != nil {
+		return ContestWidgetTicker{}, err
+	}
+	var respData ContestWidgetTicker
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestWidgetLink(
+	ctx context.Context, id int64, form CreateContestWidgetLinkForm,
+) (ContestWidgetLink, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestWidgetLink{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/widgets/links", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestWidgetLink{}, err
+	}
+	var respData ContestWidgetLink
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestMessages(
+	ctx context.Context, id int64,
+) (ContestMessages, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/messages", id), nil,
+	)
+	if err != nil {
+		return ContestMessages{}, err
+	}
+	var respData ContestMessages
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveContestUpdates returns counters of contest changes since
+// message with specified ID.
+func (c *Client) ObserveContestUpdates(
+	ctx context.Context, id int64, messageID int64,
+) (ContestUpdates, error) {
+	query := url.Values{}
+	query.Add("message_id", fmt.Sprint(messageID))
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/updates?%s", id, query.Encode()), nil,
+	)
+	if err != nil {
+		return ContestUpdates{}, err
+	}
+	var respData ContestUpdates
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestSystemTest(
+	ctx context.Context, id int64,
+) (ContestSystemTest, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/system-test", id), nil,
+	)
+	if err != nil {
+		return ContestSystemTest{}, err
+	}
+	var respData ContestSystemTest
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) StartContestSystemTest(
+	ctx context.Context, id int64,
+) (ContestSystemTest, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/system-test", id), nil,
+	)
+	if err != nil {
+		return ContestSystemTest{}, err
+	}
+	var respData ContestSystemTest
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestAddresses(
+	ctx context.Context, id int64,
+) (ContestAddressReport, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/addresses", id), nil,
+	)
+	if err != nil {
+		return ContestAddressReport{}, err
+	}
+	var respData ContestAddressReport
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestDashboard(
+	ctx context.Context, id int64,
+) (ContestDashboard, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/dashboard", id), nil,
+	)
+	if err != nil {
+		return ContestDashboard{}, err
+	}
+	var respData ContestDashboard
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ExportContestSolutions starts export of contest solutions and
+// returns its status.
+func (c *Client) ExportContestSolutions(
+	ctx context.Context, id int64, refresh bool,
+) (ContestExport, error) {
+	query := url.Values{}
+	if refresh {
+		query.Add("refresh", "t")
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/solutions/export?%s", id, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return ContestExport{}, err
+	}
+	var respData ContestExport
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// DownloadContestSolutionsExport returns generated archive of contest
+// solutions.
+func (c *Client) DownloadContestSolutionsExport(
+	ctx context.Context, id int64,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/solutions/export/download", id),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return io.ReadAll(resp.Body)
+}
+
+func (c *Client) ObserveContestStatistics(
+	ctx context.Context, id int64,
+) (ContestStatistics, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/statistics", id), nil,
+	)
+	if err != nil {
+		return ContestStatistics{}, err
+	}
+	var respData ContestStatistics
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveLabelContestStatistics returns statistics of participants with
+// specified label.
+func (c *Client) ObserveLabelContestStatistics(
+	ctx context.Context, id int64, label string,
+) (ContestStatistics, error) {
+	query := url.Values{}
+	query.Add("label", label)
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/statistics?%s", id, query.Encode()), nil,
+	)
+	if err != nil {
+		return ContestStatistics{}, err
+	}
+	var respData ContestStatistics
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestFeedback(
+	ctx context.Context, id int64,
+) (ContestFeedback, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/feedback", id), nil,
+	)
+	if err != nil {
+		return ContestFeedback{}, err
+	}
+	var respData ContestFeedback
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) SubmitContestFeedback(
+	ctx context.Context, id int64, form SubmitContestFeedbackForm,
+) (ContestFeedback, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestFeedback{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/feedback", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestFeedback{}, err
+	}
+	var respData ContestFeedback
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestFeedbackSummary(
+	ctx context.Context, id int64,
+) (ContestFeedbackSummary, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/feedback/summary", id), nil,
+	)
+	if err != nil {
+		return ContestFeedbackSummary{}, err
+	}
+	var respData ContestFeedbackSummary
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestResults(
+	ctx context.Context, id int64,
+) (ContestResults, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/results", id), nil,
+	)
+	if err != nil {
+		return ContestResults{}, err
+	}
+	var respData ContestResults
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) FinalizeContest(
+	ctx context.Context, id int64,
+) (ContestResults, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/finalize", id), nil,
+	)
+	if err != nil {
+		return ContestResults{}, err
+	}
+	var respData ContestResults
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) UnfinalizeContest(
+	ctx context.Context, id int64, form UnfinalizeContestForm,
+) (Contest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Contest{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/unfinalize", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+	var respData Contest
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestSolutions(
+	ctx context.Context, id int64,
+) (ContestSolutions, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/solutions", id), nil,
+	)
+	if err != nil {
+		return ContestSolutions{}, err
+	}
+	var respData ContestSolutions
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestSolution(
+	ctx context.Context, id int64, solutionID int64,
+) (ContestSolution, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/solutions/%d", id, solutionID), nil,
+	)
+	if err != nil {
+		return ContestSolution{}, err
+	}
+	var respData ContestSolution
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestParticipant(
+	ctx context.Context,
+	contest int64,
+	form CreateContestParticipantForm,
+) (ContestParticipant, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/participants", contest),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) UpdateContestParticipant(
+	ctx context.Context,
+	contest int64,
+	participant int64,
+	form UpdateContestParticipantForm,
+) (ContestParticipant, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPatch,
+		c.getURL("/v0/contests/%d/participants/%d", contest, participant),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestGroupParticipants(
+	ctx context.Context,
+	contest int64,
+	group int64,
+	form CreateContestGroupParticipantsForm,
+) (ContestParticipants, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestParticipants{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/participants/groups/%d", contest, group),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestParticipants{}, err
+	}
+	var respData ContestParticipants
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestQueue(
+	ctx context.Context, contest int64,
+) (ContestQueue, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/queue", contest), nil,
+	)
+	if err != nil {
+		return ContestQueue{}, err
+	}
+	var respData ContestQueue
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestGrants(
+	ctx context.Context, contest int64,
+) (ContestGrants, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/grants", contest), nil,
+	)
+	if err != nil {
+		return ContestGrants{}, err
+	}
+	var respData ContestGrants
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestGrant(
+	ctx context.Context, contest int64, form CreateContestGrantForm,
+) (ContestGrant, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestGrant{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/grants", contest),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestGrant{}, err
+	}
+	var respData ContestGrant
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteContestGrant(
+	ctx context.Context, contest int64, grant int64,
+) (ContestGrant, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/contests/%d/grants/%d", contest, grant), nil,
+	)
+	if err != nil {
+		return ContestGrant{}, err
+	}
+	var respData ContestGrant
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestAppeals(
+	ctx context.Context, contest int64,
+) (ContestAppeals, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/appeals", contest), nil,
+	)
+	if err != nil {
+		return ContestAppeals{}, err
+	}
+	var respData ContestAppeals
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestAppeal(
+	ctx context.Context, contest int64, form CreateContestAppealForm,
+) (ContestAppeal, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestAppeal{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/appeals", contest),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestAppeal{}, err
+	}
+	var respData ContestAppeal
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestAppealComment(
+	ctx context.Context, contest int64, appeal int64, form CreateContestAppealCommentForm,
+) (ContestAppeal, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestAppeal{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/appeals/%d/comments", contest, appeal),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestAppeal{}, err
+	}
+	var respData ContestAppeal
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ResolveContestAppeal(
+	ctx context.Context, contest int64, appeal int64, form ResolveContestAppealForm,
+) (ContestAppeal, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestAppeal{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/appeals/%d/resolve", contest, appeal),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestAppeal{}, err
+	}
+	var respData ContestAppeal
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestScoreOverrides(
+	ctx context.Context, contest int64,
+) (ContestScoreOverrides, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/overrides", contest), nil,
+	)
+	if err != nil {
+		return ContestScoreOverrides{}, err
+	}
+	var respData ContestScoreOverrides
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestScoreOverride(
+	ctx context.Context, contest int64, form CreateContestScoreOverrideForm,
+) (ContestScoreOverride, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestScoreOverride{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/overrides", contest),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestScoreOverride{}, err
+	}
+	var respData ContestScoreOverride
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteContestScoreOverride(
+	ctx context.Context, contest int64, override int64,
+) (ContestScoreOverride, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/contests/%d/overrides/%d", contest, override), nil,
+	)
+	if err != nil {
+		return ContestScoreOverride{}, err
+	}
+	var respData ContestScoreOverride
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestEditorials(
+	ctx context.Context, contest int64,
+) (ContestEditorials, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/editorials", contest), nil,
+	)
+	if err != nil {
+		return ContestEditorials{}, err
+	}
+	var respData ContestEditorials
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestEditorial(
+	ctx context.Context, contest int64, form CreateContestEditorialForm,
+) (ContestEditorial, error) {
+	defer func() { _ = form.Close() }()
+	buf := bytes.Buffer{}
+	w := multipart.NewWriter(&buf)
+	if form.ProblemID != 0 {
+		if err := w.WriteField("problem_id", fmt.Sprint(form.ProblemID)); err != nil {
+			return ContestEditorial{}, err
+		}
+	}
+	if err := w.WriteField("locale", form.Locale); err != nil {
+		return ContestEditorial{}, err
+	}
+	if err := w.WriteField("publish", fmt.Sprint(form.Publish)); err != nil {
+		return ContestEditorial{}, err
+	}
+	if form.File != nil {
+		if fw, err := w.CreateFormFile("file", form.File.Name); err != nil {
+			return ContestEditorial{}, err
+		} else if _, err := io.Copy(fw, form.File.Reader); err != nil {
+			return ContestEditorial{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return ContestEditorial{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/editorials", contest),
+		&buf,
+	)
+	if err != nil {
+		return ContestEditorial{}, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	var respData ContestEditorial
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) PublishContestEditorial(
+	ctx context.Context, contest int64, editorial int64,
+) (ContestEditorial, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/editorials/%d/publish", contest, editorial), nil,
+	)
+	if err != nil {
+		return ContestEditorial{}, err
+	}
+	var respData ContestEditorial
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteContestEditorial(
+	ctx context.Context, contest int64, editorial int64,
+) (ContestEditorial, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/contests/%d/editorials/%d", contest, editorial), nil,
+	)
+	if err != nil {
+		return ContestEditorial{}, err
+	}
+	var respData ContestEditorial
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestDraft(
+	ctx context.Context, contest int64, problem string,
+) (ContestDraft, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/problems/%s/draft", contest, problem), nil,
+	)
+	if err != nil {
+		return ContestDraft{}, err
+	}
+	var respData ContestDraft
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) UpdateContestDraft(
+	ctx context.Context, contest int64, problem string, form UpdateContestDraftForm,
+) (ContestDraft, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestDraft{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPut,
+		c.getURL("/v0/contests/%d/problems/%s/draft", contest, problem),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestDraft{}, err
+	}
+	var respData ContestDraft
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestProblemLimits(
+	ctx context.Context, contest int64, problem string,
+) (ContestProblemLimits, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/problems/%s/limits", contest, problem), nil,
+	)
+	if err != nil {
+		return ContestProblemLimits{}, err
+	}
+	var respData ContestProblemLimits
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) UpdateContestProblemLimits(
+	ctx context.Context, contest int64, problem string, form UpdateContestProblemLimitsForm,
+) (ContestProblemLimits, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestProblemLimits{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPatch,
+		c.getURL("/v0/contests/%d/problems/%s/limits", contest, problem),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestProblemLimits{}, err
+	}
+	var respData ContestProblemLimits
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestLanguages(
+	ctx context.Context, contest int64,
+) (ContestLanguages, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/languages", contest), nil,
+	)
+	if err != nil {
+		return ContestLanguages{}, err
+	}
+	var respData ContestLanguages
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveTime(ctx context.Context) (ServerTime, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/time"), nil,
+	)
+	if err != nil {
+		return ServerTime{}, err
+	}
+	var respData ServerTime
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveSettings(ctx context.Context) (Settings, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/settings"), nil,
+	)
+	if err != nil {
+		return Settings{}, err
+	}
+	var respData Settings
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveBackupStatus(ctx context.Context) (BackupStatus, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/backups/status"), nil,
+	)
+	if err != nil {
+		return BackupStatus{}, err
+	}
+	var respData BackupStatus
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveFailedTasks(ctx context.Context) (FailedTasks, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/tasks/failed"), nil,
+	)
+	if err != nil {
+		return FailedTasks{}, err
+	}
+	var respData FailedTasks
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveFailedTask(ctx context.Context, id int64) (FailedTask, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/tasks/failed/%d", id), nil,
+	)
+	if err != nil {
+		return FailedTask{}, err
+	}
+	var respData FailedTask
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) RequeueFailedTask(ctx context.Context, id int64) (RequeuedTasks, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/tasks/failed/%d/requeue", id), nil,
+	)
+	if err != nil {
+		return RequeuedTasks{}, err
+	}
+	var respData RequeuedTasks
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) RequeueFailedTasks(
+	ctx context.Context, form RequeueFailedTasksForm,
+) (RequeuedTasks, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return RequeuedTasks{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/tasks/failed/requeue"),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return RequeuedTasks{}, err
+	}
+	var respData RequeuedTasks
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveScopeUsers(ctx context.Context, scope int64) (ScopeUsers, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/scopes/%d/users", scope), nil,
+	)
+	if err != nil {
+		return ScopeUsers{}, err
+	}
+	var respData ScopeUsers
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) IssueScopeUserLoginTokens(
+	ctx context.Context, scope int64, form IssueScopeUserLoginTokensForm,
+) (ScopeUserLoginTokens, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ScopeUserLoginTokens{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/scopes/%d/login-tokens", scope),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ScopeUserLoginTokens{}, err
+	}
+	var respData ScopeUserLoginTokens
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ConsumeLoginToken(
+	ctx context.Context, token int64, secret string,
+) (ScopeUserLoginSession, error) {
+	data, err := json.Marshal(consumeTokenForm{Secret: secret})
+	if err != nil {
+		return ScopeUserLoginSession{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/tokens/%d", token),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ScopeUserLoginSession{}, err
+	}
+	var respData ScopeUserLoginSession
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateCLIToken(
+	ctx context.Context, form CLITokenForm,
+) (CLIToken, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return CLIToken{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/cli/token"), bytes.NewReader(data),
+	)
+	if err != nil {
+		return CLIToken{}, err
+	}
+	var respData CLIToken
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveCLIContests(ctx context.Context) (CLIContests, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/cli/contests"), nil,
+	)
+	if err != nil {
+		return CLIContests{}, err
+	}
+	var respData CLIContests
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveCLIContest(
+	ctx context.Context, contest int64,
+) (CLIContestInfo, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/cli/contests/%d", contest), nil,
+	)
+	if err != nil {
+		return CLIContestInfo{}, err
+	}
+	var respData CLIContestInfo
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// WatchContestSolution calls fn for every verdict update of solution until
+// judgement is finished or stream is closed.
+func (c *Client) WatchContestSolution(
+	ctx context.Context, contest, solution int64, fn func(CLIVerdict) error,
+) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/cli/contests/%d/solutions/%d/watch", contest, solution),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	// Stream can be much longer than regular request timeout.
+	client := *c
+	client.client.Timeout = 0
+	resp, err := client.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var verdict CLIVerdict
+		if err := json.Unmarshal([]byte(data), &verdict); err != nil {
+			return err
+		}
+		if err := fn(verdict); err != nil {
+			return err
+		}
+		if verdict.Final {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *Client) ObserveGroups(ctx context.Context) (Groups, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/groups"), nil,
+	)
+	if err != nil {
+		return Groups{}, err
+	}
+	var respData Groups
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveGroup(ctx context.Context, group int64) (Group, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/groups/%d", group), nil,
+	)
+	if err != nil {
+		return Group{}, err
+	}
+	var respData Group
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateGroup(ctx context.Context, form CreateGroupForm) (Group, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Group{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/groups"),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Group{}, err
+	}
+	var respData Group
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
 	return respData, err
 }
 
@@ -716,6 +2362,25 @@ func (c *Client) ObserveAccounts(ctx context.Context) (Accounts, error) {
 	return respData, err
 }
 
+func (c *Client) MergeAccounts(
+	ctx context.Context, form MergeAccountsForm,
+) (AccountMerge, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return AccountMerge{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/accounts/merge"),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return AccountMerge{}, err
+	}
+	var respData AccountMerge
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) ObservePosts(ctx context.Context) (schema.Post, error) {
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodGet, c.getURL("/v0/posts"), nil,
@@ -828,6 +2493,82 @@ func (c *Client) DeletePost(ctx context.Context, post int64) (schema.Post, error
 	return respData, err
 }
 
+func (c *Client) ObserveVerdicts(ctx context.Context) (Verdicts, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/verdicts"), nil,
+	)
+	if err != nil {
+		return Verdicts{}, err
+	}
+	var respData Verdicts
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateFileUpload(
+	ctx context.Context, form CreateFileUploadForm,
+) (FileUpload, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return FileUpload{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/uploads"), bytes.NewReader(data),
+	)
+	if err != nil {
+		return FileUpload{}, err
+	}
+	var respData FileUpload
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveFileUpload(ctx context.Context, id int64) (FileUpload, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/uploads/%d", id), nil,
+	)
+	if err != nil {
+		return FileUpload{}, err
+	}
+	var respData FileUpload
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// UploadFileChunk uploads chunk of file starting from specified offset.
+func (c *Client) UploadFileChunk(
+	ctx context.Context, id int64, offset int64, chunk []byte,
+) (FileUpload, error) {
+	checksum := sha256.Sum256(chunk)
+	query := url.Values{}
+	query.Set("offset", fmt.Sprint(offset))
+	query.Set("sha256", hex.EncodeToString(checksum[:]))
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPut,
+		c.getURL("/v0/uploads/%d/chunks?%s", id, query.Encode()),
+		bytes.NewReader(chunk),
+	)
+	if err != nil {
+		return FileUpload{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	var respData FileUpload
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) FinalizeFileUpload(ctx context.Context, id int64) (FileUpload, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/uploads/%d/finalize", id), nil,
+	)
+	if err != nil {
+		return FileUpload{}, err
+	}
+	var respData FileUpload
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) getURL(path string, args ...any) string {
 	return c.endpoint + fmt.Sprintf(path, args...)
 }
@@ -876,3 +2617,152 @@ func (r errorWithCode) Unwrap() error {
 func (r errorWithCode) StatusCode() int {
 	return r.Code
 }
+
+func (c *Client) UpdateUser(
+	ctx context.Context, login string, form updateUserForm,
+) (User, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return User{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPatch, c.getURL("/v0/users/%s", login),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return User{}, err
+	}
+	var respData User
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveOrganizations(ctx context.Context) (Organizations, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/organizations"), nil,
+	)
+	if err != nil {
+		return Organizations{}, err
+	}
+	var respData Organizations
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateOrganization(
+	ctx context.Context, form CreateOrganizationForm,
+) (Organization, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Organization{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/organizations"),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Organization{}, err
+	}
+	var respData Organization
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) UpdateOrganization(
+	ctx context.Context, id int64, form UpdateOrganizationForm,
+) (Organization, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Organization{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPatch, c.getURL("/v0/organizations/%d", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Organization{}, err
+	}
+	var respData Organization
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteOrganization(ctx context.Context, id int64) (Organization, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete, c.getURL("/v0/organizations/%d", id), nil,
+	)
+	if err != nil {
+		return Organization{}, err
+	}
+	var respData Organization
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestMashup(
+	ctx context.Context, form CreateContestMashupForm,
+) (Contest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Contest{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/mashup"),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+	var respData Contest
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveProblemRecommendations(
+	ctx context.Context,
+) (ProblemRecommendations, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/problems/recommendations"), nil,
+	)
+	if err != nil {
+		return ProblemRecommendations{}, err
+	}
+	var respData ProblemRecommendations
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateSolutionRetest(
+	ctx context.Context, contest int64, solution int64, form CreateSolutionRetestForm,
+) (SolutionRetest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return SolutionRetest{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/solutions/%d/retest", contest, solution),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return SolutionRetest{}, err
+	}
+	var respData SolutionRetest
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveSolutionRetest(
+	ctx context.Context, contest int64, solution int64, task int64,
+) (SolutionRetest, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/solutions/%d/retest/%d", contest, solution, task), nil,
+	)
+	if err != nil {
+		return SolutionRetest{}, err
+	}
+	var respData SolutionRetest
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
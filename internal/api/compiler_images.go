@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerCompilerImageHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/compilers/:compiler/build", v.buildCompilerImage,
+		v.extractAuth(v.sessionAuth), v.extractCompiler,
+		v.requirePermission(perms.UpdateCompilerRole),
+	)
+	g.GET(
+		"/v0/compilers/:compiler/build/:task", v.observeCompilerImageBuild,
+		v.extractAuth(v.sessionAuth), v.extractCompiler,
+		v.requirePermission(perms.UpdateCompilerRole),
+	)
+}
+
+// CompilerImageBuild represents build of compiler image.
+type CompilerImageBuild struct {
+	ID      int64  `json:"id"`
+	Status  string `json:"status"`
+	Stage   string `json:"stage,omitempty"`
+	Error   string `json:"error,omitempty"`
+	ImageID int64  `json:"image_id,omitempty"`
+}
+
+func makeCompilerImageBuild(task models.Task) CompilerImageBuild {
+	resp := CompilerImageBuild{
+		ID:     task.ID,
+		Status: task.Status.String(),
+	}
+	var state models.BuildCompilerImageTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Stage = state.Stage
+		resp.Error = state.Error
+		resp.ImageID = state.ImageID
+	}
+	return resp
+}
+
+// BuildCompilerImageForm represents form for building compiler image.
+type BuildCompilerImageForm struct {
+	// Image contains reference to OCI image, for example "gcc:13".
+	Image string `json:"image"`
+	// Dockerfile contains content of Dockerfile.
+	Dockerfile string `json:"dockerfile"`
+}
+
+const maxDockerfileSize = 64 * 1024
+
+func (f BuildCompilerImageForm) Update(
+	c echo.Context, config *models.BuildCompilerImageTaskConfig,
+) error {
+	errors := errorFields{}
+	if f.Image == "" && f.Dockerfile == "" {
+		errors["image"] = errorField{
+			Message: localize(c, "Image or Dockerfile should be specified."),
+		}
+	} else if f.Image != "" && f.Dockerfile != "" {
+		errors["image"] = errorField{
+			Message: localize(c, "Only one of image and Dockerfile should be specified."),
+		}
+	}
+	if f.Image != "" {
+		if len(f.Image) > 256 {
+			errors["image"] = errorField{
+				Message: localize(c, "Image is too long."),
+			}
+		} else if strings.HasPrefix(f.Image, "-") ||
+			strings.ContainsAny(f.Image, " \t\r\n") {
+			errors["image"] = errorField{
+				Message: localize(c, "Invalid image."),
+			}
+		}
+	}
+	if len(f.Dockerfile) > maxDockerfileSize {
+		errors["dockerfile"] = errorField{
+			Message: localize(c, "Dockerfile is too large."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config.Image = f.Image
+	config.Dockerfile = f.Dockerfile
+	return nil
+}
+
+func (v *View) buildCompilerImage(c echo.Context) error {
+	compiler, ok := c.Get(compilerKey).(models.Compiler)
+	if !ok {
+		return fmt.Errorf("compiler not extracted")
+	}
+	var form BuildCompilerImageForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	config := models.BuildCompilerImageTaskConfig{CompilerID: compiler.ID}
+	if err := form.Update(c, &config); err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := task.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeCompilerImageBuild(task))
+}
+
+func (v *View) observeCompilerImageBuild(c echo.Context) error {
+	compiler, ok := c.Get(compilerKey).(models.Compiler)
+	if !ok {
+		return fmt.Errorf("compiler not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTaskID,
+			Message:   localize(c, "Invalid task ID."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	task, err := v.core.Tasks.Get(getContext(c), id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	var config models.BuildCompilerImageTaskConfig
+	if task.Kind != models.BuildCompilerImageTask ||
+		task.ScanConfig(&config) != nil || config.CompilerID != compiler.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeCompilerImageBuild(task))
+}
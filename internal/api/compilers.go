@@ -24,11 +24,13 @@ func (v *View) registerCompilerHandlers(g *echo.Group) {
 		"/v0/compilers", v.createCompiler,
 		v.extractAuth(v.sessionAuth),
 		v.requirePermission(perms.CreateCompilerRole),
+		v.limitUploadBody,
 	)
 	g.PATCH(
 		"/v0/compilers/:compiler", v.updateCompiler,
 		v.extractAuth(v.sessionAuth), v.extractCompiler,
 		v.requirePermission(perms.UpdateCompilerRole),
+		v.limitUploadBody,
 	)
 	g.DELETE(
 		"/v0/compilers/:compiler", v.deleteCompiler,
@@ -72,7 +74,7 @@ func (v *View) ObserveCompilers(c echo.Context) error {
 	if err := compilers.Err(); err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, resp)
+	return jsonWithETag(c, http.StatusOK, resp)
 }
 
 type UpdateCompilerForm struct {
@@ -128,6 +130,7 @@ func (f *UpdateCompilerForm) Update(c echo.Context, compiler *models.Compiler) e
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -142,8 +145,9 @@ type CreateCompilerForm struct {
 func (f *CreateCompilerForm) Update(c echo.Context, compiler *models.Compiler) error {
 	if f.Name == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"name": errorField{Message: localize(c, "Name is required.")},
 			},
@@ -151,8 +155,9 @@ func (f *CreateCompilerForm) Update(c echo.Context, compiler *models.Compiler) e
 	}
 	if f.Config.JSON == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"config": errorField{Message: localize(c, "Config is required.")},
 			},
@@ -160,8 +165,9 @@ func (f *CreateCompilerForm) Update(c echo.Context, compiler *models.Compiler) e
 	}
 	if f.ImageFile == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"file": errorField{Message: localize(c, "File is required.")},
 			},
@@ -263,8 +269,9 @@ func (v *View) extractCompiler(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid compiler ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidCompilerID,
+				Message:   localize(c, "Invalid compiler ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Compilers); err != nil {
@@ -274,8 +281,9 @@ func (v *View) extractCompiler(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Compiler not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeCompilerNotFound,
+					Message:   localize(c, "Compiler not found."),
 				}
 			}
 			return err
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
@@ -71,3 +72,91 @@ func TestCompilersSimpleScenario(t *testing.T) {
 		e.Check(deleted)
 	}
 }
+
+func TestCompilerImageBuild(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles(perms.CreateCompilerRole, perms.UpdateCompilerRole, perms.CreateSettingRole)
+	user.LoginClient()
+	compiler := NewTestCompiler(e)
+	if _, err := e.Client.BuildCompilerImage(
+		context.Background(), compiler.ID,
+		BuildCompilerImageForm{Image: "--help"},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	build, err := e.Client.BuildCompilerImage(
+		context.Background(), compiler.ID,
+		BuildCompilerImageForm{Dockerfile: "FROM alpine:3.18\nRUN apk add g++\n"},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(build)
+	if observed, err := e.Client.ObserveCompilerImageBuild(
+		context.Background(), compiler.ID, build.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(observed)
+	}
+	if _, err := e.Client.ObserveCompilerImageBuild(
+		context.Background(), compiler.ID, build.ID+1,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+}
+
+func TestInvokerSelfTest(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles(perms.CreateCompilerRole, perms.UpdateCompilerRole, perms.CreateSettingRole)
+	user.LoginClient()
+	compiler := NewTestCompiler(e)
+	if _, err := e.Client.CreateInvokerSelfTest(
+		context.Background(), compiler.ID,
+		CreateInvokerSelfTestForm{Tests: maxInvokerSelfTestTests + 1},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	selfTest, err := e.Client.CreateInvokerSelfTest(
+		context.Background(), compiler.ID,
+		CreateInvokerSelfTestForm{Invoker: "new-invoker", Tests: 5},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if selfTest.Status != "queued" {
+		t.Fatalf("Unexpected status: %q", selfTest.Status)
+	}
+	if observed, err := e.Client.ObserveInvokerSelfTest(
+		context.Background(), compiler.ID, selfTest.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if observed.ID != selfTest.ID {
+		t.Fatalf("Expected %d, got %d", selfTest.ID, observed.ID)
+	}
+	if _, err := e.Client.ObserveInvokerSelfTest(
+		context.Background(), compiler.ID, selfTest.ID+1,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+}
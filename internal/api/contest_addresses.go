@@ -0,0 +1,279 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestAddressHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/addresses", v.observeContestAddresses,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestDashboardRole),
+	)
+}
+
+// contestAddressUpdateInterval contains minimal duration between updates
+// of last time of the same participant address.
+const contestAddressUpdateInterval = time.Minute
+
+type contestAddressKey struct {
+	ParticipantID int64
+	SessionID     int64
+	RealIP        string
+	UserAgent     string
+}
+
+// contestAddressTracker remembers recently saved participant addresses,
+// so database is updated at most once per interval for every address.
+type contestAddressTracker struct {
+	mutex     sync.Mutex
+	addresses map[contestAddressKey]models.ContestParticipantAddress
+}
+
+func newContestAddressTracker() *contestAddressTracker {
+	return &contestAddressTracker{
+		addresses: map[contestAddressKey]models.ContestParticipantAddress{},
+	}
+}
+
+// Reserve returns saved address and true if address should be updated.
+//
+// Address is marked as updated, so concurrent requests will not
+// update it again.
+func (t *contestAddressTracker) Reserve(
+	key contestAddressKey, now int64,
+) (models.ContestParticipantAddress, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	address, ok := t.addresses[key]
+	if ok && address.LastTime+int64(contestAddressUpdateInterval/time.Second) > now {
+		return address, false
+	}
+	reserved := address
+	reserved.LastTime = now
+	t.addresses[key] = reserved
+	return address, true
+}
+
+// Commit remembers saved address.
+func (t *contestAddressTracker) Commit(
+	key contestAddressKey, address models.ContestParticipantAddress,
+) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.addresses[key] = address
+}
+
+// trackContestAddress saves address and session of participant that
+// sends request during running contest.
+func (v *View) trackContestAddress(c echo.Context, contestCtx *managers.ContestContext) {
+	participant := contestCtx.GetEffectiveParticipant()
+	if participant == nil || participant.Kind != models.RegularParticipant {
+		return
+	}
+	if contestCtx.GetEffectiveContestTime().Stage() != managers.ContestStarted {
+		return
+	}
+	key := contestAddressKey{
+		ParticipantID: participant.ID,
+		RealIP:        c.RealIP(),
+		UserAgent:     c.Request().UserAgent(),
+	}
+	if session, ok := c.Get(authSessionKey).(models.Session); ok {
+		key.SessionID = session.ID
+	}
+	now := getNow(c).Unix()
+	address, ok := v.contestAddresses.Reserve(key, now)
+	if !ok {
+		return
+	}
+	ctx := getContext(c)
+	if address.ID == 0 {
+		addresses, err := collectRows(
+			v.core.ContestParticipantAddresses.FindByParticipant(ctx, participant.ID),
+		)
+		if err != nil {
+			c.Logger().Warn("Cannot find participant addresses", err)
+			return
+		}
+		for _, other := range addresses {
+			if int64(other.SessionID) == key.SessionID &&
+				other.RealIP == key.RealIP && other.UserAgent == key.UserAgent {
+				address = other
+				break
+			}
+		}
+	}
+	if address.ID == 0 {
+		address = models.ContestParticipantAddress{
+			ContestID:     contestCtx.Contest.ID,
+			ParticipantID: participant.ID,
+			SessionID:     NInt64(key.SessionID),
+			RealIP:        key.RealIP,
+			UserAgent:     key.UserAgent,
+			FirstTime:     now,
+			LastTime:      now,
+		}
+		if err := v.core.ContestParticipantAddresses.Create(ctx, &address); err != nil {
+			c.Logger().Warn("Cannot create participant address", err)
+			return
+		}
+	} else {
+		address.LastTime = now
+		if err := v.core.ContestParticipantAddresses.Update(ctx, address); err != nil {
+			c.Logger().Warn("Cannot update participant address", err)
+			return
+		}
+	}
+	v.contestAddresses.Commit(key, address)
+}
+
+// ContestAddress represents address that was used by participant.
+type ContestAddress struct {
+	SessionID int64  `json:"session_id,omitempty"`
+	RealIP    string `json:"real_ip"`
+	UserAgent string `json:"user_agent,omitempty"`
+	FirstTime int64  `json:"first_time"`
+	LastTime  int64  `json:"last_time"`
+}
+
+// ContestSharedAddress represents address that was used by participants
+// of different accounts.
+type ContestSharedAddress struct {
+	RealIP       string               `json:"real_ip"`
+	Participants []ContestParticipant `json:"participants"`
+}
+
+// ContestConcurrentSessions represents participant that used several
+// sessions at the same time.
+type ContestConcurrentSessions struct {
+	Participant ContestParticipant `json:"participant"`
+	Addresses   []ContestAddress   `json:"addresses"`
+}
+
+// ContestAddressReport represents report of suspicious participants.
+type ContestAddressReport struct {
+	SharedAddresses    []ContestSharedAddress      `json:"shared_addresses"`
+	ConcurrentSessions []ContestConcurrentSessions `json:"concurrent_sessions"`
+}
+
+func makeContestAddress(address models.ContestParticipantAddress) ContestAddress {
+	return ContestAddress{
+		SessionID: int64(address.SessionID),
+		RealIP:    address.RealIP,
+		UserAgent: address.UserAgent,
+		FirstTime: address.FirstTime,
+		LastTime:  address.LastTime,
+	}
+}
+
+// hasConcurrentSessions returns true if addresses contain different
+// sessions with intersecting usage intervals.
+func hasConcurrentSessions(addresses []models.ContestParticipantAddress) bool {
+	for i, lhs := range addresses {
+		for _, rhs := range addresses[i+1:] {
+			if lhs.SessionID == rhs.SessionID {
+				continue
+			}
+			if lhs.FirstTime <= rhs.LastTime && rhs.FirstTime <= lhs.LastTime {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *View) observeContestAddresses(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestParticipants); err != nil {
+		return err
+	}
+	addresses, err := collectRows(v.core.ContestParticipantAddresses.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	))
+	if err != nil {
+		return err
+	}
+	sortFunc(addresses, func(lhs, rhs models.ContestParticipantAddress) bool {
+		return lhs.ID < rhs.ID
+	})
+	participants := map[int64]models.ContestParticipant{}
+	byParticipant := map[int64][]models.ContestParticipantAddress{}
+	byAddress := map[string][]int64{}
+	var realIPs []string
+	for _, address := range addresses {
+		if _, ok := participants[address.ParticipantID]; !ok {
+			participant, err := v.core.ContestParticipants.Get(
+				getContext(c), address.ParticipantID,
+			)
+			if err != nil {
+				continue
+			}
+			participants[address.ParticipantID] = participant
+		}
+		byParticipant[address.ParticipantID] = append(
+			byParticipant[address.ParticipantID], address,
+		)
+		ids, ok := byAddress[address.RealIP]
+		if !ok {
+			realIPs = append(realIPs, address.RealIP)
+		}
+		found := false
+		for _, id := range ids {
+			found = found || id == address.ParticipantID
+		}
+		if !found {
+			byAddress[address.RealIP] = append(ids, address.ParticipantID)
+		}
+	}
+	resp := ContestAddressReport{
+		SharedAddresses:    []ContestSharedAddress{},
+		ConcurrentSessions: []ContestConcurrentSessions{},
+	}
+	for _, realIP := range realIPs {
+		ids := byAddress[realIP]
+		accounts := map[int64]struct{}{}
+		for _, id := range ids {
+			accounts[participants[id].AccountID] = struct{}{}
+		}
+		if len(accounts) < 2 {
+			continue
+		}
+		shared := ContestSharedAddress{RealIP: realIP}
+		for _, id := range ids {
+			shared.Participants = append(
+				shared.Participants,
+				makeContestParticipant(c, participants[id], v.core),
+			)
+		}
+		resp.SharedAddresses = append(resp.SharedAddresses, shared)
+	}
+	for id, participant := range participants {
+		participantAddresses := byParticipant[id]
+		if !hasConcurrentSessions(participantAddresses) {
+			continue
+		}
+		sessions := ContestConcurrentSessions{
+			Participant: makeContestParticipant(c, participant, v.core),
+		}
+		for _, address := range participantAddresses {
+			sessions.Addresses = append(sessions.Addresses, makeContestAddress(address))
+		}
+		resp.ConcurrentSessions = append(resp.ConcurrentSessions, sessions)
+	}
+	sortFunc(resp.ConcurrentSessions, func(lhs, rhs ContestConcurrentSessions) bool {
+		return lhs.Participant.ID < rhs.Participant.ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
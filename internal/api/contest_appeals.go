@@ -0,0 +1,559 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestAppealHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/appeals", v.observeContestAppeals,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/appeals", v.createContestAppeal,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.CreateContestAppealRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/appeals/:appeal/comments", v.createContestAppealComment,
+		v.extractAuth(v.sessionAuth), v.extractContest, v.extractContestAppeal,
+	)
+	g.POST(
+		"/v0/contests/:contest/appeals/:appeal/resolve", v.resolveContestAppeal,
+		v.extractAuth(v.sessionAuth), v.extractContest, v.extractContestAppeal,
+		v.requirePermission(perms.ResolveContestAppealRole),
+	)
+}
+
+type ContestAppealStatus = models.ContestAppealStatus
+
+type ContestAppealComment struct {
+	Author     GrantAccount `json:"author"`
+	Text       string       `json:"text"`
+	CreateTime int64        `json:"create_time"`
+}
+
+type ContestAppeal struct {
+	ID          int64                  `json:"id"`
+	SolutionID  int64                  `json:"solution_id"`
+	Participant ContestParticipant     `json:"participant"`
+	Status      ContestAppealStatus    `json:"status"`
+	Message     string                 `json:"message"`
+	Comments    []ContestAppealComment `json:"comments,omitempty"`
+	CreateTime  int64                  `json:"create_time"`
+	ResolveTime NInt64                 `json:"resolve_time,omitempty"`
+}
+
+type ContestAppeals struct {
+	Appeals []ContestAppeal `json:"appeals"`
+}
+
+func (v *View) makeContestAppeal(c echo.Context, appeal models.ContestAppeal) ContestAppeal {
+	resp := ContestAppeal{
+		ID:          appeal.ID,
+		SolutionID:  appeal.SolutionID,
+		Status:      appeal.Status,
+		Message:     appeal.Message,
+		CreateTime:  appeal.CreateTime,
+		ResolveTime: appeal.ResolveTime,
+	}
+	if participant, err := v.core.ContestParticipants.Get(
+		getContext(c), appeal.ParticipantID,
+	); err == nil {
+		resp.Participant = makeContestParticipant(c, participant, v.core)
+	}
+	if comments, err := appeal.GetComments(); err == nil {
+		for _, comment := range comments {
+			resp.Comments = append(resp.Comments, ContestAppealComment{
+				Author:     v.makeGrantAccount(c, comment.AuthorID),
+				Text:       comment.Text,
+				CreateTime: comment.CreateTime,
+			})
+		}
+	}
+	return resp
+}
+
+// isContestAppealParticipant returns true if appeal is filed by one
+// of account participants.
+func isContestAppealParticipant(
+	ctx *managers.ContestContext, appeal models.ContestAppeal,
+) bool {
+	for _, participant := range ctx.Participants {
+		if participant.ID != 0 && participant.ID == appeal.ParticipantID {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *View) observeContestAppeals(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestAppeals); err != nil {
+		return err
+	}
+	appeals, err := v.core.ContestAppeals.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = appeals.Close() }()
+	observeAll := contestCtx.HasPermission(perms.ObserveContestAppealsRole)
+	resp := ContestAppeals{Appeals: []ContestAppeal{}}
+	for appeals.Next() {
+		appeal := appeals.Row()
+		if !observeAll && !isContestAppealParticipant(contestCtx, appeal) {
+			continue
+		}
+		resp.Appeals = append(resp.Appeals, v.makeContestAppeal(c, appeal))
+	}
+	if err := appeals.Err(); err != nil {
+		return err
+	}
+	sort.Slice(resp.Appeals, func(i, j int) bool {
+		return resp.Appeals[i].ID > resp.Appeals[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateContestAppealForm struct {
+	SolutionID int64  `json:"solution_id"`
+	Message    string `json:"message"`
+}
+
+func (f CreateContestAppealForm) Update(
+	c echo.Context, o *models.ContestAppeal,
+	ctx *managers.ContestContext, v *View,
+) error {
+	if len(f.Message) < 4 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"message": errorField{Message: localize(c, "Message is too short.")},
+			},
+		}
+	} else if len(f.Message) > 1024 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"message": errorField{Message: localize(c, "Message is too long.")},
+			},
+		}
+	}
+	solution, err := v.core.ContestSolutions.Get(getContext(c), f.SolutionID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeSolutionNotFound,
+			Message:   localize(c, "Solution not found."),
+		}
+	}
+	if solution.ContestID != ctx.Contest.ID {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeSolutionNotFound,
+			Message:   localize(c, "Solution not found."),
+		}
+	}
+	var participant *models.ContestParticipant
+	for i := range ctx.Participants {
+		if ctx.Participants[i].ID != 0 && ctx.Participants[i].ID == solution.ParticipantID {
+			participant = &ctx.Participants[i]
+			break
+		}
+	}
+	if participant == nil {
+		return errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
+		}
+	}
+	// Upsolving does not affect results, so it can not be appealed.
+	switch participant.Kind {
+	case models.RegularParticipant, models.VirtualParticipant:
+	default:
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeSolutionNotAppealable,
+			Message:   localize(c, "Solution can not be appealed."),
+		}
+	}
+	base, err := v.core.Solutions.Get(getContext(c), solution.ID)
+	if err != nil {
+		return err
+	}
+	if report, err := base.GetReport(); err != nil || report == nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeSolutionNotJudged,
+			Message:   localize(c, "Solution is not judged."),
+		}
+	}
+	o.SolutionID = solution.ID
+	o.ParticipantID = participant.ID
+	o.Message = f.Message
+	return nil
+}
+
+func (v *View) createContestAppeal(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestAppealForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	appeal := models.ContestAppeal{
+		ContestID:  contestCtx.Contest.ID,
+		AuthorID:   contestCtx.Account.ID,
+		Status:     models.PendingContestAppeal,
+		CreateTime: getNow(c).Unix(),
+	}
+	if err := form.Update(c, &appeal, contestCtx, v); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestAppeals); err != nil {
+		return err
+	}
+	appeals, err := v.core.ContestAppeals.FindBySolution(getContext(c), appeal.SolutionID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = appeals.Close() }()
+	for appeals.Next() {
+		if appeals.Row().Status == models.PendingContestAppeal {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeAppealAlreadyFiled,
+				Message:   localize(c, "Appeal is already filed."),
+			}
+		}
+	}
+	if err := appeals.Err(); err != nil {
+		return err
+	}
+	if err := v.core.ContestAppeals.Create(getContext(c), &appeal); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, v.makeContestAppeal(c, appeal))
+}
+
+type CreateContestAppealCommentForm struct {
+	Text string `json:"text"`
+}
+
+func (f CreateContestAppealCommentForm) Validate(c echo.Context) error {
+	if len(f.Text) < 2 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"text": errorField{Message: localize(c, "Text is too short.")},
+			},
+		}
+	} else if len(f.Text) > 1024 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"text": errorField{Message: localize(c, "Text is too long.")},
+			},
+		}
+	}
+	return nil
+}
+
+func addContestAppealComment(
+	appeal *models.ContestAppeal, accountID int64, text string, now int64,
+) error {
+	comments, err := appeal.GetComments()
+	if err != nil {
+		return err
+	}
+	comments = append(comments, models.ContestAppealComment{
+		AuthorID:   accountID,
+		Text:       text,
+		CreateTime: now,
+	})
+	return appeal.SetComments(comments)
+}
+
+func (v *View) createContestAppealComment(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	appeal, ok := c.Get(contestAppealKey).(models.ContestAppeal)
+	if !ok {
+		return fmt.Errorf("appeal not extracted")
+	}
+	if !contestCtx.HasPermission(perms.ResolveContestAppealRole) &&
+		!isContestAppealParticipant(contestCtx, appeal) {
+		return errorResponse{
+			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{perms.ResolveContestAppealRole},
+		}
+	}
+	if appeal.Status != models.PendingContestAppeal {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeAppealAlreadyResolved,
+			Message:   localize(c, "Appeal is already resolved."),
+		}
+	}
+	var form CreateContestAppealCommentForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Validate(c); err != nil {
+		return err
+	}
+	if err := addContestAppealComment(
+		&appeal, contestCtx.Account.ID, form.Text, getNow(c).Unix(),
+	); err != nil {
+		return err
+	}
+	if err := v.core.ContestAppeals.Update(getContext(c), appeal); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, v.makeContestAppeal(c, appeal))
+}
+
+type ResolveContestAppealForm struct {
+	Status ContestAppealStatus `json:"status"`
+	// Comment contains optional explanation of resolution.
+	Comment string `json:"comment"`
+	// Rejudge enables rejudge of appealed solution.
+	Rejudge bool `json:"rejudge"`
+	// Points contains new amount of points for appealed solution.
+	Points *float64 `json:"points"`
+}
+
+func (f ResolveContestAppealForm) Validate(c echo.Context) error {
+	errors := errorFields{}
+	switch f.Status {
+	case models.AcceptedContestAppeal:
+		if f.Rejudge && f.Points != nil {
+			errors["points"] = errorField{
+				Message: localize(c, "Points can not be changed with rejudge."),
+			}
+		} else if f.Points != nil && *f.Points < 0 {
+			errors["points"] = errorField{
+				Message: localize(c, "Points should be non-negative."),
+			}
+		}
+	case models.RejectedContestAppeal:
+		if f.Rejudge || f.Points != nil {
+			errors["status"] = errorField{
+				Message: localize(c, "Rejected appeal can not change solution."),
+			}
+		}
+	default:
+		errors["status"] = errorField{
+			Message: localize(c, "Invalid status."),
+		}
+	}
+	if len(f.Comment) > 1024 {
+		errors["comment"] = errorField{
+			Message: localize(c, "Comment is too long."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) resolveContestAppeal(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	appeal, ok := c.Get(contestAppealKey).(models.ContestAppeal)
+	if !ok {
+		return fmt.Errorf("appeal not extracted")
+	}
+	if appeal.Status != models.PendingContestAppeal {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeAppealAlreadyResolved,
+			Message:   localize(c, "Appeal is already resolved."),
+		}
+	}
+	var form ResolveContestAppealForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Validate(c); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	solution, err := v.core.Solutions.Get(getContext(c), appeal.SolutionID)
+	if err != nil {
+		return err
+	}
+	if form.Points != nil {
+		report, err := solution.GetReport()
+		if err != nil {
+			return err
+		}
+		if report == nil {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeSolutionNotJudged,
+				Message:   localize(c, "Solution is not judged."),
+			}
+		}
+		report.Points = form.Points
+		if err := solution.SetReport(report); err != nil {
+			return err
+		}
+	}
+	now := getNow(c).Unix()
+	if form.Comment != "" {
+		if err := addContestAppealComment(
+			&appeal, contestCtx.Account.ID, form.Comment, now,
+		); err != nil {
+			return err
+		}
+	}
+	appeal.Status = form.Status
+	appeal.ResolverID = NInt64(contestCtx.Account.ID)
+	appeal.ResolveTime = NInt64(now)
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if form.Rejudge {
+			if err := solution.SetReport(nil); err != nil {
+				return err
+			}
+			task := models.Task{}
+			if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+				SolutionID:    solution.ID,
+				EnablePoints:  getEnablePoints(contestCtx),
+				ParticipantID: appeal.ParticipantID,
+			}); err != nil {
+				return err
+			}
+			if err := v.core.Tasks.Create(ctx, &task); err != nil {
+				return err
+			}
+		}
+		if form.Rejudge || form.Points != nil {
+			if err := v.core.Solutions.Update(ctx, solution); err != nil {
+				return err
+			}
+		}
+		return v.core.ContestAppeals.Update(ctx, appeal)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	if form.Rejudge || form.Points != nil {
+		// Standings should be recomputed with updated solution.
+		v.standings.InvalidateContest(contestCtx.Contest.ID)
+	}
+	return c.JSON(http.StatusOK, v.makeContestAppeal(c, appeal))
+}
+
+func (v *View) extractContestAppeal(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("appeal"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidAppealID,
+				Message:   localize(c, "Invalid appeal ID."),
+			}
+		}
+		contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+		if !ok {
+			return fmt.Errorf("contest not extracted")
+		}
+		if err := syncStore(c, v.core.ContestAppeals); err != nil {
+			return err
+		}
+		appeal, err := v.core.ContestAppeals.Get(getContext(c), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeAppealNotFound,
+					Message:   localize(c, "Appeal not found."),
+				}
+			}
+			return err
+		}
+		if appeal.ContestID != contestCtx.Contest.ID {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeAppealNotFound,
+				Message:   localize(c, "Appeal not found."),
+			}
+		}
+		if !contestCtx.HasPermission(perms.ObserveContestAppealsRole) &&
+			!isContestAppealParticipant(contestCtx, appeal) {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeAppealNotFound,
+				Message:   localize(c, "Appeal not found."),
+			}
+		}
+		c.Set(contestAppealKey, appeal)
+		return next(c)
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestDashboardHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/dashboard", v.observeContestDashboard,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestDashboardRole),
+	)
+}
+
+// ContestDashboardError represents solution that is failed to judge.
+type ContestDashboardError struct {
+	SolutionID int64 `json:"solution_id"`
+	ProblemID  int64 `json:"problem_id"`
+	CreateTime int64 `json:"create_time"`
+}
+
+// ContestDashboardInvoker represents health of invoker host.
+type ContestDashboardInvoker struct {
+	Name     string `json:"name"`
+	Workers  int64  `json:"workers"`
+	PingTime int64  `json:"ping_time"`
+	// Alive means that invoker has sent heartbeat recently.
+	Alive bool `json:"alive"`
+	// ClockOffset contains offset of invoker clock relative to clock
+	// of database in milliseconds.
+	ClockOffset *int64 `json:"clock_offset,omitempty"`
+	// ClockSkewed means that invoker does not judge because of clock skew.
+	ClockSkewed bool `json:"clock_skewed,omitempty"`
+}
+
+// ContestDashboard represents summary for jury overview page.
+type ContestDashboard struct {
+	// PendingQuestions contains amount of questions without answer.
+	PendingQuestions int `json:"pending_questions"`
+	// Queued contains amount of queued solutions of contest.
+	Queued int `json:"queued"`
+	// Running contains amount of running solutions of contest.
+	Running int `json:"running"`
+	// TotalQueued contains amount of queued judge tasks of all contests.
+	TotalQueued int `json:"total_queued"`
+	// FailedTasks contains amount of failed tasks of contest solutions.
+	FailedTasks int                       `json:"failed_tasks"`
+	Errors      []ContestDashboardError   `json:"errors"`
+	Invokers    []ContestDashboardInvoker `json:"invokers"`
+}
+
+const (
+	// maxDashboardErrors contains amount of recent errors in dashboard.
+	maxDashboardErrors = 10
+	// invokerAliveTimeout contains maximal duration between heartbeats
+	// of alive invoker.
+	invokerAliveTimeout = time.Minute
+)
+
+func (v *View) observeContestDashboard(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	for _, store := range []any{
+		v.core.ContestMessages, v.core.ContestSolutions, v.core.Solutions, v.core.Tasks,
+	} {
+		if err := syncStore(c, store); err != nil {
+			return err
+		}
+	}
+	resp := ContestDashboard{
+		Errors:   []ContestDashboardError{},
+		Invokers: []ContestDashboardInvoker{},
+	}
+	messages, err := collectRows(v.core.ContestMessages.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	))
+	if err != nil {
+		return err
+	}
+	answered := map[int64]struct{}{}
+	for _, message := range messages {
+		if message.Kind == models.AnswerContestMessage && message.ParentID != 0 {
+			answered[int64(message.ParentID)] = struct{}{}
+		}
+	}
+	for _, message := range messages {
+		if message.Kind != models.QuestionContestMessage {
+			continue
+		}
+		if _, ok := answered[message.ID]; !ok {
+			resp.PendingQuestions++
+		}
+	}
+	solutions, err := collectRows(v.core.ContestSolutions.ReverseFindByContestFrom(
+		getContext(c), []int64{contestCtx.Contest.ID}, 0,
+	))
+	if err != nil {
+		return err
+	}
+	contestSolutions := map[int64]struct{}{}
+	for _, solution := range solutions {
+		contestSolutions[solution.ID] = struct{}{}
+		if len(resp.Errors) >= maxDashboardErrors {
+			continue
+		}
+		baseSolution, err := v.core.Solutions.Get(getContext(c), solution.ID)
+		if err != nil {
+			continue
+		}
+		report, err := baseSolution.GetReport()
+		if err != nil || report == nil || report.Verdict != models.Failed {
+			continue
+		}
+		resp.Errors = append(resp.Errors, ContestDashboardError{
+			SolutionID: solution.ID,
+			ProblemID:  solution.ProblemID,
+			CreateTime: baseSolution.CreateTime,
+		})
+	}
+	tasks, err := collectRows(v.core.Tasks.FindByStatus(
+		getContext(c), models.QueuedTask, models.RunningTask, models.FailedTask,
+	))
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if task.Kind != models.JudgeSolutionTask {
+			continue
+		}
+		if task.Status == models.QueuedTask {
+			resp.TotalQueued++
+		}
+		var config models.JudgeSolutionTaskConfig
+		if err := task.ScanConfig(&config); err != nil {
+			continue
+		}
+		if _, ok := contestSolutions[config.SolutionID]; !ok {
+			continue
+		}
+		switch task.Status {
+		case models.QueuedTask:
+			resp.Queued++
+		case models.RunningTask:
+			resp.Running++
+		case models.FailedTask:
+			resp.FailedTasks++
+		}
+	}
+	heartbeats, err := collectRows(v.core.InvokerHeartbeats.All(getContext(c)))
+	if err != nil {
+		return err
+	}
+	now := getNow(c)
+	for _, heartbeat := range heartbeats {
+		pingTime := time.Unix(heartbeat.PingTime, 0)
+		invoker := ContestDashboardInvoker{
+			Name:     heartbeat.Name,
+			Workers:  heartbeat.Workers,
+			PingTime: heartbeat.PingTime,
+			Alive:    now.Sub(pingTime) <= invokerAliveTimeout,
+		}
+		if heartbeat.ClockOffset != 0 {
+			offset := int64(heartbeat.ClockOffset)
+			invoker.ClockOffset = &offset
+			invoker.ClockSkewed = v.core.IsClockSkewed(
+				time.Duration(offset) * time.Millisecond,
+			)
+		}
+		resp.Invokers = append(resp.Invokers, invoker)
+	}
+	sortFunc(resp.Invokers, func(lhs, rhs ContestDashboardInvoker) bool {
+		return lhs.Name < rhs.Name
+	})
+	return c.JSON(http.StatusOK, resp)
+}
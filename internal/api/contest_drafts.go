@@ -0,0 +1,188 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestDraftHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/problems/:problem/draft",
+		v.observeContestDraft, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestProblem,
+		v.requirePermission(perms.ObserveContestProblemRole),
+	)
+	g.PUT(
+		"/v0/contests/:contest/problems/:problem/draft",
+		v.updateContestDraft, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestProblem,
+		v.requirePermission(perms.SubmitContestSolutionRole),
+	)
+}
+
+// ContestDraft represents autosaved solution of participant.
+type ContestDraft struct {
+	ProblemID  int64  `json:"problem_id"`
+	CompilerID int64  `json:"compiler_id,omitempty"`
+	Content    string `json:"content"`
+	UpdateTime int64  `json:"update_time"`
+}
+
+func makeContestDraft(draft models.ContestDraft) ContestDraft {
+	return ContestDraft{
+		ProblemID:  draft.ProblemID,
+		CompilerID: int64(draft.CompilerID),
+		Content:    draft.Content,
+		UpdateTime: draft.UpdateTime,
+	}
+}
+
+// getContestDraftMaxSize returns maximal size of draft content in bytes.
+func (v *View) getContestDraftMaxSize(logger echo.Logger) int64 {
+	return v.getInt64Setting("contests.drafts.max_size", logger).OrElse(64 * 1024)
+}
+
+type UpdateContestDraftForm struct {
+	CompilerID int64  `json:"compiler_id"`
+	Content    string `json:"content"`
+}
+
+func (f *UpdateContestDraftForm) Update(
+	c echo.Context, o *models.ContestDraft, v *View,
+) error {
+	if maxSize := v.getContestDraftMaxSize(c.Logger()); int64(len(f.Content)) > maxSize {
+		return errorResponse{
+			Code:      http.StatusRequestEntityTooLarge,
+			ErrorCode: CodeDraftTooLarge,
+			Message: localize(
+				c, "Draft is larger than {size} bytes.",
+				replaceField("size", maxSize),
+			),
+		}
+	}
+	if f.CompilerID != 0 {
+		if err := syncStore(c, v.core.Compilers); err != nil {
+			return err
+		}
+		if _, err := v.core.Compilers.Get(getContext(c), f.CompilerID); err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
+				InvalidFields: errorFields{
+					"compiler_id": errorField{
+						Message: localize(c, "Compiler not found."),
+					},
+				},
+			}
+		}
+	}
+	o.CompilerID = models.NInt64(f.CompilerID)
+	o.Content = f.Content
+	return nil
+}
+
+// setContestDraftHeaders sets Last-Modified header of draft response.
+func setContestDraftHeaders(c echo.Context, draft models.ContestDraft) {
+	c.Response().Header().Set(
+		"Last-Modified",
+		time.Unix(draft.UpdateTime, 0).UTC().Format(http.TimeFormat),
+	)
+}
+
+func (v *View) observeContestDraft(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	problem, ok := c.Get(contestProblemKey).(models.ContestProblem)
+	if !ok {
+		return fmt.Errorf("contest problem not extracted")
+	}
+	notFound := errorResponse{
+		Code:      http.StatusNotFound,
+		ErrorCode: CodeDraftNotFound,
+		Message:   localize(c, "Draft not found."),
+	}
+	participant := contestCtx.GetEffectiveParticipant()
+	if participant == nil || participant.ID == 0 {
+		return notFound
+	}
+	draft, err := v.core.ContestDrafts.GetByParticipantProblem(
+		getContext(c), participant.ID, problem.ID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound
+		}
+		return err
+	}
+	setContestDraftHeaders(c, draft)
+	return c.JSON(http.StatusOK, makeContestDraft(draft))
+}
+
+func (v *View) updateContestDraft(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	problem, ok := c.Get(contestProblemKey).(models.ContestProblem)
+	if !ok {
+		return fmt.Errorf("contest problem not extracted")
+	}
+	participant := contestCtx.GetEffectiveParticipant()
+	if participant == nil || participant.ID == 0 {
+		return errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
+		}
+	}
+	var form UpdateContestDraftForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	ctx := getContext(c)
+	draft, err := v.core.ContestDrafts.GetByParticipantProblem(
+		ctx, participant.ID, problem.ID,
+	)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		draft = models.ContestDraft{
+			ContestID:     contestCtx.Contest.ID,
+			ParticipantID: participant.ID,
+			ProblemID:     problem.ID,
+		}
+	}
+	if err := form.Update(c, &draft, v); err != nil {
+		return err
+	}
+	draft.UpdateTime = getNow(c).Unix()
+	if draft.ID == 0 {
+		err = v.core.ContestDrafts.Create(ctx, &draft)
+	} else {
+		err = v.core.ContestDrafts.Update(ctx, draft)
+	}
+	if err != nil {
+		return err
+	}
+	setContestDraftHeaders(c, draft)
+	return c.JSON(http.StatusOK, makeContestDraft(draft))
+}
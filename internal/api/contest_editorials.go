@@ -0,0 +1,399 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerContestEditorialHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/editorials", v.observeContestEditorials,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestEditorialsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/editorials", v.createContestEditorial,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ManageContestEditorialsRole),
+		v.limitUploadBody,
+	)
+	g.GET(
+		"/v0/contests/:contest/editorials/:editorial/content",
+		v.observeContestEditorialContent,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.extractContestEditorial,
+		v.requirePermission(perms.ObserveContestEditorialsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/editorials/:editorial/publish",
+		v.publishContestEditorial,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.extractContestEditorial,
+		v.requirePermission(perms.ManageContestEditorialsRole),
+	)
+	g.DELETE(
+		"/v0/contests/:contest/editorials/:editorial",
+		v.deleteContestEditorial,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.extractContestEditorial,
+		v.requirePermission(perms.ManageContestEditorialsRole),
+	)
+}
+
+// ContestEditorialFormat represents format of editorial document.
+type ContestEditorialFormat string
+
+const (
+	MarkdownEditorialFormat ContestEditorialFormat = "markdown"
+	PDFEditorialFormat      ContestEditorialFormat = "pdf"
+)
+
+// getContestEditorialFormat returns format of editorial document by
+// name of file.
+func getContestEditorialFormat(name string) (ContestEditorialFormat, bool) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return MarkdownEditorialFormat, true
+	case ".pdf":
+		return PDFEditorialFormat, true
+	default:
+		return "", false
+	}
+}
+
+type ContestEditorial struct {
+	ID int64 `json:"id"`
+	// ProblemID contains ID of contest problem.
+	ProblemID int64                  `json:"problem_id,omitempty"`
+	Locale    string                 `json:"locale"`
+	Name      string                 `json:"name"`
+	Format    ContestEditorialFormat `json:"format"`
+	// Published is true when editorial is available for participants.
+	Published   bool  `json:"published"`
+	PublishTime int64 `json:"publish_time,omitempty"`
+}
+
+type ContestEditorials struct {
+	Editorials []ContestEditorial `json:"editorials"`
+}
+
+func makeContestEditorial(
+	ctx *managers.ContestContext, editorial models.ContestEditorial,
+) ContestEditorial {
+	format, _ := getContestEditorialFormat(editorial.Name)
+	return ContestEditorial{
+		ID:          editorial.ID,
+		ProblemID:   int64(editorial.ProblemID),
+		Locale:      editorial.Locale,
+		Name:        editorial.Name,
+		Format:      format,
+		Published:   isContestEditorialPublished(ctx, editorial),
+		PublishTime: int64(editorial.PublishTime),
+	}
+}
+
+// isContestEditorialPublished returns true if editorial is published
+// for effective participant.
+//
+// Editorial without manual publication is published at the end of
+// contest, so virtual participants see it only after finish.
+func isContestEditorialPublished(
+	ctx *managers.ContestContext, editorial models.ContestEditorial,
+) bool {
+	if editorial.PublishTime != 0 {
+		return int64(editorial.PublishTime) <= ctx.Now.Unix()
+	}
+	return ctx.GetEffectiveContestTime().Stage() == managers.ContestFinished
+}
+
+func canObserveContestEditorial(
+	ctx *managers.ContestContext, editorial models.ContestEditorial,
+) bool {
+	return ctx.HasPermission(perms.ManageContestEditorialsRole) ||
+		isContestEditorialPublished(ctx, editorial)
+}
+
+func (v *View) observeContestEditorials(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestEditorials); err != nil {
+		return err
+	}
+	editorials, err := v.core.ContestEditorials.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = editorials.Close() }()
+	resp := ContestEditorials{Editorials: []ContestEditorial{}}
+	for editorials.Next() {
+		editorial := editorials.Row()
+		if !canObserveContestEditorial(contestCtx, editorial) {
+			continue
+		}
+		resp.Editorials = append(
+			resp.Editorials, makeContestEditorial(contestCtx, editorial),
+		)
+	}
+	if err := editorials.Err(); err != nil {
+		return err
+	}
+	sort.Slice(resp.Editorials, func(i, j int) bool {
+		return resp.Editorials[i].ID < resp.Editorials[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateContestEditorialForm struct {
+	// ProblemID contains ID of contest problem.
+	ProblemID int64  `form:"problem_id" json:"problem_id"`
+	Locale    string `form:"locale" json:"locale"`
+	// Publish publishes editorial immediately.
+	Publish bool        `form:"publish" json:"publish"`
+	File    *FileReader `json:"-"`
+}
+
+func (f *CreateContestEditorialForm) Close() error {
+	if f.File != nil {
+		return f.File.Close()
+	}
+	return nil
+}
+
+func (f *CreateContestEditorialForm) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	formFile, err := c.FormFile("file")
+	if err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"file": {Message: localize(c, "File is required.")},
+			},
+		}
+	}
+	file, err := managers.NewMultipartFileReader(formFile)
+	if err != nil {
+		return err
+	}
+	f.File = file
+	return nil
+}
+
+func (f *CreateContestEditorialForm) Update(
+	c echo.Context, o *models.ContestEditorial,
+	ctx *managers.ContestContext, v *View,
+) error {
+	errors := errorFields{}
+	if !isValidLocaleName(f.Locale) {
+		errors["locale"] = errorField{
+			Message: localize(c, "Locale is not supported."),
+		}
+	}
+	name := filepath.Base(f.File.Name)
+	if _, ok := getContestEditorialFormat(name); !ok {
+		errors["file"] = errorField{
+			Message: localize(c, "Only Markdown and PDF documents are supported."),
+		}
+	} else if len(name) > 255 {
+		errors["file"] = errorField{
+			Message: localize(c, "Name is too long."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	if f.ProblemID != 0 {
+		problem, err := v.core.ContestProblems.Get(getContext(c), f.ProblemID)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeProblemNotFound,
+				Message:   localize(c, "Problem not found."),
+			}
+		}
+		if problem.ContestID != ctx.Contest.ID {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeProblemNotFound,
+				Message:   localize(c, "Problem not found."),
+			}
+		}
+	}
+	o.ContestID = ctx.Contest.ID
+	o.ProblemID = models.NInt64(f.ProblemID)
+	o.Locale = f.Locale
+	o.Name = name
+	if f.Publish {
+		o.PublishTime = models.NInt64(getNow(c).Unix())
+	}
+	return nil
+}
+
+func (v *View) createContestEditorial(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestEditorialForm
+	if err := form.Parse(c); err != nil {
+		return err
+	}
+	defer func() { _ = form.Close() }()
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	var editorial models.ContestEditorial
+	if err := form.Update(c, &editorial, contestCtx, v); err != nil {
+		return err
+	}
+	file, err := v.files.UploadFile(getContext(c), form.File)
+	if err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.files.ConfirmUploadFile(ctx, &file); err != nil {
+			return err
+		}
+		editorial.FileID = file.ID
+		return v.core.ContestEditorials.Create(ctx, &editorial)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	v.core.Logger().Info(
+		"Contest editorial attached",
+		logs.Any("contest_id", editorial.ContestID),
+		logs.Any("problem_id", editorial.ProblemID),
+		logs.Any("editorial_id", editorial.ID),
+		logs.Any("account_id", contestCtx.Account.ID),
+	)
+	return c.JSON(http.StatusCreated, makeContestEditorial(contestCtx, editorial))
+}
+
+func (v *View) publishContestEditorial(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	editorial, ok := c.Get(contestEditorialKey).(models.ContestEditorial)
+	if !ok {
+		return fmt.Errorf("editorial not extracted")
+	}
+	if !isContestEditorialPublished(contestCtx, editorial) {
+		editorial.PublishTime = models.NInt64(getNow(c).Unix())
+		if err := v.core.ContestEditorials.Update(getContext(c), editorial); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, makeContestEditorial(contestCtx, editorial))
+}
+
+func (v *View) deleteContestEditorial(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	editorial, ok := c.Get(contestEditorialKey).(models.ContestEditorial)
+	if !ok {
+		return fmt.Errorf("editorial not extracted")
+	}
+	if err := v.core.ContestEditorials.Delete(
+		getContext(c), editorial.ID,
+	); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeContestEditorial(contestCtx, editorial))
+}
+
+func (v *View) observeContestEditorialContent(c echo.Context) error {
+	editorial, ok := c.Get(contestEditorialKey).(models.ContestEditorial)
+	if !ok {
+		return fmt.Errorf("editorial not extracted")
+	}
+	if err := syncStore(c, v.core.Files); err != nil {
+		return err
+	}
+	file, err := v.core.Files.Get(getContext(c), editorial.FileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeFileNotFound,
+				Message:   localize(c, "File not found."),
+			}
+		}
+		return err
+	}
+	c.Set(fileKey, file)
+	return v.observeFileContent(c)
+}
+
+func (v *View) extractContestEditorial(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("editorial"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidEditorialID,
+				Message:   localize(c, "Invalid editorial ID."),
+			}
+		}
+		contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+		if !ok {
+			return fmt.Errorf("contest not extracted")
+		}
+		if err := syncStore(c, v.core.ContestEditorials); err != nil {
+			return err
+		}
+		notFound := errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeEditorialNotFound,
+			Message:   localize(c, "Editorial not found."),
+		}
+		editorial, err := v.core.ContestEditorials.Get(getContext(c), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return notFound
+			}
+			return err
+		}
+		if editorial.ContestID != contestCtx.Contest.ID ||
+			!canObserveContestEditorial(contestCtx, editorial) {
+			return notFound
+		}
+		c.Set(contestEditorialKey, editorial)
+		return next(c)
+	}
+}
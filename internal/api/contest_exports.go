@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerContestExportHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/solutions/export",
+		v.exportContestSolutions,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ExportContestSolutionsRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/solutions/export/download",
+		v.downloadContestSolutionsExport,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ExportContestSolutionsRole),
+	)
+}
+
+// ExportStatus represents status of background export.
+type ExportStatus string
+
+const (
+	// PendingExport means that archive is generating.
+	PendingExport ExportStatus = "pending"
+	// ReadyExport means that archive can be downloaded.
+	ReadyExport ExportStatus = "ready"
+	// FailedExport means that archive cannot be generated.
+	FailedExport ExportStatus = "failed"
+)
+
+// ContestExport represents background export of contest data.
+type ContestExport struct {
+	ContestID  int64        `json:"contest_id"`
+	Status     ExportStatus `json:"status"`
+	CreateTime int64        `json:"create_time"`
+	// DownloadURL contains URL of archive when it is ready.
+	DownloadURL string `json:"download_url,omitempty"`
+	// Size contains size of archive in bytes.
+	Size int64 `json:"size,omitempty"`
+}
+
+const (
+	// contestExportTTL contains lifetime of generated archives.
+	contestExportTTL = time.Hour
+	// contestExportTimeout contains timeout of archive generation.
+	//
+	// Pending export is restarted after timeout, because server
+	// that generates archive can be stopped.
+	contestExportTimeout = 30 * time.Minute
+)
+
+// reserveContestExport returns current export of contest and true if
+// new export should be started.
+func (v *View) reserveContestExport(
+	ctx context.Context, contestID int64, refresh bool, now time.Time,
+) (models.ContestExport, bool, error) {
+	var export models.ContestExport
+	var started bool
+	var oldFileID models.NInt64
+	if err := v.core.WrapTx(ctx, func(ctx context.Context) error {
+		var err error
+		export, err = v.core.ContestExports.GetByContest(ctx, contestID)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			export = models.ContestExport{ContestID: contestID}
+		} else if now.Unix() < export.ExpireTime {
+			if export.Status == models.PendingContestExport || !refresh {
+				return nil
+			}
+		}
+		oldFileID = export.FileID
+		export.Status = models.PendingContestExport
+		export.CreateTime = now.Unix()
+		export.ExpireTime = now.Add(contestExportTimeout).Unix()
+		export.FileID = 0
+		export.Size = 0
+		started = true
+		if export.ID == 0 {
+			return v.core.ContestExports.Create(ctx, &export)
+		}
+		return v.core.ContestExports.Update(ctx, export)
+	}); err != nil {
+		return models.ContestExport{}, false, err
+	}
+	if oldFileID != 0 {
+		v.deleteContestExportFile(ctx, int64(oldFileID))
+	}
+	return export, started, nil
+}
+
+// finishContestExport saves result of export.
+//
+// If export is restarted or removed, then generated archive is deleted.
+func (v *View) finishContestExport(ctx context.Context, export models.ContestExport) error {
+	saved := false
+	if err := v.core.WrapTx(ctx, func(ctx context.Context) error {
+		current, err := v.core.ContestExports.GetByContest(ctx, export.ContestID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+		if current.ID != export.ID || current.CreateTime != export.CreateTime {
+			return nil
+		}
+		saved = true
+		return v.core.ContestExports.Update(ctx, export)
+	}); err != nil {
+		return err
+	}
+	if !saved && export.FileID != 0 {
+		v.deleteContestExportFile(ctx, int64(export.FileID))
+	}
+	return nil
+}
+
+func (v *View) deleteContestExportFile(ctx context.Context, fileID int64) {
+	if err := v.files.DeleteFile(models.WithSync(ctx), fileID); err != nil {
+		v.core.Logger().Warn(
+			"Cannot delete contest export file",
+			logs.Any("file_id", fileID),
+			err,
+		)
+	}
+}
+
+// cleanupContestExports removes exports that are expired at specified
+// time with their archives.
+func (v *View) cleanupContestExports(ctx context.Context, now time.Time) error {
+	rows, err := v.core.ContestExports.FindExpired(ctx, now.Unix())
+	if err != nil {
+		return err
+	}
+	exports, err := db.CollectRows(rows)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err := v.core.ContestExports.Delete(ctx, export.ID); err != nil {
+			v.core.Logger().Warn(
+				"Cannot remove expired contest export",
+				logs.Any("id", export.ID),
+				err,
+			)
+			continue
+		}
+		if export.FileID != 0 {
+			v.deleteContestExportFile(ctx, int64(export.FileID))
+		}
+		v.core.Logger().Info(
+			"Removed expired contest export",
+			logs.Any("id", export.ID),
+			logs.Any("contest_id", export.ContestID),
+		)
+	}
+	return nil
+}
+
+// contestExportsCleanupDaemon periodically removes expired exports.
+func (v *View) contestExportsCleanupDaemon(ctx context.Context) {
+	ticker := time.NewTicker(contestExportTTL / 4)
+	defer ticker.Stop()
+	for {
+		if err := v.cleanupContestExports(ctx, time.Now()); err != nil {
+			v.core.Logger().Warn("Contest exports cleanup error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type ExportContestSolutionsRequest struct {
+	// Refresh enables generation of new archive instead of
+	// already generated one.
+	Refresh bool `query:"refresh"`
+}
+
+func (v *View) exportContestSolutions(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	var form ExportContestSolutionsRequest
+	if err := c.Bind(&form); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	export, ok, err := v.reserveContestExport(
+		getContext(c), contestCtx.Contest.ID, form.Refresh, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if ok {
+		v.core.StartTask("contest_solutions_export", func(ctx context.Context) {
+			v.runContestSolutionsExport(ctx, export)
+		})
+	}
+	return c.JSON(http.StatusOK, makeContestExport(export))
+}
+
+func (v *View) runContestSolutionsExport(ctx context.Context, export models.ContestExport) {
+	if err := func() error {
+		file, err := os.CreateTemp("", "solve-contest-solutions-*.zip")
+		if err != nil {
+			return err
+		}
+		// Archive is moved to files storage, so temporary file is
+		// not needed after upload.
+		defer func() { _ = os.Remove(file.Name()) }()
+		defer func() { _ = file.Close() }()
+		if err := v.exports.ExportSolutions(ctx, export.ContestID, file); err != nil {
+			return err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		name := fmt.Sprintf("contest-%d-solutions.zip", export.ContestID)
+		uploaded, err := v.files.UploadFile(ctx, &managers.FileReader{
+			Name:   name,
+			Reader: file,
+		})
+		if err != nil {
+			return err
+		}
+		if err := v.files.ConfirmUploadFile(ctx, &uploaded); err != nil {
+			return err
+		}
+		meta, err := uploaded.GetMeta()
+		if err != nil {
+			return err
+		}
+		export.FileID = models.NInt64(uploaded.ID)
+		export.Size = meta.Size
+		return nil
+	}(); err != nil {
+		v.core.Logger().Error(
+			"Cannot export contest solutions",
+			logs.Any("contest_id", export.ContestID),
+			err,
+		)
+		export.Status = models.FailedContestExport
+	} else {
+		export.Status = models.ReadyContestExport
+	}
+	export.ExpireTime = time.Now().Add(contestExportTTL).Unix()
+	if err := v.finishContestExport(ctx, export); err != nil {
+		v.core.Logger().Error(
+			"Cannot save contest solutions export",
+			logs.Any("contest_id", export.ContestID),
+			err,
+		)
+	}
+}
+
+func (v *View) downloadContestSolutionsExport(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	export, err := v.core.ContestExports.GetByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == sql.ErrNoRows || export.Status != models.ReadyContestExport ||
+		export.ExpireTime <= time.Now().Unix() {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeExportNotFound,
+			Message:   localize(c, "Export not found."),
+		}
+	}
+	file, err := v.files.DownloadFile(getContext(c), int64(export.FileID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeExportNotFound,
+				Message:   localize(c, "Export not found."),
+			}
+		}
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", fmt.Sprintf(
+			"contest-%d-solutions.zip", export.ContestID,
+		)),
+	)
+	return c.Stream(http.StatusOK, "application/zip", file)
+}
+
+func makeContestExport(export models.ContestExport) ContestExport {
+	resp := ContestExport{
+		ContestID:  export.ContestID,
+		Status:     ExportStatus(export.Status.String()),
+		CreateTime: export.CreateTime,
+		Size:       export.Size,
+	}
+	if export.Status == models.ReadyContestExport {
+		resp.DownloadURL = fmt.Sprintf(
+			"/api/v0/contests/%d/solutions/export/download", export.ContestID,
+		)
+	}
+	return resp
+}
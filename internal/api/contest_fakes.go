@@ -55,6 +55,7 @@ func (f *createContestFakeParticipantForm) Update(
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -104,6 +105,7 @@ func (f *createContestFakeSolutionForm) Update(
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -141,7 +143,8 @@ func (v *View) createContestFakeSolution(c echo.Context) error {
 	}
 	if problem == nil || problem.ContestID != contest.ID {
 		return errorResponse{
-			Code: http.StatusNotFound,
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeProblemNotFound,
 			Message: localize(
 				c, "Problem {code} does not exists.",
 				replaceField("code", form.ProblemCode),
@@ -152,16 +155,18 @@ func (v *View) createContestFakeSolution(c echo.Context) error {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "Participant not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeParticipantNotFound,
+				Message:   localize(c, "Participant not found."),
 			}
 		}
 		return err
 	}
 	if participant.ContestID != contest.ID {
 		return errorResponse{
-			Code:    http.StatusNotFound,
-			Message: localize(c, "Participant not found."),
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
 		}
 	}
 	solution.ContestID = contest.ID
@@ -217,6 +222,7 @@ func (v *View) makeContestFakeSolution(c echo.Context, solution models.ContestFa
 	if err == nil {
 		reportResp := SolutionReport{}
 		reportResp.Verdict = report.Verdict.String()
+		v.setReportVerdict(c, &reportResp)
 		reportResp.Points = report.Points
 		resp.Report = &reportResp
 	}
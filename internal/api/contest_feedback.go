@@ -0,0 +1,395 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestFeedbackHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/feedback", v.observeContestFeedback,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/feedback", v.submitContestFeedback,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.SubmitContestFeedbackRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/feedback/summary", v.observeContestFeedbackSummary,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestFeedbackRole),
+	)
+}
+
+const maxFeedbackTextLength = 4096
+
+func validateContestFeedbackConfig(
+	c echo.Context, config *models.ContestFeedbackConfig,
+) *errorField {
+	names := map[string]struct{}{}
+	for _, question := range config.Questions {
+		if len(question.Name) == 0 || len(question.Name) > 32 {
+			return &errorField{
+				Message: localize(c, "Invalid question name."),
+			}
+		}
+		if _, ok := names[question.Name]; ok {
+			return &errorField{
+				Message: localize(c, "Question names should be unique."),
+			}
+		}
+		names[question.Name] = struct{}{}
+		switch question.Kind {
+		case models.RatingFeedbackQuestion, models.TextFeedbackQuestion:
+		case models.ChoiceFeedbackQuestion:
+			if len(question.Options) == 0 {
+				return &errorField{
+					Message: localize(c, "Choice question should have options."),
+				}
+			}
+		default:
+			return &errorField{
+				Message: localize(c, "Invalid question kind."),
+			}
+		}
+	}
+	return nil
+}
+
+type ContestFeedbackQuestion struct {
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`
+	Kind     string   `json:"kind"`
+	Options  []string `json:"options,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+type ContestFeedback struct {
+	Questions []ContestFeedbackQuestion     `json:"questions"`
+	Anonymous bool                          `json:"anonymous,omitempty"`
+	Answers   models.ContestFeedbackAnswers `json:"answers,omitempty"`
+}
+
+func makeContestFeedbackQuestions(
+	config *models.ContestFeedbackConfig,
+) []ContestFeedbackQuestion {
+	questions := []ContestFeedbackQuestion{}
+	for _, question := range config.Questions {
+		questions = append(questions, ContestFeedbackQuestion{
+			Name:     question.Name,
+			Title:    question.Title,
+			Kind:     string(question.Kind),
+			Options:  question.Options,
+			Required: question.Required,
+		})
+	}
+	return questions
+}
+
+// getFeedbackParticipant returns participant that can submit feedback.
+func getFeedbackParticipant(ctx *managers.ContestContext) *models.ContestParticipant {
+	for i, participant := range ctx.Participants {
+		if participant.ID == 0 {
+			continue
+		}
+		switch participant.Kind {
+		case models.RegularParticipant, models.VirtualParticipant:
+			return &ctx.Participants[i]
+		}
+	}
+	return nil
+}
+
+func (v *View) findParticipantFeedback(
+	c echo.Context, participantID int64,
+) (models.ContestFeedback, error) {
+	feedbacks, err := v.core.ContestFeedbacks.FindByParticipant(
+		getContext(c), participantID,
+	)
+	if err != nil {
+		return models.ContestFeedback{}, err
+	}
+	defer func() { _ = feedbacks.Close() }()
+	if feedbacks.Next() {
+		return feedbacks.Row(), nil
+	}
+	if err := feedbacks.Err(); err != nil {
+		return models.ContestFeedback{}, err
+	}
+	return models.ContestFeedback{}, sql.ErrNoRows
+}
+
+func (v *View) observeContestFeedback(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	config := contestCtx.ContestConfig.Feedback
+	if config == nil {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeFeedbackDisabled,
+			Message:   localize(c, "Feedback is disabled."),
+		}
+	}
+	resp := ContestFeedback{
+		Questions: makeContestFeedbackQuestions(config),
+		Anonymous: config.Anonymous,
+	}
+	if participant := getFeedbackParticipant(contestCtx); participant != nil {
+		if err := syncStore(c, v.core.ContestFeedbacks); err != nil {
+			return err
+		}
+		feedback, err := v.findParticipantFeedback(c, participant.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == nil {
+			if answers, err := feedback.GetAnswers(); err == nil {
+				resp.Answers = answers
+			}
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type SubmitContestFeedbackForm struct {
+	Answers models.ContestFeedbackAnswers `json:"answers"`
+}
+
+func (f *SubmitContestFeedbackForm) Update(
+	c echo.Context, config *models.ContestFeedbackConfig,
+) error {
+	errors := errorFields{}
+	questions := map[string]struct{}{}
+	for _, question := range config.Questions {
+		questions[question.Name] = struct{}{}
+		answer, ok := f.Answers[question.Name]
+		if !ok || answer == "" {
+			if question.Required {
+				errors[question.Name] = errorField{
+					Message: localize(c, "Answer is required."),
+				}
+			}
+			continue
+		}
+		switch question.Kind {
+		case models.RatingFeedbackQuestion:
+			rating, err := strconv.Atoi(answer)
+			if err != nil || rating < 1 || rating > 5 {
+				errors[question.Name] = errorField{
+					Message: localize(c, "Rating should be from 1 to 5."),
+				}
+			}
+		case models.ChoiceFeedbackQuestion:
+			found := false
+			for _, option := range question.Options {
+				if option == answer {
+					found = true
+					break
+				}
+			}
+			if !found {
+				errors[question.Name] = errorField{
+					Message: localize(c, "Invalid option."),
+				}
+			}
+		case models.TextFeedbackQuestion:
+			if utf8.RuneCountInString(answer) > maxFeedbackTextLength {
+				errors[question.Name] = errorField{
+					Message: localize(c, "Answer is too long."),
+				}
+			}
+		}
+	}
+	for name := range f.Answers {
+		if _, ok := questions[name]; !ok {
+			errors[name] = errorField{
+				Message: localize(c, "Unknown question."),
+			}
+		}
+	}
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) submitContestFeedback(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	config := contestCtx.ContestConfig.Feedback
+	if config == nil {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeFeedbackDisabled,
+			Message:   localize(c, "Feedback is disabled."),
+		}
+	}
+	participant := getFeedbackParticipant(contestCtx)
+	if participant == nil {
+		return errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
+		}
+	}
+	var form SubmitContestFeedbackForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Update(c, config); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestFeedbacks); err != nil {
+		return err
+	}
+	feedback, err := v.findParticipantFeedback(c, participant.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err := feedback.SetAnswers(form.Answers); err != nil {
+		return err
+	}
+	if feedback.ID == 0 {
+		feedback.ContestID = contestCtx.Contest.ID
+		feedback.ParticipantID = participant.ID
+		feedback.CreateTime = getNow(c).Unix()
+		if err := v.core.ContestFeedbacks.Create(getContext(c), &feedback); err != nil {
+			return err
+		}
+	} else {
+		if err := v.core.ContestFeedbacks.Update(getContext(c), feedback); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, ContestFeedback{
+		Questions: makeContestFeedbackQuestions(config),
+		Anonymous: config.Anonymous,
+		Answers:   form.Answers,
+	})
+}
+
+type ContestFeedbackTextAnswer struct {
+	Participant *ContestParticipant `json:"participant,omitempty"`
+	Answer      string              `json:"answer"`
+}
+
+type ContestFeedbackQuestionSummary struct {
+	Name          string                      `json:"name"`
+	TotalAnswers  int                         `json:"total_answers"`
+	AverageRating *float64                    `json:"average_rating,omitempty"`
+	Options       map[string]int              `json:"options,omitempty"`
+	TextAnswers   []ContestFeedbackTextAnswer `json:"text_answers,omitempty"`
+}
+
+type ContestFeedbackSummary struct {
+	TotalFeedbacks int                              `json:"total_feedbacks"`
+	Questions      []ContestFeedbackQuestionSummary `json:"questions"`
+}
+
+func (v *View) observeContestFeedbackSummary(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	config := contestCtx.ContestConfig.Feedback
+	if config == nil {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeFeedbackDisabled,
+			Message:   localize(c, "Feedback is disabled."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestFeedbacks); err != nil {
+		return err
+	}
+	feedbacks, err := v.core.ContestFeedbacks.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = feedbacks.Close() }()
+	resp := ContestFeedbackSummary{}
+	ratings := make([]int, len(config.Questions))
+	for _, question := range config.Questions {
+		summary := ContestFeedbackQuestionSummary{Name: question.Name}
+		if question.Kind != models.TextFeedbackQuestion {
+			summary.Options = map[string]int{}
+		}
+		resp.Questions = append(resp.Questions, summary)
+	}
+	for feedbacks.Next() {
+		feedback := feedbacks.Row()
+		answers, err := feedback.GetAnswers()
+		if err != nil {
+			continue
+		}
+		resp.TotalFeedbacks++
+		for i, question := range config.Questions {
+			answer, ok := answers[question.Name]
+			if !ok || answer == "" {
+				continue
+			}
+			summary := &resp.Questions[i]
+			summary.TotalAnswers++
+			switch question.Kind {
+			case models.RatingFeedbackQuestion:
+				if rating, err := strconv.Atoi(answer); err == nil {
+					ratings[i] += rating
+				}
+				summary.Options[answer]++
+			case models.ChoiceFeedbackQuestion:
+				summary.Options[answer]++
+			case models.TextFeedbackQuestion:
+				textAnswer := ContestFeedbackTextAnswer{Answer: answer}
+				if !config.Anonymous {
+					participant, err := v.core.ContestParticipants.Get(
+						getContext(c), feedback.ParticipantID,
+					)
+					if err == nil {
+						textAnswer.Participant = getPtr(
+							makeContestParticipant(c, participant, v.core),
+						)
+					}
+				}
+				summary.TextAnswers = append(summary.TextAnswers, textAnswer)
+			}
+		}
+	}
+	if err := feedbacks.Err(); err != nil {
+		return err
+	}
+	for i, question := range config.Questions {
+		summary := &resp.Questions[i]
+		if question.Kind == models.RatingFeedbackQuestion && summary.TotalAnswers > 0 {
+			summary.AverageRating = getPtr(
+				float64(ratings[i]) / float64(summary.TotalAnswers),
+			)
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
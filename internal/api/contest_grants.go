@@ -0,0 +1,215 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestGrantHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/grants", v.observeContestGrants,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestGrantsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/grants", v.createContestGrant,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.CreateContestGrantRole),
+	)
+	g.DELETE(
+		"/v0/contests/:contest/grants/:grant", v.deleteContestGrant,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.DeleteContestGrantRole),
+	)
+}
+
+type ContestGrantKind = models.ContestGrantKind
+
+// GrantAccount represents account that has grant.
+type GrantAccount struct {
+	User      *User      `json:"user,omitempty"`
+	ScopeUser *ScopeUser `json:"scope_user,omitempty"`
+	Scope     *Scope     `json:"scope,omitempty"`
+	Group     *Group     `json:"group,omitempty"`
+}
+
+func (v *View) makeGrantAccount(c echo.Context, accountID int64) GrantAccount {
+	// Account is resolved in the same way as for participants.
+	account := makeContestParticipant(c, models.ContestParticipant{
+		AccountID: accountID,
+	}, v.core)
+	return GrantAccount{
+		User:      account.User,
+		ScopeUser: account.ScopeUser,
+		Scope:     account.Scope,
+		Group:     account.Group,
+	}
+}
+
+type ContestGrant struct {
+	ID   int64            `json:"id"`
+	Kind ContestGrantKind `json:"kind"`
+	GrantAccount
+}
+
+type ContestGrants struct {
+	Grants []ContestGrant `json:"grants"`
+}
+
+func (v *View) makeContestGrant(c echo.Context, grant models.ContestGrant) ContestGrant {
+	return ContestGrant{
+		ID:           grant.ID,
+		Kind:         grant.Kind,
+		GrantAccount: v.makeGrantAccount(c, grant.AccountID),
+	}
+}
+
+func (v *View) observeContestGrants(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestGrants); err != nil {
+		return err
+	}
+	grants, err := v.core.ContestGrants.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = grants.Close() }()
+	resp := ContestGrants{Grants: []ContestGrant{}}
+	for grants.Next() {
+		resp.Grants = append(resp.Grants, v.makeContestGrant(c, grants.Row()))
+	}
+	if err := grants.Err(); err != nil {
+		return err
+	}
+	sort.Slice(resp.Grants, func(i, j int) bool {
+		return resp.Grants[i].ID < resp.Grants[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateContestGrantForm struct {
+	Kind      ContestGrantKind `json:"kind"`
+	AccountID int64            `json:"account_id"`
+}
+
+func (f CreateContestGrantForm) Update(
+	c echo.Context, o *models.ContestGrant, v *View,
+) error {
+	if !f.Kind.IsValid() {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"kind": errorField{Message: localize(c, "Invalid kind.")},
+			},
+		}
+	}
+	account, err := getContestAccount(c, f.AccountID, v.core)
+	if err != nil {
+		return err
+	}
+	o.AccountID = account.ID
+	o.Kind = f.Kind
+	return nil
+}
+
+func (v *View) createContestGrant(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestGrantForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	grant := models.ContestGrant{ContestID: contestCtx.Contest.ID}
+	if err := form.Update(c, &grant, v); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestGrants); err != nil {
+		return err
+	}
+	grants, err := v.core.ContestGrants.FindByContestAccount(
+		getContext(c), grant.ContestID, grant.AccountID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = grants.Close() }()
+	for grants.Next() {
+		if grants.Row().Kind == grant.Kind {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeGrantAlreadyExists,
+				Message:   localize(c, "Grant already exists."),
+			}
+		}
+	}
+	if err := grants.Err(); err != nil {
+		return err
+	}
+	if err := v.core.ContestGrants.Create(getContext(c), &grant); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, v.makeContestGrant(c, grant))
+}
+
+func (v *View) deleteContestGrant(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("grant"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidGrantID,
+			Message:   localize(c, "Invalid grant ID."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestGrants); err != nil {
+		return err
+	}
+	grant, err := v.core.ContestGrants.Get(getContext(c), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeGrantNotFound,
+				Message:   localize(c, "Grant not found."),
+			}
+		}
+		return err
+	}
+	if grant.ContestID != contestCtx.Contest.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeGrantNotFound,
+			Message:   localize(c, "Grant not found."),
+		}
+	}
+	if err := v.core.ContestGrants.Delete(getContext(c), grant.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, v.makeContestGrant(c, grant))
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestLanguageHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/languages", v.observeContestLanguages,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestDashboardRole),
+	)
+}
+
+// ContestLanguage represents usage of compiler in contest.
+type ContestLanguage struct {
+	CompilerID        int64  `json:"compiler_id"`
+	Compiler          string `json:"compiler"`
+	Language          string `json:"language,omitempty"`
+	TotalSolutions    int    `json:"total_solutions"`
+	AcceptedSolutions int    `json:"accepted_solutions"`
+}
+
+type ContestLanguages struct {
+	Languages []ContestLanguage `json:"languages"`
+}
+
+// observeContestLanguages returns amount of solutions for every
+// compiler that is allowed in contest or was used in contest.
+//
+// Unlike statistics all solutions of contest are taken into account,
+// including solutions of jury and upsolving.
+func (v *View) observeContestLanguages(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	for _, store := range []any{
+		v.core.Compilers, v.core.ContestSolutions, v.core.Solutions,
+	} {
+		if err := syncStore(c, store); err != nil {
+			return err
+		}
+	}
+	compilers, err := collectRows(v.core.Compilers.All(getContext(c), 0, 0))
+	if err != nil {
+		return err
+	}
+	languages := map[int64]*ContestLanguage{}
+	for _, compiler := range compilers {
+		language := ContestLanguage{
+			CompilerID: compiler.ID,
+			Compiler:   compiler.Name,
+		}
+		if config, err := compiler.GetConfig(); err == nil {
+			language.Language = config.Language
+		}
+		languages[compiler.ID] = &language
+	}
+	solutions, err := collectRows(v.core.ContestSolutions.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	))
+	if err != nil {
+		return err
+	}
+	used := map[int64]struct{}{}
+	for _, contestSolution := range solutions {
+		solution, err := v.core.Solutions.Get(getContext(c), contestSolution.ID)
+		if err != nil {
+			continue
+		}
+		language, ok := languages[solution.CompilerID]
+		if !ok {
+			continue
+		}
+		used[solution.CompilerID] = struct{}{}
+		language.TotalSolutions++
+		if report, err := solution.GetReport(); err == nil &&
+			report != nil && report.Verdict == models.Accepted {
+			language.AcceptedSolutions++
+		}
+	}
+	resp := ContestLanguages{Languages: []ContestLanguage{}}
+	for id, language := range languages {
+		_, ok := used[id]
+		if ok || contestCtx.ContestConfig.IsCompilerAllowed(id) {
+			resp.Languages = append(resp.Languages, *language)
+		}
+	}
+	sortFunc(resp.Languages, func(lhs, rhs ContestLanguage) bool {
+		if lhs.TotalSolutions != rhs.TotalSolutions {
+			return lhs.TotalSolutions > rhs.TotalSolutions
+		}
+		return lhs.CompilerID < rhs.CompilerID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerContestMashupHandlers registers handlers for training contests
+// assembled from archive problems.
+func (v *View) registerContestMashupHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/contests/mashup", v.createContestMashup,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.CreateMashupContestRole),
+	)
+}
+
+// maxMashupProblems contains maximal amount of problems in mashup, so
+// every problem gets single letter code.
+const maxMashupProblems = 26
+
+// CreateContestMashupForm represents form for creating mashup contest.
+type CreateContestMashupForm struct {
+	Title     *string `json:"title"`
+	BeginTime *NInt64 `json:"begin_time"`
+	Duration  *int    `json:"duration"`
+	// ProblemIDs contains IDs of archive problems in order of contest
+	// problem codes.
+	ProblemIDs []int64 `json:"problem_ids"`
+}
+
+func (f CreateContestMashupForm) Update(
+	c echo.Context, contest *models.Contest,
+) error {
+	errors := errorFields{}
+	if f.Duration == nil || *f.Duration <= 0 {
+		errors["duration"] = errorField{
+			Message: localize(c, "Duration is required."),
+		}
+	}
+	if len(f.ProblemIDs) == 0 {
+		errors["problem_ids"] = errorField{
+			Message: localize(c, "Problems are required."),
+		}
+	} else if len(f.ProblemIDs) > maxMashupProblems {
+		errors["problem_ids"] = errorField{
+			Message: localize(
+				c, "Amount of problems cannot be greater than {max}.",
+				replaceField("max", maxMashupProblems),
+			),
+		}
+	} else {
+		ids := map[int64]struct{}{}
+		for _, id := range f.ProblemIDs {
+			if _, ok := ids[id]; ok {
+				errors["problem_ids"] = errorField{
+					Message: localize(c, "Problems should be unique."),
+				}
+				break
+			}
+			ids[id] = struct{}{}
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	form := createContestForm{
+		Title:     f.Title,
+		BeginTime: f.BeginTime,
+		Duration:  f.Duration,
+	}
+	return form.Update(c, contest)
+}
+
+// getMashupProblems returns archive problems that can be observed by
+// account.
+func (v *View) getMashupProblems(
+	c echo.Context, accountCtx *managers.AccountContext, ids []int64,
+) ([]models.Problem, error) {
+	if err := syncStore(c, v.core.Problems); err != nil {
+		return nil, err
+	}
+	var problems []models.Problem
+	for _, id := range ids {
+		problem, err := v.core.Problems.Get(getContext(c), id)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return nil, err
+			}
+			return nil, errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeProblemNotFound,
+				Message: localize(
+					c, "Problem {id} does not exists.",
+					replaceField("id", id),
+				),
+			}
+		}
+		permissions := v.getProblemPermissions(accountCtx, problem)
+		if !permissions.HasPermission(perms.ObserveProblemRole) {
+			return nil, errorResponse{
+				Code:               http.StatusForbidden,
+				ErrorCode:          CodeMissingPermissions,
+				Message:            localize(c, "Account missing permissions."),
+				MissingPermissions: []string{perms.ObserveProblemRole},
+			}
+		}
+		problems = append(problems, problem)
+	}
+	return problems, nil
+}
+
+// createContestMashup creates private contest with archive problems.
+//
+// Contest has no owner, creator receives manager grant instead, so
+// mashup can be managed without global contest permissions.
+func (v *View) createContestMashup(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	account := accountCtx.Account
+	if account == nil {
+		return fmt.Errorf("account not extracted")
+	}
+	var form CreateContestMashupForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	var contest models.Contest
+	if err := form.Update(c, &contest); err != nil {
+		return err
+	}
+	problems, err := v.getMashupProblems(c, accountCtx, form.ProblemIDs)
+	if err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.Contests.Create(ctx, &contest); err != nil {
+			return err
+		}
+		grant := models.ContestGrant{
+			ContestID: contest.ID,
+			AccountID: account.ID,
+			Kind:      models.ManagerContestGrant,
+		}
+		if err := v.core.ContestGrants.Create(ctx, &grant); err != nil {
+			return err
+		}
+		for i, problem := range problems {
+			contestProblem := models.ContestProblem{
+				ContestID: contest.ID,
+				ProblemID: problem.ID,
+				Code:      string(rune('A' + i)),
+			}
+			if err := v.core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusCreated,
+		makeContest(c, contest, accountCtx, nil),
+	)
+}
@@ -101,6 +101,7 @@ func (f *CreateContestMessageForm) Update(
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -112,14 +113,16 @@ func (f *CreateContestMessageForm) Update(
 				return err
 			}
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Message not found."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeMessageNotFound,
+				Message:   localize(c, "Message not found."),
 			}
 		}
 		if message.Kind != models.QuestionContestMessage {
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Message should be a question."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeMessageNotQuestion,
+				Message:   localize(c, "Message should be a question."),
 			}
 		}
 		o.Kind = models.AnswerContestMessage
@@ -188,6 +191,7 @@ func (f SubmitContestQuestionForm) Update(
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -251,13 +255,15 @@ func (v *View) submitContestQuestion(c echo.Context) error {
 	participant := contestCtx.GetEffectiveParticipant()
 	if participant == nil {
 		return errorResponse{
-			Code:    http.StatusForbidden,
-			Message: localize(c, "Participant not found."),
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
 		}
 	}
 	if !contestCtx.HasEffectivePermission(perms.SubmitContestQuestionRole) {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: []string{perms.SubmitContestQuestionRole},
 		}
@@ -274,8 +280,9 @@ func (v *View) submitContestQuestion(c echo.Context) error {
 	}
 	if !v.hasQuestionsQuota(contestCtx, *participant, c.Logger()) {
 		return errorResponse{
-			Code:    http.StatusTooManyRequests,
-			Message: localize(c, "Too many requests."),
+			Code:      http.StatusTooManyRequests,
+			ErrorCode: CodeTooManyRequests,
+			Message:   localize(c, "Too many requests."),
 		}
 	}
 	message := models.ContestMessage{
@@ -334,6 +341,10 @@ func (v *View) getContestMessagePermissions(
 	ctx *managers.ContestContext, message models.ContestMessage,
 ) perms.PermissionSet {
 	permissions := ctx.Permissions.Clone()
+	if message.Kind == models.AlertContestMessage {
+		// Alerts are visible only for judges.
+		return permissions
+	}
 	if message.ParticipantID != 0 {
 		for _, participant := range ctx.Participants {
 			if participant.ID == int64(message.ParticipantID) {
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestProblemLimitHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/problems/:problem/limits",
+		v.observeContestProblemLimits, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestProblem,
+		v.requirePermission(perms.UpdateContestProblemRole),
+	)
+	g.PATCH(
+		"/v0/contests/:contest/problems/:problem/limits",
+		v.updateContestProblemLimits, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestProblem,
+		v.requirePermission(perms.UpdateContestProblemRole),
+	)
+}
+
+// ContestProblemLimits represents limits of contest problem.
+type ContestProblemLimits struct {
+	// TimeLimit contains time limit in milliseconds that overrides time
+	// limit of problem package.
+	TimeLimit *int64 `json:"time_limit,omitempty"`
+	// MemoryLimit contains memory limit in bytes that overrides memory
+	// limit of problem package.
+	MemoryLimit *int64 `json:"memory_limit,omitempty"`
+	// PackageTimeLimit contains time limit of problem package.
+	PackageTimeLimit int64 `json:"package_time_limit,omitempty"`
+	// PackageMemoryLimit contains memory limit of problem package.
+	PackageMemoryLimit int64 `json:"package_memory_limit,omitempty"`
+	// RejudgedSolutions contains amount of solutions that are rejudged
+	// after update of limits.
+	RejudgedSolutions int `json:"rejudged_solutions,omitempty"`
+}
+
+func makeContestProblemLimits(
+	contestProblem models.ContestProblem, problem models.Problem,
+) ContestProblemLimits {
+	resp := ContestProblemLimits{}
+	if config, err := contestProblem.GetConfig(); err == nil {
+		resp.TimeLimit = config.TimeLimit
+		resp.MemoryLimit = config.MemoryLimit
+	}
+	if config, err := problem.GetConfig(); err == nil {
+		resp.PackageTimeLimit = config.TimeLimit
+		resp.PackageMemoryLimit = config.MemoryLimit
+	}
+	return resp
+}
+
+func (v *View) observeContestProblemLimits(c echo.Context) error {
+	contestProblem, ok := c.Get(contestProblemKey).(models.ContestProblem)
+	if !ok {
+		return fmt.Errorf("contest problem not extracted")
+	}
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	return c.JSON(http.StatusOK, makeContestProblemLimits(contestProblem, problem))
+}
+
+const (
+	minContestProblemTimeLimit   = 100
+	maxContestProblemTimeLimit   = 60 * 1000
+	minContestProblemMemoryLimit = 4 * 1024 * 1024
+	maxContestProblemMemoryLimit = 4 * 1024 * 1024 * 1024
+)
+
+// UpdateContestProblemLimitsForm represents form for updating limits of
+// contest problem.
+type UpdateContestProblemLimitsForm struct {
+	// TimeLimit contains time limit in milliseconds.
+	//
+	// Zero value resets limit to limit of problem package.
+	TimeLimit *int64 `json:"time_limit"`
+	// MemoryLimit contains memory limit in bytes.
+	//
+	// Zero value resets limit to limit of problem package.
+	MemoryLimit *int64 `json:"memory_limit"`
+}
+
+func (f UpdateContestProblemLimitsForm) Update(
+	c echo.Context, config *models.ContestProblemConfig,
+) error {
+	errors := errorFields{}
+	if f.TimeLimit != nil && *f.TimeLimit != 0 &&
+		(*f.TimeLimit < minContestProblemTimeLimit || *f.TimeLimit > maxContestProblemTimeLimit) {
+		errors["time_limit"] = errorField{
+			Message: localize(
+				c, "Time limit should be between {min} and {max} milliseconds.",
+				replaceField("min", minContestProblemTimeLimit),
+				replaceField("max", maxContestProblemTimeLimit),
+			),
+		}
+	}
+	if f.MemoryLimit != nil && *f.MemoryLimit != 0 &&
+		(*f.MemoryLimit < minContestProblemMemoryLimit || *f.MemoryLimit > maxContestProblemMemoryLimit) {
+		errors["memory_limit"] = errorField{
+			Message: localize(
+				c, "Memory limit should be between {min} and {max} bytes.",
+				replaceField("min", minContestProblemMemoryLimit),
+				replaceField("max", maxContestProblemMemoryLimit),
+			),
+		}
+	}
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	if f.TimeLimit != nil {
+		if *f.TimeLimit != 0 {
+			config.TimeLimit = f.TimeLimit
+		} else {
+			config.TimeLimit = nil
+		}
+	}
+	if f.MemoryLimit != nil {
+		if *f.MemoryLimit != 0 {
+			config.MemoryLimit = f.MemoryLimit
+		} else {
+			config.MemoryLimit = nil
+		}
+	}
+	return nil
+}
+
+// getEffectiveLimit returns limit that overrides package limit or
+// package limit itself.
+func getEffectiveLimit(limit *int64, packageLimit int64) int64 {
+	if limit != nil {
+		return *limit
+	}
+	return packageLimit
+}
+
+// getLimitsRejudgeFilter returns filter of verdicts that can be changed
+// after update of limits.
+//
+// Decreased limits can change verdict of any judged solution, increased
+// limits can change only verdicts caused by exceeded limits.
+func getLimitsRejudgeFilter(
+	oldConfig, newConfig models.ContestProblemConfig,
+	problemConfig models.ProblemConfig,
+) func(models.Verdict) bool {
+	oldTimeLimit := getEffectiveLimit(oldConfig.TimeLimit, problemConfig.TimeLimit)
+	newTimeLimit := getEffectiveLimit(newConfig.TimeLimit, problemConfig.TimeLimit)
+	oldMemoryLimit := getEffectiveLimit(oldConfig.MemoryLimit, problemConfig.MemoryLimit)
+	newMemoryLimit := getEffectiveLimit(newConfig.MemoryLimit, problemConfig.MemoryLimit)
+	if newTimeLimit < oldTimeLimit || newMemoryLimit < oldMemoryLimit {
+		return func(verdict models.Verdict) bool {
+			return verdict != models.CompilationError
+		}
+	}
+	verdicts := map[models.Verdict]struct{}{}
+	if newTimeLimit > oldTimeLimit {
+		verdicts[models.TimeLimitExceeded] = struct{}{}
+	}
+	if newMemoryLimit > oldMemoryLimit {
+		verdicts[models.MemoryLimitExceeded] = struct{}{}
+		verdicts[models.RuntimeError] = struct{}{}
+	}
+	return func(verdict models.Verdict) bool {
+		_, ok := verdicts[verdict]
+		return ok
+	}
+}
+
+func (v *View) updateContestProblemLimits(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	contestProblem, ok := c.Get(contestProblemKey).(models.ContestProblem)
+	if !ok {
+		return fmt.Errorf("contest problem not extracted")
+	}
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	var form UpdateContestProblemLimitsForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	oldConfig, err := contestProblem.GetConfig()
+	if err != nil {
+		return err
+	}
+	newConfig, err := contestProblem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if err := form.Update(c, &newConfig); err != nil {
+		return err
+	}
+	problemConfig, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if err := contestProblem.SetConfig(newConfig); err != nil {
+		return err
+	}
+	filter := getLimitsRejudgeFilter(oldConfig, newConfig, problemConfig)
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	rejudged := 0
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.ContestProblems.Update(ctx, contestProblem); err != nil {
+			return err
+		}
+		contestSolutions, err := collectRows(
+			v.core.ContestSolutions.FindByContest(ctx, contestProblem.ContestID),
+		)
+		if err != nil {
+			return err
+		}
+		for _, contestSolution := range contestSolutions {
+			if contestSolution.ProblemID != contestProblem.ID {
+				continue
+			}
+			solution, err := v.core.Solutions.Get(ctx, contestSolution.ID)
+			if err != nil {
+				return err
+			}
+			report, err := solution.GetReport()
+			if err != nil || report == nil || !filter(report.Verdict) {
+				continue
+			}
+			if err := solution.SetReport(nil); err != nil {
+				return err
+			}
+			if err := v.core.Solutions.Update(ctx, solution); err != nil {
+				return err
+			}
+			task := models.Task{}
+			if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+				SolutionID:    solution.ID,
+				EnablePoints:  getEnablePoints(contestCtx),
+				ParticipantID: contestSolution.ParticipantID,
+			}); err != nil {
+				return err
+			}
+			if err := v.core.Tasks.Create(ctx, &task); err != nil {
+				return err
+			}
+			rejudged++
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	resp := makeContestProblemLimits(contestProblem, problem)
+	resp.RejudgedSolutions = rejudged
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestQueueHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/queue", v.observeContestQueue,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestSolutionsRole),
+	)
+}
+
+// defaultJudgeTime contains default estimation of solution judging
+// duration in seconds.
+const defaultJudgeTime = 10
+
+type ContestQueueSolution struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	// Position contains amount of solutions that will be judged before
+	// this solution including itself, zero for running solutions.
+	Position int `json:"position,omitempty"`
+	// EstimatedWait contains estimated wait time in seconds.
+	EstimatedWait int64 `json:"estimated_wait,omitempty"`
+}
+
+type ContestQueue struct {
+	// Queued contains total amount of queued judge tasks.
+	Queued int `json:"queued"`
+	// Running contains total amount of running judge tasks.
+	Running   int                    `json:"running"`
+	Solutions []ContestQueueSolution `json:"solutions"`
+}
+
+// estimateQueueWait returns estimated wait time for solution at
+// specified queue position.
+//
+// Every running task represents one busy judge slot, so queue is
+// assumed to be processed in parallel by all running judges.
+func estimateQueueWait(position, running int, judgeTime int64) int64 {
+	if position <= 0 {
+		return 0
+	}
+	workers := int64(max(running, 1))
+	return (int64(position) + workers - 1) / workers * judgeTime
+}
+
+func (v *View) getJudgeTime() int64 {
+	judgeTime, err := v.core.Settings.GetInt64("invoker.average_judge_time")
+	if err != nil || judgeTime.Empty || judgeTime.Value <= 0 {
+		return defaultJudgeTime
+	}
+	return judgeTime.Value
+}
+
+func (v *View) observeContestQueue(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	tasks, err := v.core.Tasks.FindByStatus(
+		getContext(c), models.QueuedTask, models.RunningTask,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tasks.Close() }()
+	var queued []int64
+	running := 0
+	taskBySolution := map[int64]models.Task{}
+	for tasks.Next() {
+		task := tasks.Row()
+		if task.Kind != models.JudgeSolutionTask {
+			continue
+		}
+		var config models.JudgeSolutionTaskConfig
+		if err := task.ScanConfig(&config); err != nil {
+			continue
+		}
+		if task.Status == models.QueuedTask {
+			queued = append(queued, task.ID)
+		} else {
+			running++
+		}
+		if prev, ok := taskBySolution[config.SolutionID]; !ok || prev.ID < task.ID {
+			taskBySolution[config.SolutionID] = task
+		}
+	}
+	if err := tasks.Err(); err != nil {
+		return err
+	}
+	// Queued tasks are popped in order of identifiers.
+	sort.Slice(queued, func(i, j int) bool { return queued[i] < queued[j] })
+	resp := ContestQueue{
+		Queued:    len(queued),
+		Running:   running,
+		Solutions: []ContestQueueSolution{},
+	}
+	var participantIDs []int64
+	for _, participant := range contestCtx.Participants {
+		if participant.ID != 0 {
+			participantIDs = append(participantIDs, participant.ID)
+		}
+	}
+	if len(participantIDs) == 0 || len(taskBySolution) == 0 {
+		return c.JSON(http.StatusOK, resp)
+	}
+	solutions, err := v.core.ContestSolutions.ReverseFindByParticipantFrom(
+		getContext(c), participantIDs, 0,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = solutions.Close() }()
+	judgeTime := v.getJudgeTime()
+	solutionsCount := 0
+	for solutions.Next() && solutionsCount < maxSolutionLimit {
+		solutionsCount++
+		solution := solutions.Row()
+		task, ok := taskBySolution[solution.ID]
+		if !ok {
+			continue
+		}
+		item := ContestQueueSolution{
+			ID:     solution.ID,
+			Status: task.Status.String(),
+		}
+		if task.Status == models.QueuedTask {
+			item.Position = sort.Search(len(queued), func(i int) bool {
+				return queued[i] >= task.ID
+			}) + 1
+			item.EstimatedWait = estimateQueueWait(item.Position, running, judgeTime)
+		}
+		resp.Solutions = append(resp.Solutions, item)
+	}
+	if err := solutions.Err(); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
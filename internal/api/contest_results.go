@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerContestResultHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/results", v.observeContestResults,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestStandingsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/finalize", v.finalizeContest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.FinalizeContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/unfinalize", v.unfinalizeContest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.FinalizeContestRole),
+	)
+}
+
+type ContestResultRow struct {
+	Participant ContestParticipant  `json:"participant"`
+	Place       int                 `json:"place"`
+	Score       float64             `json:"score"`
+	Penalty     *int64              `json:"penalty,omitempty"`
+	Award       models.ContestAward `json:"award,omitempty"`
+}
+
+type ContestResults struct {
+	FinalizeTime int64              `json:"finalize_time"`
+	Results      []ContestResultRow `json:"results"`
+}
+
+func (v *View) makeContestResults(
+	c echo.Context, result models.ContestResult,
+) (ContestResults, error) {
+	rows, err := result.GetResults()
+	if err != nil {
+		return ContestResults{}, err
+	}
+	resp := ContestResults{
+		FinalizeTime: result.CreateTime,
+		Results:      []ContestResultRow{},
+	}
+	for _, row := range rows {
+		rowResp := ContestResultRow{
+			Place:   row.Place,
+			Score:   row.Score,
+			Penalty: row.Penalty,
+			Award:   row.Award,
+		}
+		if row.FakeParticipantID != 0 {
+			participant, err := v.core.ContestFakeParticipants.Get(
+				getContext(c), row.FakeParticipantID,
+			)
+			if err != nil && err != sql.ErrNoRows {
+				return ContestResults{}, err
+			}
+			fakeResp := makeContestFakeParticipant(participant)
+			rowResp.Participant.Kind = models.RegularParticipant
+			rowResp.Participant.Fake = &fakeResp
+		} else {
+			participant, err := v.core.ContestParticipants.Get(
+				getContext(c), row.ParticipantID,
+			)
+			if err != nil {
+				if err != sql.ErrNoRows {
+					return ContestResults{}, err
+				}
+				participant = models.ContestParticipant{
+					Kind: models.RegularParticipant,
+				}
+				participant.ID = row.ParticipantID
+			}
+			rowResp.Participant = makeContestParticipant(c, participant, v.core)
+		}
+		resp.Results = append(resp.Results, rowResp)
+	}
+	return resp, nil
+}
+
+func (v *View) findContestResult(
+	c echo.Context, contestID int64,
+) (models.ContestResult, error) {
+	if err := syncStore(c, v.core.ContestResults); err != nil {
+		return models.ContestResult{}, err
+	}
+	results, err := v.core.ContestResults.FindByContest(getContext(c), contestID)
+	if err != nil {
+		return models.ContestResult{}, err
+	}
+	defer func() { _ = results.Close() }()
+	if results.Next() {
+		return results.Row(), nil
+	}
+	if err := results.Err(); err != nil {
+		return models.ContestResult{}, err
+	}
+	return models.ContestResult{}, sql.ErrNoRows
+}
+
+func (v *View) observeContestResults(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if !contestCtx.IsFinalized() {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeContestNotFinalized,
+			Message:   localize(c, "Contest is not finalized."),
+		}
+	}
+	result, err := v.findContestResult(c, contestCtx.Contest.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeContestNotFinalized,
+				Message:   localize(c, "Contest is not finalized."),
+			}
+		}
+		return err
+	}
+	resp, err := v.makeContestResults(c, result)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) finalizeContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if contestCtx.IsFinalized() {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeContestAlreadyFinalized,
+			Message:   localize(c, "Contest is already finalized."),
+		}
+	}
+	if contestCtx.GetContestTime().Stage() != managers.ContestFinished {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeContestNotFinished,
+			Message:   localize(c, "Contest is not finished."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	contest := contestCtx.Contest
+	config := contestCtx.ContestConfig
+	var result models.ContestResult
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		var err error
+		result, err = v.standings.FinalizeContest(ctx, contestCtx, &config)
+		if err != nil {
+			return err
+		}
+		if err := contest.SetConfig(config); err != nil {
+			return err
+		}
+		return v.core.Contests.Update(ctx, contest)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	resp, err := v.makeContestResults(c, result)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type UnfinalizeContestForm struct {
+	// Reason contains reason of unfinalization for audit log.
+	Reason string `json:"reason"`
+}
+
+func (f *UnfinalizeContestForm) Validate(c echo.Context) error {
+	errors := errorFields{}
+	if len(f.Reason) < 4 {
+		errors["reason"] = errorField{
+			Message: localize(c, "Reason is too short."),
+		}
+	} else if len(f.Reason) > 1024 {
+		errors["reason"] = errorField{
+			Message: localize(c, "Reason is too long."),
+		}
+	}
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) unfinalizeContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if !contestCtx.IsFinalized() {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeContestNotFinalized,
+			Message:   localize(c, "Contest is not finalized."),
+		}
+	}
+	var form UnfinalizeContestForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Validate(c); err != nil {
+		return err
+	}
+	result, err := v.findContestResult(c, contestCtx.Contest.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	contest := contestCtx.Contest
+	config := contestCtx.ContestConfig
+	config.FinalizeTime = 0
+	if err := contest.SetConfig(config); err != nil {
+		return err
+	}
+	unfinalization := models.ContestUnfinalization{
+		Time:      getNow(c).Unix(),
+		ContestID: contest.ID,
+		Reason:    form.Reason,
+	}
+	if account := contestCtx.Account; account != nil {
+		unfinalization.AuthorID = NInt64(account.ID)
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if result.ID != 0 {
+			if err := v.core.ContestResults.Delete(ctx, result.ID); err != nil {
+				return err
+			}
+		}
+		if err := v.core.ContestUnfinalizations.Create(ctx, &unfinalization); err != nil {
+			return err
+		}
+		return v.core.Contests.Update(ctx, contest)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	v.core.Logger().Info(
+		"Contest unfinalized",
+		logs.Any("contest_id", contest.ID),
+		logs.Any("account_id", unfinalization.AuthorID),
+		logs.Any("reason", form.Reason),
+	)
+	return c.JSON(http.StatusOK, makeContest(c, contest, contestCtx, v.core))
+}
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerContestScoreOverrideHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/overrides", v.observeContestScoreOverrides,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestScoreOverridesRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/overrides", v.createContestScoreOverride,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.CreateContestScoreOverrideRole),
+	)
+	g.DELETE(
+		"/v0/contests/:contest/overrides/:override", v.deleteContestScoreOverride,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.extractContestScoreOverride,
+		v.requirePermission(perms.DeleteContestScoreOverrideRole),
+	)
+}
+
+type ContestScoreOverride struct {
+	ID          int64              `json:"id"`
+	Participant ContestParticipant `json:"participant"`
+	ProblemID   int64              `json:"problem_id"`
+	Verdict     string             `json:"verdict,omitempty"`
+	Points      *float64           `json:"points,omitempty"`
+	Reason      string             `json:"reason"`
+	Author      GrantAccount       `json:"author"`
+	CreateTime  int64              `json:"create_time"`
+	// VerdictCode contains machine-readable code of verdict.
+	VerdictCode string `json:"verdict_code,omitempty"`
+}
+
+type ContestScoreOverrides struct {
+	Overrides []ContestScoreOverride `json:"overrides"`
+}
+
+func (v *View) makeContestScoreOverride(
+	c echo.Context, override models.ContestScoreOverride,
+) ContestScoreOverride {
+	resp := ContestScoreOverride{
+		ID:         override.ID,
+		ProblemID:  override.ProblemID,
+		Reason:     override.Reason,
+		Author:     v.makeGrantAccount(c, override.AuthorID),
+		CreateTime: override.CreateTime,
+	}
+	if participant, err := v.core.ContestParticipants.Get(
+		getContext(c), override.ParticipantID,
+	); err == nil {
+		resp.Participant = makeContestParticipant(c, participant, v.core)
+	}
+	if value, err := override.GetValue(); err == nil {
+		if value.Verdict != 0 {
+			verdict := v.localizeVerdict(c, value.Verdict.String())
+			resp.Verdict = verdict.Name
+			resp.VerdictCode = verdict.Code
+		}
+		resp.Points = value.Points
+	}
+	return resp
+}
+
+func (v *View) observeContestScoreOverrides(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestScoreOverrides); err != nil {
+		return err
+	}
+	overrides, err := v.core.ContestScoreOverrides.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = overrides.Close() }()
+	resp := ContestScoreOverrides{Overrides: []ContestScoreOverride{}}
+	for overrides.Next() {
+		resp.Overrides = append(
+			resp.Overrides, v.makeContestScoreOverride(c, overrides.Row()),
+		)
+	}
+	if err := overrides.Err(); err != nil {
+		return err
+	}
+	sort.Slice(resp.Overrides, func(i, j int) bool {
+		return resp.Overrides[i].ID < resp.Overrides[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateContestScoreOverrideForm struct {
+	ParticipantID int64 `json:"participant_id"`
+	// ProblemID contains ID of contest problem.
+	ProblemID int64 `json:"problem_id"`
+	// Verdict contains name of built-in or custom verdict.
+	Verdict string   `json:"verdict"`
+	Points  *float64 `json:"points"`
+	// Reason contains explanation of override for audit.
+	Reason string `json:"reason"`
+}
+
+func (f CreateContestScoreOverrideForm) Update(
+	c echo.Context, o *models.ContestScoreOverride,
+	ctx *managers.ContestContext, v *View,
+) error {
+	errors := errorFields{}
+	if len(f.Reason) < 4 {
+		errors["reason"] = errorField{
+			Message: localize(c, "Reason is too short."),
+		}
+	} else if len(f.Reason) > 1024 {
+		errors["reason"] = errorField{
+			Message: localize(c, "Reason is too long."),
+		}
+	}
+	var verdict models.Verdict
+	if f.Verdict == "" && f.Points == nil {
+		errors["verdict"] = errorField{
+			Message: localize(c, "Verdict or points should be specified."),
+		}
+	} else if f.Verdict != "" {
+		value, ok := v.parseVerdict(c, f.Verdict)
+		if !ok {
+			errors["verdict"] = errorField{
+				Message: localize(c, "Unknown verdict."),
+			}
+		}
+		verdict = value
+	}
+	if f.Points != nil && *f.Points < 0 {
+		errors["points"] = errorField{
+			Message: localize(c, "Points should be non-negative."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	participant, err := v.core.ContestParticipants.Get(getContext(c), f.ParticipantID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
+		}
+	}
+	if participant.ContestID != ctx.Contest.ID {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
+		}
+	}
+	problem, err := v.core.ContestProblems.Get(getContext(c), f.ProblemID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeProblemNotFound,
+			Message:   localize(c, "Problem not found."),
+		}
+	}
+	if problem.ContestID != ctx.Contest.ID {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeProblemNotFound,
+			Message:   localize(c, "Problem not found."),
+		}
+	}
+	o.ParticipantID = participant.ID
+	o.ProblemID = problem.ID
+	o.Reason = f.Reason
+	return o.SetValue(models.ContestScoreOverrideValue{
+		Verdict: verdict,
+		Points:  f.Points,
+	})
+}
+
+// findContestScoreOverride returns override of standings cell.
+func (v *View) findContestScoreOverride(
+	c echo.Context, contestID, participantID, problemID int64,
+) (models.ContestScoreOverride, error) {
+	overrides, err := v.core.ContestScoreOverrides.FindByContest(
+		getContext(c), contestID,
+	)
+	if err != nil {
+		return models.ContestScoreOverride{}, err
+	}
+	defer func() { _ = overrides.Close() }()
+	for overrides.Next() {
+		override := overrides.Row()
+		if override.ParticipantID == participantID &&
+			override.ProblemID == problemID {
+			return override, nil
+		}
+	}
+	if err := overrides.Err(); err != nil {
+		return models.ContestScoreOverride{}, err
+	}
+	return models.ContestScoreOverride{}, sql.ErrNoRows
+}
+
+func (v *View) createContestScoreOverride(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestScoreOverrideForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestParticipants); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestScoreOverrides); err != nil {
+		return err
+	}
+	override, err := v.findContestScoreOverride(
+		c, contestCtx.Contest.ID, form.ParticipantID, form.ProblemID,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	// Override of standings cell is replaced by new one.
+	override.ContestID = contestCtx.Contest.ID
+	override.AuthorID = contestCtx.Account.ID
+	override.CreateTime = getNow(c).Unix()
+	if err := form.Update(c, &override, contestCtx, v); err != nil {
+		return err
+	}
+	if override.ID != 0 {
+		err = v.core.ContestScoreOverrides.Update(getContext(c), override)
+	} else {
+		err = v.core.ContestScoreOverrides.Create(getContext(c), &override)
+	}
+	if err != nil {
+		return err
+	}
+	v.core.Logger().Info(
+		"Contest score overridden",
+		logs.Any("contest_id", override.ContestID),
+		logs.Any("participant_id", override.ParticipantID),
+		logs.Any("problem_id", override.ProblemID),
+		logs.Any("account_id", override.AuthorID),
+		logs.Any("reason", override.Reason),
+	)
+	v.standings.InvalidateContest(contestCtx.Contest.ID)
+	return c.JSON(http.StatusCreated, v.makeContestScoreOverride(c, override))
+}
+
+func (v *View) deleteContestScoreOverride(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	override, ok := c.Get(contestScoreOverrideKey).(models.ContestScoreOverride)
+	if !ok {
+		return fmt.Errorf("override not extracted")
+	}
+	if err := v.core.ContestScoreOverrides.Delete(
+		getContext(c), override.ID,
+	); err != nil {
+		return err
+	}
+	v.core.Logger().Info(
+		"Contest score override removed",
+		logs.Any("contest_id", override.ContestID),
+		logs.Any("participant_id", override.ParticipantID),
+		logs.Any("problem_id", override.ProblemID),
+		logs.Any("account_id", contestCtx.Account.ID),
+	)
+	v.standings.InvalidateContest(contestCtx.Contest.ID)
+	return c.JSON(http.StatusOK, v.makeContestScoreOverride(c, override))
+}
+
+func (v *View) extractContestScoreOverride(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("override"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidOverrideID,
+				Message:   localize(c, "Invalid override ID."),
+			}
+		}
+		contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+		if !ok {
+			return fmt.Errorf("contest not extracted")
+		}
+		if err := syncStore(c, v.core.ContestScoreOverrides); err != nil {
+			return err
+		}
+		override, err := v.core.ContestScoreOverrides.Get(getContext(c), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeOverrideNotFound,
+					Message:   localize(c, "Override not found."),
+				}
+			}
+			return err
+		}
+		if override.ContestID != contestCtx.Contest.ID {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeOverrideNotFound,
+				Message:   localize(c, "Override not found."),
+			}
+		}
+		c.Set(contestScoreOverrideKey, override)
+		return next(c)
+	}
+}
@@ -1,8 +1,12 @@
 package api
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/udovin/solve/internal/managers"
@@ -13,9 +17,19 @@ import (
 func (v *View) registerContestStandingsHandlers(g *echo.Group) {
 	g.GET(
 		"/v0/contests/:contest/standings", v.observeContestStandings,
+		v.extractAuth(v.sessionAuth, v.contestTokenAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/standings/guest", v.observeGuestContestStandings,
 		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
 		v.requirePermission(perms.ObserveContestStandingsRole),
 	)
+	g.GET(
+		"/v0/contests/:contest/standings/me", v.observeParticipantContestStandings,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestStandingsRole),
+	)
 }
 
 type ContestStandingsColumn struct {
@@ -31,6 +45,10 @@ type ContestStandingsCell struct {
 	Points  float64 `json:"points,omitempty"`
 	Attempt int     `json:"attempt"`
 	Time    *int64  `json:"time,omitempty"`
+	// FrozenAttempts contains amount of attempts during freeze.
+	FrozenAttempts int `json:"frozen_attempts,omitempty"`
+	// Frozen means that cell contains attempts with hidden verdict.
+	Frozen bool `json:"frozen,omitempty"`
 }
 
 type ContestStandingsRow struct {
@@ -50,8 +68,33 @@ type ContestStandings struct {
 }
 
 type ObserveContestStandingsForm struct {
-	IgnoreFreeze bool `query:"ignore_freeze"`
-	OnlyOfficial bool `query:"only_official"`
+	IgnoreFreeze bool                   `query:"ignore_freeze"`
+	OnlyOfficial bool                   `query:"only_official"`
+	Kind         models.ParticipantKind `query:"kind"`
+	ScopeID      int64                  `query:"scope_id"`
+	// Label contains label filter in format "name:value".
+	Label string `query:"label"`
+}
+
+// parseParticipantLabel parses label filter in format "name:value".
+func parseParticipantLabel(c echo.Context, label string) (managers.ParticipantLabel, error) {
+	if label == "" {
+		return managers.ParticipantLabel{}, nil
+	}
+	name, value, _ := strings.Cut(label, ":")
+	if !isValidParticipantLabelName(name) {
+		return managers.ParticipantLabel{}, errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"label": errorField{
+					Message: localize(c, "Invalid label."),
+				},
+			},
+		}
+	}
+	return managers.ParticipantLabel{Name: name, Value: value}, nil
 }
 
 func (v *View) observeContestStandings(c echo.Context) error {
@@ -68,18 +111,55 @@ func (v *View) observeContestStandings(c echo.Context) error {
 	if err := c.Bind(&form); err != nil {
 		c.Logger().Warn(err)
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid form."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if form.Kind != 0 && !form.Kind.IsValid() {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"kind": errorField{
+					Message: localize(c, "Invalid participant kind."),
+				},
+			},
+		}
+	}
+	if form.IgnoreFreeze &&
+		!contestCtx.HasPermission(perms.ObserveContestFullStandingsRole) {
+		return errorResponse{
+			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{perms.ObserveContestFullStandingsRole},
 		}
 	}
+	label, err := parseParticipantLabel(c, form.Label)
+	if err != nil {
+		return err
+	}
 	options := managers.BuildStandingsOptions{
 		IgnoreFreeze: form.IgnoreFreeze,
 		OnlyOfficial: form.OnlyOfficial,
+		Kind:         form.Kind,
+		ScopeID:      form.ScopeID,
+		Label:        label,
 	}
 	standings, err := v.standings.BuildStandings(contestCtx, options)
 	if err != nil {
 		return err
 	}
+	return c.JSON(http.StatusOK, v.makeContestStandings(c, contestCtx, standings))
+}
+
+func (v *View) makeContestStandings(
+	c echo.Context,
+	contestCtx *managers.ContestContext,
+	standings *managers.ContestStandings,
+) ContestStandings {
 	resp := ContestStandings{
 		Kind:   contestCtx.ContestConfig.StandingsKind.String(),
 		Stage:  makeContestStage(standings.Stage),
@@ -112,9 +192,11 @@ func (v *View) observeContestStandings(c echo.Context) error {
 		}
 		for _, cell := range row.Cells {
 			cellResp := ContestStandingsCell{
-				Column:  cell.Column,
-				Attempt: cell.Attempt,
-				Points:  cell.Points,
+				Column:         cell.Column,
+				Attempt:        cell.Attempt,
+				Points:         cell.Points,
+				FrozenAttempts: cell.FrozenAttempts,
+				Frozen:         cell.Frozen,
 			}
 			if row.Participant.Kind == models.RegularParticipant ||
 				row.Participant.Kind == models.VirtualParticipant {
@@ -131,5 +213,97 @@ func (v *View) observeContestStandings(c echo.Context) error {
 		}
 		resp.Rows = append(resp.Rows, rowResp)
 	}
-	return c.JSON(http.StatusOK, resp)
+	return resp
+}
+
+// ObserveParticipantContestStandingsForm represents form for observing
+// standings row of current participant.
+type ObserveParticipantContestStandingsForm struct {
+	// Label contains label filter in format "name:value".
+	//
+	// Place of participant is calculated within participants with label.
+	Label string `query:"label"`
+}
+
+// observeParticipantContestStandings returns standings with only row
+// of current participant.
+//
+// Columns of response do not contain statistics of solutions.
+func (v *View) observeParticipantContestStandings(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if contestCtx.ContestConfig.StandingsKind == models.DisabledStandings {
+		return c.JSON(http.StatusOK, ContestStandings{
+			Kind: contestCtx.ContestConfig.StandingsKind.String(),
+		})
+	}
+	form := ObserveParticipantContestStandingsForm{}
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	label, err := parseParticipantLabel(c, form.Label)
+	if err != nil {
+		return err
+	}
+	standings, err := v.standings.BuildParticipantStandings(
+		contestCtx, managers.BuildStandingsOptions{Label: label},
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeParticipantNotFound,
+				Message:   localize(c, "Participant not found."),
+			}
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, v.makeContestStandings(c, contestCtx, standings))
+}
+
+// guestStandingsMaxAge contains lifetime of guest standings in
+// shared caches.
+const guestStandingsMaxAge = 5 * time.Second
+
+// observeGuestContestStandings returns standings as they are visible
+// for contest observers.
+//
+// Response is served from pre-serialized cache and supports
+// conditional requests, so it is cheap to poll it frequently.
+func (v *View) observeGuestContestStandings(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if contestCtx.ContestConfig.StandingsKind == models.DisabledStandings {
+		return c.JSON(http.StatusOK, ContestStandings{
+			Kind: contestCtx.ContestConfig.StandingsKind.String(),
+		})
+	}
+	standings, err := v.standings.BuildGuestStandings(
+		contestCtx,
+		func(standings *managers.ContestStandings) ([]byte, error) {
+			return json.Marshal(v.makeContestStandings(c, contestCtx, standings))
+		},
+	)
+	if err != nil {
+		return err
+	}
+	header := c.Response().Header()
+	header.Set("ETag", standings.ETag)
+	header.Set("Last-Modified", standings.ModifyTime.UTC().Format(http.TimeFormat))
+	header.Set("Cache-Control", fmt.Sprintf(
+		"public, max-age=%d", int(guestStandingsMaxAge.Seconds()),
+	))
+	if isNotModified(c.Request(), standings.ETag, standings.ModifyTime) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, standings.Data)
 }
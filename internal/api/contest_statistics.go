@@ -0,0 +1,148 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestStatisticsHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/statistics", v.observeContestStatistics,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestStandingsRole),
+	)
+}
+
+type ContestProblemStatistics struct {
+	Code                 string         `json:"code"`
+	TotalSolutions       int            `json:"total_solutions"`
+	AcceptedSolutions    int            `json:"accepted_solutions"`
+	TotalParticipants    int            `json:"total_participants"`
+	AcceptedParticipants int            `json:"accepted_participants"`
+	AcceptanceRate       float64        `json:"acceptance_rate"`
+	Verdicts             map[string]int `json:"verdicts,omitempty"`
+}
+
+type ContestStatisticsBucket struct {
+	BeginTime         int64 `json:"begin_time"`
+	TotalSolutions    int   `json:"total_solutions"`
+	AcceptedSolutions int   `json:"accepted_solutions"`
+}
+
+type ContestLanguageStatistics struct {
+	Compiler       string `json:"compiler"`
+	Language       string `json:"language,omitempty"`
+	TotalSolutions int    `json:"total_solutions"`
+}
+
+type ContestStatistics struct {
+	Problems       []ContestProblemStatistics  `json:"problems"`
+	Verdicts       map[string]int              `json:"verdicts,omitempty"`
+	BucketDuration int64                       `json:"bucket_duration"`
+	Buckets        []ContestStatisticsBucket   `json:"buckets,omitempty"`
+	Languages      []ContestLanguageStatistics `json:"languages,omitempty"`
+}
+
+func makeVerdictStatistics(verdicts map[models.Verdict]int) map[string]int {
+	if len(verdicts) == 0 {
+		return nil
+	}
+	resp := map[string]int{}
+	for verdict, count := range verdicts {
+		resp[verdict.String()] = count
+	}
+	return resp
+}
+
+// ObserveContestStatisticsForm represents form for observing statistics.
+type ObserveContestStatisticsForm struct {
+	// Label contains label filter in format "name:value".
+	Label string `query:"label"`
+}
+
+func (v *View) observeContestStatistics(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	form := ObserveContestStatisticsForm{}
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	label, err := parseParticipantLabel(c, form.Label)
+	if err != nil {
+		return err
+	}
+	if !contestCtx.HasPermission(perms.ObserveContestFullStandingsRole) &&
+		!managers.IsPublicStatistics(contestCtx) {
+		return errorResponse{
+			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{perms.ObserveContestFullStandingsRole},
+		}
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	statistics, err := v.statistics.BuildStatistics(
+		contestCtx, managers.BuildStatisticsOptions{Label: label},
+	)
+	if err != nil {
+		return err
+	}
+	resp := ContestStatistics{
+		Problems:       []ContestProblemStatistics{},
+		Verdicts:       makeVerdictStatistics(statistics.Verdicts),
+		BucketDuration: statistics.BucketDuration,
+	}
+	for _, problem := range statistics.Problems {
+		problemResp := ContestProblemStatistics{
+			Code:                 problem.Problem.Code,
+			TotalSolutions:       problem.TotalSolutions,
+			AcceptedSolutions:    problem.AcceptedSolutions,
+			TotalParticipants:    problem.TotalParticipants,
+			AcceptedParticipants: problem.AcceptedParticipants,
+			Verdicts:             makeVerdictStatistics(problem.Verdicts),
+		}
+		if problem.TotalSolutions > 0 {
+			problemResp.AcceptanceRate = float64(problem.AcceptedSolutions) /
+				float64(problem.TotalSolutions)
+		}
+		resp.Problems = append(resp.Problems, problemResp)
+	}
+	for _, bucket := range statistics.Buckets {
+		resp.Buckets = append(resp.Buckets, ContestStatisticsBucket{
+			BeginTime:         bucket.BeginTime,
+			TotalSolutions:    bucket.TotalSolutions,
+			AcceptedSolutions: bucket.AcceptedSolutions,
+		})
+	}
+	for _, language := range statistics.Languages {
+		languageResp := ContestLanguageStatistics{
+			Compiler:       language.Compiler.Name,
+			TotalSolutions: language.TotalSolutions,
+		}
+		if config, err := language.Compiler.GetConfig(); err == nil {
+			languageResp.Language = config.Language
+		}
+		resp.Languages = append(resp.Languages, languageResp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,327 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerContestSubmissionHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/submissions/:submission",
+		v.observeContestSubmission,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestRole),
+	)
+}
+
+// SubmissionStatus represents status of asynchronous submission.
+type SubmissionStatus string
+
+const (
+	// QueuedSubmission means that solution is not created yet.
+	QueuedSubmission SubmissionStatus = "queued"
+	// CreatedSubmission means that solution is successfully created.
+	CreatedSubmission SubmissionStatus = "created"
+	// FailedSubmission means that solution cannot be created.
+	FailedSubmission SubmissionStatus = "failed"
+)
+
+// ContestSubmission represents asynchronous submission of contest
+// solution.
+type ContestSubmission struct {
+	ID        string           `json:"id"`
+	ContestID int64            `json:"contest_id"`
+	Status    SubmissionStatus `json:"status"`
+	// URL contains follow-up URL of submission.
+	URL string `json:"url"`
+	// SolutionID contains ID of created solution.
+	SolutionID int64 `json:"solution_id,omitempty"`
+	// SolutionURL contains URL of created solution.
+	SolutionURL string `json:"solution_url,omitempty"`
+	// Message contains reason of failed submission.
+	Message string `json:"message,omitempty"`
+}
+
+// contestSubmissionTTL contains lifetime of finished submissions.
+const contestSubmissionTTL = 10 * time.Minute
+
+// contestSubmissionQueueSize contains maximal amount of queued
+// submissions.
+const contestSubmissionQueueSize = 256
+
+type contestSubmissionKey struct {
+	ParticipantID int64
+	ProblemID     int64
+	CompilerID    int64
+	SHA256        string
+}
+
+type contestSubmission struct {
+	ID              string
+	AccountID       int64
+	Key             contestSubmissionKey
+	File            models.File
+	Solution        models.Solution
+	ContestSolution models.ContestSolution
+	EnablePoints    bool
+	Status          SubmissionStatus
+	Message         string
+	FinishTime      time.Time
+}
+
+// contestSubmissionQueue contains submissions that are accepted but
+// not yet created.
+//
+// Submissions are created by single worker in order of acceptance.
+// Equal submissions of participant are deduplicated while queued.
+type contestSubmissionQueue struct {
+	mutex       sync.Mutex
+	submissions map[string]*contestSubmission
+	queued      map[contestSubmissionKey]string
+	tasks       chan string
+}
+
+func newContestSubmissionQueue() *contestSubmissionQueue {
+	return &contestSubmissionQueue{
+		submissions: map[string]*contestSubmission{},
+		queued:      map[contestSubmissionKey]string{},
+		tasks:       make(chan string, contestSubmissionQueueSize),
+	}
+}
+
+// Push adds submission to queue.
+//
+// If equal submission is already queued then it will be returned
+// and false will be returned.
+func (q *contestSubmissionQueue) Push(submission contestSubmission) (contestSubmission, bool, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.cleanup(time.Now())
+	if id, ok := q.queued[submission.Key]; ok {
+		return *q.submissions[id], false, nil
+	}
+	id, err := generateSubmissionID()
+	if err != nil {
+		return contestSubmission{}, false, err
+	}
+	submission.ID = id
+	submission.Status = QueuedSubmission
+	select {
+	case q.tasks <- id:
+	default:
+		return contestSubmission{}, false, fmt.Errorf("submissions queue overflow")
+	}
+	q.submissions[id] = &submission
+	q.queued[submission.Key] = id
+	return submission, true, nil
+}
+
+// Get returns submission by ID.
+func (q *contestSubmissionQueue) Get(id string) (contestSubmission, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	submission, ok := q.submissions[id]
+	if !ok {
+		return contestSubmission{}, false
+	}
+	return *submission, true
+}
+
+// Finish marks submission as finished.
+func (q *contestSubmissionQueue) Finish(submission contestSubmission) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	submission.FinishTime = time.Now()
+	q.submissions[submission.ID] = &submission
+	if q.queued[submission.Key] == submission.ID {
+		delete(q.queued, submission.Key)
+	}
+}
+
+func (q *contestSubmissionQueue) cleanup(now time.Time) {
+	for id, submission := range q.submissions {
+		if submission.Status == QueuedSubmission {
+			continue
+		}
+		if submission.FinishTime.Add(contestSubmissionTTL).Before(now) {
+			delete(q.submissions, id)
+		}
+	}
+}
+
+func generateSubmissionID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// isAsyncSubmitEnabled returns true if solution should be created in
+// background.
+func (v *View) isAsyncSubmitEnabled(c echo.Context, form SubmitSolutionForm) bool {
+	if !form.Async || v.submissions == nil {
+		return false
+	}
+	return v.getBoolSetting("contests.async_submit", c.Logger()).OrElse(true)
+}
+
+// enqueueContestSolution accepts solution that will be created in
+// background and returns follow-up submission.
+func (v *View) enqueueContestSolution(
+	c echo.Context,
+	account *models.Account,
+	file models.File,
+	solution models.Solution,
+	contestSolution models.ContestSolution,
+	enablePoints bool,
+) error {
+	submission, ok, err := v.submissions.Push(contestSubmission{
+		AccountID: account.ID,
+		Key: contestSubmissionKey{
+			ParticipantID: contestSolution.ParticipantID,
+			ProblemID:     contestSolution.ProblemID,
+			CompilerID:    solution.CompilerID,
+			SHA256:        string(file.SHA256),
+		},
+		File:            file,
+		Solution:        solution,
+		ContestSolution: contestSolution,
+		EnablePoints:    enablePoints,
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// File of duplicate is not required anymore.
+		if err := v.files.DeleteFile(getContext(c), file.ID); err != nil {
+			c.Logger().Warn("Cannot delete duplicate file", logs.Any("id", file.ID), err)
+		}
+	}
+	return c.JSON(http.StatusAccepted, makeContestSubmission(submission))
+}
+
+func (v *View) observeContestSubmission(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if v.submissions == nil {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeSubmissionNotFound,
+			Message:   localize(c, "Submission not found."),
+		}
+	}
+	submission, ok := v.submissions.Get(c.Param("submission"))
+	if !ok ||
+		submission.ContestSolution.ContestID != contestCtx.Contest.ID ||
+		contestCtx.Account == nil ||
+		submission.AccountID != contestCtx.Account.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeSubmissionNotFound,
+			Message:   localize(c, "Submission not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeContestSubmission(submission))
+}
+
+func makeContestSubmission(submission contestSubmission) ContestSubmission {
+	contestID := submission.ContestSolution.ContestID
+	resp := ContestSubmission{
+		ID:        submission.ID,
+		ContestID: contestID,
+		Status:    submission.Status,
+		URL: fmt.Sprintf(
+			"/api/v0/contests/%d/submissions/%s", contestID, submission.ID,
+		),
+		Message: submission.Message,
+	}
+	if submission.Status == CreatedSubmission {
+		resp.SolutionID = submission.Solution.ID
+		resp.SolutionURL = fmt.Sprintf(
+			"/api/v0/contests/%d/solutions/%d", contestID, submission.Solution.ID,
+		)
+	}
+	return resp
+}
+
+// createContestSolution creates solution with judge task and confirms
+// uploaded file.
+func (v *View) createContestSolution(
+	ctx context.Context,
+	file *models.File,
+	solution *models.Solution,
+	contestSolution *models.ContestSolution,
+	enablePoints bool,
+) error {
+	return v.core.WrapTx(ctx, func(ctx context.Context) error {
+		if err := v.files.ConfirmUploadFile(ctx, file); err != nil {
+			return err
+		}
+		solution.ContentID = models.NInt64(file.ID)
+		if err := v.core.Solutions.Create(ctx, solution); err != nil {
+			return err
+		}
+		contestSolution.ID = solution.ID
+		if err := v.core.ContestSolutions.Create(
+			ctx, contestSolution,
+		); err != nil {
+			return err
+		}
+		task := models.Task{}
+		if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+			SolutionID:    solution.ID,
+			EnablePoints:  enablePoints,
+			ParticipantID: contestSolution.ParticipantID,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead)
+}
+
+func (v *View) processContestSubmission(ctx context.Context, id string) {
+	submission, ok := v.submissions.Get(id)
+	if !ok {
+		return
+	}
+	if err := v.createContestSolution(
+		ctx, &submission.File, &submission.Solution,
+		&submission.ContestSolution, submission.EnablePoints,
+	); err != nil {
+		v.core.Logger().Error(
+			"Cannot create submitted solution",
+			logs.Any("submission", submission.ID),
+			err,
+		)
+		submission.Status = FailedSubmission
+		submission.Message = "Unable to create solution."
+	} else {
+		submission.Status = CreatedSubmission
+	}
+	v.submissions.Finish(submission)
+}
+
+func (v *View) contestSubmissionsDaemon(ctx context.Context) {
+	for {
+		select {
+		case id := <-v.submissions.tasks:
+			v.processContestSubmission(ctx, id)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
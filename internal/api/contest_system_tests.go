@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerContestSystemTestHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/system-test", v.observeContestSystemTest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/system-test", v.startContestSystemTest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestRole),
+	)
+}
+
+// ContestSystemTest represents progress of system testing.
+type ContestSystemTest struct {
+	// StartTime contains time when system testing was started.
+	StartTime int64  `json:"start_time"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	// Rejudge contains progress of judgement on full tests.
+	Rejudge *ProblemRejudge `json:"rejudge,omitempty"`
+}
+
+func (v *View) makeContestSystemTest(
+	c echo.Context, config models.ContestConfig,
+) ContestSystemTest {
+	resp := ContestSystemTest{
+		StartTime: int64(config.SystemTestTime),
+		Status:    models.QueuedTask.String(),
+	}
+	task, err := v.core.Tasks.Get(getContext(c), int64(config.SystemTestTaskID))
+	if err != nil {
+		// Removed task is considered as succeeded.
+		resp.Status = models.SucceededTask.String()
+		return resp
+	}
+	resp.Status = task.Status.String()
+	var state models.SystemTestContestTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Error = state.Error
+		if task.Status == models.SucceededTask {
+			resp.Rejudge = v.makeProblemRejudge(c, state.RejudgeTasks)
+		}
+	}
+	return resp
+}
+
+func (v *View) observeContestSystemTest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if contestCtx.ContestConfig.SystemTestTime == 0 {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeSystemTestNotStarted,
+			Message:   localize(c, "System testing is not started."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, v.makeContestSystemTest(c, contestCtx.ContestConfig))
+}
+
+func (v *View) startContestSystemTest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	contest := contestCtx.Contest
+	config, err := contest.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.SystemTestTime != 0 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeSystemTestAlreadyStarted,
+			Message:   localize(c, "System testing is already started."),
+		}
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.StartContestSystemTest(
+			ctx, contest.ID, &config, getNow(c),
+		); err != nil {
+			return err
+		}
+		if err := contest.SetConfig(config); err != nil {
+			return err
+		}
+		return v.core.Contests.Update(ctx, contest)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, v.makeContestSystemTest(c, config))
+}
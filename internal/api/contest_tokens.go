@@ -0,0 +1,290 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerContestTokenHandlers registers handlers for API tokens that
+// allow only reading of single contest.
+//
+// Token should be passed in Authorization header with Token scheme.
+func (v *View) registerContestTokenHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/tokens", v.observeContestTokens,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/tokens", v.createContestToken,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestRole),
+	)
+	g.DELETE(
+		"/v0/contests/:contest/tokens/:token", v.deleteContestToken,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestRole),
+	)
+}
+
+// ContestToken represents API token of contest.
+type ContestToken struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title,omitempty"`
+	// Token contains value of Authorization header.
+	//
+	// Token is returned only after creation.
+	Token      string `json:"token,omitempty"`
+	CreateTime int64  `json:"create_time"`
+	ExpireTime int64  `json:"expire_time"`
+}
+
+// ContestTokens represents list of contest API tokens.
+type ContestTokens struct {
+	Tokens []ContestToken `json:"tokens"`
+}
+
+const contestTokenScheme = "Token "
+
+func makeContestToken(token models.Token, config models.ContestAPITokenConfig) ContestToken {
+	return ContestToken{
+		ID:         token.ID,
+		Title:      config.Title,
+		CreateTime: token.CreateTime,
+		ExpireTime: token.ExpireTime,
+	}
+}
+
+// getContestToken returns value of Authorization header for token.
+func getContestToken(token models.Token) string {
+	return contestTokenScheme + fmt.Sprintf("%d_%s", token.ID, token.Secret)
+}
+
+// findContestTokens returns API tokens of contest.
+func (v *View) findContestTokens(c echo.Context, contestID int64) ([]models.Token, error) {
+	tokens, err := collectRows(v.core.Tokens.Find(getContext(c), db.FindQuery{
+		Where: gosql.Column("kind").Equal(models.ContestAPIToken),
+	}))
+	if err != nil {
+		return nil, err
+	}
+	var result []models.Token
+	for _, token := range tokens {
+		var config models.ContestAPITokenConfig
+		if err := token.ScanConfig(&config); err != nil {
+			continue
+		}
+		if config.ContestID == contestID {
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+func (v *View) observeContestTokens(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	tokens, err := v.findContestTokens(c, contestCtx.Contest.ID)
+	if err != nil {
+		return err
+	}
+	resp := ContestTokens{Tokens: []ContestToken{}}
+	for _, token := range tokens {
+		var config models.ContestAPITokenConfig
+		if err := token.ScanConfig(&config); err != nil {
+			continue
+		}
+		resp.Tokens = append(resp.Tokens, makeContestToken(token, config))
+	}
+	sort.Slice(resp.Tokens, func(i, j int) bool {
+		return resp.Tokens[i].ID < resp.Tokens[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateContestTokenForm represents form for creating of contest API
+// token.
+type CreateContestTokenForm struct {
+	Title string `json:"title"`
+	// ExpireTime contains expiration time of token.
+	//
+	// Token expires after one year by default.
+	ExpireTime *int64 `json:"expire_time,omitempty"`
+}
+
+const (
+	defaultContestTokenDuration = 365 * 24 * 60 * 60
+	maxContestTokenTitleLength  = 64
+)
+
+func (f CreateContestTokenForm) Update(
+	c echo.Context, token *models.Token, config *models.ContestAPITokenConfig,
+) error {
+	errors := errorFields{}
+	if len(f.Title) > maxContestTokenTitleLength {
+		errors["title"] = errorField{
+			Message: localize(c, "Title is too long."),
+		}
+	}
+	now := getNow(c).Unix()
+	token.CreateTime = now
+	token.ExpireTime = now + defaultContestTokenDuration
+	if f.ExpireTime != nil {
+		if *f.ExpireTime <= now {
+			errors["expire_time"] = errorField{
+				Message: localize(c, "Expiration time should be in future."),
+			}
+		}
+		token.ExpireTime = *f.ExpireTime
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config.Title = f.Title
+	return nil
+}
+
+// createContestToken issues API token that allows only reading of
+// contest.
+//
+// Permissions of token are derived from permissions of account that
+// issued token, so token stops working when account loses access to
+// contest.
+func (v *View) createContestToken(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestTokenForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	token := models.Token{AccountID: contestCtx.Account.ID}
+	config := models.ContestAPITokenConfig{ContestID: contestCtx.Contest.ID}
+	if err := form.Update(c, &token, &config); err != nil {
+		return err
+	}
+	if err := token.GenerateSecret(); err != nil {
+		return err
+	}
+	if err := token.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.Tokens.Create(getContext(c), &token); err != nil {
+		return err
+	}
+	resp := makeContestToken(token, config)
+	resp.Token = getContestToken(token)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (v *View) deleteContestToken(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("token"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTokenID,
+			Message:   localize(c, "Invalid token ID."),
+		}
+	}
+	token, err := v.core.Tokens.Get(getContext(c), id)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	var config models.ContestAPITokenConfig
+	if err != nil || token.Kind != models.ContestAPIToken ||
+		token.ScanConfig(&config) != nil ||
+		config.ContestID != contestCtx.Contest.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTokenNotFound,
+			Message:   localize(c, "Token not found."),
+		}
+	}
+	if err := v.core.Tokens.Delete(getContext(c), token.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeContestToken(token, config))
+}
+
+// contestTokenAuth authorizes account with contest API token.
+//
+// Permissions of account are restricted to reading of contest.
+func (v *View) contestTokenAuth(c echo.Context) (bool, error) {
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	value, ok := strings.CutPrefix(header, contestTokenScheme)
+	if !ok {
+		return false, nil
+	}
+	rawID, secret, ok := strings.Cut(strings.TrimSpace(value), "_")
+	if !ok {
+		return false, nil
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	token, err := v.core.Tokens.Get(getContext(c), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if token.Kind != models.ContestAPIToken ||
+		subtle.ConstantTimeCompare([]byte(token.Secret), []byte(secret)) != 1 ||
+		token.ExpireTime <= getNow(c).Unix() {
+		return false, nil
+	}
+	var config models.ContestAPITokenConfig
+	if err := token.ScanConfig(&config); err != nil {
+		return false, err
+	}
+	if err := syncStore(c, v.core.Accounts); err != nil {
+		return false, err
+	}
+	account, err := v.core.Accounts.Get(getContext(c), token.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	accountCtx, err := v.accounts.MakeContext(getContext(c), &account)
+	if err != nil {
+		return false, err
+	}
+	accountCtx.RestrictToContest(config.ContestID)
+	c.Set(accountCtxKey, accountCtx)
+	c.Set(permissionCtxKey, accountCtx)
+	return true, nil
+}
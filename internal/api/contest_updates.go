@@ -0,0 +1,206 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerContestUpdateHandlers registers lightweight endpoint that
+// allows frontends to badge tabs of contest without polling of
+// messages, solutions and standings.
+func (v *View) registerContestUpdateHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/updates", v.observeContestUpdates,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestRole),
+	)
+}
+
+// ContestUpdates represents counters of contest changes.
+type ContestUpdates struct {
+	// NewMessages contains amount of visible messages with ID greater
+	// than message_id query parameter.
+	NewMessages int `json:"new_messages"`
+	// LastMessageID contains ID of last visible message.
+	LastMessageID int64 `json:"last_message_id,omitempty"`
+	// PendingSolutions contains amount of solutions of participant
+	// that are not judged yet.
+	PendingSolutions int `json:"pending_solutions"`
+	// SolutionsGeneration changes when any verdict of participant
+	// solutions is changed.
+	SolutionsGeneration string `json:"solutions_generation,omitempty"`
+	// StandingsGeneration changes when standings are changed.
+	StandingsGeneration string `json:"standings_generation,omitempty"`
+}
+
+type contestUpdatesFilter struct {
+	// MessageID contains ID of last message that was read.
+	MessageID int64 `query:"message_id"`
+	// Wait contains amount of seconds to wait for changes when
+	// request contains If-None-Match header.
+	Wait int `query:"wait"`
+}
+
+const (
+	maxUpdatesWait      = 60
+	updatesPollInterval = time.Second
+)
+
+func (f *contestUpdatesFilter) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
+		}
+	}
+	f.Wait = max(min(f.Wait, maxUpdatesWait), 0)
+	return nil
+}
+
+// getGeneration returns short hash of JSON representation of value.
+func getGeneration(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:8]), nil
+}
+
+func (v *View) buildContestUpdates(
+	c echo.Context, contestCtx *managers.ContestContext, filter contestUpdatesFilter,
+) (ContestUpdates, error) {
+	resp := ContestUpdates{}
+	if contestCtx.HasPermission(perms.ObserveContestMessagesRole) {
+		messages, err := collectRows(v.core.ContestMessages.FindByContest(
+			getContext(c), contestCtx.Contest.ID,
+		))
+		if err != nil {
+			return ContestUpdates{}, err
+		}
+		for _, message := range messages {
+			permissions := v.getContestMessagePermissions(contestCtx, message)
+			if !permissions.HasPermission(perms.ObserveContestMessageRole) {
+				continue
+			}
+			if message.ID > filter.MessageID {
+				resp.NewMessages++
+			}
+			resp.LastMessageID = max(resp.LastMessageID, message.ID)
+		}
+	}
+	if participant := contestCtx.GetEffectiveParticipant(); participant != nil {
+		contestSolutions, err := collectRows(v.core.ContestSolutions.FindByParticipant(
+			getContext(c), participant.ID,
+		))
+		if err != nil {
+			return ContestUpdates{}, err
+		}
+		type solutionVerdict struct {
+			ID      int64
+			Verdict models.Verdict
+		}
+		var verdicts []solutionVerdict
+		for _, contestSolution := range contestSolutions {
+			solution, err := v.core.Solutions.Get(getContext(c), contestSolution.ID)
+			if err != nil {
+				continue
+			}
+			verdict := solutionVerdict{ID: solution.ID}
+			if report, err := solution.GetReport(); err == nil && report != nil {
+				verdict.Verdict = report.Verdict
+			}
+			if verdict.Verdict == 0 {
+				resp.PendingSolutions++
+			}
+			verdicts = append(verdicts, verdict)
+		}
+		generation, err := getGeneration(verdicts)
+		if err != nil {
+			return ContestUpdates{}, err
+		}
+		resp.SolutionsGeneration = generation
+	}
+	if contestCtx.HasPermission(perms.ObserveContestStandingsRole) &&
+		contestCtx.ContestConfig.StandingsKind != models.DisabledStandings {
+		standings, err := v.standings.BuildStandings(
+			contestCtx, managers.BuildStandingsOptions{},
+		)
+		if err != nil {
+			return ContestUpdates{}, err
+		}
+		generation, err := getGeneration(standings)
+		if err != nil {
+			return ContestUpdates{}, err
+		}
+		resp.StandingsGeneration = generation
+	}
+	return resp, nil
+}
+
+// observeContestUpdates returns counters of contest changes.
+//
+// If request contains If-None-Match header and wait parameter, counters
+// are rebuilt until they change or wait time is expired.
+func (v *View) observeContestUpdates(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	filter := contestUpdatesFilter{}
+	if err := filter.Parse(c); err != nil {
+		c.Logger().Warn(err)
+		return err
+	}
+	deadline := time.NewTimer(time.Duration(filter.Wait) * time.Second)
+	defer deadline.Stop()
+	ticker := time.NewTicker(updatesPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := syncStore(c, v.core.ContestMessages); err != nil {
+			return err
+		}
+		if err := syncStore(c, v.core.ContestSolutions); err != nil {
+			return err
+		}
+		if err := syncStore(c, v.core.Solutions); err != nil {
+			return err
+		}
+		resp, err := v.buildContestUpdates(c, contestCtx, filter)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(data)
+		etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+		if !isNotModified(c.Request(), etag, time.Time{}) {
+			c.Response().Header().Set("ETag", etag)
+			return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, data)
+		}
+		if filter.Wait == 0 {
+			c.Response().Header().Set("ETag", etag)
+			return c.NoContent(http.StatusNotModified)
+		}
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-deadline.C:
+			c.Response().Header().Set("ETag", etag)
+			return c.NoContent(http.StatusNotModified)
+		case <-ticker.C:
+		}
+	}
+}
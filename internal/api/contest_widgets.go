@@ -0,0 +1,370 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerContestWidgetHandlers registers compact standings endpoints
+// for hall displays.
+//
+// Signed endpoints do not require authentication and are available
+// only when password salt is configured, because it is used as
+// signing key.
+func (v *View) registerContestWidgetHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/widgets/top", v.observeContestWidgetTop,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/widgets/ticker", v.observeContestWidgetTicker,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(perms.ObserveContestStandingsRole),
+	)
+	if v.getWidgetSecret() == "" {
+		return
+	}
+	g.POST(
+		"/v0/contests/:contest/widgets/links", v.createContestWidgetLink,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestRole),
+	)
+	g.GET(
+		"/v0/public/contests/:contest/widgets/top", v.observeContestWidgetTop,
+		v.extractAuth(v.guestAuth), v.extractContest, v.extractWidgetSignature,
+	)
+	g.GET(
+		"/v0/public/contests/:contest/widgets/ticker", v.observeContestWidgetTicker,
+		v.extractAuth(v.guestAuth), v.extractContest, v.extractWidgetSignature,
+	)
+}
+
+// ContestWidgetRow represents compact row of standings.
+type ContestWidgetRow struct {
+	Place   int     `json:"place"`
+	Title   string  `json:"title"`
+	Score   float64 `json:"score"`
+	Penalty *int64  `json:"penalty,omitempty"`
+	Solved  int     `json:"solved"`
+}
+
+// ContestWidgetTop represents top rows of standings.
+type ContestWidgetTop struct {
+	Stage  string             `json:"stage,omitempty"`
+	Frozen bool               `json:"frozen,omitempty"`
+	Rows   []ContestWidgetRow `json:"rows"`
+}
+
+// ContestWidgetTickerItem represents first accepted solution of
+// participant for problem.
+type ContestWidgetTickerItem struct {
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	ProblemCode string `json:"problem_code"`
+}
+
+// ContestWidgetTicker represents recent accepted solutions.
+type ContestWidgetTicker struct {
+	Stage  string                    `json:"stage,omitempty"`
+	Frozen bool                      `json:"frozen,omitempty"`
+	Items  []ContestWidgetTickerItem `json:"items"`
+}
+
+type contestWidgetFilter struct {
+	// Limit contains amount of returned rows.
+	Limit int `query:"limit"`
+	// ScopeID contains scope whose participants are shown, so every
+	// room with its own scope can have separate leaderboard.
+	ScopeID int64 `query:"scope_id"`
+	// Wait contains amount of seconds to wait for changes when
+	// request contains If-None-Match header.
+	Wait int `query:"wait"`
+}
+
+const (
+	defaultWidgetLimit = 10
+	maxWidgetLimit     = 100
+	maxWidgetWait      = 60
+	widgetPollInterval = time.Second
+)
+
+func (f *contestWidgetFilter) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
+		}
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultWidgetLimit
+	}
+	f.Limit = min(f.Limit, maxWidgetLimit)
+	f.Wait = max(min(f.Wait, maxWidgetWait), 0)
+	return nil
+}
+
+func (v *View) observeContestWidgetTop(c echo.Context) error {
+	return v.serveContestWidget(c, func(
+		contestCtx *managers.ContestContext,
+		filter contestWidgetFilter,
+		standings *managers.ContestStandings,
+	) any {
+		resp := ContestWidgetTop{
+			Stage:  makeContestStage(standings.Stage),
+			Frozen: standings.Frozen,
+			Rows:   []ContestWidgetRow{},
+		}
+		for _, row := range standings.Rows {
+			if row.Place == 0 {
+				continue
+			}
+			if len(resp.Rows) >= filter.Limit {
+				break
+			}
+			rowResp := ContestWidgetRow{
+				Place:   row.Place,
+				Title:   v.getStandingsRowTitle(c, row),
+				Score:   row.Score,
+				Penalty: row.Penalty,
+			}
+			for _, cell := range row.Cells {
+				if cell.Verdict == models.Accepted {
+					rowResp.Solved++
+				}
+			}
+			resp.Rows = append(resp.Rows, rowResp)
+		}
+		return resp
+	})
+}
+
+func (v *View) observeContestWidgetTicker(c echo.Context) error {
+	return v.serveContestWidget(c, func(
+		contestCtx *managers.ContestContext,
+		filter contestWidgetFilter,
+		standings *managers.ContestStandings,
+	) any {
+		resp := ContestWidgetTicker{
+			Stage:  makeContestStage(standings.Stage),
+			Frozen: standings.Frozen,
+			Items:  []ContestWidgetTickerItem{},
+		}
+		for _, row := range standings.Rows {
+			if row.Place == 0 {
+				continue
+			}
+			for _, cell := range row.Cells {
+				if cell.Verdict != models.Accepted {
+					continue
+				}
+				resp.Items = append(resp.Items, ContestWidgetTickerItem{
+					Time:        cell.Time,
+					Title:       v.getStandingsRowTitle(c, row),
+					ProblemCode: standings.Columns[cell.Column].Problem.Code,
+				})
+			}
+		}
+		sortFunc(resp.Items, func(lhs, rhs ContestWidgetTickerItem) bool {
+			return lhs.Time > rhs.Time
+		})
+		if len(resp.Items) > filter.Limit {
+			resp.Items = resp.Items[:filter.Limit]
+		}
+		return resp
+	})
+}
+
+func (v *View) getStandingsRowTitle(
+	c echo.Context, row managers.ContestStandingsRow,
+) string {
+	if row.FakeParticipant != nil {
+		return row.FakeParticipant.Title
+	}
+	participant := makeContestParticipant(c, row.Participant, v.core)
+	switch {
+	case participant.User != nil:
+		return participant.User.Login
+	case participant.ScopeUser != nil:
+		if participant.ScopeUser.Title != "" {
+			return participant.ScopeUser.Title
+		}
+		return participant.ScopeUser.Login
+	case participant.Scope != nil:
+		return participant.Scope.Title
+	case participant.Group != nil:
+		return participant.Group.Title
+	}
+	return ""
+}
+
+// serveContestWidget builds widget and sends it with ETag.
+//
+// If request contains If-None-Match header and wait parameter, widget
+// is rebuilt until it changes or wait time is expired.
+func (v *View) serveContestWidget(
+	c echo.Context,
+	build func(*managers.ContestContext, contestWidgetFilter, *managers.ContestStandings) any,
+) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	filter := contestWidgetFilter{}
+	if err := filter.Parse(c); err != nil {
+		c.Logger().Warn(err)
+		return err
+	}
+	if contestCtx.ContestConfig.StandingsKind == models.DisabledStandings {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeStandingsDisabled,
+			Message:   localize(c, "Standings are disabled."),
+		}
+	}
+	deadline := time.NewTimer(time.Duration(filter.Wait) * time.Second)
+	defer deadline.Stop()
+	ticker := time.NewTicker(widgetPollInterval)
+	defer ticker.Stop()
+	for {
+		standings, err := v.standings.BuildStandings(
+			contestCtx, managers.BuildStandingsOptions{ScopeID: filter.ScopeID},
+		)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(build(contestCtx, filter, standings))
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256(data)
+		etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+		if !isNotModified(c.Request(), etag, time.Time{}) {
+			c.Response().Header().Set("ETag", etag)
+			return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, data)
+		}
+		if filter.Wait == 0 {
+			c.Response().Header().Set("ETag", etag)
+			return c.NoContent(http.StatusNotModified)
+		}
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-deadline.C:
+			c.Response().Header().Set("ETag", etag)
+			return c.NoContent(http.StatusNotModified)
+		case <-ticker.C:
+		}
+		if err := syncStore(c, v.core.ContestSolutions); err != nil {
+			return err
+		}
+		if err := syncStore(c, v.core.Solutions); err != nil {
+			return err
+		}
+	}
+}
+
+// CreateContestWidgetLinkForm represents form for signing public
+// widget links.
+type CreateContestWidgetLinkForm struct {
+	// ExpireTime contains expiration time of link.
+	ExpireTime int64 `json:"expire_time"`
+}
+
+// ContestWidgetLink represents signed query of public widget.
+type ContestWidgetLink struct {
+	ExpireTime int64  `json:"expire_time"`
+	Signature  string `json:"signature"`
+	// Query contains query parameters that should be added to public
+	// widget URL.
+	Query string `json:"query"`
+}
+
+func (v *View) getWidgetSecret() string {
+	if v.core.Config.Security == nil {
+		return ""
+	}
+	return v.core.Config.Security.PasswordSalt
+}
+
+func (v *View) signContestWidget(contestID int64, expireTime int64) string {
+	mac := hmac.New(sha256.New, []byte(v.getWidgetSecret()))
+	fmt.Fprintf(mac, "contest_widget:%d:%d", contestID, expireTime)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (v *View) createContestWidgetLink(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestWidgetLinkForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if form.ExpireTime <= getNow(c).Unix() {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"expire_time": errorField{
+					Message: localize(c, "Expiration time should be in future."),
+				},
+			},
+		}
+	}
+	signature := v.signContestWidget(contestCtx.Contest.ID, form.ExpireTime)
+	query := url.Values{}
+	query.Set("expire", strconv.FormatInt(form.ExpireTime, 10))
+	query.Set("signature", signature)
+	return c.JSON(http.StatusCreated, ContestWidgetLink{
+		ExpireTime: form.ExpireTime,
+		Signature:  signature,
+		Query:      query.Encode(),
+	})
+}
+
+// extractWidgetSignature checks signature of public widget link and
+// allows to observe standings of contest.
+func (v *View) extractWidgetSignature(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+		if !ok {
+			return fmt.Errorf("contest not extracted")
+		}
+		resp := errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeInvalidSignature,
+			Message:   localize(c, "Invalid signature."),
+		}
+		expireTime, err := strconv.ParseInt(c.QueryParam("expire"), 10, 64)
+		if err != nil || expireTime <= getNow(c).Unix() {
+			return resp
+		}
+		signature := v.signContestWidget(contestCtx.Contest.ID, expireTime)
+		if !hmac.Equal([]byte(signature), []byte(c.QueryParam("signature"))) {
+			return resp
+		}
+		contestCtx.Permissions.AddPermission(perms.ObserveContestStandingsRole)
+		return next(c)
+	}
+}
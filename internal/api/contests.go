@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -34,7 +38,7 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 	)
 	g.GET(
 		"/v0/contests/:contest", v.observeContest,
-		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.extractAuth(v.sessionAuth, v.contestTokenAuth, v.guestAuth), v.extractContest,
 		v.requirePermission(perms.ObserveContestRole),
 	)
 	g.PATCH(
@@ -49,7 +53,7 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 	)
 	g.GET(
 		"/v0/contests/:contest/problems", v.observeContestProblems,
-		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.extractAuth(v.sessionAuth, v.contestTokenAuth, v.guestAuth), v.extractContest,
 		v.requirePermission(perms.ObserveContestProblemsRole),
 	)
 	g.GET(
@@ -78,6 +82,11 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth), v.extractContest,
 		v.requirePermission(perms.CreateContestProblemRole),
 	)
+	g.POST(
+		"/v0/contests/:contest/problems/order", v.reorderContestProblems,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.UpdateContestProblemRole),
+	)
 	g.PATCH(
 		"/v0/contests/:contest/problems/:problem", v.updateContestProblem,
 		v.extractAuth(v.sessionAuth), v.extractContest,
@@ -95,18 +104,26 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 		v.submitContestProblemSolution, v.extractAuth(v.sessionAuth),
 		v.extractContest, v.extractContestProblem,
 		v.requirePermission(perms.SubmitContestSolutionRole),
+		v.limitSubmitBody,
 	)
 	g.GET(
 		"/v0/contests/:contest/solutions", v.observeContestSolutions,
-		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.extractAuth(v.sessionAuth, v.contestTokenAuth, v.guestAuth), v.extractContest,
 		v.requirePermission(perms.ObserveContestSolutionsRole),
 	)
 	g.GET(
 		"/v0/contests/:contest/solutions/:solution", v.observeContestSolution,
-		v.extractAuth(v.sessionAuth, v.guestAuth),
+		v.extractAuth(v.sessionAuth, v.contestTokenAuth, v.guestAuth),
 		v.extractContest, v.extractContestSolution,
 		v.requirePermission(perms.ObserveContestSolutionRole),
 	)
+	g.GET(
+		"/v0/contests/:contest/solutions/:solution/tests/:test/artifacts/:artifact",
+		v.observeContestSolutionTestArtifact,
+		v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestSolution,
+		v.requirePermission(perms.ObserveSolutionReportCheckerLogs),
+	)
 	g.POST(
 		"/v0/contests/:contest/solutions/:solution/rejudge", v.rejudgeContestSolution,
 		v.extractAuth(v.sessionAuth),
@@ -123,6 +140,18 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth), v.extractContest,
 		v.requirePermission(perms.CreateContestParticipantRole),
 	)
+	g.POST(
+		"/v0/contests/:contest/participants/groups/:group",
+		v.createContestGroupParticipants,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(perms.CreateContestParticipantRole),
+	)
+	g.PATCH(
+		"/v0/contests/:contest/participants/:participant",
+		v.updateContestParticipant, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestParticipant,
+		v.requirePermission(perms.CreateContestParticipantRole),
+	)
 	g.DELETE(
 		"/v0/contests/:contest/participants/:participant",
 		v.deleteContestParticipant, v.extractAuth(v.sessionAuth),
@@ -139,6 +168,12 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 type ContestState struct {
 	Stage     string `json:"stage"`
 	BeginTime int64  `json:"begin_time,omitempty"`
+	// ServerTime contains time of server when state is computed.
+	ServerTime int64 `json:"server_time,omitempty"`
+	// TimeToStart contains amount of seconds before contest start.
+	TimeToStart *int64 `json:"time_to_start,omitempty"`
+	// RemainingTime contains amount of seconds before contest end.
+	RemainingTime *int64 `json:"remaining_time,omitempty"`
 	// Participant contains effective participant.
 	Participant *ContestParticipant `json:"participant,omitempty"`
 }
@@ -156,7 +191,30 @@ type Contest struct {
 	FreezeBeginDuration int                  `json:"freeze_begin_duration,omitempty"`
 	FreezeEndTime       NInt64               `json:"freeze_end_time,omitempty"`
 	StandingsKind       models.StandingsKind `json:"standings_kind,omitempty"`
-	State               *ContestState        `json:"state,omitempty"`
+	PenaltyTime         *int                 `json:"penalty_time,omitempty"`
+	HideFrozenAttempts  bool                 `json:"hide_frozen_attempts,omitempty"`
+	EnableFeedback      bool                 `json:"enable_feedback,omitempty"`
+	FinalizeTime        NInt64               `json:"finalize_time,omitempty"`
+	ScopeID             NInt64               `json:"scope_id,omitempty"`
+	Locale              string               `json:"locale,omitempty"`
+	// DefaultCompilers contains compilers for solutions submitted
+	// without compiler.
+	DefaultCompilers map[string]int64 `json:"default_compilers,omitempty"`
+	// PublishTime contains time when contest becomes visible.
+	PublishTime NInt64 `json:"publish_time,omitempty"`
+	// Compilers contains IDs of allowed compilers.
+	Compilers []int64 `json:"compilers,omitempty"`
+	// EnablePretests means that solutions are judged only on pretests
+	// until system testing.
+	EnablePretests bool `json:"enable_pretests,omitempty"`
+	// PretestGroup contains name of test group with pretests.
+	PretestGroup string `json:"pretest_group,omitempty"`
+	// SystemTestTime contains time when system testing was started.
+	SystemTestTime NInt64 `json:"system_test_time,omitempty"`
+	// Actions contains scheduled actions and is visible only for
+	// accounts that can update contest.
+	Actions []models.ContestAction `json:"actions,omitempty"`
+	State   *ContestState          `json:"state,omitempty"`
 }
 
 type Contests struct {
@@ -171,6 +229,35 @@ type ContestProblem struct {
 	Points    *int     `json:"points,omitempty"`
 	Locales   []string `json:"locales,omitempty"`
 	Solved    *bool    `json:"solved,omitempty"`
+	// Position contains explicit position of problem in contest.
+	Position *int `json:"position,omitempty"`
+	// Title contains display title of problem in contest.
+	Title *string `json:"title,omitempty"`
+	// Color contains display color of problem in contest.
+	Color *string `json:"color,omitempty"`
+	// MaxSolutions contains maximum amount of solutions per participant.
+	MaxSolutions *int `json:"max_solutions,omitempty"`
+	// SolutionInterval contains minimum interval between solutions in
+	// seconds.
+	SolutionInterval *int `json:"solution_interval,omitempty"`
+	// FeedbackPolicy contains details of reports shown to participants.
+	FeedbackPolicy models.FeedbackPolicy `json:"feedback_policy,omitempty"`
+	// Constraints contains effective constraints of submissions.
+	Constraints *ContestProblemConstraints `json:"constraints,omitempty"`
+}
+
+// ContestProblemConstraints represents effective constraints for
+// solutions submitted by current account.
+type ContestProblemConstraints struct {
+	// MaxFileSize contains maximum size of solution in bytes.
+	MaxFileSize int64 `json:"max_file_size"`
+	// Compilers contains IDs of compilers available for account.
+	Compilers []int64 `json:"compilers,omitempty"`
+	// QuotaWindow contains duration of contest-wide quota window
+	// in seconds.
+	QuotaWindow int64 `json:"quota_window,omitempty"`
+	// QuotaAmount contains amount of solutions allowed in quota window.
+	QuotaAmount int64 `json:"quota_amount,omitempty"`
 }
 
 type ContestProblems struct {
@@ -183,6 +270,7 @@ var contestPermissions = []string{
 	perms.DeleteContestRole,
 	perms.RegisterContestRole,
 	perms.RegisterContestVirtualRole,
+	perms.RegisterContestObserverRole,
 	perms.DeregisterContestRole,
 	perms.ObserveContestProblemsRole,
 	perms.CreateContestProblemRole,
@@ -203,6 +291,20 @@ var contestPermissions = []string{
 	perms.UpdateContestMessageRole,
 	perms.DeleteContestMessageRole,
 	perms.SubmitContestQuestionRole,
+	perms.ObserveContestFeedbackRole,
+	perms.SubmitContestFeedbackRole,
+	perms.FinalizeContestRole,
+	perms.ObserveContestGrantsRole,
+	perms.CreateContestGrantRole,
+	perms.DeleteContestGrantRole,
+	perms.ObserveContestAppealsRole,
+	perms.CreateContestAppealRole,
+	perms.ResolveContestAppealRole,
+	perms.ObserveContestScoreOverridesRole,
+	perms.CreateContestScoreOverrideRole,
+	perms.DeleteContestScoreOverrideRole,
+	perms.ObserveContestDashboardRole,
+	perms.ExportContestSolutionsRole,
 }
 
 func makeContestStage(stage managers.ContestStage) string {
@@ -226,7 +328,11 @@ func makeContest(
 	permissions perms.Permissions,
 	core *core.Core,
 ) Contest {
-	resp := Contest{ID: contest.ID, Title: contest.Title}
+	resp := Contest{
+		ID:      contest.ID,
+		Title:   contest.Title,
+		ScopeID: contest.ScopeID,
+	}
 	if config, err := contest.GetConfig(); err == nil {
 		resp.BeginTime = config.BeginTime
 		resp.Duration = config.Duration
@@ -237,6 +343,20 @@ func makeContest(
 		resp.FreezeBeginDuration = config.FreezeBeginDuration
 		resp.FreezeEndTime = config.FreezeEndTime
 		resp.StandingsKind = config.StandingsKind
+		resp.PenaltyTime = config.PenaltyTime
+		resp.HideFrozenAttempts = config.HideFrozenAttempts
+		resp.EnableFeedback = config.Feedback != nil
+		resp.FinalizeTime = config.FinalizeTime
+		resp.Locale = config.Locale
+		resp.DefaultCompilers = config.DefaultCompilers
+		resp.PublishTime = config.PublishTime
+		resp.Compilers = config.Compilers
+		resp.EnablePretests = config.EnablePretests
+		resp.PretestGroup = config.PretestGroup
+		resp.SystemTestTime = config.SystemTestTime
+		if permissions.HasPermission(perms.UpdateContestRole) {
+			resp.Actions = config.Actions
+		}
 	}
 	for _, permission := range contestPermissions {
 		if permissions.HasPermission(permission) {
@@ -244,9 +364,19 @@ func makeContest(
 		}
 	}
 	if contextCtx, ok := permissions.(*managers.ContestContext); ok {
+		contestTime := contextCtx.GetEffectiveContestTime()
 		state := ContestState{
-			Stage:     makeContestStage(contextCtx.GetEffectiveContestTime().Stage()),
-			BeginTime: contextCtx.GetEffectiveBeginTime(),
+			Stage:      makeContestStage(contestTime.Stage()),
+			BeginTime:  contextCtx.GetEffectiveBeginTime(),
+			ServerTime: contextCtx.Now.Unix(),
+		}
+		switch contestTime.Stage() {
+		case managers.ContestNotStarted:
+			state.TimeToStart = getPtr(state.BeginTime - state.ServerTime)
+		case managers.ContestStarted:
+			state.RemainingTime = getPtr(
+				state.BeginTime + int64(contextCtx.ContestConfig.Duration) - state.ServerTime,
+			)
 		}
 		participant := contextCtx.GetEffectiveParticipant()
 		if core != nil && participant != nil {
@@ -269,9 +399,16 @@ func (v *View) makeContestProblem(
 		Code:      contestProblem.Code,
 	}
 	locales := map[string]struct{}{}
-	if config, err := contestProblem.GetConfig(); err == nil {
+	config, err := contestProblem.GetConfig()
+	if err == nil {
 		resp.Points = config.Points
 		resp.Locales = config.Locales
+		resp.Position = config.Position
+		resp.Title = config.Title
+		resp.Color = config.Color
+		resp.MaxSolutions = config.MaxSolutions
+		resp.SolutionInterval = config.SolutionInterval
+		resp.FeedbackPolicy = config.FeedbackPolicy
 		for _, locale := range config.Locales {
 			locales[locale] = struct{}{}
 		}
@@ -283,6 +420,15 @@ func (v *View) makeContestProblem(
 			c, problem, perms.PermissionSet{}, withStatement, false, locales,
 		)
 		resp.Problem.Permissions = nil
+		// Statement should contain limits that are used for judgement.
+		if problemConfig := resp.Problem.Config; problemConfig != nil {
+			if config.TimeLimit != nil {
+				problemConfig.TimeLimit = *config.TimeLimit
+			}
+			if config.MemoryLimit != nil {
+				problemConfig.MemoryLimit = *config.MemoryLimit
+			}
+		}
 	}
 	return resp
 }
@@ -312,8 +458,9 @@ func (v *View) observeContests(c echo.Context) error {
 	if err := c.Bind(&filter); err != nil {
 		c.Logger().Warn(err)
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid filter."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
 		}
 	}
 	if err := syncStore(c, v.core.Contests); err != nil {
@@ -371,6 +518,36 @@ type updateContestForm struct {
 	FreezeEndTime       *NInt64               `json:"freeze_end_time" form:"freeze_end_time"`
 	StandingsKind       *models.StandingsKind `json:"standings_kind" form:"standings_kind"`
 	OwnerID             *int64                `json:"owner_id" form:"owner_id"`
+	// ScopeID contains scope that owns contest, zero value removes
+	// contest from scope.
+	ScopeID *int64 `json:"scope_id" form:"scope_id"`
+	// Feedback contains feedback config, empty list of questions
+	// disables feedback.
+	Feedback *models.ContestFeedbackConfig `json:"feedback"`
+	Actions  *[]models.ContestAction       `json:"actions"`
+	// Awards contains award rules, all zero values disable awards.
+	Awards *models.ContestAwardsConfig `json:"awards"`
+	// Locale contains default locale of problem statements.
+	Locale *string `json:"locale" form:"locale"`
+	// PenaltyTime contains penalty in minutes for rejected attempt,
+	// negative value resets penalty to default.
+	PenaltyTime        *int  `json:"penalty_time" form:"penalty_time"`
+	HideFrozenAttempts *bool `json:"hide_frozen_attempts" form:"hide_frozen_attempts"`
+	// DefaultCompilers contains mapping from language to compiler,
+	// empty mapping disables default compilers.
+	DefaultCompilers *map[string]int64 `json:"default_compilers"`
+	// PublishTime contains time when contest becomes visible, zero
+	// value publishes contest immediately.
+	PublishTime *NInt64 `json:"publish_time" form:"publish_time"`
+	// Compilers contains IDs of allowed compilers, empty list allows
+	// all compilers.
+	Compilers *[]int64 `json:"compilers"`
+	// EnablePretests enables judgement only on groups marked as
+	// pretests in problem packages.
+	EnablePretests *bool `json:"enable_pretests" form:"enable_pretests"`
+	// PretestGroup contains name of test group with pretests that
+	// overrides marked groups, empty value resets group.
+	PretestGroup *string `json:"pretest_group" form:"pretest_group"`
 }
 
 func (f *updateContestForm) Update(
@@ -422,9 +599,92 @@ func (f *updateContestForm) Update(
 	if f.StandingsKind != nil {
 		config.StandingsKind = *f.StandingsKind
 	}
+	if f.PenaltyTime != nil {
+		if *f.PenaltyTime < 0 {
+			config.PenaltyTime = nil
+		} else {
+			config.PenaltyTime = f.PenaltyTime
+		}
+	}
+	if f.HideFrozenAttempts != nil {
+		config.HideFrozenAttempts = *f.HideFrozenAttempts
+	}
 	if f.EnableObserving != nil {
 		config.EnableObserving = *f.EnableObserving
 	}
+	if f.PublishTime != nil {
+		if *f.PublishTime < 0 {
+			errors["publish_time"] = errorField{
+				Message: localize(c, "Publish time cannot be negative."),
+			}
+		}
+		config.PublishTime = *f.PublishTime
+	}
+	if f.Compilers != nil {
+		if len(*f.Compilers) == 0 {
+			config.Compilers = nil
+		} else {
+			config.Compilers = slices.Clone(*f.Compilers)
+			slices.Sort(config.Compilers)
+			config.Compilers = slices.Compact(config.Compilers)
+		}
+	}
+	if f.EnablePretests != nil {
+		config.EnablePretests = *f.EnablePretests
+	}
+	if f.PretestGroup != nil {
+		if len(*f.PretestGroup) > 32 {
+			errors["pretest_group"] = errorField{
+				Message: localize(c, "Pretest group is too long."),
+			}
+		}
+		config.PretestGroup = *f.PretestGroup
+	}
+	if f.Locale != nil {
+		if len(*f.Locale) > 16 {
+			errors["locale"] = errorField{
+				Message: localize(c, "Locale is too long."),
+			}
+		}
+		config.Locale = *f.Locale
+	}
+	if f.DefaultCompilers != nil {
+		if len(*f.DefaultCompilers) == 0 {
+			config.DefaultCompilers = nil
+		} else {
+			config.DefaultCompilers = map[string]int64{}
+			for language, id := range *f.DefaultCompilers {
+				config.DefaultCompilers[strings.ToLower(language)] = id
+			}
+		}
+	}
+	if f.Feedback != nil {
+		if len(f.Feedback.Questions) == 0 {
+			config.Feedback = nil
+		} else if err := validateContestFeedbackConfig(c, f.Feedback); err != nil {
+			errors["feedback"] = *err
+		} else {
+			config.Feedback = f.Feedback
+		}
+	}
+	if f.Awards != nil {
+		if f.Awards.Gold < 0 || f.Awards.Silver < 0 || f.Awards.Bronze < 0 {
+			errors["awards"] = errorField{
+				Message: localize(c, "Amount of awards cannot be negative."),
+			}
+		} else if *f.Awards == (models.ContestAwardsConfig{}) {
+			config.Awards = nil
+		} else {
+			config.Awards = f.Awards
+		}
+	}
+	if f.Actions != nil {
+		if err := validateContestActions(c, *f.Actions); err != nil {
+			errors["actions"] = *err
+		} else {
+			config.Actions = mergeContestActions(config.Actions, *f.Actions)
+		}
+	}
 	if err := contest.SetConfig(config); err != nil {
 		errors["config"] = errorField{
 			Message: localize(c, "Invalid config."),
@@ -433,6 +693,7 @@ func (f *updateContestForm) Update(
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -440,6 +701,56 @@ func (f *updateContestForm) Update(
 	return nil
 }
 
+func validateContestActions(
+	c echo.Context, actions []models.ContestAction,
+) *errorField {
+	for _, action := range actions {
+		if action.Time < 0 {
+			return &errorField{
+				Message: localize(c, "Action time cannot be negative."),
+			}
+		}
+		switch action.Kind {
+		case models.AnnouncementContestAction:
+			if len(action.Title) == 0 {
+				return &errorField{
+					Message: localize(c, "Announcement should have title."),
+				}
+			}
+		case models.FreezeContestAction,
+			models.UnfreezeContestAction,
+			models.CloseRegistrationContestAction,
+			models.FinalizeStandingsContestAction,
+			models.SystemTestContestAction:
+		default:
+			return &errorField{
+				Message: localize(c, "Invalid action kind."),
+			}
+		}
+	}
+	return nil
+}
+
+// mergeContestActions returns new list of actions with preserved
+// execution times of already executed actions.
+func mergeContestActions(
+	oldActions, newActions []models.ContestAction,
+) []models.ContestAction {
+	executed := map[models.ContestAction]models.NInt64{}
+	for _, action := range oldActions {
+		if executeTime := action.ExecuteTime; executeTime != 0 {
+			action.ExecuteTime = 0
+			executed[action] = executeTime
+		}
+	}
+	var actions []models.ContestAction
+	for _, action := range newActions {
+		action.ExecuteTime = executed[action]
+		actions = append(actions, action)
+	}
+	return actions
+}
+
 type createContestForm updateContestForm
 
 func (f *createContestForm) Update(
@@ -447,8 +758,9 @@ func (f *createContestForm) Update(
 ) error {
 	if f.Title == nil {
 		return &errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"title": errorField{
 					Message: localize(c, "Title is required."),
@@ -470,12 +782,28 @@ func (v *View) createContest(c echo.Context) error {
 		return c.NoContent(http.StatusBadRequest)
 	}
 	var contest models.Contest
+	if name := c.QueryParam("template"); name != "" {
+		var template updateContestForm
+		if err := v.getContestTemplate(c, name, &template); err != nil {
+			return err
+		}
+		if err := template.Update(c, &contest); err != nil {
+			return err
+		}
+	}
+	// Fields of form override fields of template.
 	if err := form.Update(c, &contest); err != nil {
 		return err
 	}
 	if account := accountCtx.Account; account != nil {
 		contest.OwnerID = NInt64(account.ID)
 	}
+	if form.ScopeID != nil {
+		if err := v.checkEntityScope(c, accountCtx, *form.ScopeID); err != nil {
+			return err
+		}
+		contest.ScopeID = NInt64(*form.ScopeID)
+	}
 	if err := v.core.Contests.Create(getContext(c), &contest); err != nil {
 		return err
 	}
@@ -485,6 +813,39 @@ func (v *View) createContest(c echo.Context) error {
 	)
 }
 
+// contestTemplateSettingPrefix contains prefix of settings with
+// contest templates.
+//
+// Value of setting contains JSON of contest form, for example setting
+// "contests.templates.weekly" is used for "?template=weekly".
+const contestTemplateSettingPrefix = "contests.templates."
+
+// getContestTemplate fills form with fields of contest template.
+func (v *View) getContestTemplate(
+	c echo.Context, name string, form *updateContestForm,
+) error {
+	setting, err := v.core.Settings.GetByKey(contestTemplateSettingPrefix + name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeContestTemplateNotFound,
+				Message:   localize(c, "Contest template not found."),
+			}
+		}
+		return err
+	}
+	if err := json.Unmarshal([]byte(setting.Value), form); err != nil {
+		c.Logger().Warn("Invalid contest template", logs.Any("name", name), err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidContestTemplate,
+			Message:   localize(c, "Invalid contest template."),
+		}
+	}
+	return nil
+}
+
 func (v *View) updateContest(c echo.Context) error {
 	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
 	if !ok {
@@ -508,16 +869,18 @@ func (v *View) updateContest(c echo.Context) error {
 			if err != nil {
 				if err == sql.ErrNoRows {
 					return errorResponse{
-						Code:    http.StatusBadRequest,
-						Message: localize(c, "User not found."),
+						Code:      http.StatusBadRequest,
+						ErrorCode: CodeUserNotFound,
+						Message:   localize(c, "User not found."),
 					}
 				}
 				return err
 			}
 			if account.Kind != models.UserAccountKind {
 				return errorResponse{
-					Code:    http.StatusBadRequest,
-					Message: localize(c, "User not found."),
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeUserNotFound,
+					Message:   localize(c, "User not found."),
 				}
 			}
 			contest.OwnerID = models.NInt64(*form.OwnerID)
@@ -526,10 +889,17 @@ func (v *View) updateContest(c echo.Context) error {
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
 	}
+	if form.ScopeID != nil {
+		if err := v.checkEntityScope(c, contestCtx.AccountContext, *form.ScopeID); err != nil {
+			return err
+		}
+		contest.ScopeID = models.NInt64(*form.ScopeID)
+	}
 	if err := v.core.Contests.Update(getContext(c), contest); err != nil {
 		return err
 	}
@@ -590,15 +960,26 @@ func (v *View) observeContestProblems(c echo.Context) error {
 	}
 	contest := contestCtx.Contest
 	solvedProblems := getSolvedProblems(contestCtx, v.core)
-	problems, err := v.core.ContestProblems.FindByContest(getContext(c), contest.ID)
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	problemRows, err := v.core.ContestProblems.FindByContest(getContext(c), contest.ID)
+	if err != nil {
+		return err
+	}
+	problems, err := db.CollectRows(problemRows)
+	if err != nil {
+		return err
+	}
+	models.SortContestProblems(problems)
+	constraints, err := v.makeContestProblemConstraints(c, contestCtx)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = problems.Close() }()
 	resp := ContestProblems{}
-	for problems.Next() {
-		problem := problems.Row()
+	for _, problem := range problems {
 		problemResp := v.makeContestProblem(c, problem, false)
+		problemResp.Constraints = constraints
 		if v, ok := solvedProblems[problem.ID]; ok {
 			problemResp.Solved = &v
 		}
@@ -607,19 +988,72 @@ func (v *View) observeContestProblems(c echo.Context) error {
 			problemResp,
 		)
 	}
-	if err := problems.Err(); err != nil {
-		return err
-	}
-	sortFunc(resp.Problems, contestProblemLess)
-	return c.JSON(http.StatusOK, resp)
+	return jsonWithETag(c, http.StatusOK, resp)
 }
 
 func (v *View) observeContestProblem(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
 	problem, ok := c.Get(contestProblemKey).(models.ContestProblem)
 	if !ok {
 		return fmt.Errorf("contest problem not extracted")
 	}
-	return c.JSON(http.StatusOK, v.makeContestProblem(c, problem, true))
+	resp := v.makeContestProblem(c, problem, true)
+	constraints, err := v.makeContestProblemConstraints(c, contestCtx)
+	if err != nil {
+		return err
+	}
+	resp.Constraints = constraints
+	return c.JSON(http.StatusOK, resp)
+}
+
+// maxSolutionFileSize contains maximum size of submitted solution.
+const maxSolutionFileSize = 256 * 1024
+
+// makeContestProblemConstraints returns constraints that are checked on
+// submission, so frontends can validate solutions before submitting.
+//
+// Returns nil if account cannot submit solutions.
+func (v *View) makeContestProblemConstraints(
+	c echo.Context, contestCtx *managers.ContestContext,
+) (*ContestProblemConstraints, error) {
+	if !contestCtx.HasEffectivePermission(perms.SubmitContestSolutionRole) {
+		return nil, nil
+	}
+	constraints := ContestProblemConstraints{
+		MaxFileSize: maxSolutionFileSize,
+	}
+	participant := contestCtx.GetEffectiveParticipant()
+	if participant == nil || participant.Kind != models.ManagerParticipant {
+		constraints.QuotaWindow, constraints.QuotaAmount = v.getSolutionsQuota(c.Logger())
+	}
+	if err := syncStore(c, v.core.Compilers); err != nil {
+		return nil, err
+	}
+	compilers, err := v.core.Compilers.All(getContext(c), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = compilers.Close() }()
+	for compilers.Next() {
+		compiler := compilers.Row()
+		if !contestCtx.ContestConfig.IsCompilerAllowed(compiler.ID) {
+			continue
+		}
+		permissions := v.getCompilerPermissions(contestCtx.AccountContext, compiler)
+		if permissions.HasPermission(perms.ObserveCompilerRole) {
+			constraints.Compilers = append(constraints.Compilers, compiler.ID)
+		}
+	}
+	if err := compilers.Err(); err != nil {
+		return nil, err
+	}
+	sortFunc(constraints.Compilers, func(lhs, rhs int64) bool {
+		return lhs < rhs
+	})
+	return &constraints, nil
 }
 
 type updateContestProblemForm struct {
@@ -627,8 +1061,26 @@ type updateContestProblemForm struct {
 	ProblemID *int64    `json:"problem_id"`
 	Points    *int      `json:"points"`
 	Locales   *[]string `json:"locales"`
+	Position  *int      `json:"position"`
+	Title     *string   `json:"title"`
+	Color     *string   `json:"color"`
+	// MaxSolutions contains maximum amount of solutions per participant.
+	//
+	// Zero value removes limit.
+	MaxSolutions *int `json:"max_solutions"`
+	// SolutionInterval contains minimum interval between solutions in
+	// seconds.
+	//
+	// Zero value removes limit.
+	SolutionInterval *int `json:"solution_interval"`
+	// FeedbackPolicy contains details of reports shown to participants.
+	//
+	// Empty value resets policy.
+	FeedbackPolicy *models.FeedbackPolicy `json:"feedback_policy"`
 }
 
+var contestProblemColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
 func (f updateContestProblemForm) Update(
 	c echo.Context,
 	problem *models.ContestProblem,
@@ -648,9 +1100,42 @@ func (f updateContestProblemForm) Update(
 		}
 		problem.Code = *f.Code
 	}
+	if f.Position != nil && *f.Position < 0 {
+		errors["position"] = errorField{
+			Message: localize(c, "Position cannot be negative."),
+		}
+	}
+	if f.Title != nil && len(*f.Title) > 64 {
+		errors["title"] = errorField{
+			Message: localize(c, "Title is too long."),
+		}
+	}
+	if f.Color != nil && len(*f.Color) > 0 &&
+		!contestProblemColorRegexp.MatchString(*f.Color) {
+		errors["color"] = errorField{
+			Message: localize(c, "Color has invalid format."),
+		}
+	}
+	if f.MaxSolutions != nil && *f.MaxSolutions < 0 {
+		errors["max_solutions"] = errorField{
+			Message: localize(c, "Limit cannot be negative."),
+		}
+	}
+	if f.SolutionInterval != nil && *f.SolutionInterval < 0 {
+		errors["solution_interval"] = errorField{
+			Message: localize(c, "Interval cannot be negative."),
+		}
+	}
+	if f.FeedbackPolicy != nil && *f.FeedbackPolicy != "" &&
+		!f.FeedbackPolicy.IsValid() {
+		errors["feedback_policy"] = errorField{
+			Message: localize(c, "Feedback policy is not supported."),
+		}
+	}
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -658,7 +1143,8 @@ func (f updateContestProblemForm) Update(
 	if f.ProblemID != nil {
 		if _, err := problems.Get(getContext(c), *f.ProblemID); err != nil {
 			return &errorResponse{
-				Code: http.StatusNotFound,
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeProblemNotFound,
 				Message: localize(
 					c, "Problem {id} does not exists.",
 					replaceField("id", *f.ProblemID),
@@ -684,6 +1170,50 @@ func (f updateContestProblemForm) Update(
 		config.Locales = *f.Locales
 		configUpdated = true
 	}
+	if f.Position != nil {
+		if *f.Position != 0 {
+			config.Position = f.Position
+		} else {
+			config.Position = nil
+		}
+		configUpdated = true
+	}
+	if f.Title != nil {
+		if len(*f.Title) != 0 {
+			config.Title = f.Title
+		} else {
+			config.Title = nil
+		}
+		configUpdated = true
+	}
+	if f.Color != nil {
+		if len(*f.Color) != 0 {
+			config.Color = f.Color
+		} else {
+			config.Color = nil
+		}
+		configUpdated = true
+	}
+	if f.MaxSolutions != nil {
+		if *f.MaxSolutions != 0 {
+			config.MaxSolutions = f.MaxSolutions
+		} else {
+			config.MaxSolutions = nil
+		}
+		configUpdated = true
+	}
+	if f.SolutionInterval != nil {
+		if *f.SolutionInterval != 0 {
+			config.SolutionInterval = f.SolutionInterval
+		} else {
+			config.SolutionInterval = nil
+		}
+		configUpdated = true
+	}
+	if f.FeedbackPolicy != nil {
+		config.FeedbackPolicy = *f.FeedbackPolicy
+		configUpdated = true
+	}
 	if configUpdated {
 		if err := problem.SetConfig(config); err != nil {
 			return err
@@ -701,8 +1231,9 @@ func (f *createContestProblemForm) Update(
 ) error {
 	if f.Code == nil {
 		return &errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"title": errorField{
 					Message: localize(c, "Code is empty."),
@@ -712,7 +1243,8 @@ func (f *createContestProblemForm) Update(
 	}
 	if f.ProblemID == nil {
 		return &errorResponse{
-			Code: http.StatusNotFound,
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeProblemNotFound,
 			Message: localize(
 				c, "Problem {id} does not exists.",
 				replaceField("id", 0),
@@ -751,7 +1283,8 @@ func (v *View) createContestProblem(c echo.Context) error {
 			row := rows.Row()
 			if problem.Code == row.Code {
 				return errorResponse{
-					Code: http.StatusBadRequest,
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeProblemAlreadyExists,
 					Message: localize(
 						c, "Problem with code {code} already exists.",
 						replaceField("code", problem.Code),
@@ -760,7 +1293,8 @@ func (v *View) createContestProblem(c echo.Context) error {
 			}
 			if problem.ProblemID == row.ProblemID {
 				return errorResponse{
-					Code: http.StatusBadRequest,
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeProblemAlreadyExists,
 					Message: localize(
 						c, "Problem {id} already exists.",
 						replaceField("id", problem.ProblemID),
@@ -801,6 +1335,98 @@ func (v *View) updateContestProblem(c echo.Context) error {
 	return c.JSON(http.StatusOK, v.makeContestProblem(c, problem, false))
 }
 
+type reorderContestProblemsForm struct {
+	// ProblemIDs contains all problems of contest in required order.
+	ProblemIDs []int64 `json:"problem_ids"`
+}
+
+// reorderContestProblems sets explicit positions for all problems of
+// contest.
+func (v *View) reorderContestProblems(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form reorderContestProblemsForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	problemRows, err := v.core.ContestProblems.FindByContest(
+		getContext(c), contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	problems, err := db.CollectRows(problemRows)
+	if err != nil {
+		return err
+	}
+	problemByID := map[int64]models.ContestProblem{}
+	for _, problem := range problems {
+		problemByID[problem.ID] = problem
+	}
+	positions := map[int64]int{}
+	for i, id := range form.ProblemIDs {
+		if _, ok := problemByID[id]; !ok {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeProblemNotFound,
+				Message: localize(
+					c, "Problem {id} does not exists.",
+					replaceField("id", id),
+				),
+			}
+		}
+		if _, ok := positions[id]; ok {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeDuplicateProblem,
+				Message: localize(
+					c, "Problem {id} is specified twice.",
+					replaceField("id", id),
+				),
+			}
+		}
+		positions[id] = i + 1
+	}
+	if len(positions) != len(problems) {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeMissingContestProblems,
+			Message:   localize(c, "All problems of contest should be specified."),
+		}
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		for i, problem := range problems {
+			config, err := problem.GetConfig()
+			if err != nil {
+				return err
+			}
+			config.Position = getPtr(positions[problem.ID])
+			if err := problem.SetConfig(config); err != nil {
+				return err
+			}
+			if err := v.core.ContestProblems.Update(ctx, problem); err != nil {
+				return err
+			}
+			problems[i] = problem
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	models.SortContestProblems(problems)
+	resp := ContestProblems{}
+	for _, problem := range problems {
+		resp.Problems = append(resp.Problems, v.makeContestProblem(c, problem, false))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 func (v *View) deleteContestProblem(c echo.Context) error {
 	problem, ok := c.Get(contestProblemKey).(models.ContestProblem)
 	if !ok {
@@ -824,6 +1450,12 @@ type ContestParticipant struct {
 	ContestID int64                   `json:"contest_id,omitempty"`
 	// Kind contains kind.
 	Kind models.ParticipantKind `json:"kind"`
+	// Labels contains labels of participant like division or school.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country string `json:"country,omitempty"`
+	// Organization contains organization of participant.
+	Organization *Organization `json:"organization,omitempty"`
 }
 
 type ContestParticipants struct {
@@ -857,29 +1489,119 @@ func (v *View) observeContestParticipants(c echo.Context) error {
 type ParticipantKind = models.ParticipantKind
 
 type CreateContestParticipantForm struct {
-	Kind      ParticipantKind `json:"kind"`
-	AccountID int64           `json:"account_id"`
+	Kind      ParticipantKind   `json:"kind"`
+	AccountID int64             `json:"account_id"`
+	Labels    map[string]string `json:"labels,omitempty"`
 }
 
 func (f CreateContestParticipantForm) Update(
 	c echo.Context, o *models.ContestParticipant, core *core.Core,
 ) *errorResponse {
-	ctx := getContext(c)
-	account, err := core.Accounts.Get(ctx, f.AccountID)
+	if errors := validateParticipantLabels(c, f.Labels); len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	account, err := getContestAccount(c, f.AccountID, core)
 	if err != nil {
+		return err
+	}
+	// TODO: Check for observe permissions.
+	if !f.Kind.IsValid() {
+		f.Kind = models.RegularParticipant
+	}
+	o.AccountID = account.ID
+	o.Kind = f.Kind
+	if err := o.SetLabels(f.Labels); err != nil {
 		return &errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidLabels,
+			Message:   localize(c, "Invalid labels."),
+		}
+	}
+	return nil
+}
+
+const (
+	maxParticipantLabels      = 16
+	maxParticipantLabelLength = 64
+)
+
+// validateParticipantLabels validates labels of participant.
+//
+// Label names consist of lowercase latin letters, digits and
+// underscores.
+func validateParticipantLabels(c echo.Context, labels map[string]string) errorFields {
+	errors := errorFields{}
+	if len(labels) > maxParticipantLabels {
+		errors["labels"] = errorField{
 			Message: localize(
-				c, "Account {id} does not exists.",
-				replaceField("id", f.AccountID),
+				c, "Amount of labels should not be greater than {max}.",
+				replaceField("max", maxParticipantLabels),
 			),
 		}
+		return errors
 	}
-	switch account.Kind {
-	case models.UserAccountKind:
-		if _, err := core.Users.Get(ctx, account.ID); err != nil {
-			return &errorResponse{
-				Code: http.StatusBadRequest,
+	for name, value := range labels {
+		if !isValidParticipantLabelName(name) {
+			errors["labels"] = errorField{
+				Message: localize(
+					c, "Label {name} has invalid name.",
+					replaceField("name", name),
+				),
+			}
+			break
+		}
+		if len(value) > maxParticipantLabelLength {
+			errors["labels"] = errorField{
+				Message: localize(
+					c, "Label {name} is too long.",
+					replaceField("name", name),
+				),
+			}
+			break
+		}
+	}
+	return errors
+}
+
+func isValidParticipantLabelName(name string) bool {
+	if len(name) == 0 || len(name) > maxParticipantLabelLength {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// getContestAccount returns account that can be added to contest.
+func getContestAccount(
+	c echo.Context, accountID int64, core *core.Core,
+) (models.Account, *errorResponse) {
+	ctx := getContext(c)
+	account, err := core.Accounts.Get(ctx, accountID)
+	if err != nil {
+		return models.Account{}, &errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeAccountNotFound,
+			Message: localize(
+				c, "Account {id} does not exists.",
+				replaceField("id", accountID),
+			),
+		}
+	}
+	switch account.Kind {
+	case models.UserAccountKind:
+		if _, err := core.Users.Get(ctx, account.ID); err != nil {
+			return models.Account{}, &errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeUserNotFound,
 				Message: localize(
 					c, "User {id} does not exists.",
 					replaceField("id", account.ID),
@@ -889,8 +1611,9 @@ func (f CreateContestParticipantForm) Update(
 	case models.ScopeUserAccountKind:
 		scopeUser, err := core.ScopeUsers.Get(ctx, account.ID)
 		if err != nil {
-			return &errorResponse{
-				Code: http.StatusBadRequest,
+			return models.Account{}, &errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeUserNotFound,
 				Message: localize(
 					c, "User {id} does not exists.",
 					replaceField("id", account.ID),
@@ -898,8 +1621,9 @@ func (f CreateContestParticipantForm) Update(
 			}
 		}
 		if _, err := core.Scopes.Get(ctx, scopeUser.ScopeID); err != nil {
-			return &errorResponse{
-				Code: http.StatusBadRequest,
+			return models.Account{}, &errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeScopeNotFound,
 				Message: localize(
 					c, "Scope {id} does not exists.",
 					replaceField("id", scopeUser.ScopeID),
@@ -908,8 +1632,9 @@ func (f CreateContestParticipantForm) Update(
 		}
 	case models.ScopeAccountKind:
 		if _, err := core.Scopes.Get(ctx, account.ID); err != nil {
-			return &errorResponse{
-				Code: http.StatusBadRequest,
+			return models.Account{}, &errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeScopeNotFound,
 				Message: localize(
 					c, "Scope {id} does not exists.",
 					replaceField("id", account.ID),
@@ -918,8 +1643,9 @@ func (f CreateContestParticipantForm) Update(
 		}
 	case models.GroupAccountKind:
 		if _, err := core.Groups.Get(ctx, account.ID); err != nil {
-			return &errorResponse{
-				Code: http.StatusBadRequest,
+			return models.Account{}, &errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeGroupNotFound,
 				Message: localize(
 					c, "Group {id} does not exists.",
 					replaceField("id", account.ID),
@@ -932,21 +1658,16 @@ func (f CreateContestParticipantForm) Update(
 			logs.Any("id", account.ID),
 			logs.Any("kind", account.Kind),
 		)
-		return &errorResponse{
-			Code: http.StatusBadRequest,
+		return models.Account{}, &errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeAccountNotFound,
 			Message: localize(
 				c, "Account {id} does not exists.",
-				replaceField("id", f.AccountID),
+				replaceField("id", accountID),
 			),
 		}
 	}
-	// TODO: Check for observe permissions.
-	if !f.Kind.IsValid() {
-		f.Kind = models.RegularParticipant
-	}
-	o.AccountID = account.ID
-	o.Kind = f.Kind
-	return nil
+	return account, nil
 }
 
 func (v *View) createContestParticipant(c echo.Context) error {
@@ -977,7 +1698,8 @@ func (v *View) createContestParticipant(c echo.Context) error {
 			row := rows.Row()
 			if row.Kind == participant.Kind {
 				return errorResponse{
-					Code: http.StatusBadRequest,
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeParticipantAlreadyExists,
 					Message: localize(
 						c, "Participant with {kind} kind already exists.",
 						replaceField("kind", row.Kind),
@@ -1000,6 +1722,189 @@ func (v *View) createContestParticipant(c echo.Context) error {
 	)
 }
 
+// CreateContestGroupParticipantsForm represents form for registration
+// of all group members as contest participants.
+type CreateContestGroupParticipantsForm struct {
+	Kind ParticipantKind `json:"kind,omitempty"`
+}
+
+// createContestGroupParticipants registers every member of group as
+// separate contest participant.
+//
+// Members that already have participant with the same kind are skipped.
+func (v *View) createContestGroupParticipants(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	contest := contestCtx.Contest
+	groupID, err := strconv.ParseInt(c.Param("group"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidGroupID,
+			Message:   localize(c, "Invalid group ID."),
+		}
+	}
+	var form CreateContestGroupParticipantsForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if !form.Kind.IsValid() {
+		form.Kind = models.RegularParticipant
+	}
+	if err := syncStore(c, v.core.Groups); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.GroupMembers); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestParticipants); err != nil {
+		return err
+	}
+	ctx := getContext(c)
+	group, err := v.core.Groups.Get(ctx, groupID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeGroupNotFound,
+				Message:   localize(c, "Group not found."),
+			}
+		}
+		return err
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	permissions := v.getGroupPermissions(accountCtx, group)
+	if !permissions.HasPermission(perms.ObserveGroupMembersRole) {
+		return errorResponse{
+			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{perms.ObserveGroupMembersRole},
+		}
+	}
+	members, err := v.core.GroupMembers.FindByGroup(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	groupMembers, err := db.CollectRows(members)
+	if err != nil {
+		return err
+	}
+	var participants []models.ContestParticipant
+	for _, member := range groupMembers {
+		exists, err := func() (bool, error) {
+			rows, err := v.core.ContestParticipants.FindByContestAccount(
+				ctx, contest.ID, member.AccountID,
+			)
+			if err != nil {
+				return false, err
+			}
+			defer func() { _ = rows.Close() }()
+			for rows.Next() {
+				if rows.Row().Kind == form.Kind {
+					return true, nil
+				}
+			}
+			return false, rows.Err()
+		}()
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		participants = append(participants, models.ContestParticipant{
+			ContestID: contest.ID,
+			AccountID: member.AccountID,
+			Kind:      form.Kind,
+		})
+	}
+	if err := v.core.WrapTx(ctx, func(ctx context.Context) error {
+		for i := range participants {
+			if err := v.core.ContestParticipants.Create(
+				ctx, &participants[i],
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	resp := ContestParticipants{}
+	for _, participant := range participants {
+		resp.Participants = append(
+			resp.Participants,
+			makeContestParticipant(c, participant, v.core),
+		)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateContestParticipantForm represents form for updating participant.
+type UpdateContestParticipantForm struct {
+	Labels *map[string]string `json:"labels"`
+}
+
+func (f UpdateContestParticipantForm) Update(
+	c echo.Context, o *models.ContestParticipant,
+) error {
+	if f.Labels == nil {
+		return nil
+	}
+	if errors := validateParticipantLabels(c, *f.Labels); len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	if err := o.SetLabels(*f.Labels); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidLabels,
+			Message:   localize(c, "Invalid labels."),
+		}
+	}
+	return nil
+}
+
+func (v *View) updateContestParticipant(c echo.Context) error {
+	participant, ok := c.Get(contestParticipantKey).(models.ContestParticipant)
+	if !ok {
+		return fmt.Errorf("contest participant not extracted")
+	}
+	var form UpdateContestParticipantForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Update(c, &participant); err != nil {
+		return err
+	}
+	if err := v.core.ContestParticipants.Update(
+		getContext(c), participant,
+	); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusOK,
+		makeContestParticipant(c, participant, v.core),
+	)
+}
+
 func (v *View) deleteContestParticipant(c echo.Context) error {
 	participant, ok := c.Get(contestParticipantKey).(models.ContestParticipant)
 	if !ok {
@@ -1018,7 +1923,7 @@ func (v *View) deleteContestParticipant(c echo.Context) error {
 
 type registerContestForm struct {
 	Kind      *ParticipantKind `json:"kind"`
-	BeginTime *int64           `json:"begin"_time"`
+	BeginTime *int64           `json:"begin_time"`
 }
 
 func (f *registerContestForm) Update(c echo.Context, o *models.ContestParticipant) error {
@@ -1055,6 +1960,7 @@ func (f *registerContestForm) Update(c echo.Context, o *models.ContestParticipan
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -1095,15 +2001,21 @@ func (v *View) registerContest(c echo.Context) error {
 		if !contestCtx.HasPermission(perms.RegisterContestVirtualRole) {
 			missingPermissions = append(missingPermissions, perms.RegisterContestVirtualRole)
 		}
+	case models.ObserverParticipant:
+		if !contestCtx.HasPermission(perms.RegisterContestObserverRole) {
+			missingPermissions = append(missingPermissions, perms.RegisterContestObserverRole)
+		}
 	default:
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 		}
 	}
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
@@ -1111,7 +2023,8 @@ func (v *View) registerContest(c echo.Context) error {
 	for _, p := range contestCtx.Participants {
 		if p.ID != 0 && p.Kind == participant.Kind {
 			return errorResponse{
-				Code: http.StatusBadRequest,
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeParticipantAlreadyExists,
 				Message: localize(
 					c, "Participant with {kind} kind already exists.",
 					replaceField("kind", p.Kind),
@@ -1147,8 +2060,9 @@ type contestSolutionsFilter struct {
 func (f *contestSolutionsFilter) Parse(c echo.Context) error {
 	if err := c.Bind(f); err != nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid filter."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
 		}
 	}
 	if f.BeginID < 0 || f.BeginID == math.MaxInt64 {
@@ -1253,6 +2167,19 @@ func (v *View) observeContestSolution(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+func (v *View) observeContestSolutionTestArtifact(c echo.Context) error {
+	contestSolution, ok := c.Get(contestSolutionKey).(models.ContestSolution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	solution, err := v.core.Solutions.Get(getContext(c), contestSolution.ID)
+	if err != nil {
+		return err
+	}
+	c.Set(solutionKey, solution)
+	return v.observeSolutionTestArtifact(c)
+}
+
 func (v *View) rejudgeContestSolution(c echo.Context) error {
 	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
 	if !ok {
@@ -1275,8 +2202,9 @@ func (v *View) rejudgeContestSolution(c echo.Context) error {
 		}
 		task := models.Task{}
 		if err := task.SetConfig(models.JudgeSolutionTaskConfig{
-			SolutionID:   solution.ID,
-			EnablePoints: getEnablePoints(contestCtx),
+			SolutionID:    solution.ID,
+			EnablePoints:  getEnablePoints(contestCtx),
+			ParticipantID: contestSolution.ParticipantID,
 		}); err != nil {
 			return err
 		}
@@ -1288,6 +2216,7 @@ func (v *View) rejudgeContestSolution(c echo.Context) error {
 	resp.Solution.Report = &SolutionReport{
 		Verdict: models.QueuedTask.String(),
 	}
+	v.setReportVerdict(c, resp.Solution.Report)
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -1297,11 +2226,18 @@ type ContestSolution struct {
 	Solution    Solution            `json:"solution"`
 	Problem     *ContestProblem     `json:"problem,omitempty"`
 	Participant *ContestParticipant `json:"participant,omitempty"`
+	// ContestTime contains amount of seconds since participant contest
+	// begin till submission.
+	ContestTime *int64 `json:"contest_time,omitempty"`
 }
 
 type SubmitSolutionForm struct {
 	CompilerID int64   `form:"compiler_id" json:"compiler_id"`
 	Content    *string `form:"content" json:"content,omitempty"`
+	// URL contains address of solution file that is fetched by server.
+	URL *string `form:"url" json:"url,omitempty"`
+	// Async means that solution can be created in background.
+	Async bool `form:"async" json:"async,omitempty"`
 	// ContentFile will be initialized with the content if it is provided.
 	ContentFile *FileReader `json:"-"`
 }
@@ -1318,7 +2254,7 @@ func (f *SubmitSolutionForm) Parse(c echo.Context) error {
 			Size:   int64(content.Len()),
 		}
 		f.ContentFile = &file
-	} else {
+	} else if f.URL == nil {
 		formFile, err := c.FormFile("file")
 		if err != nil {
 			return err
@@ -1332,6 +2268,14 @@ func (f *SubmitSolutionForm) Parse(c echo.Context) error {
 	return nil
 }
 
+// getSolutionsQuota returns window in seconds and amount of solutions
+// allowed in window.
+func (v *View) getSolutionsQuota(logger echo.Logger) (int64, int64) {
+	window := v.getInt64Setting("contests.solutions_quota.window", logger).OrElse(60)
+	amount := v.getInt64Setting("contests.solutions_quota.amount", logger).OrElse(3)
+	return window, amount
+}
+
 func (v *View) hasSolutionsQuota(
 	contestCtx *managers.ContestContext,
 	participant models.ContestParticipant,
@@ -1346,8 +2290,7 @@ func (v *View) hasSolutionsQuota(
 		return false
 	}
 	defer func() { _ = solutions.Close() }()
-	window := v.getInt64Setting("contests.solutions_quota.window", logger).OrElse(60)
-	amount := v.getInt64Setting("contests.solutions_quota.amount", logger).OrElse(3)
+	window, amount := v.getSolutionsQuota(logger)
 	toTime := contestCtx.Now
 	fromTime := toTime.Add(-time.Second * time.Duration(window))
 	for solutions.Next() {
@@ -1372,6 +2315,94 @@ func (v *View) hasSolutionsQuota(
 	return true
 }
 
+// checkProblemSolutionsLimit checks that participant does not exceed
+// limits of solutions for contest problem.
+func (v *View) checkProblemSolutionsLimit(
+	c echo.Context,
+	contestCtx *managers.ContestContext,
+	participant models.ContestParticipant,
+	problem models.ContestProblem,
+) error {
+	if participant.Kind == models.ManagerParticipant {
+		return nil
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.MaxSolutions == nil && config.SolutionInterval == nil {
+		return nil
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	solutions, err := v.core.ContestSolutions.FindByParticipant(
+		contestCtx, participant.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = solutions.Close() }()
+	count := 0
+	var lastTime int64
+	for solutions.Next() {
+		contestSolution := solutions.Row()
+		if contestSolution.ProblemID != problem.ID {
+			continue
+		}
+		count++
+		solution, err := v.core.Solutions.Get(contestCtx, contestSolution.ID)
+		if err != nil {
+			c.Logger().Warn("Cannot find solution", logs.Any("id", contestSolution.ID))
+			continue
+		}
+		if solution.CreateTime > lastTime {
+			lastTime = solution.CreateTime
+		}
+	}
+	if err := solutions.Err(); err != nil {
+		return err
+	}
+	if config.MaxSolutions != nil && count >= *config.MaxSolutions {
+		return errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeSolutionsLimitReached,
+			Message: localize(
+				c, "Limit of {limit} solutions for problem is reached.",
+				replaceField("limit", *config.MaxSolutions),
+			),
+		}
+	}
+	if config.SolutionInterval != nil && lastTime != 0 {
+		nextTime := lastTime + int64(*config.SolutionInterval)
+		if now := contestCtx.Now.Unix(); now < nextTime {
+			if config.MaxSolutions != nil {
+				return errorResponse{
+					Code:      http.StatusTooManyRequests,
+					ErrorCode: CodeQuotaExceeded,
+					Message: localize(
+						c, "Next solution can be submitted in {seconds} seconds, {remaining} attempts remaining.",
+						replaceField("seconds", nextTime-now),
+						replaceField("remaining", *config.MaxSolutions-count),
+					),
+				}
+			}
+			return errorResponse{
+				Code:      http.StatusTooManyRequests,
+				ErrorCode: CodeQuotaExceeded,
+				Message: localize(
+					c, "Next solution can be submitted in {seconds} seconds.",
+					replaceField("seconds", nextTime-now),
+				),
+			}
+		}
+	}
+	return nil
+}
+
 func (v *View) submitContestProblemSolution(c echo.Context) error {
 	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
 	if !ok {
@@ -1389,13 +2420,15 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 	participant := contestCtx.GetEffectiveParticipant()
 	if participant == nil {
 		return errorResponse{
-			Code:    http.StatusForbidden,
-			Message: localize(c, "Participant not found."),
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeParticipantNotFound,
+			Message:   localize(c, "Participant not found."),
 		}
 	}
 	if !contestCtx.HasEffectivePermission(perms.SubmitContestSolutionRole) {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: []string{perms.SubmitContestSolutionRole},
 		}
@@ -1439,41 +2472,80 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 	}
 	if !v.hasSolutionsQuota(contestCtx, *participant, c.Logger()) {
 		return errorResponse{
-			Code:    http.StatusTooManyRequests,
-			Message: localize(c, "Too many requests."),
+			Code:      http.StatusTooManyRequests,
+			ErrorCode: CodeTooManyRequests,
+			Message:   localize(c, "Too many requests."),
 		}
 	}
+	if err := v.checkProblemSolutionsLimit(
+		c, contestCtx, *participant, problem,
+	); err != nil {
+		return err
+	}
 	var form SubmitSolutionForm
 	if err := form.Parse(c); err != nil {
 		return err
 	}
+	if form.ContentFile == nil && form.URL != nil {
+		file, err := v.fetchSolutionURL(c, *form.URL)
+		if err != nil {
+			return err
+		}
+		form.ContentFile = file
+	}
 	defer func() { _ = form.ContentFile.Close() }()
 	if form.ContentFile.Size <= 0 {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "File is empty."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeFileEmpty,
+			Message:   localize(c, "File is empty."),
 		}
 	}
-	if form.ContentFile.Size >= 256*1024 {
+	if form.ContentFile.Size > maxSolutionFileSize {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "File is too large."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeFileTooLarge,
+			Message:   localize(c, "File is too large."),
 		}
 	}
-	if _, err := v.core.Compilers.Get(getContext(c), form.CompilerID); err != nil {
-		if err == sql.ErrNoRows {
-			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Compiler not found."),
+	var compiler models.Compiler
+	var err error
+	if form.CompilerID == 0 {
+		if err := syncStore(c, v.core.Compilers); err != nil {
+			return err
+		}
+		compiler, err = v.detectSolutionCompiler(c, contestCtx, form.ContentFile)
+		if err != nil {
+			return err
+		}
+	} else {
+		compiler, err = v.core.Compilers.Get(getContext(c), form.CompilerID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeCompilerNotFound,
+					Message:   localize(c, "Compiler not found."),
+				}
 			}
+			return err
+		}
+	}
+	if !contestCtx.ContestConfig.IsCompilerAllowed(compiler.ID) {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeCompilerNotAllowed,
+			Message:   localize(c, "Compiler is not allowed in contest."),
 		}
+	}
+	if err := normalizeSolutionContent(c, compiler, form.ContentFile); err != nil {
 		return err
 	}
 	solution := models.Solution{
 		Kind:       models.ContestSolutionKind,
 		ProblemID:  problem.ProblemID,
 		AuthorID:   account.ID,
-		CompilerID: form.CompilerID,
+		CompilerID: compiler.ID,
 		CreateTime: contestCtx.Now.Unix(),
 	}
 	contestSolution := models.ContestSolution{
@@ -1485,29 +2557,16 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
-		if err := v.files.ConfirmUploadFile(ctx, &file); err != nil {
-			return err
-		}
-		solution.ContentID = models.NInt64(file.ID)
-		if err := v.core.Solutions.Create(ctx, &solution); err != nil {
-			return err
-		}
-		contestSolution.ID = solution.ID
-		if err := v.core.ContestSolutions.Create(
-			ctx, &contestSolution,
-		); err != nil {
-			return err
-		}
-		task := models.Task{}
-		if err := task.SetConfig(models.JudgeSolutionTaskConfig{
-			SolutionID:   solution.ID,
-			EnablePoints: getEnablePoints(contestCtx),
-		}); err != nil {
-			return err
-		}
-		return v.core.Tasks.Create(ctx, &task)
-	}, sqlRepeatableRead); err != nil {
+	if v.isAsyncSubmitEnabled(c, form) {
+		return v.enqueueContestSolution(
+			c, account, file, solution, contestSolution,
+			getEnablePoints(contestCtx),
+		)
+	}
+	if err := v.createContestSolution(
+		getContext(c), &file, &solution, &contestSolution,
+		getEnablePoints(contestCtx),
+	); err != nil {
 		return err
 	}
 	return c.JSON(
@@ -1516,6 +2575,44 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 	)
 }
 
+// normalizeSolutionContent applies normalization of compiler to
+// solution content.
+func normalizeSolutionContent(
+	c echo.Context, compiler models.Compiler, file *FileReader,
+) error {
+	config, err := compiler.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.Normalize == nil {
+		return nil
+	}
+	content, err := io.ReadAll(file.Reader)
+	if err != nil {
+		return err
+	}
+	normalized, err := config.Normalize.Apply(content)
+	if err != nil {
+		if err == models.ErrInvalidUTF8 {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
+				InvalidFields: errorFields{
+					"file": errorField{
+						Message: localize(c, "File is not valid UTF-8 text."),
+					},
+				},
+			}
+		}
+		return err
+	}
+	_ = file.Close()
+	file.Reader = bytes.NewReader(normalized)
+	file.Size = int64(len(normalized))
+	return nil
+}
+
 func getEnablePoints(ctx *managers.ContestContext) bool {
 	return ctx.ContestConfig.StandingsKind == models.IOIStandings
 }
@@ -1527,25 +2624,38 @@ func (v *View) makeContestSolution(
 		ID:        solution.ID,
 		ContestID: solution.ContestID,
 	}
+	var report *models.SolutionReport
+	var createTime int64
 	if baseSolution, err := v.core.Solutions.Get(
 		getContext(c), solution.ID,
 	); err == nil {
+		createTime = baseSolution.CreateTime
 		resp.Solution = v.makeSolution(c, baseSolution, withLogs)
 		resp.Solution.Problem = nil
 		resp.Solution.User = nil
 		resp.Solution.ScopeUser = nil
+		report, _ = baseSolution.GetReport()
 	}
 	if problem, err := v.core.ContestProblems.Get(
 		getContext(c), solution.ProblemID,
 	); err == nil {
 		problemResp := v.makeContestProblem(c, problem, false)
 		resp.Problem = &problemResp
+		if resp.Solution.Report != nil {
+			v.applyFeedbackPolicy(c, resp.Solution.Report, report, problem, withLogs)
+		}
 	}
 	if participant, err := v.core.ContestParticipants.Get(
 		getContext(c), solution.ParticipantID,
 	); err == nil {
 		participantResp := makeContestParticipant(c, participant, v.core)
 		resp.Participant = &participantResp
+		if contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext); ok && createTime != 0 {
+			beginTime := contestCtx.GetParticipantBeginTime(&participant)
+			if beginTime != 0 && createTime >= beginTime {
+				resp.ContestTime = getPtr(createTime - beginTime)
+			}
+		}
 	}
 	return resp
 }
@@ -1561,6 +2671,9 @@ func makeContestParticipant(
 		ContestID: participant.ContestID,
 		Kind:      participant.Kind,
 	}
+	if labels, err := participant.GetLabels(); err == nil && len(labels) > 0 {
+		resp.Labels = labels
+	}
 	if account, err := core.Accounts.Get(
 		ctx, participant.AccountID,
 	); err == nil {
@@ -1571,6 +2684,9 @@ func makeContestParticipant(
 					ID:    user.ID,
 					Login: user.Login,
 				}
+				setParticipantOrganization(
+					c, &resp, string(user.Country), int64(user.OrganizationID), core,
+				)
 			}
 		case models.ScopeUserAccountKind:
 			if user, err := core.ScopeUsers.Get(ctx, account.ID); err == nil {
@@ -1579,6 +2695,9 @@ func makeContestParticipant(
 					Login: user.Login,
 					Title: string(user.Title),
 				}
+				setParticipantOrganization(
+					c, &resp, string(user.Country), int64(user.OrganizationID), core,
+				)
 			}
 		case models.ScopeAccountKind:
 			if scope, err := core.Scopes.Get(ctx, account.ID); err == nil {
@@ -1599,14 +2718,37 @@ func makeContestParticipant(
 	return resp
 }
 
+// setParticipantOrganization sets country and organization of participant.
+//
+// Country of organization is used if country is not specified.
+func setParticipantOrganization(
+	c echo.Context, resp *ContestParticipant,
+	country string, organizationID int64, core *core.Core,
+) {
+	resp.Country = country
+	if organizationID == 0 || core.Organizations == nil {
+		return
+	}
+	organization, err := core.Organizations.Get(getContext(c), organizationID)
+	if err != nil {
+		return
+	}
+	organizationResp := makeOrganization(organization)
+	resp.Organization = &organizationResp
+	if resp.Country == "" {
+		resp.Country = organizationResp.Country
+	}
+}
+
 func (v *View) extractContest(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		id, err := strconv.ParseInt(c.Param("contest"), 10, 64)
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid contest ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidContestID,
+				Message:   localize(c, "Invalid contest ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Contests); err != nil {
@@ -1616,8 +2758,9 @@ func (v *View) extractContest(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Contest not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeContestNotFound,
+					Message:   localize(c, "Contest not found."),
 				}
 			}
 			return err
@@ -1632,6 +2775,7 @@ func (v *View) extractContest(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 		c.Set(contestCtxKey, contestCtx)
 		c.Set(permissionCtxKey, contestCtx)
+		v.trackContestAddress(c, contestCtx)
 		return next(c)
 	}
 }
@@ -1641,8 +2785,9 @@ func (v *View) extractContestProblem(next echo.HandlerFunc) echo.HandlerFunc {
 		code := c.Param("problem")
 		if len(code) == 0 {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "Empty problem code."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeProblemNotFound,
+				Message:   localize(c, "Empty problem code."),
 			}
 		}
 		contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
@@ -1674,7 +2819,8 @@ func (v *View) extractContestProblem(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 		if contestProblem == nil {
 			return errorResponse{
-				Code: http.StatusNotFound,
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeProblemNotFound,
 				Message: localize(
 					c, "Problem {code} does not exists.",
 					replaceField("code", code),
@@ -1714,8 +2860,9 @@ func (v *View) extractContestParticipant(
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid participant ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidParticipantID,
+				Message:   localize(c, "Invalid participant ID."),
 			}
 		}
 		if err := syncStore(c, v.core.ContestParticipants); err != nil {
@@ -1725,8 +2872,9 @@ func (v *View) extractContestParticipant(
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Participant not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeParticipantNotFound,
+					Message:   localize(c, "Participant not found."),
 				}
 			}
 			return err
@@ -1737,8 +2885,9 @@ func (v *View) extractContestParticipant(
 		}
 		if contestCtx.Contest.ID != participant.ContestID {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "Participant not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeParticipantNotFound,
+				Message:   localize(c, "Participant not found."),
 			}
 		}
 		c.Set(contestParticipantKey, participant)
@@ -1752,8 +2901,9 @@ func (v *View) extractContestSolution(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid solution ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidSolutionID,
+				Message:   localize(c, "Invalid solution ID."),
 			}
 		}
 		if err := syncStore(c, v.core.ContestSolutions); err != nil {
@@ -1766,8 +2916,9 @@ func (v *View) extractContestSolution(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Solution not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeSolutionNotFound,
+					Message:   localize(c, "Solution not found."),
 				}
 			}
 			return err
@@ -1778,8 +2929,9 @@ func (v *View) extractContestSolution(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 		if contestCtx.Contest.ID != solution.ContestID {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "Solution not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeSolutionNotFound,
+				Message:   localize(c, "Solution not found."),
 			}
 		}
 		c.Set(contestSolutionKey, solution)
@@ -1808,10 +2960,3 @@ func (v *View) getContestSolutionPermissions(
 	}
 	return permissions
 }
-
-func contestProblemLess(l, r ContestProblem) bool {
-	if l.ContestID == r.ContestID {
-		return l.Code < r.Code
-	}
-	return l.ID < r.ID
-}
@@ -1,13 +1,22 @@
 package api
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
 )
 
 var testSimpleContest = createContestForm{
@@ -91,6 +100,13 @@ func TestContestSimpleScenario(t *testing.T) {
 		}
 		e.Check(contestProblem)
 	}
+	{
+		statistics, err := e.Client.ObserveContestStatistics(context.Background(), contest.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		e.Check(statistics)
+	}
 }
 
 func TestContestParticipation(t *testing.T) {
@@ -173,143 +189,3683 @@ func TestContestParticipation(t *testing.T) {
 	}()
 }
 
-func TestContestStandings(t *testing.T) {
-	e := NewTestEnv(t, WithInvoker{})
+func TestContestFeedback(t *testing.T) {
+	e := NewTestEnv(t)
 	defer e.Close()
 	owner := NewTestUser(e)
-	owner.AddRoles("create_contest", "create_compiler", "create_problem", "create_setting")
-	user1 := NewTestUser(e)
-	user2 := NewTestUser(e)
-	user3 := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user := NewTestUser(e)
 	owner.LoginClient()
-	compiler := NewTestCompiler(e)
-	problem := NewTestProblem(e)
-	interactiveProblem := NewTestInteractiveProblem(e)
 	contestForm := createContestForm{
-		Title:              getPtr("Test contest"),
-		BeginTime:          getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
-		Duration:           getPtr(7200),
-		EnableRegistration: getPtr(true),
-		EnableUpsolving:    getPtr(true),
-		StandingsKind:      getPtr(models.ICPCStandings),
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:  getPtr(7200),
+		Feedback: &models.ContestFeedbackConfig{
+			Questions: []models.ContestFeedbackQuestion{
+				{Name: "rating", Title: "Rate contest", Kind: models.RatingFeedbackQuestion, Required: true},
+				{Name: "difficulty", Title: "Difficulty", Kind: models.ChoiceFeedbackQuestion, Options: []string{"easy", "hard"}},
+				{Name: "comment", Title: "Comment", Kind: models.TextFeedbackQuestion},
+			},
+		},
 	}
 	contest, err := e.Client.CreateContest(contestForm)
 	if err != nil {
 		t.Fatal("Error:", err)
 	}
-	{
-		problemForm := createContestProblemForm{}
-		problemForm.Code = getPtr("A")
-		problemForm.ProblemID = getPtr(problem.ID)
-		if _, err := e.Client.CreateContestProblem(contest.ID, problemForm); err != nil {
-			t.Fatal("Error", err)
-		}
-	}
-	{
-		problemForm := createContestProblemForm{}
-		problemForm.Code = getPtr("B")
-		problemForm.ProblemID = getPtr(interactiveProblem.ID)
-		if _, err := e.Client.CreateContestProblem(contest.ID, problemForm); err != nil {
-			t.Fatal("Error", err)
-		}
-	}
+	e.Check(contest)
 	{
 		form := CreateContestParticipantForm{
-			AccountID: user1.ID,
+			AccountID: user.ID,
 			Kind:      models.RegularParticipant,
 		}
 		if _, err := e.Client.CreateContestParticipant(context.Background(), contest.ID, form); err != nil {
-			t.Fatal("Error", err)
+			t.Fatal("Error:", err)
 		}
 	}
+	owner.LogoutClient()
+	e.SyncStores()
+	now := e.Now
+	user.LoginClient()
+	e.Now = now.Add(2 * time.Hour)
+	form := SubmitContestFeedbackForm{
+		Answers: models.ContestFeedbackAnswers{"rating": "5", "comment": "Nice"},
+	}
+	if _, err := e.Client.SubmitContestFeedback(context.Background(), contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	e.Now = now.Add(3*time.Hour + time.Second)
 	{
-		form := CreateContestParticipantForm{
-			AccountID: user2.ID,
-			Kind:      models.RegularParticipant,
+		invalidForm := SubmitContestFeedbackForm{
+			Answers: models.ContestFeedbackAnswers{"rating": "6", "unknown": "value"},
 		}
-		if _, err := e.Client.CreateContestParticipant(context.Background(), contest.ID, form); err != nil {
-			t.Fatal("Error", err)
+		if _, err := e.Client.SubmitContestFeedback(context.Background(), contest.ID, invalidForm); err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+		}
+	}
+	if feedback, err := e.Client.SubmitContestFeedback(context.Background(), contest.ID, form); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(feedback)
+	}
+	form.Answers["difficulty"] = "hard"
+	if _, err := e.Client.SubmitContestFeedback(context.Background(), contest.ID, form); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if feedback, err := e.Client.ObserveContestFeedback(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(feedback)
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	if summary, err := e.Client.ObserveContestFeedbackSummary(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(summary)
+	}
+	owner.LogoutClient()
+}
+
+func TestContestFinalization(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contestForm := createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		Awards:        &models.ContestAwardsConfig{Gold: 1, Silver: 1, Bronze: 1},
+		StandingsKind: getPtr(models.ICPCStandings),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(context.Background(), &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	for i := 0; i < 4; i++ {
+		participant := models.ContestFakeParticipant{
+			ContestID: contest.ID,
+			Title:     fmt.Sprintf("Fake participant %d", i+1),
+		}
+		if err := e.Core.ContestFakeParticipants.Create(context.Background(), &participant); err != nil {
+			t.Fatal("Error:", err)
+		}
+		solution := models.ContestFakeSolution{
+			ContestID:     contest.ID,
+			ParticipantID: participant.ID,
+			ProblemID:     contestProblem.ID,
+			ContestTime:   int64(60 * (i + 1)),
+		}
+		verdict := models.Accepted
+		if i == 3 {
+			verdict = models.WrongAnswer
+		}
+		if err := solution.SetReport(&models.FakeSolutionReport{Verdict: verdict}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.ContestFakeSolutions.Create(context.Background(), &solution); err != nil {
+			t.Fatal("Error:", err)
 		}
 	}
 	{
 		form := CreateContestParticipantForm{
-			AccountID: user3.ID,
+			AccountID: user.ID,
 			Kind:      models.RegularParticipant,
 		}
 		if _, err := e.Client.CreateContestParticipant(context.Background(), contest.ID, form); err != nil {
-			t.Fatal("Error", err)
+			t.Fatal("Error:", err)
+		}
+	}
+	e.SyncStores()
+	if _, err := e.Client.FinalizeContest(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	e.Now = e.Now.Add(3*time.Hour + time.Second)
+	if results, err := e.Client.FinalizeContest(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(results)
+	}
+	if results, err := e.Client.ObserveContestResults(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(results)
+	}
+	if contest, err := e.Client.ObserveContest(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(contest)
+	}
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("B"),
+		ProblemID: getPtr(problem.ID),
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	if _, err := e.Client.UnfinalizeContest(context.Background(), contest.ID, UnfinalizeContestForm{}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form := UnfinalizeContestForm{Reason: "Rejudge solutions"}
+	if _, err := e.Client.UnfinalizeContest(context.Background(), contest.ID, form); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if unfinalizations, err := e.Core.ContestUnfinalizations.FindByContest(
+		context.Background(), contest.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(unfinalizations) != 1 {
+		t.Fatalf("Expected 1 unfinalization, got %d", len(unfinalizations))
+	} else if u := unfinalizations[0]; u.AuthorID != NInt64(owner.ID) ||
+		u.Reason != form.Reason || u.Time != e.Now.Unix() {
+		t.Fatalf("Unexpected unfinalization: %+v", u)
+	}
+	if _, err := e.Client.ObserveContestResults(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+}
+
+func TestContestGuestStandings(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contestForm := createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.ICPCStandings),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant := models.ContestFakeParticipant{
+		ContestID: contest.ID,
+		Title:     "Fake participant",
+	}
+	if err := e.Core.ContestFakeParticipants.Create(context.Background(), &participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	standings, etag, err := e.Client.ObserveGuestContestStandings(context.Background(), contest.ID, "")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if etag == "" {
+		t.Fatal("Expected ETag")
+	}
+	e.Check(standings)
+	if standings, newETag, err := e.Client.ObserveGuestContestStandings(
+		context.Background(), contest.ID, etag,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if standings != nil {
+		t.Fatal("Expected not modified standings")
+	} else if newETag != etag {
+		t.Fatalf("Expected ETag %q, got %q", etag, newETag)
+	}
+}
+
+func TestContestParticipantStandings(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	user3 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.ICPCStandings),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, user := range []*TestUser{user1, user2, user3} {
+		if _, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
 		}
 	}
 	owner.LogoutClient()
 	e.SyncStores()
 	now := e.Now
-	{
-		user1.LoginClient()
-		e.Now = now.Add(time.Hour).Add(time.Minute * 5)
-		form := SubmitSolutionForm{
-			CompilerID: compiler.ID,
-			Content:    getPtr("CompilationError"),
-		}
-		solution, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", form)
+	submit := func(user *TestUser, delay time.Duration, verdict models.Verdict) {
+		e.Now = now.Add(delay)
+		submitJudgedContestSolution(e, user, contest.ID, compiler.ID, verdict)
+	}
+	submit(user1, time.Hour+5*time.Minute, models.Rejected)
+	submit(user2, time.Hour+10*time.Minute, models.Accepted)
+	submit(user1, time.Hour+20*time.Minute, models.Accepted)
+	e.Now = now.Add(time.Hour + 30*time.Minute)
+	for _, test := range []struct {
+		User  *TestUser
+		Place int
+		Cells int
+	}{
+		{User: user1, Place: 2, Cells: 1},
+		{User: user2, Place: 1, Cells: 1},
+		{User: user3, Place: 0, Cells: 0},
+	} {
+		test.User.LoginClient()
+		standings, err := e.Client.ObserveParticipantContestStandings(context.Background(), contest.ID)
+		test.User.LogoutClient()
 		if err != nil {
-			t.Fatal("Error", err)
-		}
-		// TODO: Fix submit solution handler.
-		if err := e.Core.ContestSolutions.Sync(context.Background()); err != nil {
-			t.Fatal("Error", err)
+			t.Fatal("Error:", err)
 		}
-		solutionModel, err := e.Core.ContestSolutions.Get(context.Background(), solution.ID)
-		if err != nil {
-			t.Fatal("Error", err)
+		if len(standings.Rows) != 1 {
+			t.Fatalf("Expected single row, got %d", len(standings.Rows))
 		}
-		e.WaitSolutionJudged(solutionModel.ID)
-		solution, err = e.Client.ObserveContestSolution(context.Background(), contest.ID, solution.ID)
-		if err != nil {
-			t.Fatal("Error", err)
+		row := standings.Rows[0]
+		if row.Participant.User == nil || row.Participant.User.ID != test.User.ID {
+			t.Fatal("Invalid participant:", row.Participant)
 		}
-		if v := solution.Solution.Report.Verdict; v != models.CompilationError.String() {
-			t.Fatalf("Expected: %q, got: %q", models.CompilationError, solution.Solution.Report.Verdict)
+		if row.Place != test.Place {
+			t.Fatalf("Expected place %d, got %d", test.Place, row.Place)
 		}
-		if standings, err := e.Client.ObserveContestStandings(context.Background(), contest.ID); err != nil {
-			t.Fatal("Error:", err)
-		} else {
-			if v := standings.Stage; v != "started" {
-				t.Errorf("Expected: %q, got: %q", "started", v)
-			}
-			e.Check(standings)
+		if len(row.Cells) != test.Cells {
+			t.Fatalf("Expected %d cells, got %d", test.Cells, len(row.Cells))
 		}
-		user1.LogoutClient()
+	}
+	user4 := NewTestUser(e)
+	user4.LoginClient()
+	defer user4.LogoutClient()
+	if _, err := e.Client.ObserveParticipantContestStandings(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else if resp.StatusCode() != http.StatusForbidden {
+		t.Fatalf("Expected %d, got %d", http.StatusForbidden, resp.StatusCode())
 	}
 }
 
-func BenchmarkContests(b *testing.B) {
-	e := NewTestEnv(b)
-	defer e.Close()
-	user := NewTestUser(e)
-	user.AddRoles("observe_contest", "create_contest", "update_contest", "delete_contest")
+// submitJudgedContestSolution submits solution of problem "A" and sets
+// its verdict without judging.
+func submitJudgedContestSolution(
+	e *TestEnv, user *TestUser, contestID, compilerID int64, verdict models.Verdict,
+) int64 {
 	user.LoginClient()
-	b.ResetTimer()
-	var ids []int64
-	for i := 0; i < b.N; i++ {
-		form := createContestForm{
-			Title: getPtr(fmt.Sprintf("Contest %d", i+1)),
+	defer user.LogoutClient()
+	solution, err := e.Client.SubmitContestSolution(context.Background(), contestID, "A", SubmitSolutionForm{
+		CompilerID: compilerID,
+		Content:    getPtr("int main() { return 0; }"),
+	})
+	if err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	solutionModel, err := e.Core.Solutions.Get(models.WithSync(context.Background()), solution.ID)
+	if err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := solutionModel.SetReport(&models.SolutionReport{
+		Verdict: verdict,
+	}); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(context.Background(), solutionModel); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	return solution.ID
+}
+
+func TestContestParticipantLabels(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	user3 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.ICPCStandings),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user1.ID,
+			Kind:      models.RegularParticipant,
+			Labels:    map[string]string{"division": strings.Repeat("a", 65)},
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user1.ID,
+			Kind:      models.RegularParticipant,
+			Labels:    map[string]string{"Division": "1"},
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	participants := map[int64]ContestParticipant{}
+	for _, user := range []*TestUser{user1, user2, user3} {
+		division := "2"
+		if user == user1 {
+			division = "1"
 		}
-		contest, err := e.Client.CreateContest(form)
+		participant, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+				Labels:    map[string]string{"division": division},
+			},
+		)
 		if err != nil {
-			b.Fatal("Error:", err)
+			t.Fatal("Error:", err)
 		}
-		ids = append(ids, contest.ID)
+		if participant.Labels["division"] != division {
+			t.Fatal("Invalid labels:", participant.Labels)
+		}
+		participants[user.ID] = participant
 	}
-	e.Rand.Shuffle(len(ids), func(i, j int) {
-		ids[i], ids[j] = ids[j], ids[i]
-	})
-	for _, id := range ids {
-		contest, err := e.Client.ObserveContest(context.Background(), id)
+	owner.LogoutClient()
+	e.SyncStores()
+	now := e.Now
+	e.Now = now.Add(time.Hour + 5*time.Minute)
+	submitJudgedContestSolution(e, user1, contest.ID, compiler.ID, models.Accepted)
+	e.Now = now.Add(time.Hour + 10*time.Minute)
+	submitJudgedContestSolution(e, user2, contest.ID, compiler.ID, models.Rejected)
+	e.Now = now.Add(time.Hour + 15*time.Minute)
+	submitJudgedContestSolution(e, user3, contest.ID, compiler.ID, models.Accepted)
+	e.Now = now.Add(time.Hour + 30*time.Minute)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	checkPlaces := func(label string, places map[int64]int) {
+		standings, err := e.Client.ObserveLabelContestStandings(
+			context.Background(), contest.ID, label,
+		)
 		if err != nil {
-			b.Fatal("Error:", err)
+			t.Fatal("Error:", err)
 		}
-		if contest.ID != id {
-			b.Fatal("Invalid contest ID:", contest.ID)
+		if len(standings.Rows) != len(places) {
+			t.Fatalf("Expected %d rows, got %d", len(places), len(standings.Rows))
 		}
+		for _, row := range standings.Rows {
+			if row.Participant.User == nil {
+				t.Fatal("Invalid participant:", row.Participant)
+			}
+			if place := places[row.Participant.User.ID]; place != row.Place {
+				t.Fatalf("Expected place %d, got %d", place, row.Place)
+			}
+		}
+	}
+	checkPlaces("division:1", map[int64]int{user1.ID: 1})
+	checkPlaces("division:2", map[int64]int{user3.ID: 1, user2.ID: 2})
+	checkPlaces("school:1", map[int64]int{})
+	if _, err := e.Client.ObserveLabelContestStandings(
+		context.Background(), contest.ID, "Invalid label",
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else if resp.StatusCode() != http.StatusBadRequest {
+		t.Fatalf("Expected %d, got %d", http.StatusBadRequest, resp.StatusCode())
+	}
+	participant, err := e.Client.UpdateContestParticipant(
+		context.Background(), contest.ID, participants[user3.ID].ID,
+		UpdateContestParticipantForm{
+			Labels: &map[string]string{"division": "1", "school": "1"},
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if participant.Labels["division"] != "1" || participant.Labels["school"] != "1" {
+		t.Fatal("Invalid labels:", participant.Labels)
+	}
+	e.SyncStores()
+	checkPlaces("division:1", map[int64]int{user1.ID: 1, user3.ID: 2})
+	checkPlaces("division:2", map[int64]int{user2.ID: 1})
+	checkPlaces("school:1", map[int64]int{user3.ID: 1})
+}
+
+func TestContestStatistics(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	now := e.Now
+	// Contest is running from 1h to 3h, standings are frozen from 2h
+	// and unfrozen at 4h.
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:               getPtr("Test contest"),
+		BeginTime:           getPtr(NInt64(now.Add(time.Hour).Unix())),
+		Duration:            getPtr(7200),
+		FreezeBeginDuration: getPtr(3600),
+		FreezeEndTime:       getPtr(NInt64(now.Add(4 * time.Hour).Unix())),
+		StandingsKind:       getPtr(models.ICPCStandings),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	contestProblem, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	createParticipant := func(
+		accountID int64, kind models.ParticipantKind, division string, config any,
+	) models.ContestParticipant {
+		if accountID == 0 {
+			account := models.Account{Kind: models.UserAccountKind}
+			if err := e.Core.Accounts.Create(context.Background(), &account); err != nil {
+				t.Fatal("Error:", err)
+			}
+			accountID = account.ID
+		}
+		participant := models.ContestParticipant{
+			ContestID: contest.ID,
+			AccountID: accountID,
+			Kind:      kind,
+		}
+		if err := participant.SetLabels(map[string]string{"division": division}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if config != nil {
+			if err := participant.SetConfig(config); err != nil {
+				t.Fatal("Error:", err)
+			}
+		}
+		if err := e.Core.ContestParticipants.Create(context.Background(), &participant); err != nil {
+			t.Fatal("Error:", err)
+		}
+		return participant
+	}
+	createSolution := func(
+		participant models.ContestParticipant, verdict models.Verdict, createTime time.Time,
+	) {
+		solution := models.Solution{
+			Kind:       models.ContestSolutionKind,
+			ProblemID:  problem.ID,
+			CompilerID: compiler.ID,
+			AuthorID:   participant.AccountID,
+			CreateTime: createTime.Unix(),
+		}
+		if err := solution.SetReport(&models.SolutionReport{Verdict: verdict}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.Solutions.Create(context.Background(), &solution); err != nil {
+			t.Fatal("Error:", err)
+		}
+		contestSolution := models.ContestSolution{
+			ContestID:     contest.ID,
+			ParticipantID: participant.ID,
+			ProblemID:     contestProblem.ID,
+		}
+		contestSolution.ID = solution.ID
+		if err := e.Core.ContestSolutions.Create(context.Background(), &contestSolution); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	regular1 := createParticipant(user.ID, models.RegularParticipant, "1", nil)
+	regular2 := createParticipant(0, models.RegularParticipant, "2", nil)
+	virtual := createParticipant(0, models.VirtualParticipant, "2", models.VirtualParticipantConfig{
+		BeginTime: now.Add(5 * time.Hour).Unix(),
+	})
+	manager := createParticipant(0, models.ManagerParticipant, "1", nil)
+	upsolving := createParticipant(0, models.UpsolvingParticipant, "1", nil)
+	createSolution(regular1, models.Accepted, now.Add(time.Hour+10*time.Minute))
+	createSolution(regular2, models.Rejected, now.Add(time.Hour+20*time.Minute))
+	createSolution(virtual, models.Accepted, now.Add(5*time.Hour+10*time.Minute))
+	// Solutions of other kinds and out of contest are not counted.
+	createSolution(regular2, models.Accepted, now.Add(3*time.Hour+10*time.Minute))
+	createSolution(virtual, models.Rejected, now.Add(7*time.Hour+10*time.Minute))
+	createSolution(manager, models.Accepted, now.Add(time.Hour+30*time.Minute))
+	createSolution(upsolving, models.Accepted, now.Add(3*time.Hour+30*time.Minute))
+	e.SyncStores()
+	checkStatistics := func(statistics ContestStatistics, total, accepted, participants int) {
+		if len(statistics.Problems) != 1 {
+			t.Fatalf("Expected %d problems, got %d", 1, len(statistics.Problems))
+		}
+		problem := statistics.Problems[0]
+		if problem.TotalSolutions != total || problem.AcceptedSolutions != accepted ||
+			problem.TotalParticipants != participants {
+			t.Fatalf("Unexpected statistics: %+v", problem)
+		}
+	}
+	checkAccess := func(client *TestUser, allowed bool) {
+		client.LoginClient()
+		defer client.LogoutClient()
+		statistics, err := e.Client.ObserveContestStatistics(context.Background(), contest.ID)
+		if !allowed {
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			resp, ok := err.(*errorResponse)
+			if !ok {
+				t.Fatal("Invalid error:", err)
+			}
+			expectStatus(t, http.StatusForbidden, resp.StatusCode())
+			if !slices.Equal(resp.MissingPermissions, []string{perms.ObserveContestFullStandingsRole}) {
+				t.Fatal("Unexpected missing permissions:", resp.MissingPermissions)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		checkStatistics(statistics, 3, 2, 3)
+	}
+	// Running contest.
+	e.Now = now.Add(time.Hour + 30*time.Minute)
+	checkAccess(user, false)
+	checkAccess(owner, true)
+	// Frozen contest.
+	e.Now = now.Add(2*time.Hour + 30*time.Minute)
+	checkAccess(user, false)
+	checkAccess(owner, true)
+	// Finished and frozen contest.
+	e.Now = now.Add(3*time.Hour + 30*time.Minute)
+	checkAccess(user, false)
+	// Finished and unfrozen contest.
+	e.Now = now.Add(4*time.Hour + 30*time.Minute)
+	checkAccess(user, true)
+	// Statistics for label are not cached.
+	createSolution(regular1, models.Rejected, now.Add(time.Hour+40*time.Minute))
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	statistics, err := e.Client.ObserveContestStatistics(context.Background(), contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkStatistics(statistics, 3, 2, 3)
+	statistics, err = e.Client.ObserveLabelContestStatistics(
+		context.Background(), contest.ID, "division:1",
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkStatistics(statistics, 2, 1, 1)
+	statistics, err = e.Client.ObserveLabelContestStatistics(
+		context.Background(), contest.ID, "division:2",
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkStatistics(statistics, 2, 1, 2)
+}
+
+func TestContestStandingsFilters(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.ICPCStandings),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	contestProblem, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participants := map[int64]ContestParticipant{}
+	for _, user := range []*TestUser{user1, user2} {
+		participant, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		participants[user.ID] = participant
+	}
+	owner.LogoutClient()
+	scope := models.Scope{Title: "Test scope", OwnerID: NInt64(owner.ID)}
+	scopeUser := models.ScopeUser{Login: "team1"}
+	if err := e.Core.WrapTx(context.Background(), func(ctx context.Context) error {
+		account := models.Account{Kind: scope.AccountKind()}
+		if err := e.Core.Accounts.Create(ctx, &account); err != nil {
+			return err
+		}
+		scope.ID = account.ID
+		if err := e.Core.Scopes.Create(ctx, &scope); err != nil {
+			return err
+		}
+		scopeUser.ScopeID = scope.ID
+		if err := e.Core.ScopeUsers.SetPassword(&scopeUser, "qwerty123"); err != nil {
+			return err
+		}
+		userAccount := models.Account{Kind: scopeUser.AccountKind()}
+		if err := e.Core.Accounts.Create(ctx, &userAccount); err != nil {
+			return err
+		}
+		scopeUser.ID = userAccount.ID
+		if err := e.Core.ScopeUsers.Create(ctx, &scopeUser); err != nil {
+			return err
+		}
+		participant := models.ContestParticipant{
+			ContestID: contest.ID,
+			AccountID: scopeUser.ID,
+			Kind:      models.RegularParticipant,
+		}
+		if err := e.Core.ContestParticipants.Create(ctx, &participant); err != nil {
+			return err
+		}
+		solution := models.Solution{
+			Kind:       models.ContestSolutionKind,
+			ProblemID:  problem.ID,
+			CompilerID: compiler.ID,
+			AuthorID:   scopeUser.ID,
+			CreateTime: e.Now.Add(time.Hour + 20*time.Minute).Unix(),
+		}
+		if err := solution.SetReport(&models.SolutionReport{
+			Verdict: models.Accepted,
+		}); err != nil {
+			return err
+		}
+		if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+			return err
+		}
+		contestSolution := models.ContestSolution{
+			ContestID:     contest.ID,
+			ParticipantID: participant.ID,
+			ProblemID:     contestProblem.ID,
+		}
+		contestSolution.ID = solution.ID
+		return e.Core.ContestSolutions.Create(ctx, &contestSolution)
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	now := e.Now
+	e.Now = now.Add(time.Hour + 5*time.Minute)
+	submitJudgedContestSolution(e, user1, contest.ID, compiler.ID, models.Accepted)
+	e.Now = now.Add(time.Hour + 10*time.Minute)
+	submitJudgedContestSolution(e, user2, contest.ID, compiler.ID, models.Rejected)
+	e.Now = now.Add(time.Hour + 30*time.Minute)
+	// Make second participant manager to check kind filter.
+	participant, err := e.Core.ContestParticipants.Get(
+		context.Background(), participants[user2.ID].ID,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant.Kind = models.ManagerParticipant
+	if err := e.Core.ContestParticipants.Update(context.Background(), participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	checkRows := func(query url.Values, accounts ...int64) {
+		standings, err := e.Client.ObserveFilteredContestStandings(contest.ID, query)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if len(standings.Rows) != len(accounts) {
+			t.Fatalf("Expected %d rows, got %d", len(accounts), len(standings.Rows))
+		}
+		for _, row := range standings.Rows {
+			var accountID int64
+			switch {
+			case row.Participant.User != nil:
+				accountID = row.Participant.User.ID
+			case row.Participant.ScopeUser != nil:
+				accountID = row.Participant.ScopeUser.ID
+			}
+			if !slices.Contains(accounts, accountID) {
+				t.Fatalf("Unexpected account %d", accountID)
+			}
+		}
+	}
+	expectError := func(query url.Values, code int) {
+		_, err := e.Client.ObserveFilteredContestStandings(contest.ID, query)
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		resp, ok := err.(statusCodeResponse)
+		if !ok {
+			t.Fatal("Invalid error:", err)
+		}
+		expectStatus(t, code, resp.StatusCode())
+	}
+	owner.LoginClient()
+	checkRows(url.Values{}, user1.ID, scopeUser.ID, user2.ID)
+	checkRows(url.Values{"kind": {"regular"}}, user1.ID, scopeUser.ID)
+	checkRows(url.Values{"kind": {"manager"}}, user2.ID)
+	checkRows(url.Values{"kind": {"upsolving"}})
+	checkRows(url.Values{"scope_id": {fmt.Sprint(scope.ID)}}, scopeUser.ID)
+	checkRows(url.Values{
+		"kind":     {"manager"},
+		"scope_id": {fmt.Sprint(scope.ID)},
+	})
+	checkRows(url.Values{"ignore_freeze": {"true"}}, user1.ID, scopeUser.ID, user2.ID)
+	expectError(url.Values{"kind": {"unknown"}}, http.StatusBadRequest)
+	expectError(url.Values{"kind": {"virtual"}}, http.StatusBadRequest)
+	owner.LogoutClient()
+	user1.LoginClient()
+	defer user1.LogoutClient()
+	checkRows(url.Values{"kind": {"regular"}}, user1.ID, scopeUser.ID)
+	_, err = e.Client.ObserveFilteredContestStandings(
+		contest.ID, url.Values{"ignore_freeze": {"true"}},
+	)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	resp, ok := err.(*errorResponse)
+	if !ok {
+		t.Fatal("Invalid error:", err)
+	}
+	expectStatus(t, http.StatusForbidden, resp.Code)
+	if !slices.Equal(resp.MissingPermissions, []string{perms.ObserveContestFullStandingsRole}) {
+		t.Fatal("Unexpected missing permissions:", resp.MissingPermissions)
+	}
+}
+
+func TestContestStandings(t *testing.T) {
+	e := NewTestEnv(t, WithInvoker{})
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_problem", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	user3 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	problem := NewTestProblem(e)
+	interactiveProblem := NewTestInteractiveProblem(e)
+	contestForm := createContestForm{
+		Title:              getPtr("Test contest"),
+		BeginTime:          getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:           getPtr(7200),
+		EnableRegistration: getPtr(true),
+		EnableUpsolving:    getPtr(true),
+		StandingsKind:      getPtr(models.ICPCStandings),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	{
+		problemForm := createContestProblemForm{}
+		problemForm.Code = getPtr("A")
+		problemForm.ProblemID = getPtr(problem.ID)
+		if _, err := e.Client.CreateContestProblem(contest.ID, problemForm); err != nil {
+			t.Fatal("Error", err)
+		}
+	}
+	{
+		problemForm := createContestProblemForm{}
+		problemForm.Code = getPtr("B")
+		problemForm.ProblemID = getPtr(interactiveProblem.ID)
+		if _, err := e.Client.CreateContestProblem(contest.ID, problemForm); err != nil {
+			t.Fatal("Error", err)
+		}
+	}
+	{
+		form := CreateContestParticipantForm{
+			AccountID: user1.ID,
+			Kind:      models.RegularParticipant,
+		}
+		if _, err := e.Client.CreateContestParticipant(context.Background(), contest.ID, form); err != nil {
+			t.Fatal("Error", err)
+		}
+	}
+	{
+		form := CreateContestParticipantForm{
+			AccountID: user2.ID,
+			Kind:      models.RegularParticipant,
+		}
+		if _, err := e.Client.CreateContestParticipant(context.Background(), contest.ID, form); err != nil {
+			t.Fatal("Error", err)
+		}
+	}
+	{
+		form := CreateContestParticipantForm{
+			AccountID: user3.ID,
+			Kind:      models.RegularParticipant,
+		}
+		if _, err := e.Client.CreateContestParticipant(context.Background(), contest.ID, form); err != nil {
+			t.Fatal("Error", err)
+		}
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	now := e.Now
+	{
+		user1.LoginClient()
+		e.Now = now.Add(time.Hour).Add(time.Minute * 5)
+		form := SubmitSolutionForm{
+			CompilerID: compiler.ID,
+			Content:    getPtr("CompilationError"),
+		}
+		solution, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", form)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+		// TODO: Fix submit solution handler.
+		if err := e.Core.ContestSolutions.Sync(context.Background()); err != nil {
+			t.Fatal("Error", err)
+		}
+		solutionModel, err := e.Core.ContestSolutions.Get(context.Background(), solution.ID)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+		e.WaitSolutionJudged(solutionModel.ID)
+		solution, err = e.Client.ObserveContestSolution(context.Background(), contest.ID, solution.ID)
+		if err != nil {
+			t.Fatal("Error", err)
+		}
+		if v := solution.Solution.Report.Verdict; v != models.CompilationError.String() {
+			t.Fatalf("Expected: %q, got: %q", models.CompilationError, solution.Solution.Report.Verdict)
+		}
+		if standings, err := e.Client.ObserveContestStandings(context.Background(), contest.ID); err != nil {
+			t.Fatal("Error:", err)
+		} else {
+			if v := standings.Stage; v != "started" {
+				t.Errorf("Expected: %q, got: %q", "started", v)
+			}
+			e.Check(standings)
+		}
+		user1.LogoutClient()
+	}
+}
+
+func BenchmarkContests(b *testing.B) {
+	e := NewTestEnv(b)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_contest", "create_contest", "update_contest", "delete_contest")
+	user.LoginClient()
+	b.ResetTimer()
+	var ids []int64
+	for i := 0; i < b.N; i++ {
+		form := createContestForm{
+			Title: getPtr(fmt.Sprintf("Contest %d", i+1)),
+		}
+		contest, err := e.Client.CreateContest(form)
+		if err != nil {
+			b.Fatal("Error:", err)
+		}
+		ids = append(ids, contest.ID)
+	}
+	e.Rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+	for _, id := range ids {
+		contest, err := e.Client.ObserveContest(context.Background(), id)
+		if err != nil {
+			b.Fatal("Error:", err)
+		}
+		if contest.ID != id {
+			b.Fatal("Invalid contest ID:", contest.ID)
+		}
+	}
+}
+
+func TestContestScope(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	admin := NewTestUser(e)
+	admin.AddRoles("create_contest")
+	other := NewTestUser(e)
+	other.AddRoles("create_contest")
+	scope := models.Scope{Title: "Test scope", OwnerID: NInt64(admin.ID)}
+	if err := e.Core.WrapTx(context.Background(), func(ctx context.Context) error {
+		account := models.Account{Kind: scope.AccountKind()}
+		if err := e.Core.Accounts.Create(ctx, &account); err != nil {
+			return err
+		}
+		scope.ID = account.ID
+		return e.Core.Scopes.Create(ctx, &scope)
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	form := createContestForm{
+		Title:           getPtr("Scope contest"),
+		EnableObserving: getPtr(true),
+		ScopeID:         getPtr(scope.ID),
+	}
+	other.LoginClient()
+	if _, err := e.Client.CreateContest(form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	other.LogoutClient()
+	admin.LoginClient()
+	contest, err := e.Client.CreateContest(form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if contest.ScopeID != NInt64(scope.ID) {
+		t.Fatalf("Expected scope %d, got %d", scope.ID, contest.ScopeID)
+	}
+	form.ScopeID = nil
+	publicContest, err := e.Client.CreateContest(form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	admin.LogoutClient()
+	e.SyncStores()
+	other.LoginClient()
+	if _, err := e.Client.ObserveContest(context.Background(), publicContest.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Contest of scope is not observable outside of scope.
+	if _, err := e.Client.ObserveContest(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	other.LogoutClient()
+}
+
+func TestContestGrants(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	jury := NewTestUser(e)
+	owner.LoginClient()
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	form := CreateContestGrantForm{
+		AccountID: jury.ID,
+		Kind:      models.JuryContestGrant,
+	}
+	grant, err := e.Client.CreateContestGrant(context.Background(), contest.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(grant)
+	if _, err := e.Client.CreateContestGrant(context.Background(), contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if grants, err := e.Client.ObserveContestGrants(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(grants)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	jury.LoginClient()
+	if contest, err := e.Client.ObserveContest(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(contest)
+	}
+	if _, err := e.Client.ObserveContestGrants(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	jury.LogoutClient()
+	owner.LoginClient()
+	if _, err := e.Client.DeleteContestGrant(context.Background(), contest.ID, grant.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	jury.LoginClient()
+	if _, err := e.Client.ObserveContest(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	jury.LogoutClient()
+}
+
+func TestEstimateQueueWait(t *testing.T) {
+	tests := []struct {
+		Position int
+		Running  int
+		Expected int64
+	}{
+		{0, 0, 0},
+		{1, 0, 10},
+		{1, 2, 10},
+		{3, 2, 20},
+		{4, 2, 20},
+	}
+	for _, test := range tests {
+		if wait := estimateQueueWait(test.Position, test.Running, 10); wait != test.Expected {
+			t.Errorf("Expected %d, got %d", test.Expected, wait)
+		}
+	}
+}
+
+func TestContestQueue(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	owner.LoginClient()
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if queue, err := e.Client.ObserveContestQueue(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(queue)
+	}
+	owner.LogoutClient()
+}
+
+func TestContestAppeals(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contestForm := createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem", OwnerID: NInt64(owner.ID)}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   user.ID,
+		Content:    "int main() {}",
+		CreateTime: e.Now.Add(time.Hour + time.Minute).Unix(),
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.WrongAnswer,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     contest.ID,
+		ParticipantID: participant.ID,
+		ProblemID:     contestProblem.ID,
+	}
+	contestSolution.ID = solution.ID
+	if err := e.Core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	now := e.Now
+	user.LoginClient()
+	form := CreateContestAppealForm{
+		SolutionID: solution.ID,
+		Message:    "Checker rejects valid answer",
+	}
+	e.Now = now.Add(2 * time.Hour)
+	if _, err := e.Client.CreateContestAppeal(ctx, contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	e.Now = now.Add(3*time.Hour + time.Second)
+	appeal, err := e.Client.CreateContestAppeal(ctx, contest.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(appeal)
+	if _, err := e.Client.CreateContestAppeal(ctx, contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if _, err := e.Client.CreateContestAppealComment(
+		ctx, contest.ID, appeal.ID,
+		CreateContestAppealCommentForm{Text: "Output format is correct"},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ResolveContestAppeal(
+		ctx, contest.ID, appeal.ID,
+		ResolveContestAppealForm{Status: models.AcceptedContestAppeal},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	if appeals, err := e.Client.ObserveContestAppeals(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(appeals)
+	}
+	if resolved, err := e.Client.ResolveContestAppeal(
+		ctx, contest.ID, appeal.ID,
+		ResolveContestAppealForm{
+			Status:  models.AcceptedContestAppeal,
+			Comment: "Checker is fixed",
+			Points:  getPtr(100.0),
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(resolved)
+	}
+	if _, err := e.Client.CreateContestAppealComment(
+		ctx, contest.ID, appeal.ID,
+		CreateContestAppealCommentForm{Text: "Thanks"},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	if solution, err := e.Core.Solutions.Get(models.WithSync(ctx), solution.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if report, err := solution.GetReport(); err != nil {
+		t.Fatal("Error:", err)
+	} else if report.Points == nil || *report.Points != 100 {
+		t.Fatalf("Unexpected points: %v", report.Points)
+	}
+}
+
+func TestContestScoreOverrides(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contestForm := createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.IOIStandings),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem", OwnerID: NInt64(owner.ID)}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	form := CreateContestScoreOverrideForm{
+		ParticipantID: participant.ID,
+		ProblemID:     contestProblem.ID,
+		Verdict:       models.Accepted.String(),
+	}
+	if _, err := e.Client.CreateContestScoreOverride(ctx, contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.Reason = "Paper-based solution"
+	if _, err := e.Client.CreateContestScoreOverride(ctx, contest.ID, form); err != nil {
+		t.Fatal("Error:", err)
+	}
+	form.Verdict = models.PartiallyAccepted.String()
+	form.Points = getPtr(50.0)
+	override, err := e.Client.CreateContestScoreOverride(ctx, contest.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(override)
+	if overrides, err := e.Client.ObserveContestScoreOverrides(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(overrides.Overrides) != 1 {
+		t.Fatalf("Expected single override, got %d", len(overrides.Overrides))
+	}
+	if standings, err := e.Client.ObserveContestStandings(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(standings)
+	}
+	if _, err := e.Client.DeleteContestScoreOverride(ctx, contest.ID, override.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if standings, err := e.Client.ObserveContestStandings(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(standings.Rows) != 0 {
+		t.Fatalf("Expected empty standings, got %d rows", len(standings.Rows))
+	}
+	setting := models.Setting{
+		Key:   customVerdictsSetting,
+		Value: `[{"id": 1, "name": "disqualified", "code": "DQ", "message": "Disqualified"}]`,
+	}
+	if err := e.Core.Settings.Create(ctx, &setting); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	form.Verdict = "unknown"
+	form.Points = nil
+	if _, err := e.Client.CreateContestScoreOverride(ctx, contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.Verdict = "disqualified"
+	override, err = e.Client.CreateContestScoreOverride(ctx, contest.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if override.Verdict != "disqualified" || override.VerdictCode != "DQ" {
+		t.Fatalf("Unexpected override: %v", override)
+	}
+	verdicts, err := e.Client.ObserveVerdicts(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if last := verdicts.Verdicts[len(verdicts.Verdicts)-1]; !last.Custom ||
+		last.Code != "DQ" || last.Message != "Disqualified" {
+		t.Fatalf("Unexpected verdict: %v", last)
+	}
+}
+
+func TestContestEditorials(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	contestForm := createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	ctx := context.Background()
+	if _, err := e.Client.CreateContestParticipant(
+		ctx, contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	form := CreateContestEditorialForm{
+		Locale: "en",
+		File: &FileReader{
+			Name:   "editorial.txt",
+			Reader: strings.NewReader("Editorial"),
+		},
+	}
+	if _, err := e.Client.CreateContestEditorial(ctx, contest.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.File = &FileReader{
+		Name:   "editorial.md",
+		Reader: strings.NewReader("# Editorial"),
+	}
+	editorial, err := e.Client.CreateContestEditorial(ctx, contest.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if editorial.Published || editorial.Format != MarkdownEditorialFormat {
+		t.Fatalf("Unexpected editorial: %v", editorial)
+	}
+	owner.LogoutClient()
+	user.LoginClient()
+	e.SyncStores()
+	if editorials, err := e.Client.ObserveContestEditorials(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(editorials.Editorials) != 0 {
+		t.Fatalf("Expected no editorials, got %d", len(editorials.Editorials))
+	}
+	if _, err := e.Client.PublishContestEditorial(ctx, contest.ID, editorial.ID); err == nil {
+		t.Fatal("Expected error")
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	if editorial, err := e.Client.PublishContestEditorial(ctx, contest.ID, editorial.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if !editorial.Published {
+		t.Fatalf("Expected published editorial: %v", editorial)
+	}
+	owner.LogoutClient()
+	user.LoginClient()
+	e.SyncStores()
+	if editorials, err := e.Client.ObserveContestEditorials(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(editorials.Editorials) != 1 {
+		t.Fatalf("Expected single editorial, got %d", len(editorials.Editorials))
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	if _, err := e.Client.DeleteContestEditorial(ctx, contest.ID, editorial.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if editorials, err := e.Client.ObserveContestEditorials(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(editorials.Editorials) != 0 {
+		t.Fatalf("Expected no editorials, got %d", len(editorials.Editorials))
+	}
+}
+
+func TestContestDrafts(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	contestForm := createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	ctx := context.Background()
+	if _, err := e.Client.CreateContestParticipant(
+		ctx, contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	problem := models.Problem{Title: "Test problem", OwnerID: NInt64(owner.ID)}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	if _, err := e.Client.ObserveContestDraft(ctx, contest.ID, "A"); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+	form := UpdateContestDraftForm{Content: "int main() {}"}
+	if _, err := e.Client.UpdateContestDraft(ctx, contest.ID, "A", form); err != nil {
+		t.Fatal("Error:", err)
+	}
+	form.Content = "int main() { return 0; }"
+	if _, err := e.Client.UpdateContestDraft(ctx, contest.ID, "A", form); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if draft, err := e.Client.ObserveContestDraft(ctx, contest.ID, "A"); err != nil {
+		t.Fatal("Error:", err)
+	} else if draft.Content != form.Content || draft.UpdateTime != e.Now.Unix() {
+		t.Fatalf("Unexpected draft: %v", draft)
+	}
+	form.CompilerID = 1000
+	if _, err := e.Client.UpdateContestDraft(ctx, contest.ID, "A", form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.CompilerID = 0
+	form.Content = strings.Repeat("a", 64*1024+1)
+	if _, err := e.Client.UpdateContestDraft(ctx, contest.ID, "A", form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusRequestEntityTooLarge, resp.StatusCode())
+	}
+}
+
+func TestContestSubmitURL(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting", "observe_contest", "create_contest")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(context.Background(), &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/solution.cpp" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("int main() { return 0; }"))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	form := SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		URL:        getPtr(server.URL + "/solution.cpp"),
+	}
+	if _, err := e.Client.SubmitContestSolution(ctx, contest.ID, "A", form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	e.SyncStores()
+	e.Core.Config.Security.SolutionURLHosts = []string{"127.0.0.1"}
+	solution, err := e.Client.SubmitContestSolution(ctx, contest.ID, "A", form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if solution.ID == 0 {
+		t.Fatal("Expected solution")
+	}
+	e.SyncStores()
+	form.URL = getPtr(server.URL + "/missing.cpp")
+	if _, err := e.Client.SubmitContestSolution(ctx, contest.ID, "A", form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+}
+
+func TestContestLanguages(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting", "observe_contest", "create_contest")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(context.Background(), &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	ctx := context.Background()
+	if languages, err := e.Client.ObserveContestLanguages(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(languages.Languages) != 1 || languages.Languages[0].TotalSolutions != 0 {
+		t.Fatalf("Unexpected languages: %v", languages.Languages)
+	}
+	form := SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	}
+	solution, err := e.Client.SubmitContestSolution(ctx, contest.ID, "A", form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	solutionModel, err := e.Core.Solutions.Get(models.WithSync(ctx), solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solutionModel.SetReport(&models.SolutionReport{
+		Verdict: models.Accepted,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(ctx, solutionModel); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	languages, err := e.Client.ObserveContestLanguages(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(languages.Languages) != 1 {
+		t.Fatalf("Expected single language, got %d", len(languages.Languages))
+	}
+	if language := languages.Languages[0]; language.CompilerID != compiler.ID ||
+		language.TotalSolutions != 1 || language.AcceptedSolutions != 1 {
+		t.Fatalf("Unexpected language: %v", language)
+	}
+}
+
+func TestGetRawSolutionURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/owner/repo/blob/main/a.cpp":    "https://raw.githubusercontent.com/owner/repo/main/a.cpp",
+		"https://gitlab.com/owner/repo/-/blob/main/a.cpp":  "https://gitlab.com/owner/repo/-/raw/main/a.cpp",
+		"https://example.com/owner/repo/blob/main/a.cpp":   "https://example.com/owner/repo/blob/main/a.cpp",
+		"https://github.com/owner/repo/raw/main/dir/a.cpp": "https://github.com/owner/repo/raw/main/dir/a.cpp",
+	}
+	for input, output := range tests {
+		u, err := url.Parse(input)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if raw := getRawSolutionURL(u).String(); raw != output {
+			t.Fatalf("Expected %q, got %q", output, raw)
+		}
+	}
+}
+
+func TestContestProblemLocale(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contestForm := createContestForm{
+		Title:  getPtr("Test contest"),
+		Locale: getPtr("ru"),
+	}
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if contest.Locale != "ru" {
+		t.Fatalf("Expected: %q, got: %q", "ru", contest.Locale)
+	}
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem", OwnerID: NInt64(owner.ID)}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, locale := range []string{"en", "ru", "de"} {
+		resource := models.ProblemResource{ProblemID: problem.ID}
+		if err := resource.SetConfig(models.ProblemStatementConfig{
+			Locale: locale,
+			Title:  "Title " + locale,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.ProblemResources.Create(ctx, &resource); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	tests := []struct {
+		Locale string
+		Result string
+	}{
+		{"", "ru"},
+		{"de", "de"},
+		{"fr", "ru"},
+	}
+	for _, test := range tests {
+		resp, err := e.Client.ObserveContestProblem(contest.ID, contestProblem.ID, test.Locale)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if resp.Problem.Statement == nil {
+			t.Fatal("Expected statement")
+		}
+		if v := resp.Problem.Statement.Locale; v != test.Result {
+			t.Fatalf("Expected: %q, got: %q", test.Result, v)
+		}
+	}
+}
+
+func TestContestProblemOrder(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	ctx := context.Background()
+	ids := map[string]int64{}
+	for _, code := range []string{"A10", "B", "A2"} {
+		problem := models.Problem{Title: "Problem " + code, OwnerID: NInt64(owner.ID)}
+		if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+			t.Fatal("Error:", err)
+		}
+		contestProblem := models.ContestProblem{
+			ContestID: contest.ID,
+			ProblemID: problem.ID,
+			Code:      code,
+		}
+		if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+			t.Fatal("Error:", err)
+		}
+		ids[code] = contestProblem.ID
+	}
+	e.SyncStores()
+	checkOrder := func(problems []ContestProblem, codes ...string) {
+		if len(problems) != len(codes) {
+			t.Fatalf("Expected %d problems, got %d", len(codes), len(problems))
+		}
+		for i, problem := range problems {
+			if problem.Code != codes[i] {
+				t.Fatalf("Expected %q at %d, got %q", codes[i], i, problem.Code)
+			}
+		}
+	}
+	if resp, err := e.Client.ObserveContestProblems(contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		checkOrder(resp.Problems, "A2", "A10", "B")
+	}
+	if _, err := e.Client.UpdateContestProblem(
+		contest.ID, ids["B"], updateContestProblemForm{Color: getPtr("red")},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if resp, err := e.Client.UpdateContestProblem(
+		contest.ID, ids["B"], updateContestProblemForm{
+			Title: getPtr("Warmup"),
+			Color: getPtr("#FF0000"),
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if resp.Title == nil || *resp.Title != "Warmup" ||
+		resp.Color == nil || *resp.Color != "#FF0000" {
+		t.Fatalf("Unexpected problem: %v", resp)
+	}
+	if _, err := e.Client.ReorderContestProblems(
+		contest.ID, reorderContestProblemsForm{
+			ProblemIDs: []int64{ids["B"], ids["A2"]},
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if resp, err := e.Client.ReorderContestProblems(
+		contest.ID, reorderContestProblemsForm{
+			ProblemIDs: []int64{ids["B"], ids["A10"], ids["A2"]},
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		checkOrder(resp.Problems, "B", "A10", "A2")
+	}
+	if resp, err := e.Client.ObserveContestProblems(contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		checkOrder(resp.Problems, "B", "A10", "A2")
+		if resp.Problems[0].Title == nil || *resp.Problems[0].Title != "Warmup" {
+			t.Fatal("Expected display title")
+		}
+	}
+}
+
+func TestContestProblemSolutionsLimit(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	problemResp, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:             getPtr("A"),
+		ProblemID:        getPtr(problem.ID),
+		MaxSolutions:     getPtr(2),
+		SolutionInterval: getPtr(120),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	form := SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	}
+	now := e.Now.Add(time.Hour)
+	e.Now = now
+	if resp, err := e.Client.ObserveContestProblem(contest.ID, problemResp.ID, ""); err != nil {
+		t.Fatal("Error:", err)
+	} else if resp.Constraints == nil {
+		t.Fatal("Expected constraints")
+	} else {
+		if v := resp.Constraints.MaxFileSize; v != maxSolutionFileSize {
+			t.Fatalf("Expected max file size %d, got %d", maxSolutionFileSize, v)
+		}
+		if len(resp.Constraints.Compilers) != 1 || resp.Constraints.Compilers[0] != compiler.ID {
+			t.Fatalf("Unexpected compilers: %v", resp.Constraints.Compilers)
+		}
+		if resp.MaxSolutions == nil || *resp.MaxSolutions != 2 {
+			t.Fatalf("Unexpected max solutions: %v", resp.MaxSolutions)
+		}
+	}
+	submit := func(delay time.Duration, code int) {
+		e.Now = now.Add(delay)
+		_, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", form)
+		if code == http.StatusCreated {
+			if err != nil {
+				t.Fatal("Error:", err)
+			}
+			return
+		}
+		if err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, code, resp.StatusCode())
+		}
+	}
+	submit(time.Minute, http.StatusCreated)
+	submit(2*time.Minute, http.StatusTooManyRequests)
+	submit(4*time.Minute, http.StatusCreated)
+	submit(10*time.Minute, http.StatusForbidden)
+}
+
+func TestContestProblemFeedbackPolicy(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	problemResp, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.UpdateContestProblem(
+		contest.ID, problemResp.ID, updateContestProblemForm{
+			FeedbackPolicy: getPtr(models.FeedbackPolicy("unknown")),
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	e.Now = e.Now.Add(time.Hour + time.Minute)
+	user.LoginClient()
+	solution, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	user.LogoutClient()
+	solutionModel, err := e.Core.Solutions.Get(models.WithSync(context.Background()), solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solutionModel.SetReport(&models.SolutionReport{
+		Verdict: models.WrongAnswer,
+		Usage:   models.UsageReport{Time: 100, Memory: 1024},
+		Tests: []models.TestReport{
+			{Verdict: models.Accepted, Group: "samples", Points: getPtr(1.0), Sample: true},
+			{Verdict: models.Accepted, Group: "main", Points: getPtr(2.0)},
+			{Verdict: models.WrongAnswer, Group: "main", Points: getPtr(0.0)},
+		},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(context.Background(), solutionModel); err != nil {
+		t.Fatal("Error:", err)
+	}
+	observe := func(policy models.FeedbackPolicy) *SolutionReport {
+		owner.LoginClient()
+		if _, err := e.Client.UpdateContestProblem(
+			contest.ID, problemResp.ID, updateContestProblemForm{
+				FeedbackPolicy: getPtr(policy),
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+		owner.LogoutClient()
+		e.SyncStores()
+		user.LoginClient()
+		defer user.LogoutClient()
+		resp, err := e.Client.ObserveContestSolution(context.Background(), contest.ID, solution.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if resp.Solution.Report == nil {
+			t.Fatal("Expected report")
+		}
+		return resp.Solution.Report
+	}
+	if report := observe(""); len(report.Tests) != 0 || report.TestNumber != 3 {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	if report := observe(models.FullFeedback); len(report.Tests) != 3 ||
+		report.Tests[2].Verdict != models.WrongAnswer || report.Tests[2].CheckLog != "" {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	if report := observe(models.FirstFailedFeedback); len(report.Tests) != 1 ||
+		report.TestNumber != 3 || report.Tests[0].VerdictCode != "WA" {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	if report := observe(models.GroupsFeedback); len(report.Tests) != 0 ||
+		report.TestNumber != 0 || len(report.Groups) != 2 {
+		t.Fatalf("Unexpected report: %v", report)
+	} else if group := report.Groups[1]; group.Name != "main" ||
+		group.Verdict != models.WrongAnswer || group.Tests != 2 ||
+		group.AcceptedTests != 1 || group.Points == nil || *group.Points != 2 {
+		t.Fatalf("Unexpected group: %v", group)
+	}
+	if report := observe(models.VerdictFeedback); len(report.Tests) != 0 ||
+		report.TestNumber != 0 || report.UsedTime != 0 ||
+		report.Verdict != models.WrongAnswer.String() {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	if report := observe(models.SamplesFeedback); len(report.Tests) != 1 ||
+		report.TestNumber != 0 || report.Tests[0].Verdict != models.Accepted {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	e.Now = e.Now.Add(2 * time.Hour)
+	if report := observe(models.SamplesFeedback); len(report.Tests) != 3 {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+}
+
+func TestContestStandingsFreeze(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	owner.LogoutClient()
+	ctx := context.Background()
+	for _, hideAttempts := range []bool{false, true} {
+		owner.LoginClient()
+		contestForm := createContestForm{
+			Title:               getPtr("Test contest"),
+			BeginTime:           getPtr(NInt64(e.Now.Add(-2 * time.Hour).Unix())),
+			Duration:            getPtr(3 * 3600),
+			FreezeBeginDuration: getPtr(3600),
+			StandingsKind:       getPtr(models.ICPCStandings),
+			PenaltyTime:         getPtr(10),
+			HideFrozenAttempts:  getPtr(hideAttempts),
+		}
+		contest, err := e.Client.CreateContest(contestForm)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		participant, err := e.Client.CreateContestParticipant(
+			ctx, contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		owner.LogoutClient()
+		attempts := []struct {
+			Code    string
+			Time    time.Duration
+			Verdict models.Verdict
+		}{
+			{"A", 30 * time.Minute, models.WrongAnswer},
+			{"A", 40 * time.Minute, models.Accepted},
+			{"B", 70 * time.Minute, models.WrongAnswer},
+			{"B", 80 * time.Minute, models.Accepted},
+		}
+		contestProblems := map[string]models.ContestProblem{}
+		for _, attempt := range attempts {
+			if _, ok := contestProblems[attempt.Code]; !ok {
+				problem := models.Problem{Title: "Test problem", OwnerID: NInt64(owner.ID)}
+				if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+					t.Fatal("Error:", err)
+				}
+				contestProblem := models.ContestProblem{
+					ContestID: contest.ID,
+					ProblemID: problem.ID,
+					Code:      attempt.Code,
+				}
+				if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+					t.Fatal("Error:", err)
+				}
+				contestProblems[attempt.Code] = contestProblem
+			}
+			contestProblem := contestProblems[attempt.Code]
+			solution := models.Solution{
+				ProblemID:  contestProblem.ProblemID,
+				CompilerID: compiler.ID,
+				AuthorID:   user.ID,
+				Content:    "int main() {}",
+				CreateTime: e.Now.Add(-2*time.Hour + attempt.Time).Unix(),
+			}
+			if err := solution.SetReport(&models.SolutionReport{
+				Verdict: attempt.Verdict,
+			}); err != nil {
+				t.Fatal("Error:", err)
+			}
+			if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+				t.Fatal("Error:", err)
+			}
+			contestSolution := models.ContestSolution{
+				ContestID:     contest.ID,
+				ParticipantID: participant.ID,
+				ProblemID:     contestProblem.ID,
+			}
+			contestSolution.ID = solution.ID
+			if err := e.Core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+				t.Fatal("Error:", err)
+			}
+		}
+		e.SyncStores()
+		user.LoginClient()
+		if standings, err := e.Client.ObserveContestStandings(ctx, contest.ID); err != nil {
+			t.Fatal("Error:", err)
+		} else {
+			e.Check(standings)
+		}
+		user.LogoutClient()
+	}
+}
+
+func TestContestObserverRegistration(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contestForm := createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.ICPCStandings),
+	}
+	closedContest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestForm.EnableObserving = getPtr(true)
+	contest, err := e.Client.CreateContest(contestForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	expectError := func(err error, code int) {
+		if err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, code, resp.StatusCode())
+		}
+	}
+	form := registerContestForm{Kind: getPtr(models.ObserverParticipant)}
+	_, err = e.Client.RegisterContest(closedContest.ID, form)
+	expectError(err, http.StatusForbidden)
+	participant, err := e.Client.RegisterContest(contest.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if participant.Kind != models.ObserverParticipant {
+		t.Fatalf("Expected observer, got %v", participant.Kind)
+	}
+	e.SyncStores()
+	_, err = e.Client.RegisterContest(contest.ID, form)
+	expectError(err, http.StatusForbidden)
+	_, err = e.Client.ObserveContestProblems(contest.ID)
+	expectError(err, http.StatusForbidden)
+	e.Now = e.Now.Add(90 * time.Minute)
+	if resp, err := e.Client.ObserveContestProblems(contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(resp.Problems) != 1 {
+		t.Fatalf("Expected 1 problem, got %d", len(resp.Problems))
+	}
+	if _, err := e.Client.ObserveContestStandings(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	_, err = e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	})
+	expectError(err, http.StatusForbidden)
+}
+
+func TestContestWidgets(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:         getPtr("Test contest"),
+		BeginTime:     getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:      getPtr(7200),
+		StandingsKind: getPtr(models.ICPCStandings),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, user := range []*TestUser{user1, user2} {
+		if _, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	now := e.Now
+	e.Now = now.Add(time.Hour + 5*time.Minute)
+	user1.LoginClient()
+	solution, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if solution, err := e.Client.ObserveContestSolution(
+		context.Background(), contest.ID, solution.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if solution.ContestTime == nil || *solution.ContestTime != 300 {
+		t.Fatal("Invalid contest time:", solution.ContestTime)
+	}
+	user1.LogoutClient()
+	solutionModel, err := e.Core.Solutions.Get(models.WithSync(context.Background()), solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solutionModel.SetReport(&models.SolutionReport{
+		Verdict: models.Accepted,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(context.Background(), solutionModel); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	e.Now = now.Add(time.Hour + 10*time.Minute)
+	request := ObserveContestWidgetRequest{ContestID: contest.ID}
+	expectError := func(err error, code int) {
+		if err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, code, resp.StatusCode())
+		}
+	}
+	_, err = e.Client.ObserveContestWidgetTop(context.Background(), request)
+	expectError(err, http.StatusForbidden)
+	owner.LoginClient()
+	top, err := e.Client.ObserveContestWidgetTop(context.Background(), request)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(top.Rows) != 1 {
+		t.Fatalf("Expected 1 row, got %d", len(top.Rows))
+	}
+	if top.Rows[0].Title != user1.Login || top.Rows[0].Solved != 1 || top.Rows[0].Place != 1 {
+		t.Fatalf("Unexpected row: %v", top.Rows[0])
+	}
+	link, err := e.Client.CreateContestWidgetLink(
+		context.Background(), contest.ID, CreateContestWidgetLinkForm{
+			ExpireTime: e.Now.Add(time.Hour).Unix(),
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	request.ExpireTime = link.ExpireTime
+	request.Signature = link.Signature
+	request.Limit = 1
+	ticker, err := e.Client.ObserveContestWidgetTicker(context.Background(), request)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(ticker.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(ticker.Items))
+	}
+	if item := ticker.Items[0]; item.ProblemCode != "A" || item.Time != 300 {
+		t.Fatalf("Unexpected item: %v", item)
+	}
+	if top, err := e.Client.ObserveContestWidgetTop(context.Background(), request); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(top.Rows) != 1 || top.Rows[0].Title != user1.Login {
+		t.Fatalf("Unexpected rows: %v", top.Rows)
+	}
+	request.Signature = "invalid"
+	_, err = e.Client.ObserveContestWidgetTop(context.Background(), request)
+	expectError(err, http.StatusForbidden)
+}
+
+func TestContestDashboard(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	solution, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ObserveContestDashboard(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user.LogoutClient()
+	ctx := context.Background()
+	question := models.ContestMessage{
+		ContestID:     contest.ID,
+		ParticipantID: NInt64(participant.ID),
+		AuthorID:      user.ID,
+		Kind:          models.QuestionContestMessage,
+		Title:         "Question",
+		CreateTime:    e.Now.Unix(),
+	}
+	if err := e.Core.ContestMessages.Create(ctx, &question); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solutionModel, err := e.Core.Solutions.Get(models.WithSync(ctx), solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solutionModel.SetReport(&models.SolutionReport{
+		Verdict: models.Failed,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(ctx, solutionModel); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.InvokerHeartbeats.Create(ctx, &models.InvokerHeartbeat{
+		Name:     "judge-1",
+		Workers:  4,
+		PingTime: e.Now.Unix(),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	dashboard, err := e.Client.ObserveContestDashboard(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if dashboard.PendingQuestions != 1 {
+		t.Fatalf("Expected 1 pending question, got %d", dashboard.PendingQuestions)
+	}
+	if dashboard.Queued != 1 {
+		t.Fatalf("Expected 1 queued solution, got %d", dashboard.Queued)
+	}
+	if len(dashboard.Errors) != 1 || dashboard.Errors[0].SolutionID != solution.ID {
+		t.Fatalf("Unexpected errors: %v", dashboard.Errors)
+	}
+	if len(dashboard.Invokers) != 1 || !dashboard.Invokers[0].Alive {
+		t.Fatalf("Unexpected invokers: %v", dashboard.Invokers)
+	}
+}
+
+func TestContestSimilarityAlerts(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, user := range []*TestUser{user1, user2} {
+		if _, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	ctx := context.Background()
+	contents := []string{
+		"#include <cstdio>\nint main() {\n\tint a, b;\n\tscanf(\"%d%d\", &a, &b);\n\tprintf(\"%d\\n\", a + b);\n}\n",
+		"#include <cstdio>\n// my solution\nint main()\n{\n  int a, b;\n  scanf(\"%d%d\", &a, &b);\n  printf(\"%d\\n\", a + b);\n}\n",
+	}
+	var solutionIDs []int64
+	for i, user := range []*TestUser{user1, user2} {
+		user.LoginClient()
+		solution, err := e.Client.SubmitContestSolution(ctx, contest.ID, "A", SubmitSolutionForm{
+			CompilerID: compiler.ID,
+			Content:    getPtr(contents[i]),
+		})
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		user.LogoutClient()
+		solutionModel, err := e.Core.Solutions.Get(models.WithSync(ctx), solution.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := solutionModel.SetReport(&models.SolutionReport{
+			Verdict: models.Accepted,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.Solutions.Update(ctx, solutionModel); err != nil {
+			t.Fatal("Error:", err)
+		}
+		solutionIDs = append(solutionIDs, solution.ID)
+	}
+	similarity := managers.NewContestSimilarityManager(
+		e.Core, managers.NewFileManager(e.Core),
+	)
+	if err := similarity.CheckSolution(
+		models.WithNow(ctx, e.Now), solutionIDs[1],
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	user2.LoginClient()
+	if messages, err := e.Client.ObserveContestMessages(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(messages.Messages) != 0 {
+		t.Fatalf("Unexpected messages: %v", messages.Messages)
+	}
+	user2.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	messages, err := e.Client.ObserveContestMessages(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(messages.Messages) != 1 || messages.Messages[0].Kind != "alert" {
+		t.Fatalf("Unexpected messages: %v", messages.Messages)
+	}
+}
+
+func TestContestAddresses(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	owner.LoginClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participants := map[int64]int64{}
+	for _, user := range []*TestUser{user1, user2} {
+		participant, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		participants[participant.ID] = user.ID
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	ctx := context.Background()
+	// Second login of user1 creates concurrent session.
+	for _, user := range []*TestUser{user1, user1, user2} {
+		user.LoginClient()
+		if _, err := e.Client.ObserveContest(ctx, contest.ID); err != nil {
+			t.Fatal("Error:", err)
+		}
+		user.LogoutClient()
+	}
+	user1.LoginClient()
+	if _, err := e.Client.ObserveContestAddresses(ctx, contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user1.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	report, err := e.Client.ObserveContestAddresses(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(report.SharedAddresses) != 1 {
+		t.Fatalf("Unexpected shared addresses: %v", report.SharedAddresses)
+	}
+	if len(report.SharedAddresses[0].Participants) != 2 {
+		t.Fatalf("Unexpected participants: %v", report.SharedAddresses[0].Participants)
+	}
+	if len(report.ConcurrentSessions) != 1 {
+		t.Fatalf("Unexpected concurrent sessions: %v", report.ConcurrentSessions)
+	}
+	sessions := report.ConcurrentSessions[0]
+	if participants[sessions.Participant.ID] != user1.ID {
+		t.Fatalf("Unexpected participant: %v", sessions.Participant)
+	}
+	if len(sessions.Addresses) < 2 {
+		t.Fatalf("Unexpected addresses: %v", sessions.Addresses)
+	}
+}
+
+func TestContestPermissionExplanations(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	owner.LoginClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user1.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	getExplanation := func(t testing.TB) permissionExplanation {
+		_, err := e.Client.ObserveContestProblems(contest.ID)
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		resp, ok := err.(*errorResponse)
+		if !ok {
+			t.Fatal("Invalid error:", err)
+		}
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+		explanation, ok := resp.PermissionExplanations[perms.ObserveContestProblemsRole]
+		if !ok {
+			t.Fatalf("Missing explanation: %v", resp.PermissionExplanations)
+		}
+		return explanation
+	}
+	user1.LoginClient()
+	explanation := getExplanation(t)
+	if explanation.Reason != "contest_stage" {
+		t.Fatalf("Unexpected reason: %q", explanation.Reason)
+	}
+	if !slices.Equal(explanation.Stages, []string{"started", "finished"}) {
+		t.Fatalf("Unexpected stages: %v", explanation.Stages)
+	}
+	user1.LogoutClient()
+	user2.LoginClient()
+	explanation = getExplanation(t)
+	if explanation.Reason != "participant_kind" {
+		t.Fatalf("Unexpected reason: %q", explanation.Reason)
+	}
+	if !slices.Contains(explanation.ParticipantKinds, models.ManagerParticipant.String()) {
+		t.Fatalf("Unexpected participant kinds: %v", explanation.ParticipantKinds)
+	}
+	user2.LogoutClient()
+}
+
+func TestContestAsyncSubmit(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	form := SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	}
+	submission, err := e.Client.SubmitContestSolutionAsync(
+		context.Background(), contest.ID, "A", form,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if submission.ID == "" || submission.URL == "" {
+		t.Fatalf("Unexpected submission: %v", submission)
+	}
+	for i := 0; submission.Status == QueuedSubmission; i++ {
+		if i > 100 {
+			t.Fatal("Submission is not processed")
+		}
+		time.Sleep(10 * time.Millisecond)
+		submission, err = e.Client.ObserveContestSubmission(
+			context.Background(), contest.ID, submission.ID,
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if submission.Status != CreatedSubmission {
+		t.Fatalf("Unexpected submission: %v", submission)
+	}
+	e.SyncStores()
+	if _, err := e.Client.ObserveContestSolution(
+		context.Background(), contest.ID, submission.SolutionID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ObserveContestSubmission(
+		context.Background(), contest.ID, "unknown",
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestContestSolutionsExport(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	solution, err := e.Client.SubmitContestSolution(
+		context.Background(), contest.ID, "A", SubmitSolutionForm{
+			CompilerID: compiler.ID,
+			Content:    getPtr("int main() { return 0; }"),
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ExportContestSolutions(
+		context.Background(), contest.ID, false,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	user.LogoutClient()
+	e.SyncStores()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	export, err := e.Client.ExportContestSolutions(
+		context.Background(), contest.ID, false,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	for i := 0; export.Status == PendingExport; i++ {
+		if i > 100 {
+			t.Fatal("Export is not finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+		export, err = e.Client.ExportContestSolutions(
+			context.Background(), contest.ID, false,
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if export.Status != ReadyExport || export.DownloadURL == "" {
+		t.Fatalf("Unexpected export: %v", export)
+	}
+	data, err := e.Client.DownloadContestSolutionsExport(
+		context.Background(), contest.ID,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(archive.File) != 1 {
+		t.Fatalf("Expected 1 file, got %d", len(archive.File))
+	}
+	if name := archive.File[0].Name; !strings.HasSuffix(
+		name, fmt.Sprintf("/A/pending/%d.cpp", solution.ID),
+	) {
+		t.Fatalf("Unexpected file name: %q", name)
+	}
+	// Export is shared between server instances.
+	saved, err := e.Core.ContestExports.GetByContest(context.Background(), contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if saved.Status != models.ReadyContestExport || saved.FileID == 0 {
+		t.Fatalf("Unexpected export: %v", saved)
+	}
+	// Expired export is removed with archive.
+	if err := NewView(e.Core).cleanupContestExports(
+		context.Background(), time.Now().Add(contestExportTTL+time.Minute),
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Core.ContestExports.GetByContest(
+		context.Background(), contest.ID,
+	); err != sql.ErrNoRows {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	if _, err := e.Core.Files.Get(
+		models.WithSync(context.Background()), int64(saved.FileID),
+	); err != sql.ErrNoRows {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	if _, err := e.Client.DownloadContestSolutionsExport(
+		context.Background(), contest.ID,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestContestPublishTime(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:              getPtr("Test contest"),
+		BeginTime:          getPtr(NInt64(e.Now.Add(2 * time.Hour).Unix())),
+		Duration:           getPtr(7200),
+		EnableRegistration: getPtr(true),
+		PublishTime:        getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if contest.PublishTime != NInt64(e.Now.Add(time.Hour).Unix()) {
+		t.Fatalf("Unexpected publish time: %v", contest.PublishTime)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	if _, err := e.Client.ObserveContest(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	if contests, err := e.Client.ObserveContests(); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(contests.Contests) != 0 {
+		t.Fatalf("Expected hidden contest, got %v", contests.Contests)
+	}
+	e.Now = e.Now.Add(90 * time.Minute)
+	if _, err := e.Client.ObserveContest(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if contests, err := e.Client.ObserveContests(); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(contests.Contests) != 1 {
+		t.Fatalf("Expected 1 contest, got %d", len(contests.Contests))
+	}
+	if _, err := e.Client.RegisterContest(contest.ID, registerContestForm{}); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func TestContestTemplates(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_setting", "create_compiler")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	compiler := NewTestCompiler(e)
+	settingForm := CreateSettingForm{}
+	settingForm.Key = getPtr(contestTemplateSettingPrefix + "weekly")
+	settingForm.Value = getPtr(fmt.Sprintf(
+		`{"duration":7200,"standings_kind":"ioi","enable_registration":true,"compilers":[%d]}`,
+		compiler.ID,
+	))
+	if _, err := e.Client.CreateSetting(context.Background(), settingForm); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	contest, err := e.Client.CreateContestFromTemplate("weekly", createContestForm{
+		Title:    getPtr("Weekly contest"),
+		Duration: getPtr(3600),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if contest.Title != "Weekly contest" || contest.Duration != 3600 {
+		t.Fatalf("Unexpected contest: %v", contest)
+	}
+	if contest.StandingsKind != models.IOIStandings || !contest.EnableRegistration {
+		t.Fatalf("Template is not applied: %v", contest)
+	}
+	if len(contest.Compilers) != 1 || contest.Compilers[0] != compiler.ID {
+		t.Fatalf("Unexpected compilers: %v", contest.Compilers)
+	}
+	if _, err := e.Client.CreateContestFromTemplate("unknown", createContestForm{
+		Title: getPtr("Weekly contest"),
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+}
+
+func TestContestMashup(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	coach := NewTestUser(e)
+	coach.AddRoles("create_mashup_contest")
+	other := NewTestUser(e)
+	var problemIDs []int64
+	for i, ownerID := range []int64{coach.ID, coach.ID, other.ID} {
+		problem := models.Problem{
+			Title:   fmt.Sprintf("Problem %d", i+1),
+			OwnerID: NInt64(ownerID),
+		}
+		if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+			t.Fatal("Error:", err)
+		}
+		problemIDs = append(problemIDs, problem.ID)
+	}
+	e.SyncStores()
+	coach.LoginClient()
+	defer coach.LogoutClient()
+	if _, err := e.Client.CreateContestMashup(
+		context.Background(), CreateContestMashupForm{
+			Title:      getPtr("Training"),
+			Duration:   getPtr(7200),
+			ProblemIDs: []int64{problemIDs[0], problemIDs[0]},
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if _, err := e.Client.CreateContestMashup(
+		context.Background(), CreateContestMashupForm{
+			Title:      getPtr("Training"),
+			Duration:   getPtr(7200),
+			ProblemIDs: problemIDs,
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	contest, err := e.Client.CreateContestMashup(
+		context.Background(), CreateContestMashupForm{
+			Title:      getPtr("Training"),
+			BeginTime:  getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+			Duration:   getPtr(7200),
+			ProblemIDs: []int64{problemIDs[1], problemIDs[0]},
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(contest)
+	e.SyncStores()
+	if v, err := e.Client.ObserveContest(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	problems, err := collectRows(
+		e.Core.ContestProblems.FindByContest(context.Background(), contest.ID),
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	codes := map[string]int64{}
+	for _, problem := range problems {
+		codes[problem.Code] = problem.ProblemID
+	}
+	if len(codes) != 2 || codes["A"] != problemIDs[1] || codes["B"] != problemIDs[0] {
+		t.Fatal("Invalid problems:", codes)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: other.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func TestContestSolutionRetest(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	solutionID := submitJudgedContestSolution(e, user, contest.ID, compiler.ID, models.WrongAnswer)
+	user.LoginClient()
+	if _, err := e.Client.CreateSolutionRetest(
+		context.Background(), contest.ID, solutionID, CreateSolutionRetestForm{Test: 1},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	if _, err := e.Client.CreateSolutionRetest(
+		context.Background(), contest.ID, solutionID, CreateSolutionRetestForm{},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	retest, err := e.Client.CreateSolutionRetest(
+		context.Background(), contest.ID, solutionID,
+		CreateSolutionRetestForm{Test: 2, UpdateReport: true},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(retest)
+	if v, err := e.Client.ObserveSolutionRetest(
+		context.Background(), contest.ID, solutionID, retest.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	if v, err := e.Client.ObserveContestSolution(
+		context.Background(), contest.ID, solutionID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if v.Solution.Report == nil || v.Solution.Report.Verdict != models.WrongAnswer.String() {
+		t.Fatal("Invalid report:", v.Solution.Report)
+	}
+}
+
+func TestContestProblemLimits(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user1 := NewTestUser(e)
+	user2 := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := problem.SetConfig(models.ProblemConfig{
+		TimeLimit:   1000,
+		MemoryLimit: 256 * 1024 * 1024,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	contestProblem, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, user := range []*TestUser{user1, user2} {
+		if _, err := e.Client.CreateContestParticipant(
+			context.Background(), contest.ID, CreateContestParticipantForm{
+				AccountID: user.ID,
+				Kind:      models.RegularParticipant,
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	submitJudgedContestSolution(e, user1, contest.ID, compiler.ID, models.TimeLimitExceeded)
+	submitJudgedContestSolution(e, user2, contest.ID, compiler.ID, models.WrongAnswer)
+	user1.LoginClient()
+	if _, err := e.Client.ObserveContestProblemLimits(
+		context.Background(), contest.ID, "A",
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user1.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	if v, err := e.Client.ObserveContestProblemLimits(
+		context.Background(), contest.ID, "A",
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	if _, err := e.Client.UpdateContestProblemLimits(
+		context.Background(), contest.ID, "A",
+		UpdateContestProblemLimitsForm{TimeLimit: getPtr[int64](10)},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	// Increased time limit rejudges only solutions with exceeded limit.
+	if v, err := e.Client.UpdateContestProblemLimits(
+		context.Background(), contest.ID, "A",
+		UpdateContestProblemLimitsForm{TimeLimit: getPtr[int64](2000)},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	if v, err := e.Client.ObserveContestProblem(
+		contest.ID, contestProblem.ID, "",
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if v.Problem.Config == nil || v.Problem.Config.TimeLimit != 2000 {
+		t.Fatal("Invalid config:", v.Problem.Config)
+	}
+	// Decreased memory limit rejudges all judged solutions.
+	if v, err := e.Client.UpdateContestProblemLimits(
+		context.Background(), contest.ID, "A",
+		UpdateContestProblemLimitsForm{MemoryLimit: getPtr[int64](64 * 1024 * 1024)},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	// Zero limits reset overrides.
+	if v, err := e.Client.UpdateContestProblemLimits(
+		context.Background(), contest.ID, "A",
+		UpdateContestProblemLimitsForm{
+			TimeLimit:   getPtr[int64](0),
+			MemoryLimit: getPtr[int64](0),
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+}
+
+func TestContestUpdates(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	updates, err := e.Client.ObserveContestUpdates(context.Background(), contest.ID, 0)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if updates.NewMessages != 0 || updates.PendingSolutions != 0 {
+		t.Fatal("Invalid updates:", updates)
+	}
+	message := models.ContestMessage{
+		ContestID:  contest.ID,
+		AuthorID:   owner.ID,
+		Kind:       models.RegularContestMessage,
+		Title:      "Test message",
+		CreateTime: e.Now.Unix(),
+	}
+	if err := e.Core.ContestMessages.Create(context.Background(), &message); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.SubmitContestSolution(context.Background(), contest.ID, "A", SubmitSolutionForm{
+		CompilerID: compiler.ID,
+		Content:    getPtr("int main() { return 0; }"),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	newUpdates, err := e.Client.ObserveContestUpdates(context.Background(), contest.ID, 0)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if newUpdates.NewMessages != 1 || newUpdates.LastMessageID != message.ID {
+		t.Fatal("Invalid updates:", newUpdates)
+	}
+	if newUpdates.PendingSolutions != 1 ||
+		newUpdates.SolutionsGeneration == updates.SolutionsGeneration {
+		t.Fatal("Invalid updates:", newUpdates)
+	}
+	if v, err := e.Client.ObserveContestUpdates(
+		context.Background(), contest.ID, newUpdates.LastMessageID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if v.NewMessages != 0 {
+		t.Fatal("Invalid updates:", v)
+	}
+}
+
+func TestContestSystemTest(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest", "create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:          getPtr("Test contest"),
+		BeginTime:      getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:       getPtr(7200),
+		EnablePretests: getPtr(true),
+		PretestGroup:   getPtr("pretests"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !contest.EnablePretests || contest.PretestGroup != "pretests" {
+		t.Fatal("Invalid pretests:", contest.EnablePretests, contest.PretestGroup)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	solutionID := submitJudgedContestSolution(e, user, contest.ID, compiler.ID, models.Accepted)
+	solution, err := e.Core.Solutions.Get(models.WithSync(context.Background()), solutionID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict:  models.Accepted,
+		Pretests: true,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(context.Background(), solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	user.LoginClient()
+	if v, err := e.Client.ObserveContestSolution(
+		context.Background(), contest.ID, solutionID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if v.Solution.Report == nil || !v.Solution.Report.Pretests {
+		t.Fatal("Invalid report:", v.Solution.Report)
+	}
+	if _, err := e.Client.StartContestSystemTest(
+		context.Background(), contest.ID,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	if _, err := e.Client.ObserveContestSystemTest(
+		context.Background(), contest.ID,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+	if v, err := e.Client.StartContestSystemTest(
+		context.Background(), contest.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	if _, err := e.Client.StartContestSystemTest(
+		context.Background(), contest.ID,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if v, err := e.Client.ObserveContestSystemTest(
+		context.Background(), contest.ID,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+}
+
+func TestContestTokens(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_contest")
+	owner.LoginClient()
+	contest, err := e.Client.CreateContest(testSimpleConfiguredContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	otherContest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestToken(context.Background(), contest.ID, CreateContestTokenForm{
+		ExpireTime: getPtr(e.Now.Add(-time.Hour).Unix()),
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	token, err := e.Client.CreateContestToken(context.Background(), contest.ID, CreateContestTokenForm{
+		Title: "Scoreboard mirror",
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if token.Token == "" {
+		t.Fatal("Expected token")
+	}
+	if tokens, err := e.Client.ObserveContestTokens(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(tokens)
+	}
+	owner.LogoutClient()
+	e.Client.Headers["Authorization"] = token.Token
+	if observed, err := e.Client.ObserveContest(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(observed.Permissions)
+	}
+	if _, err := e.Client.ObserveContestStandings(context.Background(), contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ObserveContest(context.Background(), otherContest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	if _, err := e.Client.ObserveContestTokens(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusUnauthorized, resp.StatusCode())
+	}
+	delete(e.Client.Headers, "Authorization")
+	owner.LoginClient()
+	if _, err := e.Client.DeleteContestToken(context.Background(), otherContest.ID, token.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+	if _, err := e.Client.DeleteContestToken(context.Background(), contest.ID, token.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.Client.Headers["Authorization"] = token.Token
+	if _, err := e.Client.ObserveContest(context.Background(), contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+}
+
+func TestContestErrorCodes(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.LoginClient()
+	if _, err := e.Client.ObserveContest(context.Background(), 100); err == nil {
+		t.Fatal("Expected error")
+	} else if code := GetErrorCode(err); code != CodeContestNotFound {
+		t.Fatalf("Unexpected code: %q", code)
+	}
+	if _, err := e.Client.CreateContest(testSimpleContest); err == nil {
+		t.Fatal("Expected error")
+	} else if code := GetErrorCode(err); code != CodeMissingPermissions {
+		t.Fatalf("Unexpected code: %q", code)
+	}
+	if code := GetErrorCode(fmt.Errorf("unknown")); code != "" {
+		t.Fatalf("Unexpected code: %q", code)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/udovin/gosql"
 	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/pkg/logs"
 )
@@ -14,8 +15,35 @@ import (
 func (v *View) StartDaemons() {
 	v.visits = make(chan visitContext, 100)
 	v.core.StartTask("visits", v.visitsDaemon)
+	v.core.StartTask("standings_invalidation", v.standings.RunInvalidation)
+	v.core.StartTask("clock_guard", v.clockGuardDaemon)
+	if v.files != nil {
+		v.submissions = newContestSubmissionQueue()
+		v.core.StartTask("contest_submissions", v.contestSubmissionsDaemon)
+	}
 	v.core.StartUniqueDaemon("session_cleanup", v.sessionCleanupDaemon)
 	v.core.StartUniqueDaemon("token_cleanup", v.tokenCleanupDaemon)
+	v.core.StartUniqueDaemon("contest_actions", func(ctx context.Context) {
+		v.core.RunContestActions(ctx, v.standings)
+	})
+	v.core.StartUniqueDaemon("contest_similarity", v.similarity.RunDetection)
+	if v.core.Config.Backup != nil {
+		v.core.StartUniqueDaemon("backups", v.backups.Run)
+	}
+	if cfg := v.core.Config.Analytics; cfg != nil {
+		sink, err := managers.NewAnalyticsSink(*cfg)
+		if err != nil {
+			v.core.Logger().Error("Cannot create analytics sink", err)
+		} else {
+			analytics := managers.NewAnalyticsManager(v.core, sink)
+			v.core.StartUniqueDaemon("analytics", analytics.Run)
+		}
+	}
+	if v.files != nil {
+		collector := managers.NewFileCollector(v.core, v.files)
+		v.core.StartUniqueDaemon("files_cleanup", collector.Run)
+		v.core.StartUniqueDaemon("contest_exports_cleanup", v.contestExportsCleanupDaemon)
+	}
 }
 
 type visitContext struct {
@@ -50,6 +78,32 @@ func (v *View) visitsDaemon(ctx context.Context) {
 	}
 }
 
+// clockGuardInterval contains interval between clock skew checks.
+const clockGuardInterval = time.Minute
+
+// clockGuardDaemon periodically checks that server clock does not
+// differ from clock of database.
+func (v *View) clockGuardDaemon(ctx context.Context) {
+	ticker := time.NewTicker(clockGuardInterval)
+	defer ticker.Stop()
+	for {
+		offset, err := v.core.GetClockOffset(ctx)
+		if err != nil {
+			v.core.Logger().Warn("Unable to get clock offset", err)
+		} else if v.core.IsClockSkewed(offset) {
+			v.core.Logger().Error(
+				"Clock skew exceeds threshold",
+				logs.Any("offset", offset.String()),
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (v *View) sessionCleanupDaemon(ctx context.Context) {
 	cleanupTask := func() error {
 		rows, err := v.core.Sessions.Find(ctx, db.FindQuery{
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode represents stable machine-readable code of API error.
+//
+// Messages of errors are localized and can be changed at any time,
+// so clients should use codes to distinguish errors.
+type ErrorCode string
+
+// Common error codes.
+const (
+	// CodeBadRequest is used for bad requests without specific code.
+	CodeBadRequest ErrorCode = "bad_request"
+	// CodeUnauthorized is used when request can not be authorized.
+	CodeUnauthorized ErrorCode = "unauthorized"
+	// CodeForbidden is used for forbidden requests without specific code.
+	CodeForbidden ErrorCode = "forbidden"
+	// CodeNotFound is used when requested object does not exist.
+	CodeNotFound ErrorCode = "not_found"
+	// CodeConflict is used for conflicting requests without specific code.
+	CodeConflict ErrorCode = "conflict"
+	// CodeRequestTooLarge is used when request body exceeds limit.
+	CodeRequestTooLarge ErrorCode = "request_too_large"
+	// CodeTooManyRequests is used when rate of requests exceeds limit.
+	CodeTooManyRequests ErrorCode = "too_many_requests"
+	// CodeInternalError is used for unexpected errors.
+	CodeInternalError ErrorCode = "internal_error"
+	// CodeServiceUnavailable is used when service can not handle requests.
+	CodeServiceUnavailable ErrorCode = "service_unavailable"
+	// CodeInvalidForm is used when request body can not be parsed.
+	CodeInvalidForm ErrorCode = "invalid_form"
+	// CodeInvalidFields is used when form has invalid fields that are
+	// described in invalid_fields.
+	CodeInvalidFields ErrorCode = "invalid_fields"
+	// CodeInvalidFilter is used when query parameters are invalid.
+	CodeInvalidFilter ErrorCode = "invalid_filter"
+	// CodeMissingPermissions is used when account does not have permissions
+	// that are described in missing_permissions.
+	CodeMissingPermissions ErrorCode = "missing_permissions"
+	// CodeQuotaExceeded is used when quota of solutions is exceeded.
+	CodeQuotaExceeded ErrorCode = "quota_exceeded"
+	// CodeReadOnlyMode is used when server is in read-only mode.
+	CodeReadOnlyMode ErrorCode = "read_only_mode"
+)
+
+// Error codes for objects that do not exist.
+const (
+	CodeAccountNotFound         ErrorCode = "account_not_found"
+	CodeAppealNotFound          ErrorCode = "appeal_not_found"
+	CodeCheckerNotFound         ErrorCode = "checker_not_found"
+	CodeChildRoleNotFound       ErrorCode = "child_role_not_found"
+	CodeCompilerNotFound        ErrorCode = "compiler_not_found"
+	CodeContestNotFound         ErrorCode = "contest_not_found"
+	CodeContestTemplateNotFound ErrorCode = "contest_template_not_found"
+	CodeDraftNotFound           ErrorCode = "draft_not_found"
+	CodeEditorialNotFound       ErrorCode = "editorial_not_found"
+	CodeExportNotFound          ErrorCode = "export_not_found"
+	CodeFileNotFound            ErrorCode = "file_not_found"
+	CodeGrantNotFound           ErrorCode = "grant_not_found"
+	CodeGroupMemberNotFound     ErrorCode = "group_member_not_found"
+	CodeGroupNotFound           ErrorCode = "group_not_found"
+	CodeMessageNotFound         ErrorCode = "message_not_found"
+	CodeOrganizationNotFound    ErrorCode = "organization_not_found"
+	CodeOverrideNotFound        ErrorCode = "override_not_found"
+	CodeParticipantNotFound     ErrorCode = "participant_not_found"
+	CodePostNotFound            ErrorCode = "post_not_found"
+	CodeProblemNotFound         ErrorCode = "problem_not_found"
+	CodeRoleNotFound            ErrorCode = "role_not_found"
+	CodeScopeNotFound           ErrorCode = "scope_not_found"
+	CodeSessionNotFound         ErrorCode = "session_not_found"
+	CodeSettingNotFound         ErrorCode = "setting_not_found"
+	CodeSolutionNotFound        ErrorCode = "solution_not_found"
+	CodeSubmissionNotFound      ErrorCode = "submission_not_found"
+	CodeTaskNotFound            ErrorCode = "task_not_found"
+	CodeTestNotFound            ErrorCode = "test_not_found"
+	CodeTokenNotFound           ErrorCode = "token_not_found"
+	CodeUploadNotFound          ErrorCode = "upload_not_found"
+	CodeUserNotFound            ErrorCode = "user_not_found"
+)
+
+// Error codes for invalid parameters.
+const (
+	CodeInvalidAccountID       ErrorCode = "invalid_account_id"
+	CodeInvalidAppealID        ErrorCode = "invalid_appeal_id"
+	CodeInvalidCheckerID       ErrorCode = "invalid_checker_id"
+	CodeInvalidChunkOffset     ErrorCode = "invalid_chunk_offset"
+	CodeInvalidChunkSize       ErrorCode = "invalid_chunk_size"
+	CodeInvalidCompilerID      ErrorCode = "invalid_compiler_id"
+	CodeInvalidContestID       ErrorCode = "invalid_contest_id"
+	CodeInvalidContestTemplate ErrorCode = "invalid_contest_template"
+	CodeInvalidEditorialID     ErrorCode = "invalid_editorial_id"
+	CodeInvalidFileID          ErrorCode = "invalid_file_id"
+	CodeInvalidGrantID         ErrorCode = "invalid_grant_id"
+	CodeInvalidGroupID         ErrorCode = "invalid_group_id"
+	CodeInvalidGroupMemberID   ErrorCode = "invalid_group_member_id"
+	CodeInvalidLabels          ErrorCode = "invalid_labels"
+	CodeInvalidOrganizationID  ErrorCode = "invalid_organization_id"
+	CodeInvalidOverrideID      ErrorCode = "invalid_override_id"
+	CodeInvalidParticipantID   ErrorCode = "invalid_participant_id"
+	CodeInvalidPassword        ErrorCode = "invalid_password"
+	CodeInvalidPostID          ErrorCode = "invalid_post_id"
+	CodeInvalidProblemID       ErrorCode = "invalid_problem_id"
+	CodeInvalidProblemPackage  ErrorCode = "invalid_problem_package"
+	CodeInvalidScopeID         ErrorCode = "invalid_scope_id"
+	CodeInvalidSignature       ErrorCode = "invalid_signature"
+	CodeInvalidSolutionID      ErrorCode = "invalid_solution_id"
+	CodeInvalidStatement       ErrorCode = "invalid_statement"
+	CodeInvalidTaskID          ErrorCode = "invalid_task_id"
+	CodeInvalidTestID          ErrorCode = "invalid_test_id"
+	CodeInvalidTestNumber      ErrorCode = "invalid_test_number"
+	CodeInvalidTokenID         ErrorCode = "invalid_token_id"
+	CodeInvalidUploadID        ErrorCode = "invalid_upload_id"
+	CodeInvalidUserID          ErrorCode = "invalid_user_id"
+)
+
+// Error codes for specific conditions.
+const (
+	CodeAccountLocked            ErrorCode = "account_locked"
+	CodeAppealAlreadyFiled       ErrorCode = "appeal_already_filed"
+	CodeAppealAlreadyResolved    ErrorCode = "appeal_already_resolved"
+	CodeBuiltinRole              ErrorCode = "builtin_role"
+	CodeChildRoleAlreadyExists   ErrorCode = "child_role_already_exists"
+	CodeChunkChecksumMismatch    ErrorCode = "chunk_checksum_mismatch"
+	CodeChunkTooLarge            ErrorCode = "chunk_too_large"
+	CodeCompilerNotAllowed       ErrorCode = "compiler_not_allowed"
+	CodeContestAlreadyFinalized  ErrorCode = "contest_already_finalized"
+	CodeContestNotFinalized      ErrorCode = "contest_not_finalized"
+	CodeContestNotFinished       ErrorCode = "contest_not_finished"
+	CodeDraftTooLarge            ErrorCode = "draft_too_large"
+	CodeDuplicateProblem         ErrorCode = "duplicate_problem"
+	CodeEmptySettingKey          ErrorCode = "empty_setting_key"
+	CodeFeedbackDisabled         ErrorCode = "feedback_disabled"
+	CodeFileEmpty                ErrorCode = "file_empty"
+	CodeFileTooLarge             ErrorCode = "file_too_large"
+	CodeGrantAlreadyExists       ErrorCode = "grant_already_exists"
+	CodeMessageNotQuestion       ErrorCode = "message_not_question"
+	CodeMissingContestProblems   ErrorCode = "missing_contest_problems"
+	CodeParticipantAlreadyExists ErrorCode = "participant_already_exists"
+	CodePasswordNotSet           ErrorCode = "password_not_set"
+	CodeProblemAlreadyExists     ErrorCode = "problem_already_exists"
+	CodeProblemWithoutPackage    ErrorCode = "problem_without_package"
+	CodeRoleAlreadyExists        ErrorCode = "role_already_exists"
+	CodeRoleAlreadyGranted       ErrorCode = "role_already_granted"
+	CodeRoleNotGranted           ErrorCode = "role_not_granted"
+	CodeSamePassword             ErrorCode = "same_password"
+	CodeSolutionNotAppealable    ErrorCode = "solution_not_appealable"
+	CodeSolutionNotJudged        ErrorCode = "solution_not_judged"
+	CodeSolutionURLUnavailable   ErrorCode = "solution_url_unavailable"
+	CodeSolutionsLimitReached    ErrorCode = "solutions_limit_reached"
+	CodeStandingsDisabled        ErrorCode = "standings_disabled"
+	CodeSystemTestAlreadyStarted ErrorCode = "system_test_already_started"
+	CodeSystemTestNotStarted     ErrorCode = "system_test_not_started"
+	CodeTokenNotConsumable       ErrorCode = "token_not_consumable"
+	CodeTooManyUploads           ErrorCode = "too_many_uploads"
+	CodeUploadFinalized          ErrorCode = "upload_finalized"
+	CodeUploadIncomplete         ErrorCode = "upload_incomplete"
+	CodeUserAlreadyExists        ErrorCode = "user_already_exists"
+)
+
+// getStatusErrorCode returns error code for errors without specific code.
+func getStatusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodeRequestTooLarge
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternalError
+	}
+}
+
+// GetErrorCode returns code of error that is returned by Client.
+//
+// Empty code is returned for errors that are not API errors.
+func GetErrorCode(err error) ErrorCode {
+	var resp *errorResponse
+	if errors.As(err, &resp) {
+		return resp.ErrorCode
+	}
+	return ""
+}
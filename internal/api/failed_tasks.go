@@ -0,0 +1,310 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerFailedTaskHandlers registers handlers for dead-letter queue
+// of failed tasks.
+func (v *View) registerFailedTaskHandlers(g *echo.Group) {
+	if v.core.TaskFailures == nil {
+		return
+	}
+	g.GET(
+		"/v0/tasks/failed", v.observeFailedTasks,
+		v.extractAuth(v.sessionAuth, v.guestAuth),
+		v.requirePermission(perms.ObserveFailedTasksRole),
+	)
+	g.POST(
+		"/v0/tasks/failed/requeue", v.requeueFailedTasks,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.RequeueFailedTasksRole),
+	)
+	g.GET(
+		"/v0/tasks/failed/:task", v.observeFailedTask,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractFailedTask,
+		v.requirePermission(perms.ObserveFailedTasksRole),
+	)
+	g.POST(
+		"/v0/tasks/failed/:task/requeue", v.requeueFailedTask,
+		v.extractAuth(v.sessionAuth), v.extractFailedTask,
+		v.requirePermission(perms.RequeueFailedTasksRole),
+	)
+}
+
+// FailedTask represents task in dead-letter queue.
+type FailedTask struct {
+	ID     int64           `json:"id"`
+	Kind   string          `json:"kind"`
+	Config json.RawMessage `json:"config,omitempty"`
+	// Error contains text of task error.
+	Error string `json:"error,omitempty"`
+	// Stack contains stack trace of panicked task.
+	Stack       string                         `json:"stack,omitempty"`
+	Environment *models.TaskFailureEnvironment `json:"environment,omitempty"`
+	FailTime    int64                          `json:"fail_time,omitempty"`
+}
+
+type FailedTasks struct {
+	Tasks       []FailedTask `json:"tasks"`
+	NextBeginID int64        `json:"next_begin_id,omitempty"`
+}
+
+// RequeuedTasks represents result of failed tasks requeue.
+type RequeuedTasks struct {
+	TaskIDs []int64 `json:"task_ids"`
+}
+
+func makeFailedTask(task models.Task, failure *models.TaskFailure, full bool) FailedTask {
+	resp := FailedTask{
+		ID:   task.ID,
+		Kind: task.Kind.String(),
+	}
+	if full {
+		resp.Config = json.RawMessage(task.Config)
+	}
+	if failure != nil {
+		resp.Error = failure.Error
+		resp.FailTime = failure.FailTime
+		if full {
+			resp.Stack = failure.Stack
+			if env, err := failure.GetEnvironment(); err == nil {
+				resp.Environment = &env
+			}
+		}
+	}
+	return resp
+}
+
+const (
+	defaultFailedTaskLimit = 100
+	maxFailedTaskLimit     = 1000
+)
+
+type failedTaskFilter struct {
+	Kind    string `query:"kind"`
+	BeginID int64  `query:"begin_id"`
+	Limit   int    `query:"limit"`
+}
+
+func (f *failedTaskFilter) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
+		}
+	}
+	if f.BeginID < 0 || f.BeginID == math.MaxInt64 {
+		f.BeginID = 0
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultFailedTaskLimit
+	}
+	f.Limit = min(f.Limit, maxFailedTaskLimit)
+	return nil
+}
+
+func (f *failedTaskFilter) Filter(task models.Task) bool {
+	if f.BeginID != 0 && task.ID > f.BeginID {
+		return false
+	}
+	if f.Kind != "" && task.Kind.String() != f.Kind {
+		return false
+	}
+	return true
+}
+
+// getFailedTasks returns failed tasks sorted by ID in descending order.
+func (v *View) getFailedTasks(ctx context.Context) ([]models.Task, error) {
+	tasks, err := collectRows(v.core.Tasks.FindByStatus(ctx, models.FailedTask))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID > tasks[j].ID
+	})
+	return tasks, nil
+}
+
+func (v *View) getTaskFailure(ctx context.Context, taskID int64) (*models.TaskFailure, error) {
+	failure, err := v.core.TaskFailures.GetByTask(ctx, taskID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &failure, nil
+}
+
+func (v *View) observeFailedTasks(c echo.Context) error {
+	var filter failedTaskFilter
+	if err := filter.Parse(c); err != nil {
+		c.Logger().Warn(err)
+		return err
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	ctx := getContext(c)
+	tasks, err := v.getFailedTasks(ctx)
+	if err != nil {
+		return err
+	}
+	resp := FailedTasks{Tasks: []FailedTask{}}
+	for _, task := range tasks {
+		if !filter.Filter(task) {
+			continue
+		}
+		if len(resp.Tasks) >= filter.Limit {
+			resp.NextBeginID = task.ID
+			break
+		}
+		failure, err := v.getTaskFailure(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		resp.Tasks = append(resp.Tasks, makeFailedTask(task, failure, false))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) observeFailedTask(c echo.Context) error {
+	task, ok := c.Get(failedTaskKey).(models.Task)
+	if !ok {
+		return fmt.Errorf("task not extracted")
+	}
+	failure, err := v.getTaskFailure(getContext(c), task.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeFailedTask(task, failure, true))
+}
+
+// requeueTask moves failed task back to queue and removes its failure.
+func (v *View) requeueTask(ctx context.Context, task models.Task) error {
+	return v.core.WrapTx(ctx, func(ctx context.Context) error {
+		failure, err := v.core.TaskFailures.GetByTask(ctx, task.ID)
+		if err == nil {
+			if err := v.core.TaskFailures.Delete(ctx, failure.ID); err != nil {
+				return err
+			}
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+		task.Status = models.QueuedTask
+		task.ExpireTime = 0
+		return v.core.Tasks.Update(ctx, task)
+	})
+}
+
+func (v *View) requeueFailedTask(c echo.Context) error {
+	task, ok := c.Get(failedTaskKey).(models.Task)
+	if !ok {
+		return fmt.Errorf("task not extracted")
+	}
+	if err := v.requeueTask(getContext(c), task); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, RequeuedTasks{TaskIDs: []int64{task.ID}})
+}
+
+// RequeueFailedTasksForm represents form for bulk requeue of failed tasks.
+//
+// If TaskIDs is empty, then all failed tasks of specified kind are
+// requeued.
+type RequeueFailedTasksForm struct {
+	TaskIDs []int64 `json:"task_ids"`
+	Kind    string  `json:"kind"`
+}
+
+func (v *View) requeueFailedTasks(c echo.Context) error {
+	var form RequeueFailedTasksForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	ctx := getContext(c)
+	tasks, err := v.getFailedTasks(ctx)
+	if err != nil {
+		return err
+	}
+	ids := map[int64]struct{}{}
+	for _, id := range form.TaskIDs {
+		ids[id] = struct{}{}
+	}
+	resp := RequeuedTasks{TaskIDs: []int64{}}
+	for _, task := range tasks {
+		if len(ids) > 0 {
+			if _, ok := ids[task.ID]; !ok {
+				continue
+			}
+		}
+		if form.Kind != "" && task.Kind.String() != form.Kind {
+			continue
+		}
+		if err := v.requeueTask(ctx, task); err != nil {
+			return err
+		}
+		resp.TaskIDs = append(resp.TaskIDs, task.ID)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+const failedTaskKey = "failed_task"
+
+func (v *View) extractFailedTask(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("task"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidTaskID,
+				Message:   localize(c, "Invalid task ID."),
+			}
+		}
+		if err := syncStore(c, v.core.Tasks); err != nil {
+			return err
+		}
+		task, err := v.core.Tasks.Get(getContext(c), id)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeTaskNotFound,
+				Message:   localize(c, "Task not found."),
+			}
+		}
+		if task.Status != models.FailedTask {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeTaskNotFound,
+				Message:   localize(c, "Task not found."),
+			}
+		}
+		c.Set(failedTaskKey, task)
+		return next(c)
+	}
+}
@@ -3,11 +3,12 @@ package api
 import (
 	"database/sql"
 	"fmt"
+	"io"
 	"mime"
 	"net/http"
 	"path/filepath"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/udovin/solve/internal/models"
@@ -31,16 +32,29 @@ func (v *View) observeFileContent(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	hash := strings.Trim(c.Request().Header.Get("If-None-Match"), "\"")
-	if hash == meta.MD5 {
-		return c.NoContent(http.StatusNotModified)
+	if meta.MD5 != "" {
+		// Files are immutable, so hash of content is a strong ETag.
+		etag := fmt.Sprintf("%q", meta.MD5)
+		c.Response().Header().Set("ETag", etag)
+		if isNotModified(c.Request(), etag, time.Time{}) {
+			return c.NoContent(http.StatusNotModified)
+		}
 	}
 	content, err := v.files.DownloadFile(c.Request().Context(), file.ID)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = content.Close() }()
 	contentType := mime.TypeByExtension(filepath.Ext(meta.Name))
-	c.Response().Header().Add("ETag", fmt.Sprintf("%q", meta.MD5))
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		// Seekable content supports range requests, so large files
+		// can be downloaded partially or resumed after failures.
+		if contentType != "" {
+			c.Response().Header().Set(echo.HeaderContentType, contentType)
+		}
+		http.ServeContent(c.Response(), c.Request(), meta.Name, time.Time{}, seeker)
+		return nil
+	}
 	return c.Stream(http.StatusOK, contentType, content)
 }
 
@@ -50,8 +64,9 @@ func (v *View) extractFile(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid file ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFileID,
+				Message:   localize(c, "Invalid file ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Files); err != nil {
@@ -61,8 +76,9 @@ func (v *View) extractFile(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "File not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeFileNotFound,
+					Message:   localize(c, "File not found."),
 				}
 			}
 			return err
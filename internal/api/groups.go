@@ -126,6 +126,7 @@ func (f *UpdateGroupForm) Update(c echo.Context, o *models.Group) error {
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -138,8 +139,9 @@ type CreateGroupForm UpdateGroupForm
 func (f *CreateGroupForm) Update(c echo.Context, o *models.Group) error {
 	if f.Title == nil {
 		return &errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"title": errorField{
 					Message: localize(c, "Title is required."),
@@ -208,8 +210,9 @@ func (v *View) updateGroup(c echo.Context) error {
 			if _, err := v.core.Users.Get(getContext(c), *form.OwnerID); err != nil {
 				if err == sql.ErrNoRows {
 					return errorResponse{
-						Code:    http.StatusBadRequest,
-						Message: localize(c, "User not found."),
+						Code:      http.StatusBadRequest,
+						ErrorCode: CodeUserNotFound,
+						Message:   localize(c, "User not found."),
 					}
 				}
 				return err
@@ -220,6 +223,7 @@ func (v *View) updateGroup(c echo.Context) error {
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
@@ -285,16 +289,18 @@ func (f *CreateGroupMemberForm) Update(c echo.Context, o *models.GroupMember, co
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "User not found."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeUserNotFound,
+				Message:   localize(c, "User not found."),
 			}
 		}
 		return err
 	}
 	if account.Kind != models.UserAccountKind {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "User not found."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeUserNotFound,
+			Message:   localize(c, "User not found."),
 		}
 	}
 	if !f.Kind.IsValid() {
@@ -387,8 +393,9 @@ func (v *View) extractGroup(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid group ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidGroupID,
+				Message:   localize(c, "Invalid group ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Groups); err != nil {
@@ -398,8 +405,9 @@ func (v *View) extractGroup(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Group not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeGroupNotFound,
+					Message:   localize(c, "Group not found."),
 				}
 			}
 			return err
@@ -420,8 +428,9 @@ func (v *View) extractGroupMember(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid group member ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidGroupMemberID,
+				Message:   localize(c, "Invalid group member ID."),
 			}
 		}
 		if err := syncStore(c, v.core.GroupMembers); err != nil {
@@ -431,16 +440,18 @@ func (v *View) extractGroupMember(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Group member not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeGroupMemberNotFound,
+					Message:   localize(c, "Group member not found."),
 				}
 			}
 			return err
 		}
 		if member.GroupID != group.ID {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "Group member not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeGroupMemberNotFound,
+				Message:   localize(c, "Group member not found."),
 			}
 		}
 		c.Set(groupMemberKey, member)
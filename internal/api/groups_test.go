@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/udovin/solve/internal/models"
@@ -140,3 +141,109 @@ func TestGroupSimpleScenario(t *testing.T) {
 		}
 	}()
 }
+
+func TestGroupRolesAndParticipants(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	admin := NewTestUser(e)
+	admin.AddRoles(
+		"create_group", "observe_group_roles", "create_group_role",
+		"delete_group_role", "create_contest",
+	)
+	member := NewTestUser(e)
+	admin.LoginClient()
+	group, err := e.Client.CreateGroup(context.Background(), CreateGroupForm{
+		Title: getPtr("Test class"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateGroupMember(
+		context.Background(), group.ID,
+		CreateGroupMemberForm{Kind: models.RegularMember, AccountID: member.ID},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateGroupRole(
+		context.Background(), group.ID, "create_contest",
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateGroupRole(
+		context.Background(), group.ID, "create_contest",
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	if roles, err := e.Client.ObserveGroupRoles(context.Background(), group.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(roles.Roles) != 1 || roles.Roles[0].Name != "create_contest" {
+		t.Fatalf("Unexpected roles: %v", roles.Roles)
+	}
+	admin.LogoutClient()
+	e.SyncStores()
+	// Member inherits roles of group.
+	member.LoginClient()
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	member.LogoutClient()
+	admin.LoginClient()
+	if _, err := e.Client.DeleteGroupRole(
+		context.Background(), group.ID, "create_contest",
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Contest of other user requires grant.
+	if _, err := e.Client.CreateContestGroupParticipants(
+		context.Background(), contest.ID, group.ID,
+		CreateContestGroupParticipantsForm{},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	ownContest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participants, err := e.Client.CreateContestGroupParticipants(
+		context.Background(), ownContest.ID, group.ID,
+		CreateContestGroupParticipantsForm{},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(participants.Participants) != 1 ||
+		participants.Participants[0].User == nil ||
+		participants.Participants[0].User.ID != member.ID {
+		t.Fatalf("Unexpected participants: %v", participants.Participants)
+	}
+	// Existing participants are skipped.
+	participants, err = e.Client.CreateContestGroupParticipants(
+		context.Background(), ownContest.ID, group.ID,
+		CreateContestGroupParticipantsForm{},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(participants.Participants) != 0 {
+		t.Fatalf("Unexpected participants: %v", participants.Participants)
+	}
+	admin.LogoutClient()
+	e.SyncStores()
+	member.LoginClient()
+	if _, err := e.Client.CreateContest(testSimpleContest); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerInvokerSelfTestHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/compilers/:compiler/self-test", v.createInvokerSelfTest,
+		v.extractAuth(v.sessionAuth), v.extractCompiler,
+		v.requirePermission(perms.UpdateCompilerRole),
+	)
+	g.GET(
+		"/v0/compilers/:compiler/self-test/:task", v.observeInvokerSelfTest,
+		v.extractAuth(v.sessionAuth), v.extractCompiler,
+		v.requirePermission(perms.UpdateCompilerRole),
+	)
+}
+
+// InvokerSelfTest represents self-test of invoker with compiler.
+type InvokerSelfTest struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Stage  string `json:"stage,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Invoker contains name of invoker that has run self-test.
+	Invoker string `json:"invoker,omitempty"`
+	// CompileTime contains time of compilation in milliseconds.
+	CompileTime int64 `json:"compile_time,omitempty"`
+	// Tests contains amount of passed tests.
+	Tests     int   `json:"tests,omitempty"`
+	MinTime   int64 `json:"min_time,omitempty"`
+	MaxTime   int64 `json:"max_time,omitempty"`
+	AvgTime   int64 `json:"avg_time,omitempty"`
+	MaxMemory int64 `json:"max_memory,omitempty"`
+	// TimeFactor contains saved time factor of invoker in per mille.
+	TimeFactor int64 `json:"time_factor,omitempty"`
+}
+
+func makeInvokerSelfTest(task models.Task) InvokerSelfTest {
+	resp := InvokerSelfTest{
+		ID:     task.ID,
+		Status: task.Status.String(),
+	}
+	var state models.SelfTestInvokerTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Stage = state.Stage
+		resp.Error = state.Error
+		resp.Invoker = state.Invoker
+		resp.CompileTime = state.CompileTime
+		resp.Tests = state.Tests
+		resp.MinTime = state.MinTime
+		resp.MaxTime = state.MaxTime
+		resp.AvgTime = state.AvgTime
+		resp.MaxMemory = state.MaxMemory
+		resp.TimeFactor = state.TimeFactor
+	}
+	return resp
+}
+
+// CreateInvokerSelfTestForm represents form for running self-test.
+type CreateInvokerSelfTestForm struct {
+	// Invoker contains name of invoker that should run self-test.
+	//
+	// Empty invoker means that self-test can be run by any invoker.
+	Invoker string `json:"invoker"`
+	// Source contains solution of A+B problem.
+	//
+	// Built-in solution for language of compiler is used if empty.
+	Source string `json:"source"`
+	Tests  int    `json:"tests"`
+	// Reference means that invoker is reference machine for calibration
+	// of other invokers.
+	Reference bool `json:"reference"`
+	// Calibrate enables update of invoker time factor, that is applied
+	// to time limits of tests.
+	Calibrate bool `json:"calibrate"`
+}
+
+const maxInvokerSelfTestTests = 1000
+
+func (f CreateInvokerSelfTestForm) Update(
+	c echo.Context, config *models.SelfTestInvokerTaskConfig,
+) error {
+	errors := errorFields{}
+	if len(f.Invoker) > 256 {
+		errors["invoker"] = errorField{
+			Message: localize(c, "Invoker is too long."),
+		}
+	}
+	if len(f.Source) > maxSolutionFileSize {
+		errors["source"] = errorField{
+			Message: localize(c, "Source is too large."),
+		}
+	}
+	if f.Tests < 0 || f.Tests > maxInvokerSelfTestTests {
+		errors["tests"] = errorField{
+			Message: localize(
+				c, "Amount of tests should be between {min} and {max}.",
+				replaceField("min", 0), replaceField("max", maxInvokerSelfTestTests),
+			),
+		}
+	}
+	if f.Reference && f.Calibrate {
+		errors["calibrate"] = errorField{
+			Message: localize(c, "Reference invoker cannot be calibrated."),
+		}
+	}
+	if (f.Reference || f.Calibrate) && f.Invoker == "" {
+		errors["invoker"] = errorField{
+			Message: localize(c, "Invoker is required for calibration."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config.Invoker = f.Invoker
+	config.Source = f.Source
+	config.Tests = f.Tests
+	config.Reference = f.Reference
+	config.Calibrate = f.Calibrate
+	return nil
+}
+
+func (v *View) createInvokerSelfTest(c echo.Context) error {
+	compiler, ok := c.Get(compilerKey).(models.Compiler)
+	if !ok {
+		return fmt.Errorf("compiler not extracted")
+	}
+	var form CreateInvokerSelfTestForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	config := models.SelfTestInvokerTaskConfig{CompilerID: compiler.ID}
+	if err := form.Update(c, &config); err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := task.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeInvokerSelfTest(task))
+}
+
+func (v *View) observeInvokerSelfTest(c echo.Context) error {
+	compiler, ok := c.Get(compilerKey).(models.Compiler)
+	if !ok {
+		return fmt.Errorf("compiler not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTaskID,
+			Message:   localize(c, "Invalid task ID."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	task, err := v.core.Tasks.Get(getContext(c), id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	var config models.SelfTestInvokerTaskConfig
+	if task.Kind != models.SelfTestInvokerTask ||
+		task.ScanConfig(&config) != nil || config.CompilerID != compiler.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeInvokerSelfTest(task))
+}
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerLoginLockoutHandlers(g *echo.Group) {
+	if v.core.Users == nil {
+		return
+	}
+	g.GET(
+		"/v0/users/:user/lock", v.observeUserLock,
+		v.extractAuth(v.sessionAuth), v.extractUser,
+		v.requirePermission(perms.UpdateUserRole, perms.UpdateUserStatusRole),
+	)
+	g.DELETE(
+		"/v0/users/:user/lock", v.unlockUser,
+		v.extractAuth(v.sessionAuth), v.extractUser,
+		v.requirePermission(perms.UpdateUserRole, perms.UpdateUserStatusRole),
+	)
+}
+
+// loginLockoutPolicy represents policy of account lockout.
+type loginLockoutPolicy struct {
+	// Attempts contains amount of failed attempts after which account
+	// is locked.
+	Attempts int64
+	// Window contains duration in seconds after which failed attempts
+	// are forgotten.
+	Window int64
+	// Duration contains duration of lock in seconds.
+	Duration int64
+	// AddressAttempts contains amount of failed attempts from single
+	// IP address after which address is blocked.
+	AddressAttempts int64
+}
+
+func (v *View) getLoginLockoutPolicy() loginLockoutPolicy {
+	policy := loginLockoutPolicy{
+		Attempts:        10,
+		Window:          15 * 60,
+		Duration:        15 * 60,
+		AddressAttempts: 50,
+	}
+	getValue := func(key string, value *int64) {
+		if setting, err := v.core.Settings.GetInt64(key); err == nil {
+			if intValue := setting.OrElse(0); intValue > 0 {
+				*value = intValue
+			}
+		}
+	}
+	getValue("accounts.lockout.attempts", &policy.Attempts)
+	getValue("accounts.lockout.window", &policy.Window)
+	getValue("accounts.lockout.duration", &policy.Duration)
+	getValue("accounts.lockout.address_attempts", &policy.AddressAttempts)
+	return policy
+}
+
+type addressAttempts struct {
+	Count int64
+	Time  int64
+}
+
+// loginAddressTracker tracks failed login attempts per IP address.
+type loginAddressTracker struct {
+	mutex     sync.Mutex
+	addresses map[string]addressAttempts
+}
+
+func newLoginAddressTracker() *loginAddressTracker {
+	return &loginAddressTracker{addresses: map[string]addressAttempts{}}
+}
+
+// IsBlocked returns true if address has too many recent failed attempts.
+func (t *loginAddressTracker) IsBlocked(
+	address string, now int64, policy loginLockoutPolicy,
+) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	attempts, ok := t.addresses[address]
+	if !ok {
+		return false
+	}
+	if attempts.Time+policy.Window <= now {
+		delete(t.addresses, address)
+		return false
+	}
+	return attempts.Count >= policy.AddressAttempts
+}
+
+// AddFailed registers failed attempt from address.
+func (t *loginAddressTracker) AddFailed(
+	address string, now int64, policy loginLockoutPolicy,
+) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, attempts := range t.addresses {
+		if attempts.Time+policy.Window <= now {
+			delete(t.addresses, key)
+		}
+	}
+	attempts := t.addresses[address]
+	attempts.Count++
+	attempts.Time = now
+	t.addresses[address] = attempts
+}
+
+func (v *View) logLoginAttempt(
+	c echo.Context, accountID int64, status models.LoginAttemptStatus,
+) {
+	attempt := models.LoginAttempt{
+		Time:      getNow(c).Unix(),
+		AccountID: accountID,
+		Status:    status,
+		UserAgent: c.Request().UserAgent(),
+		RealIP:    c.RealIP(),
+	}
+	if err := v.core.LoginAttempts.Create(getContext(c), &attempt); err != nil {
+		c.Logger().Warn("Cannot log login attempt", err)
+	}
+}
+
+// checkLoginLock returns error if account or address is locked.
+func (v *View) checkLoginLock(c echo.Context, user models.User) error {
+	policy := v.getLoginLockoutPolicy()
+	now := getNow(c).Unix()
+	if v.loginAddresses.IsBlocked(c.RealIP(), now, policy) {
+		v.logLoginAttempt(c, user.ID, models.LockedLoginAttempt)
+		return errorResponse{
+			Code:      http.StatusTooManyRequests,
+			ErrorCode: CodeTooManyRequests,
+			Message:   localize(c, "Too many requests."),
+		}
+	}
+	if err := syncStore(c, v.core.AccountLocks); err != nil {
+		return err
+	}
+	lock, err := v.core.AccountLocks.GetByAccount(user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	if lock.IsLocked(now) {
+		v.logLoginAttempt(c, user.ID, models.LockedLoginAttempt)
+		return errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeAccountLocked,
+			Message:   localize(c, "Account is temporarily locked."),
+		}
+	}
+	return nil
+}
+
+// registerFailedLogin updates lock of account after failed attempt.
+func (v *View) registerFailedLogin(c echo.Context, user models.User) error {
+	v.logLoginAttempt(c, user.ID, models.FailedLoginAttempt)
+	policy := v.getLoginLockoutPolicy()
+	now := getNow(c).Unix()
+	v.loginAddresses.AddFailed(c.RealIP(), now, policy)
+	lock, err := v.core.AccountLocks.GetByAccount(user.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if lock.AttemptTime+policy.Window <= now {
+		lock.FailedAttempts = 0
+	}
+	lock.AccountID = user.ID
+	lock.FailedAttempts++
+	lock.AttemptTime = now
+	locked := false
+	if lock.FailedAttempts >= policy.Attempts {
+		lock.FailedAttempts = 0
+		lock.ExpireTime = models.NInt64(now + policy.Duration)
+		locked = true
+	}
+	if lock.ID == 0 {
+		err = v.core.AccountLocks.Create(getContext(c), &lock)
+	} else {
+		err = v.core.AccountLocks.Update(getContext(c), lock)
+	}
+	if err != nil {
+		return err
+	}
+	if locked {
+		v.core.Logger().Warn(
+			"Account locked",
+			logs.Any("account_id", user.ID),
+			logs.Any("expire_time", lock.ExpireTime),
+		)
+		v.sendAccountLockedMail(c, user, lock)
+	}
+	return nil
+}
+
+// registerSucceededLogin resets failed attempts of account.
+func (v *View) registerSucceededLogin(c echo.Context, user models.User) error {
+	v.logLoginAttempt(c, user.ID, models.SucceededLoginAttempt)
+	lock, err := v.core.AccountLocks.GetByAccount(user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	return v.core.AccountLocks.Delete(getContext(c), lock.ID)
+}
+
+func (v *View) sendAccountLockedMail(
+	c echo.Context, user models.User, lock models.AccountLock,
+) {
+	cfg := v.core.Config.SMTP
+	if cfg == nil || user.Email == "" {
+		return
+	}
+	to := mail.Address{Address: string(user.Email)}
+	values := map[string]any{
+		"login":       user.Login,
+		"real_ip":     c.RealIP(),
+		"expire_time": time.Unix(int64(lock.ExpireTime), 0).UTC().Format(time.RFC3339),
+	}
+	if err := v.sendMail(
+		c, cfg, to, "account_locked", values,
+		"Account is locked on Solve",
+		"Your account {{.login}} is locked until {{.expire_time}} because of too many failed login attempts from {{.real_ip}}.",
+	); err != nil {
+		c.Logger().Error("Cannot send account lock mail", err)
+	}
+}
+
+// UserLock represents lock of user account.
+type UserLock struct {
+	Locked         bool  `json:"locked"`
+	FailedAttempts int64 `json:"failed_attempts,omitempty"`
+	ExpireTime     int64 `json:"expire_time,omitempty"`
+}
+
+func (v *View) getUserLock(c echo.Context, user models.User) (UserLock, error) {
+	if err := syncStore(c, v.core.AccountLocks); err != nil {
+		return UserLock{}, err
+	}
+	lock, err := v.core.AccountLocks.GetByAccount(user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return UserLock{}, nil
+		}
+		return UserLock{}, err
+	}
+	resp := UserLock{FailedAttempts: lock.FailedAttempts}
+	if lock.IsLocked(getNow(c).Unix()) {
+		resp.Locked = true
+		resp.ExpireTime = int64(lock.ExpireTime)
+	}
+	return resp, nil
+}
+
+func (v *View) observeUserLock(c echo.Context) error {
+	user, ok := c.Get(userKey).(models.User)
+	if !ok {
+		return fmt.Errorf("user not extracted")
+	}
+	resp, err := v.getUserLock(c, user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) unlockUser(c echo.Context) error {
+	user, ok := c.Get(userKey).(models.User)
+	if !ok {
+		return fmt.Errorf("user not extracted")
+	}
+	if err := syncStore(c, v.core.AccountLocks); err != nil {
+		return err
+	}
+	lock, err := v.core.AccountLocks.GetByAccount(user.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusOK, UserLock{})
+		}
+		return err
+	}
+	if err := v.core.AccountLocks.Delete(getContext(c), lock.ID); err != nil {
+		return err
+	}
+	v.core.Logger().Info(
+		"Account unlocked",
+		logs.Any("account_id", user.ID),
+	)
+	return c.JSON(http.StatusOK, UserLock{})
+}
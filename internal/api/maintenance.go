@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// readOnlySetting contains key of setting that enables read-only mode.
+const readOnlySetting = "server.read_only"
+
+// SetReadOnly enables or disables read-only mode of current server
+// instance.
+//
+// Read-only mode is enabled if it is enabled either by this switch
+// or by setting.
+func (v *View) SetReadOnly(readOnly bool) {
+	v.readOnly.Store(readOnly)
+}
+
+// IsReadOnly returns true if API is in read-only mode.
+func (v *View) IsReadOnly(logger echo.Logger) bool {
+	if v.readOnly.Load() {
+		return true
+	}
+	return v.getBoolSetting(readOnlySetting, logger).OrElse(false)
+}
+
+// isMutatingMethod returns true if requests with specified method
+// can modify data.
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// readOnlyExemptPaths contains paths of handlers that are allowed in
+// read-only mode.
+//
+// Settings can be modified, so read-only mode enabled by setting
+// can be disabled by administrator. Login and logout are allowed,
+// so administrator can get session for that.
+var readOnlyExemptPaths = []string{
+	"/api/v0/login",
+	"/api/v0/logout",
+	"/api/v0/settings",
+	"/api/v1/login",
+	"/api/v1/logout",
+	"/api/v1/settings",
+}
+
+// isReadOnlyExempt returns true if request is allowed in read-only mode.
+func isReadOnlyExempt(path string) bool {
+	for _, exempt := range readOnlyExemptPaths {
+		if path == exempt || strings.HasPrefix(path, exempt+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReadOnly rejects mutating requests in read-only mode.
+func (v *View) checkReadOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if isMutatingMethod(c.Request().Method) &&
+			!isReadOnlyExempt(c.Path()) && v.IsReadOnly(c.Logger()) {
+			return errorResponse{
+				Code:      http.StatusServiceUnavailable,
+				ErrorCode: CodeReadOnlyMode,
+				Message: localize(
+					c, "Service is in read-only mode due to maintenance. Please try again later.",
+				),
+			}
+		}
+		return next(c)
+	}
+}
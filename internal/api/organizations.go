@@ -0,0 +1,267 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerOrganizationHandlers registers handlers for directory of
+// organizations.
+func (v *View) registerOrganizationHandlers(g *echo.Group) {
+	if v.core.Organizations == nil {
+		return
+	}
+	g.GET(
+		"/v0/organizations", v.observeOrganizations,
+		v.extractAuth(v.sessionAuth, v.guestAuth),
+		v.requirePermission(perms.ObserveOrganizationsRole),
+	)
+	g.POST(
+		"/v0/organizations", v.createOrganization,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.CreateOrganizationRole),
+	)
+	g.PATCH(
+		"/v0/organizations/:organization", v.updateOrganization,
+		v.extractAuth(v.sessionAuth), v.extractOrganization,
+		v.requirePermission(perms.UpdateOrganizationRole),
+	)
+	g.DELETE(
+		"/v0/organizations/:organization", v.deleteOrganization,
+		v.extractAuth(v.sessionAuth), v.extractOrganization,
+		v.requirePermission(perms.DeleteOrganizationRole),
+	)
+}
+
+// Organization represents organization of users.
+type Organization struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country string `json:"country,omitempty"`
+}
+
+type Organizations struct {
+	Organizations []Organization `json:"organizations"`
+}
+
+func makeOrganization(organization models.Organization) Organization {
+	return Organization{
+		ID:      organization.ID,
+		Title:   organization.Title,
+		Country: string(organization.Country),
+	}
+}
+
+func (v *View) observeOrganizations(c echo.Context) error {
+	if err := syncStore(c, v.core.Organizations); err != nil {
+		return err
+	}
+	organizations, err := v.core.Organizations.All(getContext(c), 0, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = organizations.Close() }()
+	resp := Organizations{Organizations: []Organization{}}
+	for organizations.Next() {
+		resp.Organizations = append(
+			resp.Organizations, makeOrganization(organizations.Row()),
+		)
+	}
+	if err := organizations.Err(); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type UpdateOrganizationForm struct {
+	Title   *string `json:"title"`
+	Country *string `json:"country"`
+}
+
+func (f *UpdateOrganizationForm) Update(c echo.Context, o *models.Organization) error {
+	errors := errorFields{}
+	if f.Title != nil {
+		title := []rune(*f.Title)
+		if len(title) < 2 {
+			errors["title"] = errorField{
+				Message: localize(c, "Title is too short."),
+			}
+		} else if len(title) > 128 {
+			errors["title"] = errorField{
+				Message: localize(c, "Title is too long."),
+			}
+		}
+		o.Title = *f.Title
+	}
+	if f.Country != nil {
+		validateCountry(c, errors, *f.Country)
+		o.Country = models.NString(*f.Country)
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+type CreateOrganizationForm UpdateOrganizationForm
+
+func (f *CreateOrganizationForm) Update(c echo.Context, o *models.Organization) error {
+	if f.Title == nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"title": errorField{
+					Message: localize(c, "Title is required."),
+				},
+			},
+		}
+	}
+	return (*UpdateOrganizationForm)(f).Update(c, o)
+}
+
+func (v *View) createOrganization(c echo.Context) error {
+	var form CreateOrganizationForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	var organization models.Organization
+	if err := form.Update(c, &organization); err != nil {
+		return err
+	}
+	if err := v.core.Organizations.Create(getContext(c), &organization); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeOrganization(organization))
+}
+
+func (v *View) updateOrganization(c echo.Context) error {
+	organization, ok := c.Get(organizationKey).(models.Organization)
+	if !ok {
+		return fmt.Errorf("organization not extracted")
+	}
+	var form UpdateOrganizationForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Update(c, &organization); err != nil {
+		return err
+	}
+	if err := v.core.Organizations.Update(getContext(c), organization); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeOrganization(organization))
+}
+
+// deleteOrganization deletes organization from directory.
+//
+// Users keep ID of deleted organization, but it is not displayed.
+func (v *View) deleteOrganization(c echo.Context) error {
+	organization, ok := c.Get(organizationKey).(models.Organization)
+	if !ok {
+		return fmt.Errorf("organization not extracted")
+	}
+	if err := v.core.Organizations.Delete(getContext(c), organization.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeOrganization(organization))
+}
+
+// validateCountry validates ISO 3166-1 alpha-2 code of country.
+//
+// Empty code means that country is not specified.
+func validateCountry(c echo.Context, errors errorFields, country string) {
+	if country == "" {
+		return
+	}
+	valid := len(country) == 2
+	for _, c := range country {
+		if c < 'A' || c > 'Z' {
+			valid = false
+		}
+	}
+	if !valid {
+		errors["country"] = errorField{
+			Message: localize(c, "Invalid country code."),
+		}
+	}
+}
+
+// validateOrganization checks that organization exists.
+//
+// Zero ID means that organization is not specified.
+func validateOrganization(
+	c echo.Context, errors errorFields,
+	organizations *models.OrganizationStore, id int64,
+) error {
+	if id == 0 {
+		return nil
+	}
+	if err := syncStore(c, organizations); err != nil {
+		return err
+	}
+	if _, err := organizations.Get(getContext(c), id); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		errors["organization_id"] = errorField{
+			Message: localize(c, "Organization not found."),
+		}
+	}
+	return nil
+}
+
+const organizationKey = "organization"
+
+func (v *View) extractOrganization(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("organization"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidOrganizationID,
+				Message:   localize(c, "Invalid organization ID."),
+			}
+		}
+		if err := syncStore(c, v.core.Organizations); err != nil {
+			return err
+		}
+		organization, err := v.core.Organizations.Get(getContext(c), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeOrganizationNotFound,
+					Message:   localize(c, "Organization not found."),
+				}
+			}
+			return err
+		}
+		c.Set(organizationKey, organization)
+		return next(c)
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestOrganizations(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles(
+		"create_organization", "update_organization", "delete_organization",
+		"create_contest",
+	)
+	user := NewTestUser(e)
+	owner.LoginClient()
+	if _, err := e.Client.CreateOrganization(
+		context.Background(), CreateOrganizationForm{},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := e.Client.CreateOrganization(
+		context.Background(), CreateOrganizationForm{
+			Title:   getPtr("Test university"),
+			Country: getPtr("usa"),
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	organization, err := e.Client.CreateOrganization(
+		context.Background(), CreateOrganizationForm{
+			Title:   getPtr("Test university"),
+			Country: getPtr("RU"),
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(organization)
+	removed, err := e.Client.CreateOrganization(
+		context.Background(), CreateOrganizationForm{
+			Title: getPtr("Removed university"),
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.DeleteOrganization(context.Background(), removed.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if v, err := e.Client.UpdateOrganization(
+		context.Background(), organization.ID, UpdateOrganizationForm{
+			Title: getPtr("Updated university"),
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	user.LoginClient()
+	if v, err := e.Client.ObserveOrganizations(context.Background()); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	if _, err := e.Client.UpdateUser(
+		context.Background(), user.Login, updateUserForm{
+			OrganizationID: getPtr(removed.ID),
+		},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	if v, err := e.Client.UpdateUser(
+		context.Background(), user.Login, updateUserForm{
+			OrganizationID: getPtr(organization.ID),
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	e.SyncStores()
+	if v, err := e.Client.CreateContestParticipant(
+		context.Background(), contest.ID, CreateContestParticipantForm{
+			AccountID: user.ID,
+			Kind:      models.RegularParticipant,
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(v)
+	}
+}
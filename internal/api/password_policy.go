@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/pkg/passwords"
+)
+
+var breachesClient = &http.Client{Timeout: 5 * time.Second}
+
+// checkPasswordPolicy returns error if password does not satisfy
+// password policy.
+//
+// Inputs contain user specific words like login or email that should
+// not be used in password. Password is considered valid when breaches
+// API is unavailable.
+func (v *View) checkPasswordPolicy(
+	c echo.Context, password string, inputs ...string,
+) error {
+	security := v.core.Config.Security
+	if security == nil || security.PasswordPolicy == nil {
+		return nil
+	}
+	policy := security.PasswordPolicy
+	errors := errorFields{}
+	if len(password) < policy.MinLength {
+		errors["password"] = errorField{
+			Message: localize(c, "Password too short."),
+		}
+	} else if passwords.Score(password, inputs...) < policy.MinScore {
+		errors["password"] = errorField{
+			Message: localize(c, "Password is too weak."),
+		}
+	} else if policy.CheckBreaches {
+		url := policy.BreachesURL
+		if url == "" {
+			url = passwords.DefaultBreachesURL
+		}
+		count, err := passwords.CountBreaches(
+			getContext(c), breachesClient, url, password,
+		)
+		if err != nil {
+			c.Logger().Warn("Cannot check password breaches", err)
+		} else if count > 0 {
+			errors["password"] = errorField{
+				Message: localize(c, "Password is found in data breaches."),
+			}
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
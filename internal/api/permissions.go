@@ -0,0 +1,151 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// registerPermissionHandlers registers handlers for introspection of
+// effective permissions.
+func (v *View) registerPermissionHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/roles/permissions", v.observeRolePermissions,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.ObserveRolesRole, perms.ObserveRoleRolesRole),
+	)
+	g.GET(
+		"/v0/accounts/:account/permissions", v.observeAccountPermissions,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.ObserveRoleRolesRole, perms.ObserveUserRolesRole),
+	)
+}
+
+// RolePermissions represents role with expanded permissions.
+type RolePermissions struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	BuiltIn bool   `json:"built_in,omitempty"`
+	// Permissions contains built-in roles that are reachable from role.
+	Permissions []string `json:"permissions"`
+}
+
+// RolePermissionsMatrix represents expanded permissions of all roles.
+type RolePermissionsMatrix struct {
+	Roles []RolePermissions `json:"roles"`
+}
+
+// AccountPermissions represents effective permissions of account.
+type AccountPermissions struct {
+	ID          int64       `json:"id"`
+	Kind        AccountKind `json:"kind"`
+	Permissions []string    `json:"permissions"`
+}
+
+func getSortedPermissions(permissions perms.PermissionSet) []string {
+	result := make([]string, 0, len(permissions))
+	for name := range permissions {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (v *View) observeRolePermissions(c echo.Context) error {
+	for _, store := range []models.CachedStore{v.core.Roles, v.core.RoleEdges} {
+		if err := syncStore(c, store); err != nil {
+			return err
+		}
+	}
+	roles, err := collectRows(v.core.Roles.All(getContext(c), 0, 0))
+	if err != nil {
+		return err
+	}
+	resp := RolePermissionsMatrix{Roles: []RolePermissions{}}
+	for _, role := range roles {
+		permissions, err := v.accounts.GetRecursivePermissions(
+			getContext(c), []int64{role.ID},
+		)
+		if err != nil {
+			return err
+		}
+		resp.Roles = append(resp.Roles, RolePermissions{
+			ID:          role.ID,
+			Name:        role.Name,
+			BuiltIn:     perms.IsBuiltInRole(role.Name),
+			Permissions: getSortedPermissions(permissions),
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// observeAccountPermissions returns permissions of account that are
+// granted by roles, so it can be checked why account lacks permission.
+//
+// Permissions of scopes and groups are permissions inherited by their
+// members.
+func (v *View) observeAccountPermissions(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("account"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidAccountID,
+			Message:   localize(c, "Invalid account ID."),
+		}
+	}
+	for _, store := range []models.CachedStore{
+		v.core.Accounts, v.core.Roles, v.core.RoleEdges, v.core.AccountRoles,
+	} {
+		if err := syncStore(c, store); err != nil {
+			return err
+		}
+	}
+	ctx := getContext(c)
+	account, err := v.core.Accounts.Get(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeAccountNotFound,
+				Message:   localize(c, "Account not found."),
+			}
+		}
+		return err
+	}
+	var permissions perms.PermissionSet
+	switch account.Kind {
+	case models.UserAccountKind, models.ScopeUserAccountKind:
+		accountCtx, err := v.accounts.MakeContext(ctx, &account)
+		if err != nil {
+			return err
+		}
+		permissions = accountCtx.Permissions
+	case models.ScopeAccountKind, models.GroupAccountKind:
+		edges, err := collectRows(v.core.AccountRoles.FindByAccount(ctx, account.ID))
+		if err != nil {
+			return err
+		}
+		var roleIDs []int64
+		for _, edge := range edges {
+			roleIDs = append(roleIDs, edge.RoleID)
+		}
+		permissions, err = v.accounts.GetRecursivePermissions(ctx, roleIDs)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown account kind: %v", account.Kind)
+	}
+	return c.JSON(http.StatusOK, AccountPermissions{
+		ID:          account.ID,
+		Kind:        account.Kind,
+		Permissions: getSortedPermissions(permissions),
+	})
+}
@@ -29,6 +29,7 @@ func (v *View) registerPostHandlers(g *echo.Group) {
 		"/v0/posts", v.createPost,
 		v.extractAuth(v.sessionAuth),
 		v.requirePermission(perms.CreatePostRole),
+		v.limitUploadBody,
 	)
 	g.GET(
 		"/v0/posts/:post", v.observePost,
@@ -39,6 +40,7 @@ func (v *View) registerPostHandlers(g *echo.Group) {
 		"/v0/posts/:post", v.updatePost,
 		v.extractAuth(v.sessionAuth), v.extractPost,
 		v.requirePermission(perms.UpdatePostRole),
+		v.limitUploadBody,
 	)
 	g.DELETE(
 		"/v0/posts/:post", v.deletePost,
@@ -72,8 +74,9 @@ const (
 func (f *postsFilter) Parse(c echo.Context) error {
 	if err := c.Bind(f); err != nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid filter."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
 		}
 	}
 	if f.BeginID < 0 || f.BeginID == math.MaxInt64 {
@@ -210,8 +213,9 @@ func (f *CreatePostForm) Parse(c echo.Context) error {
 	for i := range f.Files {
 		if _, ok := uploadedFiles[f.Files[i].Name]; ok {
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Form has invalid fields."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
 				InvalidFields: errorFields{
 					"files": {
 						Message: localize(c, "Form has invalid fields."),
@@ -268,6 +272,7 @@ func (f *CreatePostForm) Update(c echo.Context, post *models.Post) error {
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -359,8 +364,9 @@ func (f *UpdatePostForm) Parse(c echo.Context) error {
 	for i := range f.Files {
 		if _, ok := uploadFiles[f.Files[i].Name]; ok {
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Form has invalid fields."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
 				InvalidFields: errorFields{
 					"files": {
 						Message: localize(c, "Form has invalid fields."),
@@ -421,6 +427,7 @@ func (f *UpdatePostForm) Update(c echo.Context, post *models.Post) error {
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -463,16 +470,18 @@ func (v *View) updatePost(c echo.Context) error {
 			if err != nil {
 				if err == sql.ErrNoRows {
 					return errorResponse{
-						Code:    http.StatusBadRequest,
-						Message: localize(c, "User not found."),
+						Code:      http.StatusBadRequest,
+						ErrorCode: CodeUserNotFound,
+						Message:   localize(c, "User not found."),
 					}
 				}
 				return err
 			}
 			if account.Kind != models.UserAccountKind {
 				return errorResponse{
-					Code:    http.StatusBadRequest,
-					Message: localize(c, "User not found."),
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeUserNotFound,
+					Message:   localize(c, "User not found."),
 				}
 			}
 			post.OwnerID = models.NInt64(*form.OwnerID)
@@ -481,6 +490,7 @@ func (v *View) updatePost(c echo.Context) error {
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
@@ -491,8 +501,9 @@ func (v *View) updatePost(c echo.Context) error {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusBadRequest,
-					Message: localize(c, "Form has invalid fields."),
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeInvalidFields,
+					Message:   localize(c, "Form has invalid fields."),
 					InvalidFields: errorFields{
 						"delete_files": {
 							Message: localize(c, "Form has invalid fields."),
@@ -504,8 +515,9 @@ func (v *View) updatePost(c echo.Context) error {
 		}
 		if file.PostID != post.ID {
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Form has invalid fields."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
 				InvalidFields: errorFields{
 					"delete_files": {
 						Message: localize(c, "Form has invalid fields."),
@@ -515,8 +527,9 @@ func (v *View) updatePost(c echo.Context) error {
 		}
 		if _, ok := deleteFiles[file.Name]; ok {
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Form has invalid fields."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
 				InvalidFields: errorFields{
 					"delete_files": {
 						Message: localize(c, "Form has invalid fields."),
@@ -534,8 +547,9 @@ func (v *View) updatePost(c echo.Context) error {
 		if err != sql.ErrNoRows {
 			if err == nil {
 				return errorResponse{
-					Code:    http.StatusBadRequest,
-					Message: localize(c, "Form has invalid fields."),
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeInvalidFields,
+					Message:   localize(c, "Form has invalid fields."),
 					InvalidFields: errorFields{
 						"files": {
 							Message: localize(c, "Form has invalid fields."),
@@ -633,8 +647,9 @@ func (v *View) observePostContent(c echo.Context) error {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "File not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeFileNotFound,
+				Message:   localize(c, "File not found."),
 			}
 		}
 		return err
@@ -643,8 +658,9 @@ func (v *View) observePostContent(c echo.Context) error {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "File not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeFileNotFound,
+				Message:   localize(c, "File not found."),
 			}
 		}
 		return err
@@ -687,8 +703,9 @@ func (v *View) extractPost(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid post ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidPostID,
+				Message:   localize(c, "Invalid post ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Posts); err != nil {
@@ -698,8 +715,9 @@ func (v *View) extractPost(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Post not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodePostNotFound,
+					Message:   localize(c, "Post not found."),
 				}
 			}
 			return err
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerProblemCheckerHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/problems/:problem/checkers", v.observeProblemCheckers,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+	g.POST(
+		"/v0/problems/:problem/checkers", v.createProblemChecker,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+		v.limitUploadBody,
+	)
+	g.DELETE(
+		"/v0/problems/:problem/checkers/:checker", v.deleteProblemChecker,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.extractProblemChecker,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+}
+
+// ProblemChecker represents uploaded checker of problem.
+type ProblemChecker struct {
+	ID         int64  `json:"id"`
+	CompilerID int64  `json:"compiler_id"`
+	Status     string `json:"status"`
+	// Message contains compilation log of checker.
+	Message string `json:"message,omitempty"`
+	// Current is true when checker is used for judging.
+	Current    bool  `json:"current,omitempty"`
+	CreateTime int64 `json:"create_time"`
+}
+
+// ProblemCheckers represents versions of uploaded checker.
+type ProblemCheckers struct {
+	Checkers []ProblemChecker `json:"checkers"`
+}
+
+func makeProblemChecker(checker models.ProblemChecker, currentID int64) ProblemChecker {
+	return ProblemChecker{
+		ID:         checker.ID,
+		CompilerID: checker.CompilerID,
+		Status:     checker.Status.String(),
+		Message:    checker.Message,
+		Current:    checker.ID == currentID,
+		CreateTime: checker.CreateTime,
+	}
+}
+
+// getCurrentProblemChecker returns ID of checker that is used for
+// judging or zero if checker from package is used.
+func (v *View) getCurrentProblemChecker(c echo.Context, problemID int64) (int64, error) {
+	checker, err := v.core.ProblemCheckers.GetCompiledByProblem(
+		getContext(c), problemID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return checker.ID, nil
+}
+
+func (v *View) observeProblemCheckers(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	if err := syncStore(c, v.core.ProblemCheckers); err != nil {
+		return err
+	}
+	checkers, err := collectRows(v.core.ProblemCheckers.FindByProblem(
+		getContext(c), problem.ID,
+	))
+	if err != nil {
+		return err
+	}
+	currentID, err := v.getCurrentProblemChecker(c, problem.ID)
+	if err != nil {
+		return err
+	}
+	resp := ProblemCheckers{Checkers: []ProblemChecker{}}
+	for _, checker := range checkers {
+		resp.Checkers = append(resp.Checkers, makeProblemChecker(checker, currentID))
+	}
+	sort.Slice(resp.Checkers, func(i, j int) bool {
+		return resp.Checkers[i].ID > resp.Checkers[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateProblemCheckerForm represents form for uploading of checker.
+//
+// Checker is specified by source or by binary that is uploaded as
+// multipart file "binary_file".
+type CreateProblemCheckerForm struct {
+	CompilerID int64   `form:"compiler_id" json:"compiler_id"`
+	Source     *string `form:"source" json:"source,omitempty"`
+	// SourceFile will be initialized with the source if it is provided.
+	SourceFile *FileReader `json:"-"`
+	// BinaryFile will be initialized with the binary if it is provided.
+	BinaryFile *FileReader `json:"-"`
+}
+
+func (f *CreateProblemCheckerForm) Parse(c echo.Context, v *View) error {
+	if err := c.Bind(f); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if _, err := v.core.Compilers.Get(getContext(c), f.CompilerID); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"compiler_id": errorField{Message: localize(c, "Compiler not found.")},
+			},
+		}
+	}
+	if _, err := c.FormFile("binary_file"); f.Source == nil && err == nil {
+		binary, err := parseTestFile(c, nil, "binary")
+		if err != nil {
+			return err
+		}
+		f.BinaryFile = binary
+		return nil
+	}
+	source, err := parseTestFile(c, f.Source, "source")
+	if err != nil {
+		return err
+	}
+	f.SourceFile = source
+	return nil
+}
+
+func (f *CreateProblemCheckerForm) Close() error {
+	if f.SourceFile != nil {
+		_ = f.SourceFile.Close()
+	}
+	if f.BinaryFile != nil {
+		_ = f.BinaryFile.Close()
+	}
+	return nil
+}
+
+// createProblemChecker uploads new version of problem checker.
+//
+// Checker is used for judging only after successful compilation.
+func (v *View) createProblemChecker(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	var form CreateProblemCheckerForm
+	defer func() { _ = form.Close() }()
+	if err := form.Parse(c, v); err != nil {
+		return err
+	}
+	if problem.PackageID == 0 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeProblemWithoutPackage,
+			Message:   localize(c, "Problem does not have package."),
+		}
+	}
+	checker := models.ProblemChecker{
+		ProblemID:  problem.ID,
+		CompilerID: form.CompilerID,
+		Status:     models.PendingProblemChecker,
+		CreateTime: getNow(c).Unix(),
+	}
+	if account := accountCtx.Account; account != nil {
+		checker.AuthorID = account.ID
+	}
+	content := form.SourceFile
+	if form.BinaryFile != nil {
+		content = form.BinaryFile
+	}
+	file, err := v.files.UploadFile(getContext(c), content)
+	if err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.files.ConfirmUploadFile(ctx, &file); err != nil {
+			return err
+		}
+		if form.BinaryFile != nil {
+			checker.BinaryID = models.NInt64(file.ID)
+		} else {
+			checker.SourceID = models.NInt64(file.ID)
+		}
+		if err := v.core.ProblemCheckers.Create(ctx, &checker); err != nil {
+			return err
+		}
+		task := models.Task{}
+		if err := task.SetConfig(models.CompileProblemCheckerTaskConfig{
+			ProblemID: problem.ID,
+			CheckerID: checker.ID,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeProblemChecker(checker, 0))
+}
+
+// deleteProblemChecker removes version of problem checker.
+//
+// Solutions of problem are rejudged with previous version of checker,
+// if removed version was used for judging.
+func (v *View) deleteProblemChecker(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	checker, ok := c.Get(problemCheckerKey).(models.ProblemChecker)
+	if !ok {
+		return fmt.Errorf("checker not extracted")
+	}
+	currentID, err := v.getCurrentProblemChecker(c, problem.ID)
+	if err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.ProblemCheckers.Delete(ctx, checker.ID); err != nil {
+			return err
+		}
+		if checker.ID != currentID {
+			return nil
+		}
+		task := models.Task{}
+		if err := task.SetConfig(models.UpdateProblemPackageTaskConfig{
+			ProblemID: problem.ID,
+			FileID:    int64(problem.PackageID),
+			Compile:   problem.CompiledID == 0,
+			Rejudge:   true,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeProblemChecker(checker, currentID))
+}
+
+func (v *View) extractProblemChecker(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("checker"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidCheckerID,
+				Message:   localize(c, "Invalid checker ID."),
+			}
+		}
+		problem, ok := c.Get(problemKey).(models.Problem)
+		if !ok {
+			return fmt.Errorf("problem not extracted")
+		}
+		if err := syncStore(c, v.core.ProblemCheckers); err != nil {
+			return err
+		}
+		checker, err := v.core.ProblemCheckers.Get(getContext(c), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeCheckerNotFound,
+					Message:   localize(c, "Checker not found."),
+				}
+			}
+			return err
+		}
+		if checker.ProblemID != problem.ID {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeCheckerNotFound,
+				Message:   localize(c, "Checker not found."),
+			}
+		}
+		c.Set(problemCheckerKey, checker)
+		return next(c)
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerProblemGrantHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/problems/:problem/grants", v.observeProblemGrants,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.ObserveProblemGrantsRole),
+	)
+	g.POST(
+		"/v0/problems/:problem/grants", v.createProblemGrant,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.CreateProblemGrantRole),
+	)
+	g.DELETE(
+		"/v0/problems/:problem/grants/:grant", v.deleteProblemGrant,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.DeleteProblemGrantRole),
+	)
+}
+
+type ProblemGrantKind = models.ProblemGrantKind
+
+type ProblemGrant struct {
+	ID   int64            `json:"id"`
+	Kind ProblemGrantKind `json:"kind"`
+	GrantAccount
+}
+
+type ProblemGrants struct {
+	Grants []ProblemGrant `json:"grants"`
+}
+
+func (v *View) makeProblemGrant(c echo.Context, grant models.ProblemGrant) ProblemGrant {
+	return ProblemGrant{
+		ID:           grant.ID,
+		Kind:         grant.Kind,
+		GrantAccount: v.makeGrantAccount(c, grant.AccountID),
+	}
+}
+
+func (v *View) observeProblemGrants(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	if err := syncStore(c, v.core.ProblemGrants); err != nil {
+		return err
+	}
+	grants, err := v.core.ProblemGrants.FindByProblem(getContext(c), problem.ID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = grants.Close() }()
+	resp := ProblemGrants{Grants: []ProblemGrant{}}
+	for grants.Next() {
+		resp.Grants = append(resp.Grants, v.makeProblemGrant(c, grants.Row()))
+	}
+	if err := grants.Err(); err != nil {
+		return err
+	}
+	sort.Slice(resp.Grants, func(i, j int) bool {
+		return resp.Grants[i].ID < resp.Grants[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateProblemGrantForm struct {
+	Kind      ProblemGrantKind `json:"kind"`
+	AccountID int64            `json:"account_id"`
+}
+
+func (f CreateProblemGrantForm) Update(
+	c echo.Context, o *models.ProblemGrant, v *View,
+) error {
+	if !f.Kind.IsValid() {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"kind": errorField{Message: localize(c, "Invalid kind.")},
+			},
+		}
+	}
+	account, err := getContestAccount(c, f.AccountID, v.core)
+	if err != nil {
+		return err
+	}
+	o.AccountID = account.ID
+	o.Kind = f.Kind
+	return nil
+}
+
+func (v *View) createProblemGrant(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	var form CreateProblemGrantForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	grant := models.ProblemGrant{ProblemID: problem.ID}
+	if err := form.Update(c, &grant, v); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ProblemGrants); err != nil {
+		return err
+	}
+	grants, err := v.core.ProblemGrants.FindByProblemAccount(
+		getContext(c), grant.ProblemID, grant.AccountID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = grants.Close() }()
+	for grants.Next() {
+		if grants.Row().Kind == grant.Kind {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeGrantAlreadyExists,
+				Message:   localize(c, "Grant already exists."),
+			}
+		}
+	}
+	if err := grants.Err(); err != nil {
+		return err
+	}
+	if err := v.core.ProblemGrants.Create(getContext(c), &grant); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, v.makeProblemGrant(c, grant))
+}
+
+func (v *View) deleteProblemGrant(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("grant"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidGrantID,
+			Message:   localize(c, "Invalid grant ID."),
+		}
+	}
+	if err := syncStore(c, v.core.ProblemGrants); err != nil {
+		return err
+	}
+	grant, err := v.core.ProblemGrants.Get(getContext(c), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeGrantNotFound,
+				Message:   localize(c, "Grant not found."),
+			}
+		}
+		return err
+	}
+	if grant.ProblemID != problem.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeGrantNotFound,
+			Message:   localize(c, "Grant not found."),
+		}
+	}
+	if err := v.core.ProblemGrants.Delete(getContext(c), grant.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, v.makeProblemGrant(c, grant))
+}
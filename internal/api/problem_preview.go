@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/problems"
+	"github.com/udovin/solve/internal/pkg/problems/cache"
+)
+
+// ProblemPreviewStatement represents statement of problem package
+// with embedded resources.
+type ProblemPreviewStatement struct {
+	ProblemStatement
+	// Resources contains statement resources encoded as data URLs
+	// by name.
+	Resources map[string]string `json:"resources,omitempty"`
+}
+
+// ProblemPreview represents problem package that is not applied to
+// problem yet.
+type ProblemPreview struct {
+	Config     *models.ProblemConfig     `json:"config,omitempty"`
+	Statements []ProblemPreviewStatement `json:"statements"`
+}
+
+type PreviewProblemForm struct {
+	PackageFile *FileReader `json:"-"`
+}
+
+func (f *PreviewProblemForm) Close() error {
+	if f.PackageFile == nil {
+		return nil
+	}
+	return f.PackageFile.Close()
+}
+
+func (f *PreviewProblemForm) Parse(c echo.Context) error {
+	formFile, err := c.FormFile("file")
+	if err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"file": {Message: localize(c, "File is required.")},
+			},
+		}
+	}
+	file, err := managers.NewMultipartFileReader(formFile)
+	if err != nil {
+		return err
+	}
+	f.PackageFile = file
+	return nil
+}
+
+// maxPreviewResourceSize contains maximal size of statement resource
+// that is embedded into preview.
+const maxPreviewResourceSize = 4 * 1024 * 1024
+
+// previewProblem renders statements from uploaded problem package
+// without updating problem.
+//
+// Package is neither stored nor compiled, so setters can check
+// statements before activation of package.
+func (v *View) previewProblem(c echo.Context) error {
+	var form PreviewProblemForm
+	if err := form.Parse(c); err != nil {
+		return err
+	}
+	defer func() { _ = form.Close() }()
+	tempDir, err := os.MkdirTemp("", "solve-preview-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	packagePath := filepath.Join(tempDir, "package.zip")
+	if err := writePreviewPackage(packagePath, form.PackageFile); err != nil {
+		return err
+	}
+	problem, err := cache.ExtractProblem(
+		problems.PolygonProblem, filepath.Join(tempDir, "problem"), packagePath,
+	)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidProblemPackage,
+			Message:   localize(c, "Invalid problem package."),
+		}
+	}
+	resp := ProblemPreview{Statements: []ProblemPreviewStatement{}}
+	if testSets, err := problem.GetTestSets(); err == nil {
+		config := models.ProblemConfig{}
+		for _, testSet := range testSets {
+			config.TimeLimit = max(config.TimeLimit, testSet.TimeLimit())
+			config.MemoryLimit = max(config.MemoryLimit, testSet.MemoryLimit())
+		}
+		resp.Config = &config
+	}
+	statements, err := problem.GetStatements()
+	if err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		config, err := statement.GetConfig()
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidStatement,
+				Message: localize(
+					c, "Invalid statement \"{locale}\".",
+					replaceField("locale", statement.Locale()),
+				),
+			}
+		}
+		resources, err := statement.GetResources()
+		if err != nil {
+			return err
+		}
+		previewStatement := ProblemPreviewStatement{ProblemStatement: config}
+		for _, resource := range resources {
+			data, err := readPreviewResource(resource)
+			if err != nil {
+				c.Logger().Warn(err)
+				continue
+			}
+			if previewStatement.Resources == nil {
+				previewStatement.Resources = map[string]string{}
+			}
+			previewStatement.Resources[resource.Name()] = data
+		}
+		resp.Statements = append(resp.Statements, previewStatement)
+	}
+	sort.Slice(resp.Statements, func(i, j int) bool {
+		return resp.Statements[i].Locale < resp.Statements[j].Locale
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+func writePreviewPackage(path string, file *FileReader) error {
+	target, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = target.Close() }()
+	if _, err := io.Copy(target, file.Reader); err != nil {
+		return err
+	}
+	return target.Sync()
+}
+
+// readPreviewResource returns content of statement resource encoded
+// as data URL.
+func readPreviewResource(resource problems.ProblemResource) (string, error) {
+	file, err := resource.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	content, err := io.ReadAll(io.LimitReader(file, maxPreviewResourceSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(content) > maxPreviewResourceSize {
+		return "", fmt.Errorf("resource %q is too large", resource.Name())
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(resource.Name()))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	return fmt.Sprintf(
+		"data:%s;base64,%s",
+		contentType, base64.StdEncoding.EncodeToString(content),
+	), nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerProblemRecommendationHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/problems/recommendations", v.observeProblemRecommendations,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(perms.ObserveProblemsRole),
+	)
+}
+
+// ProblemRecommendation represents recommended problem.
+type ProblemRecommendation struct {
+	Problem Problem `json:"problem"`
+	// Difficulty contains estimated difficulty from 0 (easy) to 1 (hard).
+	Difficulty    float64 `json:"difficulty"`
+	TotalAuthors  int     `json:"total_authors"`
+	SolvedAuthors int     `json:"solved_authors"`
+}
+
+// ProblemRecommendations represents recommended problems for user.
+type ProblemRecommendations struct {
+	// Skill contains estimated skill of user in units of difficulty.
+	Skill    float64                 `json:"skill"`
+	Problems []ProblemRecommendation `json:"problems"`
+}
+
+const (
+	defaultProblemRecommendationLimit = 10
+	maxProblemRecommendationLimit     = 50
+)
+
+type problemRecommendationFilter struct {
+	Limit int `query:"limit"`
+}
+
+func (f *problemRecommendationFilter) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
+		}
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultProblemRecommendationLimit
+	}
+	f.Limit = min(f.Limit, maxProblemRecommendationLimit)
+	return nil
+}
+
+func (v *View) observeProblemRecommendations(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	var filter problemRecommendationFilter
+	if err := filter.Parse(c); err != nil {
+		c.Logger().Warn(err)
+		return err
+	}
+	if err := syncStore(c, v.core.Problems); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	recommendations, err := v.recommendations.RecommendProblems(
+		accountCtx, managers.RecommendProblemsOptions{
+			Limit: filter.Limit,
+			Filter: func(problem models.Problem) bool {
+				permissions := v.getProblemPermissions(accountCtx, problem)
+				return permissions.HasPermission(perms.ObserveProblemRole)
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	resp := ProblemRecommendations{
+		Skill:    recommendations.Skill,
+		Problems: []ProblemRecommendation{},
+	}
+	for _, recommendation := range recommendations.Problems {
+		permissions := v.getProblemPermissions(accountCtx, recommendation.Problem)
+		resp.Problems = append(resp.Problems, ProblemRecommendation{
+			Problem: v.makeProblem(
+				c, recommendation.Problem, permissions, false, false, nil,
+			),
+			Difficulty:    recommendation.Difficulty.Difficulty,
+			TotalAuthors:  recommendation.Difficulty.TotalAuthors,
+			SolvedAuthors: recommendation.Difficulty.SolvedAuthors,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerProblemStressHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/problems/:problem/stress", v.createProblemStress,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+		v.limitSubmitBody,
+	)
+	g.GET(
+		"/v0/problems/:problem/stress/:task", v.observeProblemStress,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+}
+
+type StressMismatch = models.StressMismatch
+
+// ProblemStress represents stress-testing of solution.
+type ProblemStress struct {
+	ID       int64           `json:"id"`
+	Status   string          `json:"status"`
+	Stage    string          `json:"stage,omitempty"`
+	Test     int             `json:"test,omitempty"`
+	Tests    int             `json:"tests"`
+	Error    string          `json:"error,omitempty"`
+	Mismatch *StressMismatch `json:"mismatch,omitempty"`
+}
+
+func makeProblemStress(task models.Task) ProblemStress {
+	resp := ProblemStress{
+		ID:     task.ID,
+		Status: task.Status.String(),
+	}
+	var config models.StressProblemTaskConfig
+	if err := task.ScanConfig(&config); err == nil {
+		resp.Tests = config.Tests
+	}
+	var state models.StressProblemTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Stage = state.Stage
+		resp.Test = state.Test
+		resp.Error = state.Error
+		resp.Mismatch = state.Mismatch
+	}
+	return resp
+}
+
+type StressSourceForm struct {
+	CompilerID int64  `json:"compiler_id"`
+	Content    string `json:"content"`
+}
+
+// CreateProblemStressForm represents form for stress-testing of solution.
+type CreateProblemStressForm struct {
+	Generator     StressSourceForm `json:"generator"`
+	ModelSolution StressSourceForm `json:"model_solution"`
+	Solution      StressSourceForm `json:"solution"`
+	// Tests contains amount of random tests.
+	Tests int `json:"tests"`
+}
+
+const (
+	defaultStressTests = 100
+	maxStressTests     = 1000
+)
+
+func (f StressSourceForm) validate(
+	c echo.Context, v *View, name string, errors errorFields,
+) error {
+	if len(f.Content) == 0 {
+		errors[name] = errorField{
+			Message: localize(c, "Source is empty."),
+		}
+	} else if len(f.Content) >= 256*1024 {
+		errors[name] = errorField{
+			Message: localize(c, "Source is too large."),
+		}
+	} else if _, err := v.core.Compilers.Get(getContext(c), f.CompilerID); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		errors[name] = errorField{
+			Message: localize(c, "Compiler not found."),
+		}
+	}
+	return nil
+}
+
+func (f CreateProblemStressForm) Update(
+	c echo.Context, v *View, config *models.StressProblemTaskConfig,
+) error {
+	errors := errorFields{}
+	if err := f.Generator.validate(c, v, "generator", errors); err != nil {
+		return err
+	}
+	if err := f.ModelSolution.validate(c, v, "model_solution", errors); err != nil {
+		return err
+	}
+	if err := f.Solution.validate(c, v, "solution", errors); err != nil {
+		return err
+	}
+	if f.Tests < 0 {
+		errors["tests"] = errorField{
+			Message: localize(c, "Amount of tests should be positive."),
+		}
+	} else if f.Tests > maxStressTests {
+		errors["tests"] = errorField{
+			Message: localize(c, "Too many tests."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config.Generator = models.StressSource(f.Generator)
+	config.ModelSolution = models.StressSource(f.ModelSolution)
+	config.Solution = models.StressSource(f.Solution)
+	config.Tests = f.Tests
+	if config.Tests == 0 {
+		config.Tests = defaultStressTests
+	}
+	return nil
+}
+
+func (v *View) createProblemStress(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	var form CreateProblemStressForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if problem.PackageID == 0 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeProblemWithoutPackage,
+			Message:   localize(c, "Problem does not have package."),
+		}
+	}
+	config := models.StressProblemTaskConfig{ProblemID: problem.ID}
+	if err := form.Update(c, v, &config); err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := task.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeProblemStress(task))
+}
+
+func (v *View) observeProblemStress(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTaskID,
+			Message:   localize(c, "Invalid task ID."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	task, err := v.core.Tasks.Get(getContext(c), id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	var config models.StressProblemTaskConfig
+	if task.Kind != models.StressProblemTask ||
+		task.ScanConfig(&config) != nil || config.ProblemID != problem.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeProblemStress(task))
+}
@@ -0,0 +1,448 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerProblemTestHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/problems/:problem/validate", v.validateProblemTest,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+		v.limitUploadBody,
+	)
+	g.GET(
+		"/v0/problems/:problem/validate/:task", v.observeProblemTestValidation,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+	g.GET(
+		"/v0/problems/:problem/tests", v.observeProblemExtraTests,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+	g.POST(
+		"/v0/problems/:problem/tests", v.createProblemExtraTest,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+		v.limitUploadBody,
+	)
+	g.DELETE(
+		"/v0/problems/:problem/tests/:test", v.deleteProblemExtraTest,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.extractProblemExtraTest,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+}
+
+// ProblemTestValidation represents result of test validation.
+type ProblemTestValidation struct {
+	ID      int64  `json:"id"`
+	Status  string `json:"status"`
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func makeProblemTestValidation(task models.Task) ProblemTestValidation {
+	resp := ProblemTestValidation{
+		ID:     task.ID,
+		Status: task.Status.String(),
+	}
+	var state models.ValidateProblemTestTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Valid = state.Valid
+		resp.Message = state.Message
+		resp.Error = state.Error
+	}
+	return resp
+}
+
+// maxProblemTestSize contains maximal size of uploaded test file.
+const maxProblemTestSize = 64 * 1024 * 1024
+
+type ValidateProblemTestForm struct {
+	Input *string `form:"input" json:"input,omitempty"`
+	// InputFile will be initialized with the input if it is provided.
+	InputFile *FileReader `json:"-"`
+}
+
+// parseTestFile returns reader of test file that is specified as form
+// value or as multipart file with "_file" suffix.
+func parseTestFile(
+	c echo.Context, value *string, name string,
+) (*FileReader, error) {
+	var file *FileReader
+	if value != nil {
+		content := bytes.NewReader([]byte(*value))
+		file = &FileReader{
+			Reader: content,
+			Size:   int64(content.Len()),
+		}
+	} else {
+		formFile, err := c.FormFile(name + "_file")
+		if err != nil {
+			c.Logger().Warn(err)
+			return nil, errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidFields,
+				Message:   localize(c, "Form has invalid fields."),
+				InvalidFields: errorFields{
+					name: errorField{Message: localize(c, "File is required.")},
+				},
+			}
+		}
+		file, err = managers.NewMultipartFileReader(formFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if file.Size > maxProblemTestSize {
+		_ = file.Close()
+		return nil, errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeFileTooLarge,
+			Message:   localize(c, "File is too large."),
+		}
+	}
+	return file, nil
+}
+
+func (f *ValidateProblemTestForm) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	file, err := parseTestFile(c, f.Input, "input")
+	if err != nil {
+		return err
+	}
+	f.InputFile = file
+	return nil
+}
+
+func (f *ValidateProblemTestForm) Close() error {
+	if f.InputFile == nil {
+		return nil
+	}
+	return f.InputFile.Close()
+}
+
+func (v *View) validateProblemTest(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	var form ValidateProblemTestForm
+	if err := form.Parse(c); err != nil {
+		return err
+	}
+	defer func() { _ = form.Close() }()
+	if problem.PackageID == 0 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeProblemWithoutPackage,
+			Message:   localize(c, "Problem does not have package."),
+		}
+	}
+	file, err := v.files.UploadFile(getContext(c), form.InputFile)
+	if err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.files.ConfirmUploadFile(ctx, &file); err != nil {
+			return err
+		}
+		if err := task.SetConfig(models.ValidateProblemTestTaskConfig{
+			ProblemID: problem.ID,
+			InputID:   file.ID,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeProblemTestValidation(task))
+}
+
+func (v *View) observeProblemTestValidation(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTaskID,
+			Message:   localize(c, "Invalid task ID."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	task, err := v.core.Tasks.Get(getContext(c), id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	var config models.ValidateProblemTestTaskConfig
+	if task.Kind != models.ValidateProblemTestTask ||
+		task.ScanConfig(&config) != nil || config.ProblemID != problem.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeProblemTestValidation(task))
+}
+
+// ProblemExtraTest represents extra test of problem.
+type ProblemExtraTest struct {
+	ID         int64  `json:"id"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	CreateTime int64  `json:"create_time"`
+}
+
+type ProblemExtraTests struct {
+	Tests []ProblemExtraTest `json:"tests"`
+}
+
+func makeProblemExtraTest(test models.ProblemExtraTest) ProblemExtraTest {
+	return ProblemExtraTest{
+		ID:         test.ID,
+		Status:     test.Status.String(),
+		Message:    test.Message,
+		CreateTime: test.CreateTime,
+	}
+}
+
+func (v *View) observeProblemExtraTests(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	if err := syncStore(c, v.core.ProblemExtraTests); err != nil {
+		return err
+	}
+	tests, err := v.core.ProblemExtraTests.FindByProblem(
+		getContext(c), problem.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tests.Close() }()
+	resp := ProblemExtraTests{Tests: []ProblemExtraTest{}}
+	for tests.Next() {
+		resp.Tests = append(resp.Tests, makeProblemExtraTest(tests.Row()))
+	}
+	if err := tests.Err(); err != nil {
+		return err
+	}
+	sort.Slice(resp.Tests, func(i, j int) bool {
+		return resp.Tests[i].ID < resp.Tests[j].ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateProblemExtraTestForm struct {
+	Input  *string `form:"input" json:"input,omitempty"`
+	Answer *string `form:"answer" json:"answer,omitempty"`
+	// InputFile will be initialized with the input if it is provided.
+	InputFile *FileReader `json:"-"`
+	// AnswerFile will be initialized with the answer if it is provided.
+	AnswerFile *FileReader `json:"-"`
+}
+
+func (f *CreateProblemExtraTestForm) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	input, err := parseTestFile(c, f.Input, "input")
+	if err != nil {
+		return err
+	}
+	f.InputFile = input
+	answer, err := parseTestFile(c, f.Answer, "answer")
+	if err != nil {
+		return err
+	}
+	f.AnswerFile = answer
+	return nil
+}
+
+func (f *CreateProblemExtraTestForm) Close() error {
+	if f.InputFile != nil {
+		_ = f.InputFile.Close()
+	}
+	if f.AnswerFile != nil {
+		_ = f.AnswerFile.Close()
+	}
+	return nil
+}
+
+// createProblemExtraTest adds extra test to problem.
+//
+// Test is used for judging only after successful validation.
+func (v *View) createProblemExtraTest(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	var form CreateProblemExtraTestForm
+	defer func() { _ = form.Close() }()
+	if err := form.Parse(c); err != nil {
+		return err
+	}
+	if problem.PackageID == 0 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeProblemWithoutPackage,
+			Message:   localize(c, "Problem does not have package."),
+		}
+	}
+	input, err := v.files.UploadFile(getContext(c), form.InputFile)
+	if err != nil {
+		return err
+	}
+	answer, err := v.files.UploadFile(getContext(c), form.AnswerFile)
+	if err != nil {
+		return err
+	}
+	test := models.ProblemExtraTest{
+		ProblemID:  problem.ID,
+		Status:     models.PendingProblemExtraTest,
+		CreateTime: getNow(c).Unix(),
+	}
+	if account := accountCtx.Account; account != nil {
+		test.AuthorID = account.ID
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.files.ConfirmUploadFile(ctx, &input); err != nil {
+			return err
+		}
+		if err := v.files.ConfirmUploadFile(ctx, &answer); err != nil {
+			return err
+		}
+		test.InputID = input.ID
+		test.AnswerID = answer.ID
+		if err := v.core.ProblemExtraTests.Create(ctx, &test); err != nil {
+			return err
+		}
+		task := models.Task{}
+		if err := task.SetConfig(models.ValidateProblemTestTaskConfig{
+			ProblemID: problem.ID,
+			InputID:   input.ID,
+			TestID:    test.ID,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeProblemExtraTest(test))
+}
+
+// deleteProblemExtraTest removes extra test from problem.
+//
+// Solutions of problem are rejudged, if test was used for judging.
+func (v *View) deleteProblemExtraTest(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	test, ok := c.Get(problemExtraTestKey).(models.ProblemExtraTest)
+	if !ok {
+		return fmt.Errorf("test not extracted")
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.ProblemExtraTests.Delete(ctx, test.ID); err != nil {
+			return err
+		}
+		if test.Status != models.ActiveProblemExtraTest {
+			return nil
+		}
+		task := models.Task{}
+		if err := task.SetConfig(models.UpdateProblemPackageTaskConfig{
+			ProblemID: problem.ID,
+			FileID:    int64(problem.PackageID),
+			Compile:   problem.CompiledID == 0,
+			Rejudge:   true,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeProblemExtraTest(test))
+}
+
+func (v *View) extractProblemExtraTest(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("test"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidTestID,
+				Message:   localize(c, "Invalid test ID."),
+			}
+		}
+		problem, ok := c.Get(problemKey).(models.Problem)
+		if !ok {
+			return fmt.Errorf("problem not extracted")
+		}
+		if err := syncStore(c, v.core.ProblemExtraTests); err != nil {
+			return err
+		}
+		test, err := v.core.ProblemExtraTests.Get(getContext(c), id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeTestNotFound,
+					Message:   localize(c, "Test not found."),
+				}
+			}
+			return err
+		}
+		if test.ProblemID != problem.ID {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeTestNotFound,
+				Message:   localize(c, "Test not found."),
+			}
+		}
+		c.Set(problemExtraTestKey, test)
+		return next(c)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerProblemUsageHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/problems/:problem/usages", v.observeProblemUsages,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+	)
+}
+
+// ProblemUsage represents contest that references problem.
+type ProblemUsage struct {
+	ContestID    int64  `json:"contest_id"`
+	ContestTitle string `json:"contest_title"`
+	// ContestProblemID contains ID of problem in contest.
+	ContestProblemID int64  `json:"contest_problem_id"`
+	Code             string `json:"code"`
+}
+
+// ProblemUsages represents contests that reference problem.
+type ProblemUsages struct {
+	Usages []ProblemUsage `json:"usages"`
+	// HiddenUsages contains amount of contests that reference problem
+	// but can not be observed by account.
+	HiddenUsages int `json:"hidden_usages,omitempty"`
+}
+
+// observeProblemUsages returns contests that reference problem.
+//
+// Changes of problem affect all these contests, so jury can check
+// usages before update of problem or make clone of problem.
+func (v *View) observeProblemUsages(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Contests); err != nil {
+		return err
+	}
+	contestProblems, err := collectRows(v.core.ContestProblems.FindByProblem(
+		getContext(c), problem.ID,
+	))
+	if err != nil {
+		return err
+	}
+	resp := ProblemUsages{Usages: []ProblemUsage{}}
+	for _, contestProblem := range contestProblems {
+		contest, err := v.core.Contests.Get(getContext(c), contestProblem.ContestID)
+		if err != nil {
+			continue
+		}
+		contestCtx, err := v.contests.BuildContext(accountCtx, contest)
+		if err != nil {
+			return err
+		}
+		if !contestCtx.HasPermission(perms.ObserveContestRole) {
+			resp.HiddenUsages++
+			continue
+		}
+		resp.Usages = append(resp.Usages, ProblemUsage{
+			ContestID:        contest.ID,
+			ContestTitle:     contest.Title,
+			ContestProblemID: contestProblem.ID,
+			Code:             contestProblem.Code,
+		})
+	}
+	sort.Slice(resp.Usages, func(i, j int) bool {
+		return resp.Usages[i].ContestProblemID < resp.Usages[j].ContestProblemID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
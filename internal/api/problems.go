@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/db"
 	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
 )
 
 // registerProblemHandlers registers handlers for problem management.
@@ -25,6 +28,7 @@ func (v *View) registerProblemHandlers(g *echo.Group) {
 		"/v0/problems", v.createProblem,
 		v.extractAuth(v.sessionAuth),
 		v.requirePermission(perms.CreateProblemRole),
+		v.limitUploadBody,
 	)
 	g.GET(
 		"/v0/problems/:problem", v.observeProblem,
@@ -35,12 +39,24 @@ func (v *View) registerProblemHandlers(g *echo.Group) {
 		"/v0/problems/:problem", v.updateProblem,
 		v.extractAuth(v.sessionAuth), v.extractProblem,
 		v.requirePermission(perms.UpdateProblemRole),
+		v.limitUploadBody,
 	)
 	g.POST(
 		"/v0/problems/:problem/rebuild", v.rebuildProblem,
 		v.extractAuth(v.sessionAuth), v.extractProblem,
 		v.requirePermission(perms.UpdateProblemRole),
 	)
+	g.POST(
+		"/v0/problems/:problem/clone", v.cloneProblem,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole, perms.CreateProblemRole),
+	)
+	g.POST(
+		"/v0/problems/:problem/preview", v.previewProblem,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(perms.UpdateProblemRole),
+		v.limitUploadBody,
+	)
 	// Deprecated
 	g.GET(
 		"/v0/problems/:problem/statement-files/:name",
@@ -64,8 +80,15 @@ func (v *View) registerProblemHandlers(g *echo.Group) {
 type ProblemStatement = models.ProblemStatementConfig
 
 type ProblemTask struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	Status  string          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Rejudge *ProblemRejudge `json:"rejudge,omitempty"`
+}
+
+// ProblemRejudge represents progress of rejudge after package update.
+type ProblemRejudge struct {
+	Total    int `json:"total"`
+	Finished int `json:"finished"`
 }
 
 type Problem struct {
@@ -75,6 +98,11 @@ type Problem struct {
 	Config      *models.ProblemConfig `json:"config,omitempty"`
 	Permissions []string              `json:"permissions,omitempty"`
 	LastTask    *ProblemTask          `json:"last_task,omitempty"`
+	ScopeID     NInt64                `json:"scope_id,omitempty"`
+	// ResourceHashes contains hashes of statement resources by name.
+	//
+	// Resources requested with "?hash=" are cached by clients forever.
+	ResourceHashes map[string]string `json:"resource_hashes,omitempty"`
 }
 
 type Problems struct {
@@ -85,6 +113,9 @@ var problemPermissions = []string{
 	perms.UpdateProblemRole,
 	perms.UpdateProblemOwnerRole,
 	perms.DeleteProblemRole,
+	perms.ObserveProblemGrantsRole,
+	perms.CreateProblemGrantRole,
+	perms.DeleteProblemGrantRole,
 }
 
 func (v *View) makeProblem(
@@ -96,8 +127,9 @@ func (v *View) makeProblem(
 	locales map[string]struct{},
 ) Problem {
 	resp := Problem{
-		ID:    problem.ID,
-		Title: problem.Title,
+		ID:      problem.ID,
+		Title:   problem.Title,
+		ScopeID: problem.ScopeID,
 	}
 	if withStatement {
 		config, err := problem.GetConfig()
@@ -108,7 +140,7 @@ func (v *View) makeProblem(
 			}
 		}
 	}
-	locale := getLocale(c)
+	preferred := getStatementLocales(c)
 	func() {
 		resources, err := v.core.ProblemResources.FindByProblem(
 			getContext(c), problem.ID,
@@ -117,6 +149,7 @@ func (v *View) makeProblem(
 			return
 		}
 		defer func() { _ = resources.Close() }()
+		bestRank := len(preferred)
 		for resources.Next() {
 			resource := resources.Row()
 			if resource.Kind != models.ProblemStatement {
@@ -131,7 +164,9 @@ func (v *View) makeProblem(
 					continue
 				}
 			}
-			if resp.Statement == nil || config.Locale == locale.Name() {
+			rank := getLocaleRank(preferred, config.Locale)
+			if resp.Statement == nil || rank < bestRank {
+				bestRank = rank
 				statement := ProblemStatement{
 					Locale: config.Locale,
 					Title:  config.Title,
@@ -147,11 +182,14 @@ func (v *View) makeProblem(
 				}
 				resp.Statement = &statement
 			}
-			if config.Locale == locale.Name() {
+			if bestRank == 0 {
 				return
 			}
 		}
 	}()
+	if withStatement {
+		resp.ResourceHashes = v.getProblemResourceHashes(c, problem.ID)
+	}
 	if withTask && permissions.HasPermission(perms.UpdateProblemRole) {
 		task, err := v.findProblemTask(c, problem.ID)
 		if err == nil {
@@ -161,6 +199,9 @@ func (v *View) makeProblem(
 			var state models.UpdateProblemPackageTaskState
 			if err := task.ScanState(&state); err == nil {
 				taskResp.Error = state.Error
+				if len(state.RejudgeTasks) > 0 {
+					taskResp.Rejudge = v.makeProblemRejudge(c, state.RejudgeTasks)
+				}
 			}
 			resp.LastTask = &taskResp
 		}
@@ -173,6 +214,38 @@ func (v *View) makeProblem(
 	return resp
 }
 
+// getStatementLocales returns locales of statements in descending
+// order of preference: locale from query, locale of account and
+// default locale of contest.
+//
+// Any available statement is used when there are no preferred ones.
+func getStatementLocales(c echo.Context) []string {
+	var locales []string
+	if name := c.QueryParam("locale"); name != "" {
+		locales = append(locales, name)
+	}
+	if name := getLocale(c).Name(); name != "" {
+		locales = append(locales, name)
+	}
+	if contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext); ok {
+		if name := contestCtx.ContestConfig.Locale; name != "" {
+			locales = append(locales, name)
+		}
+	}
+	return locales
+}
+
+// getLocaleRank returns position of locale in list of preferred
+// locales or length of list if locale is not preferred.
+func getLocaleRank(locales []string, name string) int {
+	for i, locale := range locales {
+		if locale == name {
+			return i
+		}
+	}
+	return len(locales)
+}
+
 type problemFilter struct {
 	Query string `query:"q"`
 }
@@ -198,8 +271,9 @@ func (v *View) observeProblems(c echo.Context) error {
 	if err := c.Bind(&filter); err != nil {
 		c.Logger().Warn(err)
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid filter."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
 		}
 	}
 	problems, err := v.core.Problems.ReverseAll(getContext(c), 0, 0)
@@ -249,61 +323,156 @@ func (v *View) observeProblemContent(c echo.Context) error {
 		return fmt.Errorf("problem not extracted")
 	}
 	resourceName := c.Param("name")
-	locale := getLocale(c)
-	var foundResource *models.ProblemResource
-	if err := func() error {
-		resources, err := v.core.ProblemResources.FindByProblem(getContext(c), problem.ID)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = resources.Close() }()
-		for resources.Next() {
-			resource := resources.Row()
-			if resource.Kind != models.ProblemStatementResource {
-				continue
-			}
-			if resource.FileID == 0 {
-				continue
-			}
-			config := models.ProblemStatementResourceConfig{}
-			if err := resource.ScanConfig(&config); err != nil {
-				continue
-			}
-			if config.Name != resourceName {
-				continue
-			}
-			if foundResource == nil || config.Locale == locale.Name() {
-				foundResource = &resource
-			}
-		}
-		return nil
-	}(); err != nil {
+	resources, err := v.findProblemResources(c, problem.ID)
+	if err != nil {
 		return err
 	}
-	if foundResource == nil {
+	foundResource, ok := resources[resourceName]
+	if !ok {
 		return errorResponse{
-			Code:    http.StatusNotFound,
-			Message: localize(c, "File not found."),
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeFileNotFound,
+			Message:   localize(c, "File not found."),
 		}
 	}
 	file, err := v.core.Files.Get(getContext(c), int64(foundResource.FileID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "File not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeFileNotFound,
+				Message:   localize(c, "File not found."),
 			}
 		}
 		return err
 	}
 	c.Set(fileKey, file)
+	return v.observeResourceContent(c)
+}
+
+// resourceCacheMaxAge contains lifetime of cached resources with
+// content hash in URL.
+const resourceCacheMaxAge = 365 * 24 * time.Hour
+
+// observeResourceContent serves content of problem resource with cache
+// headers.
+//
+// Resource is served by redirect if storage supports signed URLs.
+func (v *View) observeResourceContent(c echo.Context) error {
+	file, ok := c.Get(fileKey).(models.File)
+	if !ok {
+		return fmt.Errorf("file not extracted")
+	}
+	meta, err := file.GetMeta()
+	if err != nil {
+		return err
+	}
+	// Resources are immutable, so URL with hash of content can be
+	// cached forever.
+	immutable := meta.MD5 != "" && c.QueryParam("hash") == meta.MD5
+	header := c.Response().Header()
+	if immutable {
+		header.Set(echo.HeaderCacheControl, fmt.Sprintf(
+			"private, max-age=%d, immutable", int64(resourceCacheMaxAge.Seconds()),
+		))
+	} else {
+		header.Set(echo.HeaderCacheControl, "private, no-cache")
+	}
+	if v.files != nil {
+		url, expires, err := v.files.SignFileURL(getContext(c), file)
+		if err != nil {
+			c.Logger().Warn("Cannot sign file URL", logs.Any("id", file.ID), err)
+		} else if url != "" {
+			if immutable {
+				// Redirect should not outlive signed URL.
+				header.Set(echo.HeaderCacheControl, fmt.Sprintf(
+					"private, max-age=%d", int64(expires.Seconds())/2,
+				))
+			}
+			return c.Redirect(http.StatusFound, url)
+		}
+	}
 	return v.observeFileContent(c)
 }
 
+// findProblemResources returns statement resources of problem with
+// files by name.
+//
+// For every name resource with most preferred locale is returned.
+func (v *View) findProblemResources(
+	c echo.Context, problemID int64,
+) (map[string]models.ProblemResource, error) {
+	if err := syncStore(c, v.core.ProblemResources); err != nil {
+		return nil, err
+	}
+	if err := syncStore(c, v.core.Files); err != nil {
+		return nil, err
+	}
+	preferred := getStatementLocales(c)
+	resources, err := v.core.ProblemResources.FindByProblem(getContext(c), problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resources.Close() }()
+	found := map[string]models.ProblemResource{}
+	ranks := map[string]int{}
+	for resources.Next() {
+		resource := resources.Row()
+		if resource.Kind != models.ProblemStatementResource {
+			continue
+		}
+		if resource.FileID == 0 {
+			continue
+		}
+		config := models.ProblemStatementResourceConfig{}
+		if err := resource.ScanConfig(&config); err != nil {
+			continue
+		}
+		rank := getLocaleRank(preferred, config.Locale)
+		if bestRank, ok := ranks[config.Name]; !ok || rank < bestRank {
+			ranks[config.Name] = rank
+			found[config.Name] = resource
+		}
+	}
+	return found, resources.Err()
+}
+
+// getProblemResourceHashes returns hashes of content of statement
+// resources by name.
+func (v *View) getProblemResourceHashes(
+	c echo.Context, problemID int64,
+) map[string]string {
+	resources, err := v.findProblemResources(c, problemID)
+	if err != nil {
+		c.Logger().Warn("Cannot find problem resources", logs.Any("id", problemID), err)
+		return nil
+	}
+	var hashes map[string]string
+	for name, resource := range resources {
+		file, err := v.core.Files.Get(getContext(c), int64(resource.FileID))
+		if err != nil {
+			continue
+		}
+		meta, err := file.GetMeta()
+		if err != nil || meta.MD5 == "" {
+			continue
+		}
+		if hashes == nil {
+			hashes = map[string]string{}
+		}
+		hashes[name] = meta.MD5
+	}
+	return hashes
+}
+
 type UpdateProblemForm struct {
 	Title       *string     `json:"title" form:"title"`
 	OwnerID     *int64      `json:"owner_id" form:"owner_id"`
+	ScopeID     *int64      `json:"scope_id" form:"scope_id"`
+	Rejudge     bool        `json:"rejudge" form:"rejudge"`
 	PackageFile *FileReader `json:"-"`
+	// UploadID contains ID of finalized chunked upload of package.
+	UploadID *int64 `json:"upload_id" form:"upload_id"`
 }
 
 func (f *UpdateProblemForm) Close() error {
@@ -349,6 +518,7 @@ func (f *UpdateProblemForm) Update(c echo.Context, problem *models.Problem) erro
 	}
 	if len(errors) > 0 {
 		return errorResponse{
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -363,17 +533,19 @@ type CreateProblemForm struct {
 func (f *CreateProblemForm) Update(c echo.Context, problem *models.Problem) error {
 	if f.Title == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"title": errorField{Message: localize(c, "Title is required.")},
 			},
 		}
 	}
-	if f.PackageFile == nil {
+	if f.PackageFile == nil && f.UploadID == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"file": errorField{Message: localize(c, "File is required.")},
 			},
@@ -399,7 +571,13 @@ func (v *View) createProblem(c echo.Context) error {
 	if account := accountCtx.Account; account != nil {
 		problem.OwnerID = NInt64(account.ID)
 	}
-	file, err := v.files.UploadFile(getContext(c), form.PackageFile)
+	if form.ScopeID != nil {
+		if err := v.checkEntityScope(c, accountCtx, *form.ScopeID); err != nil {
+			return err
+		}
+		problem.ScopeID = NInt64(*form.ScopeID)
+	}
+	file, err := v.uploadProblemPackage(c, accountCtx, form.UpdateProblemForm)
 	if err != nil {
 		return err
 	}
@@ -430,6 +608,101 @@ func (v *View) createProblem(c echo.Context) error {
 	)
 }
 
+// uploadProblemPackage returns pending file of problem package from
+// form file or from finalized chunked upload.
+func (v *View) uploadProblemPackage(
+	c echo.Context, accountCtx *managers.AccountContext, form UpdateProblemForm,
+) (models.File, error) {
+	if form.UploadID != nil {
+		return v.getFinalizedFileUpload(c, accountCtx, *form.UploadID)
+	}
+	return v.files.UploadFile(getContext(c), form.PackageFile)
+}
+
+// CloneProblemForm represents form for cloning of problem.
+type CloneProblemForm struct {
+	// Title contains title of clone, title of problem is used by default.
+	Title   *string `json:"title" form:"title"`
+	ScopeID *int64  `json:"scope_id" form:"scope_id"`
+}
+
+func (f CloneProblemForm) Update(c echo.Context, problem *models.Problem) error {
+	form := UpdateProblemForm{Title: f.Title}
+	return form.Update(c, problem)
+}
+
+// cloneProblem creates new problem that references package and
+// statements of problem.
+//
+// Clone is updated independently, so variant of problem can be made
+// without changes in contests that use original problem. Extra tests
+// and uploaded checkers are not copied.
+func (v *View) cloneProblem(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	original, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	var form CloneProblemForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	problem := models.Problem{
+		Config:     original.Config.Clone(),
+		Title:      original.Title,
+		PackageID:  original.PackageID,
+		CompiledID: original.CompiledID,
+	}
+	if err := form.Update(c, &problem); err != nil {
+		return c.JSON(http.StatusBadRequest, err)
+	}
+	if account := accountCtx.Account; account != nil {
+		problem.OwnerID = NInt64(account.ID)
+	}
+	if form.ScopeID != nil {
+		if err := v.checkEntityScope(c, accountCtx, *form.ScopeID); err != nil {
+			return err
+		}
+		problem.ScopeID = NInt64(*form.ScopeID)
+	}
+	if err := syncStore(c, v.core.ProblemResources); err != nil {
+		return err
+	}
+	resources, err := collectRows(v.core.ProblemResources.FindByProblem(
+		getContext(c), original.ID,
+	))
+	if err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.Problems.Create(ctx, &problem); err != nil {
+			return err
+		}
+		for _, resource := range resources {
+			resource.ProblemID = problem.ID
+			if err := v.core.ProblemResources.Create(ctx, &resource); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	permissions := v.getProblemPermissions(accountCtx, problem)
+	return c.JSON(
+		http.StatusCreated,
+		v.makeProblem(c, problem, permissions, false, false, nil),
+	)
+}
+
 func (v *View) updateProblem(c echo.Context) error {
 	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
 	if !ok {
@@ -457,16 +730,18 @@ func (v *View) updateProblem(c echo.Context) error {
 			if err != nil {
 				if err == sql.ErrNoRows {
 					return errorResponse{
-						Code:    http.StatusBadRequest,
-						Message: localize(c, "User not found."),
+						Code:      http.StatusBadRequest,
+						ErrorCode: CodeUserNotFound,
+						Message:   localize(c, "User not found."),
 					}
 				}
 				return err
 			}
 			if account.Kind != models.UserAccountKind {
 				return errorResponse{
-					Code:    http.StatusBadRequest,
-					Message: localize(c, "User not found."),
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeUserNotFound,
+					Message:   localize(c, "User not found."),
 				}
 			}
 			problem.OwnerID = models.NInt64(*form.OwnerID)
@@ -475,13 +750,20 @@ func (v *View) updateProblem(c echo.Context) error {
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
 	}
+	if form.ScopeID != nil {
+		if err := v.checkEntityScope(c, accountCtx, *form.ScopeID); err != nil {
+			return err
+		}
+		problem.ScopeID = models.NInt64(*form.ScopeID)
+	}
 	var formFile *models.File
-	if form.PackageFile != nil {
-		file, err := v.files.UploadFile(getContext(c), form.PackageFile)
+	if form.PackageFile != nil || form.UploadID != nil {
+		file, err := v.uploadProblemPackage(c, accountCtx, form)
 		if err != nil {
 			return err
 		}
@@ -498,6 +780,7 @@ func (v *View) updateProblem(c echo.Context) error {
 				ProblemID: problem.ID,
 				FileID:    formFile.ID,
 				Compile:   true,
+				Rejudge:   form.Rejudge,
 			}); err != nil {
 				return err
 			}
@@ -517,6 +800,7 @@ func (v *View) updateProblem(c echo.Context) error {
 
 type RebuildProblemForm struct {
 	Compile bool `json:"compile"`
+	Rejudge bool `json:"rejudge"`
 }
 
 func (v *View) rebuildProblem(c echo.Context) error {
@@ -546,6 +830,7 @@ func (v *View) rebuildProblem(c echo.Context) error {
 			ProblemID: problem.ID,
 			FileID:    int64(problem.PackageID),
 			Compile:   problem.CompiledID == 0 || form.Compile,
+			Rejudge:   form.Rejudge,
 		}); err != nil {
 			return err
 		}
@@ -597,6 +882,45 @@ func (v *View) deleteProblem(c echo.Context) error {
 		if err := resources.Err(); err != nil {
 			return err
 		}
+		tests, err := v.core.ProblemExtraTests.FindByProblem(ctx, problem.ID)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tests.Close() }()
+		for tests.Next() {
+			if err := v.core.ProblemExtraTests.Delete(ctx, tests.Row().ID); err != nil {
+				return err
+			}
+		}
+		if err := tests.Err(); err != nil {
+			return err
+		}
+		checkers, err := v.core.ProblemCheckers.FindByProblem(ctx, problem.ID)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = checkers.Close() }()
+		for checkers.Next() {
+			if err := v.core.ProblemCheckers.Delete(ctx, checkers.Row().ID); err != nil {
+				return err
+			}
+		}
+		if err := checkers.Err(); err != nil {
+			return err
+		}
+		grants, err := v.core.ProblemGrants.FindByProblem(ctx, problem.ID)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = grants.Close() }()
+		for grants.Next() {
+			if err := v.core.ProblemGrants.Delete(ctx, grants.Row().ID); err != nil {
+				return err
+			}
+		}
+		if err := grants.Err(); err != nil {
+			return err
+		}
 		return v.core.Problems.Delete(ctx, problem.ID)
 	}, sqlRepeatableRead); err != nil {
 		return err
@@ -607,6 +931,23 @@ func (v *View) deleteProblem(c echo.Context) error {
 	)
 }
 
+func (v *View) makeProblemRejudge(c echo.Context, taskIDs []int64) *ProblemRejudge {
+	resp := ProblemRejudge{Total: len(taskIDs)}
+	for _, id := range taskIDs {
+		task, err := v.core.Tasks.Get(getContext(c), id)
+		if err != nil {
+			// Removed tasks are considered as finished.
+			resp.Finished++
+			continue
+		}
+		switch task.Status {
+		case models.SucceededTask, models.FailedTask:
+			resp.Finished++
+		}
+	}
+	return &resp
+}
+
 func (v *View) findProblemTask(c echo.Context, id int64) (models.Task, error) {
 	tasks, err := v.core.Tasks.FindByProblem(getContext(c), id)
 	if err != nil {
@@ -641,8 +982,9 @@ func (v *View) extractProblem(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid problem ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidProblemID,
+				Message:   localize(c, "Invalid problem ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Problems); err != nil {
@@ -652,8 +994,9 @@ func (v *View) extractProblem(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Problem not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeProblemNotFound,
+					Message:   localize(c, "Problem not found."),
 				}
 			}
 			return err
@@ -672,14 +1015,64 @@ func (v *View) getProblemPermissions(
 	ctx *managers.AccountContext, problem models.Problem,
 ) perms.PermissionSet {
 	permissions := ctx.Permissions.Clone()
-	if account := ctx.Account; account != nil &&
-		problem.OwnerID != 0 && account.ID == int64(problem.OwnerID) {
+	isOwner := false
+	if account := ctx.Account; account != nil {
+		isOwner = problem.OwnerID != 0 && account.ID == int64(problem.OwnerID)
+	}
+	// Scope administrator manages all problems of scope.
+	if !isOwner && problem.ScopeID != 0 {
+		isOwner = managers.IsScopeAdmin(ctx, v.core.Scopes, int64(problem.ScopeID))
+	}
+	if isOwner {
 		permissions.AddPermission(
 			perms.ObserveProblemRole,
 			perms.UpdateProblemRole,
 			perms.UpdateProblemOwnerRole,
 			perms.DeleteProblemRole,
+			perms.ObserveProblemGrantsRole,
+			perms.CreateProblemGrantRole,
+			perms.DeleteProblemGrantRole,
 		)
 	}
+	for kind := range v.findProblemAccountGrants(ctx, problem.ID) {
+		switch kind {
+		case models.ReadProblemGrant:
+			permissions.AddPermission(perms.ObserveProblemRole)
+		case models.WriteProblemGrant:
+			permissions.AddPermission(
+				perms.ObserveProblemRole,
+				perms.UpdateProblemRole,
+			)
+		}
+	}
 	return permissions
 }
+
+// findProblemAccountGrants returns kinds of problem grants for account
+// and its groups.
+func (v *View) findProblemAccountGrants(
+	ctx *managers.AccountContext, problemID int64,
+) map[models.ProblemGrantKind]struct{} {
+	kinds := map[models.ProblemGrantKind]struct{}{}
+	if ctx.Account == nil {
+		return kinds
+	}
+	accountIDs := []int64{ctx.Account.ID}
+	for _, group := range ctx.GroupAccounts {
+		accountIDs = append(accountIDs, group.ID)
+	}
+	for _, accountID := range accountIDs {
+		rows, err := v.core.ProblemGrants.FindByProblemAccount(ctx, problemID, accountID)
+		if err != nil {
+			continue
+		}
+		grants, err := db.CollectRows(rows)
+		if err != nil {
+			continue
+		}
+		for _, grant := range grants {
+			kinds[grant.Kind] = struct{}{}
+		}
+	}
+	return kinds
+}
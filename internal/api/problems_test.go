@@ -3,10 +3,13 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/udovin/solve/internal/config"
 	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/models"
 )
@@ -52,6 +55,296 @@ func TestProblemsSimpleScenario(t *testing.T) {
 	}
 }
 
+type withServerLimits config.ServerLimits
+
+func (o withServerLimits) UpdateConfig(cfg *config.Config) {
+	limits := config.ServerLimits(o)
+	cfg.Server = &config.Server{Limits: &limits}
+}
+
+func (o withServerLimits) Setup(env *TestEnv) error {
+	return nil
+}
+
+func TestRequestBodyLimits(t *testing.T) {
+	e := NewTestEnv(t, withServerLimits{
+		BodySize:       1024,
+		SubmitBodySize: 2048,
+		UploadBodySize: 4096,
+	})
+	defer e.Close()
+	user := NewTestUser(e)
+	user.LoginClient()
+	if _, err := e.Client.CreateFileUpload(context.Background(), CreateFileUploadForm{
+		Name: strings.Repeat("a", 8192),
+		Size: 8192,
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusRequestEntityTooLarge, resp.StatusCode())
+	}
+	upload, err := e.Client.CreateFileUpload(context.Background(), CreateFileUploadForm{
+		Name: "data.bin",
+		Size: 8192,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	data := make([]byte, 8192)
+	if _, err := e.Client.UploadFileChunk(
+		context.Background(), upload.ID, 0, data[:2048],
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.UploadFileChunk(
+		context.Background(), upload.ID, 2048, data[2048:],
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusRequestEntityTooLarge, resp.StatusCode())
+	}
+}
+
+func TestUploadLimiter(t *testing.T) {
+	limiter := newUploadLimiter(3, 2)
+	if !limiter.Acquire("a") || !limiter.Acquire("a") {
+		t.Fatal("Expected acquired upload")
+	}
+	if limiter.Acquire("a") {
+		t.Fatal("Expected limit per key")
+	}
+	if !limiter.Acquire("b") {
+		t.Fatal("Expected acquired upload")
+	}
+	if limiter.Acquire("c") {
+		t.Fatal("Expected total limit")
+	}
+	limiter.Release("a")
+	if !limiter.Acquire("c") {
+		t.Fatal("Expected acquired upload")
+	}
+}
+
+func TestProblemChunkedUpload(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("create_problem")
+	other := NewTestUser(e)
+	user.LoginClient()
+	data, err := os.ReadFile(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	upload, err := e.Client.CreateFileUpload(context.Background(), CreateFileUploadForm{
+		Name: "a-plus-b.zip",
+		Size: int64(len(data)),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	chunkSize := len(data)/3 + 1
+	for offset := 0; offset < len(data); offset += chunkSize {
+		end := min(offset+chunkSize, len(data))
+		if _, err := e.Client.UploadFileChunk(
+			context.Background(), upload.ID, int64(offset), data[offset:end],
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+		// Repeated chunk should be rejected, so client can resume
+		// upload from actual offset.
+		_, err := e.Client.UploadFileChunk(
+			context.Background(), upload.ID, int64(offset), data[offset:end],
+		)
+		resp, ok := err.(statusCodeResponse)
+		if !ok {
+			t.Fatal("Invalid error:", err)
+		}
+		expectStatus(t, http.StatusConflict, resp.StatusCode())
+	}
+	upload, err = e.Client.ObserveFileUpload(context.Background(), upload.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if upload.Offset != int64(len(data)) || len(upload.Chunks) != 3 {
+		t.Fatalf("Unexpected upload: %v", upload)
+	}
+	form := CreateProblemForm{}
+	form.Title = getPtr("a-plus-b")
+	form.UploadID = getPtr(upload.ID)
+	{
+		_, err := e.Client.CreateProblem(context.Background(), form)
+		resp, ok := err.(statusCodeResponse)
+		if !ok {
+			t.Fatal("Invalid error:", err)
+		}
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	upload, err = e.Client.FinalizeFileUpload(context.Background(), upload.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !upload.Finalized {
+		t.Fatal("Expected finalized upload")
+	}
+	{
+		other.LoginClient()
+		_, err := e.Client.ObserveFileUpload(context.Background(), upload.ID)
+		resp, ok := err.(statusCodeResponse)
+		if !ok {
+			t.Fatal("Invalid error:", err)
+		}
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+		user.LoginClient()
+	}
+	problem, err := e.Client.CreateProblem(context.Background(), form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if problem.Title != "a-plus-b" {
+		t.Fatalf("Unexpected problem: %v", problem)
+	}
+	// Upload can be used only once.
+	if _, err := e.Client.CreateProblem(context.Background(), form); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestProblemPreview(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_problem")
+	user := NewTestUser(e)
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem", OwnerID: NInt64(owner.ID)}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	newForm := func() PreviewProblemForm {
+		file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		return PreviewProblemForm{PackageFile: managers.NewFileReader(file)}
+	}
+	user.LoginClient()
+	if _, err := e.Client.PreviewProblem(ctx, problem.ID, newForm()); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	invalidForm := PreviewProblemForm{PackageFile: &FileReader{
+		Name:   "package.zip",
+		Reader: strings.NewReader("invalid package"),
+	}}
+	if _, err := e.Client.PreviewProblem(ctx, problem.ID, invalidForm); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	preview, err := e.Client.PreviewProblem(ctx, problem.ID, newForm())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(preview.Statements) == 0 {
+		t.Fatal("Expected statements")
+	}
+	for _, statement := range preview.Statements {
+		if statement.Title == "" || len(statement.Samples) == 0 {
+			t.Fatalf("Unexpected statement: %v", statement)
+		}
+	}
+	if preview.Config == nil || preview.Config.TimeLimit == 0 {
+		t.Fatalf("Unexpected config: %v", preview.Config)
+	}
+	e.SyncStores()
+	if updated, err := e.Core.Problems.Get(ctx, problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if updated.PackageID != 0 {
+		t.Fatal("Package should not be applied")
+	}
+}
+
+func TestProblemGrants(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_problem")
+	coauthor := NewTestUser(e)
+	owner.LoginClient()
+	file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	form := CreateProblemForm{}
+	form.Title = getPtr("a-plus-b")
+	form.PackageFile = managers.NewFileReader(file)
+	problem, err := e.Client.CreateProblem(context.Background(), form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	updateForm := UpdateProblemForm{Title: getPtr("a-plus-b-2")}
+	coauthor.LoginClient()
+	if _, err := e.Client.UpdateProblem(context.Background(), problem.ID, updateForm); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	coauthor.LogoutClient()
+	owner.LoginClient()
+	grant, err := e.Client.CreateProblemGrant(context.Background(), problem.ID, CreateProblemGrantForm{
+		AccountID: coauthor.ID,
+		Kind:      models.WriteProblemGrant,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(grant)
+	if grants, err := e.Client.ObserveProblemGrants(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(grants)
+	}
+	owner.LogoutClient()
+	e.SyncStores()
+	coauthor.LoginClient()
+	if updated, err := e.Client.UpdateProblem(context.Background(), problem.ID, updateForm); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(updated)
+	}
+	if _, err := e.Client.DeleteProblem(context.Background(), problem.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	coauthor.LogoutClient()
+	owner.LoginClient()
+	if _, err := e.Client.DeleteProblem(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	owner.LogoutClient()
+}
+
 func NewTestCompiler(e *TestEnv) Compiler {
 	file, err := os.Open(filepath.Join(testDataDir, "alpine-cpp.tar.gz"))
 	if err != nil {
@@ -148,3 +441,432 @@ func TestProblemBuildScenario(t *testing.T) {
 	// Create interactive problem.
 	NewTestInteractiveProblem(e)
 }
+
+func TestProblemStress(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("create_compiler", "create_problem", "create_setting")
+	user.LoginClient()
+	compiler := NewTestCompiler(e)
+	file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemForm := CreateProblemForm{}
+	problemForm.Title = getPtr("a-plus-b")
+	problemForm.PackageFile = managers.NewFileReader(file)
+	problem, err := e.Client.CreateProblem(context.Background(), problemForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	form := CreateProblemStressForm{
+		Generator: StressSourceForm{
+			CompilerID: compiler.ID,
+			Content:    "int main() { return 0; }",
+		},
+		ModelSolution: StressSourceForm{
+			CompilerID: compiler.ID,
+			Content:    "int main() { return 0; }",
+		},
+		Solution: StressSourceForm{
+			CompilerID: compiler.ID + 1,
+			Content:    "int main() { return 0; }",
+		},
+		Tests: 5000,
+	}
+	if _, err := e.Client.CreateProblemStress(context.Background(), problem.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.Solution.CompilerID = compiler.ID
+	form.Tests = 0
+	stress, err := e.Client.CreateProblemStress(context.Background(), problem.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(stress)
+	if observed, err := e.Client.ObserveProblemStress(context.Background(), problem.ID, stress.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(observed)
+	}
+	if _, err := e.Client.ObserveProblemStress(context.Background(), problem.ID, stress.ID+1); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+}
+
+func TestProblemTestValidation(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("create_problem")
+	user.LoginClient()
+	file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemForm := CreateProblemForm{}
+	problemForm.Title = getPtr("a-plus-b")
+	problemForm.PackageFile = managers.NewFileReader(file)
+	problem, err := e.Client.CreateProblem(context.Background(), problemForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	if _, err := e.Client.ValidateProblemTest(context.Background(), problem.ID, ValidateProblemTestForm{}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form := ValidateProblemTestForm{Input: getPtr("1 2\n")}
+	validation, err := e.Client.ValidateProblemTest(context.Background(), problem.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(validation)
+	if observed, err := e.Client.ObserveProblemTestValidation(context.Background(), problem.ID, validation.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(observed)
+	}
+}
+
+func TestProblemExtraTests(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("create_problem")
+	user.LoginClient()
+	file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemForm := CreateProblemForm{}
+	problemForm.Title = getPtr("a-plus-b")
+	problemForm.PackageFile = managers.NewFileReader(file)
+	problem, err := e.Client.CreateProblem(context.Background(), problemForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	form := CreateProblemExtraTestForm{Input: getPtr("1 2\n")}
+	if _, err := e.Client.CreateProblemExtraTest(context.Background(), problem.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.Answer = getPtr("3\n")
+	test, err := e.Client.CreateProblemExtraTest(context.Background(), problem.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(test)
+	// Emulate successful validation of test.
+	extraTest, err := e.Core.ProblemExtraTests.Get(models.WithSync(context.Background()), test.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	extraTest.Status = models.ActiveProblemExtraTest
+	if err := e.Core.ProblemExtraTests.Update(context.Background(), extraTest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if tests, err := e.Client.ObserveProblemExtraTests(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(tests)
+	}
+	if deleted, err := e.Client.DeleteProblemExtraTest(context.Background(), problem.ID, test.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(deleted)
+	}
+	if _, err := e.Client.DeleteProblemExtraTest(context.Background(), problem.ID, test.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+	if tests, err := e.Client.ObserveProblemExtraTests(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(tests)
+	}
+}
+
+func TestProblemCheckers(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("create_problem", "create_compiler", "create_setting")
+	user.LoginClient()
+	compiler := NewTestCompiler(e)
+	file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemForm := CreateProblemForm{}
+	problemForm.Title = getPtr("a-plus-b")
+	problemForm.PackageFile = managers.NewFileReader(file)
+	problem, err := e.Client.CreateProblem(context.Background(), problemForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	form := CreateProblemCheckerForm{Source: getPtr("int main() {}\n")}
+	if _, err := e.Client.CreateProblemChecker(context.Background(), problem.ID, form); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	form.CompilerID = compiler.ID
+	checker, err := e.Client.CreateProblemChecker(context.Background(), problem.ID, form)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(checker)
+	// Emulate successful compilation of checker.
+	problemChecker, err := e.Core.ProblemCheckers.Get(models.WithSync(context.Background()), checker.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemChecker.Status = models.CompiledProblemChecker
+	problemChecker.BinaryID = problemChecker.SourceID
+	if err := e.Core.ProblemCheckers.Update(context.Background(), problemChecker); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateProblemChecker(context.Background(), problem.ID, form); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if checkers, err := e.Client.ObserveProblemCheckers(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(checkers)
+	}
+	if deleted, err := e.Client.DeleteProblemChecker(context.Background(), problem.ID, checker.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(deleted)
+	}
+	if _, err := e.Client.DeleteProblemChecker(context.Background(), problem.ID, checker.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+	if checkers, err := e.Client.ObserveProblemCheckers(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(checkers)
+	}
+}
+
+func TestProblemUsages(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("create_problem", "create_contest")
+	user.LoginClient()
+	file, err := os.Open(filepath.Join(testDataDir, "a-plus-b.zip"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemForm := CreateProblemForm{}
+	problemForm.Title = getPtr("a-plus-b")
+	problemForm.PackageFile = managers.NewFileReader(file)
+	problem, err := e.Client.CreateProblem(context.Background(), problemForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if usages, err := e.Client.ObserveProblemUsages(context.Background(), problem.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(usages)
+	}
+	if _, err := e.Client.CloneProblem(context.Background(), problem.ID, CloneProblemForm{
+		Title: getPtr("abc"),
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	clone, err := e.Client.CloneProblem(context.Background(), problem.ID, CloneProblemForm{
+		Title: getPtr("a-plus-b variant"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(clone)
+	if usages, err := e.Client.ObserveProblemUsages(context.Background(), clone.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(usages)
+	}
+	cloned, err := e.Core.Problems.Get(models.WithSync(context.Background()), clone.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	original, err := e.Core.Problems.Get(context.Background(), problem.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if cloned.PackageID != original.PackageID {
+		t.Fatal("Expected same package:", cloned.PackageID, original.PackageID)
+	}
+}
+
+func TestProblemResourceCache(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_problems", "observe_problem")
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	files := managers.NewFileManager(e.Core)
+	file, err := files.UploadFile(ctx, &managers.FileReader{
+		Name:   "image.png",
+		Size:   5,
+		Reader: strings.NewReader("image"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := files.ConfirmUploadFile(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	resource := models.ProblemResource{
+		ProblemID: problem.ID,
+		FileID:    models.NInt64(file.ID),
+	}
+	if err := resource.SetConfig(models.ProblemStatementResourceConfig{
+		Name: "image.png",
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.ProblemResources.Create(ctx, &resource); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	resp, err := e.Client.ObserveProblem(ctx, problem.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	hash, ok := resp.ResourceHashes["image.png"]
+	if !ok || hash == "" {
+		t.Fatalf("Expected resource hash, got %v", resp.ResourceHashes)
+	}
+	content, header, err := e.Client.ObserveProblemContent(problem.ID, "image.png", hash)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(content) != "image" {
+		t.Fatalf("Unexpected content: %q", content)
+	}
+	if cache := header.Get("Cache-Control"); !strings.Contains(cache, "immutable") {
+		t.Fatalf("Expected immutable resource, got %q", cache)
+	}
+	_, header, err = e.Client.ObserveProblemContent(problem.ID, "image.png", "")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if cache := header.Get("Cache-Control"); cache != "private, no-cache" {
+		t.Fatalf("Expected revalidated resource, got %q", cache)
+	}
+}
+
+func TestProblemRecommendations(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting")
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	owner.LogoutClient()
+	user := NewTestUser(e)
+	user.AddRoles("observe_problems", "observe_problem")
+	var authors []*TestUser
+	for i := 0; i < 4; i++ {
+		authors = append(authors, NewTestUser(e))
+	}
+	var problems []models.Problem
+	for _, title := range []string{"Easy", "Medium", "Hard", "Unknown"} {
+		problem := models.Problem{Title: title}
+		if err := e.Core.Problems.Create(context.Background(), &problem); err != nil {
+			t.Fatal("Error:", err)
+		}
+		problems = append(problems, problem)
+	}
+	createSolution := func(authorID, problemID int64, verdict models.Verdict) {
+		solution := models.Solution{
+			Kind:       models.ContestSolutionKind,
+			ProblemID:  problemID,
+			CompilerID: compiler.ID,
+			AuthorID:   authorID,
+		}
+		if err := solution.SetReport(&models.SolutionReport{Verdict: verdict}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.Solutions.Create(context.Background(), &solution); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	for i, author := range authors {
+		createSolution(author.ID, problems[0].ID, models.Accepted)
+		if i%2 == 0 {
+			createSolution(author.ID, problems[1].ID, models.Accepted)
+		} else {
+			createSolution(author.ID, problems[1].ID, models.WrongAnswer)
+		}
+		createSolution(author.ID, problems[2].ID, models.TimeLimitExceeded)
+	}
+	createSolution(user.ID, problems[0].ID, models.WrongAnswer)
+	createSolution(user.ID, problems[0].ID, models.Accepted)
+	e.SyncStores()
+	user.LoginClient()
+	defer user.LogoutClient()
+	recommendations, err := e.Client.ObserveProblemRecommendations(context.Background())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Check(recommendations)
+	var titles []string
+	for _, recommendation := range recommendations.Problems {
+		titles = append(titles, recommendation.Problem.Title)
+	}
+	if strings.Join(titles, ",") != "Medium,Unknown,Hard" {
+		t.Fatal("Invalid recommendations:", titles)
+	}
+}
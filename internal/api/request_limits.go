@@ -0,0 +1,210 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/managers"
+)
+
+// requestLimits contains resolved limits of requests.
+type requestLimits struct {
+	BodySize          int64
+	SubmitBodySize    int64
+	UploadBodySize    int64
+	BodyTimeout       time.Duration
+	UploadBodyTimeout time.Duration
+	Uploads           int
+	AccountUploads    int
+}
+
+// newRequestLimits returns limits from server config with defaults
+// for unspecified values.
+func newRequestLimits(cfg *config.Server) requestLimits {
+	limits := requestLimits{
+		BodySize:          4 << 20,
+		SubmitBodySize:    1 << 20,
+		UploadBodySize:    256 << 20,
+		BodyTimeout:       time.Minute,
+		UploadBodyTimeout: 15 * time.Minute,
+		Uploads:           16,
+		AccountUploads:    2,
+	}
+	if cfg == nil || cfg.Limits == nil {
+		return limits
+	}
+	if value := cfg.Limits.BodySize; value > 0 {
+		limits.BodySize = value
+	}
+	if value := cfg.Limits.SubmitBodySize; value > 0 {
+		limits.SubmitBodySize = value
+	}
+	if value := cfg.Limits.UploadBodySize; value > 0 {
+		limits.UploadBodySize = value
+	}
+	if value := cfg.Limits.BodyTimeout; value > 0 {
+		limits.BodyTimeout = time.Duration(value) * time.Second
+	}
+	if value := cfg.Limits.UploadBodyTimeout; value > 0 {
+		limits.UploadBodyTimeout = time.Duration(value) * time.Second
+	}
+	if value := cfg.Limits.Uploads; value > 0 {
+		limits.Uploads = value
+	}
+	if value := cfg.Limits.AccountUploads; value > 0 {
+		limits.AccountUploads = value
+	}
+	return limits
+}
+
+// limitRequestBody limits size and read time of request body with
+// default limits.
+//
+// Routes that accept larger bodies override limits using
+// limitSubmitBody and limitUploadBody middlewares, so requests are
+// rejected by Content-Length only when it exceeds all limits.
+func (v *View) limitRequestBody(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		maxSize := max(
+			v.limits.BodySize, v.limits.SubmitBodySize, v.limits.UploadBodySize,
+		)
+		if req.ContentLength > maxSize {
+			return errBodyTooLarge(c)
+		}
+		c.Set(requestBodyKey, req.Body)
+		setBodyLimit(c, req.Body, v.limits.BodySize, v.limits.BodyTimeout)
+		return next(c)
+	}
+}
+
+// limitSubmitBody overrides limits of request body for submission
+// of solutions.
+func (v *View) limitSubmitBody(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := overrideBodyLimit(
+			c, v.limits.SubmitBodySize, v.limits.BodyTimeout,
+		); err != nil {
+			return err
+		}
+		return next(c)
+	}
+}
+
+// limitUploadBody overrides limits of request body for requests with
+// files and limits amount of concurrent uploads.
+//
+// Middleware should be used after extractAuth, so uploads are counted
+// per account.
+func (v *View) limitUploadBody(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := "address:" + c.RealIP()
+		accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+		if ok && accountCtx.Account != nil {
+			key = fmt.Sprintf("account:%d", accountCtx.Account.ID)
+		}
+		if err := overrideBodyLimit(
+			c, v.limits.UploadBodySize, v.limits.UploadBodyTimeout,
+		); err != nil {
+			return err
+		}
+		if !v.uploads.Acquire(key) {
+			return errorResponse{
+				Code:      http.StatusTooManyRequests,
+				ErrorCode: CodeTooManyUploads,
+				Message:   localize(c, "Too many concurrent uploads."),
+			}
+		}
+		defer v.uploads.Release(key)
+		return next(c)
+	}
+}
+
+// overrideBodyLimit replaces default limits of request body.
+func overrideBodyLimit(c echo.Context, size int64, timeout time.Duration) error {
+	if c.Request().ContentLength > size {
+		return errBodyTooLarge(c)
+	}
+	body, ok := c.Get(requestBodyKey).(io.ReadCloser)
+	if !ok {
+		body = c.Request().Body
+	}
+	setBodyLimit(c, body, size, timeout)
+	return nil
+}
+
+// setBodyLimit wraps request body with reader that fails when body
+// is larger than specified size.
+func setBodyLimit(
+	c echo.Context, body io.ReadCloser, size int64, timeout time.Duration,
+) {
+	if body != nil && body != http.NoBody {
+		c.Request().Body = http.MaxBytesReader(c.Response(), body, size)
+	}
+	// Slow clients should not hold connections forever. Error is
+	// ignored because some response writers do not support deadlines.
+	_ = http.NewResponseController(c.Response()).SetReadDeadline(
+		time.Now().Add(timeout),
+	)
+}
+
+func errBodyTooLarge(c echo.Context) error {
+	return errorResponse{
+		Code:      http.StatusRequestEntityTooLarge,
+		ErrorCode: CodeRequestTooLarge,
+		Message:   localize(c, "Request body is too large."),
+	}
+}
+
+// isBodyTooLarge returns true if error is caused by limit of request
+// body size.
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// uploadLimiter limits amount of concurrent uploads in total and
+// per account or address.
+type uploadLimiter struct {
+	mutex     sync.Mutex
+	total     int
+	keys      map[string]int
+	maxTotal  int
+	maxPerKey int
+}
+
+func newUploadLimiter(maxTotal, maxPerKey int) *uploadLimiter {
+	return &uploadLimiter{
+		keys:      map[string]int{},
+		maxTotal:  maxTotal,
+		maxPerKey: maxPerKey,
+	}
+}
+
+// Acquire returns true if upload with specified key can be started.
+func (l *uploadLimiter) Acquire(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.total >= l.maxTotal || l.keys[key] >= l.maxPerKey {
+		return false
+	}
+	l.total++
+	l.keys[key]++
+	return true
+}
+
+// Release finishes upload with specified key.
+func (l *uploadLimiter) Release(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.total--
+	if l.keys[key]--; l.keys[key] <= 0 {
+		delete(l.keys, key)
+	}
+}
@@ -77,6 +77,21 @@ func (v *View) registerRoleHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth), v.extractUser, v.extractRole,
 		v.requirePermission(perms.DeleteUserRoleRole),
 	)
+	g.GET(
+		"/v0/groups/:group/roles", v.observeGroupRoles,
+		v.extractAuth(v.sessionAuth), v.extractGroup,
+		v.requirePermission(perms.ObserveGroupRolesRole),
+	)
+	g.POST(
+		"/v0/groups/:group/roles/:role", v.createGroupRole,
+		v.extractAuth(v.sessionAuth), v.extractGroup, v.extractRole,
+		v.requirePermission(perms.CreateGroupRoleRole),
+	)
+	g.DELETE(
+		"/v0/groups/:group/roles/:role", v.deleteGroupRole,
+		v.extractAuth(v.sessionAuth), v.extractGroup, v.extractRole,
+		v.requirePermission(perms.DeleteGroupRoleRole),
+	)
 }
 
 // registerUserHandlers registers handlers for user management.
@@ -159,6 +174,7 @@ func (f createRoleForm) Update(
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -170,7 +186,8 @@ func (f createRoleForm) Update(
 			return err
 		}
 		return errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeRoleAlreadyExists,
 			Message: localize(
 				c, "Role \"{role}\" already exists.",
 				replaceField("role", role.Name),
@@ -185,8 +202,9 @@ func (v *View) createRole(c echo.Context) error {
 	if err := c.Bind(&form); err != nil {
 		c.Logger().Warn(err)
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid form."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
 		}
 	}
 	var role models.Role
@@ -209,8 +227,9 @@ func (v *View) deleteRole(c echo.Context) error {
 	}
 	if perms.IsBuiltInRole(role.Name) {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Unable to delete builtin role."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeBuiltinRole,
+			Message:   localize(c, "Unable to delete builtin role."),
 		}
 	}
 	if err := v.core.Roles.Delete(getContext(c), role.ID); err != nil {
@@ -272,7 +291,8 @@ func (v *View) createRoleRole(c echo.Context) error {
 		return err
 	} else if edge != nil {
 		return errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeChildRoleAlreadyExists,
 			Message: localize(
 				c, "Role \"{role}\" already has child \"{child}\".",
 				replaceField("role", role.Name),
@@ -312,7 +332,8 @@ func (v *View) deleteRoleRole(c echo.Context) error {
 	}
 	if edge == nil {
 		return errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeChildRoleNotFound,
 			Message: localize(
 				c, "Role \"{role}\" does not have child \"{child}\".",
 				replaceField("role", role.Name),
@@ -376,7 +397,8 @@ func (v *View) createUserRole(c echo.Context) error {
 		return err
 	} else if edge != nil {
 		return errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeRoleAlreadyGranted,
 			Message: localize(
 				c, "User \"{user}\" already has role \"{role}\".",
 				replaceField("user", user.Login),
@@ -416,7 +438,8 @@ func (v *View) deleteUserRole(c echo.Context) error {
 	}
 	if edge == nil {
 		return errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeRoleNotGranted,
 			Message: localize(
 				c, "User \"{user}\" does not have role \"{role}\".",
 				replaceField("user", user.Login),
@@ -433,6 +456,121 @@ func (v *View) deleteUserRole(c echo.Context) error {
 	})
 }
 
+// observeGroupRoles returns roles that are attached to group.
+//
+// Attached roles are inherited by all members of group.
+func (v *View) observeGroupRoles(c echo.Context) error {
+	group, ok := c.Get(groupKey).(models.Group)
+	if !ok {
+		return fmt.Errorf("group not extracted")
+	}
+	if err := syncStore(c, v.core.AccountRoles); err != nil {
+		return err
+	}
+	ctx := getContext(c)
+	edges, err := v.core.AccountRoles.FindByAccount(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = edges.Close() }()
+	resp := Roles{}
+	for edges.Next() {
+		edge := edges.Row()
+		role, err := v.core.Roles.Get(ctx, edge.RoleID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.Logger().Warnf("Role %v not found", edge.RoleID)
+				continue
+			}
+			return err
+		}
+		resp.Roles = append(resp.Roles, Role{
+			ID:   role.ID,
+			Name: role.Name,
+		})
+	}
+	if err := edges.Err(); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) createGroupRole(c echo.Context) error {
+	group, ok := c.Get(groupKey).(models.Group)
+	if !ok {
+		return fmt.Errorf("group not extracted")
+	}
+	role, ok := c.Get(roleKey).(models.Role)
+	if !ok {
+		return fmt.Errorf("role not extracted")
+	}
+	if err := syncStore(c, v.core.AccountRoles); err != nil {
+		return err
+	}
+	ctx := getContext(c)
+	if edge, err := findAccountRole(ctx, v.core, group.ID, role.ID); err != nil {
+		return err
+	} else if edge != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeRoleAlreadyGranted,
+			Message: localize(
+				c, "Group \"{group}\" already has role \"{role}\".",
+				replaceField("group", group.Title),
+				replaceField("role", role.Name),
+			),
+		}
+	}
+	edge := models.AccountRole{
+		AccountID: group.ID,
+		RoleID:    role.ID,
+	}
+	if err := v.core.AccountRoles.Create(ctx, &edge); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, Role{
+		ID:   role.ID,
+		Name: role.Name,
+	})
+}
+
+func (v *View) deleteGroupRole(c echo.Context) error {
+	group, ok := c.Get(groupKey).(models.Group)
+	if !ok {
+		return fmt.Errorf("group not extracted")
+	}
+	role, ok := c.Get(roleKey).(models.Role)
+	if !ok {
+		return fmt.Errorf("role not extracted")
+	}
+	if err := syncStore(c, v.core.AccountRoles); err != nil {
+		return err
+	}
+	ctx := getContext(c)
+	edge, err := findAccountRole(ctx, v.core, group.ID, role.ID)
+	if err != nil {
+		return err
+	}
+	if edge == nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeRoleNotGranted,
+			Message: localize(
+				c, "Group \"{group}\" does not have role \"{role}\".",
+				replaceField("group", group.Title),
+				replaceField("role", role.Name),
+			),
+		}
+	}
+	if err := v.core.AccountRoles.Delete(ctx, edge.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, Role{
+		ID:   role.ID,
+		Name: role.Name,
+	})
+}
+
 func findAccountRole(ctx context.Context, c *core.Core, accountID int64, roleID int64) (*models.AccountRole, error) {
 	roles, err := c.AccountRoles.FindByAccount(ctx, accountID)
 	if err != nil {
@@ -485,6 +623,7 @@ func (v *View) extractRole(next echo.HandlerFunc) echo.HandlerFunc {
 		role, err := getRoleByParam(c, v.core.Roles, name)
 		if err == sql.ErrNoRows {
 			resp := errorResponse{
+				ErrorCode: CodeRoleNotFound,
 				Message: localize(
 					c, "Role \"{role}\" not found.",
 					replaceField("role", name),
@@ -509,6 +648,7 @@ func (v *View) extractChildRole(next echo.HandlerFunc) echo.HandlerFunc {
 		role, err := getRoleByParam(c, v.core.Roles, name)
 		if err == sql.ErrNoRows {
 			resp := errorResponse{
+				ErrorCode: CodeRoleNotFound,
 				Message: localize(
 					c, "Role \"{role}\" not found.",
 					replaceField("role", name),
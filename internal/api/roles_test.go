@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/http"
+	"slices"
 	"testing"
 )
 
@@ -137,3 +139,123 @@ func BenchmarkSqrtDepthRoles(b *testing.B) {
 		user.LogoutClient()
 	}
 }
+
+func TestRolePermissions(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	if _, err := e.Socket.CreateRole(ctx, "test_role"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Socket.CreateRoleRole("test_role", "observe_settings"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	admin := NewTestUser(e)
+	admin.AddRoles("admin_group")
+	user := NewTestUser(e)
+	user.AddRoles("test_role")
+	user.LoginClient()
+	if _, err := e.Client.ObserveRolePermissions(ctx); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	user.LogoutClient()
+	admin.LoginClient()
+	defer admin.LogoutClient()
+	matrix, err := e.Client.ObserveRolePermissions(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	found := false
+	for _, role := range matrix.Roles {
+		if role.Name == "test_role" {
+			found = true
+			if !slices.Contains(role.Permissions, "observe_settings") {
+				t.Fatalf("Unexpected permissions: %v", role.Permissions)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Role not found")
+	}
+	permissions, err := e.Client.ObserveAccountPermissions(ctx, user.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !slices.Contains(permissions.Permissions, "observe_settings") {
+		t.Fatalf("Unexpected permissions: %v", permissions.Permissions)
+	}
+	if slices.Contains(permissions.Permissions, "create_role") {
+		t.Fatalf("Unexpected permissions: %v", permissions.Permissions)
+	}
+	if _, err := e.Client.ObserveAccountPermissions(ctx, 100500); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+}
+
+func TestRolePermissionsCache(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	if _, err := e.Socket.CreateRole(ctx, "test_role"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Socket.CreateRoleRole("test_role", "observe_settings"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	user1 := NewTestUser(e)
+	user1.AddRoles("test_role")
+	user2 := NewTestUser(e)
+	user2.AddRoles("test_role")
+	e.SyncStores()
+	checkSettings := func(user *TestUser, expected int) {
+		user.LoginClient()
+		defer user.LogoutClient()
+		_, err := e.Client.ObserveSettings(ctx)
+		if expected == http.StatusOK {
+			if err != nil {
+				t.Fatal("Error:", err)
+			}
+			return
+		}
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		resp, ok := err.(statusCodeResponse)
+		if !ok {
+			t.Fatal("Invalid error:", err)
+		}
+		expectStatus(t, expected, resp.StatusCode())
+	}
+	checkSettings(user1, http.StatusOK)
+	// Accounts with the same roles share cached permissions.
+	checkSettings(user2, http.StatusOK)
+	if _, err := e.Socket.DeleteRoleRole("test_role", "observe_settings"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	checkSettings(user1, http.StatusForbidden)
+	checkSettings(user2, http.StatusForbidden)
+	if _, err := e.Socket.CreateRoleRole("test_role", "observe_settings"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	checkSettings(user1, http.StatusOK)
+	// Changes of account roles produce different role sets.
+	user2.AddRoles("admin_group")
+	e.SyncStores()
+	checkSettings(user2, http.StatusOK)
+	if _, err := e.Socket.DeleteRoleRole("test_role", "observe_settings"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	checkSettings(user1, http.StatusForbidden)
+	checkSettings(user2, http.StatusOK)
+}
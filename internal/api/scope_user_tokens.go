@@ -0,0 +1,294 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerScopeUserTokenHandlers(g *echo.Group) {
+	if v.core.ScopeUsers == nil {
+		return
+	}
+	g.POST(
+		"/v0/scopes/:scope/login-tokens", v.issueScopeUserLoginTokens,
+		v.extractAuth(v.sessionAuth), v.extractScope,
+		v.requirePermission(perms.UpdateScopeUserRole),
+	)
+}
+
+// ScopeUserLoginToken represents one-time login link of scope user.
+type ScopeUserLoginToken struct {
+	UserID     int64  `json:"user_id"`
+	Login      string `json:"login"`
+	Title      string `json:"title,omitempty"`
+	TokenID    int64  `json:"token_id"`
+	Secret     string `json:"secret"`
+	URL        string `json:"url,omitempty"`
+	ExpireTime int64  `json:"expire_time"`
+}
+
+// ScopeUserLoginTokens represents list of one-time login links.
+type ScopeUserLoginTokens struct {
+	Tokens []ScopeUserLoginToken `json:"tokens"`
+}
+
+// IssueScopeUserLoginTokensForm represents form for issuing one-time
+// login links for scope users.
+type IssueScopeUserLoginTokensForm struct {
+	// UserIDs contains list of users. Empty list means all users of scope.
+	UserIDs []int64 `json:"user_ids,omitempty"`
+	// ContestID contains contest of scope for which links are issued.
+	//
+	// Links expire at the end of contest by default.
+	ContestID *int64 `json:"contest_id,omitempty"`
+	// ExpireTime contains explicit expiration time of links.
+	ExpireTime *int64 `json:"expire_time,omitempty"`
+}
+
+const (
+	defaultLoginTokenDuration = 24 * 60 * 60
+	maxLoginTokenDuration     = 30 * 24 * 60 * 60
+)
+
+func (f IssueScopeUserLoginTokensForm) getExpireTime(
+	c echo.Context, v *View, scope models.Scope, errors errorFields,
+) (int64, error) {
+	now := getNow(c).Unix()
+	expireTime := now + defaultLoginTokenDuration
+	if f.ContestID != nil {
+		if err := syncStore(c, v.core.Contests); err != nil {
+			return 0, err
+		}
+		contest, err := v.core.Contests.Get(getContext(c), *f.ContestID)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return 0, err
+			}
+			errors["contest_id"] = errorField{
+				Message: localize(c, "Contest not found."),
+			}
+			return 0, nil
+		}
+		if int64(contest.ScopeID) != scope.ID {
+			errors["contest_id"] = errorField{
+				Message: localize(c, "Contest does not belong to scope."),
+			}
+			return 0, nil
+		}
+		config, err := contest.GetConfig()
+		if err != nil {
+			return 0, err
+		}
+		if config.BeginTime != 0 && config.Duration > 0 {
+			expireTime = int64(config.BeginTime) + int64(config.Duration)
+		}
+	}
+	if f.ExpireTime != nil {
+		expireTime = *f.ExpireTime
+	}
+	if expireTime <= now {
+		errors["expire_time"] = errorField{
+			Message: localize(c, "Expiration time should be in future."),
+		}
+	} else if expireTime > now+maxLoginTokenDuration {
+		errors["expire_time"] = errorField{
+			Message: localize(c, "Expiration time is too far in future."),
+		}
+	}
+	return expireTime, nil
+}
+
+func (v *View) getScopeUsersForTokens(
+	c echo.Context, scope models.Scope, ids []int64, errors errorFields,
+) ([]models.ScopeUser, error) {
+	if err := syncStore(c, v.core.ScopeUsers); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		users, err := v.core.ScopeUsers.FindByScope(scope.ID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = users.Close() }()
+		var result []models.ScopeUser
+		for users.Next() {
+			result = append(result, users.Row())
+		}
+		if err := users.Err(); err != nil {
+			return nil, err
+		}
+		sortFunc(result, func(lhs, rhs models.ScopeUser) bool {
+			return lhs.ID < rhs.ID
+		})
+		return result, nil
+	}
+	var result []models.ScopeUser
+	for _, id := range ids {
+		user, err := v.core.ScopeUsers.Get(getContext(c), id)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if err == sql.ErrNoRows || user.ScopeID != scope.ID {
+			errors["user_ids"] = errorField{
+				Message: localize(c, "User {id} not found.", replaceField("id", id)),
+			}
+			return nil, nil
+		}
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+func (v *View) getLoginTokenURL(token models.Token) string {
+	if v.core.Config.Server == nil || v.core.Config.Server.SiteURL == "" {
+		return ""
+	}
+	values := url.Values{}
+	values.Set("id", strconv.FormatInt(token.ID, 10))
+	values.Set("secret", token.Secret)
+	return v.core.Config.Server.SiteURL + "/login-token?" + values.Encode()
+}
+
+// issueScopeUserLoginTokens issues one-time login links for scope users.
+//
+// Links are intended to be printed on cards as QR codes, so secrets are
+// returned only once.
+func (v *View) issueScopeUserLoginTokens(c echo.Context) error {
+	scope, ok := c.Get(scopeKey).(models.Scope)
+	if !ok {
+		return fmt.Errorf("scope not extracted")
+	}
+	var form IssueScopeUserLoginTokensForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	errors := errorFields{}
+	expireTime, err := form.getExpireTime(c, v, scope, errors)
+	if err != nil {
+		return err
+	}
+	users, err := v.getScopeUsersForTokens(c, scope, form.UserIDs, errors)
+	if err != nil {
+		return err
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config := models.ScopeUserLoginTokenConfig{}
+	if form.ContestID != nil {
+		config.ContestID = *form.ContestID
+	}
+	now := getNow(c).Unix()
+	resp := ScopeUserLoginTokens{}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		resp.Tokens = nil
+		for _, user := range users {
+			token := models.Token{
+				AccountID:  user.ID,
+				CreateTime: now,
+				ExpireTime: expireTime,
+			}
+			if err := token.GenerateSecret(); err != nil {
+				return err
+			}
+			if err := token.SetConfig(config); err != nil {
+				return err
+			}
+			if err := v.core.Tokens.Create(ctx, &token); err != nil {
+				return err
+			}
+			resp.Tokens = append(resp.Tokens, ScopeUserLoginToken{
+				UserID:     user.ID,
+				Login:      user.Login,
+				Title:      string(user.Title),
+				TokenID:    token.ID,
+				Secret:     token.Secret,
+				URL:        v.getLoginTokenURL(token),
+				ExpireTime: token.ExpireTime,
+			})
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// consumeScopeUserLoginToken creates session for scope user and removes
+// token, so every token can be used only once.
+func (v *View) consumeScopeUserLoginToken(
+	c echo.Context, token models.Token,
+) error {
+	var config models.ScopeUserLoginTokenConfig
+	if err := token.ScanConfig(&config); err != nil {
+		return err
+	}
+	now := getNow(c)
+	session := models.Session{
+		AccountID:  token.AccountID,
+		CreateTime: now.Unix(),
+		ExpireTime: token.ExpireTime,
+		RealIP:     c.RealIP(),
+		UserAgent:  c.Request().UserAgent(),
+	}
+	if err := session.GenerateSecret(); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if _, err := v.core.ScopeUsers.Get(ctx, token.AccountID); err != nil {
+			return err
+		}
+		if err := v.core.Tokens.Delete(ctx, token.ID); err != nil {
+			return err
+		}
+		return v.core.Sessions.Create(ctx, &session)
+	}, sqlRepeatableRead); err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeInvalidTokenID,
+				Message:   localize(c, "Invalid token ID."),
+			}
+		}
+		return err
+	}
+	cookie := session.Cookie()
+	cookie.Name = sessionCookie
+	if v.core.Config.Security != nil {
+		cookie.Path = v.core.Config.Security.CookiePath
+	}
+	c.SetCookie(&cookie)
+	return c.JSON(http.StatusCreated, ScopeUserLoginSession{
+		Session: Session{
+			ID:         session.ID,
+			CreateTime: session.CreateTime,
+			ExpireTime: session.ExpireTime,
+		},
+		ContestID: config.ContestID,
+	})
+}
+
+// ScopeUserLoginSession represents session created by one-time login link.
+type ScopeUserLoginSession struct {
+	Session
+	// ContestID contains contest for which link was issued.
+	ContestID int64 `json:"contest_id,omitempty"`
+}
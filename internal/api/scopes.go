@@ -134,6 +134,7 @@ func (f *updateScopeForm) Update(c echo.Context, o *models.Scope) error {
 	if len(errors) > 0 {
 		return &errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -146,8 +147,9 @@ type createScopeForm updateScopeForm
 func (f *createScopeForm) Update(c echo.Context, o *models.Scope) error {
 	if f.Title == nil {
 		return &errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"title": errorField{
 					Message: localize(c, "Title is required."),
@@ -216,16 +218,18 @@ func (v *View) updateScope(c echo.Context) error {
 			if err != nil {
 				if err == sql.ErrNoRows {
 					return errorResponse{
-						Code:    http.StatusBadRequest,
-						Message: localize(c, "User not found."),
+						Code:      http.StatusBadRequest,
+						ErrorCode: CodeUserNotFound,
+						Message:   localize(c, "User not found."),
 					}
 				}
 				return err
 			}
 			if account.Kind != models.UserAccountKind {
 				return errorResponse{
-					Code:    http.StatusBadRequest,
-					Message: localize(c, "User not found."),
+					Code:      http.StatusBadRequest,
+					ErrorCode: CodeUserNotFound,
+					Message:   localize(c, "User not found."),
 				}
 			}
 			scope.OwnerID = models.NInt64(*form.OwnerID)
@@ -234,6 +238,7 @@ func (v *View) updateScope(c echo.Context) error {
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
@@ -302,10 +307,13 @@ type updateScopeUserForm struct {
 	Title            *string `json:"title"`
 	Password         *string `json:"password"`
 	GeneratePassword *bool   `json:"generate_password"`
+	Country          *string `json:"country"`
+	OrganizationID   *int64  `json:"organization_id"`
 }
 
 func (f *updateScopeUserForm) Update(
 	c echo.Context, o *models.ScopeUser, users *models.ScopeUserStore,
+	organizations *models.OrganizationStore,
 ) error {
 	errors := errorFields{}
 	if f.Login != nil {
@@ -344,9 +352,22 @@ func (f *updateScopeUserForm) Update(
 			o.PasswordSalt = ""
 		}
 	}
+	if f.Country != nil {
+		validateCountry(c, errors, *f.Country)
+		o.Country = models.NString(*f.Country)
+	}
+	if f.OrganizationID != nil {
+		if err := validateOrganization(
+			c, errors, organizations, *f.OrganizationID,
+		); err != nil {
+			return err
+		}
+		o.OrganizationID = models.NInt64(*f.OrganizationID)
+	}
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -358,11 +379,13 @@ type createScopeUserForm updateScopeUserForm
 
 func (f *createScopeUserForm) Update(
 	c echo.Context, o *models.ScopeUser, users *models.ScopeUserStore,
+	organizations *models.OrganizationStore,
 ) error {
 	if f.Login == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"login": errorField{Message: localize(c, "Login too short.")},
 			},
@@ -370,8 +393,9 @@ func (f *createScopeUserForm) Update(
 	}
 	if f.Title == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 			InvalidFields: errorFields{
 				"title": errorField{Message: localize(c, "Title is required.")},
 			},
@@ -380,7 +404,7 @@ func (f *createScopeUserForm) Update(
 	if f.GeneratePassword == nil && f.Password == nil {
 		f.GeneratePassword = getPtr(true)
 	}
-	return (*updateScopeUserForm)(f).Update(c, o, users)
+	return (*updateScopeUserForm)(f).Update(c, o, users, organizations)
 }
 
 func (v *View) createScopeUser(c echo.Context) error {
@@ -394,7 +418,7 @@ func (v *View) createScopeUser(c echo.Context) error {
 		return c.NoContent(http.StatusBadRequest)
 	}
 	var user models.ScopeUser
-	if err := form.Update(c, &user, v.core.ScopeUsers); err != nil {
+	if err := form.Update(c, &user, v.core.ScopeUsers, v.core.Organizations); err != nil {
 		return err
 	}
 	user.ScopeID = scope.ID
@@ -426,7 +450,7 @@ func (v *View) updateScopeUser(c echo.Context) error {
 		c.Logger().Warn(err)
 		return c.NoContent(http.StatusBadRequest)
 	}
-	if err := form.Update(c, &user, v.core.ScopeUsers); err != nil {
+	if err := form.Update(c, &user, v.core.ScopeUsers, v.core.Organizations); err != nil {
 		return err
 	}
 	if err := v.core.ScopeUsers.Update(getContext(c), user); err != nil {
@@ -487,8 +511,9 @@ func (v *View) extractScope(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid scope ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidScopeID,
+				Message:   localize(c, "Invalid scope ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Scopes); err != nil {
@@ -498,8 +523,9 @@ func (v *View) extractScope(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Scope not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeScopeNotFound,
+					Message:   localize(c, "Scope not found."),
 				}
 			}
 			return err
@@ -520,8 +546,9 @@ func (v *View) extractScopeUser(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid user ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidUserID,
+				Message:   localize(c, "Invalid user ID."),
 			}
 		}
 		if err := syncStore(c, v.core.ScopeUsers); err != nil {
@@ -531,16 +558,18 @@ func (v *View) extractScopeUser(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "User not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeUserNotFound,
+					Message:   localize(c, "User not found."),
 				}
 			}
 			return err
 		}
 		if user.ScopeID != scope.ID {
 			return errorResponse{
-				Code:    http.StatusNotFound,
-				Message: localize(c, "User not found."),
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeUserNotFound,
+				Message:   localize(c, "User not found."),
 			}
 		}
 		c.Set(scopeUserKey, user)
@@ -566,6 +595,40 @@ func (v *View) getScopePermissions(
 	return permissions
 }
 
+// checkEntityScope checks that account can place entity into scope.
+//
+// Zero scope means that entity does not belong to any scope.
+func (v *View) checkEntityScope(
+	c echo.Context, ctx *managers.AccountContext, scopeID int64,
+) error {
+	if scopeID == 0 {
+		return nil
+	}
+	if err := syncStore(c, v.core.Scopes); err != nil {
+		return err
+	}
+	scope, err := v.core.Scopes.Get(getContext(c), scopeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeScopeNotFound,
+				Message:   localize(c, "Scope not found."),
+			}
+		}
+		return err
+	}
+	if !v.getScopePermissions(ctx, scope).HasPermission(perms.UpdateScopeRole) {
+		return errorResponse{
+			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{perms.UpdateScopeRole},
+		}
+	}
+	return nil
+}
+
 type Scope struct {
 	ID    int64  `json:"id"`
 	Title string `json:"title"`
@@ -587,6 +650,10 @@ type ScopeUser struct {
 	Login    string `json:"login"`
 	Title    string `json:"title,omitempty"`
 	Password string `json:"password,omitempty"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country string `json:"country,omitempty"`
+	// OrganizationID contains ID of organization.
+	OrganizationID int64 `json:"organization_id,omitempty"`
 }
 
 type ScopeUsers struct {
@@ -595,9 +662,11 @@ type ScopeUsers struct {
 
 func makeScopeUser(user models.ScopeUser) ScopeUser {
 	return ScopeUser{
-		ID:    user.ID,
-		Login: user.Login,
-		Title: string(user.Title),
+		ID:             user.ID,
+		Login:          user.Login,
+		Title:          string(user.Title),
+		Country:        string(user.Country),
+		OrganizationID: int64(user.OrganizationID),
 	}
 }
 
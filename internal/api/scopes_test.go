@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestScopeUserLoginTokens(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	admin := NewTestUser(e)
+	admin.AddRoles("create_contest")
+	scope := models.Scope{Title: "Test scope", OwnerID: NInt64(admin.ID)}
+	var users []models.ScopeUser
+	if err := e.Core.WrapTx(context.Background(), func(ctx context.Context) error {
+		account := models.Account{Kind: scope.AccountKind()}
+		if err := e.Core.Accounts.Create(ctx, &account); err != nil {
+			return err
+		}
+		scope.ID = account.ID
+		if err := e.Core.Scopes.Create(ctx, &scope); err != nil {
+			return err
+		}
+		for _, login := range []string{"team1", "team2"} {
+			user := models.ScopeUser{ScopeID: scope.ID, Login: login}
+			if err := e.Core.ScopeUsers.SetPassword(&user, "qwerty123"); err != nil {
+				return err
+			}
+			account := models.Account{Kind: user.AccountKind()}
+			if err := e.Core.Accounts.Create(ctx, &account); err != nil {
+				return err
+			}
+			user.ID = account.ID
+			if err := e.Core.ScopeUsers.Create(ctx, &user); err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	admin.LoginClient()
+	publicContest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.IssueScopeUserLoginTokens(
+		context.Background(), scope.ID,
+		IssueScopeUserLoginTokensForm{ContestID: getPtr(publicContest.ID)},
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	tokens, err := e.Client.IssueScopeUserLoginTokens(
+		context.Background(), scope.ID, IssueScopeUserLoginTokensForm{},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(tokens.Tokens) != len(users) {
+		t.Fatalf("Expected %d tokens, got %d", len(users), len(tokens.Tokens))
+	}
+	for i, token := range tokens.Tokens {
+		if token.UserID != users[i].ID {
+			t.Fatalf("Expected user %d, got %d", users[i].ID, token.UserID)
+		}
+		if token.ExpireTime != e.Now.Unix()+defaultLoginTokenDuration {
+			t.Fatalf("Unexpected expire time: %d", token.ExpireTime)
+		}
+	}
+	admin.LogoutClient()
+	token := tokens.Tokens[0]
+	if _, err := e.Client.ConsumeLoginToken(
+		context.Background(), token.TokenID, "invalid",
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+	session, err := e.Client.ConsumeLoginToken(
+		context.Background(), token.TokenID, token.Secret,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if session.ExpireTime != token.ExpireTime {
+		t.Fatalf("Expected expire time %d, got %d", token.ExpireTime, session.ExpireTime)
+	}
+	e.SyncStores()
+	status, err := e.Client.Status()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if status.ScopeUser == nil || status.ScopeUser.ID != token.UserID {
+		t.Fatalf("Expected scope user %d, got %v", token.UserID, status.ScopeUser)
+	}
+	// Token can be used only once.
+	if _, err := e.Client.ConsumeLoginToken(
+		context.Background(), token.TokenID, token.Secret,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusNotFound, resp.StatusCode())
+	}
+}
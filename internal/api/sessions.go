@@ -88,7 +88,8 @@ func (v *View) extractSession(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				resp := errorResponse{
-					Message: localize(c, "Session not found."),
+					ErrorCode: CodeSessionNotFound,
+					Message:   localize(c, "Session not found."),
 				}
 				return c.JSON(http.StatusNotFound, resp)
 			}
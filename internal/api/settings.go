@@ -96,8 +96,9 @@ type CreateSettingForm struct {
 func (f *CreateSettingForm) Update(c echo.Context, o *models.Setting) error {
 	if f.Key == nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Setting key cannot be empty."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeEmptySettingKey,
+			Message:   localize(c, "Setting key cannot be empty."),
 		}
 	}
 	return f.UpdateSettingForm.Update(c, o)
@@ -171,8 +172,9 @@ func (v *View) extractSetting(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Setting not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeSettingNotFound,
+					Message:   localize(c, "Setting not found."),
 				}
 			}
 			return err
@@ -1,8 +1,17 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
+
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
 )
 
 func TestObserveSettings(t *testing.T) {
@@ -37,3 +46,219 @@ func TestObserveSettings(t *testing.T) {
 		e.Check(updated)
 	}
 }
+
+func TestObserveBackupStatus(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_settings", "create_setting")
+	user.LoginClient()
+	defer user.LogoutClient()
+	status, err := e.Client.ObserveBackupStatus(context.Background())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if status.Enabled || status.Age != nil {
+		t.Fatalf("Unexpected status: %v", status)
+	}
+	createForm := CreateSettingForm{}
+	createForm.Key = getPtr(managers.LastBackupTimeSetting)
+	createForm.Value = getPtr(fmt.Sprint(e.Now.Add(-time.Hour).Unix()))
+	if _, err := e.Client.CreateSetting(context.Background(), createForm); err != nil {
+		t.Fatal("Error:", err)
+	}
+	status, err = e.Client.ObserveBackupStatus(context.Background())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if status.Age == nil || *status.Age != 3600 {
+		t.Fatalf("Unexpected status: %v", status)
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_settings", "create_setting", "update_setting")
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	createForm := CreateSettingForm{}
+	createForm.Key = getPtr(readOnlySetting)
+	createForm.Value = getPtr("true")
+	setting, err := e.Client.CreateSetting(ctx, createForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	status, err := e.Client.Status()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !status.ReadOnly {
+		t.Fatal("Expected read-only status")
+	}
+	if _, err := e.Client.MergeAccounts(ctx, MergeAccountsForm{
+		SourceID: 1, TargetID: 2,
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusServiceUnavailable, resp.StatusCode())
+	}
+	updateForm := UpdateSettingForm{}
+	updateForm.Value = getPtr("false")
+	if _, err := e.Client.UpdateSetting(ctx, setting.ID, updateForm); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	status, err = e.Client.Status()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if status.ReadOnly {
+		t.Fatal("Expected writable status")
+	}
+}
+
+func TestReadOnlyModeLogin(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_settings", "create_setting", "update_setting")
+	user.LoginClient()
+	ctx := context.Background()
+	createForm := CreateSettingForm{}
+	createForm.Key = getPtr(readOnlySetting)
+	createForm.Value = getPtr("true")
+	setting, err := e.Client.CreateSetting(ctx, createForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	// Administrator should be able to login in read-only mode.
+	user.LogoutClient()
+	user.LoginClient()
+	defer user.LogoutClient()
+	updateForm := UpdateSettingForm{}
+	updateForm.Value = getPtr("false")
+	data, err := json.Marshal(updateForm)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPatch,
+		e.Client.getURL("/v1/settings/%d", setting.ID), bytes.NewReader(data),
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var envelope struct {
+		Data Setting `json:"data"`
+	}
+	if _, err := e.Client.doRequest(req, http.StatusCreated, &envelope); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	status, err := e.Client.Status()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if status.ReadOnly {
+		t.Fatal("Expected writable status")
+	}
+}
+
+func TestFailedTasks(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	var taskIDs []int64
+	for i := 0; i < 3; i++ {
+		task := models.Task{Status: models.FailedTask}
+		if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+			SolutionID: int64(i + 1),
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.Tasks.Create(ctx, &task); err != nil {
+			t.Fatal("Error:", err)
+		}
+		taskIDs = append(taskIDs, task.ID)
+	}
+	failure := models.TaskFailure{
+		TaskID:   taskIDs[0],
+		Error:    "task panicked: test",
+		Stack:    "goroutine 1 [running]",
+		FailTime: 1000,
+	}
+	if err := failure.SetEnvironment(models.TaskFailureEnvironment{
+		Hostname: "invoker-1",
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.TaskFailures.Create(ctx, &failure); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Tasks.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	user := NewTestUser(e)
+	user.LoginClient()
+	defer user.LogoutClient()
+	if _, err := e.Client.ObserveFailedTasks(ctx); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok || resp.StatusCode() != http.StatusForbidden {
+		t.Fatal("Unexpected error:", err)
+	}
+	user.AddRoles("observe_failed_tasks", "requeue_failed_tasks")
+	tasks, err := e.Client.ObserveFailedTasks(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(tasks.Tasks) != 3 || tasks.Tasks[2].Error != failure.Error {
+		t.Fatalf("Unexpected tasks: %v", tasks)
+	}
+	task, err := e.Client.ObserveFailedTask(ctx, taskIDs[0])
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if task.Stack != failure.Stack || task.Environment == nil ||
+		task.Environment.Hostname != "invoker-1" {
+		t.Fatalf("Unexpected task: %v", task)
+	}
+	if _, err := e.Client.RequeueFailedTask(ctx, taskIDs[0]); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Core.TaskFailures.GetByTask(ctx, taskIDs[0]); err != sql.ErrNoRows {
+		t.Fatal("Expected failure to be removed, got:", err)
+	}
+	if _, err := e.Client.ObserveFailedTask(ctx, taskIDs[0]); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok || resp.StatusCode() != http.StatusNotFound {
+		t.Fatal("Unexpected error:", err)
+	}
+	requeued, err := e.Client.RequeueFailedTasks(ctx, RequeueFailedTasksForm{
+		Kind: "judge_solution",
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(requeued.TaskIDs) != 2 {
+		t.Fatalf("Unexpected requeued tasks: %v", requeued)
+	}
+	if err := e.Core.Tasks.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, id := range taskIDs {
+		task, err := e.Core.Tasks.Get(ctx, id)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if task.Status != models.QueuedTask {
+			t.Fatalf("Expected queued task, got %v", task.Status)
+		}
+	}
+}
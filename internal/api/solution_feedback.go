@@ -0,0 +1,142 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// applyFeedbackPolicy limits details of solution report according to
+// feedback policy of contest problem.
+//
+// Policy is not applied for accounts that can observe checker logs.
+// Details of tests are shown only when withLogs is true.
+func (v *View) applyFeedbackPolicy(
+	c echo.Context, resp *SolutionReport, report *models.SolutionReport,
+	problem models.ContestProblem, withLogs bool,
+) {
+	permissions, ok := c.Get(permissionCtxKey).(perms.Permissions)
+	if ok && permissions.HasPermission(perms.ObserveSolutionReportCheckerLogs) {
+		return
+	}
+	config, err := problem.GetConfig()
+	if err != nil || config.FeedbackPolicy == "" {
+		return
+	}
+	policy := config.FeedbackPolicy
+	if policy == models.SamplesFeedback && isContestFinished(c) {
+		policy = models.FullFeedback
+	}
+	switch policy {
+	case models.FullFeedback:
+		if withLogs && report != nil {
+			resp.Tests = nil
+			for _, test := range report.Tests {
+				resp.Tests = append(resp.Tests, v.makeFeedbackTest(c, test))
+			}
+		}
+	case models.SamplesFeedback:
+		resp.Tests = nil
+		resp.TestNumber = 0
+		if report == nil {
+			break
+		}
+		failed := false
+		for i, test := range report.Tests {
+			if !test.Sample {
+				continue
+			}
+			if !failed && test.Verdict != models.Accepted {
+				resp.TestNumber = i + 1
+				failed = true
+			}
+			if withLogs {
+				resp.Tests = append(resp.Tests, v.makeFeedbackTest(c, test))
+			}
+		}
+	case models.FirstFailedFeedback:
+		if report == nil {
+			break
+		}
+		resp.Tests = nil
+		resp.TestNumber = 0
+		for i, test := range report.Tests {
+			if test.Verdict == models.Accepted {
+				continue
+			}
+			resp.TestNumber = i + 1
+			if withLogs {
+				resp.Tests = []TestReport{v.makeFeedbackTest(c, test)}
+			}
+			break
+		}
+	case models.GroupsFeedback:
+		resp.Tests = nil
+		resp.TestNumber = 0
+		if withLogs && report != nil {
+			resp.Groups = makeTestGroupReports(report.Tests)
+		}
+	case models.VerdictFeedback:
+		resp.Tests = nil
+		resp.TestNumber = 0
+		resp.Groups = nil
+		resp.UsedTime = 0
+		resp.UsedMemory = 0
+	}
+}
+
+// isContestFinished returns true if contest is finished for effective
+// participant.
+func isContestFinished(c echo.Context) bool {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return false
+	}
+	return contestCtx.GetEffectiveContestTime().Stage() == managers.ContestFinished
+}
+
+func (v *View) makeFeedbackTest(c echo.Context, test models.TestReport) TestReport {
+	verdict := v.localizeVerdict(c, test.Verdict.String())
+	return TestReport{
+		Verdict:        test.Verdict,
+		UsedTime:       test.Usage.Time,
+		UsedMemory:     test.Usage.Memory,
+		Points:         test.Points,
+		VerdictCode:    verdict.Code,
+		VerdictMessage: verdict.Message,
+	}
+}
+
+// makeTestGroupReports returns subtotals of test groups in order of
+// their first occurrence.
+func makeTestGroupReports(tests []models.TestReport) []TestGroupReport {
+	var groups []TestGroupReport
+	indexes := map[string]int{}
+	for _, test := range tests {
+		index, ok := indexes[test.Group]
+		if !ok {
+			index = len(groups)
+			indexes[test.Group] = index
+			groups = append(groups, TestGroupReport{
+				Name:    test.Group,
+				Verdict: models.Accepted,
+			})
+		}
+		group := &groups[index]
+		group.Tests++
+		if test.Verdict == models.Accepted {
+			group.AcceptedTests++
+		} else if group.Verdict == models.Accepted {
+			group.Verdict = test.Verdict
+		}
+		if test.Points != nil {
+			points := *test.Points
+			if group.Points != nil {
+				points += *group.Points
+			}
+			group.Points = &points
+		}
+	}
+	return groups
+}
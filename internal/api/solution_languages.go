@@ -0,0 +1,177 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+)
+
+// solutionLanguageSignatures contains source fragments that are used for
+// detection of language by content.
+//
+// Languages are checked in specified order, so more specific languages
+// should be placed first.
+var solutionLanguageSignatures = []struct {
+	Language string
+	Patterns []string
+}{
+	{"go", []string{"package main"}},
+	{"java", []string{"public class ", "import java.", "public static void main"}},
+	{"c#", []string{"using System", "static void Main"}},
+	{"c++", []string{"#include <iostream>", "#include <bits/stdc++.h>", "using namespace std", "std::"}},
+	{"c", []string{"#include <stdio.h>", "#include<stdio.h>"}},
+	{"pascal", []string{"program ", "begin", "writeln"}},
+	{"python", []string{"def ", "print(", "input()", "import sys"}},
+}
+
+// solutionSignatureSize contains size of solution prefix that is used
+// for detection of language by content.
+const solutionSignatureSize = 4096
+
+// getLanguageName returns compiler language name without version.
+//
+// For example "C++ 17" will be transformed to "c++".
+func getLanguageName(language string) string {
+	fields := strings.Fields(strings.ToLower(language))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// detectSolutionLanguage returns language of solution by its content.
+func detectSolutionLanguage(content []byte) string {
+	for _, signature := range solutionLanguageSignatures {
+		for _, pattern := range signature.Patterns {
+			if bytes.Contains(content, []byte(pattern)) {
+				return signature.Language
+			}
+		}
+	}
+	return ""
+}
+
+type detectedCompiler struct {
+	Compiler models.Compiler
+	Language string
+}
+
+func (v *View) getDetectableCompilers(c echo.Context) ([]detectedCompiler, error) {
+	compilers, err := v.core.Compilers.All(getContext(c), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = compilers.Close() }()
+	var result []detectedCompiler
+	for compilers.Next() {
+		compiler := compilers.Row()
+		config, err := compiler.GetConfig()
+		if err != nil {
+			continue
+		}
+		result = append(result, detectedCompiler{
+			Compiler: compiler,
+			Language: getLanguageName(config.Language),
+		})
+	}
+	if err := compilers.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Compiler.ID < result[j].Compiler.ID
+	})
+	return result, nil
+}
+
+func hasCompilerExtension(compiler models.Compiler, ext string) bool {
+	config, err := compiler.GetConfig()
+	if err != nil {
+		return false
+	}
+	for _, extension := range config.Extensions {
+		if strings.EqualFold(extension, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectSolutionCompiler returns compiler for solution submitted without
+// compiler.
+//
+// Candidates are selected by file extension and by content if extension
+// is unknown. Default compiler of contest for candidate language is
+// preferred. Solution content is rewound to the beginning.
+func (v *View) detectSolutionCompiler(
+	c echo.Context, contestCtx *managers.ContestContext, file *FileReader,
+) (models.Compiler, error) {
+	allCompilers, err := v.getDetectableCompilers(c)
+	if err != nil {
+		return models.Compiler{}, err
+	}
+	var compilers []detectedCompiler
+	for _, compiler := range allCompilers {
+		if contestCtx.ContestConfig.IsCompilerAllowed(compiler.Compiler.ID) {
+			compilers = append(compilers, compiler)
+		}
+	}
+	var candidates []detectedCompiler
+	if ext := strings.TrimPrefix(filepath.Ext(file.Name), "."); ext != "" {
+		for _, compiler := range compilers {
+			if hasCompilerExtension(compiler.Compiler, ext) {
+				candidates = append(candidates, compiler)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		content, err := io.ReadAll(io.LimitReader(file.Reader, solutionSignatureSize))
+		if err != nil {
+			return models.Compiler{}, err
+		}
+		if _, err := file.Reader.Seek(0, io.SeekStart); err != nil {
+			return models.Compiler{}, err
+		}
+		if language := detectSolutionLanguage(content); language != "" {
+			for _, compiler := range compilers {
+				if compiler.Language == language {
+					candidates = append(candidates, compiler)
+				}
+			}
+		}
+	}
+	var defaults map[string]int64
+	if config, err := contestCtx.Contest.GetConfig(); err == nil {
+		defaults = config.DefaultCompilers
+	}
+	for _, candidate := range candidates {
+		id, ok := defaults[candidate.Language]
+		if !ok {
+			continue
+		}
+		for _, compiler := range compilers {
+			if compiler.Compiler.ID == id {
+				return compiler.Compiler, nil
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0].Compiler, nil
+	}
+	return models.Compiler{}, errorResponse{
+		Code:      http.StatusBadRequest,
+		ErrorCode: CodeInvalidFields,
+		Message:   localize(c, "Form has invalid fields."),
+		InvalidFields: errorFields{
+			"compiler_id": errorField{
+				Message: localize(c, "Cannot detect language of solution."),
+			},
+		},
+	}
+}
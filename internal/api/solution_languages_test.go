@@ -0,0 +1,24 @@
+package api
+
+import "testing"
+
+func TestDetectSolutionLanguage(t *testing.T) {
+	for _, test := range []struct {
+		Content  string
+		Language string
+	}{
+		{"#include <bits/stdc++.h>\nint main() {}\n", "c++"},
+		{"#include <stdio.h>\nint main() {}\n", "c"},
+		{"package main\n\nfunc main() {}\n", "go"},
+		{"import java.util.*;\npublic class Main {}\n", "java"},
+		{"a, b = map(int, input().split())\nprint(a + b)\n", "python"},
+		{"42\n", ""},
+	} {
+		if language := detectSolutionLanguage([]byte(test.Content)); language != test.Language {
+			t.Fatalf("Expected %q, got %q", test.Language, language)
+		}
+	}
+	if language := getLanguageName("C++ 17"); language != "c++" {
+		t.Fatalf("Expected %q, got %q", "c++", language)
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func (v *View) registerSolutionRetestHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/contests/:contest/solutions/:solution/retest",
+		v.createSolutionRetest, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestSolution,
+		v.requirePermission(perms.UpdateContestSolutionRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/solutions/:solution/retest/:task",
+		v.observeSolutionRetest, v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestSolution,
+		v.requirePermission(perms.UpdateContestSolutionRole),
+	)
+}
+
+// SolutionRetest represents run of solution on single test.
+type SolutionRetest struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Test   int    `json:"test"`
+	Stage  string `json:"stage,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// UpdateReport means that test in solution report is replaced.
+	UpdateReport bool               `json:"update_report,omitempty"`
+	CompileLog   string             `json:"compile_log,omitempty"`
+	Report       *models.TestReport `json:"report,omitempty"`
+	// Output contains prefix of solution output.
+	Output     string `json:"output,omitempty"`
+	CheckerLog string `json:"checker_log,omitempty"`
+}
+
+func makeSolutionRetest(task models.Task) SolutionRetest {
+	resp := SolutionRetest{
+		ID:     task.ID,
+		Status: task.Status.String(),
+	}
+	var config models.RetestSolutionTaskConfig
+	if err := task.ScanConfig(&config); err == nil {
+		resp.Test = config.Test
+		resp.UpdateReport = config.UpdateReport
+	}
+	var state models.RetestSolutionTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Stage = state.Stage
+		resp.Error = state.Error
+		resp.CompileLog = state.CompileLog
+		resp.Report = state.Report
+		resp.Output = state.Output
+		resp.CheckerLog = state.CheckerLog
+	}
+	return resp
+}
+
+// CreateSolutionRetestForm represents form for running solution on
+// single test.
+type CreateSolutionRetestForm struct {
+	// Test contains number of test starting from 1.
+	Test int `json:"test"`
+	// UpdateReport enables replacement of test in solution report.
+	UpdateReport bool `json:"update_report"`
+}
+
+func (f CreateSolutionRetestForm) Update(
+	c echo.Context, config *models.RetestSolutionTaskConfig,
+) error {
+	if f.Test <= 0 {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"test": errorField{
+					Message: localize(c, "Test should be positive."),
+				},
+			},
+		}
+	}
+	config.Test = f.Test
+	config.UpdateReport = f.UpdateReport
+	return nil
+}
+
+func (v *View) createSolutionRetest(c echo.Context) error {
+	contestSolution, ok := c.Get(contestSolutionKey).(models.ContestSolution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	var form CreateSolutionRetestForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	config := models.RetestSolutionTaskConfig{SolutionID: contestSolution.ID}
+	if err := form.Update(c, &config); err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := task.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeSolutionRetest(task))
+}
+
+func (v *View) observeSolutionRetest(c echo.Context) error {
+	contestSolution, ok := c.Get(contestSolutionKey).(models.ContestSolution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	id, err := strconv.ParseInt(c.Param("task"), 10, 64)
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTaskID,
+			Message:   localize(c, "Invalid task ID."),
+		}
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	task, err := v.core.Tasks.Get(getContext(c), id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	var config models.RetestSolutionTaskConfig
+	if task.Kind != models.RetestSolutionTask ||
+		task.ScanConfig(&config) != nil || config.SolutionID != contestSolution.ID {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeTaskNotFound,
+			Message:   localize(c, "Task not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeSolutionRetest(task))
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// solutionURLTimeout contains timeout of fetching solution by URL.
+const solutionURLTimeout = 10 * time.Second
+
+// getRawSolutionURL returns URL of raw file content for references
+// to files in git repositories.
+//
+// Blob URLs of GitHub and GitLab are rewritten to raw file URLs,
+// other URLs are returned as is.
+func getRawSolutionURL(u *url.URL) *url.URL {
+	raw := *u
+	switch u.Hostname() {
+	case "github.com":
+		// Path has format "/owner/repo/blob/ref/file".
+		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+		if len(parts) == 4 && parts[2] == "blob" {
+			raw.Host = "raw.githubusercontent.com"
+			raw.Path = "/" + parts[0] + "/" + parts[1] + "/" + parts[3]
+		}
+	case "gitlab.com":
+		raw.Path = strings.Replace(u.Path, "/-/blob/", "/-/raw/", 1)
+	}
+	return &raw
+}
+
+// isSolutionURLHostAllowed returns true if solutions can be fetched
+// from specified host.
+func (v *View) isSolutionURLHostAllowed(host string) bool {
+	security := v.core.Config.Security
+	if security == nil {
+		return false
+	}
+	for _, allowed := range security.SolutionURLHosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchSolutionURL downloads solution file by URL from one of allowed
+// hosts.
+func (v *View) fetchSolutionURL(c echo.Context, rawURL string) (*FileReader, error) {
+	invalidURL := errorResponse{
+		Code:      http.StatusBadRequest,
+		ErrorCode: CodeInvalidFields,
+		Message:   localize(c, "Form has invalid fields."),
+		InvalidFields: errorFields{
+			"url": errorField{Message: localize(c, "Invalid URL.")},
+		},
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, invalidURL
+	}
+	if !v.isSolutionURLHostAllowed(u.Hostname()) {
+		return nil, errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"url": errorField{
+					Message: localize(
+						c, "Host \"{host}\" is not allowed.",
+						replaceField("host", u.Hostname()),
+					),
+				},
+			},
+		}
+	}
+	rawFileURL := getRawSolutionURL(u)
+	client := http.Client{
+		Timeout: solutionURLTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("too many redirects")
+			}
+			host := req.URL.Hostname()
+			if host != rawFileURL.Hostname() && !v.isSolutionURLHostAllowed(host) {
+				return fmt.Errorf("redirect to host %q is not allowed", host)
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(
+		getContext(c), http.MethodGet, rawFileURL.String(), nil,
+	)
+	if err != nil {
+		return nil, invalidURL
+	}
+	unavailable := errorResponse{
+		Code:      http.StatusBadRequest,
+		ErrorCode: CodeSolutionURLUnavailable,
+		Message:   localize(c, "Cannot fetch solution by URL."),
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		c.Logger().Warn("Cannot fetch solution", err)
+		return nil, unavailable
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		c.Logger().Warn("Cannot fetch solution", logs.Any("status", resp.StatusCode))
+		return nil, unavailable
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSolutionFileSize+1))
+	if err != nil {
+		c.Logger().Warn("Cannot fetch solution", err)
+		return nil, unavailable
+	}
+	return &FileReader{
+		Name:   path.Base(rawFileURL.Path),
+		Reader: bytes.NewReader(content),
+		Size:   int64(len(content)),
+	}, nil
+}
@@ -9,12 +9,14 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
 	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/diff"
 )
 
 // registerSolutionHandlers registers handlers for solution management.
@@ -29,6 +31,27 @@ func (v *View) registerSolutionHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractSolution,
 		v.requirePermission(perms.ObserveSolutionRole),
 	)
+	g.GET(
+		"/v0/solutions/:solution/diff/:other", v.observeSolutionDiff,
+		v.extractAuth(v.sessionAuth), v.extractSolution,
+		v.requirePermission(perms.ObserveSolutionRole),
+	)
+	g.GET(
+		"/v0/solutions/:solution/tests/:test/artifacts/:artifact",
+		v.observeSolutionTestArtifact,
+		v.extractAuth(v.sessionAuth), v.extractSolution,
+		v.requirePermission(perms.ObserveSolutionReportCheckerLogs),
+	)
+	g.GET(
+		"/v0/users/:user/solutions", v.observeUserSolutions,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractUser,
+		v.requirePermission(perms.ObserveUserRole),
+	)
+	g.GET(
+		"/v0/users/:user/solutions/activity", v.observeUserSolutionsActivity,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractUser,
+		v.requirePermission(perms.ObserveUserRole),
+	)
 }
 
 type Solution struct {
@@ -114,6 +137,27 @@ type TestReport struct {
 	CheckLog   string         `json:"check_log,omitempty"`
 	Input      string         `json:"input,omitempty"`
 	Output     string         `json:"output,omitempty"`
+	// Artifacts contains names of available test artifacts.
+	Artifacts []string `json:"artifacts,omitempty"`
+	// VerdictCode contains machine-readable code of verdict.
+	VerdictCode string `json:"verdict_code,omitempty"`
+	// VerdictMessage contains localized message of verdict.
+	VerdictMessage string `json:"verdict_message,omitempty"`
+	// Points contains points for test.
+	Points *float64 `json:"points,omitempty"`
+	// TimeLimit contains effective time limit of test in milliseconds.
+	TimeLimit int64 `json:"time_limit,omitempty"`
+}
+
+// TestGroupReport represents subtotal of test group.
+type TestGroupReport struct {
+	Name    string         `json:"name,omitempty"`
+	Verdict models.Verdict `json:"verdict"`
+	Points  *float64       `json:"points,omitempty"`
+	// Tests contains amount of judged tests in group.
+	Tests int `json:"tests"`
+	// AcceptedTests contains amount of accepted tests in group.
+	AcceptedTests int `json:"accepted_tests"`
 }
 
 type SolutionReport struct {
@@ -124,9 +168,33 @@ type SolutionReport struct {
 	Tests      []TestReport `json:"tests,omitempty"`
 	TestNumber int          `json:"test_number,omitempty"`
 	CompileLog string       `json:"compile_log,omitempty"`
+	// Stage contains current stage of judgement.
+	Stage string `json:"stage,omitempty"`
+	// TotalTests contains total amount of tests for running judgement.
+	TotalTests int `json:"total_tests,omitempty"`
+	// VerdictCode contains machine-readable code of verdict.
+	VerdictCode string `json:"verdict_code,omitempty"`
+	// VerdictMessage contains localized message of verdict.
+	VerdictMessage string `json:"verdict_message,omitempty"`
+	// Groups contains subtotals of test groups.
+	Groups []TestGroupReport `json:"groups,omitempty"`
+	// Pretests means that verdict is preliminary and solution will be
+	// judged on full tests during system testing.
+	Pretests bool `json:"pretests,omitempty"`
 }
 
 func (v *View) makeSolutionReport(c echo.Context, solution models.Solution, withLogs bool) *SolutionReport {
+	resp := v.buildSolutionReport(c, solution, withLogs)
+	v.setReportVerdict(c, resp)
+	for i, test := range resp.Tests {
+		verdict := v.localizeVerdict(c, test.Verdict.String())
+		resp.Tests[i].VerdictCode = verdict.Code
+		resp.Tests[i].VerdictMessage = verdict.Message
+	}
+	return resp
+}
+
+func (v *View) buildSolutionReport(c echo.Context, solution models.Solution, withLogs bool) *SolutionReport {
 	report, err := solution.GetReport()
 	if err != nil {
 		return &SolutionReport{
@@ -149,6 +217,10 @@ func (v *View) makeSolutionReport(c echo.Context, solution models.Solution, with
 		var state models.JudgeSolutionTaskState
 		if err := task.ScanState(&state); err == nil {
 			resp.TestNumber = state.Test
+			if task.Status == models.RunningTask {
+				resp.Stage = state.Stage
+				resp.TotalTests = state.TotalTests
+			}
 		}
 		return &resp
 	}
@@ -161,6 +233,7 @@ func (v *View) makeSolutionReport(c echo.Context, solution models.Solution, with
 		Points:     report.Points,
 		UsedTime:   report.Usage.Time,
 		UsedMemory: report.Usage.Memory,
+		Pretests:   report.Pretests,
 	}
 	if report.Verdict != models.Accepted &&
 		permissions.HasPermission(perms.ObserveSolutionReportTestNumber) {
@@ -184,6 +257,8 @@ func (v *View) makeSolutionReport(c echo.Context, solution models.Solution, with
 				Verdict:    test.Verdict,
 				UsedTime:   test.Usage.Time,
 				UsedMemory: test.Usage.Memory,
+				Points:     test.Points,
+				TimeLimit:  test.TimeLimit,
 			}
 			if test.Interactor != nil {
 				testResp.CheckLog = test.Interactor.Log
@@ -191,6 +266,13 @@ func (v *View) makeSolutionReport(c echo.Context, solution models.Solution, with
 			if test.Checker != nil {
 				testResp.CheckLog = test.Checker.Log
 			}
+			if test.Artifacts != nil {
+				for _, name := range testArtifactNames {
+					if getTestArtifactID(*test.Artifacts, name) != 0 {
+						testResp.Artifacts = append(testResp.Artifacts, name)
+					}
+				}
+			}
 			resp.Tests = append(resp.Tests, testResp)
 		}
 	}
@@ -244,6 +326,10 @@ type solutionsFilter struct {
 	Verdict   models.Verdict `query:"verdict"`
 	BeginID   int64          `query:"begin_id"`
 	Limit     int            `query:"limit"`
+	// From contains minimal create time of solution (inclusive).
+	From int64 `query:"from"`
+	// To contains maximal create time of solution (exclusive).
+	To int64 `query:"to"`
 }
 
 const (
@@ -254,8 +340,9 @@ const (
 func (f *solutionsFilter) Parse(c echo.Context) error {
 	if err := c.Bind(f); err != nil {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid filter."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
 		}
 	}
 	if f.BeginID < 0 || f.BeginID == math.MaxInt64 {
@@ -275,6 +362,12 @@ func (f *solutionsFilter) Filter(solution models.Solution) bool {
 	if f.ProblemID != 0 && solution.ProblemID != f.ProblemID {
 		return false
 	}
+	if f.From != 0 && solution.CreateTime < f.From {
+		return false
+	}
+	if f.To != 0 && solution.CreateTime >= f.To {
+		return false
+	}
 	if f.Verdict != 0 {
 		report, err := solution.GetReport()
 		if err != nil {
@@ -328,6 +421,170 @@ func (v *View) observeSolutions(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+func (v *View) observeUserSolutions(c echo.Context) error {
+	user, ok := c.Get(userKey).(models.User)
+	if !ok {
+		return fmt.Errorf("user not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("auth not extracted")
+	}
+	filter := solutionsFilter{Limit: 250}
+	if err := filter.Parse(c); err != nil {
+		c.Logger().Warn(err)
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	solutions, err := v.core.Solutions.ReverseFindByAuthorFrom(
+		getContext(c), []int64{user.ID}, filter.BeginID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = solutions.Close() }()
+	resp := Solutions{Solutions: []Solution{}}
+	solutionsCount := 0
+	for solutions.Next() {
+		solution := solutions.Row()
+		if solutionsCount >= maxSolutionLimit ||
+			len(resp.Solutions) >= filter.Limit {
+			resp.NextBeginID = solution.ID
+			break
+		}
+		solutionsCount++
+		if !filter.Filter(solution) {
+			continue
+		}
+		permissions := v.getSolutionPermissions(accountCtx, solution)
+		if permissions.HasPermission(perms.ObserveSolutionRole) {
+			resp.Solutions = append(resp.Solutions, v.makeSolution(c, solution, false))
+		}
+	}
+	if err := solutions.Err(); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type SolutionsActivityDay struct {
+	// Date contains day in format "YYYY-MM-DD" (UTC).
+	Date              string `json:"date"`
+	TotalSolutions    int    `json:"total_solutions"`
+	AcceptedSolutions int    `json:"accepted_solutions"`
+	SolvedProblems    int    `json:"solved_problems"`
+}
+
+type SolutionsActivity struct {
+	Days     []SolutionsActivityDay `json:"days"`
+	Verdicts map[string]int         `json:"verdicts,omitempty"`
+}
+
+type solutionsActivityFilter struct {
+	From int64 `query:"from"`
+	To   int64 `query:"to"`
+}
+
+const maxSolutionsActivityDuration = 366 * 24 * time.Hour
+
+func (f *solutionsActivityFilter) Parse(c echo.Context) error {
+	if err := c.Bind(f); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFilter,
+			Message:   localize(c, "Invalid filter."),
+		}
+	}
+	if f.To == 0 {
+		f.To = getNow(c).Unix()
+	}
+	minFrom := f.To - int64(maxSolutionsActivityDuration/time.Second)
+	if f.From < minFrom {
+		f.From = minFrom
+	}
+	if f.From > f.To {
+		f.From = f.To
+	}
+	return nil
+}
+
+func (v *View) observeUserSolutionsActivity(c echo.Context) error {
+	user, ok := c.Get(userKey).(models.User)
+	if !ok {
+		return fmt.Errorf("user not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("auth not extracted")
+	}
+	var filter solutionsActivityFilter
+	if err := filter.Parse(c); err != nil {
+		c.Logger().Warn(err)
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	solutions, err := v.core.Solutions.FindByAuthor(getContext(c), user.ID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = solutions.Close() }()
+	type dayProblem struct {
+		Date      string
+		ProblemID int64
+	}
+	days := map[string]*SolutionsActivityDay{}
+	solved := map[dayProblem]struct{}{}
+	verdicts := map[string]int{}
+	for solutions.Next() {
+		solution := solutions.Row()
+		if solution.CreateTime < filter.From || solution.CreateTime >= filter.To {
+			continue
+		}
+		permissions := v.getSolutionPermissions(accountCtx, solution)
+		if !permissions.HasPermission(perms.ObserveSolutionRole) {
+			continue
+		}
+		report, err := solution.GetReport()
+		if err != nil || report == nil {
+			continue
+		}
+		date := time.Unix(solution.CreateTime, 0).UTC().Format(time.DateOnly)
+		day, ok := days[date]
+		if !ok {
+			day = &SolutionsActivityDay{Date: date}
+			days[date] = day
+		}
+		day.TotalSolutions++
+		verdicts[report.Verdict.String()]++
+		if report.Verdict == models.Accepted {
+			day.AcceptedSolutions++
+			key := dayProblem{Date: date, ProblemID: solution.ProblemID}
+			if _, ok := solved[key]; !ok {
+				solved[key] = struct{}{}
+				day.SolvedProblems++
+			}
+		}
+	}
+	if err := solutions.Err(); err != nil {
+		return err
+	}
+	resp := SolutionsActivity{Days: []SolutionsActivityDay{}}
+	for _, day := range days {
+		resp.Days = append(resp.Days, *day)
+	}
+	sortFunc(resp.Days, func(lhs, rhs SolutionsActivityDay) bool {
+		return lhs.Date < rhs.Date
+	})
+	if len(verdicts) > 0 {
+		resp.Verdicts = verdicts
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 func (v *View) observeSolution(c echo.Context) error {
 	solution, ok := c.Get(solutionKey).(models.Solution)
 	if !ok {
@@ -337,14 +594,174 @@ func (v *View) observeSolution(c echo.Context) error {
 	return c.JSON(http.StatusOK, v.makeSolution(c, solution, true))
 }
 
+type SolutionDiff struct {
+	SolutionID int64 `json:"solution_id"`
+	OtherID    int64 `json:"other_id"`
+	// Diff contains unified diff of solutions content.
+	Diff string `json:"diff"`
+}
+
+type ObserveSolutionDiffRequest struct {
+	ID      int64
+	OtherID int64
+	// Normalize enables removal of comments and whitespaces.
+	Normalize bool `query:"normalize"`
+}
+
+const solutionDiffContext = 3
+
+func (v *View) makeSolutionDiffContent(
+	c echo.Context, solution models.Solution, normalize bool,
+) string {
+	content := v.makeSolutionContent(c, solution)
+	if !normalize {
+		return content
+	}
+	var language string
+	if compiler, err := v.core.Compilers.Get(getContext(c), solution.CompilerID); err == nil {
+		if config, err := compiler.GetConfig(); err == nil {
+			language = config.Language
+		}
+	}
+	return diff.Normalize(content, language)
+}
+
+func (v *View) observeSolutionDiff(c echo.Context) error {
+	solution, ok := c.Get(solutionKey).(models.Solution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("auth not extracted")
+	}
+	var form ObserveSolutionDiffRequest
+	if err := c.Bind(&form); err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	otherID, err := strconv.ParseInt(c.Param("other"), 10, 64)
+	if err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidSolutionID,
+			Message:   localize(c, "Invalid solution ID."),
+		}
+	}
+	other, err := v.core.Solutions.Get(getContext(c), otherID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeSolutionNotFound,
+				Message:   localize(c, "Solution not found."),
+			}
+		}
+		return err
+	}
+	otherPermissions := v.getSolutionPermissions(accountCtx, other)
+	if !otherPermissions.HasPermission(perms.ObserveSolutionRole) {
+		return errorResponse{
+			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{perms.ObserveSolutionRole},
+		}
+	}
+	content := v.makeSolutionDiffContent(c, solution, form.Normalize)
+	otherContent := v.makeSolutionDiffContent(c, other, form.Normalize)
+	resp := SolutionDiff{
+		SolutionID: solution.ID,
+		OtherID:    other.ID,
+		Diff: diff.Unified(
+			fmt.Sprintf("solution/%d", solution.ID),
+			fmt.Sprintf("solution/%d", other.ID),
+			diff.Lines(content), diff.Lines(otherContent),
+			solutionDiffContext,
+		),
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+var testArtifactNames = []string{"output", "checker_log"}
+
+func getTestArtifactID(artifacts models.TestArtifacts, name string) int64 {
+	switch name {
+	case "output":
+		return artifacts.OutputID
+	case "checker_log":
+		return artifacts.CheckerLogID
+	default:
+		return 0
+	}
+}
+
+// observeSolutionTestArtifact returns compressed artifact of failed
+// solution test.
+func (v *View) observeSolutionTestArtifact(c echo.Context) error {
+	solution, ok := c.Get(solutionKey).(models.Solution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	test, err := strconv.Atoi(c.Param("test"))
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidTestNumber,
+			Message:   localize(c, "Invalid test number."),
+		}
+	}
+	report, err := solution.GetReport()
+	if err != nil {
+		return err
+	}
+	if report == nil || test < 1 || test > len(report.Tests) ||
+		report.Tests[test-1].Artifacts == nil {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeFileNotFound,
+			Message:   localize(c, "File not found."),
+		}
+	}
+	id := getTestArtifactID(*report.Tests[test-1].Artifacts, c.Param("artifact"))
+	if id == 0 {
+		return errorResponse{
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeFileNotFound,
+			Message:   localize(c, "File not found."),
+		}
+	}
+	if err := syncStore(c, v.core.Files); err != nil {
+		return err
+	}
+	file, err := v.core.Files.Get(getContext(c), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorResponse{
+				Code:      http.StatusNotFound,
+				ErrorCode: CodeFileNotFound,
+				Message:   localize(c, "File not found."),
+			}
+		}
+		return err
+	}
+	c.Set(fileKey, file)
+	return v.observeFileContent(c)
+}
+
 func (v *View) extractSolution(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		id, err := strconv.ParseInt(c.Param("solution"), 10, 64)
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid solution ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidSolutionID,
+				Message:   localize(c, "Invalid solution ID."),
 			}
 		}
 		if err := syncStore(c, v.core.Solutions); err != nil {
@@ -354,8 +771,9 @@ func (v *View) extractSolution(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Solution not found."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeSolutionNotFound,
+					Message:   localize(c, "Solution not found."),
 				}
 			}
 			c.Logger().Error(err)
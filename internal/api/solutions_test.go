@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestSolutionDiff(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	owner.LogoutClient()
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contents := []string{
+		"int main() {\n  // First solution.\n  return 0;\n}\n",
+		"int main() {\n\treturn  0; /* Second solution. */\n}\n",
+	}
+	var ids []int64
+	for _, content := range contents {
+		solution := models.Solution{
+			ProblemID:  problem.ID,
+			CompilerID: compiler.ID,
+			AuthorID:   user.ID,
+			Content:    models.NString(content),
+			CreateTime: e.Now.Unix(),
+		}
+		if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+			t.Fatal("Error:", err)
+		}
+		ids = append(ids, solution.ID)
+	}
+	e.SyncStores()
+	owner.LoginClient()
+	if _, err := e.Client.ObserveSolutionDiff(ctx, ObserveSolutionDiffRequest{
+		ID: ids[0], OtherID: ids[1],
+	}); err == nil {
+		t.Fatal("Expected error")
+	}
+	owner.LogoutClient()
+	user.LoginClient()
+	if resp, err := e.Client.ObserveSolutionDiff(ctx, ObserveSolutionDiffRequest{
+		ID: ids[0], OtherID: ids[1],
+	}); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(resp)
+	}
+	if resp, err := e.Client.ObserveSolutionDiff(ctx, ObserveSolutionDiffRequest{
+		ID: ids[0], OtherID: ids[1], Normalize: true,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	} else if resp.Diff != "" {
+		t.Fatalf("Expected empty diff, got %q", resp.Diff)
+	}
+	user.LogoutClient()
+}
+
+func TestSolutionTestArtifacts(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting", "observe_solution_report_checker_logs")
+	user := NewTestUser(e)
+	owner.LoginClient()
+	compiler := NewTestCompiler(e)
+	owner.LogoutClient()
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	files := managers.NewFileManager(e.Core)
+	content := []byte("compressed output")
+	file, err := files.UploadFile(ctx, &managers.FileReader{
+		Name:   "output.out.gz",
+		Size:   int64(len(content)),
+		Reader: bytes.NewReader(content),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := files.ConfirmUploadFile(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   user.ID,
+		CreateTime: e.Now.Unix(),
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.WrongAnswer,
+		Tests: []models.TestReport{
+			{Verdict: models.Accepted},
+			{
+				Verdict:   models.WrongAnswer,
+				Artifacts: &models.TestArtifacts{OutputID: file.ID},
+			},
+		},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	user.LoginClient()
+	if _, err := e.Client.ObserveSolutionTestArtifact(ctx, solution.ID, 2, "output"); err == nil {
+		t.Fatal("Expected error")
+	}
+	user.LogoutClient()
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	if data, err := e.Client.ObserveSolutionTestArtifact(ctx, solution.ID, 2, "output"); err != nil {
+		t.Fatal("Error:", err)
+	} else if !bytes.Equal(data, content) {
+		t.Fatalf("Expected %q, got %q", content, data)
+	}
+	for _, test := range []int{1, 3} {
+		if _, err := e.Client.ObserveSolutionTestArtifact(ctx, solution.ID, test, "output"); err == nil {
+			t.Fatal("Expected error")
+		}
+	}
+	if _, err := e.Client.ObserveSolutionTestArtifact(ctx, solution.ID, 2, "checker_log"); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestVerdicts(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	verdicts, err := e.Client.ObserveVerdicts(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	codes := map[string]string{}
+	for _, verdict := range verdicts.Verdicts {
+		if verdict.Code == "" || verdict.Message == "" {
+			t.Fatalf("Invalid verdict: %v", verdict)
+		}
+		if _, ok := codes[verdict.Code]; ok {
+			t.Fatalf("Duplicate verdict code: %q", verdict.Code)
+		}
+		codes[verdict.Code] = verdict.Name
+	}
+	if codes["AC"] != "accepted" || codes["WA"] != "wrong_answer" {
+		t.Fatalf("Unexpected verdicts: %v", codes)
+	}
+	owner := NewTestUser(e)
+	owner.AddRoles("create_compiler", "create_setting", "observe_solution_report_checker_logs")
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	compiler := NewTestCompiler(e)
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   owner.ID,
+		CreateTime: e.Now.Unix(),
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.WrongAnswer,
+		Tests: []models.TestReport{
+			{Verdict: models.Accepted},
+			{Verdict: models.WrongAnswer},
+		},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.SyncStores()
+	observed, err := e.Client.ObserveSolution(ctx, solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	report := observed.Report
+	if report == nil || report.VerdictCode != "WA" ||
+		report.VerdictMessage != "Wrong answer" {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	if len(report.Tests) != 2 || report.Tests[0].VerdictCode != "AC" {
+		t.Fatalf("Unexpected tests: %v", report.Tests)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/udovin/solve/internal/models"
@@ -36,11 +35,12 @@ func (v *View) consumeToken(c echo.Context) error {
 		c.Logger().Error("token not extracted")
 		return fmt.Errorf("token not extracted")
 	}
-	if token.ExpireTime <= time.Now().Unix() {
+	if token.ExpireTime <= getNow(c).Unix() {
 		_ = v.core.Tokens.Delete(c.Request().Context(), token.ID)
 		return errorResponse{
-			Code:    http.StatusNotFound,
-			Message: localize(c, "Invalid token ID."),
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeInvalidTokenID,
+			Message:   localize(c, "Invalid token ID."),
 		}
 	}
 	form := consumeTokenForm{}
@@ -49,8 +49,9 @@ func (v *View) consumeToken(c echo.Context) error {
 	}
 	if token.Secret != form.Secret {
 		return errorResponse{
-			Code:    http.StatusNotFound,
-			Message: localize(c, "Invalid token ID."),
+			Code:      http.StatusNotFound,
+			ErrorCode: CodeInvalidTokenID,
+			Message:   localize(c, "Invalid token ID."),
 		}
 	}
 	switch token.Kind {
@@ -80,15 +81,19 @@ func (v *View) consumeToken(c echo.Context) error {
 		if err := c.Bind(&form); err != nil {
 			return err
 		}
-		var errors errorFields
+		errors := errorFields{}
 		validatePassword(c, errors, form.Password)
 		if len(errors) > 0 {
 			return errorResponse{
 				Code:          http.StatusBadRequest,
+				ErrorCode:     CodeInvalidFields,
 				Message:       localize(c, "Form has invalid fields."),
 				InvalidFields: errors,
 			}
 		}
+		if err := v.checkPasswordPolicy(c, form.Password); err != nil {
+			return err
+		}
 		if err := v.core.WrapTx(c.Request().Context(), func(ctx context.Context) error {
 			user, err := v.core.Users.Get(ctx, token.AccountID)
 			if err != nil {
@@ -104,6 +109,14 @@ func (v *View) consumeToken(c echo.Context) error {
 		}, sqlRepeatableRead); err != nil {
 			return err
 		}
+	case models.ScopeUserLoginToken:
+		return v.consumeScopeUserLoginToken(c, token)
+	case models.ContestAPIToken:
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeTokenNotConsumable,
+			Message:   localize(c, "Token can not be consumed."),
+		}
 	default:
 		return fmt.Errorf("token %v not supported", token.Kind)
 	}
@@ -116,16 +129,18 @@ func (v *View) extractToken(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			c.Logger().Warn(err)
 			return errorResponse{
-				Code:    http.StatusBadRequest,
-				Message: localize(c, "Invalid token ID."),
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidTokenID,
+				Message:   localize(c, "Invalid token ID."),
 			}
 		}
 		token, err := v.core.Tokens.Get(getContext(c), id)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code:    http.StatusNotFound,
-					Message: localize(c, "Invalid token ID."),
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeInvalidTokenID,
+					Message:   localize(c, "Invalid token ID."),
 				}
 			}
 			c.Logger().Error(err)
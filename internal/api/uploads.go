@@ -0,0 +1,334 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+)
+
+func (v *View) registerUploadHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/uploads", v.createFileUpload,
+		v.extractAuth(v.sessionAuth),
+	)
+	g.GET(
+		"/v0/uploads/:upload", v.observeFileUpload,
+		v.extractAuth(v.sessionAuth), v.extractFileUpload,
+	)
+	g.PUT(
+		"/v0/uploads/:upload/chunks", v.uploadFileChunk,
+		v.extractAuth(v.sessionAuth), v.extractFileUpload,
+		v.limitUploadBody,
+	)
+	g.POST(
+		"/v0/uploads/:upload/finalize", v.finalizeFileUpload,
+		v.extractAuth(v.sessionAuth), v.extractFileUpload,
+	)
+}
+
+const (
+	// maxFileUploadSize contains maximal size of chunked upload.
+	maxFileUploadSize = 2 << 30
+	// maxFileChunkSize contains maximal size of single chunk.
+	maxFileChunkSize = 64 << 20
+)
+
+// FileUploadChunk represents uploaded chunk of file.
+type FileUploadChunk struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// FileUpload represents chunked upload of file.
+//
+// Finalized upload can be used instead of file in forms that accept
+// upload ID.
+type FileUpload struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+	Size int64  `json:"size"`
+	// Offset contains size of uploaded content.
+	Offset     int64             `json:"offset"`
+	Chunks     []FileUploadChunk `json:"chunks,omitempty"`
+	Finalized  bool              `json:"finalized,omitempty"`
+	ExpireTime int64             `json:"expire_time,omitempty"`
+}
+
+func makeFileUpload(file models.File) FileUpload {
+	upload := FileUpload{
+		ID:         file.ID,
+		ExpireTime: int64(file.ExpireTime),
+	}
+	meta, err := file.GetMeta()
+	if err != nil || meta.Upload == nil {
+		return upload
+	}
+	upload.Name = meta.Name
+	upload.Size = meta.Size
+	upload.Offset = meta.Upload.UploadedSize()
+	upload.Finalized = meta.Upload.Finalized
+	for _, chunk := range meta.Upload.Chunks {
+		upload.Chunks = append(upload.Chunks, FileUploadChunk{
+			Size:   chunk.Size,
+			SHA256: chunk.SHA256,
+		})
+	}
+	return upload
+}
+
+type CreateFileUploadForm struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+func (f CreateFileUploadForm) Validate(c echo.Context) error {
+	errors := errorFields{}
+	if len(f.Name) > 255 {
+		errors["name"] = errorField{
+			Message: localize(c, "Name is too long."),
+		}
+	}
+	if f.Size <= 0 {
+		errors["size"] = errorField{
+			Message: localize(c, "Size should be positive."),
+		}
+	} else if f.Size > maxFileUploadSize {
+		errors["size"] = errorField{
+			Message: localize(c, "File is too large."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) createFileUpload(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	if accountCtx.Account == nil {
+		return errorResponse{
+			Code:      http.StatusForbidden,
+			ErrorCode: CodeMissingPermissions,
+			Message:   localize(c, "Account missing permissions."),
+		}
+	}
+	var form CreateFileUploadForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidForm,
+			Message:   localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Validate(c); err != nil {
+		return err
+	}
+	file, err := v.files.CreateFileUpload(
+		getContext(c), accountCtx.Account.ID, form.Name, form.Size,
+	)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeFileUpload(file))
+}
+
+func (v *View) observeFileUpload(c echo.Context) error {
+	file, ok := c.Get(fileUploadKey).(models.File)
+	if !ok {
+		return fmt.Errorf("upload not extracted")
+	}
+	return c.JSON(http.StatusOK, makeFileUpload(file))
+}
+
+func (v *View) uploadFileChunk(c echo.Context) error {
+	file, ok := c.Get(fileUploadKey).(models.File)
+	if !ok {
+		return fmt.Errorf("upload not extracted")
+	}
+	offset, err := strconv.ParseInt(c.QueryParam("offset"), 10, 64)
+	if err != nil {
+		return errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidChunkOffset,
+			Message:   localize(c, "Invalid chunk offset."),
+		}
+	}
+	// Chunk is stored in temporary file because storage requires
+	// seekable content and chunk can be large.
+	chunk, err := os.CreateTemp("", "solve-chunk-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = chunk.Close()
+		_ = os.Remove(chunk.Name())
+	}()
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxFileChunkSize)
+	if _, err := io.Copy(chunk, body); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return errorResponse{
+				Code:      http.StatusRequestEntityTooLarge,
+				ErrorCode: CodeChunkTooLarge,
+				Message:   localize(c, "Chunk is too large."),
+			}
+		}
+		return err
+	}
+	if _, err := chunk.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := v.files.UploadFileChunk(
+		getContext(c), &file, offset, c.QueryParam("sha256"), chunk,
+	); err != nil {
+		switch err {
+		case managers.ErrFileUploadFinalized:
+			return errorResponse{
+				Code:      http.StatusConflict,
+				ErrorCode: CodeUploadFinalized,
+				Message:   localize(c, "Upload is already finalized."),
+			}
+		case managers.ErrFileChunkOffset:
+			return errorResponse{
+				Code:      http.StatusConflict,
+				ErrorCode: CodeInvalidChunkOffset,
+				Message:   localize(c, "Invalid chunk offset."),
+			}
+		case managers.ErrFileChunkSize:
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidChunkSize,
+				Message:   localize(c, "Invalid chunk size."),
+			}
+		case managers.ErrFileChunkChecksum:
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeChunkChecksumMismatch,
+				Message:   localize(c, "Chunk checksum mismatch."),
+			}
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, makeFileUpload(file))
+}
+
+func (v *View) finalizeFileUpload(c echo.Context) error {
+	file, ok := c.Get(fileUploadKey).(models.File)
+	if !ok {
+		return fmt.Errorf("upload not extracted")
+	}
+	if err := v.files.FinalizeFileUpload(getContext(c), &file); err != nil {
+		switch err {
+		case managers.ErrFileUploadIncomplete:
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeUploadIncomplete,
+				Message:   localize(c, "Upload is incomplete."),
+			}
+		case managers.ErrFileChunkChecksum:
+			return errorResponse{
+				Code:      http.StatusConflict,
+				ErrorCode: CodeChunkChecksumMismatch,
+				Message:   localize(c, "Chunk checksum mismatch."),
+			}
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, makeFileUpload(file))
+}
+
+// getFinalizedFileUpload returns finalized upload of current account
+// that can be confirmed as file.
+func (v *View) getFinalizedFileUpload(
+	c echo.Context, accountCtx *managers.AccountContext, id int64,
+) (models.File, error) {
+	file, err := v.getFileUpload(c, accountCtx, id)
+	if err != nil {
+		return models.File{}, err
+	}
+	meta, err := file.GetMeta()
+	if err != nil {
+		return models.File{}, err
+	}
+	if !meta.Upload.Finalized {
+		return models.File{}, errorResponse{
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeUploadIncomplete,
+			Message:   localize(c, "Upload is incomplete."),
+		}
+	}
+	return file, nil
+}
+
+func (v *View) getFileUpload(
+	c echo.Context, accountCtx *managers.AccountContext, id int64,
+) (models.File, error) {
+	notFound := errorResponse{
+		Code:      http.StatusNotFound,
+		ErrorCode: CodeUploadNotFound,
+		Message:   localize(c, "Upload not found."),
+	}
+	if accountCtx.Account == nil {
+		return models.File{}, notFound
+	}
+	if err := syncStore(c, v.core.Files); err != nil {
+		return models.File{}, err
+	}
+	file, err := v.core.Files.Get(getContext(c), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.File{}, notFound
+		}
+		return models.File{}, err
+	}
+	if file.Status != models.PendingFile {
+		return models.File{}, notFound
+	}
+	meta, err := file.GetMeta()
+	if err != nil || meta.Upload == nil ||
+		meta.Upload.AccountID != accountCtx.Account.ID {
+		return models.File{}, notFound
+	}
+	return file, nil
+}
+
+func (v *View) extractFileUpload(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("upload"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:      http.StatusBadRequest,
+				ErrorCode: CodeInvalidUploadID,
+				Message:   localize(c, "Invalid upload ID."),
+			}
+		}
+		accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+		if !ok {
+			return fmt.Errorf("account not extracted")
+		}
+		file, err := v.getFileUpload(c, accountCtx, id)
+		if err != nil {
+			return err
+		}
+		c.Set(fileUploadKey, file)
+		return next(c)
+	}
+}
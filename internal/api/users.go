@@ -43,6 +43,10 @@ type User struct {
 	MiddleName string `json:"middle_name,omitempty"`
 	// UnconfirmedEmail contains email address that currently unconfirmed.
 	UnconfirmedEmail string `json:"unconfirmed_email,omitempty"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country string `json:"country,omitempty"`
+	// OrganizationID contains ID of organization.
+	OrganizationID int64 `json:"organization_id,omitempty"`
 }
 
 // Status represents current authorization status.
@@ -52,6 +56,8 @@ type Status struct {
 	Session     *Session   `json:"session,omitempty"`
 	Permissions []string   `json:"permissions"`
 	Locale      string     `json:"locale,omitempty"`
+	// ReadOnly is true if service is in read-only mode.
+	ReadOnly bool `json:"read_only,omitempty"`
 }
 
 // registerUserHandlers registers handlers for user management.
@@ -136,7 +142,12 @@ func makeUser(user models.User, permissions perms.Permissions) User {
 			*field = value
 		}
 	}
-	resp := User{ID: user.ID, Login: user.Login}
+	resp := User{
+		ID:             user.ID,
+		Login:          user.Login,
+		Country:        string(user.Country),
+		OrganizationID: int64(user.OrganizationID),
+	}
 	assign(&resp.Status, user.Status.String(), perms.ObserveUserStatusRole)
 	assign(&resp.Email, string(user.Email), perms.ObserveUserEmailRole)
 	assign(&resp.FirstName, string(user.FirstName), perms.ObserveUserFirstNameRole)
@@ -160,12 +171,16 @@ func (v *View) observeUser(c echo.Context) error {
 }
 
 type updateUserForm struct {
-	FirstName  *string `json:"first_name"`
-	LastName   *string `json:"last_name"`
-	MiddleName *string `json:"middle_name"`
+	FirstName      *string `json:"first_name"`
+	LastName       *string `json:"last_name"`
+	MiddleName     *string `json:"middle_name"`
+	Country        *string `json:"country"`
+	OrganizationID *int64  `json:"organization_id"`
 }
 
-func (f updateUserForm) Update(c echo.Context, user *models.User) error {
+func (f updateUserForm) Update(
+	c echo.Context, user *models.User, organizations *models.OrganizationStore,
+) error {
 	errors := errorFields{}
 	if f.FirstName != nil && len(*f.FirstName) > 0 {
 		validateFirstName(c, errors, *f.FirstName)
@@ -176,9 +191,20 @@ func (f updateUserForm) Update(c echo.Context, user *models.User) error {
 	if f.MiddleName != nil && len(*f.MiddleName) > 0 {
 		validateMiddleName(c, errors, *f.MiddleName)
 	}
+	if f.Country != nil {
+		validateCountry(c, errors, *f.Country)
+	}
+	if f.OrganizationID != nil {
+		if err := validateOrganization(
+			c, errors, organizations, *f.OrganizationID,
+		); err != nil {
+			return err
+		}
+	}
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -192,6 +218,12 @@ func (f updateUserForm) Update(c echo.Context, user *models.User) error {
 	if f.MiddleName != nil {
 		user.MiddleName = NString(*f.MiddleName)
 	}
+	if f.Country != nil {
+		user.Country = NString(*f.Country)
+	}
+	if f.OrganizationID != nil {
+		user.OrganizationID = NInt64(*f.OrganizationID)
+	}
 	return nil
 }
 
@@ -226,14 +258,25 @@ func (v *View) updateUser(c echo.Context) error {
 			missingPermissions = append(missingPermissions, perms.UpdateUserMiddleNameRole)
 		}
 	}
+	if form.Country != nil {
+		if !permissions.HasPermission(perms.UpdateUserCountryRole) {
+			missingPermissions = append(missingPermissions, perms.UpdateUserCountryRole)
+		}
+	}
+	if form.OrganizationID != nil {
+		if !permissions.HasPermission(perms.UpdateUserOrganizationRole) {
+			missingPermissions = append(missingPermissions, perms.UpdateUserOrganizationRole)
+		}
+	}
 	if len(missingPermissions) > 0 {
 		return errorResponse{
 			Code:               http.StatusForbidden,
+			ErrorCode:          CodeMissingPermissions,
 			Message:            localize(c, "Account missing permissions."),
 			MissingPermissions: missingPermissions,
 		}
 	}
-	if err := form.Update(c, &user); err != nil {
+	if err := form.Update(c, &user, v.core.Organizations); err != nil {
 		c.Logger().Warn(err)
 		return err
 	}
@@ -266,6 +309,7 @@ func (f updateUserStatusForm) Update(c echo.Context, user *models.User) error {
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -302,8 +346,9 @@ func (v *View) updateUserStatus(c echo.Context) error {
 		len(form.CurrentPassword) == 0 ||
 		!v.core.Users.CheckPassword(*authUser, form.CurrentPassword) {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid password."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidPassword,
+			Message:   localize(c, "Invalid password."),
 		}
 	}
 	if err := v.core.Users.Update(getContext(c), user); err != nil {
@@ -326,14 +371,16 @@ func (f updatePasswordForm) Update(
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
 	}
 	if err := users.SetPassword(user, f.Password); err != nil {
 		return errorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: localize(c, "Can not set password."),
+			Code:      http.StatusInternalServerError,
+			ErrorCode: CodePasswordNotSet,
+			Message:   localize(c, "Can not set password."),
 		}
 	}
 	return nil
@@ -365,19 +412,26 @@ func (v *View) updateUserPassword(c echo.Context) error {
 		len(form.CurrentPassword) == 0 ||
 		!v.core.Users.CheckPassword(*authUser, form.CurrentPassword) {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid password."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidPassword,
+			Message:   localize(c, "Invalid password."),
 		}
 	}
 	if authUser.ID == user.ID && form.CurrentPassword == form.Password {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Old and new passwords are the same."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeSamePassword,
+			Message:   localize(c, "Old and new passwords are the same."),
 		}
 	}
 	if err := form.Update(c, &user, v.core.Users); err != nil {
 		return err
 	}
+	if err := v.checkPasswordPolicy(
+		c, form.Password, user.Login, string(user.Email),
+	); err != nil {
+		return err
+	}
 	if err := v.core.Users.Update(getContext(c), user); err != nil {
 		c.Logger().Error(err)
 		return err
@@ -396,14 +450,16 @@ func (f updateEmailForm) Update(c echo.Context, user *models.User) error {
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
 	}
 	if f.Email == string(user.Email) {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Form has invalid fields."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidFields,
+			Message:   localize(c, "Form has invalid fields."),
 		}
 	}
 	return nil
@@ -439,8 +495,9 @@ func (v *View) updateUserEmail(c echo.Context) error {
 		len(form.CurrentPassword) == 0 ||
 		!v.core.Users.CheckPassword(*authUser, form.CurrentPassword) {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "Invalid password."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeInvalidPassword,
+			Message:   localize(c, "Invalid password."),
 		}
 	}
 	if err := form.Update(c, &user); err != nil {
@@ -463,8 +520,9 @@ func (v *View) updateUserEmail(c echo.Context) error {
 		return err
 	} else if count >= emailTokensLimit {
 		return errorResponse{
-			Code:    http.StatusTooManyRequests,
-			Message: localize(c, "Too many requests."),
+			Code:      http.StatusTooManyRequests,
+			ErrorCode: CodeTooManyRequests,
+			Message:   localize(c, "Too many requests."),
 		}
 	}
 	expires := now.Add(3 * time.Hour)
@@ -522,6 +580,7 @@ func (v *View) resendUserEmail(c echo.Context) error {
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -533,8 +592,9 @@ func (v *View) resendUserEmail(c echo.Context) error {
 		return err
 	} else if count >= emailTokensLimit {
 		return errorResponse{
-			Code:    http.StatusTooManyRequests,
-			Message: localize(c, "Too many requests."),
+			Code:      http.StatusTooManyRequests,
+			ErrorCode: CodeTooManyRequests,
+			Message:   localize(c, "Too many requests."),
 		}
 	}
 	expires := now.Add(3 * time.Hour)
@@ -642,6 +702,7 @@ func (v *View) status(c echo.Context) error {
 	if l := getLocale(c); l != nil {
 		status.Locale = l.Name()
 	}
+	status.ReadOnly = v.IsReadOnly(c.Logger())
 	sort.Strings(status.Permissions)
 	return c.JSON(http.StatusOK, status)
 }
@@ -656,8 +717,9 @@ func (v *View) loginAccount(c echo.Context) error {
 	}
 	if accountCtx.Account.Kind == models.ScopeAccountKind {
 		return errorResponse{
-			Code:    http.StatusBadRequest,
-			Message: localize(c, "User not found."),
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeUserNotFound,
+			Message:   localize(c, "User not found."),
 		}
 	}
 	expires := now.AddDate(0, 0, 90)
@@ -839,6 +901,7 @@ func (f RegisterUserForm) Update(
 	if len(errors) > 0 {
 		return errorResponse{
 			Code:          http.StatusBadRequest,
+			ErrorCode:     CodeInvalidFields,
 			Message:       localize(c, "Form has invalid fields."),
 			InvalidFields: errors,
 		}
@@ -846,12 +909,14 @@ func (f RegisterUserForm) Update(
 	if _, err := store.GetByLogin(getContext(c), f.Login); err != sql.ErrNoRows {
 		if err != nil {
 			return errorResponse{
-				Code:    http.StatusInternalServerError,
-				Message: localize(c, "Unknown error."),
+				Code:      http.StatusInternalServerError,
+				ErrorCode: CodeInternalError,
+				Message:   localize(c, "Unknown error."),
 			}
 		}
 		return errorResponse{
-			Code: http.StatusBadRequest,
+			Code:      http.StatusBadRequest,
+			ErrorCode: CodeUserAlreadyExists,
 			Message: localize(
 				c, "User with login \"{login}\" already exists.",
 				replaceField("login", f.Login),
@@ -861,8 +926,9 @@ func (f RegisterUserForm) Update(
 	user.Login = f.Login
 	if err := store.SetPassword(user, f.Password); err != nil {
 		return errorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: localize(c, "Can not set password."),
+			Code:      http.StatusInternalServerError,
+			ErrorCode: CodePasswordNotSet,
+			Message:   localize(c, "Can not set password."),
 		}
 	}
 	user.Email = NString(f.Email)
@@ -884,6 +950,11 @@ func (v *View) registerUser(c echo.Context) error {
 	if err := form.Update(c, &user, v.core.Users); err != nil {
 		return err
 	}
+	if err := v.checkPasswordPolicy(
+		c, form.Password, form.Login, form.Email,
+	); err != nil {
+		return err
+	}
 	user.Status = models.PendingUser
 	if v.core.Config.SMTP == nil {
 		user.Status = models.ActiveUser
@@ -967,8 +1038,9 @@ func (v *View) resetUserPassword(c echo.Context) error {
 		return err
 	} else if count >= passwordTokensLimit {
 		return errorResponse{
-			Code:    http.StatusTooManyRequests,
-			Message: localize(c, "Too many requests."),
+			Code:      http.StatusTooManyRequests,
+			ErrorCode: CodeTooManyRequests,
+			Message:   localize(c, "Too many requests."),
 		}
 	}
 	expires := now.Add(30 * time.Minute)
@@ -1114,7 +1186,8 @@ func (v *View) extractUser(next echo.HandlerFunc) echo.HandlerFunc {
 			if err != nil {
 				if err == sql.ErrNoRows {
 					return errorResponse{
-						Code: http.StatusNotFound,
+						Code:      http.StatusNotFound,
+						ErrorCode: CodeUserNotFound,
 						Message: localize(
 							c, "User \"{login}\" does not exists.",
 							replaceField("login", login),
@@ -1131,7 +1204,8 @@ func (v *View) extractUser(next echo.HandlerFunc) echo.HandlerFunc {
 		if err != nil {
 			if err == sql.ErrNoRows {
 				return errorResponse{
-					Code: http.StatusNotFound,
+					Code:      http.StatusNotFound,
+					ErrorCode: CodeUserNotFound,
 					Message: localize(
 						c, "User {id} does not exists.",
 						replaceField("id", id),
@@ -1162,6 +1236,8 @@ func (v *View) getUserPermissions(
 			perms.UpdateUserFirstNameRole,
 			perms.UpdateUserLastNameRole,
 			perms.UpdateUserMiddleNameRole,
+			perms.UpdateUserCountryRole,
+			perms.UpdateUserOrganizationRole,
 		)
 	}
 	permissions.AddPermission(
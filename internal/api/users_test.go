@@ -2,7 +2,14 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
 )
 
 func TestUserSimpleScenario(t *testing.T) {
@@ -31,4 +38,93 @@ func TestUserSimpleScenario(t *testing.T) {
 	} else {
 		e.Check(user)
 	}
+	if activity, err := e.Client.ObserveUserSolutionsActivity(user.Login); err != nil {
+		t.Fatal("Error:", err)
+	} else {
+		e.Check(activity)
+	}
+}
+
+func TestUserLockout(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	setting := models.Setting{Key: "accounts.lockout.attempts", Value: "3"}
+	if err := e.Core.Settings.Create(context.Background(), &setting); err != nil {
+		t.Fatal("Error:", err)
+	}
+	admin := NewTestUser(e)
+	admin.AddRoles(perms.UpdateUserRole, perms.UpdateUserStatusRole)
+	user := NewTestUser(e)
+	e.SyncStores()
+	for i := 0; i < 3; i++ {
+		if _, err := e.Client.Login(context.Background(), user.Login, "invalid"); err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, http.StatusUnauthorized, resp.StatusCode())
+		}
+	}
+	if _, err := e.Client.Login(context.Background(), user.Login, user.Password); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	admin.LoginClient()
+	if lock, err := e.Client.ObserveUserLock(context.Background(), user.Login); err != nil {
+		t.Fatal("Error:", err)
+	} else if !lock.Locked {
+		t.Fatal("Expected locked user")
+	}
+	if _, err := e.Client.UnlockUser(context.Background(), user.Login); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if lock, err := e.Client.ObserveUserLock(context.Background(), user.Login); err != nil {
+		t.Fatal("Error:", err)
+	} else if lock.Locked {
+		t.Fatal("Expected unlocked user")
+	}
+	admin.LogoutClient()
+	user.LoginClient()
+	user.LogoutClient()
+}
+
+func TestRegisterPasswordPolicy(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	breaches := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Suffix of SHA-1 hash of "correct horse battery staple".
+		fmt.Fprint(w, "AD6438836DBE526AA231ABDE2D0EEF74D42:3\r\n")
+	}))
+	defer breaches.Close()
+	e.Core.Config.Security.PasswordPolicy = &config.PasswordPolicy{
+		MinScore:      3,
+		CheckBreaches: true,
+		BreachesURL:   breaches.URL,
+	}
+	form := RegisterUserForm{
+		Login:     "test-user",
+		Email:     "test-user@example.com",
+		Password:  "test-user1",
+		FirstName: "First",
+	}
+	checkInvalidPassword := func() {
+		t.Helper()
+		if _, err := e.Client.Register(context.Background(), form); err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+		}
+	}
+	checkInvalidPassword()
+	form.Password = "correct horse battery staple"
+	checkInvalidPassword()
+	form.Password = "Tr0ub4dour&3x"
+	if _, err := e.Client.Register(context.Background(), form); err != nil {
+		t.Fatal("Error:", err)
+	}
 }
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func (v *View) registerVerdictHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/verdicts", v.observeVerdicts,
+		v.extractAuth(v.sessionAuth, v.guestAuth),
+	)
+}
+
+// Verdict represents description of verdict that can be returned in
+// solution reports.
+type Verdict struct {
+	// Name contains verdict as it is returned in reports.
+	Name string `json:"name"`
+	// Code contains stable machine-readable code of verdict.
+	Code string `json:"code"`
+	// Message contains localized human-readable message.
+	Message string `json:"message"`
+	// Custom is true for verdicts defined in settings.
+	Custom bool `json:"custom,omitempty"`
+}
+
+type Verdicts struct {
+	Verdicts []Verdict `json:"verdicts"`
+}
+
+// verdictInfo contains code and message of verdict.
+//
+// Message is not localized and is used as localization key.
+type verdictInfo struct {
+	Name    string
+	Code    string
+	Message string
+	// Verdict contains verdict value or zero for judgement statuses.
+	Verdict models.Verdict
+}
+
+// builtInVerdicts contains mapping of verdicts and judgement statuses.
+//
+// Codes should never be changed, because they are used by clients.
+var builtInVerdicts = []verdictInfo{
+	{models.Accepted.String(), "AC", "Accepted", models.Accepted},
+	{models.Rejected.String(), "RJ", "Rejected", models.Rejected},
+	{models.CompilationError.String(), "CE", "Compilation error", models.CompilationError},
+	{models.TimeLimitExceeded.String(), "TL", "Time limit exceeded", models.TimeLimitExceeded},
+	{models.MemoryLimitExceeded.String(), "ML", "Memory limit exceeded", models.MemoryLimitExceeded},
+	{models.RuntimeError.String(), "RE", "Runtime error", models.RuntimeError},
+	{models.WrongAnswer.String(), "WA", "Wrong answer", models.WrongAnswer},
+	{models.PresentationError.String(), "PE", "Presentation error", models.PresentationError},
+	{models.PartiallyAccepted.String(), "PA", "Partially accepted", models.PartiallyAccepted},
+	{models.Failed.String(), "FL", "Judgement failed", models.Failed},
+	{models.QueuedTask.String(), "QU", "In queue", 0},
+	{models.RunningTask.String(), "RU", "Running", 0},
+}
+
+// customVerdictsSetting contains key of setting with custom verdicts.
+//
+// Value of setting contains JSON array of customVerdict, for example:
+//
+//	[{"id": 1, "name": "disqualified", "code": "DQ", "message": "Disqualified"}]
+//
+// ID of custom verdict is stored in reports, so it should never be
+// reused for other verdicts.
+const customVerdictsSetting = "verdicts.custom"
+
+type customVerdict struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// getCustomVerdicts returns verdicts defined in settings.
+//
+// Invalid or conflicting verdicts are skipped.
+func (v *View) getCustomVerdicts(c echo.Context) []verdictInfo {
+	setting, err := v.core.Settings.GetByKey(customVerdictsSetting)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			c.Logger().Warn("Cannot get setting", logs.Any("key", customVerdictsSetting), err)
+		}
+		return nil
+	}
+	var verdicts []customVerdict
+	if err := json.Unmarshal([]byte(setting.Value), &verdicts); err != nil {
+		c.Logger().Warn("Invalid custom verdicts", err)
+		return nil
+	}
+	names := map[string]struct{}{}
+	codes := map[string]struct{}{}
+	ids := map[int]struct{}{}
+	for _, info := range builtInVerdicts {
+		names[info.Name] = struct{}{}
+		codes[info.Code] = struct{}{}
+	}
+	var infos []verdictInfo
+	for _, verdict := range verdicts {
+		_, hasName := names[verdict.Name]
+		_, hasCode := codes[verdict.Code]
+		_, hasID := ids[verdict.ID]
+		if verdict.ID <= 0 || verdict.Name == "" || verdict.Code == "" ||
+			hasName || hasCode || hasID {
+			c.Logger().Warn("Invalid custom verdict", logs.Any("name", verdict.Name))
+			continue
+		}
+		names[verdict.Name] = struct{}{}
+		codes[verdict.Code] = struct{}{}
+		ids[verdict.ID] = struct{}{}
+		message := verdict.Message
+		if message == "" {
+			message = verdict.Name
+		}
+		infos = append(infos, verdictInfo{
+			Name:    verdict.Name,
+			Code:    verdict.Code,
+			Message: message,
+			Verdict: models.CustomVerdict(verdict.ID),
+		})
+	}
+	return infos
+}
+
+func (v *View) getVerdictInfos(c echo.Context) []verdictInfo {
+	infos := builtInVerdicts
+	if custom := v.getCustomVerdicts(c); len(custom) > 0 {
+		infos = append(infos[:len(infos):len(infos)], custom...)
+	}
+	return infos
+}
+
+// findVerdictInfo returns code and message of verdict with specified name.
+//
+// Custom verdicts can also be found by their text representation.
+func (v *View) findVerdictInfo(c echo.Context, name string) (verdictInfo, bool) {
+	for _, info := range v.getVerdictInfos(c) {
+		if info.Name == name ||
+			(info.Verdict.IsCustom() && info.Verdict.String() == name) {
+			return info, true
+		}
+	}
+	return verdictInfo{}, false
+}
+
+// parseVerdict returns verdict with specified name.
+func (v *View) parseVerdict(c echo.Context, name string) (models.Verdict, bool) {
+	info, ok := v.findVerdictInfo(c, name)
+	if !ok || info.Verdict == 0 {
+		return 0, false
+	}
+	return info.Verdict, true
+}
+
+func makeVerdict(c echo.Context, info verdictInfo) Verdict {
+	return Verdict{
+		Name:    info.Name,
+		Code:    info.Code,
+		Message: localize(c, info.Message),
+		Custom:  info.Verdict.IsCustom(),
+	}
+}
+
+// localizeVerdict returns verdict with code and localized message.
+//
+// Only name is returned for unknown verdicts.
+func (v *View) localizeVerdict(c echo.Context, name string) Verdict {
+	info, ok := v.findVerdictInfo(c, name)
+	if !ok {
+		return Verdict{Name: name}
+	}
+	return makeVerdict(c, info)
+}
+
+// setReportVerdict fills verdict of report using verdict mapping.
+func (v *View) setReportVerdict(c echo.Context, report *SolutionReport) {
+	verdict := v.localizeVerdict(c, report.Verdict)
+	report.Verdict = verdict.Name
+	report.VerdictCode = verdict.Code
+	report.VerdictMessage = verdict.Message
+}
+
+func (v *View) observeVerdicts(c echo.Context) error {
+	var resp Verdicts
+	for _, info := range v.getVerdictInfos(c) {
+		resp.Verdicts = append(resp.Verdicts, makeVerdict(c, info))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// registerV1Handlers registers handlers of API v1.
+//
+// API v1 reuses handlers of v0 using compatibility shim: path of
+// request is rewritten to v0 and JSON responses are wrapped into
+// envelope. Group should not have middlewares, because middlewares
+// of v0 group are applied after rewriting.
+func (v *View) registerV1Handlers(g *echo.Group) {
+	g.Any("/v1/*", v.serveV1)
+}
+
+// responseEnvelope represents response of API v1.
+type responseEnvelope struct {
+	// Data contains response of v0 handler.
+	Data json.RawMessage `json:"data,omitempty"`
+	// Pagination contains metadata of paginated lists.
+	Pagination *responsePagination `json:"pagination,omitempty"`
+	// Error contains error with stable code.
+	Error json.RawMessage `json:"error,omitempty"`
+}
+
+// responsePagination represents pagination metadata of list.
+type responsePagination struct {
+	// NextBeginID contains begin_id of next page.
+	NextBeginID int64 `json:"next_begin_id"`
+}
+
+func (v *View) serveV1(c echo.Context) error {
+	req := c.Request()
+	prefix := strings.TrimSuffix(c.Path(), "/v1/*")
+	path := echo.GetPath(req)
+	if !strings.HasPrefix(path, prefix+"/v1/") {
+		return echo.ErrNotFound
+	}
+	c.Set(apiVersionKey, 1)
+	c.Echo().Router().Find(
+		req.Method, prefix+"/v0/"+strings.TrimPrefix(path, prefix+"/v1/"), c,
+	)
+	writer := envelopeWriter{ResponseWriter: c.Response().Writer}
+	c.Response().Writer = &writer
+	defer func() { c.Response().Writer = writer.ResponseWriter }()
+	if err := c.Handler()(c); err != nil {
+		c.Error(err)
+	}
+	return writer.Close()
+}
+
+// getAPIVersion returns version of API that is requested by client.
+func getAPIVersion(c echo.Context) int {
+	if version, ok := c.Get(apiVersionKey).(int); ok {
+		return version
+	}
+	return 0
+}
+
+// markDeprecated adds Deprecation and Sunset headers to responses of
+// API v0.
+//
+// Dates of deprecation and sunset are specified in settings
+// "handlers.v0.deprecation_time" and "handlers.v0.sunset_time" as
+// unix timestamps.
+func (v *View) markDeprecated(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if getAPIVersion(c) != 0 || !strings.Contains(c.Path(), "/v0/") {
+			return next(c)
+		}
+		header := c.Response().Header()
+		deprecation := "true"
+		if value := v.getInt64Setting(
+			"handlers.v0.deprecation_time", c.Logger(),
+		).OrElse(0); value > 0 {
+			deprecation = "@" + strconv.FormatInt(value, 10)
+		}
+		header.Set("Deprecation", deprecation)
+		if value := v.getInt64Setting(
+			"handlers.v0.sunset_time", c.Logger(),
+		).OrElse(0); value > 0 {
+			header.Set("Sunset", time.Unix(value, 0).UTC().Format(http.TimeFormat))
+		}
+		successor := strings.Replace(c.Request().URL.Path, "/v0/", "/v1/", 1)
+		header.Add("Link", "<"+successor+`>; rel="successor-version"`)
+		return next(c)
+	}
+}
+
+// envelopeWriter buffers JSON responses and writes them wrapped into
+// envelope of API v1.
+//
+// Other responses, like files and event streams, are written as is.
+type envelopeWriter struct {
+	http.ResponseWriter
+	buffer      bytes.Buffer
+	status      int
+	buffered    bool
+	wroteHeader bool
+}
+
+func (w *envelopeWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	contentType := w.Header().Get(echo.HeaderContentType)
+	if strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		w.buffered = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffered {
+		return w.buffer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *envelopeWriter) Flush() {
+	if !w.buffered {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+func (w *envelopeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close writes buffered response.
+func (w *envelopeWriter) Close() error {
+	if !w.buffered {
+		return nil
+	}
+	data := w.buffer.Bytes()
+	if envelope, err := makeResponseEnvelope(w.status, data); err == nil {
+		data = envelope
+	}
+	w.Header().Del(echo.HeaderContentLength)
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// makeResponseEnvelope wraps JSON response of v0 handler into envelope.
+//
+// Field next_begin_id of paginated lists is moved into pagination.
+func makeResponseEnvelope(status int, data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JSON response")
+	}
+	var envelope responseEnvelope
+	if status >= http.StatusBadRequest {
+		envelope.Error = data
+		return json.Marshal(envelope)
+	}
+	envelope.Data = data
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err == nil {
+		if value, ok := object["next_begin_id"]; ok {
+			var pagination responsePagination
+			if err := json.Unmarshal(value, &pagination.NextBeginID); err != nil {
+				return nil, err
+			}
+			delete(object, "next_begin_id")
+			if envelope.Data, err = json.Marshal(object); err != nil {
+				return nil, err
+			}
+			envelope.Pagination = &pagination
+		}
+	}
+	return json.Marshal(envelope)
+}
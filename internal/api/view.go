@@ -3,7 +3,10 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
@@ -11,6 +14,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 
@@ -28,39 +32,106 @@ import (
 
 // View represents API view.
 type View struct {
-	core      *core.Core
-	accounts  *managers.AccountManager
-	contests  *managers.ContestManager
-	files     *managers.FileManager
-	solutions *managers.SolutionManager
-	standings *managers.ContestStandingsManager
-	visits    chan visitContext
+	core       *core.Core
+	accounts   *managers.AccountManager
+	contests   *managers.ContestManager
+	files      *managers.FileManager
+	solutions  *managers.SolutionManager
+	standings  *managers.ContestStandingsManager
+	similarity *managers.ContestSimilarityManager
+	statistics *managers.ContestStatisticsManager
+	backups    *managers.BackupManager
+	exports    *managers.ContestExportManager
+	// recommendations contains manager of problem recommendations.
+	recommendations *managers.ProblemRecommendationManager
+	visits          chan visitContext
+	// loginAddresses contains failed login attempts per IP address
+	// that are tracked locally by each server instance.
+	loginAddresses *loginAddressTracker
+	// contestAddresses contains recently saved addresses of contest
+	// participants.
+	contestAddresses *contestAddressTracker
+	// submissions contains queue of asynchronous submissions.
+	submissions *contestSubmissionQueue
+	// readOnly contains read-only mode switch of server instance.
+	readOnly atomic.Bool
+	// limits contains limits of requests.
+	limits requestLimits
+	// uploads contains amount of concurrent uploads.
+	uploads *uploadLimiter
 }
 
 // Register registers handlers in specified group.
 func (v *View) Register(g *echo.Group) {
-	g.Use(wrapResponse, v.wrapSyncStores, v.logVisit, v.extractLocale)
+	// Handlers of v1 should be registered before middlewares, because
+	// they reuse handlers of v0 with middlewares.
+	v.registerV1Handlers(g.Group(""))
+	g.Use(
+		wrapResponse, v.wrapSyncStores, v.logVisit, v.extractLocale,
+		v.checkReadOnly, v.limitRequestBody, v.markDeprecated,
+	)
 	g.GET("/ping", v.ping)
 	g.GET("/health", v.health)
+	g.GET("/v0/time", v.observeTime)
 	v.registerAccountHandlers(g)
+	v.registerAccountMergeHandlers(g)
 	v.registerUserHandlers(g)
+	v.registerLoginLockoutHandlers(g)
 	v.registerScopeHandlers(g)
+	v.registerScopeUserTokenHandlers(g)
 	v.registerGroupHandlers(g)
+	v.registerOrganizationHandlers(g)
 	v.registerRoleHandlers(g)
+	v.registerPermissionHandlers(g)
 	v.registerSessionHandlers(g)
 	v.registerTokenHandlers(g)
+	v.registerCLIHandlers(g)
 	v.registerContestHandlers(g)
 	v.registerContestStandingsHandlers(g)
+	v.registerContestWidgetHandlers(g)
+	v.registerContestStatisticsHandlers(g)
+	v.registerContestFeedbackHandlers(g)
+	v.registerContestResultHandlers(g)
+	v.registerContestGrantHandlers(g)
+	v.registerContestMashupHandlers(g)
+	v.registerContestAppealHandlers(g)
+	v.registerContestScoreOverrideHandlers(g)
+	v.registerContestEditorialHandlers(g)
+	v.registerContestDraftHandlers(g)
+	v.registerContestProblemLimitHandlers(g)
+	v.registerContestSystemTestHandlers(g)
+	v.registerContestQueueHandlers(g)
+	v.registerContestDashboardHandlers(g)
+	v.registerContestLanguageHandlers(g)
+	v.registerContestAddressHandlers(g)
+	v.registerContestSubmissionHandlers(g)
+	v.registerContestExportHandlers(g)
 	v.registerContestMessageHandlers(g)
+	v.registerContestUpdateHandlers(g)
+	v.registerContestTokenHandlers(g)
 	v.registerContestFakeHandlers(g)
 	v.registerProblemHandlers(g)
+	v.registerProblemGrantHandlers(g)
+	v.registerProblemRecommendationHandlers(g)
+	v.registerProblemStressHandlers(g)
+	v.registerProblemTestHandlers(g)
+	v.registerProblemCheckerHandlers(g)
+	v.registerProblemUsageHandlers(g)
 	v.registerSolutionHandlers(g)
+	v.registerSolutionRetestHandlers(g)
+	v.registerVerdictHandlers(g)
 	v.registerCompilerHandlers(g)
+	v.registerCompilerImageHandlers(g)
+	v.registerInvokerSelfTestHandlers(g)
 	v.registerSettingHandlers(g)
+	v.registerFailedTaskHandlers(g)
+	v.registerBackupHandlers(g)
 	v.registerLocaleHandlers(g)
 	v.registerFileHandlers(g)
+	v.registerUploadHandlers(g)
 	v.registerPostHandlers(g)
 	v.registerTokenHandlers(g)
+	v.registerCLIHandlers(g)
 }
 
 func (v *View) RegisterSocket(g *echo.Group) {
@@ -85,51 +156,86 @@ func (v *View) health(c echo.Context) error {
 	return c.String(http.StatusOK, "healthy")
 }
 
+// ServerTime represents current time of server.
+type ServerTime struct {
+	// Time contains unix time in seconds.
+	Time int64 `json:"time"`
+	// TimeMilli contains unix time in milliseconds.
+	TimeMilli int64 `json:"time_ms"`
+}
+
+// observeTime returns current time of server for synchronization of
+// client clocks.
+func (v *View) observeTime(c echo.Context) error {
+	now := getNow(c)
+	return c.JSON(http.StatusOK, ServerTime{
+		Time:      now.Unix(),
+		TimeMilli: now.UnixMilli(),
+	})
+}
+
 // NewView returns a new instance of view.
 func NewView(core *core.Core) *View {
 	v := View{
-		core:      core,
-		accounts:  managers.NewAccountManager(core),
-		contests:  managers.NewContestManager(core),
-		standings: managers.NewContestStandingsManager(core),
+		core:             core,
+		accounts:         managers.NewAccountManager(core),
+		contests:         managers.NewContestManager(core),
+		standings:        managers.NewContestStandingsManager(core),
+		statistics:       managers.NewContestStatisticsManager(core),
+		backups:          managers.NewBackupManager(core),
+		recommendations:  managers.NewProblemRecommendationManager(core),
+		loginAddresses:   newLoginAddressTracker(),
+		contestAddresses: newContestAddressTracker(),
+		limits:           newRequestLimits(core.Config.Server),
 	}
+	v.uploads = newUploadLimiter(v.limits.Uploads, v.limits.AccountUploads)
 	if core.Config.Storage != nil {
 		v.files = managers.NewFileManager(core)
 	}
 	if v.files != nil {
 		v.solutions = managers.NewSolutionManager(core, v.files)
 	}
+	v.similarity = managers.NewContestSimilarityManager(core, v.files)
+	v.exports = managers.NewContestExportManager(core, v.files)
 	return &v
 }
 
 const (
-	nowKey                = "now"
-	authVisitKey          = "auth_visit"
-	authSessionKey        = "auth_session"
-	accountCtxKey         = "account_ctx"
-	permissionCtxKey      = "permission_ctx"
-	roleKey               = "role"
-	childRoleKey          = "child_role"
-	userKey               = "user"
-	sessionKey            = "session"
-	sessionCookie         = "session"
-	contestCtxKey         = "contest_ctx"
-	contestProblemKey     = "contest_problem"
-	contestParticipantKey = "contest_participant"
-	contestSolutionKey    = "contest_solution"
-	problemKey            = "problem"
-	solutionKey           = "solution"
-	compilerKey           = "compiler"
-	fileKey               = "file"
-	settingKey            = "setting"
-	scopeKey              = "scope"
-	scopeUserKey          = "scope_user"
-	groupKey              = "group"
-	groupMemberKey        = "group_member"
-	postKey               = "post"
-	tokenKey              = "token"
-	localeKey             = "locale"
-	syncKey               = "sync"
+	nowKey                  = "now"
+	requestBodyKey          = "request_body"
+	apiVersionKey           = "api_version"
+	authVisitKey            = "auth_visit"
+	authSessionKey          = "auth_session"
+	accountCtxKey           = "account_ctx"
+	permissionCtxKey        = "permission_ctx"
+	roleKey                 = "role"
+	childRoleKey            = "child_role"
+	userKey                 = "user"
+	sessionKey              = "session"
+	sessionCookie           = "session"
+	contestCtxKey           = "contest_ctx"
+	contestProblemKey       = "contest_problem"
+	contestParticipantKey   = "contest_participant"
+	contestSolutionKey      = "contest_solution"
+	contestAppealKey        = "contest_appeal"
+	contestScoreOverrideKey = "contest_score_override"
+	contestEditorialKey     = "contest_editorial"
+	problemKey              = "problem"
+	problemExtraTestKey     = "problem_extra_test"
+	problemCheckerKey       = "problem_checker"
+	solutionKey             = "solution"
+	compilerKey             = "compiler"
+	fileKey                 = "file"
+	fileUploadKey           = "file_upload"
+	settingKey              = "setting"
+	scopeKey                = "scope"
+	scopeUserKey            = "scope_user"
+	groupKey                = "group"
+	groupMemberKey          = "group_member"
+	postKey                 = "post"
+	tokenKey                = "token"
+	localeKey               = "locale"
+	syncKey                 = "sync"
 )
 
 type (
@@ -219,12 +325,31 @@ type errorFields map[string]errorField
 type errorResponse struct {
 	// Code.
 	Code int `json:"-"`
+	// ErrorCode contains stable machine-readable code of error.
+	//
+	// If code is not specified, it is derived from status code.
+	ErrorCode ErrorCode `json:"code"`
 	// Message.
 	Message string `json:"message"`
 	// MissingPermissions.
 	MissingPermissions []string `json:"missing_permissions,omitempty"`
 	// InvalidFields.
 	InvalidFields errorFields `json:"invalid_fields,omitempty"`
+	// PermissionExplanations.
+	PermissionExplanations map[string]permissionExplanation `json:"permission_explanations,omitempty"`
+}
+
+// permissionExplanation describes why account missing permission.
+type permissionExplanation struct {
+	// Reason contains source of denial: role_graph, contest_stage,
+	// participant_kind or finalized.
+	Reason string `json:"reason"`
+	// Roles contains roles that grant permission.
+	Roles []string `json:"roles,omitempty"`
+	// Stages contains contest stages when participant has permission.
+	Stages []string `json:"stages,omitempty"`
+	// ParticipantKinds contains kinds of participants that have permission.
+	ParticipantKinds []string `json:"participant_kinds,omitempty"`
 }
 
 // StatusCode returns response status code.
@@ -265,6 +390,24 @@ type statusCodeResponse interface {
 	StatusCode() int
 }
 
+// withErrorCode fills code of error response if it is not specified.
+func withErrorCode(resp statusCodeResponse, status int) statusCodeResponse {
+	switch r := resp.(type) {
+	case errorResponse:
+		if r.ErrorCode == "" {
+			r.ErrorCode = getStatusErrorCode(status)
+		}
+		return r
+	case *errorResponse:
+		if r.ErrorCode == "" {
+			copy := *r
+			copy.ErrorCode = getStatusErrorCode(status)
+			return copy
+		}
+	}
+	return resp
+}
+
 var (
 	rnd      = rand.NewSource(time.Now().UnixNano())
 	rndMutex = sync.Mutex{}
@@ -288,6 +431,9 @@ func wrapResponse(next echo.HandlerFunc) echo.HandlerFunc {
 		c.Response().Header().Add("X-Solve-Version", config.Version)
 		start := time.Now()
 		err := next(c)
+		if isBodyTooLarge(err) {
+			err = errBodyTooLarge(c)
+		}
 		status := c.Response().Status
 		if err != nil {
 			status = 500
@@ -323,7 +469,7 @@ func wrapResponse(next echo.HandlerFunc) echo.HandlerFunc {
 			if status == 0 {
 				status = http.StatusInternalServerError
 			}
-			return c.JSON(status, resp)
+			return c.JSON(status, withErrorCode(resp, status))
 		}
 		return err
 	}
@@ -356,28 +502,43 @@ func (v *View) extractAuth(authMethods ...authMethod) echo.MiddlewareFunc {
 				}
 			}
 			return errorResponse{
-				Code:    http.StatusUnauthorized,
-				Message: localize(c, "Unable to authorize."),
+				Code:      http.StatusUnauthorized,
+				ErrorCode: CodeUnauthorized,
+				Message:   localize(c, "Unable to authorize."),
 			}
 		}
 	}
 }
 
-func (v *View) sessionAuth(c echo.Context) (bool, error) {
+// getSessionToken returns session token from cookie or from
+// Authorization header with Bearer scheme.
+func getSessionToken(c echo.Context) (string, error) {
 	cookie, err := c.Cookie(sessionCookie)
+	if err == nil && len(cookie.Value) > 0 {
+		return cookie.Value, nil
+	}
+	if err != nil && err != http.ErrNoCookie {
+		return "", err
+	}
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return strings.TrimSpace(token), nil
+	}
+	return "", nil
+}
+
+func (v *View) sessionAuth(c echo.Context) (bool, error) {
+	token, err := getSessionToken(c)
 	if err != nil {
-		if err == http.ErrNoCookie {
-			return false, nil
-		}
 		return false, err
 	}
-	if len(cookie.Value) == 0 {
+	if len(token) == 0 {
 		return false, nil
 	}
 	if err := syncStore(c, v.core.Sessions); err != nil {
 		return false, err
 	}
-	session, err := v.core.Sessions.GetByCookie(cookie.Value)
+	session, err := v.core.Sessions.GetByCookie(token)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
@@ -410,6 +571,34 @@ type userAuthForm struct {
 	Password string `json:"password"`
 }
 
+// rehashUserPassword hashes password of user again with current
+// parameters after successful login.
+//
+// Errors are only logged because they should not prevent login.
+func (v *View) rehashUserPassword(c echo.Context, user models.User, password string) {
+	if err := v.core.Users.SetPassword(&user, password); err != nil {
+		c.Logger().Warn("Cannot rehash password", err)
+		return
+	}
+	if err := v.core.Users.Update(getContext(c), user); err != nil {
+		c.Logger().Warn("Cannot rehash password", err)
+	}
+}
+
+// rehashScopeUserPassword hashes password of scope user again with
+// current parameters after successful login.
+func (v *View) rehashScopeUserPassword(
+	c echo.Context, user models.ScopeUser, password string,
+) {
+	if err := v.core.ScopeUsers.SetPassword(&user, password); err != nil {
+		c.Logger().Warn("Cannot rehash password", err)
+		return
+	}
+	if err := v.core.ScopeUsers.Update(getContext(c), user); err != nil {
+		c.Logger().Warn("Cannot rehash password", err)
+	}
+}
+
 func (v *View) userAuth(c echo.Context) (bool, error) {
 	var form userAuthForm
 	if err := reusableBind(c, &form); err != nil {
@@ -426,20 +615,34 @@ func (v *View) userAuth(c echo.Context) (bool, error) {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			resp := errorResponse{
-				Code:    http.StatusUnauthorized,
-				Message: localize(c, "User not found."),
+				Code:      http.StatusUnauthorized,
+				ErrorCode: CodeUserNotFound,
+				Message:   localize(c, "User not found."),
 			}
 			return false, resp
 		}
 		return false, err
 	}
+	if err := v.checkLoginLock(c, user); err != nil {
+		return false, err
+	}
 	if !v.core.Users.CheckPassword(user, form.Password) {
+		if err := v.registerFailedLogin(c, user); err != nil {
+			return false, err
+		}
 		resp := errorResponse{
-			Code:    http.StatusUnauthorized,
-			Message: localize(c, "Invalid password."),
+			Code:      http.StatusUnauthorized,
+			ErrorCode: CodeInvalidPassword,
+			Message:   localize(c, "Invalid password."),
 		}
 		return false, resp
 	}
+	if err := v.registerSucceededLogin(c, user); err != nil {
+		return false, err
+	}
+	if v.core.Users.NeedsRehash(user) {
+		v.rehashUserPassword(c, user, form.Password)
+	}
 	if err := syncStore(c, v.core.Accounts); err != nil {
 		return false, err
 	}
@@ -484,8 +687,9 @@ func (v *View) scopeUserAuth(c echo.Context) (bool, error) {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			resp := errorResponse{
-				Code:    http.StatusUnauthorized,
-				Message: localize(c, "User not found."),
+				Code:      http.StatusUnauthorized,
+				ErrorCode: CodeUserNotFound,
+				Message:   localize(c, "User not found."),
 			}
 			return false, resp
 		}
@@ -493,11 +697,15 @@ func (v *View) scopeUserAuth(c echo.Context) (bool, error) {
 	}
 	if !v.core.ScopeUsers.CheckPassword(user, form.Password) {
 		resp := errorResponse{
-			Code:    http.StatusUnauthorized,
-			Message: localize(c, "Invalid password."),
+			Code:      http.StatusUnauthorized,
+			ErrorCode: CodeInvalidPassword,
+			Message:   localize(c, "Invalid password."),
 		}
 		return false, resp
 	}
+	if v.core.ScopeUsers.NeedsRehash(user) {
+		v.rehashScopeUserPassword(c, user, form.Password)
+	}
 	if err := syncStore(c, v.core.Accounts); err != nil {
 		return false, err
 	}
@@ -536,8 +744,9 @@ func (v *View) requirePermission(names ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			resp := errorResponse{
-				Code:    http.StatusForbidden,
-				Message: localize(c, "Account missing permissions."),
+				Code:      http.StatusForbidden,
+				ErrorCode: CodeMissingPermissions,
+				Message:   localize(c, "Account missing permissions."),
 			}
 			ctx, ok := c.Get(permissionCtxKey).(perms.Permissions)
 			if !ok {
@@ -550,6 +759,9 @@ func (v *View) requirePermission(names ...string) echo.MiddlewareFunc {
 				}
 			}
 			if len(resp.MissingPermissions) > 0 {
+				resp.PermissionExplanations = v.explainPermissions(
+					c, ctx, resp.MissingPermissions,
+				)
 				return resp
 			}
 			return next(c)
@@ -557,6 +769,83 @@ func (v *View) requirePermission(names ...string) echo.MiddlewareFunc {
 	}
 }
 
+// explainPermissions returns explanations for missing permissions.
+func (v *View) explainPermissions(
+	c echo.Context, ctx perms.Permissions, names []string,
+) map[string]permissionExplanation {
+	explainer, _ := ctx.(managers.PermissionExplainer)
+	explanations := map[string]permissionExplanation{}
+	for _, name := range names {
+		explanation := permissionExplanation{
+			Reason: managers.RoleGraphDenial.String(),
+		}
+		if explainer != nil {
+			contestExplanation := explainer.ExplainPermission(name)
+			explanation.Reason = contestExplanation.Reason.String()
+			for _, stage := range contestExplanation.Stages {
+				explanation.Stages = append(explanation.Stages, makeContestStage(stage))
+			}
+			for _, kind := range contestExplanation.Kinds {
+				explanation.ParticipantKinds = append(explanation.ParticipantKinds, kind.String())
+			}
+		}
+		roles, err := v.getGrantingRoles(c, name)
+		if err != nil {
+			c.Logger().Warn("Cannot get granting roles", err)
+		}
+		explanation.Roles = roles
+		explanations[name] = explanation
+	}
+	return explanations
+}
+
+// getGrantingRoles returns names of non built-in roles that
+// recursively contain specified built-in role.
+func (v *View) getGrantingRoles(c echo.Context, name string) ([]string, error) {
+	ctx := getContext(c)
+	role, err := v.core.Roles.GetByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	edges, err := collectRows(v.core.RoleEdges.All(ctx, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	parents := map[int64][]int64{}
+	for _, edge := range edges {
+		parents[edge.ChildID] = append(parents[edge.ChildID], edge.RoleID)
+	}
+	visited := map[int64]struct{}{role.ID: {}}
+	queue := []int64{role.ID}
+	var result []string
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, parentID := range parents[id] {
+			if _, ok := visited[parentID]; ok {
+				continue
+			}
+			visited[parentID] = struct{}{}
+			queue = append(queue, parentID)
+			parent, err := v.core.Roles.Get(ctx, parentID)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					continue
+				}
+				return nil, err
+			}
+			if !perms.IsBuiltInRole(parent.Name) {
+				result = append(result, parent.Name)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
 func (v *View) getBoolSetting(key string, logger echo.Logger) models.Option[bool] {
 	value, err := v.core.Settings.GetBool(key)
 	if err != nil {
@@ -768,3 +1057,46 @@ func reusableBind(c echo.Context, form any) error {
 	}
 	return c.Bind(form)
 }
+
+// isNotModified checks conditional request headers.
+//
+// If-None-Match has precedence over If-Modified-Since as specified
+// in RFC 9110.
+func isNotModified(r *http.Request, etag string, modifyTime time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if modifyTime.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modifyTime.Truncate(time.Second).After(since)
+}
+
+// jsonWithETag sends JSON response with ETag computed from content
+// and handles conditional requests.
+//
+// ETag is computed by encoding response into hash and response is
+// encoded again directly into connection, so large responses are
+// never buffered in memory.
+func jsonWithETag(c echo.Context, code int, resp any) error {
+	hash := sha256.New()
+	if err := json.NewEncoder(hash).Encode(resp); err != nil {
+		return err
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	c.Response().Header().Set("ETag", etag)
+	if isNotModified(c.Request(), etag, time.Time{}) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(code, resp)
+}
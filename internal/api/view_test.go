@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
@@ -55,6 +56,9 @@ func (e *TestEnv) SyncStores() {
 	if err := e.Core.Sessions.Sync(ctx); err != nil {
 		e.tb.Fatal("Error:", err)
 	}
+	if err := e.Core.AccountLocks.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
 	if err := e.Core.Roles.Sync(ctx); err != nil {
 		e.tb.Fatal("Error:", err)
 	}
@@ -73,9 +77,39 @@ func (e *TestEnv) SyncStores() {
 	if err := e.Core.ContestParticipants.Sync(ctx); err != nil {
 		e.tb.Fatal("Error:", err)
 	}
+	if err := e.Core.ContestSolutions.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestScoreOverrides.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestEditorials.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestAppeals.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestGrants.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
 	if err := e.Core.Problems.Sync(ctx); err != nil {
 		e.tb.Fatal("Error:", err)
 	}
+	if err := e.Core.ProblemGrants.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ProblemResources.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ProblemExtraTests.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
+	if err := e.Core.ProblemCheckers.Sync(ctx); err != nil {
+		e.tb.Fatal("Error:", err)
+	}
 	if err := e.Core.Compilers.Sync(ctx); err != nil {
 		e.tb.Fatal("Error:", err)
 	}
@@ -455,6 +489,18 @@ func (c *testClient) ObserveUser(login string) (User, error) {
 	return respData, err
 }
 
+func (c *testClient) ObserveUserSolutionsActivity(login string) (SolutionsActivity, error) {
+	req, err := http.NewRequest(
+		http.MethodGet, c.getURL("/v0/users/%s/solutions/activity", login), nil,
+	)
+	if err != nil {
+		return SolutionsActivity{}, err
+	}
+	var respData SolutionsActivity
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *testClient) ObserveContests() (Contests, error) {
 	req, err := http.NewRequest(
 		http.MethodGet, c.getURL("/v0/contests"), nil,
@@ -484,6 +530,65 @@ func (c *testClient) CreateContest(form createContestForm) (Contest, error) {
 	return respData, err
 }
 
+// ObserveProblemContent returns content of problem resource and
+// response headers.
+func (c *testClient) ObserveProblemContent(
+	problemID int64, name string, hash string,
+) ([]byte, http.Header, error) {
+	path := c.getURL("/v0/problems/%d/content/%s", problemID, url.PathEscape(name))
+	if hash != "" {
+		path += "?hash=" + url.QueryEscape(hash)
+	}
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	content, err := io.ReadAll(resp.Body)
+	return content, resp.Header, err
+}
+
+func (c *testClient) CreateContestFromTemplate(
+	template string, form createContestForm,
+) (Contest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Contest{}, err
+	}
+	req, err := http.NewRequest(
+		http.MethodPost,
+		c.getURL("/v0/contests?template=%s", url.QueryEscape(template)),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+	var respData Contest
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+// ObserveFilteredContestStandings returns standings with specified query.
+func (c *testClient) ObserveFilteredContestStandings(
+	contestID int64, query url.Values,
+) (ContestStandings, error) {
+	req, err := http.NewRequest(
+		http.MethodGet,
+		c.getURL("/v0/contests/%d/standings?%s", contestID, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+	var respData ContestStandings
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *testClient) CreateContestProblem(
 	contestID int64,
 	form createContestProblemForm,
@@ -505,6 +610,98 @@ func (c *testClient) CreateContestProblem(
 	return respData, err
 }
 
+func (c *testClient) ObserveContestProblems(contestID int64) (ContestProblems, error) {
+	req, err := http.NewRequest(
+		http.MethodGet, c.getURL("/v0/contests/%d/problems", contestID), nil,
+	)
+	if err != nil {
+		return ContestProblems{}, err
+	}
+	var respData ContestProblems
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *testClient) UpdateContestProblem(
+	contestID int64, problemID int64, form updateContestProblemForm,
+) (ContestProblem, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestProblem{}, err
+	}
+	req, err := http.NewRequest(
+		http.MethodPatch,
+		c.getURL("/v0/contests/%d/problems/%d", contestID, problemID),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestProblem{}, err
+	}
+	var respData ContestProblem
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *testClient) ReorderContestProblems(
+	contestID int64, form reorderContestProblemsForm,
+) (ContestProblems, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestProblems{}, err
+	}
+	req, err := http.NewRequest(
+		http.MethodPost,
+		c.getURL("/v0/contests/%d/problems/order", contestID),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestProblems{}, err
+	}
+	var respData ContestProblems
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *testClient) ObserveContestProblem(
+	contestID int64, problemID int64, locale string,
+) (ContestProblem, error) {
+	query := url.Values{}
+	if locale != "" {
+		query.Add("locale", locale)
+	}
+	req, err := http.NewRequest(
+		http.MethodGet,
+		c.getURL("/v0/contests/%d/problems/%d?%s", contestID, problemID, query.Encode()),
+		nil,
+	)
+	if err != nil {
+		return ContestProblem{}, err
+	}
+	var respData ContestProblem
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *testClient) RegisterContest(
+	contestID int64, form registerContestForm,
+) (ContestParticipant, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	req, err := http.NewRequest(
+		http.MethodPost,
+		c.getURL("/v0/contests/%d/register", contestID),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
 func (c *testClient) CreateRoleRole(role string, child string) (Role, error) {
 	req, err := http.NewRequest(
 		http.MethodPost, c.getURL("/v0/roles/%s/roles/%s", role, child),
@@ -547,6 +744,105 @@ func TestHealth(t *testing.T) {
 	}
 }
 
+func TestObserveTime(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	serverTime, err := e.Client.ObserveTime(context.Background())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if serverTime.Time != e.Now.Unix() || serverTime.TimeMilli != e.Now.UnixMilli() {
+		t.Fatalf("Unexpected time: %v", serverTime)
+	}
+}
+
+func TestAPIVersions(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	{
+		resp, err := http.Get(e.Server.URL + "/api/v0/time")
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		_ = resp.Body.Close()
+		expectStatus(t, http.StatusOK, resp.StatusCode)
+		if v := resp.Header.Get("Deprecation"); v != "true" {
+			t.Fatalf("Unexpected Deprecation: %q", v)
+		}
+		if v := resp.Header.Get("Link"); v != `</api/v1/time>; rel="successor-version"` {
+			t.Fatalf("Unexpected Link: %q", v)
+		}
+	}
+	{
+		resp, err := http.Get(e.Server.URL + "/api/v1/time")
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		expectStatus(t, http.StatusOK, resp.StatusCode)
+		if v := resp.Header.Get("Deprecation"); v != "" {
+			t.Fatalf("Unexpected Deprecation: %q", v)
+		}
+		var envelope struct {
+			Data ServerTime `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if envelope.Data.Time != e.Now.Unix() {
+			t.Fatalf("Unexpected time: %v", envelope.Data)
+		}
+	}
+	{
+		resp, err := http.Get(e.Server.URL + "/api/v1/users/unknown")
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		expectStatus(t, http.StatusNotFound, resp.StatusCode)
+		var envelope struct {
+			Error errorResponse `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if envelope.Error.ErrorCode != CodeUserNotFound {
+			t.Fatalf("Unexpected code: %q", envelope.Error.ErrorCode)
+		}
+	}
+}
+
+func TestMakeResponseEnvelope(t *testing.T) {
+	tests := []struct {
+		Status   int
+		Data     string
+		Expected string
+	}{
+		{http.StatusOK, `{"id":1}`, `{"data":{"id":1}}`},
+		{http.StatusOK, `[1,2]`, `{"data":[1,2]}`},
+		{
+			http.StatusOK, `{"posts":[],"next_begin_id":5}`,
+			`{"data":{"posts":[]},"pagination":{"next_begin_id":5}}`,
+		},
+		{
+			http.StatusNotFound, `{"code":"not_found","message":"Not found."}`,
+			`{"error":{"code":"not_found","message":"Not found."}}`,
+		},
+	}
+	for _, test := range tests {
+		data, err := makeResponseEnvelope(test.Status, []byte(test.Data))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if string(data) != test.Expected {
+			t.Errorf("Expected %s, got %s", test.Expected, data)
+		}
+	}
+	if _, err := makeResponseEnvelope(http.StatusOK, []byte("invalid")); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
 func TestHealthUnhealthy(t *testing.T) {
 	e := NewTestEnv(t)
 	defer e.Close()
@@ -561,6 +857,34 @@ func TestHealthUnhealthy(t *testing.T) {
 	expectStatus(t, http.StatusInternalServerError, resp.StatusCode())
 }
 
+func TestIsNotModified(t *testing.T) {
+	modifyTime := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		Header   string
+		Value    string
+		Expected bool
+	}{
+		{"", "", false},
+		{"If-None-Match", `"abc"`, true},
+		{"If-None-Match", `W/"abc"`, true},
+		{"If-None-Match", `"def", "abc"`, true},
+		{"If-None-Match", `"def"`, false},
+		{"If-None-Match", "*", true},
+		{"If-Modified-Since", modifyTime.Format(http.TimeFormat), true},
+		{"If-Modified-Since", modifyTime.Add(-time.Second).Format(http.TimeFormat), false},
+		{"If-Modified-Since", "invalid", false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.Header != "" {
+			req.Header.Set(test.Header, test.Value)
+		}
+		if v := isNotModified(req, `"abc"`, modifyTime); v != test.Expected {
+			t.Errorf("Expected %v for %s: %q, got %v", test.Expected, test.Header, test.Value, v)
+		}
+	}
+}
+
 func expectStatus(tb testing.TB, expected, got int) {
 	if got != expected {
 		tb.Fatalf("Expected %v, got %v", expected, got)
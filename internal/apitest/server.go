@@ -0,0 +1,459 @@
+// Package apitest provides in-memory API server for integration tests.
+//
+// Server runs all API handlers on top of in-memory SQLite database with
+// applied migrations, so built-in roles are already created. Real
+// invoker is replaced with fake one that instantly judges solutions
+// with configurable verdicts.
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+
+	"github.com/udovin/solve/internal/api"
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
+)
+
+// JudgeFunc returns report for solution that is judged by fake invoker.
+type JudgeFunc func(solution models.Solution) models.SolutionReport
+
+// WithVerdict returns JudgeFunc that judges all solutions with verdict.
+func WithVerdict(verdict models.Verdict) JudgeFunc {
+	return func(models.Solution) models.SolutionReport {
+		return models.SolutionReport{Verdict: verdict}
+	}
+}
+
+// Option represents option of test server.
+type Option func(*options)
+
+type options struct {
+	judge        JudgeFunc
+	updateConfig []func(*config.Config)
+}
+
+// WithJudge sets function that judges solutions.
+//
+// By default all solutions are accepted.
+func WithJudge(fn JudgeFunc) Option {
+	return func(o *options) {
+		o.judge = fn
+	}
+}
+
+// WithConfig modifies config of server core.
+func WithConfig(fn func(*config.Config)) Option {
+	return func(o *options) {
+		o.updateConfig = append(o.updateConfig, fn)
+	}
+}
+
+// Server represents API server that is running in memory.
+type Server struct {
+	tb    testing.TB
+	judge atomic.Pointer[JudgeFunc]
+	files *managers.FileManager
+	// Core contains core of server.
+	Core *core.Core
+	// Server contains HTTP server with API and socket handlers.
+	//
+	// API handlers are available with "/api" prefix and socket
+	// handlers are available with "/socket" prefix.
+	Server *httptest.Server
+	// Client contains client without authorization.
+	Client *api.Client
+	// cancel stops fake invoker.
+	cancel  context.CancelFunc
+	waiter  sync.WaitGroup
+	counter atomic.Int64
+}
+
+// NewServer creates and starts a new instance of test server.
+//
+// Server is closed automatically on test cleanup.
+func NewServer(tb testing.TB, opts ...Option) *Server {
+	tb.Helper()
+	o := options{judge: WithVerdict(models.Accepted)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cfg := config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: ":memory:"},
+		},
+		Security: &config.Security{
+			PasswordSalt: "apitest",
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: tb.TempDir(),
+			},
+		},
+	}
+	if os.Getenv("TEST_ENABLE_LOGS") != "1" {
+		log.SetLevel(log.OFF)
+		cfg.LogLevel = config.LogLevel(log.OFF)
+	}
+	for _, fn := range o.updateConfig {
+		fn(&cfg)
+	}
+	c, err := core.NewCore(cfg)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	ctx := context.Background()
+	// Database can be shared between servers, so it should be cleared
+	// after previous servers.
+	_ = db.ApplyMigrations(ctx, c.DB, "solve", migrations.Schema, db.WithZeroMigration)
+	_ = db.ApplyMigrations(ctx, c.DB, "solve_data", migrations.Data, db.WithZeroMigration)
+	if err := db.ApplyMigrations(ctx, c.DB, "solve", migrations.Schema); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := db.ApplyMigrations(ctx, c.DB, "solve_data", migrations.Data); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := c.Start(); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	e := echo.New()
+	e.Logger = c.Logger()
+	view := api.NewView(c)
+	view.Register(e.Group("/api"))
+	view.RegisterSocket(e.Group("/socket"))
+	view.StartDaemons()
+	s := Server{
+		tb:     tb,
+		files:  managers.NewFileManager(c),
+		Core:   c,
+		Server: httptest.NewServer(e),
+	}
+	s.judge.Store(&o.judge)
+	s.Client = s.NewClient()
+	invokerCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.waiter.Add(1)
+	go s.runInvoker(invokerCtx)
+	tb.Cleanup(s.Close)
+	return &s
+}
+
+// Close stops server and fake invoker.
+func (s *Server) Close() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.cancel = nil
+	s.waiter.Wait()
+	s.Server.Close()
+	s.Core.Stop()
+	ctx := context.Background()
+	_ = db.ApplyMigrations(ctx, s.Core.DB, "solve", migrations.Schema, db.WithZeroMigration)
+	_ = db.ApplyMigrations(ctx, s.Core.DB, "solve_data", migrations.Data, db.WithZeroMigration)
+}
+
+// SetJudge replaces function that judges solutions.
+func (s *Server) SetJudge(fn JudgeFunc) {
+	s.judge.Store(&fn)
+}
+
+// NewClient returns a new API client without authorization.
+//
+// Client requests synchronization of stores, so changes made by
+// previous requests are always visible.
+func (s *Server) NewClient(options ...api.ClientOption) *api.Client {
+	client := api.NewClient(s.Server.URL+"/api", options...)
+	if client.Headers == nil {
+		client.Headers = map[string]string{}
+	}
+	client.Headers["X-Solve-Sync"] = "1"
+	return client
+}
+
+// User represents active user with password.
+type User struct {
+	models.User
+	Password string
+}
+
+// CreateUser creates a new active user with specified roles.
+//
+// User is created directly in stores, so registration checks like
+// lookup of mail server are skipped.
+func (s *Server) CreateUser(roles ...string) User {
+	s.tb.Helper()
+	ctx := context.Background()
+	id := s.counter.Add(1)
+	login := fmt.Sprintf("user-%d", id)
+	password := fmt.Sprintf("password-%d", id)
+	user := models.User{
+		Login:  login,
+		Email:  models.NString(login + "@example.com"),
+		Status: models.ActiveUser,
+	}
+	if err := s.Core.Users.SetPassword(&user, password); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	if err := s.Core.Roles.Sync(ctx); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	if err := s.Core.WrapTx(ctx, func(ctx context.Context) error {
+		account := models.Account{Kind: user.AccountKind()}
+		if err := s.Core.Accounts.Create(ctx, &account); err != nil {
+			return err
+		}
+		user.ID = account.ID
+		if err := s.Core.Users.Create(ctx, &user); err != nil {
+			return err
+		}
+		for _, name := range roles {
+			role, err := s.Core.Roles.GetByName(ctx, name)
+			if err != nil {
+				return fmt.Errorf("cannot find role %q: %w", name, err)
+			}
+			edge := models.AccountRole{
+				AccountID: account.ID,
+				RoleID:    role.ID,
+			}
+			if err := s.Core.AccountRoles.Create(ctx, &edge); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	s.syncStores(s.Core.Accounts, s.Core.Users, s.Core.AccountRoles)
+	return User{User: user, Password: password}
+}
+
+// Login returns a new API client that is authorized as user.
+func (s *Server) Login(user User) *api.Client {
+	s.tb.Helper()
+	client := s.NewClient()
+	if _, err := client.Login(
+		context.Background(), user.Login, user.Password,
+	); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	return client
+}
+
+// CreateCompiler creates compiler with empty image.
+//
+// Compiler images are never used by fake invoker.
+func (s *Server) CreateCompiler(name string, extensions ...string) models.Compiler {
+	s.tb.Helper()
+	ctx := context.Background()
+	compiler := models.Compiler{Name: name}
+	if err := compiler.SetConfig(models.CompilerConfig{
+		Language:   name,
+		Compiler:   name,
+		Extensions: extensions,
+	}); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	image := []byte(name)
+	file, err := s.files.UploadFile(ctx, &managers.FileReader{
+		Name:   name + ".tar.gz",
+		Size:   int64(len(image)),
+		Reader: bytes.NewReader(image),
+	})
+	if err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	if err := s.Core.WrapTx(ctx, func(ctx context.Context) error {
+		if err := s.files.ConfirmUploadFile(ctx, &file); err != nil {
+			return err
+		}
+		compiler.ImageID = file.ID
+		return s.Core.Compilers.Create(ctx, &compiler)
+	}); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	s.syncStores(s.Core.Compilers)
+	return compiler
+}
+
+// CreateProblem creates problem without package.
+func (s *Server) CreateProblem(title string) models.Problem {
+	s.tb.Helper()
+	problem := models.Problem{Title: title}
+	if err := s.Core.Problems.Create(context.Background(), &problem); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	s.syncStores(s.Core.Problems)
+	return problem
+}
+
+// CreateContest creates running contest with specified problems.
+//
+// Contest is started now and lasts for an hour. Problems get codes
+// "A", "B", and so on in specified order.
+func (s *Server) CreateContest(title string, problems ...models.Problem) models.Contest {
+	s.tb.Helper()
+	ctx := context.Background()
+	contest := models.Contest{Title: title}
+	if err := contest.SetConfig(models.ContestConfig{
+		BeginTime:          models.NInt64(time.Now().Unix()),
+		Duration:           int(time.Hour / time.Second),
+		EnableRegistration: true,
+		EnableUpsolving:    true,
+	}); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	if err := s.Core.WrapTx(ctx, func(ctx context.Context) error {
+		if err := s.Core.Contests.Create(ctx, &contest); err != nil {
+			return err
+		}
+		for i, problem := range problems {
+			contestProblem := models.ContestProblem{
+				ContestID: contest.ID,
+				ProblemID: problem.ID,
+				Code:      string(rune('A' + i)),
+			}
+			if err := s.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	s.syncStores(s.Core.Contests, s.Core.ContestProblems)
+	return contest
+}
+
+// CreateParticipant registers user as regular participant of contest.
+func (s *Server) CreateParticipant(contest models.Contest, user User) models.ContestParticipant {
+	s.tb.Helper()
+	participant := models.ContestParticipant{
+		ContestID: contest.ID,
+		AccountID: user.ID,
+		Kind:      models.RegularParticipant,
+	}
+	if err := s.Core.ContestParticipants.Create(
+		context.Background(), &participant,
+	); err != nil {
+		s.tb.Fatal("Error:", err)
+	}
+	s.syncStores(s.Core.ContestParticipants)
+	return participant
+}
+
+// syncStores synchronizes caches of stores with database.
+func (s *Server) syncStores(stores ...models.CachedStore) {
+	s.tb.Helper()
+	for _, store := range stores {
+		if err := store.Sync(context.Background()); err != nil {
+			s.tb.Fatal("Error:", err)
+		}
+	}
+}
+
+// WaitTasks waits until all queued tasks are processed by fake invoker.
+func (s *Server) WaitTasks() {
+	s.tb.Helper()
+	for {
+		ok, err := s.hasActiveTasks(context.Background())
+		if err != nil {
+			s.tb.Fatal("Error:", err)
+		}
+		if !ok {
+			return
+		}
+		time.Sleep(invokerInterval)
+	}
+}
+
+func (s *Server) hasActiveTasks(ctx context.Context) (bool, error) {
+	if err := s.Core.Tasks.Sync(ctx); err != nil {
+		return false, err
+	}
+	tasks, err := s.Core.Tasks.FindByStatus(
+		ctx, models.QueuedTask, models.RunningTask,
+	)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tasks.Close() }()
+	if tasks.Next() {
+		return true, nil
+	}
+	return false, tasks.Err()
+}
+
+// invokerInterval represents interval of polling for queued tasks.
+const invokerInterval = 10 * time.Millisecond
+
+// runInvoker runs fake invoker that processes all queued tasks.
+//
+// Solutions are judged using JudgeFunc, other tasks are marked as
+// succeeded without any processing.
+func (s *Server) runInvoker(ctx context.Context) {
+	defer s.waiter.Done()
+	ticker := time.NewTicker(invokerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for ctx.Err() == nil {
+			task, err := s.Core.Tasks.PopQueued(ctx, time.Minute, 0, nil)
+			if err != nil {
+				if err != sql.ErrNoRows && ctx.Err() == nil {
+					s.Core.Logger().Error("Cannot pop task", err)
+				}
+				break
+			}
+			task.Status = models.SucceededTask
+			if err := s.executeTask(ctx, task); err != nil {
+				s.Core.Logger().Error("Cannot execute task", err)
+				task.Status = models.FailedTask
+			}
+			if err := s.Core.Tasks.Update(ctx, task); err != nil {
+				s.Core.Logger().Error("Cannot update task", err)
+			}
+		}
+	}
+}
+
+func (s *Server) executeTask(ctx context.Context, task models.Task) error {
+	if task.Kind != models.JudgeSolutionTask {
+		return nil
+	}
+	var config models.JudgeSolutionTaskConfig
+	if err := task.ScanConfig(&config); err != nil {
+		return err
+	}
+	solution, err := s.Core.Solutions.Get(
+		models.WithSync(ctx), config.SolutionID,
+	)
+	if err != nil {
+		return err
+	}
+	report := (*s.judge.Load())(solution)
+	if err := solution.SetReport(&report); err != nil {
+		return err
+	}
+	return s.Core.Solutions.Update(ctx, solution)
+}
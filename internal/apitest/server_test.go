@@ -0,0 +1,70 @@
+package apitest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udovin/solve/internal/api"
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestServerContestScenario(t *testing.T) {
+	s := NewServer(t, WithJudge(WithVerdict(models.WrongAnswer)))
+	compiler := s.CreateCompiler("cpp", "cpp")
+	problem := s.CreateProblem("A + B")
+	contest := s.CreateContest("Test contest", problem)
+	user := s.CreateUser()
+	s.CreateParticipant(contest, user)
+	client := s.Login(user)
+	ctx := context.Background()
+	submit := func(content string) api.ContestSolution {
+		solution, err := client.SubmitContestSolution(
+			ctx, contest.ID, "A", api.SubmitSolutionForm{
+				CompilerID: compiler.ID,
+				Content:    &content,
+			},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		return solution
+	}
+	expectVerdict := func(id int64, verdict string) {
+		s.WaitTasks()
+		solution, err := client.ObserveContestSolution(ctx, contest.ID, id)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if solution.Solution.Report == nil {
+			t.Fatal("Solution is not judged")
+		}
+		if v := solution.Solution.Report.Verdict; v != verdict {
+			t.Fatalf("Expected verdict %q, got %q", verdict, v)
+		}
+	}
+	expectVerdict(submit("wrong").ID, "wrong_answer")
+	s.SetJudge(WithVerdict(models.Accepted))
+	expectVerdict(submit("correct").ID, "accepted")
+}
+
+func TestServerRoles(t *testing.T) {
+	s := NewServer(t)
+	ctx := context.Background()
+	expectCode := func(err error, code api.ErrorCode) {
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		if c := api.GetErrorCode(err); c != code {
+			t.Fatalf("Expected code %q, got %q", code, c)
+		}
+	}
+	_, err := s.Client.ObserveSettings(ctx)
+	expectCode(err, api.CodeMissingPermissions)
+	user := s.CreateUser()
+	_, err = s.Login(user).ObserveSettings(ctx)
+	expectCode(err, api.CodeMissingPermissions)
+	admin := s.CreateUser("admin_group")
+	if _, err := s.Login(admin).ObserveSettings(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
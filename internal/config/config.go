@@ -73,6 +73,12 @@ type Config struct {
 	Security *Security `json:"security"`
 	// SMTP contains SMTP config.
 	SMTP *SMTP `json:"smtp"`
+	// Backup contains config for scheduled backups.
+	Backup *Backup `json:"backup,omitempty"`
+	// Analytics contains config for export of events to analytics.
+	Analytics *Analytics `json:"analytics,omitempty"`
+	// Bootstrap contains objects that are created on first run.
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
 	// LogLevel contains level of logging.
 	//
 	// You can use following values:
@@ -92,6 +98,143 @@ type Server struct {
 	Port int `json:"port"`
 	// SiteURL contains site index url.
 	SiteURL string `json:"site_url"`
+	// CORS contains policy of cross-origin requests.
+	//
+	// Cross-origin requests are not allowed if policy is not specified.
+	CORS *CORS `json:"cors,omitempty"`
+	// Headers contains security headers that are added to responses.
+	Headers *SecurityHeaders `json:"security_headers,omitempty"`
+	// StaticDir contains path to directory with built frontend.
+	//
+	// If directory is specified, server serves its files and responds
+	// with index.html for unknown paths outside of API.
+	StaticDir string `json:"static_dir,omitempty"`
+	// TLS contains HTTPS config.
+	//
+	// Server accepts plain HTTP requests if TLS is not specified.
+	TLS *TLS `json:"tls,omitempty"`
+	// Limits contains limits of requests that protect server from
+	// misbehaving clients.
+	Limits *ServerLimits `json:"limits,omitempty"`
+	// InvokerService contains config of gRPC service for invokers.
+	//
+	// Service is not started if config is not specified.
+	InvokerService *InvokerService `json:"invoker_service,omitempty"`
+}
+
+// InvokerService contains config of gRPC service for invokers.
+type InvokerService struct {
+	// Address contains address for listening, e.g. ":4243".
+	Address string `json:"address"`
+	// Token contains secret that invokers should pass in
+	// "authorization" metadata as "Bearer <token>".
+	Token string `json:"token"`
+}
+
+// ServerLimits contains limits of requests.
+//
+// Zero values are replaced with defaults.
+type ServerLimits struct {
+	// BodySize contains maximal size of request body in bytes.
+	//
+	// By default 4 MiB is used.
+	BodySize int64 `json:"body_size,omitempty"`
+	// SubmitBodySize contains maximal size of request body in bytes
+	// for submission of solutions.
+	//
+	// By default 1 MiB is used.
+	SubmitBodySize int64 `json:"submit_body_size,omitempty"`
+	// UploadBodySize contains maximal size of request body in bytes
+	// for requests with files, like problem packages.
+	//
+	// By default 256 MiB is used.
+	UploadBodySize int64 `json:"upload_body_size,omitempty"`
+	// ReadHeaderTimeout contains amount of seconds that client has
+	// to send request headers.
+	//
+	// By default 30 seconds is used.
+	ReadHeaderTimeout int `json:"read_header_timeout,omitempty"`
+	// BodyTimeout contains amount of seconds that client has to send
+	// request body.
+	//
+	// By default 60 seconds is used.
+	BodyTimeout int `json:"body_timeout,omitempty"`
+	// UploadBodyTimeout contains amount of seconds that client has
+	// to send request body with files.
+	//
+	// By default 900 seconds is used.
+	UploadBodyTimeout int `json:"upload_body_timeout,omitempty"`
+	// Uploads contains maximal amount of concurrent requests with
+	// files that are handled by server.
+	//
+	// By default 16 is used.
+	Uploads int `json:"uploads,omitempty"`
+	// AccountUploads contains maximal amount of concurrent requests
+	// with files from single account.
+	//
+	// By default 2 is used.
+	AccountUploads int `json:"account_uploads,omitempty"`
+}
+
+// TLS contains config of HTTPS server.
+//
+// Either certificate files or ACME should be specified.
+type TLS struct {
+	// CertFile contains path to certificate file.
+	CertFile string `json:"cert_file,omitempty"`
+	// KeyFile contains path to private key file.
+	KeyFile string `json:"key_file,omitempty"`
+	// ACME contains config for automatic certificate provisioning.
+	ACME *ACME `json:"acme,omitempty"`
+}
+
+// ACME contains config of automatic certificate provisioning
+// (for example, using Let's Encrypt).
+type ACME struct {
+	// Hosts contains list of hosts that certificates are issued for.
+	Hosts []string `json:"hosts"`
+	// Email contains contact email of certificates owner.
+	Email string `json:"email,omitempty"`
+	// CacheDir contains path to directory with issued certificates.
+	//
+	// Certificates are issued on every start if directory is not
+	// specified.
+	CacheDir string `json:"cache_dir,omitempty"`
+	// HTTPAddress contains address of server that handles HTTP-01
+	// challenges and redirects other requests to HTTPS.
+	//
+	// By default ":80" is used.
+	HTTPAddress string `json:"http_address,omitempty"`
+}
+
+// CORS contains config of cross-origin resource sharing.
+type CORS struct {
+	// AllowOrigins contains list of allowed origins.
+	//
+	// Use "*" to allow any origin.
+	AllowOrigins []string `json:"allow_origins"`
+	// AllowCredentials allows to send cookies with cross-origin requests.
+	AllowCredentials bool `json:"allow_credentials,omitempty"`
+	// MaxAge contains amount of seconds that preflight response
+	// can be cached.
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// SecurityHeaders contains config of security headers.
+type SecurityHeaders struct {
+	// ContentSecurityPolicy contains value of Content-Security-Policy header.
+	ContentSecurityPolicy string `json:"content_security_policy,omitempty"`
+	// HSTSMaxAge contains max-age of Strict-Transport-Security header.
+	//
+	// Header is sent only for HTTPS requests.
+	HSTSMaxAge int `json:"hsts_max_age,omitempty"`
+	// HSTSIncludeSubdomains adds includeSubdomains directive to
+	// Strict-Transport-Security header.
+	HSTSIncludeSubdomains bool `json:"hsts_include_subdomains,omitempty"`
+	// FrameOptions contains value of X-Frame-Options header.
+	//
+	// By default SAMEORIGIN is used.
+	FrameOptions string `json:"frame_options,omitempty"`
 }
 
 // Address returns string representation of server address.
@@ -105,6 +248,47 @@ type Security struct {
 	PasswordSalt string `json:"password_salt"`
 	// CookiePath contains path for cookies.
 	CookiePath string `json:"cookie_path"`
+	// PasswordPolicy contains requirements for new passwords.
+	PasswordPolicy *PasswordPolicy `json:"password_policy,omitempty"`
+	// PasswordHashing contains parameters of password hashing.
+	PasswordHashing *PasswordHashing `json:"password_hashing,omitempty"`
+	// SolutionURLHosts contains hosts that solutions can be fetched
+	// from by URL.
+	//
+	// Submission of solutions by URL is disabled when list is empty.
+	SolutionURLHosts []string `json:"solution_url_hosts,omitempty"`
+}
+
+// PasswordHashing contains parameters of Argon2id password hashing.
+//
+// Zero values are replaced with defaults.
+type PasswordHashing struct {
+	// Time contains amount of passes.
+	Time uint32 `json:"time,omitempty"`
+	// Memory contains amount of memory in KiB.
+	Memory uint32 `json:"memory,omitempty"`
+	// Threads contains amount of parallel threads.
+	Threads uint8 `json:"threads,omitempty"`
+	// KeyLength contains length of hash in bytes.
+	KeyLength uint32 `json:"key_length,omitempty"`
+	// DisableLegacy disables login with passwords that are hashed
+	// with legacy salted scheme and were not rehashed yet.
+	DisableLegacy bool `json:"disable_legacy,omitempty"`
+}
+
+// PasswordPolicy contains requirements for new passwords.
+type PasswordPolicy struct {
+	// MinLength contains minimal length of password.
+	MinLength int `json:"min_length,omitempty"`
+	// MinScore contains minimal strength score of password from 0 to 4.
+	MinScore int `json:"min_score,omitempty"`
+	// CheckBreaches enables check of password in database of breached
+	// passwords using k-anonymity range API.
+	CheckBreaches bool `json:"check_breaches,omitempty"`
+	// BreachesURL contains URL of range API.
+	//
+	// By default Pwned Passwords API is used.
+	BreachesURL string `json:"breaches_url,omitempty"`
 }
 
 // Invoker contains invoker config.
@@ -113,6 +297,32 @@ type Invoker struct {
 	Workers int `json:"workers"`
 	// Safeexec contains config for safeexec binary.
 	Safeexec Safeexec `json:"safeexec"`
+	// Docker contains path to docker compatible binary that is used
+	// for building compiler images.
+	//
+	// By default "docker" is used.
+	Docker string `json:"docker,omitempty"`
+	// Partitions contains dedicated resources for each worker.
+	//
+	// If partitions are specified, amount of workers is equal to
+	// amount of partitions.
+	Partitions []InvokerPartition `json:"partitions,omitempty"`
+	// ReservedCPUs contains amount of first CPU cores that are reserved
+	// for OS. If partitions are not specified, each worker uses one
+	// dedicated CPU core from remaining cores.
+	ReservedCPUs int `json:"reserved_cpus,omitempty"`
+	// Name contains name of invoker host that is reported in heartbeats.
+	//
+	// By default hostname is used.
+	Name string `json:"name,omitempty"`
+}
+
+// InvokerPartition contains resources of invoker worker.
+type InvokerPartition struct {
+	// CPUs contains list of CPU cores in cpuset format, for example "2-3".
+	CPUs string `json:"cpus,omitempty"`
+	// Memory contains memory budget of worker in bytes.
+	Memory int64 `json:"memory,omitempty"`
 }
 
 type Safeexec struct {
@@ -123,6 +333,92 @@ type Safeexec struct {
 	PidsLimit         int    `json:"pids_limit,omitempty"`
 }
 
+// Bootstrap contains config of first-run bootstrap that is performed
+// after migrations.
+//
+// Bootstrap is idempotent: existing objects are not modified.
+type Bootstrap struct {
+	// Admin contains initial administrator account.
+	//
+	// Login, password and email can be overridden with
+	// SOLVE_ADMIN_LOGIN, SOLVE_ADMIN_PASSWORD and SOLVE_ADMIN_EMAIL
+	// environment variables.
+	Admin *BootstrapAdmin `json:"admin,omitempty"`
+	// Compilers contains built-in compilers.
+	Compilers []BootstrapCompiler `json:"compilers,omitempty"`
+}
+
+// BootstrapAdmin contains credentials of initial administrator.
+type BootstrapAdmin struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+}
+
+// BootstrapCompiler contains built-in compiler.
+type BootstrapCompiler struct {
+	// Name contains unique name of compiler.
+	Name string `json:"name"`
+	// Config contains compiler config.
+	Config json.RawMessage `json:"config"`
+	// ImageFile contains path to compiler image archive.
+	ImageFile string `json:"image_file"`
+}
+
+// Backup contains config for scheduled backups.
+type Backup struct {
+	// Dir contains path to directory for backup archives.
+	Dir string `json:"dir"`
+	// Interval contains interval between backups in seconds.
+	//
+	// By default backups are created once a day.
+	Interval int64 `json:"interval,omitempty"`
+	// KeepLast contains amount of latest archives that should be kept.
+	//
+	// Zero value means that archives are never removed.
+	KeepLast int `json:"keep_last,omitempty"`
+}
+
+// AnalyticsKind represents kind of analytics sink.
+type AnalyticsKind string
+
+const (
+	// ClickHouseAnalytics means that events are inserted into ClickHouse
+	// table using HTTP interface.
+	ClickHouseAnalytics AnalyticsKind = "clickhouse"
+	// KafkaAnalytics means that events are produced to Kafka topic
+	// using REST proxy.
+	KafkaAnalytics AnalyticsKind = "kafka"
+)
+
+// Analytics contains config for export of events to analytics.
+type Analytics struct {
+	// Kind contains kind of analytics sink.
+	Kind AnalyticsKind `json:"kind"`
+	// URL contains URL of ClickHouse HTTP interface or Kafka REST proxy.
+	URL string `json:"url"`
+	// Table contains name of ClickHouse table.
+	//
+	// By default "solve_events" is used.
+	Table string `json:"table,omitempty"`
+	// Topic contains name of Kafka topic.
+	//
+	// By default "solve_events" is used.
+	Topic string `json:"topic,omitempty"`
+	// User contains user for authentication.
+	User string `json:"user,omitempty"`
+	// Password contains password for authentication.
+	Password string `json:"password,omitempty"`
+	// BatchSize contains maximal amount of events in one request.
+	//
+	// By default 1000 events are sent.
+	BatchSize int `json:"batch_size,omitempty"`
+	// Interval contains interval between requests in seconds.
+	//
+	// By default events are sent every 5 seconds.
+	Interval int64 `json:"interval,omitempty"`
+}
+
 type SMTP struct {
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
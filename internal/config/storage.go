@@ -32,6 +32,11 @@ type S3StorageOptions struct {
 	Bucket          string `json:"bucket"`
 	PathPrefix      string `json:"path_prefix,omitempty"`
 	UsePathStyle    bool   `json:"use_path_style,omitempty"`
+	// PresignExpires contains lifetime of signed URLs in seconds.
+	//
+	// If specified, public resources are served by redirects to
+	// signed URLs of storage.
+	PresignExpires int64 `json:"presign_expires,omitempty"`
 }
 
 func (o S3StorageOptions) Driver() StorageDriver {
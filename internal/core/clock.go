@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/udovin/solve/internal/db"
+)
+
+// defaultMaxClockSkew contains default maximal allowed offset between
+// local clock and clock of database.
+const defaultMaxClockSkew = 2 * time.Second
+
+// GetClockOffset returns offset of local clock relative to clock of
+// database.
+//
+// Positive offset means that local clock is ahead of database.
+func (c *Core) GetClockOffset(ctx context.Context) (time.Duration, error) {
+	begin := time.Now()
+	dbTime, err := db.GetTime(ctx, c.DB)
+	if err != nil {
+		return 0, err
+	}
+	end := time.Now()
+	// Network latency is compensated by middle of request.
+	localTime := begin.Add(end.Sub(begin) / 2)
+	return localTime.Sub(dbTime), nil
+}
+
+// GetMaxClockSkew returns maximal allowed absolute clock offset.
+//
+// Value is configured by "clock.max_skew" setting in milliseconds.
+func (c *Core) GetMaxClockSkew() time.Duration {
+	skew := defaultMaxClockSkew
+	if c.Settings == nil {
+		return skew
+	}
+	if value, err := c.Settings.GetInt64("clock.max_skew"); err == nil {
+		skew = time.Duration(value.OrElse(skew.Milliseconds())) * time.Millisecond
+	}
+	return skew
+}
+
+// IsClockSkewed returns true if absolute clock offset exceeds maximal
+// allowed clock skew.
+func (c *Core) IsClockSkewed(offset time.Duration) bool {
+	return offset.Abs() > c.GetMaxClockSkew()
+}
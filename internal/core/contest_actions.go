@@ -0,0 +1,240 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// ContestFinalizer represents finalizer of contest results.
+type ContestFinalizer interface {
+	// FinalizeContestResults should save final results of contest
+	// and mark config as finalized.
+	//
+	// Contest config is saved by caller in the same transaction.
+	FinalizeContestResults(
+		ctx context.Context,
+		contest models.Contest,
+		config *models.ContestConfig,
+		now time.Time,
+	) error
+}
+
+// RunContestActions executes scheduled contest actions until context
+// is canceled.
+//
+// Task should be started as unique daemon, otherwise actions can be
+// executed multiple times.
+func (c *Core) RunContestActions(ctx context.Context, finalizer ContestFinalizer) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if err := c.ExecuteContestActions(ctx, time.Now(), finalizer); err != nil {
+			c.Logger().Warn("Cannot execute contest actions", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExecuteContestActions executes all pending contest actions that
+// are scheduled before specified time.
+func (c *Core) ExecuteContestActions(
+	ctx context.Context, now time.Time, finalizer ContestFinalizer,
+) error {
+	if err := c.Contests.Sync(ctx); err != nil {
+		return err
+	}
+	rows, err := c.Contests.All(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+	contests, err := collectPendingContests(rows, now)
+	if err != nil {
+		return err
+	}
+	for _, contest := range contests {
+		if err := c.WrapTx(ctx, func(ctx context.Context) error {
+			return c.executeContestActions(ctx, contest, now, finalizer)
+		}); err != nil {
+			c.Logger().Warn(
+				"Cannot execute contest actions",
+				logs.Any("contest_id", contest.ID),
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+func collectPendingContests(
+	rows db.Rows[models.Contest], now time.Time,
+) ([]models.Contest, error) {
+	defer func() { _ = rows.Close() }()
+	var contests []models.Contest
+	for rows.Next() {
+		contest := rows.Row()
+		config, err := contest.GetConfig()
+		if err != nil {
+			continue
+		}
+		for _, action := range config.Actions {
+			if isPendingContestAction(config, action, now) {
+				contests = append(contests, contest)
+				break
+			}
+		}
+	}
+	return contests, rows.Err()
+}
+
+func isPendingContestAction(
+	config models.ContestConfig, action models.ContestAction, now time.Time,
+) bool {
+	if config.BeginTime == 0 || action.ExecuteTime != 0 {
+		return false
+	}
+	return now.Unix() >= int64(config.BeginTime)+action.Time
+}
+
+func (c *Core) executeContestActions(
+	ctx context.Context,
+	contest models.Contest,
+	now time.Time,
+	finalizer ContestFinalizer,
+) error {
+	config, err := contest.GetConfig()
+	if err != nil {
+		return err
+	}
+	for i, action := range config.Actions {
+		if !isPendingContestAction(config, action, now) {
+			continue
+		}
+		if err := c.executeContestAction(
+			ctx, contest, &config, action, now, finalizer,
+		); err != nil {
+			return err
+		}
+		config.Actions[i].ExecuteTime = models.NInt64(now.Unix())
+		c.Logger().Info(
+			"Executed contest action",
+			logs.Any("contest_id", contest.ID),
+			logs.Any("kind", action.Kind),
+			logs.Any("time", action.Time),
+		)
+	}
+	if err := contest.SetConfig(config); err != nil {
+		return err
+	}
+	return c.Contests.Update(ctx, contest)
+}
+
+func (c *Core) executeContestAction(
+	ctx context.Context,
+	contest models.Contest,
+	config *models.ContestConfig,
+	action models.ContestAction,
+	now time.Time,
+	finalizer ContestFinalizer,
+) error {
+	contestTime := now.Unix() - int64(config.BeginTime)
+	finished := config.Duration > 0 && contestTime >= int64(config.Duration)
+	switch action.Kind {
+	case models.AnnouncementContestAction:
+		// Contest messages require author, so announcements are
+		// published on behalf of contest owner.
+		if contest.OwnerID == 0 {
+			c.Logger().Warn(
+				"Cannot publish announcement without contest owner",
+				logs.Any("contest_id", contest.ID),
+			)
+			return nil
+		}
+		message := models.ContestMessage{
+			ContestID:   contest.ID,
+			AuthorID:    int64(contest.OwnerID),
+			Kind:        models.RegularContestMessage,
+			Title:       action.Title,
+			Description: action.Description,
+			CreateTime:  now.Unix(),
+		}
+		return c.ContestMessages.Create(ctx, &message)
+	case models.FreezeContestAction:
+		if finished {
+			return nil
+		}
+		// Zero freeze begin duration means that standings are not frozen.
+		config.FreezeBeginDuration = int(max(contestTime, 1))
+		config.FreezeEndTime = 0
+	case models.UnfreezeContestAction:
+		if finished {
+			config.FreezeEndTime = models.NInt64(now.Unix())
+		} else {
+			config.FreezeBeginDuration = 0
+		}
+	case models.FinalizeStandingsContestAction:
+		if !finished {
+			c.Logger().Warn(
+				"Cannot finalize unfinished contest",
+				logs.Any("contest_id", contest.ID),
+			)
+			return nil
+		}
+		if config.FinalizeTime != 0 {
+			return nil
+		}
+		if finalizer == nil {
+			return fmt.Errorf("contest finalizer is not configured")
+		}
+		if config.FreezeEndTime == 0 {
+			config.FreezeEndTime = models.NInt64(now.Unix())
+		}
+		return finalizer.FinalizeContestResults(ctx, contest, config, now)
+	case models.CloseRegistrationContestAction:
+		config.EnableRegistration = false
+	case models.SystemTestContestAction:
+		return c.StartContestSystemTest(ctx, contest.ID, config, now)
+	default:
+		c.Logger().Warn(
+			"Unsupported contest action",
+			logs.Any("contest_id", contest.ID),
+			logs.Any("kind", action.Kind),
+		)
+	}
+	return nil
+}
+
+// StartContestSystemTest marks contest as system tested and enqueues
+// task that judges solutions on full tests.
+//
+// Contest config should be saved by caller in the same transaction.
+func (c *Core) StartContestSystemTest(
+	ctx context.Context,
+	contestID int64,
+	config *models.ContestConfig,
+	now time.Time,
+) error {
+	if config.SystemTestTime != 0 {
+		return nil
+	}
+	task := models.Task{}
+	if err := task.SetConfig(models.SystemTestContestTaskConfig{
+		ContestID: contestID,
+	}); err != nil {
+		return err
+	}
+	if err := c.Tasks.Create(ctx, &task); err != nil {
+		return err
+	}
+	config.SystemTestTime = models.NInt64(now.Unix())
+	config.SystemTestTaskID = models.NInt64(task.ID)
+	return nil
+}
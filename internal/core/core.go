@@ -24,6 +24,8 @@ type Core struct {
 	Settings *models.SettingStore
 	// Tasks contains task store.
 	Tasks *models.TaskStore
+	// TaskFailures contains task failures store.
+	TaskFailures *models.TaskFailureStore
 	// Locks contains lock store.
 	Locks *models.LockStore
 	// Files contains file store.
@@ -38,6 +40,12 @@ type Core struct {
 	AccountRoles *models.AccountRoleStore
 	// Sessions contains session store.
 	Sessions *models.SessionStore
+	// AccountLocks contains account locks store.
+	AccountLocks *models.AccountLockStore
+	// LoginAttempts contains login attempt store.
+	LoginAttempts *models.LoginAttemptStore
+	// AccountMerges contains accounts merge store.
+	AccountMerges *models.AccountMergeStore
 	// Tokens contains token store.
 	Tokens *models.TokenStore
 	// Users contains user store.
@@ -52,8 +60,14 @@ type Core struct {
 	GroupMembers *models.GroupMemberStore
 	// Problems contains problems store.
 	Problems *models.ProblemStore
+	// ProblemGrants contains problem grants store.
+	ProblemGrants *models.ProblemGrantStore
 	// ProblemResources contains problem resources store.
 	ProblemResources *models.ProblemResourceStore
+	// ProblemExtraTests contains problem extra tests store.
+	ProblemExtraTests *models.ProblemExtraTestStore
+	// ProblemCheckers contains problem checkers store.
+	ProblemCheckers *models.ProblemCheckerStore
 	// Solutions contains solutions store.
 	Solutions *models.SolutionStore
 	// Contests contains contest store.
@@ -66,12 +80,36 @@ type Core struct {
 	ContestSolutions *models.ContestSolutionStore
 	// ContestMessages contains contest messages store.
 	ContestMessages models.ContestMessageStore
+	// ContestFeedbacks contains contest feedbacks store.
+	ContestFeedbacks *models.ContestFeedbackStore
+	// ContestGrants contains contest grants store.
+	ContestGrants *models.ContestGrantStore
+	// ContestAppeals contains contest appeals store.
+	ContestAppeals *models.ContestAppealStore
+	// ContestScoreOverrides contains contest score overrides store.
+	ContestScoreOverrides *models.ContestScoreOverrideStore
+	// ContestEditorials contains contest editorials store.
+	ContestEditorials *models.ContestEditorialStore
+	// ContestResults contains contest results store.
+	ContestResults *models.ContestResultStore
+	// ContestUnfinalizations contains contest unfinalizations store.
+	ContestUnfinalizations *models.ContestUnfinalizationStore
 	// ContestFakeParticipants contains contest fake participants store.
 	ContestFakeParticipants *models.ContestFakeParticipantStore
 	// ContestFakeSolutions contains contest fake solutions store.
 	ContestFakeSolutions *models.ContestFakeSolutionStore
+	// ContestParticipantAddresses contains contest participant addresses store.
+	ContestParticipantAddresses *models.ContestParticipantAddressStore
+	// ContestDrafts contains contest drafts store.
+	ContestDrafts *models.ContestDraftStore
+	// ContestExports contains contest exports store.
+	ContestExports *models.ContestExportStore
+	// InvokerHeartbeats contains invoker heartbeats store.
+	InvokerHeartbeats *models.InvokerHeartbeatStore
 	// Compilers contains compiler store.
 	Compilers *models.CompilerStore
+	// Organizations contains organization store.
+	Organizations *models.OrganizationStore
 	// Posts contains post store.
 	Posts models.PostStore
 	// PostFiles contains post file store.
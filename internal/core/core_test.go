@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/udovin/solve/internal/config"
 	"github.com/udovin/solve/internal/db"
 	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
 )
 
 var testCfg = config.Config{
@@ -79,3 +81,107 @@ func TestCore_WithTx(t *testing.T) {
 		t.Fatal("Expected error")
 	}
 }
+
+func TestCore_ExecuteContestActions(t *testing.T) {
+	c, err := NewCore(testCfg)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer c.Stop()
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	account := models.Account{Kind: models.UserAccountKind}
+	if err := c.Accounts.Create(context.Background(), &account); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest := models.Contest{
+		Title:   "Test contest",
+		OwnerID: models.NInt64(account.ID),
+	}
+	if err := contest.SetConfig(models.ContestConfig{
+		BeginTime:          models.NInt64(now.Unix()),
+		Duration:           3600,
+		EnableRegistration: true,
+		Actions: []models.ContestAction{
+			{Kind: models.AnnouncementContestAction, Time: 600, Title: "Test"},
+			{Kind: models.CloseRegistrationContestAction, Time: 1200},
+			{Kind: models.FreezeContestAction, Time: 2400},
+			{Kind: models.UnfreezeContestAction, Time: 7200},
+			{Kind: models.SystemTestContestAction, Time: 7200},
+		},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Contests.Create(context.Background(), &contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkConfig := func(fn func(config models.ContestConfig) bool) {
+		t.Helper()
+		contest, err := c.Contests.Get(models.WithSync(context.Background()), contest.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		config, err := contest.GetConfig()
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if !fn(config) {
+			t.Fatalf("Unexpected config: %v", config)
+		}
+	}
+	if err := c.ExecuteContestActions(context.Background(), now.Add(15*time.Minute), nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkConfig(func(config models.ContestConfig) bool {
+		return config.EnableRegistration && config.Actions[0].ExecuteTime != 0 &&
+			config.Actions[1].ExecuteTime == 0
+	})
+	if message, err := c.ContestMessages.Get(
+		models.WithSync(context.Background()), 1,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if message.ContestID != contest.ID || message.Title != "Test" {
+		t.Fatalf("Unexpected message: %v", message)
+	}
+	if err := c.ExecuteContestActions(context.Background(), now.Add(45*time.Minute), nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkConfig(func(config models.ContestConfig) bool {
+		return !config.EnableRegistration && config.FreezeBeginDuration == 2700
+	})
+	if err := c.ExecuteContestActions(context.Background(), now.Add(2*time.Hour), nil); err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkConfig(func(config models.ContestConfig) bool {
+		return config.FreezeEndTime == models.NInt64(now.Add(2*time.Hour).Unix()) &&
+			config.SystemTestTime == models.NInt64(now.Add(2*time.Hour).Unix())
+	})
+	if task, err := c.Tasks.Get(models.WithSync(context.Background()), 1); err != nil {
+		t.Fatal("Error:", err)
+	} else if task.Kind != models.SystemTestContestTask {
+		t.Fatalf("Unexpected task: %v", task)
+	}
+}
+
+func TestCore_GetClockOffset(t *testing.T) {
+	c, err := NewCore(testCfg)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	offset, err := c.GetClockOffset(context.Background())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if c.IsClockSkewed(offset) {
+		t.Fatalf("Unexpected clock skew: %v", offset)
+	}
+	if !c.IsClockSkewed(-defaultMaxClockSkew - time.Millisecond) {
+		t.Fatal("Expected clock skew")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/udovin/solve/internal/config"
 	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/pkg/logs"
 )
@@ -18,6 +19,7 @@ func (c *Core) SetupAllStores() {
 	c.Tasks = models.NewTaskStore(
 		c.DB, "solve_task", "solve_task_event",
 	)
+	c.TaskFailures = models.NewTaskFailureStore(c.DB, "solve_task_failure")
 	c.Locks = models.NewLockStore(c.DB, "solve_lock")
 	c.Files = models.NewCachedFileStore(
 		c.DB, "solve_file", "solve_file_event",
@@ -37,6 +39,11 @@ func (c *Core) SetupAllStores() {
 	c.Sessions = models.NewSessionStore(
 		c.DB, "solve_session", "solve_session_event",
 	)
+	c.AccountLocks = models.NewAccountLockStore(
+		c.DB, "solve_account_lock", "solve_account_lock_event",
+	)
+	c.LoginAttempts = models.NewLoginAttemptStore(c.DB, "solve_login_attempt")
+	c.AccountMerges = models.NewAccountMergeStore(c.DB, "solve_account_merge")
 	c.Tokens = models.NewTokenStore(
 		c.DB, "solve_token", "solve_token_event",
 	)
@@ -52,6 +59,11 @@ func (c *Core) SetupAllStores() {
 			c.DB, "solve_scope_user", "solve_scope_user_event",
 			c.Config.Security.PasswordSalt,
 		)
+		if config := c.Config.Security.PasswordHashing; config != nil {
+			hashing := getPasswordHashing(*config)
+			c.Users.SetPasswordHashing(hashing)
+			c.ScopeUsers.SetPasswordHashing(hashing)
+		}
 	}
 	c.Groups = models.NewGroupStore(
 		c.DB, "solve_group", "solve_group_event",
@@ -68,6 +80,15 @@ func (c *Core) SetupAllStores() {
 	c.ProblemResources = models.NewProblemResourceStore(
 		c.DB, "solve_problem_resource", "solve_problem_resource_event",
 	)
+	c.ProblemGrants = models.NewProblemGrantStore(
+		c.DB, "solve_problem_grant", "solve_problem_grant_event",
+	)
+	c.ProblemExtraTests = models.NewProblemExtraTestStore(
+		c.DB, "solve_problem_extra_test", "solve_problem_extra_test_event",
+	)
+	c.ProblemCheckers = models.NewProblemCheckerStore(
+		c.DB, "solve_problem_checker", "solve_problem_checker_event",
+	)
 	c.Solutions = models.NewSolutionStore(
 		c.DB, "solve_solution", "solve_solution_event",
 	)
@@ -83,15 +104,51 @@ func (c *Core) SetupAllStores() {
 	c.ContestMessages = models.NewCachedContestMessageStore(
 		c.DB, "solve_contest_message", "solve_contest_message_event",
 	)
+	c.ContestFeedbacks = models.NewContestFeedbackStore(
+		c.DB, "solve_contest_feedback", "solve_contest_feedback_event",
+	)
+	c.ContestGrants = models.NewContestGrantStore(
+		c.DB, "solve_contest_grant", "solve_contest_grant_event",
+	)
+	c.ContestAppeals = models.NewContestAppealStore(
+		c.DB, "solve_contest_appeal", "solve_contest_appeal_event",
+	)
+	c.ContestScoreOverrides = models.NewContestScoreOverrideStore(
+		c.DB, "solve_contest_score_override", "solve_contest_score_override_event",
+	)
+	c.ContestEditorials = models.NewContestEditorialStore(
+		c.DB, "solve_contest_editorial", "solve_contest_editorial_event",
+	)
+	c.ContestResults = models.NewContestResultStore(
+		c.DB, "solve_contest_result", "solve_contest_result_event",
+	)
+	c.ContestUnfinalizations = models.NewContestUnfinalizationStore(
+		c.DB, "solve_contest_unfinalization",
+	)
 	c.ContestFakeParticipants = models.NewContestFakeParticipantStore(
 		c.DB, "solve_contest_fake_participant",
 	)
 	c.ContestFakeSolutions = models.NewContestFakeSolutionStore(
 		c.DB, "solve_contest_fake_solution",
 	)
+	c.ContestParticipantAddresses = models.NewContestParticipantAddressStore(
+		c.DB, "solve_contest_participant_address",
+	)
+	c.ContestDrafts = models.NewContestDraftStore(
+		c.DB, "solve_contest_draft",
+	)
+	c.ContestExports = models.NewContestExportStore(
+		c.DB, "solve_contest_export",
+	)
+	c.InvokerHeartbeats = models.NewInvokerHeartbeatStore(
+		c.DB, "solve_invoker_heartbeat",
+	)
 	c.Compilers = models.NewCompilerStore(
 		c.DB, "solve_compiler", "solve_compiler_event",
 	)
+	c.Organizations = models.NewOrganizationStore(
+		c.DB, "solve_organization", "solve_organization_event",
+	)
 	c.Posts = models.NewCachedPostStore(
 		c.DB, "solve_post", "solve_post_event",
 	)
@@ -108,6 +165,7 @@ func (c *Core) SetupInvokerStores() {
 	c.Tasks = models.NewTaskStore(
 		c.DB, "solve_task", "solve_task_event",
 	)
+	c.TaskFailures = models.NewTaskFailureStore(c.DB, "solve_task_failure")
 	c.Files = models.NewFileStore(
 		c.DB, "solve_file", "solve_file_event",
 	)
@@ -117,12 +175,30 @@ func (c *Core) SetupInvokerStores() {
 	c.ProblemResources = models.NewProblemResourceStore(
 		c.DB, "solve_problem_resource", "solve_problem_resource_event",
 	)
+	c.ProblemExtraTests = models.NewProblemExtraTestStore(
+		c.DB, "solve_problem_extra_test", "solve_problem_extra_test_event",
+	)
+	c.ProblemCheckers = models.NewProblemCheckerStore(
+		c.DB, "solve_problem_checker", "solve_problem_checker_event",
+	)
 	c.Solutions = models.NewSolutionStore(
 		c.DB, "solve_solution", "solve_solution_event",
 	)
 	c.Compilers = models.NewCompilerStore(
 		c.DB, "solve_compiler", "solve_compiler_event",
 	)
+	c.Contests = models.NewContestStore(
+		c.DB, "solve_contest", "solve_contest_event",
+	)
+	c.ContestProblems = models.NewContestProblemStore(
+		c.DB, "solve_contest_problem", "solve_contest_problem_event",
+	)
+	c.ContestSolutions = models.NewContestSolutionStore(
+		c.DB, "solve_contest_solution", "solve_contest_solution_event",
+	)
+	c.InvokerHeartbeats = models.NewInvokerHeartbeatStore(
+		c.DB, "solve_invoker_heartbeat",
+	)
 }
 
 func (c *Core) startStores(start func(any, string, time.Duration)) {
@@ -134,6 +210,7 @@ func (c *Core) startStores(start func(any, string, time.Duration)) {
 	start(c.Accounts, "accounts", time.Second)
 	start(c.AccountRoles, "account_roles", time.Second)
 	start(c.Sessions, "sessions", time.Second)
+	start(c.AccountLocks, "account_locks", time.Second)
 	start(c.Users, "users", time.Second)
 	start(c.Scopes, "scopes", time.Second*5)
 	start(c.ScopeUsers, "scope_users", time.Second)
@@ -142,12 +219,22 @@ func (c *Core) startStores(start func(any, string, time.Duration)) {
 	start(c.Contests, "contests", time.Second)
 	start(c.Problems, "problems", time.Second)
 	start(c.ProblemResources, "problem_resources", time.Second)
+	start(c.ProblemGrants, "problem_grants", time.Second)
+	start(c.ProblemExtraTests, "problem_extra_tests", time.Second)
+	start(c.ProblemCheckers, "problem_checkers", time.Second)
 	start(c.Solutions, "solutions", time.Second)
 	start(c.ContestProblems, "contest_problems", time.Second)
 	start(c.ContestParticipants, "contest_participants", time.Second)
 	start(c.ContestSolutions, "contest_solutions", time.Second)
 	start(c.ContestMessages, "contest_messages", time.Second)
+	start(c.ContestFeedbacks, "contest_feedbacks", time.Second)
+	start(c.ContestGrants, "contest_grants", time.Second)
+	start(c.ContestAppeals, "contest_appeals", time.Second)
+	start(c.ContestScoreOverrides, "contest_score_overrides", time.Second)
+	start(c.ContestEditorials, "contest_editorials", time.Second)
+	start(c.ContestResults, "contest_results", time.Second)
 	start(c.Compilers, "compilers", time.Second*5)
+	start(c.Organizations, "organizations", time.Second*5)
 	start(c.Posts, "posts", time.Second*5)
 	start(c.PostFiles, "post_files", time.Second*5)
 }
@@ -228,3 +315,23 @@ func isNil(v any) bool {
 	rv := reflect.ValueOf(v)
 	return rv.Kind() == reflect.Ptr && rv.IsNil()
 }
+
+// getPasswordHashing returns parameters of password hashing with defaults
+// for omitted values.
+func getPasswordHashing(cfg config.PasswordHashing) models.PasswordHashing {
+	hashing := models.DefaultPasswordHashing
+	if cfg.Time > 0 {
+		hashing.Time = cfg.Time
+	}
+	if cfg.Memory > 0 {
+		hashing.Memory = cfg.Memory
+	}
+	if cfg.Threads > 0 {
+		hashing.Threads = cfg.Threads
+	}
+	if cfg.KeyLength > 0 {
+		hashing.KeyLength = cfg.KeyLength
+	}
+	hashing.DisableLegacy = cfg.DisableLegacy
+	return hashing
+}
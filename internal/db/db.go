@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/udovin/gosql"
 )
@@ -35,6 +36,24 @@ func GetTx(ctx context.Context) *sql.Tx {
 	return nil
 }
 
+// GetTime returns current time of database server.
+func GetTime(ctx context.Context, conn *gosql.DB) (time.Time, error) {
+	var query string
+	switch conn.Dialect() {
+	case gosql.SQLiteDialect:
+		query = `SELECT CAST((julianday('now') - 2440587.5) * 86400000 AS INTEGER)`
+	case gosql.PostgresDialect:
+		query = `SELECT CAST(EXTRACT(EPOCH FROM clock_timestamp()) * 1000 AS BIGINT)`
+	default:
+		return time.Time{}, fmt.Errorf("unsupported dialect: %v", conn.Dialect())
+	}
+	var millis int64
+	if err := GetRunner(ctx, conn).QueryRowContext(ctx, query).Scan(&millis); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(millis), nil
+}
+
 // Rows represents reader for events.
 type Rows[T any] interface {
 	// Next should read next event and return true if event exists.
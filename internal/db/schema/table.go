@@ -199,3 +199,38 @@ func (q CreateIndex) BuildUnapply(d gosql.Dialect) (string, error) {
 	query.WriteString(fmt.Sprintf("%q", q.getName()))
 	return query.String(), nil
 }
+
+// DropIndex represents drop index query.
+//
+// Index is created again on unapply.
+type DropIndex CreateIndex
+
+// BuildApply returns drop SQL query in specified dialect.
+func (q DropIndex) BuildApply(d gosql.Dialect) (string, error) {
+	return CreateIndex(q).BuildUnapply(d)
+}
+
+func (q DropIndex) BuildUnapply(d gosql.Dialect) (string, error) {
+	return CreateIndex(q).BuildApply(d)
+}
+
+// AddColumn represents add column query.
+//
+// Column should be nullable if table is not empty.
+type AddColumn struct {
+	Table  string
+	Column Column
+}
+
+// BuildApply returns alter SQL query in specified dialect.
+func (q AddColumn) BuildApply(d gosql.Dialect) (string, error) {
+	sql, err := q.Column.BuildSQL(d)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ALTER TABLE %q ADD COLUMN %s", q.Table, sql), nil
+}
+
+func (q AddColumn) BuildUnapply(d gosql.Dialect) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %q DROP COLUMN %q", q.Table, q.Column.Name), nil
+}
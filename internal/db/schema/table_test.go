@@ -143,3 +143,42 @@ func TestCreateTableInvalidColumn(t *testing.T) {
 		t.Fatal("Expected error")
 	}
 }
+
+func TestAddColumn(t *testing.T) {
+	q := AddColumn{
+		Table:  "test_table",
+		Column: Column{Name: "name", Type: String, Nullable: true},
+	}
+	if sql, err := q.BuildApply(gosql.SQLiteDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != `ALTER TABLE "test_table" ADD COLUMN "name" text` {
+		t.Fatal("Wrong SQL:", sql)
+	}
+	if sql, err := q.BuildUnapply(gosql.PostgresDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != `ALTER TABLE "test_table" DROP COLUMN "name"` {
+		t.Fatal("Wrong SQL:", sql)
+	}
+	q.Column.Type = 228
+	if _, err := q.BuildApply(gosql.SQLiteDialect); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestDropIndex(t *testing.T) {
+	q := DropIndex{
+		Table:   "test_table",
+		Columns: []string{"name"},
+		Unique:  true,
+	}
+	if sql, err := q.BuildApply(gosql.SQLiteDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != `DROP INDEX IF EXISTS "test_table_name_idx"` {
+		t.Fatal("Wrong SQL:", sql)
+	}
+	if sql, err := q.BuildUnapply(gosql.SQLiteDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != `CREATE UNIQUE INDEX IF NOT EXISTS "test_table_name_idx" ON "test_table" ("name")` {
+		t.Fatal("Wrong SQL:", sql)
+	}
+}
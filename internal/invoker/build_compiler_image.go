@@ -0,0 +1,198 @@
+package invoker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+func init() {
+	registerTaskImpl(models.BuildCompilerImageTask, &buildCompilerImageTask{})
+}
+
+type buildCompilerImageTask struct {
+	invoker *Invoker
+	config  models.BuildCompilerImageTaskConfig
+	tempDir string
+	docker  string
+}
+
+func (buildCompilerImageTask) New(invoker *Invoker) taskImpl {
+	return &buildCompilerImageTask{invoker: invoker}
+}
+
+func (t *buildCompilerImageTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	if t.invoker.files == nil {
+		return fmt.Errorf("storage is not configured")
+	}
+	t.docker = "docker"
+	if config := t.invoker.core.Config.Invoker; config != nil && config.Docker != "" {
+		t.docker = config.Docker
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.tempDir = tempDir
+	state := models.BuildCompilerImageTaskState{}
+	if err := t.executeImpl(ctx, &state); err != nil {
+		state.Stage = ""
+		state.Error = err.Error()
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// runDocker runs docker command and returns its stdout.
+func (t *buildCompilerImageTask) runDocker(
+	ctx context.Context, stdout io.Writer, args ...string,
+) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.docker, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf(
+			"cannot run %s %s: %w: %s",
+			t.docker, args[0], err, strings.TrimSpace(stderr.String()),
+		)
+	}
+	return nil
+}
+
+// prepareImage builds or pulls image and returns its reference.
+//
+// Returned cleanup function removes built image.
+func (t *buildCompilerImageTask) prepareImage(
+	ctx TaskContext,
+) (string, func(), error) {
+	if t.config.Dockerfile == "" {
+		if err := t.runDocker(ctx, io.Discard, "pull", t.config.Image); err != nil {
+			return "", nil, err
+		}
+		return t.config.Image, func() {}, nil
+	}
+	contextPath := filepath.Join(t.tempDir, "context")
+	if err := os.MkdirAll(contextPath, fs.ModePerm); err != nil {
+		return "", nil, err
+	}
+	dockerfilePath := filepath.Join(contextPath, "Dockerfile")
+	if err := os.WriteFile(
+		dockerfilePath, []byte(t.config.Dockerfile), fs.ModePerm,
+	); err != nil {
+		return "", nil, fmt.Errorf("cannot write Dockerfile: %w", err)
+	}
+	image := "solve-compiler-" + filepath.Base(t.tempDir)
+	if err := t.runDocker(
+		ctx, io.Discard, "build", "-t", image, "-f", dockerfilePath, contextPath,
+	); err != nil {
+		return "", nil, err
+	}
+	return image, func() {
+		if err := t.runDocker(
+			context.Background(), io.Discard, "rmi", "-f", image,
+		); err != nil {
+			ctx.Logger().Warn("Cannot remove image", logs.Any("image", image), err)
+		}
+	}, nil
+}
+
+// exportImage exports root filesystem of image to tar.gz archive.
+func (t *buildCompilerImageTask) exportImage(
+	ctx TaskContext, image, imagePath string,
+) (errRes error) {
+	var containerID bytes.Buffer
+	if err := t.runDocker(ctx, &containerID, "create", image); err != nil {
+		return err
+	}
+	container := strings.TrimSpace(containerID.String())
+	defer func() {
+		if err := t.runDocker(
+			context.Background(), io.Discard, "rm", "-f", container,
+		); err != nil {
+			ctx.Logger().Warn("Cannot remove container", logs.Any("container", container), err)
+		}
+	}()
+	file, err := os.Create(imagePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil && errRes == nil {
+			errRes = err
+		}
+	}()
+	writer := gzip.NewWriter(file)
+	if err := t.runDocker(ctx, writer, "export", container); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func (t *buildCompilerImageTask) executeImpl(
+	ctx TaskContext, state *models.BuildCompilerImageTaskState,
+) error {
+	compiler, err := t.invoker.core.Compilers.Get(
+		models.WithSync(ctx), t.config.CompilerID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch compiler: %w", err)
+	}
+	state.Stage = "building"
+	if err := ctx.SetState(ctx, state); err != nil {
+		return err
+	}
+	image, cleanup, err := t.prepareImage(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	state.Stage = "exporting"
+	if err := ctx.SetState(ctx, state); err != nil {
+		return err
+	}
+	imagePath := filepath.Join(t.tempDir, "image.tar.gz")
+	if err := t.exportImage(ctx, image, imagePath); err != nil {
+		return fmt.Errorf("cannot export image: %w", err)
+	}
+	imageFile, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	imageReader := managers.NewFileReader(imageFile)
+	defer func() { _ = imageReader.Close() }()
+	file, err := t.invoker.files.UploadFile(ctx, imageReader)
+	if err != nil {
+		return fmt.Errorf("cannot upload image: %w", err)
+	}
+	if err := t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		if err := t.invoker.files.ConfirmUploadFile(ctx, &file); err != nil {
+			return err
+		}
+		compiler.ImageID = file.ID
+		return t.invoker.core.Compilers.Update(ctx, compiler)
+	}, sqlRepeatableRead); err != nil {
+		return fmt.Errorf("cannot update compiler: %w", err)
+	}
+	state.Stage = ""
+	state.ImageID = file.ID
+	return ctx.SetDeferredState(state)
+}
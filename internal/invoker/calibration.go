@@ -0,0 +1,68 @@
+package invoker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+const (
+	// referenceTimeSetting contains key of setting with average run time
+	// of self-test on reference machine in microseconds.
+	referenceTimeSetting = "invoker.reference_time"
+	// timeFactorSettingPrefix contains prefix of settings with time
+	// factors of invokers in per mille.
+	//
+	// For example, setting "invoker.time_factor.judge-1" with value 1500
+	// means that judge-1 is 1.5 times slower than reference machine.
+	timeFactorSettingPrefix = "invoker.time_factor."
+)
+
+const (
+	defaultTimeFactor = 1000
+	minTimeFactor     = 100
+	maxTimeFactor     = 10000
+)
+
+// getTimeFactor returns time factor of invoker in per mille.
+func (s *Invoker) getTimeFactor() int64 {
+	factor, err := s.core.Settings.GetInt64(timeFactorSettingPrefix + s.getName())
+	if err != nil {
+		s.core.Logger().Warn("Invalid time factor", err)
+		return defaultTimeFactor
+	}
+	return min(max(factor.OrElse(defaultTimeFactor), minTimeFactor), maxTimeFactor)
+}
+
+// getTimeFactorByRunTime returns time factor of invoker that measured
+// specified run time in microseconds.
+func getTimeFactorByRunTime(runTime, referenceTime int64) int64 {
+	if runTime <= 0 || referenceTime <= 0 {
+		return defaultTimeFactor
+	}
+	factor := (runTime*1000 + referenceTime/2) / referenceTime
+	return min(max(factor, minTimeFactor), maxTimeFactor)
+}
+
+// getEffectiveTimeLimit returns time limit scaled by time factor.
+func getEffectiveTimeLimit(timeLimit, factor int64) int64 {
+	return (timeLimit*factor + 999) / 1000
+}
+
+func (s *Invoker) setSetting(ctx context.Context, key string, value int64) error {
+	if err := s.core.Settings.Sync(ctx); err != nil {
+		return err
+	}
+	setting, err := s.core.Settings.GetByKey(key)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		setting := models.Setting{Key: key, Value: fmt.Sprint(value)}
+		return s.core.Settings.Create(ctx, &setting)
+	}
+	setting.Value = fmt.Sprint(value)
+	return s.core.Settings.Update(ctx, setting)
+}
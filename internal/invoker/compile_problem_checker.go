@@ -0,0 +1,150 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/compilers"
+)
+
+func init() {
+	registerTaskImpl(models.CompileProblemCheckerTask, &compileProblemCheckerTask{})
+}
+
+// compileProblemCheckerTask compiles checker that is uploaded to
+// problem and rejudges solutions of problem after success.
+type compileProblemCheckerTask struct {
+	invoker *Invoker
+	config  models.CompileProblemCheckerTaskConfig
+	checker models.ProblemChecker
+	tempDir string
+	// binary contains uploaded binary of compiled checker.
+	binary *models.File
+}
+
+func (compileProblemCheckerTask) New(invoker *Invoker) taskImpl {
+	return &compileProblemCheckerTask{invoker: invoker}
+}
+
+func (t *compileProblemCheckerTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	checker, err := t.invoker.core.ProblemCheckers.Get(
+		models.WithSync(ctx), t.config.CheckerID,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch checker: %w", err)
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.checker = checker
+	t.tempDir = tempDir
+	state := models.CompileProblemCheckerTaskState{}
+	if err := t.executeImpl(ctx, &state); err != nil {
+		state.Error = err.Error()
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	return ctx.SetDeferredState(&state)
+}
+
+func (t *compileProblemCheckerTask) executeImpl(
+	ctx TaskContext, state *models.CompileProblemCheckerTaskState,
+) error {
+	if t.checker.SourceID != 0 {
+		if err := t.compileChecker(ctx); err != nil {
+			return err
+		}
+	}
+	return t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		checker, err := t.invoker.core.ProblemCheckers.Get(
+			models.WithSync(ctx), t.checker.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch checker: %w", err)
+		}
+		if t.binary != nil {
+			if err := t.invoker.files.ConfirmUploadFile(ctx, t.binary); err != nil {
+				return err
+			}
+			checker.BinaryID = models.NInt64(t.binary.ID)
+		}
+		checker.Message = t.checker.Message
+		if checker.BinaryID == 0 {
+			checker.Status = models.InvalidProblemChecker
+			return t.invoker.core.ProblemCheckers.Update(ctx, checker)
+		}
+		checker.Status = models.CompiledProblemChecker
+		if err := t.invoker.core.ProblemCheckers.Update(ctx, checker); err != nil {
+			return err
+		}
+		tasks, err := rejudgeProblemSolutions(ctx, t.invoker, checker.ProblemID)
+		if err != nil {
+			return err
+		}
+		state.RejudgeTasks = tasks
+		return nil
+	}, sqlRepeatableRead)
+}
+
+// compileChecker compiles source of checker and uploads binary.
+//
+// Compilation log is saved to message of checker, binary is not set
+// if compilation fails.
+func (t *compileProblemCheckerTask) compileChecker(ctx TaskContext) error {
+	compileCtx := &compileContext{
+		compilers: t.invoker.core.Compilers,
+		cache:     t.invoker.compilerImages,
+		logger:    ctx.Logger(),
+	}
+	defer compileCtx.Release()
+	compiler, err := compileCtx.GetCompilerByID(ctx, t.checker.CompilerID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch compiler: %w", err)
+	}
+	sourcePath := filepath.Join(t.tempDir, "checker.txt")
+	if err := downloadFile(
+		ctx, t.invoker, int64(t.checker.SourceID), sourcePath,
+	); err != nil {
+		return fmt.Errorf("cannot download source: %w", err)
+	}
+	binaryPath := filepath.Join(t.tempDir, "checker")
+	report, err := compiler.Compile(ctx, compilers.CompileOptions{
+		Source:      sourcePath,
+		Target:      binaryPath,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 512 * 1024 * 1024,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot compile checker: %w", err)
+	}
+	t.checker.Message = report.Log
+	if !report.Success() {
+		return nil
+	}
+	file, err := os.Open(binaryPath)
+	if err != nil {
+		return fmt.Errorf("cannot open checker binary: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	binary, err := t.invoker.files.UploadFile(ctx, &managers.FileReader{
+		Reader: file,
+		Name:   "checker",
+	})
+	if err != nil {
+		return err
+	}
+	t.binary = &binary
+	return nil
+}
@@ -0,0 +1,139 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/problems"
+)
+
+type extraTest struct {
+	inputPath  string
+	answerPath string
+}
+
+func (t extraTest) OpenInput() (*os.File, error) {
+	return os.Open(t.inputPath)
+}
+
+func (t extraTest) OpenAnswer() (*os.File, error) {
+	return os.Open(t.answerPath)
+}
+
+func (t extraTest) Points() float64 {
+	return 0
+}
+
+func (t extraTest) Group() string {
+	return ""
+}
+
+func (t extraTest) Sample() bool {
+	return false
+}
+
+// extraTestSet represents test set of extra tests.
+//
+// Limits of extra tests are inherited from main test set of problem.
+type extraTestSet struct {
+	problems.ProblemTestSet
+	tests []problems.ProblemTest
+}
+
+func (s extraTestSet) Name() string {
+	return "extra"
+}
+
+func (s extraTestSet) GetTests() ([]problems.ProblemTest, error) {
+	return s.tests, nil
+}
+
+func (s extraTestSet) GetGroups() ([]problems.ProblemTestGroup, error) {
+	return nil, nil
+}
+
+// problemWithExtraTests represents problem with extra tests appended
+// after tests from package.
+type problemWithExtraTests struct {
+	problems.Problem
+	tests []problems.ProblemTest
+}
+
+func (p problemWithExtraTests) GetTestSets() ([]problems.ProblemTestSet, error) {
+	testSets, err := p.Problem.GetTestSets()
+	if err != nil || len(p.tests) == 0 || len(testSets) == 0 {
+		return testSets, err
+	}
+	return append(testSets, extraTestSet{
+		ProblemTestSet: testSets[0],
+		tests:          p.tests,
+	}), nil
+}
+
+// withExtraTests returns problem with appended extra tests.
+func withExtraTests(
+	problem problems.Problem, tests []problems.ProblemTest,
+) problems.Problem {
+	if len(tests) == 0 {
+		return problem
+	}
+	return problemWithExtraTests{Problem: problem, tests: tests}
+}
+
+// downloadExtraTests downloads active extra tests of problem to dir.
+func downloadExtraTests(
+	ctx context.Context, invoker *Invoker, problemID int64, dir string,
+) ([]problems.ProblemTest, error) {
+	rows, err := invoker.core.ProblemExtraTests.FindByProblem(
+		models.WithSync(ctx), problemID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	extraTests, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	var tests []problems.ProblemTest
+	for _, extra := range extraTests {
+		if extra.Status != models.ActiveProblemExtraTest {
+			continue
+		}
+		test := extraTest{
+			inputPath:  filepath.Join(dir, fmt.Sprintf("extra-%d.in", extra.ID)),
+			answerPath: filepath.Join(dir, fmt.Sprintf("extra-%d.ans", extra.ID)),
+		}
+		if err := downloadFile(ctx, invoker, extra.InputID, test.inputPath); err != nil {
+			return nil, err
+		}
+		if err := downloadFile(ctx, invoker, extra.AnswerID, test.answerPath); err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+func downloadFile(
+	ctx context.Context, invoker *Invoker, id int64, path string,
+) error {
+	reader, err := invoker.files.DownloadFile(ctx, id)
+	if err != nil {
+		return fmt.Errorf("cannot download file: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+	return file.Sync()
+}
@@ -6,9 +6,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
+	"runtime"
+	"sync/atomic"
 	"time"
 
 	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/config"
 	"github.com/udovin/solve/internal/core"
 	"github.com/udovin/solve/internal/managers"
 	"github.com/udovin/solve/internal/models"
@@ -27,6 +31,9 @@ type Invoker struct {
 	solutions       *managers.SolutionManager
 	compilerImages  *compilerCache.CompilerImageManager
 	problemPackages *problemCache.ProblemPackageManager
+	// clockSkewed means that invoker clock differs from clock of
+	// database more than allowed, so tasks should not be judged.
+	clockSkewed atomic.Bool
 }
 
 // New creates a new instance of Invoker.
@@ -62,7 +69,7 @@ func (s *Invoker) Start() error {
 	if safeexecConfig.PidsLimit > 0 {
 		safeexecOptions = append(safeexecOptions, safeexec.WithPidsLimit(safeexecConfig.PidsLimit))
 	}
-	safeexec, err := safeexec.NewManager(
+	safeexecManager, err := safeexec.NewManager(
 		safeexecConfig.Path, "/tmp/solve-safeexec", cgroupPath, safeexecOptions...,
 	)
 	if err != nil {
@@ -70,11 +77,11 @@ func (s *Invoker) Start() error {
 	}
 	s.core.Logger().Info(
 		"Safeexec initialized",
-		logs.Any("memory_peak", safeexec.HasMemoryPeak()),
-		logs.Any("cpu_limit", safeexec.HasCPULimit()),
+		logs.Any("memory_peak", safeexecManager.HasMemoryPeak()),
+		logs.Any("cpu_limit", safeexecManager.HasCPULimit()),
 	)
 	s.compilerImages, err = compilerCache.NewCompilerImageManager(
-		s.files, safeexec, "/tmp/solve-compilers",
+		s.files, safeexecManager, "/tmp/solve-compilers",
 	)
 	if err != nil {
 		return err
@@ -87,13 +94,76 @@ func (s *Invoker) Start() error {
 	if workers <= 0 {
 		workers = 1
 	}
+	partitions, err := getWorkerPartitions(*s.core.Config.Invoker, workers, runtime.NumCPU())
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		if err := safeexecManager.SetupPartition(partition); err != nil {
+			return err
+		}
+		s.core.Logger().Info(
+			"Invoker partition initialized",
+			logs.Any("name", partition.Name),
+			logs.Any("cpus", partition.CPUs),
+			logs.Any("memory", partition.Memory),
+		)
+	}
+	if len(partitions) > 0 {
+		workers = len(partitions)
+	}
 	for i := 0; i < workers; i++ {
 		name := fmt.Sprintf("invoker-%d", i+1)
-		s.core.StartTask(name, s.runDaemon)
+		if len(partitions) > 0 {
+			partition := partitions[i].Name
+			s.core.StartTask(name, func(ctx context.Context) {
+				s.runDaemon(safeexec.WithPartition(ctx, partition))
+			})
+		} else {
+			s.core.StartTask(name, s.runDaemon)
+		}
 	}
+	s.core.StartTask("invoker-heartbeat", func(ctx context.Context) {
+		s.runHeartbeat(ctx, workers)
+	})
 	return nil
 }
 
+// getWorkerPartitions returns resource partitions of invoker workers.
+//
+// Empty result means that workers share all resources.
+func getWorkerPartitions(
+	config config.Invoker, workers int, numCPU int,
+) ([]safeexec.Partition, error) {
+	var partitions []safeexec.Partition
+	if len(config.Partitions) > 0 {
+		for i, partition := range config.Partitions {
+			partitions = append(partitions, safeexec.Partition{
+				Name:   fmt.Sprintf("worker-%d", i+1),
+				CPUs:   partition.CPUs,
+				Memory: partition.Memory,
+			})
+		}
+		return partitions, nil
+	}
+	if config.ReservedCPUs <= 0 {
+		return nil, nil
+	}
+	if config.ReservedCPUs+workers > numCPU {
+		return nil, fmt.Errorf(
+			"not enough CPU cores for %d workers: %d cores available, %d reserved",
+			workers, numCPU, config.ReservedCPUs,
+		)
+	}
+	for i := 0; i < workers; i++ {
+		partitions = append(partitions, safeexec.Partition{
+			Name: fmt.Sprintf("worker-%d", i+1),
+			CPUs: fmt.Sprint(config.ReservedCPUs + i),
+		})
+	}
+	return partitions, nil
+}
+
 func (s *Invoker) runDaemon(ctx context.Context) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
@@ -113,13 +183,97 @@ func (s *Invoker) runDaemon(ctx context.Context) {
 	}
 }
 
+// heartbeatInterval contains interval between invoker heartbeats.
+const heartbeatInterval = 10 * time.Second
+
+func (s *Invoker) getName() string {
+	if name := s.core.Config.Invoker.Name; name != "" {
+		return name
+	}
+	if name, err := os.Hostname(); err == nil {
+		return name
+	}
+	return "invoker"
+}
+
+// canExecuteTask returns true if task can be executed by invoker.
+//
+// Self-test tasks with specified invoker are executed only by that invoker.
+func (s *Invoker) canExecuteTask(task models.Task) bool {
+	if !isSupportedTask(task.Kind) {
+		return false
+	}
+	if task.Kind == models.SelfTestInvokerTask {
+		var config models.SelfTestInvokerTaskConfig
+		if err := task.ScanConfig(&config); err != nil {
+			return false
+		}
+		return config.Invoker == "" || config.Invoker == s.getName()
+	}
+	return true
+}
+
+// runHeartbeat periodically reports that invoker host is alive.
+func (s *Invoker) runHeartbeat(ctx context.Context, workers int) {
+	name := s.getName()
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.ping(ctx, name, workers); err != nil {
+			s.core.Logger().Warn("Unable to send heartbeat", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Invoker) ping(ctx context.Context, name string, workers int) error {
+	heartbeat, err := s.core.InvokerHeartbeats.GetByName(ctx, name)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		heartbeat = models.InvokerHeartbeat{Name: name}
+	}
+	heartbeat.Workers = int64(workers)
+	heartbeat.PingTime = time.Now().Unix()
+	if offset, err := s.core.GetClockOffset(ctx); err != nil {
+		s.core.Logger().Warn("Unable to get clock offset", err)
+	} else {
+		heartbeat.ClockOffset = models.NInt64(offset.Milliseconds())
+		skewed := s.core.IsClockSkewed(offset)
+		if skewed {
+			s.core.Logger().Error(
+				"Clock skew exceeds threshold, judging is paused",
+				logs.Any("offset", offset.String()),
+			)
+		} else if s.clockSkewed.Load() {
+			s.core.Logger().Info(
+				"Clock skew is fixed, judging is resumed",
+				logs.Any("offset", offset.String()),
+			)
+		}
+		s.clockSkewed.Store(skewed)
+	}
+	if heartbeat.ID == 0 {
+		return s.core.InvokerHeartbeats.Create(ctx, &heartbeat)
+	}
+	return s.core.InvokerHeartbeats.Update(ctx, heartbeat)
+}
+
 func (s *Invoker) runDaemonTick(ctx context.Context) bool {
 	select {
 	case <-ctx.Done():
 		return true
 	default:
 	}
-	task, err := popQueuedTask(ctx, s.core.Tasks)
+	if s.clockSkewed.Load() {
+		return false
+	}
+	task, err := popQueuedTask(ctx, s.core.Tasks, s.core.Settings, s.canExecuteTask)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			s.core.Logger().Error("Error", err)
@@ -136,10 +290,13 @@ func (s *Invoker) runDaemonTick(ctx context.Context) bool {
 	}
 	impl := factory.New(s)
 	logger.Info("Executing task", logs.Any("kind", task.Kind().String()))
-	if err := impl.Execute(taskCtx); err != nil {
+	if err := executeTask(taskCtx, impl); err != nil {
 		s.core.Logger().Error("Task failed", err)
 		statusCtx, cancel := context.WithTimeout(s.core.Context(), 30*time.Second)
 		defer cancel()
+		if err := s.saveTaskFailure(statusCtx, task.ObjectID(), err); err != nil {
+			logger.Error("Unable to save task failure", err)
+		}
 		if err := task.SetStatus(statusCtx, models.FailedTask); err != nil {
 			logger.Error("Unable to set failed task status", err)
 		}
@@ -155,6 +312,41 @@ func (s *Invoker) runDaemonTick(ctx context.Context) bool {
 	return true
 }
 
+// saveTaskFailure captures error and environment of failed task.
+func (s *Invoker) saveTaskFailure(
+	ctx context.Context, taskID int64, taskErr error,
+) error {
+	failure, err := s.core.TaskFailures.GetByTask(ctx, taskID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		failure = models.TaskFailure{TaskID: taskID}
+	}
+	failure.Error = taskErr.Error()
+	failure.Stack = ""
+	if panicErr, ok := taskErr.(*taskPanicError); ok {
+		failure.Stack = string(panicErr.Stack)
+	}
+	env := models.TaskFailureEnvironment{
+		Version:   config.Version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if name, err := os.Hostname(); err == nil {
+		env.Hostname = name
+	}
+	if err := failure.SetEnvironment(env); err != nil {
+		return err
+	}
+	failure.FailTime = time.Now().Unix()
+	if failure.ID == 0 {
+		return s.core.TaskFailures.Create(ctx, &failure)
+	}
+	return s.core.TaskFailures.Update(ctx, failure)
+}
+
 var (
 	sqlRepeatableRead = gosql.WithIsolation(sql.LevelRepeatableRead)
 )
@@ -2,6 +2,7 @@ package invoker
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/udovin/solve/internal/core"
 	"github.com/udovin/solve/internal/db"
 	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
 )
 
 var testInvoker *Invoker
@@ -42,6 +44,50 @@ func testSetup(tb testing.TB) {
 	testInvoker = New(c)
 }
 
+// testSetupInvokerStores setups invoker that uses only stores of
+// standalone invoker and returns core with all stores that can be
+// used for preparation of data.
+func testSetupInvokerStores(tb testing.TB) *core.Core {
+	cfg := config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{
+				Path: filepath.Join(tb.TempDir(), "db.sqlite"),
+			},
+		},
+		Invoker: &config.Invoker{},
+		Security: &config.Security{
+			PasswordSalt: "qwerty123",
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: tb.TempDir(),
+			},
+		},
+	}
+	setup, err := core.NewCore(cfg)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	setup.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), setup.DB, "solve", migrations.Schema); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := setup.Start(); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	tb.Cleanup(setup.Stop)
+	c, err := core.NewCore(cfg)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	c.SetupInvokerStores()
+	if err := c.Start(); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	testInvoker = New(c)
+	return setup
+}
+
 func testTeardown(tb testing.TB) {
 	_ = db.ApplyMigrations(context.Background(), testInvoker.core.DB, "solve", migrations.Schema, db.WithZeroMigration)
 	testInvoker.core.Stop()
@@ -54,3 +100,82 @@ func TestInvoker_Start(t *testing.T) {
 	// Wait for cache sync.
 	<-time.After(1100 * time.Millisecond)
 }
+
+func TestGetWorkerPartitions(t *testing.T) {
+	if partitions, err := getWorkerPartitions(config.Invoker{}, 2, 4); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(partitions) != 0 {
+		t.Fatalf("Expected no partitions, got %v", partitions)
+	}
+	partitions, err := getWorkerPartitions(config.Invoker{ReservedCPUs: 1}, 2, 4)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(partitions) != 2 || partitions[0].CPUs != "1" || partitions[1].CPUs != "2" {
+		t.Fatalf("Unexpected partitions: %v", partitions)
+	}
+	if _, err := getWorkerPartitions(config.Invoker{ReservedCPUs: 3}, 2, 4); err == nil {
+		t.Fatal("Expected error")
+	}
+	partitions, err = getWorkerPartitions(config.Invoker{
+		Partitions: []config.InvokerPartition{
+			{CPUs: "2-3", Memory: 1024 * 1024 * 1024},
+		},
+	}, 4, 4)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(partitions) != 1 || partitions[0].Name != "worker-1" || partitions[0].CPUs != "2-3" {
+		t.Fatalf("Unexpected partitions: %v", partitions)
+	}
+}
+
+func TestInvoker_canExecuteTask(t *testing.T) {
+	invoker := &Invoker{core: &core.Core{
+		Config: config.Config{Invoker: &config.Invoker{Name: "invoker-1"}},
+	}}
+	for _, test := range []struct {
+		Name     string
+		Expected bool
+	}{
+		{"", true},
+		{"invoker-1", true},
+		{"invoker-2", false},
+	} {
+		task := models.Task{}
+		if err := task.SetConfig(models.SelfTestInvokerTaskConfig{
+			Invoker: test.Name,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if v := invoker.canExecuteTask(task); v != test.Expected {
+			t.Errorf("Expected %v for %q, got %v", test.Expected, test.Name, v)
+		}
+	}
+	if invoker.canExecuteTask(models.Task{Kind: models.TaskKind(-1)}) {
+		t.Error("Expected unsupported task")
+	}
+}
+
+func TestGetTimeFactorByRunTime(t *testing.T) {
+	for _, test := range []struct {
+		RunTime, ReferenceTime, Expected int64
+	}{
+		{1000, 1000, 1000},
+		{1500, 1000, 1500},
+		{1, 1000, minTimeFactor},
+		{1000000, 1, maxTimeFactor},
+		{1000, 0, defaultTimeFactor},
+		{0, 1000, defaultTimeFactor},
+	} {
+		if v := getTimeFactorByRunTime(test.RunTime, test.ReferenceTime); v != test.Expected {
+			t.Errorf("Expected %d for %v, got %d", test.Expected, test, v)
+		}
+	}
+	if v := getEffectiveTimeLimit(1000, 1500); v != 1500 {
+		t.Errorf("Expected 1500, got %d", v)
+	}
+	if v := getEffectiveTimeLimit(1, 1001); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
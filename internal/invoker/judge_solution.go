@@ -2,6 +2,7 @@ package invoker
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"io/fs"
@@ -34,6 +35,21 @@ type judgeSolutionTask struct {
 	checkerImpl    compilers.Executable
 	solutionPath   string
 	compiledPath   string
+	progressTime   time.Time
+	// timeFactor contains time factor of invoker in per mille.
+	timeFactor int64
+	// timeLimit contains time limit of contest problem that overrides
+	// time limits of test sets.
+	timeLimit *int64
+	// memoryLimit contains memory limit of contest problem that overrides
+	// memory limits of test sets.
+	memoryLimit *int64
+	// usePretests enables judgement only on pretests.
+	usePretests bool
+	// pretestGroup contains name of test group with pretests of contest.
+	//
+	// Empty group means that groups marked as pretests are used.
+	pretestGroup string
 }
 
 func (judgeSolutionTask) New(invoker *Invoker) taskImpl {
@@ -45,8 +61,17 @@ func (t *judgeSolutionTask) Execute(ctx TaskContext) error {
 	if err := ctx.ScanConfig(&t.config); err != nil {
 		return fmt.Errorf("unable to scan task config: %w", err)
 	}
+	return t.withSolution(ctx, t.config.SolutionID, t.executeImpl)
+}
+
+// withSolution prepares solution with its problem and compiler and
+// calls fn with prepared solution.
+func (t *judgeSolutionTask) withSolution(
+	ctx TaskContext, solutionID int64,
+	fn func(TaskContext, problems.CompileContext) error,
+) error {
 	syncCtx := models.WithSync(ctx)
-	solution, err := t.invoker.core.Solutions.Get(syncCtx, t.config.SolutionID)
+	solution, err := t.invoker.core.Solutions.Get(syncCtx, solutionID)
 	if err != nil {
 		return fmt.Errorf("unable to fetch solution: %w", err)
 	}
@@ -81,11 +106,152 @@ func (t *judgeSolutionTask) Execute(ctx TaskContext) error {
 		}
 	}()
 	defer func() { _ = os.RemoveAll(tempDir) }()
+	extraTests, err := downloadExtraTests(ctx, t.invoker, problem.ID, tempDir)
+	if err != nil {
+		return fmt.Errorf("unable to fetch extra tests: %w", err)
+	}
+	checker, err := downloadProblemChecker(ctx, t.invoker, problem.ID, tempDir)
+	if err != nil {
+		return fmt.Errorf("unable to fetch checker: %w", err)
+	}
 	t.tempDir = tempDir
 	t.solution = solution
-	t.problem = problemPackage.Get()
+	t.problem = withChecker(withExtraTests(problemPackage.Get(), extraTests), checker)
 	t.compiler = compiler
-	return t.executeImpl(ctx, compileCtx)
+	t.timeFactor = t.invoker.getTimeFactor()
+	if err := t.fetchContestConfig(syncCtx); err != nil {
+		return fmt.Errorf("unable to fetch contest config: %w", err)
+	}
+	return fn(ctx, compileCtx)
+}
+
+// fetchContestConfig fetches limits of contest problem and pretest
+// group of contest for contest solution.
+func (t *judgeSolutionTask) fetchContestConfig(ctx context.Context) error {
+	if t.invoker.core.ContestSolutions == nil {
+		return nil
+	}
+	contestSolution, err := t.invoker.core.ContestSolutions.Get(ctx, t.solution.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	contestProblem, err := t.invoker.core.ContestProblems.Get(ctx, contestSolution.ProblemID)
+	if err != nil {
+		return err
+	}
+	config, err := contestProblem.GetConfig()
+	if err != nil {
+		return err
+	}
+	t.timeLimit = config.TimeLimit
+	t.memoryLimit = config.MemoryLimit
+	contest, err := t.invoker.core.Contests.Get(ctx, contestSolution.ContestID)
+	if err != nil {
+		return err
+	}
+	contestConfig, err := contest.GetConfig()
+	if err != nil {
+		return err
+	}
+	t.usePretests = contestConfig.UsePretests()
+	t.pretestGroup = contestConfig.PretestGroup
+	return nil
+}
+
+// pretestTestSet represents test set that contains only pretests.
+type pretestTestSet struct {
+	problems.ProblemTestSet
+	tests []problems.ProblemTest
+}
+
+func (s pretestTestSet) GetTests() ([]problems.ProblemTest, error) {
+	return s.tests, nil
+}
+
+// getPretestSets returns test sets that contain only pretests.
+//
+// Pretests are tests of specified group or tests of groups marked as
+// pretests when group is empty. Returns false if there are no pretests,
+// so solution should be judged on all tests.
+func getPretestSets(
+	testSets []problems.ProblemTestSet, group string,
+) ([]problems.ProblemTestSet, bool, error) {
+	var pretestSets []problems.ProblemTestSet
+	for _, testSet := range testSets {
+		pretestGroups := map[string]struct{}{}
+		if group != "" {
+			pretestGroups[group] = struct{}{}
+		} else {
+			groups, err := testSet.GetGroups()
+			if err != nil {
+				return nil, false, err
+			}
+			for _, group := range groups {
+				if group.Pretests() {
+					pretestGroups[group.Name()] = struct{}{}
+				}
+			}
+		}
+		tests, err := testSet.GetTests()
+		if err != nil {
+			return nil, false, err
+		}
+		var pretests []problems.ProblemTest
+		for _, test := range tests {
+			if _, ok := pretestGroups[test.Group()]; ok {
+				pretests = append(pretests, test)
+			}
+		}
+		if len(pretests) > 0 {
+			pretestSets = append(pretestSets, pretestTestSet{
+				ProblemTestSet: testSet,
+				tests:          pretests,
+			})
+		}
+	}
+	if len(pretestSets) == 0 {
+		return testSets, false, nil
+	}
+	return pretestSets, true, nil
+}
+
+// limitedTestSet represents test set with overridden limits.
+type limitedTestSet struct {
+	problems.ProblemTestSet
+	timeLimit   int64
+	memoryLimit int64
+}
+
+func (s limitedTestSet) TimeLimit() int64 {
+	return s.timeLimit
+}
+
+func (s limitedTestSet) MemoryLimit() int64 {
+	return s.memoryLimit
+}
+
+// getTestSet returns test set with limits of contest problem and time
+// limit scaled by time factor of invoker.
+func (t *judgeSolutionTask) getTestSet(testSet problems.ProblemTestSet) problems.ProblemTestSet {
+	if t.timeLimit == nil && t.memoryLimit == nil && t.timeFactor == defaultTimeFactor {
+		return testSet
+	}
+	limited := limitedTestSet{
+		ProblemTestSet: testSet,
+		timeLimit:      testSet.TimeLimit(),
+		memoryLimit:    testSet.MemoryLimit(),
+	}
+	if t.timeLimit != nil {
+		limited.timeLimit = *t.timeLimit
+	}
+	if t.memoryLimit != nil {
+		limited.memoryLimit = *t.memoryLimit
+	}
+	limited.timeLimit = getEffectiveTimeLimit(limited.timeLimit, t.timeFactor)
+	return limited
 }
 
 func (t *judgeSolutionTask) newCompileContext(ctx TaskContext) CompileContext {
@@ -106,32 +272,42 @@ func (t *judgeSolutionTask) prepareSolution(ctx TaskContext) error {
 		t.solutionPath = tempSolutionPath
 		return nil
 	}
-	solutionFile, err := t.invoker.files.DownloadFile(ctx, int64(t.solution.ContentID))
-	if err != nil {
-		return fmt.Errorf("cannot download solution: %w", err)
-	}
-	defer func() { _ = solutionFile.Close() }()
 	tempSolutionPath := filepath.Join(t.tempDir, "solution.bin")
-	file, err := os.Create(tempSolutionPath)
-	if err != nil {
-		return fmt.Errorf("cannot create solution: %w", err)
-	}
-	defer func() { _ = file.Close() }()
-	if _, err := io.Copy(file, solutionFile); err != nil {
-		return fmt.Errorf("cannot write solution: %w", err)
+	if err := t.invoker.files.DownloadVerifiedFile(
+		ctx, int64(t.solution.ContentID), tempSolutionPath,
+	); err != nil {
+		return fmt.Errorf("cannot download solution: %w", err)
 	}
 	t.solutionPath = tempSolutionPath
 	t.compiledPath = filepath.Join(t.tempDir, "solution")
 	return nil
 }
 
+// progressInterval contains minimal interval between writes of
+// judgement progress to store.
+const progressInterval = time.Second
+
+// setProgress updates state of task with judgement progress.
+//
+// State is written to store not more often than progressInterval,
+// otherwise it will be written with next update of task.
+func (t *judgeSolutionTask) setProgress(
+	ctx TaskContext, state models.JudgeSolutionTaskState,
+) error {
+	if time.Since(t.progressTime) < progressInterval {
+		return ctx.SetDeferredState(state)
+	}
+	t.progressTime = time.Now()
+	return ctx.SetState(ctx, state)
+}
+
 func (t *judgeSolutionTask) compileSolution(
 	ctx TaskContext, report *models.SolutionReport,
 ) (bool, error) {
 	state := models.JudgeSolutionTaskState{
 		Stage: "compiling",
 	}
-	if err := ctx.SetDeferredState(state); err != nil {
+	if err := t.setProgress(ctx, state); err != nil {
 		return false, err
 	}
 	compileReport, err := t.compiler.Compile(ctx, compilers.CompileOptions{
@@ -169,77 +345,65 @@ var (
 func (t *judgeSolutionTask) getChecker(
 	ctx TaskContext, compileCtx problems.CompileContext, executables []problems.ProblemExecutable,
 ) (compilers.Executable, error) {
-	var checker problems.ProblemExecutable
-	for _, executable := range executables {
-		if executable.Kind() == problems.TestlibChecker {
-			checker = executable
-			break
-		}
-	}
+	checker := findProblemExecutable(executables, problems.TestlibChecker)
 	if checker == nil {
 		return nil, errNoChecker
 	}
-	compiler, err := checker.GetCompiler(ctx, compileCtx)
-	if err != nil {
-		return nil, err
-	}
 	checkerPath := filepath.Join(t.tempDir, "checker")
-	if err := func() error {
-		testFile, err := checker.OpenBinary()
-		if err != nil {
-			return err
-		}
-		file, err := os.OpenFile(checkerPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = file.Close() }()
-		if _, err := io.Copy(file, testFile); err != nil {
-			return err
-		}
-		return file.Sync()
-	}(); err != nil {
-		return nil, err
-	}
-	return compiler.CreateExecutable(ctx, checkerPath)
+	return createProblemExecutable(ctx, compileCtx, checker, checkerPath)
 }
 
 func (t *judgeSolutionTask) getInteractor(
 	ctx TaskContext, compileCtx problems.CompileContext, executables []problems.ProblemExecutable,
 ) (compilers.Executable, error) {
-	var interactor problems.ProblemExecutable
-	for _, executable := range executables {
-		if executable.Kind() == problems.TestlibInteractor {
-			interactor = executable
-			break
-		}
-	}
+	interactor := findProblemExecutable(executables, problems.TestlibInteractor)
 	if interactor == nil {
 		return nil, errNoInteractor
 	}
-	compiler, err := interactor.GetCompiler(ctx, compileCtx)
+	interactorPath := filepath.Join(t.tempDir, "interactor")
+	return createProblemExecutable(ctx, compileCtx, interactor, interactorPath)
+}
+
+func findProblemExecutable(
+	executables []problems.ProblemExecutable, kind problems.ProblemExecutableKind,
+) problems.ProblemExecutable {
+	for _, executable := range executables {
+		if executable.Kind() == kind {
+			return executable
+		}
+	}
+	return nil
+}
+
+// createProblemExecutable extracts binary of problem executable to
+// specified path and creates executable for it.
+func createProblemExecutable(
+	ctx context.Context, compileCtx problems.CompileContext,
+	executable problems.ProblemExecutable, path string,
+) (compilers.Executable, error) {
+	compiler, err := executable.GetCompiler(ctx, compileCtx)
 	if err != nil {
 		return nil, err
 	}
-	interactorPath := filepath.Join(t.tempDir, "interactor")
 	if err := func() error {
-		testFile, err := interactor.OpenBinary()
+		binary, err := executable.OpenBinary()
 		if err != nil {
 			return err
 		}
-		file, err := os.OpenFile(interactorPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+		defer func() { _ = binary.Close() }()
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
 		if err != nil {
 			return err
 		}
 		defer func() { _ = file.Close() }()
-		if _, err := io.Copy(file, testFile); err != nil {
+		if _, err := io.Copy(file, binary); err != nil {
 			return err
 		}
 		return file.Sync()
 	}(); err != nil {
 		return nil, err
 	}
-	return compiler.CreateExecutable(ctx, interactorPath)
+	return compiler.CreateExecutable(ctx, path)
 }
 
 func (t *judgeSolutionTask) calculateTestSetPoints(
@@ -333,19 +497,12 @@ func (t *judgeSolutionTask) executeSolution(
 		return models.TestReport{}, fmt.Errorf("cannot wait solution: %w", err)
 	}
 	testReport := models.TestReport{
-		Verdict: models.Accepted,
+		Verdict: getExecuteVerdict(report, testSet),
 		Usage: models.UsageReport{
 			Time:   report.Time.Milliseconds(),
 			Memory: report.Memory,
 		},
 	}
-	if report.Time.Milliseconds() > testSet.TimeLimit() {
-		testReport.Verdict = models.TimeLimitExceeded
-	} else if report.Memory > testSet.MemoryLimit() {
-		testReport.Verdict = models.MemoryLimitExceeded
-	} else if report.ExitCode != 0 {
-		testReport.Verdict = models.RuntimeError
-	}
 	return testReport, nil
 }
 
@@ -460,11 +617,104 @@ func (t *judgeSolutionTask) executeInteractiveSolution(
 	return testReport, nil
 }
 
+// Default policy of repeated runs.
+const (
+	defaultRepeatRuns      = 1
+	defaultRepeatThreshold = 90
+)
+
+// getRepeatPolicy returns maximal amount of solution runs on test and
+// percentage of time limit starting from which test is repeated.
+func (t *judgeSolutionTask) getRepeatPolicy() (int, int64) {
+	runs, threshold := int64(defaultRepeatRuns), int64(defaultRepeatThreshold)
+	if value, err := t.invoker.core.Settings.GetInt64("invoker.repeat_runs"); err == nil {
+		runs = value.OrElse(runs)
+	}
+	if value, err := t.invoker.core.Settings.GetInt64("invoker.repeat_threshold"); err == nil {
+		threshold = value.OrElse(threshold)
+	}
+	return int(min(max(runs, 1), 10)), min(max(threshold, 0), 100)
+}
+
+// isNearTimeLimit returns true when used time is not less than specified
+// percentage of time limit.
+func isNearTimeLimit(
+	report models.TestReport, testSet problems.ProblemTestSet, threshold int64,
+) bool {
+	return report.Usage.Time*100 >= testSet.TimeLimit()*threshold
+}
+
+// executeSolutionRepeated executes solution and repeats execution when
+// used time is near to time limit.
+//
+// Report of the fastest run is returned.
+func (t *judgeSolutionTask) executeSolutionRepeated(
+	ctx context.Context,
+	testSet problems.ProblemTestSet,
+	inputPath, outputPath, answerPath string,
+) (models.TestReport, error) {
+	testReport, err := t.executeSolution(
+		ctx, testSet, inputPath, outputPath, answerPath,
+	)
+	if err != nil {
+		return models.TestReport{}, err
+	}
+	maxRuns, threshold := t.getRepeatPolicy()
+	runs := 1
+	repeatPath := outputPath + ".repeat"
+	for ; runs < maxRuns && isNearTimeLimit(testReport, testSet, threshold); runs++ {
+		repeatReport, err := t.executeSolution(
+			ctx, testSet, inputPath, repeatPath, answerPath,
+		)
+		if err != nil {
+			return models.TestReport{}, err
+		}
+		if repeatReport.Usage.Time < testReport.Usage.Time {
+			if err := os.Rename(repeatPath, outputPath); err != nil {
+				return models.TestReport{}, err
+			}
+			testReport = repeatReport
+		}
+	}
+	if runs > 1 {
+		testReport.Runs = runs
+	}
+	return testReport, nil
+}
+
 func (t *judgeSolutionTask) runSolutionTest(
 	ctx TaskContext,
 	testSet problems.ProblemTestSet,
 	test problems.ProblemTest,
 ) (models.TestReport, error) {
+	run, err := t.executeSolutionTest(ctx, testSet, test)
+	if err != nil {
+		return models.TestReport{}, err
+	}
+	if run.Report.Verdict != models.Accepted {
+		if err := t.uploadTestArtifacts(
+			ctx, &run.Report, run.OutputPath, run.CheckerLog,
+		); err != nil {
+			return models.TestReport{}, err
+		}
+	}
+	return run.Report, nil
+}
+
+// solutionTestRun represents result of solution run on single test.
+type solutionTestRun struct {
+	Report     models.TestReport
+	OutputPath string
+	// CheckerLog contains full log of checker or interactor.
+	CheckerLog string
+}
+
+// executeSolutionTest runs solution on test and checks its output.
+func (t *judgeSolutionTask) executeSolutionTest(
+	ctx TaskContext,
+	testSet problems.ProblemTestSet,
+	test problems.ProblemTest,
+) (solutionTestRun, error) {
 	inputPath := filepath.Join(t.tempDir, "test.in")
 	outputPath := filepath.Join(t.tempDir, "test.out")
 	answerPath := filepath.Join(t.tempDir, "test.ans")
@@ -484,7 +734,7 @@ func (t *judgeSolutionTask) runSolutionTest(
 		}
 		return file.Sync()
 	}(); err != nil {
-		return models.TestReport{}, err
+		return solutionTestRun{}, err
 	}
 	// Copy output.
 	if err := func() error {
@@ -502,20 +752,29 @@ func (t *judgeSolutionTask) runSolutionTest(
 		}
 		return file.Sync()
 	}(); err != nil {
-		return models.TestReport{}, err
+		return solutionTestRun{}, err
 	}
-	testReport, err := t.executeSolution(
+	testReport, err := t.executeSolutionRepeated(
 		ctx, testSet, inputPath, outputPath, answerPath,
 	)
 	if err != nil {
-		return models.TestReport{}, err
+		return solutionTestRun{}, err
 	}
+	testReport.TimeLimit = testSet.TimeLimit()
+	run := solutionTestRun{OutputPath: outputPath}
 	if testReport.Verdict != models.Accepted {
-		return testReport, nil
+		if testReport.Interactor != nil {
+			run.CheckerLog = testReport.Interactor.Log
+		}
+		run.Report = testReport
+		return run, nil
 	}
-	checkerReport, err := runTestlibChecker(ctx, t.checkerImpl, inputPath, outputPath, answerPath)
+	checkerLog := utils.NewTruncateBuffer(testArtifactMaxSize)
+	checkerReport, err := runTestlibChecker(
+		ctx, t.checkerImpl, inputPath, outputPath, answerPath, checkerLog,
+	)
 	if err != nil {
-		return models.TestReport{}, err
+		return solutionTestRun{}, err
 	}
 	testReport.Verdict = checkerReport.Verdict
 	testReport.Checker = checkerReport.Checker
@@ -524,20 +783,35 @@ func (t *judgeSolutionTask) runSolutionTest(
 			testReport.Points = &points
 		}
 	}
-	return testReport, nil
+	run.Report = testReport
+	run.CheckerLog = checkerLog.String()
+	return run, nil
 }
 
 func (t *judgeSolutionTask) runSolutionTests(
 	ctx TaskContext, report *models.SolutionReport,
 ) error {
+	testSets, err := t.problem.GetTestSets()
+	if err != nil {
+		return err
+	}
+	if t.usePretests {
+		testSets, report.Pretests, err = getPretestSets(testSets, t.pretestGroup)
+		if err != nil {
+			return err
+		}
+	}
 	state := models.JudgeSolutionTaskState{
 		Stage: "testing",
 	}
-	if err := ctx.SetDeferredState(state); err != nil {
-		return err
+	for _, testSet := range testSets {
+		tests, err := testSet.GetTests()
+		if err != nil {
+			return err
+		}
+		state.TotalTests += len(tests)
 	}
-	testSets, err := t.problem.GetTestSets()
-	if err != nil {
+	if err := t.setProgress(ctx, state); err != nil {
 		return err
 	}
 	report.Verdict = models.Accepted
@@ -547,6 +821,7 @@ func (t *judgeSolutionTask) runSolutionTests(
 	}
 	testNumber := 0
 	for _, testSet := range testSets {
+		testSet = t.getTestSet(testSet)
 		tests, err := testSet.GetTests()
 		if err != nil {
 			return err
@@ -555,7 +830,7 @@ func (t *judgeSolutionTask) runSolutionTests(
 		for _, test := range tests {
 			testNumber++
 			state.Test = testNumber
-			if err := ctx.SetDeferredState(state); err != nil {
+			if err := t.setProgress(ctx, state); err != nil {
 				return err
 			}
 			testReport, err := t.runSolutionTest(ctx, testSet, test)
@@ -565,6 +840,8 @@ func (t *judgeSolutionTask) runSolutionTests(
 			if !t.config.EnablePoints {
 				testReport.Points = nil
 			}
+			testReport.Group = test.Group()
+			testReport.Sample = test.Sample()
 			groupTests[test.Group()] = append(
 				groupTests[test.Group()], len(report.Tests),
 			)
@@ -0,0 +1,355 @@
+package invoker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/problems"
+)
+
+func TestJudgeSolutionTask_SetProgress(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	task := models.Task{
+		Status:     models.RunningTask,
+		ExpireTime: models.NInt64(time.Now().Add(time.Minute).Unix()),
+	}
+	if err := task.SetConfig(models.JudgeSolutionTaskConfig{}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := testInvoker.core.Tasks.Create(ctx, &task); err != nil {
+		t.Fatal("Error:", err)
+	}
+	guard := &taskGuard{store: testInvoker.core.Tasks, task: task}
+	taskCtx := newTaskContext(ctx, guard, testInvoker.core.Logger())
+	judge := judgeSolutionTask{invoker: testInvoker}
+	checkState := func(expected models.JudgeSolutionTaskState) {
+		t.Helper()
+		task, err := testInvoker.core.Tasks.Get(models.WithSync(ctx), task.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		var state models.JudgeSolutionTaskState
+		if err := task.ScanState(&state); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if state != expected {
+			t.Fatalf("Expected %+v, got %+v", expected, state)
+		}
+	}
+	state := models.JudgeSolutionTaskState{Stage: "testing", TotalTests: 10}
+	if err := judge.setProgress(taskCtx, state); err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkState(state)
+	state.Test = 1
+	if err := judge.setProgress(taskCtx, state); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Progress is not written until progress interval is elapsed.
+	checkState(models.JudgeSolutionTaskState{Stage: "testing", TotalTests: 10})
+	judge.progressTime = time.Now().Add(-progressInterval)
+	state.Test = 2
+	if err := judge.setProgress(taskCtx, state); err != nil {
+		t.Fatal("Error:", err)
+	}
+	checkState(state)
+}
+
+type testTimeLimitTestSet struct {
+	problems.ProblemTestSet
+	timeLimit int64
+}
+
+func (s testTimeLimitTestSet) TimeLimit() int64 {
+	return s.timeLimit
+}
+
+func TestJudgeSolutionTask_RepeatPolicy(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	judge := judgeSolutionTask{invoker: testInvoker}
+	if runs, threshold := judge.getRepeatPolicy(); runs != 1 || threshold != 90 {
+		t.Fatalf("Unexpected policy: %d, %d", runs, threshold)
+	}
+	for _, setting := range []models.Setting{
+		{Key: "invoker.repeat_runs", Value: "3"},
+		{Key: "invoker.repeat_threshold", Value: "80"},
+	} {
+		if err := testInvoker.core.Settings.Create(ctx, &setting); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := testInvoker.core.Settings.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	runs, threshold := judge.getRepeatPolicy()
+	if runs != 3 || threshold != 80 {
+		t.Fatalf("Unexpected policy: %d, %d", runs, threshold)
+	}
+	testSet := testTimeLimitTestSet{timeLimit: 1000}
+	report := models.TestReport{Usage: models.UsageReport{Time: 799}}
+	if isNearTimeLimit(report, testSet, threshold) {
+		t.Fatal("Expected time far from limit")
+	}
+	report.Usage.Time = 1001
+	if !isNearTimeLimit(report, testSet, threshold) {
+		t.Fatal("Expected time near to limit")
+	}
+}
+
+type testGroupTest struct {
+	problems.ProblemTest
+	group string
+}
+
+func (t testGroupTest) Group() string {
+	return t.group
+}
+
+type testPretestGroup struct {
+	problems.ProblemTestGroup
+	name string
+}
+
+func (g testPretestGroup) Name() string {
+	return g.name
+}
+
+func (g testPretestGroup) Pretests() bool {
+	return true
+}
+
+type testTestsTestSet struct {
+	problems.ProblemTestSet
+	tests  []problems.ProblemTest
+	groups []problems.ProblemTestGroup
+}
+
+func (s testTestsTestSet) GetTests() ([]problems.ProblemTest, error) {
+	return s.tests, nil
+}
+
+func (s testTestsTestSet) GetGroups() ([]problems.ProblemTestGroup, error) {
+	return s.groups, nil
+}
+
+func TestGetPretestSets(t *testing.T) {
+	testSets := []problems.ProblemTestSet{
+		testTestsTestSet{tests: []problems.ProblemTest{
+			testGroupTest{group: "pretests"},
+			testGroupTest{group: "main"},
+			testGroupTest{group: "pretests"},
+			testGroupTest{group: "first"},
+		}, groups: []problems.ProblemTestGroup{
+			testPretestGroup{name: "first"},
+		}},
+		testTestsTestSet{tests: []problems.ProblemTest{
+			testGroupTest{group: "main"},
+		}},
+	}
+	pretestSets, ok, err := getPretestSets(testSets, "pretests")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !ok || len(pretestSets) != 1 {
+		t.Fatalf("Expected single pretest set, got %v", pretestSets)
+	}
+	if tests, err := pretestSets[0].GetTests(); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(tests) != 2 {
+		t.Fatalf("Expected 2 pretests, got %d", len(tests))
+	}
+	// Solutions are judged on all tests without pretests.
+	if sets, ok, err := getPretestSets(testSets, "unknown"); err != nil {
+		t.Fatal("Error:", err)
+	} else if ok || len(sets) != len(testSets) {
+		t.Fatalf("Expected all test sets, got %v", sets)
+	}
+	// Groups marked as pretests are used without explicit group.
+	pretestSets, ok, err = getPretestSets(testSets, "")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !ok || len(pretestSets) != 1 {
+		t.Fatalf("Expected single pretest set, got %v", pretestSets)
+	}
+	if tests, err := pretestSets[0].GetTests(); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(tests) != 1 || tests[0].Group() != "first" {
+		t.Fatalf("Unexpected pretests: %v", tests)
+	}
+}
+
+func TestJudgeSolutionTask_FetchContestConfig(t *testing.T) {
+	setup := testSetupInvokerStores(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	account := models.Account{Kind: models.UserAccountKind}
+	if err := setup.Accounts.Create(ctx, &account); err != nil {
+		t.Fatal("Error:", err)
+	}
+	image := models.File{Status: models.AvailableFile, Meta: models.JSON("{}")}
+	if err := setup.Files.Create(ctx, &image); err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "test", Config: models.JSON("{}"), ImageID: image.ID}
+	if err := setup.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := setup.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest := models.Contest{Title: "Test contest"}
+	if err := contest.SetConfig(models.ContestConfig{
+		PretestGroup: "pretests",
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := setup.Contests.Create(ctx, &contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	timeLimit := int64(2000)
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID, ProblemID: problem.ID, Code: "A",
+	}
+	if err := contestProblem.SetConfig(models.ContestProblemConfig{
+		TimeLimit: &timeLimit,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := setup.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant := models.ContestParticipant{
+		ContestID: contest.ID, AccountID: account.ID,
+		Kind: models.RegularParticipant,
+	}
+	if err := setup.ContestParticipants.Create(ctx, &participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		Kind:       models.ContestSolutionKind,
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   account.ID,
+	}
+	if err := setup.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     contest.ID,
+		ParticipantID: participant.ID,
+		ProblemID:     contestProblem.ID,
+	}
+	contestSolution.ID = solution.ID
+	if err := setup.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	judge := judgeSolutionTask{invoker: testInvoker, solution: solution}
+	if err := judge.fetchContestConfig(models.WithSync(ctx)); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if judge.timeLimit == nil || *judge.timeLimit != timeLimit {
+		t.Fatalf("Expected time limit %d, got %v", timeLimit, judge.timeLimit)
+	}
+	if !judge.usePretests || judge.pretestGroup != "pretests" {
+		t.Fatalf("Unexpected pretests: %v, %q", judge.usePretests, judge.pretestGroup)
+	}
+}
+
+func TestJudgeSolutionTask_DownloadProblemResources(t *testing.T) {
+	setup := testSetupInvokerStores(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	account := models.Account{Kind: models.UserAccountKind}
+	if err := setup.Accounts.Create(ctx, &account); err != nil {
+		t.Fatal("Error:", err)
+	}
+	files := managers.NewFileManager(setup)
+	uploadFile := func(name, content string) models.File {
+		file, err := files.UploadFile(ctx, &managers.FileReader{
+			Name:   name,
+			Size:   int64(len(content)),
+			Reader: strings.NewReader(content),
+		})
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := files.ConfirmUploadFile(ctx, &file); err != nil {
+			t.Fatal("Error:", err)
+		}
+		return file
+	}
+	image := uploadFile("image.tar.gz", "image")
+	compiler := models.Compiler{Name: "test", Config: models.JSON("{}"), ImageID: image.ID}
+	if err := setup.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := setup.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	input := uploadFile("test.in", "1 2")
+	answer := uploadFile("test.ans", "3")
+	test := models.ProblemExtraTest{
+		ProblemID: problem.ID,
+		InputID:   input.ID,
+		AnswerID:  answer.ID,
+		Status:    models.ActiveProblemExtraTest,
+		AuthorID:  account.ID,
+	}
+	if err := setup.ProblemExtraTests.Create(ctx, &test); err != nil {
+		t.Fatal("Error:", err)
+	}
+	binary := uploadFile("checker.bin", "checker")
+	checker := models.ProblemChecker{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		BinaryID:   models.NInt64(binary.ID),
+		Status:     models.CompiledProblemChecker,
+		AuthorID:   account.ID,
+	}
+	if err := setup.ProblemCheckers.Create(ctx, &checker); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, store := range []models.CachedStore{
+		testInvoker.core.ProblemExtraTests,
+		testInvoker.core.ProblemCheckers,
+	} {
+		if err := store.Sync(ctx); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	// Judging requires sandbox, so only resources that judge task
+	// fetches from stores of invoker are checked.
+	dir := t.TempDir()
+	tests, err := downloadExtraTests(ctx, testInvoker, problem.ID, dir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(tests) != 1 {
+		t.Fatalf("Expected 1 extra test, got %d", len(tests))
+	}
+	executable, err := downloadProblemChecker(ctx, testInvoker, problem.ID, dir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if executable == nil {
+		t.Fatal("Expected checker")
+	}
+	if content, err := os.ReadFile(filepath.Join(dir, "checker.bin")); err != nil {
+		t.Fatal("Error:", err)
+	} else if string(content) != "checker" {
+		t.Fatalf("Expected %q, got %q", "checker", content)
+	}
+}
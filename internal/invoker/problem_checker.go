@@ -0,0 +1,100 @@
+package invoker
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/compilers"
+	"github.com/udovin/solve/internal/pkg/problems"
+)
+
+// problemChecker represents checker that is uploaded to problem.
+type problemChecker struct {
+	binaryPath string
+	compiler   string
+}
+
+func (e problemChecker) Name() string {
+	return "checker"
+}
+
+func (e problemChecker) Kind() problems.ProblemExecutableKind {
+	return problems.TestlibChecker
+}
+
+func (e problemChecker) OpenBinary() (*os.File, error) {
+	return os.Open(e.binaryPath)
+}
+
+func (e problemChecker) GetCompiler(
+	ctx context.Context, compileCtx problems.CompileContext,
+) (compilers.Compiler, error) {
+	return compileCtx.GetCompiler(ctx, e.compiler)
+}
+
+// problemWithChecker represents problem with checker from package
+// replaced by uploaded checker.
+type problemWithChecker struct {
+	problems.Problem
+	checker problems.ProblemExecutable
+}
+
+func (p problemWithChecker) GetExecutables() ([]problems.ProblemExecutable, error) {
+	executables, err := p.Problem.GetExecutables()
+	if err != nil {
+		return nil, err
+	}
+	result := []problems.ProblemExecutable{p.checker}
+	for _, executable := range executables {
+		if executable.Kind() != problems.TestlibChecker {
+			result = append(result, executable)
+		}
+	}
+	return result, nil
+}
+
+// withChecker returns problem with replaced checker.
+func withChecker(
+	problem problems.Problem, checker problems.ProblemExecutable,
+) problems.Problem {
+	if checker == nil {
+		return problem
+	}
+	return problemWithChecker{Problem: problem, checker: checker}
+}
+
+// downloadProblemChecker downloads the latest compiled checker of
+// problem to dir.
+//
+// If problem does not have uploaded checkers, nil is returned.
+func downloadProblemChecker(
+	ctx context.Context, invoker *Invoker, problemID int64, dir string,
+) (problems.ProblemExecutable, error) {
+	syncCtx := models.WithSync(ctx)
+	checker, err := invoker.core.ProblemCheckers.GetCompiledByProblem(
+		syncCtx, problemID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	compiler, err := invoker.core.Compilers.Get(syncCtx, checker.CompilerID)
+	if err != nil {
+		return nil, err
+	}
+	result := problemChecker{
+		binaryPath: filepath.Join(dir, "checker.bin"),
+		compiler:   compiler.Name,
+	}
+	if err := downloadFile(
+		ctx, invoker, int64(checker.BinaryID), result.binaryPath,
+	); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package invoker
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/problems"
+)
+
+func init() {
+	registerTaskImpl(models.RetestSolutionTask, &retestSolutionTask{})
+}
+
+// retestOutputMaxSize contains maximal size of solution output in
+// task state, larger outputs are truncated.
+const retestOutputMaxSize = 64 * 1024
+
+// retestSolutionTask runs solution on single test.
+//
+// Task reuses preparation of solution from judge task, but stores
+// result of test in task state.
+type retestSolutionTask struct {
+	judgeSolutionTask
+	retestConfig models.RetestSolutionTaskConfig
+}
+
+func (retestSolutionTask) New(invoker *Invoker) taskImpl {
+	return &retestSolutionTask{
+		judgeSolutionTask: judgeSolutionTask{invoker: invoker},
+	}
+}
+
+func (t *retestSolutionTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.retestConfig); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	state := models.RetestSolutionTaskState{}
+	if err := t.withSolution(
+		ctx, t.retestConfig.SolutionID,
+		func(ctx TaskContext, compileCtx problems.CompileContext) error {
+			return t.retestImpl(ctx, compileCtx, &state)
+		},
+	); err != nil {
+		state.Stage = ""
+		state.Error = err.Error()
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// findTest returns test by its number in order of judgement.
+func (t *retestSolutionTask) findTest(
+	number int,
+) (problems.ProblemTestSet, problems.ProblemTest, error) {
+	testSets, err := t.problem.GetTestSets()
+	if err != nil {
+		return nil, nil, err
+	}
+	testNumber := 0
+	for _, testSet := range testSets {
+		tests, err := testSet.GetTests()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, test := range tests {
+			testNumber++
+			if testNumber == number {
+				return t.getTestSet(testSet), test, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("test %d does not exist", number)
+}
+
+func (t *retestSolutionTask) retestImpl(
+	ctx TaskContext, compileCtx problems.CompileContext,
+	state *models.RetestSolutionTaskState,
+) error {
+	testSet, test, err := t.findTest(t.retestConfig.Test)
+	if err != nil {
+		return err
+	}
+	if err := t.prepareSolution(ctx); err != nil {
+		return fmt.Errorf("cannot prepare solution: %w", err)
+	}
+	report := models.SolutionReport{}
+	if ok, err := t.compileSolution(ctx, &report); err != nil {
+		return fmt.Errorf("cannot compile solution: %w", err)
+	} else if !ok {
+		state.Stage = ""
+		state.Report = &models.TestReport{Verdict: models.CompilationError}
+		if report.Compiler != nil {
+			state.CompileLog = report.Compiler.Log
+		}
+		return ctx.SetDeferredState(state)
+	}
+	if err := t.prepareExecutables(ctx, compileCtx); err != nil {
+		return err
+	}
+	state.Stage = "testing"
+	if err := ctx.SetState(ctx, state); err != nil {
+		return err
+	}
+	run, err := t.executeSolutionTest(ctx, testSet, test)
+	if err != nil {
+		return fmt.Errorf("cannot run solution: %w", err)
+	}
+	run.Report.Group = test.Group()
+	run.Report.Sample = test.Sample()
+	output, err := readFilePrefix(run.OutputPath, retestOutputMaxSize)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	state.Output = output
+	state.CheckerLog = run.CheckerLog
+	if t.retestConfig.UpdateReport {
+		if err := t.updateSolutionReport(ctx, &run); err != nil {
+			return fmt.Errorf("cannot update report: %w", err)
+		}
+	}
+	state.Stage = ""
+	state.Report = &run.Report
+	return ctx.SetDeferredState(state)
+}
+
+// updateSolutionReport replaces report of test in solution report.
+func (t *retestSolutionTask) updateSolutionReport(
+	ctx TaskContext, run *solutionTestRun,
+) error {
+	solution, err := t.invoker.core.Solutions.Get(
+		models.WithSync(ctx), t.retestConfig.SolutionID,
+	)
+	if err != nil {
+		return err
+	}
+	report, err := solution.GetReport()
+	if err != nil {
+		return err
+	}
+	if report != nil && report.Pretests {
+		return fmt.Errorf("solution is judged on pretests")
+	}
+	index := t.retestConfig.Test - 1
+	if report == nil || index >= len(report.Tests) {
+		return fmt.Errorf("test %d is not judged", t.retestConfig.Test)
+	}
+	if report.Tests[index].Points == nil {
+		run.Report.Points = nil
+	}
+	if run.Report.Verdict != models.Accepted {
+		if err := t.uploadTestArtifacts(
+			ctx, &run.Report, run.OutputPath, run.CheckerLog,
+		); err != nil {
+			return err
+		}
+	}
+	report.Tests[index] = run.Report
+	if err := solution.SetReport(report); err != nil {
+		return err
+	}
+	return t.invoker.core.Solutions.Update(ctx, solution)
+}
@@ -0,0 +1,278 @@
+package invoker
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/compilers"
+)
+
+func init() {
+	registerTaskImpl(models.SelfTestInvokerTask, &selfTestInvokerTask{})
+}
+
+// selfTestSources contains known-good solutions of A+B problem
+// by language name.
+var selfTestSources = map[string]string{
+	"c++": `#include <iostream>
+
+int main() {
+    long long a, b;
+    std::cin >> a >> b;
+    std::cout << a + b << std::endl;
+    return 0;
+}
+`,
+	"c": `#include <stdio.h>
+
+int main() {
+    long long a, b;
+    scanf("%lld %lld", &a, &b);
+    printf("%lld\n", a + b);
+    return 0;
+}
+`,
+	"go": `package main
+
+import "fmt"
+
+func main() {
+	var a, b int64
+	fmt.Scan(&a, &b)
+	fmt.Println(a + b)
+}
+`,
+	"pascal": `var a, b: int64;
+begin
+    readln(a, b);
+    writeln(a + b);
+end.
+`,
+	"python": `a, b = map(int, input().split())
+print(a + b)
+`,
+}
+
+const (
+	defaultSelfTestTests   = 10
+	selfTestTimeLimit      = 2 * time.Second
+	selfTestMemoryLimit    = 256 * 1024 * 1024
+	selfTestCompileTimeout = 20 * time.Second
+)
+
+type selfTestInvokerTask struct {
+	invoker      *Invoker
+	config       models.SelfTestInvokerTaskConfig
+	tempDir      string
+	solutionImpl compilers.Executable
+}
+
+func (selfTestInvokerTask) New(invoker *Invoker) taskImpl {
+	return &selfTestInvokerTask{invoker: invoker}
+}
+
+func (t *selfTestInvokerTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if t.solutionImpl != nil {
+			t.solutionImpl.Release()
+		}
+	}()
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.tempDir = tempDir
+	state := models.SelfTestInvokerTaskState{Invoker: t.invoker.getName()}
+	if err := t.executeImpl(ctx, &state); err != nil {
+		state.Stage = ""
+		state.Error = err.Error()
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// getSelfTestSource returns solution of A+B problem for compiler.
+func getSelfTestSource(
+	config models.SelfTestInvokerTaskConfig, compiler models.Compiler,
+) (string, error) {
+	if config.Source != "" {
+		return config.Source, nil
+	}
+	compilerConfig, err := compiler.GetConfig()
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(strings.ToLower(compilerConfig.Language))
+	if len(fields) > 0 {
+		if source, ok := selfTestSources[fields[0]]; ok {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("no built-in solution for language %q", compilerConfig.Language)
+}
+
+func (t *selfTestInvokerTask) compileSolution(
+	ctx TaskContext, compileCtx CompileContext, state *models.SelfTestInvokerTaskState,
+) error {
+	compiler, err := t.invoker.core.Compilers.Get(models.WithSync(ctx), t.config.CompilerID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch compiler: %w", err)
+	}
+	source, err := getSelfTestSource(t.config, compiler)
+	if err != nil {
+		return err
+	}
+	compilerImpl, err := compileCtx.GetCompilerByID(ctx, compiler.ID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch compiler: %w", err)
+	}
+	sourcePath := filepath.Join(t.tempDir, "solution.txt")
+	if err := os.WriteFile(sourcePath, []byte(source), fs.ModePerm); err != nil {
+		return fmt.Errorf("cannot write solution: %w", err)
+	}
+	binaryPath := filepath.Join(t.tempDir, "solution")
+	begin := time.Now()
+	report, err := compilerImpl.Compile(ctx, compilers.CompileOptions{
+		Source:      sourcePath,
+		Target:      binaryPath,
+		TimeLimit:   selfTestCompileTimeout,
+		MemoryLimit: selfTestMemoryLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot compile solution: %w", err)
+	}
+	if !report.Success() {
+		return fmt.Errorf("cannot compile solution: %s", report.Log)
+	}
+	state.CompileTime = time.Since(begin).Milliseconds()
+	t.solutionImpl, err = compilerImpl.CreateExecutable(ctx, binaryPath)
+	return err
+}
+
+func (t *selfTestInvokerTask) executeImpl(
+	ctx TaskContext, state *models.SelfTestInvokerTaskState,
+) error {
+	state.Stage = "compiling"
+	if err := ctx.SetState(ctx, state); err != nil {
+		return err
+	}
+	compileCtx := &compileContext{
+		compilers: t.invoker.core.Compilers,
+		cache:     t.invoker.compilerImages,
+		logger:    ctx.Logger(),
+	}
+	defer compileCtx.Release()
+	if err := t.compileSolution(ctx, compileCtx, state); err != nil {
+		return err
+	}
+	state.Stage = "testing"
+	if err := ctx.SetState(ctx, state); err != nil {
+		return err
+	}
+	tests := t.config.Tests
+	if tests <= 0 {
+		tests = defaultSelfTestTests
+	}
+	var totalTime time.Duration
+	for test := 1; test <= tests; test++ {
+		usedTime, usedMemory, err := t.runTest(ctx, test)
+		if err != nil {
+			return fmt.Errorf("test %d: %w", test, err)
+		}
+		ms := usedTime.Milliseconds()
+		if test == 1 || ms < state.MinTime {
+			state.MinTime = ms
+		}
+		state.MaxTime = max(state.MaxTime, ms)
+		state.MaxMemory = max(state.MaxMemory, usedMemory)
+		totalTime += usedTime
+		state.Tests = test
+	}
+	state.AvgTime = totalTime.Milliseconds() / int64(tests)
+	if err := t.calibrate(ctx, totalTime.Microseconds()/int64(tests), state); err != nil {
+		return fmt.Errorf("cannot calibrate invoker: %w", err)
+	}
+	state.Stage = ""
+	return ctx.SetDeferredState(state)
+}
+
+// calibrate updates reference time or time factor of invoker using
+// average run time of solution in microseconds.
+func (t *selfTestInvokerTask) calibrate(
+	ctx TaskContext, runTime int64, state *models.SelfTestInvokerTaskState,
+) error {
+	name := timeFactorSettingPrefix + t.invoker.getName()
+	if t.config.Reference {
+		if err := t.invoker.setSetting(ctx, referenceTimeSetting, max(runTime, 1)); err != nil {
+			return err
+		}
+		state.TimeFactor = defaultTimeFactor
+		return t.invoker.setSetting(ctx, name, state.TimeFactor)
+	}
+	if !t.config.Calibrate {
+		return nil
+	}
+	referenceTime, err := t.invoker.core.Settings.GetInt64(referenceTimeSetting)
+	if err != nil {
+		return err
+	}
+	if referenceTime.Empty {
+		return fmt.Errorf("reference time is not configured")
+	}
+	state.TimeFactor = getTimeFactorByRunTime(runTime, referenceTime.Value)
+	return t.invoker.setSetting(ctx, name, state.TimeFactor)
+}
+
+// runTest runs solution on generated test and checks its answer.
+func (t *selfTestInvokerTask) runTest(
+	ctx TaskContext, test int,
+) (time.Duration, int64, error) {
+	inputPath := filepath.Join(t.tempDir, "test.in")
+	outputPath := filepath.Join(t.tempDir, "test.out")
+	rnd := rand.New(rand.NewSource(int64(test)))
+	a := rnd.Int63n(2_000_000_001) - 1_000_000_000
+	b := rnd.Int63n(2_000_000_001) - 1_000_000_000
+	if err := os.WriteFile(
+		inputPath, []byte(fmt.Sprintf("%d %d\n", a, b)), fs.ModePerm,
+	); err != nil {
+		return 0, 0, err
+	}
+	report, err := runStressProgram(
+		ctx, t.solutionImpl, nil, inputPath, outputPath,
+		selfTestTimeLimit, selfTestMemoryLimit,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot run solution: %w", err)
+	}
+	if report.Time > selfTestTimeLimit {
+		return 0, 0, fmt.Errorf("time limit exceeded: %s", report.Time)
+	}
+	if report.Memory > selfTestMemoryLimit {
+		return 0, 0, fmt.Errorf("memory limit exceeded: %d", report.Memory)
+	}
+	if report.ExitCode != 0 {
+		return 0, 0, fmt.Errorf("solution exited with code %d", report.ExitCode)
+	}
+	output, err := readFilePrefix(outputPath, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if strings.TrimSpace(output) != strconv.FormatInt(a+b, 10) {
+		return 0, 0, fmt.Errorf("wrong answer: expected %d, got %q", a+b, output)
+	}
+	return report.Time, report.Memory, nil
+}
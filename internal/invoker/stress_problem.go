@@ -0,0 +1,336 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/compilers"
+	"github.com/udovin/solve/internal/pkg/problems"
+	"github.com/udovin/solve/internal/pkg/safeexec"
+	"github.com/udovin/solve/internal/pkg/utils"
+)
+
+func init() {
+	registerTaskImpl(models.StressProblemTask, &stressProblemTask{})
+}
+
+// stressDataMaxSize contains maximal size of test data stored in
+// mismatch report.
+const stressDataMaxSize = 2048
+
+type stressProblemTask struct {
+	invoker       *Invoker
+	config        models.StressProblemTaskConfig
+	tempDir       string
+	problem       problems.Problem
+	generatorImpl compilers.Executable
+	modelImpl     compilers.Executable
+	solutionImpl  compilers.Executable
+	checkerImpl   compilers.Executable
+	progressTime  time.Time
+}
+
+func (stressProblemTask) New(invoker *Invoker) taskImpl {
+	return &stressProblemTask{invoker: invoker}
+}
+
+func (t *stressProblemTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	syncCtx := models.WithSync(ctx)
+	problem, err := t.invoker.core.Problems.Get(syncCtx, t.config.ProblemID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch problem: %w", err)
+	}
+	if problem.CompiledID == 0 {
+		return fmt.Errorf("problem is not compiled")
+	}
+	problemPackage, err := t.invoker.problemPackages.LoadSync(ctx, int64(problem.CompiledID), problems.CompiledProblem)
+	if err != nil {
+		return fmt.Errorf("unable to fetch package: %w", err)
+	}
+	defer problemPackage.Release()
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, impl := range []compilers.Executable{
+			t.generatorImpl, t.modelImpl, t.solutionImpl, t.checkerImpl,
+		} {
+			if impl != nil {
+				impl.Release()
+			}
+		}
+	}()
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	checker, err := downloadProblemChecker(ctx, t.invoker, problem.ID, tempDir)
+	if err != nil {
+		return fmt.Errorf("unable to fetch checker: %w", err)
+	}
+	t.tempDir = tempDir
+	t.problem = withChecker(problemPackage.Get(), checker)
+	state := models.StressProblemTaskState{}
+	if err := t.executeImpl(ctx, &state); err != nil {
+		state.Stage = ""
+		state.Error = err.Error()
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (t *stressProblemTask) newCompileContext(ctx TaskContext) CompileContext {
+	return &compileContext{
+		compilers: t.invoker.core.Compilers,
+		cache:     t.invoker.compilerImages,
+		logger:    ctx.Logger(),
+	}
+}
+
+// setProgress updates state of task with stress-testing progress.
+func (t *stressProblemTask) setProgress(
+	ctx TaskContext, state models.StressProblemTaskState,
+) error {
+	if time.Since(t.progressTime) < progressInterval {
+		return ctx.SetDeferredState(state)
+	}
+	t.progressTime = time.Now()
+	return ctx.SetState(ctx, state)
+}
+
+func (t *stressProblemTask) compileSource(
+	ctx TaskContext, compileCtx CompileContext,
+	source models.StressSource, name string,
+) (compilers.Executable, error) {
+	compiler, err := compileCtx.GetCompilerByID(ctx, source.CompilerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch compiler of %s: %w", name, err)
+	}
+	sourcePath := filepath.Join(t.tempDir, name+".txt")
+	if err := os.WriteFile(sourcePath, []byte(source.Content), fs.ModePerm); err != nil {
+		return nil, fmt.Errorf("cannot write %s: %w", name, err)
+	}
+	binaryPath := filepath.Join(t.tempDir, name)
+	report, err := compiler.Compile(ctx, compilers.CompileOptions{
+		Source:      sourcePath,
+		Target:      binaryPath,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile %s: %w", name, err)
+	}
+	if !report.Success() {
+		return nil, fmt.Errorf("cannot compile %s: %s", name, report.Log)
+	}
+	return compiler.CreateExecutable(ctx, binaryPath)
+}
+
+func (t *stressProblemTask) prepareExecutables(
+	ctx TaskContext, compileCtx CompileContext,
+) error {
+	executables, err := t.problem.GetExecutables()
+	if err != nil {
+		return fmt.Errorf("cannot get executables: %w", err)
+	}
+	if findProblemExecutable(executables, problems.TestlibInteractor) != nil {
+		return fmt.Errorf("interactive problems are not supported")
+	}
+	checker := findProblemExecutable(executables, problems.TestlibChecker)
+	if checker == nil {
+		return errNoChecker
+	}
+	t.checkerImpl, err = createProblemExecutable(
+		ctx, compileCtx, checker, filepath.Join(t.tempDir, "checker"),
+	)
+	if err != nil {
+		return err
+	}
+	t.generatorImpl, err = t.compileSource(ctx, compileCtx, t.config.Generator, "generator")
+	if err != nil {
+		return err
+	}
+	t.modelImpl, err = t.compileSource(ctx, compileCtx, t.config.ModelSolution, "model solution")
+	if err != nil {
+		return err
+	}
+	t.solutionImpl, err = t.compileSource(ctx, compileCtx, t.config.Solution, "solution")
+	return err
+}
+
+func (t *stressProblemTask) executeImpl(
+	ctx TaskContext, state *models.StressProblemTaskState,
+) error {
+	state.Stage = "compiling"
+	if err := t.setProgress(ctx, *state); err != nil {
+		return err
+	}
+	compileCtx := t.newCompileContext(ctx)
+	defer compileCtx.Release()
+	if err := t.prepareExecutables(ctx, compileCtx); err != nil {
+		return err
+	}
+	testSets, err := t.problem.GetTestSets()
+	if err != nil {
+		return fmt.Errorf("cannot get test sets: %w", err)
+	}
+	if len(testSets) == 0 {
+		return fmt.Errorf("problem does not have test sets")
+	}
+	// Limits of main test set are used for all tests.
+	testSet := testSets[0]
+	state.Stage = "testing"
+	for test := 1; test <= t.config.Tests; test++ {
+		state.Test = test
+		if err := t.setProgress(ctx, *state); err != nil {
+			return err
+		}
+		mismatch, err := t.runTest(ctx, testSet, test)
+		if err != nil {
+			return fmt.Errorf("cannot run test %d: %w", test, err)
+		}
+		if mismatch != nil {
+			state.Mismatch = mismatch
+			break
+		}
+	}
+	state.Stage = ""
+	return ctx.SetDeferredState(state)
+}
+
+func (t *stressProblemTask) runTest(
+	ctx context.Context, testSet problems.ProblemTestSet, test int,
+) (*models.StressMismatch, error) {
+	inputPath := filepath.Join(t.tempDir, "test.in")
+	outputPath := filepath.Join(t.tempDir, "test.out")
+	answerPath := filepath.Join(t.tempDir, "test.ans")
+	timeLimit := time.Duration(testSet.TimeLimit()) * time.Millisecond
+	memoryLimit := testSet.MemoryLimit()
+	if report, err := runStressProgram(
+		ctx, t.generatorImpl, []string{strconv.Itoa(test)}, "", inputPath,
+		20*time.Second, 256*1024*1024,
+	); err != nil {
+		return nil, fmt.Errorf("cannot run generator: %w", err)
+	} else if report.ExitCode != 0 {
+		return nil, fmt.Errorf("generator exited with code %d", report.ExitCode)
+	}
+	if report, err := runStressProgram(
+		ctx, t.modelImpl, nil, inputPath, answerPath, timeLimit, memoryLimit,
+	); err != nil {
+		return nil, fmt.Errorf("cannot run model solution: %w", err)
+	} else if verdict := getExecuteVerdict(report, testSet); verdict != models.Accepted {
+		return nil, fmt.Errorf("model solution failed with verdict %s", verdict)
+	}
+	report, err := runStressProgram(
+		ctx, t.solutionImpl, nil, inputPath, outputPath, timeLimit, memoryLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot run solution: %w", err)
+	}
+	mismatch := models.StressMismatch{
+		Test:    test,
+		Verdict: getExecuteVerdict(report, testSet),
+	}
+	if mismatch.Verdict == models.Accepted {
+		checkerReport, err := runTestlibChecker(
+			ctx, t.checkerImpl, inputPath, outputPath, answerPath, io.Discard,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if checkerReport.Verdict == models.Failed {
+			return nil, fmt.Errorf("checker failed: %s", checkerReport.Checker.Log)
+		}
+		if checkerReport.Verdict == models.Accepted {
+			return nil, nil
+		}
+		mismatch.Verdict = checkerReport.Verdict
+		mismatch.CheckerLog = checkerReport.Checker.Log
+	}
+	if mismatch.Input, err = readFilePrefix(inputPath, stressDataMaxSize); err != nil {
+		return nil, err
+	}
+	if mismatch.Answer, err = readFilePrefix(answerPath, stressDataMaxSize); err != nil {
+		return nil, err
+	}
+	if mismatch.Output, err = readFilePrefix(outputPath, stressDataMaxSize); err != nil {
+		return nil, err
+	}
+	return &mismatch, nil
+}
+
+// runStressProgram runs program with specified input and output files.
+//
+// Empty inputPath means that program does not read stdin.
+func runStressProgram(
+	ctx context.Context, exe compilers.Executable, args []string,
+	inputPath, outputPath string,
+	timeLimit time.Duration, memoryLimit int64,
+) (safeexec.Report, error) {
+	options := compilers.ExecuteOptions{
+		Args:        args,
+		TimeLimit:   timeLimit,
+		MemoryLimit: memoryLimit,
+	}
+	if inputPath != "" {
+		inputFile, err := os.Open(inputPath)
+		if err != nil {
+			return safeexec.Report{}, err
+		}
+		defer func() { _ = inputFile.Close() }()
+		options.Stdin = inputFile
+	}
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return safeexec.Report{}, err
+	}
+	defer func() { _ = outputFile.Close() }()
+	options.Stdout = outputFile
+	process, err := exe.CreateProcess(ctx, options)
+	if err != nil {
+		return safeexec.Report{}, err
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		return safeexec.Report{}, err
+	}
+	return process.Wait()
+}
+
+// getExecuteVerdict returns verdict of program execution.
+func getExecuteVerdict(
+	report safeexec.Report, testSet problems.ProblemTestSet,
+) models.Verdict {
+	if report.Time.Milliseconds() > testSet.TimeLimit() {
+		return models.TimeLimitExceeded
+	} else if report.Memory > testSet.MemoryLimit() {
+		return models.MemoryLimitExceeded
+	} else if report.ExitCode != 0 {
+		return models.RuntimeError
+	}
+	return models.Accepted
+}
+
+func readFilePrefix(path string, limit int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	buffer := utils.NewTruncateBuffer(limit)
+	if _, err := io.Copy(buffer, file); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,93 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+)
+
+func init() {
+	registerTaskImpl(models.SystemTestContestTask, &systemTestContestTask{})
+}
+
+// systemTestContestTask enqueues judgement of contest solutions that
+// were judged only on pretests.
+//
+// Preliminary reports are kept until solutions are judged on full
+// tests, so standings are updated gradually.
+type systemTestContestTask struct {
+	invoker *Invoker
+	config  models.SystemTestContestTaskConfig
+}
+
+func (systemTestContestTask) New(invoker *Invoker) taskImpl {
+	return &systemTestContestTask{invoker: invoker}
+}
+
+func (t *systemTestContestTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	state := models.SystemTestContestTaskState{}
+	if err := t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		tasks, err := t.rejudgeSolutions(ctx)
+		if err != nil {
+			return err
+		}
+		state.RejudgeTasks = tasks
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		state.Error = err.Error()
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	return ctx.SetDeferredState(&state)
+}
+
+// rejudgeSolutions enqueues judge tasks for contest solutions that
+// are judged on pretests and returns IDs of created tasks.
+func (t *systemTestContestTask) rejudgeSolutions(ctx context.Context) ([]int64, error) {
+	syncCtx := models.WithSync(ctx)
+	if _, err := t.invoker.core.Contests.Get(syncCtx, t.config.ContestID); err != nil {
+		return nil, fmt.Errorf("unable to fetch contest: %w", err)
+	}
+	if err := t.invoker.core.Solutions.Sync(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := t.invoker.core.ContestSolutions.FindByContest(syncCtx, t.config.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	contestSolutions, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []int64
+	for _, contestSolution := range contestSolutions {
+		solution, err := t.invoker.core.Solutions.Get(ctx, contestSolution.ID)
+		if err != nil {
+			return nil, err
+		}
+		report, err := solution.GetReport()
+		if err != nil || report == nil || !report.Pretests {
+			continue
+		}
+		task := models.Task{}
+		if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+			SolutionID:    solution.ID,
+			EnablePoints:  report.Points != nil,
+			ParticipantID: contestSolution.ParticipantID,
+		}); err != nil {
+			return nil, err
+		}
+		if err := t.invoker.core.Tasks.Create(ctx, &task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task.ID)
+	}
+	return tasks, nil
+}
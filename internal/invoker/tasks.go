@@ -3,6 +3,7 @@ package invoker
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
@@ -29,6 +30,26 @@ func isSupportedTask(kind models.TaskKind) bool {
 	return ok
 }
 
+// taskPanicError represents error of panicked task.
+type taskPanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *taskPanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// executeTask executes task and converts panic into error.
+func executeTask(ctx TaskContext, impl taskImpl) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &taskPanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return impl.Execute(ctx)
+}
+
 type TaskContext interface {
 	context.Context
 	Kind() models.TaskKind
@@ -44,10 +65,25 @@ type TaskContext interface {
 
 var popTaskMutex sync.Mutex
 
-func popQueuedTask(ctx context.Context, store *models.TaskStore) (*taskGuard, error) {
+// defaultParticipantTasksLimit represents default limit of running judge
+// tasks for single contest participant.
+const defaultParticipantTasksLimit = 2
+
+// popQueuedTask pops queued task that is supported by invoker.
+//
+// Amount of running judge tasks of single contest participant is limited
+// by "invoker.participant_tasks_limit" setting.
+func popQueuedTask(
+	ctx context.Context, store *models.TaskStore, settings *models.SettingStore,
+	filter func(models.Task) bool,
+) (*taskGuard, error) {
 	popTaskMutex.Lock()
 	defer popTaskMutex.Unlock()
-	task, err := store.PopQueued(ctx, pingDuration, isSupportedTask)
+	limit := int64(defaultParticipantTasksLimit)
+	if value, err := settings.GetInt64("invoker.participant_tasks_limit"); err == nil {
+		limit = value.OrElse(limit)
+	}
+	task, err := store.PopQueued(ctx, pingDuration, int(max(limit, 0)), filter)
 	if err != nil {
 		return nil, err
 	}
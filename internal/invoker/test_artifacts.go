@@ -0,0 +1,79 @@
+package invoker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+)
+
+// testArtifactMaxSize contains maximal size of test artifact before
+// compression, larger artifacts are truncated.
+const testArtifactMaxSize = 1024 * 1024
+
+// uploadTestArtifacts uploads solution output and checker log of
+// failed test and links them from test report.
+func (t *judgeSolutionTask) uploadTestArtifacts(
+	ctx TaskContext, report *models.TestReport,
+	outputPath string, checkerLog string,
+) error {
+	if t.invoker.files == nil {
+		return nil
+	}
+	artifacts := models.TestArtifacts{}
+	if file, err := os.Open(outputPath); err == nil {
+		defer func() { _ = file.Close() }()
+		id, err := t.uploadTestArtifact(ctx, "output.out.gz", file)
+		if err != nil {
+			return fmt.Errorf("cannot upload output: %w", err)
+		}
+		artifacts.OutputID = id
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if checkerLog != "" {
+		id, err := t.uploadTestArtifact(
+			ctx, "checker.log.gz", strings.NewReader(checkerLog),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot upload checker log: %w", err)
+		}
+		artifacts.CheckerLogID = id
+	}
+	if artifacts != (models.TestArtifacts{}) {
+		report.Artifacts = &artifacts
+	}
+	return nil
+}
+
+func (t *judgeSolutionTask) uploadTestArtifact(
+	ctx TaskContext, name string, content io.Reader,
+) (int64, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := io.Copy(
+		writer, io.LimitReader(content, testArtifactMaxSize),
+	); err != nil {
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+	file, err := t.invoker.files.UploadFile(ctx, &managers.FileReader{
+		Name:   name,
+		Size:   int64(buffer.Len()),
+		Reader: bytes.NewReader(buffer.Bytes()),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := t.invoker.files.ConfirmUploadFile(ctx, &file); err != nil {
+		return 0, err
+	}
+	return file.ID, nil
+}
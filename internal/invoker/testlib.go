@@ -3,6 +3,8 @@ package invoker
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/udovin/solve/internal/models"
@@ -30,11 +32,14 @@ func getTestlibExitCodeVerdict(exitCode int) (models.Verdict, error) {
 	}
 }
 
-func runTestlibChecker(ctx context.Context, checker compilers.Executable, inputPath, outputPath, answerPath string) (models.TestReport, error) {
+// runTestlibChecker runs checker for solution output.
+//
+// Full log of checker is additionally written to fullLog.
+func runTestlibChecker(ctx context.Context, checker compilers.Executable, inputPath, outputPath, answerPath string, fullLog io.Writer) (models.TestReport, error) {
 	log := utils.NewTruncateBuffer(2048)
 	process, err := checker.CreateProcess(ctx, compilers.ExecuteOptions{
 		Args:        []string{"input.in", "output.out", "answer.ans"},
-		Stderr:      log,
+		Stderr:      io.MultiWriter(log, fullLog),
 		TimeLimit:   20 * time.Second,
 		MemoryLimit: 256 * 1024 * 1024,
 	})
@@ -73,3 +78,36 @@ func runTestlibChecker(ctx context.Context, checker compilers.Executable, inputP
 		},
 	}, nil
 }
+
+// runTestlibValidator runs validator for test input.
+//
+// Returns log of validator, if input is invalid.
+func runTestlibValidator(ctx context.Context, validator compilers.Executable, inputPath string) (bool, string, error) {
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return false, "", fmt.Errorf("cannot open validator input file: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	log := utils.NewTruncateBuffer(2048)
+	process, err := validator.CreateProcess(ctx, compilers.ExecuteOptions{
+		Stdin:       inputFile,
+		Stderr:      log,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("cannot create validator process: %w", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		return false, "", fmt.Errorf("cannot start validator: %w", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		return false, "", fmt.Errorf("cannot wait validator: %w", err)
+	}
+	if report.ExitCode != 0 {
+		return false, log.String(), nil
+	}
+	return true, "", nil
+}
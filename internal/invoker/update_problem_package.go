@@ -289,7 +289,8 @@ func (t *updateProblemPackageTask) executeImpl(ctx TaskContext) error {
 		event.FileID = models.NInt64(file.ID)
 		events[key] = event
 	}
-	return t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+	var rejudgeTasks []int64
+	if err := t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
 		for _, file := range files {
 			if err := t.invoker.files.ConfirmUploadFile(ctx, &file); err != nil {
 				return err
@@ -328,6 +329,113 @@ func (t *updateProblemPackageTask) executeImpl(ctx TaskContext) error {
 				)
 			}
 		}
-		return t.invoker.core.Problems.Update(ctx, t.problem)
-	}, sqlRepeatableRead)
+		if err := t.invoker.core.Problems.Update(ctx, t.problem); err != nil {
+			return err
+		}
+		if !t.config.Rejudge {
+			return nil
+		}
+		tasks, err := t.rejudgeSolutions(ctx)
+		if err != nil {
+			return err
+		}
+		rejudgeTasks = tasks
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	if len(rejudgeTasks) > 0 {
+		state := models.UpdateProblemPackageTaskState{
+			RejudgeTasks: rejudgeTasks,
+		}
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+	}
+	return nil
+}
+
+// rejudgeVerdicts contains verdicts that can be changed after update
+// of checker or tests.
+var rejudgeVerdicts = map[models.Verdict]struct{}{
+	models.Accepted:          {},
+	models.WrongAnswer:       {},
+	models.PartiallyAccepted: {},
+}
+
+// rejudgeSolutions enqueues judge tasks for problem solutions which
+// verdict can be changed and returns IDs of created tasks.
+func (t *updateProblemPackageTask) rejudgeSolutions(ctx context.Context) ([]int64, error) {
+	return rejudgeProblemSolutions(ctx, t.invoker, t.problem.ID)
+}
+
+// rejudgeProblemSolutions enqueues judge tasks for solutions of problem
+// which verdict can be changed.
+func rejudgeProblemSolutions(
+	ctx context.Context, invoker *Invoker, problemID int64,
+) ([]int64, error) {
+	rows, err := invoker.core.Solutions.FindByProblem(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	solutions, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []int64
+	for _, solution := range solutions {
+		report, err := solution.GetReport()
+		if err != nil || report == nil {
+			continue
+		}
+		if _, ok := rejudgeVerdicts[report.Verdict]; !ok {
+			continue
+		}
+		config := models.JudgeSolutionTaskConfig{
+			SolutionID:   solution.ID,
+			EnablePoints: report.Points != nil,
+		}
+		if invoker.core.ContestSolutions != nil {
+			if contestSolution, err := invoker.core.ContestSolutions.Get(
+				ctx, solution.ID,
+			); err == nil {
+				// Results of finalized contests should not be changed.
+				if isFinalizedContest(ctx, invoker, contestSolution.ContestID) {
+					continue
+				}
+				config.ParticipantID = contestSolution.ParticipantID
+			}
+		}
+		if err := solution.SetReport(nil); err != nil {
+			return nil, err
+		}
+		if err := invoker.core.Solutions.Update(ctx, solution); err != nil {
+			return nil, err
+		}
+		task := models.Task{}
+		if err := task.SetConfig(config); err != nil {
+			return nil, err
+		}
+		if err := invoker.core.Tasks.Create(ctx, &task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task.ID)
+	}
+	return tasks, nil
+}
+
+// isFinalizedContest returns true if results of contest are finalized.
+func isFinalizedContest(ctx context.Context, invoker *Invoker, contestID int64) bool {
+	if invoker.core.Contests == nil {
+		return false
+	}
+	contest, err := invoker.core.Contests.Get(ctx, contestID)
+	if err != nil {
+		return false
+	}
+	config, err := contest.GetConfig()
+	if err != nil {
+		return false
+	}
+	return config.FinalizeTime != 0
 }
@@ -0,0 +1,268 @@
+package invoker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+type testRejudgeEnv struct {
+	account  models.Account
+	compiler models.Compiler
+	problem  models.Problem
+}
+
+func newTestRejudgeEnv(tb testing.TB) testRejudgeEnv {
+	ctx := context.Background()
+	var e testRejudgeEnv
+	e.account = models.Account{Kind: models.UserAccountKind}
+	if err := testInvoker.core.Accounts.Create(ctx, &e.account); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	image := models.File{Status: models.AvailableFile, Meta: models.JSON("{}")}
+	if err := testInvoker.core.Files.Create(ctx, &image); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	e.compiler = models.Compiler{
+		Name:    "test",
+		OwnerID: models.NInt64(e.account.ID),
+		Config:  models.JSON("{}"),
+		ImageID: image.ID,
+	}
+	if err := testInvoker.core.Compilers.Create(ctx, &e.compiler); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	e.problem = models.Problem{Title: "Test problem", OwnerID: models.NInt64(e.account.ID)}
+	if err := testInvoker.core.Problems.Create(ctx, &e.problem); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	return e
+}
+
+// CreateContestSolution creates accepted solution in contest with
+// specified finalize time.
+func (e testRejudgeEnv) CreateContestSolution(
+	tb testing.TB, finalizeTime int64,
+) models.Solution {
+	ctx := context.Background()
+	contest := models.Contest{Title: "Test contest"}
+	if err := contest.SetConfig(models.ContestConfig{
+		FinalizeTime: models.NInt64(finalizeTime),
+	}); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := testInvoker.core.Contests.Create(ctx, &contest); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID, ProblemID: e.problem.ID, Code: "A",
+	}
+	if err := testInvoker.core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	participant := models.ContestParticipant{
+		ContestID: contest.ID, AccountID: e.account.ID,
+		Kind: models.RegularParticipant,
+	}
+	if err := testInvoker.core.ContestParticipants.Create(ctx, &participant); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		Kind:       models.ContestSolutionKind,
+		ProblemID:  e.problem.ID,
+		CompilerID: e.compiler.ID,
+		AuthorID:   e.account.ID,
+	}
+	if err := solution.SetReport(&models.SolutionReport{Verdict: models.Accepted}); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := testInvoker.core.Solutions.Create(ctx, &solution); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     contest.ID,
+		ParticipantID: participant.ID,
+		ProblemID:     contestProblem.ID,
+	}
+	contestSolution.ID = solution.ID
+	if err := testInvoker.core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	return solution
+}
+
+// Sync syncs stores that are used for rejudge of solutions.
+func (e testRejudgeEnv) Sync(tb testing.TB) {
+	ctx := context.Background()
+	for _, store := range []models.CachedStore{
+		testInvoker.core.Contests,
+		testInvoker.core.ContestSolutions,
+		testInvoker.core.Solutions,
+	} {
+		if err := store.Sync(ctx); err != nil {
+			tb.Fatal("Error:", err)
+		}
+	}
+}
+
+// CheckRejudged checks that only specified solutions are rejudged.
+func (e testRejudgeEnv) CheckRejudged(
+	tb testing.TB, tasks []int64, solutions ...models.Solution,
+) {
+	ctx := models.WithSync(context.Background())
+	rejudged := map[int64]struct{}{}
+	for _, id := range tasks {
+		task, err := testInvoker.core.Tasks.Get(ctx, id)
+		if err != nil {
+			tb.Fatal("Error:", err)
+		}
+		var config models.JudgeSolutionTaskConfig
+		if err := task.ScanConfig(&config); err != nil {
+			tb.Fatal("Error:", err)
+		}
+		rejudged[config.SolutionID] = struct{}{}
+	}
+	if len(rejudged) != len(solutions) {
+		tb.Fatalf("Expected %d rejudged solutions, got %d", len(solutions), len(rejudged))
+	}
+	for _, solution := range solutions {
+		if _, ok := rejudged[solution.ID]; !ok {
+			tb.Fatalf("Expected rejudge of solution %d", solution.ID)
+		}
+	}
+}
+
+func TestUpdateProblemPackageTask_RejudgeSolutions(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	e := newTestRejudgeEnv(t)
+	points := 10.0
+	reports := []*models.SolutionReport{
+		{Verdict: models.Accepted},
+		{Verdict: models.WrongAnswer, Points: &points},
+		{Verdict: models.CompilationError},
+		{Verdict: models.TimeLimitExceeded},
+		nil,
+	}
+	for _, report := range reports {
+		solution := models.Solution{
+			ProblemID:  e.problem.ID,
+			CompilerID: e.compiler.ID,
+			AuthorID:   e.account.ID,
+		}
+		if err := solution.SetReport(report); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := testInvoker.core.Solutions.Create(ctx, &solution); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := testInvoker.core.Solutions.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	task := updateProblemPackageTask{invoker: testInvoker, problem: e.problem}
+	tasks, err := task.rejudgeSolutions(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected %d tasks, got %d", 2, len(tasks))
+	}
+	for i, id := range tasks {
+		judgeTask, err := testInvoker.core.Tasks.Get(models.WithSync(ctx), id)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		var config models.JudgeSolutionTaskConfig
+		if err := judgeTask.ScanConfig(&config); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if config.EnablePoints != (i == 1) {
+			t.Fatalf("Unexpected enable points: %v", config.EnablePoints)
+		}
+		solution, err := testInvoker.core.Solutions.Get(models.WithSync(ctx), config.SolutionID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if solution.Report != nil {
+			t.Fatalf("Expected empty report, got %s", solution.Report)
+		}
+	}
+}
+
+func TestUpdateProblemPackageTask_RejudgeFinalizedContest(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	e := newTestRejudgeEnv(t)
+	solution := e.CreateContestSolution(t, 0)
+	finalized := e.CreateContestSolution(t, time.Now().Unix())
+	e.Sync(t)
+	task := updateProblemPackageTask{invoker: testInvoker, problem: e.problem}
+	tasks, err := task.rejudgeSolutions(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.CheckRejudged(t, tasks, solution)
+	finalized, err = testInvoker.core.Solutions.Get(models.WithSync(ctx), finalized.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if report, err := finalized.GetReport(); err != nil {
+		t.Fatal("Error:", err)
+	} else if report == nil || report.Verdict != models.Accepted {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+}
+
+func TestValidateProblemTestTask_RejudgeFinalizedContest(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	e := newTestRejudgeEnv(t)
+	solution := e.CreateContestSolution(t, 0)
+	e.CreateContestSolution(t, time.Now().Unix())
+	input := models.File{Status: models.AvailableFile, Meta: models.JSON("{}")}
+	if err := testInvoker.core.Files.Create(ctx, &input); err != nil {
+		t.Fatal("Error:", err)
+	}
+	test := models.ProblemExtraTest{
+		ProblemID: e.problem.ID,
+		InputID:   input.ID,
+		AnswerID:  input.ID,
+		Status:    models.PendingProblemExtraTest,
+		AuthorID:  e.account.ID,
+	}
+	if err := testInvoker.core.ProblemExtraTests.Create(ctx, &test); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := testInvoker.core.ProblemExtraTests.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Sync(t)
+	task := models.Task{
+		Status:     models.RunningTask,
+		ExpireTime: models.NInt64(time.Now().Add(time.Minute).Unix()),
+	}
+	if err := testInvoker.core.Tasks.Create(ctx, &task); err != nil {
+		t.Fatal("Error:", err)
+	}
+	guard := &taskGuard{store: testInvoker.core.Tasks, task: task}
+	taskCtx := newTaskContext(ctx, guard, testInvoker.core.Logger())
+	validate := validateProblemTestTask{
+		invoker: testInvoker,
+		config: models.ValidateProblemTestTaskConfig{
+			ProblemID: e.problem.ID,
+			InputID:   input.ID,
+			TestID:    test.ID,
+		},
+	}
+	state := models.ValidateProblemTestTaskState{Valid: true}
+	if err := validate.updateExtraTest(taskCtx, &state); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.CheckRejudged(t, state.RejudgeTasks, solution)
+}
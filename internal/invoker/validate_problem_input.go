@@ -0,0 +1,149 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/problems"
+)
+
+func init() {
+	registerTaskImpl(models.ValidateProblemTestTask, &validateProblemTestTask{})
+}
+
+type validateProblemTestTask struct {
+	invoker *Invoker
+	config  models.ValidateProblemTestTaskConfig
+	tempDir string
+	problem problems.Problem
+}
+
+func (validateProblemTestTask) New(invoker *Invoker) taskImpl {
+	return &validateProblemTestTask{invoker: invoker}
+}
+
+func (t *validateProblemTestTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	syncCtx := models.WithSync(ctx)
+	problem, err := t.invoker.core.Problems.Get(syncCtx, t.config.ProblemID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch problem: %w", err)
+	}
+	if problem.CompiledID == 0 {
+		return fmt.Errorf("problem is not compiled")
+	}
+	problemPackage, err := t.invoker.problemPackages.LoadSync(ctx, int64(problem.CompiledID), problems.CompiledProblem)
+	if err != nil {
+		return fmt.Errorf("unable to fetch package: %w", err)
+	}
+	defer problemPackage.Release()
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.tempDir = tempDir
+	t.problem = problemPackage.Get()
+	state, err := t.executeImpl(ctx)
+	if err != nil {
+		state := models.ValidateProblemTestTaskState{
+			Error: err.Error(),
+		}
+		if err := ctx.SetDeferredState(&state); err != nil {
+			ctx.Logger().Error("Cannot set deferred state", err)
+		}
+		return err
+	}
+	if t.config.TestID != 0 {
+		if err := t.updateExtraTest(ctx, &state); err != nil {
+			return err
+		}
+	}
+	return ctx.SetDeferredState(&state)
+}
+
+// updateExtraTest applies result of validation to extra test.
+//
+// Solutions of problem are rejudged after activation of test.
+func (t *validateProblemTestTask) updateExtraTest(
+	ctx TaskContext, state *models.ValidateProblemTestTaskState,
+) error {
+	return t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		test, err := t.invoker.core.ProblemExtraTests.Get(
+			models.WithSync(ctx), t.config.TestID,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch extra test: %w", err)
+		}
+		if !state.Valid {
+			test.Status = models.InvalidProblemExtraTest
+			test.Message = state.Message
+			return t.invoker.core.ProblemExtraTests.Update(ctx, test)
+		}
+		test.Status = models.ActiveProblemExtraTest
+		if err := t.invoker.core.ProblemExtraTests.Update(ctx, test); err != nil {
+			return err
+		}
+		tasks, err := rejudgeProblemSolutions(ctx, t.invoker, test.ProblemID)
+		if err != nil {
+			return err
+		}
+		state.RejudgeTasks = tasks
+		return nil
+	}, sqlRepeatableRead)
+}
+
+func (t *validateProblemTestTask) executeImpl(
+	ctx TaskContext,
+) (models.ValidateProblemTestTaskState, error) {
+	inputPath := filepath.Join(t.tempDir, "test.in")
+	if err := downloadFile(ctx, t.invoker, t.config.InputID, inputPath); err != nil {
+		return models.ValidateProblemTestTaskState{}, fmt.Errorf("cannot download input: %w", err)
+	}
+	compileCtx := &compileContext{
+		compilers: t.invoker.core.Compilers,
+		cache:     t.invoker.compilerImages,
+		logger:    ctx.Logger(),
+	}
+	defer compileCtx.Release()
+	valid, message, err := validateProblemTest(
+		ctx, compileCtx, t.problem, inputPath, t.tempDir,
+	)
+	if err != nil {
+		return models.ValidateProblemTestTaskState{}, err
+	}
+	return models.ValidateProblemTestTaskState{
+		Valid:   valid,
+		Message: message,
+	}, nil
+}
+
+// validateProblemTest runs validator of problem against test input.
+//
+// Tests of problems without validator are considered as valid.
+func validateProblemTest(
+	ctx TaskContext, compileCtx problems.CompileContext,
+	problem problems.Problem, inputPath, tempDir string,
+) (bool, string, error) {
+	executables, err := problem.GetExecutables()
+	if err != nil {
+		return false, "", fmt.Errorf("cannot get executables: %w", err)
+	}
+	validator := findProblemExecutable(executables, problems.TestlibValidator)
+	if validator == nil {
+		return true, "", nil
+	}
+	validatorImpl, err := createProblemExecutable(
+		ctx, compileCtx, validator, filepath.Join(tempDir, "validator"),
+	)
+	if err != nil {
+		return false, "", fmt.Errorf("cannot prepare validator: %w", err)
+	}
+	defer func() { _ = validatorImpl.Release() }()
+	return runTestlibValidator(ctx, validatorImpl, inputPath)
+}
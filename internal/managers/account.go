@@ -4,6 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/udovin/solve/internal/core"
@@ -22,6 +26,7 @@ type AccountManager struct {
 	roleEdges    *models.RoleEdgeStore
 	accountRoles *models.AccountRoleStore
 	settings     *models.SettingStore
+	cache        permissionCache
 }
 
 func NewAccountManager(core *core.Core) *AccountManager {
@@ -36,9 +41,73 @@ func NewAccountManager(core *core.Core) *AccountManager {
 		roleEdges:    core.RoleEdges,
 		accountRoles: core.AccountRoles,
 		settings:     core.Settings,
+		cache: permissionCache{
+			entries: map[string]permissionEntry{},
+		},
 	}
 }
 
+// permissionCacheSize contains maximal amount of cached entries.
+const permissionCacheSize = 1024
+
+type permissionEntry struct {
+	Permissions      perms.PermissionSet
+	RolesVersion     int64
+	RoleEdgesVersion int64
+}
+
+// permissionCache contains permissions of role sets, so contexts of
+// accounts with the same roles do not require traversal of role graph
+// for every request.
+//
+// Entries are invalidated on every change of roles or role edges.
+// Changes of account roles produce different role sets, so they
+// do not require explicit invalidation.
+type permissionCache struct {
+	mutex   sync.Mutex
+	entries map[string]permissionEntry
+}
+
+func (c *permissionCache) Get(
+	key string, rolesVersion, roleEdgesVersion int64,
+) (perms.PermissionSet, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if entry.RolesVersion != rolesVersion ||
+		entry.RoleEdgesVersion != roleEdgesVersion {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.Permissions, true
+}
+
+func (c *permissionCache) Set(key string, entry permissionEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.entries) >= permissionCacheSize {
+		c.entries = map[string]permissionEntry{}
+	}
+	c.entries[key] = entry
+}
+
+func getRoleSetKey(roleIDs []int64) string {
+	ids := slices.Clone(roleIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	var key strings.Builder
+	for i, id := range ids {
+		if i > 0 {
+			key.WriteRune(',')
+		}
+		key.WriteString(strconv.FormatInt(id, 10))
+	}
+	return key.String()
+}
+
 func (m *AccountManager) MakeContext(ctx context.Context, account *models.Account) (*AccountContext, error) {
 	c := AccountContext{
 		context:     ctx,
@@ -95,6 +164,23 @@ func (m *AccountManager) MakeContext(ctx context.Context, account *models.Accoun
 					}
 					c.GroupMembers = append(c.GroupMembers, member)
 					c.GroupAccounts = append(c.GroupAccounts, groupAccount)
+					// Members of group inherit roles of group account.
+					if user.Status == models.BlockedUser {
+						continue
+					}
+					if err := func() error {
+						edges, err := m.accountRoles.FindByAccount(ctx, groupAccount.ID)
+						if err != nil {
+							return err
+						}
+						defer func() { _ = edges.Close() }()
+						for edges.Next() {
+							roleIDs = append(roleIDs, edges.Row().RoleID)
+						}
+						return edges.Err()
+					}(); err != nil {
+						return err
+					}
 				}
 				return members.Err()
 			}(); err != nil {
@@ -120,6 +206,21 @@ func (m *AccountManager) MakeContext(ctx context.Context, account *models.Accoun
 				return nil, err
 			}
 			roleIDs = append(roleIDs, role.ID)
+			// Scope users inherit roles of scope account.
+			if err := func() error {
+				edges, err := m.accountRoles.FindByAccount(ctx, scopeAccount.ID)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = edges.Close() }()
+				for edges.Next() {
+					edge := edges.Row()
+					roleIDs = append(roleIDs, edge.RoleID)
+				}
+				return edges.Err()
+			}(); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, fmt.Errorf("unknown account kind: %v", account.Kind)
 		}
@@ -130,7 +231,7 @@ func (m *AccountManager) MakeContext(ctx context.Context, account *models.Accoun
 		}
 		roleIDs = append(roleIDs, role.ID)
 	}
-	permissions, err := m.getRecursivePermissions(ctx, roleIDs)
+	permissions, err := m.getCachedPermissions(ctx, roleIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +239,34 @@ func (m *AccountManager) MakeContext(ctx context.Context, account *models.Accoun
 	return &c, nil
 }
 
+// getCachedPermissions returns permissions of roles using cache.
+//
+// Returned permissions can be modified by caller.
+func (m *AccountManager) getCachedPermissions(
+	ctx context.Context, roleIDs []int64,
+) (perms.PermissionSet, error) {
+	key := getRoleSetKey(roleIDs)
+	// Versions should be obtained before traversal of roles, so
+	// concurrent changes will invalidate cached entry.
+	rolesVersion := m.roles.GetVersion()
+	roleEdgesVersion := m.roleEdges.GetVersion()
+	if permissions, ok := m.cache.Get(
+		key, rolesVersion, roleEdgesVersion,
+	); ok {
+		return permissions.Clone(), nil
+	}
+	permissions, err := m.GetRecursivePermissions(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	m.cache.Set(key, permissionEntry{
+		Permissions:      permissions.Clone(),
+		RolesVersion:     rolesVersion,
+		RoleEdgesVersion: roleEdgesVersion,
+	})
+	return permissions, nil
+}
+
 func (m *AccountManager) getGuestRole(ctx context.Context) (models.Role, error) {
 	roleName := "guest_group"
 	roleNameSetting, err := m.settings.GetByKey("accounts.guest_role")
@@ -171,7 +300,9 @@ func (m *AccountManager) getScopeUserRole(ctx context.Context) (models.Role, err
 	return m.roles.GetByName(ctx, roleName)
 }
 
-func (m *AccountManager) getRecursivePermissions(ctx context.Context, roleIDs []int64) (perms.PermissionSet, error) {
+// GetRecursivePermissions returns built-in permissions of specified
+// roles and all their child roles.
+func (m *AccountManager) GetRecursivePermissions(ctx context.Context, roleIDs []int64) (perms.PermissionSet, error) {
 	roles := map[int64]struct{}{}
 	for _, id := range roleIDs {
 		roles[id] = struct{}{}
@@ -221,12 +352,36 @@ type AccountContext struct {
 	Permissions   perms.PermissionSet
 	GroupAccounts []models.Account
 	GroupMembers  []models.GroupMember
+	// ContestID contains contest to which permissions of account are
+	// restricted, when account is authorized with contest API token.
+	ContestID int64
 }
 
 func (c *AccountContext) HasPermission(name string) bool {
 	return c.Permissions.HasPermission(name)
 }
 
+// IsScopeMember returns true if account is user of specified scope.
+func (c *AccountContext) IsScopeMember(scopeID int64) bool {
+	return c.ScopeUser != nil && c.ScopeUser.ScopeID == scopeID
+}
+
+// IsScopeAdmin returns true if account can manage entities of scope.
+//
+// Only scope owner is an administrator of scope.
+func IsScopeAdmin(
+	ctx *AccountContext, scopes *models.ScopeStore, scopeID int64,
+) bool {
+	if ctx.Account == nil || scopeID == 0 {
+		return false
+	}
+	scope, err := scopes.Get(ctx, scopeID)
+	if err != nil {
+		return false
+	}
+	return scope.OwnerID != 0 && int64(scope.OwnerID) == ctx.Account.ID
+}
+
 func (c *AccountContext) Deadline() (time.Time, bool) {
 	return c.context.Deadline()
 }
@@ -0,0 +1,402 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+const (
+	// AnalyticsSolutionEventSetting contains key of setting with ID of
+	// next solution event that should be exported.
+	AnalyticsSolutionEventSetting = "analytics.solution_event_id"
+	// AnalyticsSessionEventSetting contains key of setting with ID of
+	// next session event that should be exported.
+	AnalyticsSessionEventSetting = "analytics.session_event_id"
+)
+
+const (
+	defaultAnalyticsName      = "solve_events"
+	defaultAnalyticsBatchSize = 1000
+	defaultAnalyticsInterval  = 5 * time.Second
+	// maxAnalyticsPending contains maximal amount of events that are
+	// kept in memory while sink is unavailable.
+	maxAnalyticsPending = 100000
+)
+
+// AnalyticsEventType represents type of analytics event.
+type AnalyticsEventType string
+
+const (
+	// SubmissionAnalyticsEvent means that solution is submitted.
+	SubmissionAnalyticsEvent AnalyticsEventType = "submission"
+	// VerdictAnalyticsEvent means that solution is judged.
+	VerdictAnalyticsEvent AnalyticsEventType = "verdict"
+	// LoginAnalyticsEvent means that account is logged in.
+	LoginAnalyticsEvent AnalyticsEventType = "login"
+)
+
+// AnalyticsEvent represents flat event that is shipped to analytics.
+type AnalyticsEvent struct {
+	Type AnalyticsEventType `json:"type"`
+	// EventID contains ID of source event.
+	//
+	// Together with type it can be used for deduplication.
+	EventID    int64   `json:"event_id"`
+	Time       int64   `json:"time"`
+	AccountID  int64   `json:"account_id,omitempty"`
+	SolutionID int64   `json:"solution_id,omitempty"`
+	ProblemID  int64   `json:"problem_id,omitempty"`
+	CompilerID int64   `json:"compiler_id,omitempty"`
+	Verdict    string  `json:"verdict,omitempty"`
+	Points     float64 `json:"points,omitempty"`
+	UsedTime   int64   `json:"used_time,omitempty"`
+	UsedMemory int64   `json:"used_memory,omitempty"`
+}
+
+// AnalyticsSink represents destination of analytics events.
+type AnalyticsSink interface {
+	// Send should deliver all events or return error.
+	Send(ctx context.Context, events []AnalyticsEvent) error
+}
+
+// NewAnalyticsSink creates sink from config.
+func NewAnalyticsSink(cfg config.Analytics) (AnalyticsSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("analytics url is not specified")
+	}
+	switch cfg.Kind {
+	case config.ClickHouseAnalytics:
+		table := cfg.Table
+		if table == "" {
+			table = defaultAnalyticsName
+		}
+		return &clickHouseSink{
+			url: cfg.URL, table: table,
+			user: cfg.User, password: cfg.Password,
+			client: http.DefaultClient,
+		}, nil
+	case config.KafkaAnalytics:
+		topic := cfg.Topic
+		if topic == "" {
+			topic = defaultAnalyticsName
+		}
+		return &kafkaSink{
+			url: cfg.URL, topic: topic,
+			user: cfg.User, password: cfg.Password,
+			client: http.DefaultClient,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported analytics kind: %q", cfg.Kind)
+	}
+}
+
+// clickHouseSink inserts events into ClickHouse table using
+// JSONEachRow format of HTTP interface.
+type clickHouseSink struct {
+	url      string
+	table    string
+	user     string
+	password string
+	client   *http.Client
+}
+
+func (s *clickHouseSink) Send(ctx context.Context, events []AnalyticsEvent) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", s.table))
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, s.url+"?"+query.Encode(), &body,
+	)
+	if err != nil {
+		return err
+	}
+	if s.user != "" {
+		req.Header.Set("X-ClickHouse-User", s.user)
+		req.Header.Set("X-ClickHouse-Key", s.password)
+	}
+	return doAnalyticsRequest(s.client, req)
+}
+
+// kafkaSink produces events to Kafka topic using REST proxy API.
+type kafkaSink struct {
+	url      string
+	topic    string
+	user     string
+	password string
+	client   *http.Client
+}
+
+type kafkaRecord struct {
+	Key   string         `json:"key"`
+	Value AnalyticsEvent `json:"value"`
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+func (s *kafkaSink) Send(ctx context.Context, events []AnalyticsEvent) error {
+	records := kafkaRecords{}
+	for _, event := range events {
+		records.Records = append(records.Records, kafkaRecord{
+			Key:   fmt.Sprint(event.AccountID),
+			Value: event,
+		})
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		strings.TrimSuffix(s.url, "/")+"/topics/"+url.PathEscape(s.topic),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	if s.user != "" {
+		req.SetBasicAuth(s.user, s.password)
+	}
+	return doAnalyticsRequest(s.client, req)
+}
+
+func doAnalyticsRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"unexpected status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(message)),
+		)
+	}
+	return nil
+}
+
+// AnalyticsManager represents manager for exporting events to
+// analytics storage.
+//
+// Events are delivered at least once: progress is saved in settings
+// only after successful delivery.
+type AnalyticsManager struct {
+	core *core.Core
+	sink AnalyticsSink
+}
+
+// NewAnalyticsManager creates a new instance of AnalyticsManager.
+func NewAnalyticsManager(core *core.Core, sink AnalyticsSink) *AnalyticsManager {
+	return &AnalyticsManager{core: core, sink: sink}
+}
+
+// Run exports events until context is canceled.
+//
+// Task should be started as unique daemon.
+func (m *AnalyticsManager) Run(ctx context.Context) {
+	interval := defaultAnalyticsInterval
+	batchSize := defaultAnalyticsBatchSize
+	if cfg := m.core.Config.Analytics; cfg != nil {
+		if cfg.Interval > 0 {
+			interval = time.Duration(cfg.Interval) * time.Second
+		}
+		if cfg.BatchSize > 0 {
+			batchSize = cfg.BatchSize
+		}
+	}
+	solutionBeginID, err := getAnalyticsBeginEventID(
+		ctx, m.core.Settings, AnalyticsSolutionEventSetting, m.core.Solutions.Events(),
+	)
+	if err != nil {
+		m.core.Logger().Error("Cannot start analytics export", err)
+		return
+	}
+	sessionBeginID, err := getAnalyticsBeginEventID(
+		ctx, m.core.Settings, AnalyticsSessionEventSetting, m.core.Sessions.Events(),
+	)
+	if err != nil {
+		m.core.Logger().Error("Cannot start analytics export", err)
+		return
+	}
+	exporter := analyticsExporter{
+		manager:   m,
+		batchSize: batchSize,
+		solutions: db.NewEventConsumer[models.SolutionEvent](
+			m.core.Solutions.Events(), solutionBeginID,
+		),
+		sessions: db.NewEventConsumer[models.SessionEvent](
+			m.core.Sessions.Events(), sessionBeginID,
+		),
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := exporter.Export(ctx); err != nil {
+			m.core.Logger().Warn("Cannot export analytics events", err)
+		}
+	}
+}
+
+type analyticsExporter struct {
+	manager   *AnalyticsManager
+	batchSize int
+	solutions db.EventConsumer[models.SolutionEvent, *models.SolutionEvent]
+	sessions  db.EventConsumer[models.SessionEvent, *models.SessionEvent]
+	pending   []AnalyticsEvent
+}
+
+// Export consumes new events and sends all pending events to sink.
+func (e *analyticsExporter) Export(ctx context.Context) error {
+	if err := e.solutions.ConsumeEvents(ctx, func(event models.SolutionEvent) error {
+		if item, ok := makeSolutionAnalyticsEvent(event); ok {
+			e.push(item)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := e.sessions.ConsumeEvents(ctx, func(event models.SessionEvent) error {
+		if item, ok := makeSessionAnalyticsEvent(event); ok {
+			e.push(item)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for len(e.pending) > 0 {
+		size := min(len(e.pending), e.batchSize)
+		if err := e.manager.sink.Send(ctx, e.pending[:size]); err != nil {
+			return err
+		}
+		e.pending = e.pending[size:]
+	}
+	e.pending = nil
+	return e.manager.saveProgress(
+		ctx, e.solutions.BeginEventID(), e.sessions.BeginEventID(),
+	)
+}
+
+func (e *analyticsExporter) push(event AnalyticsEvent) {
+	if len(e.pending) >= maxAnalyticsPending {
+		e.manager.core.Logger().Warn(
+			"Analytics events are dropped due to overflow",
+			logs.Any("count", len(e.pending)-maxAnalyticsPending+1),
+		)
+		e.pending = e.pending[len(e.pending)-maxAnalyticsPending+1:]
+	}
+	e.pending = append(e.pending, event)
+}
+
+func makeSolutionAnalyticsEvent(event models.SolutionEvent) (AnalyticsEvent, bool) {
+	item := AnalyticsEvent{
+		EventID:    event.EventID(),
+		Time:       event.EventTime().Unix(),
+		AccountID:  event.AuthorID,
+		SolutionID: event.ID,
+		ProblemID:  event.ProblemID,
+		CompilerID: event.CompilerID,
+	}
+	switch event.EventKind() {
+	case models.CreateEvent:
+		item.Type = SubmissionAnalyticsEvent
+		return item, true
+	case models.UpdateEvent:
+		report, err := event.GetReport()
+		if err != nil || report == nil || report.Verdict == 0 {
+			return AnalyticsEvent{}, false
+		}
+		item.Type = VerdictAnalyticsEvent
+		item.Verdict = report.Verdict.String()
+		if report.Points != nil {
+			item.Points = *report.Points
+		}
+		item.UsedTime = report.Usage.Time
+		item.UsedMemory = report.Usage.Memory
+		return item, true
+	default:
+		return AnalyticsEvent{}, false
+	}
+}
+
+func makeSessionAnalyticsEvent(event models.SessionEvent) (AnalyticsEvent, bool) {
+	if event.EventKind() != models.CreateEvent {
+		return AnalyticsEvent{}, false
+	}
+	return AnalyticsEvent{
+		Type:      LoginAnalyticsEvent,
+		EventID:   event.EventID(),
+		Time:      event.EventTime().Unix(),
+		AccountID: event.AccountID,
+	}, true
+}
+
+func getAnalyticsBeginEventID[T any](
+	ctx context.Context, settings *models.SettingStore, key string,
+	events db.EventROStore[T],
+) (int64, error) {
+	value, err := settings.GetInt64(key)
+	if err != nil {
+		return 0, err
+	}
+	if !value.Empty {
+		return value.Value, nil
+	}
+	// Export starts from new events on first run.
+	return getNextEventID(ctx, events)
+}
+
+func (m *AnalyticsManager) saveProgress(
+	ctx context.Context, solutionEventID, sessionEventID int64,
+) error {
+	if err := m.setEventID(
+		ctx, AnalyticsSolutionEventSetting, solutionEventID,
+	); err != nil {
+		return err
+	}
+	return m.setEventID(ctx, AnalyticsSessionEventSetting, sessionEventID)
+}
+
+func (m *AnalyticsManager) setEventID(ctx context.Context, key string, id int64) error {
+	value := fmt.Sprint(id)
+	setting, err := m.core.Settings.GetByKey(key)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		setting := models.Setting{Key: key, Value: value}
+		return m.core.Settings.Create(ctx, &setting)
+	}
+	if setting.Value == value {
+		return nil
+	}
+	setting.Value = value
+	return m.core.Settings.Update(ctx, setting)
+}
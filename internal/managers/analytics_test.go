@@ -0,0 +1,138 @@
+package managers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestClickHouseAnalyticsSink(t *testing.T) {
+	var query, body, user string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		user = r.Header.Get("X-ClickHouse-User")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+	sink, err := NewAnalyticsSink(config.Analytics{
+		Kind: config.ClickHouseAnalytics, URL: server.URL, User: "solve",
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := sink.Send(context.Background(), []AnalyticsEvent{
+		{Type: LoginAnalyticsEvent, EventID: 1, AccountID: 2},
+		{Type: SubmissionAnalyticsEvent, EventID: 3, SolutionID: 4},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if query != "INSERT INTO solve_events FORMAT JSONEachRow" {
+		t.Fatalf("Unexpected query: %q", query)
+	}
+	if user != "solve" {
+		t.Fatalf("Expected %q, got %q", "solve", user)
+	}
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(lines))
+	}
+	var event AnalyticsEvent
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if event.Type != SubmissionAnalyticsEvent || event.SolutionID != 4 {
+		t.Fatalf("Unexpected event: %v", event)
+	}
+}
+
+func TestKafkaAnalyticsSink(t *testing.T) {
+	var path, contentType string
+	var records kafkaRecords
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&records)
+	}))
+	defer server.Close()
+	sink, err := NewAnalyticsSink(config.Analytics{
+		Kind: config.KafkaAnalytics, URL: server.URL, Topic: "events",
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := sink.Send(context.Background(), []AnalyticsEvent{
+		{Type: VerdictAnalyticsEvent, EventID: 1, AccountID: 2, Verdict: "accepted"},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if path != "/topics/events" {
+		t.Fatalf("Unexpected path: %q", path)
+	}
+	if contentType != "application/vnd.kafka.json.v2+json" {
+		t.Fatalf("Unexpected content type: %q", contentType)
+	}
+	if len(records.Records) != 1 || records.Records[0].Key != "2" ||
+		records.Records[0].Value.Verdict != "accepted" {
+		t.Fatalf("Unexpected records: %v", records)
+	}
+}
+
+func TestAnalyticsSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	sink, err := NewAnalyticsSink(config.Analytics{
+		Kind: config.ClickHouseAnalytics, URL: server.URL,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := sink.Send(context.Background(), []AnalyticsEvent{{}}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := NewAnalyticsSink(config.Analytics{
+		Kind: "unknown", URL: server.URL,
+	}); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestMakeSolutionAnalyticsEvent(t *testing.T) {
+	event := models.SolutionEvent{}
+	event.SetEventKind(models.CreateEvent)
+	event.ID = 1
+	event.AuthorID = 2
+	if item, ok := makeSolutionAnalyticsEvent(event); !ok {
+		t.Fatal("Expected event")
+	} else if item.Type != SubmissionAnalyticsEvent || item.AccountID != 2 {
+		t.Fatalf("Unexpected event: %v", item)
+	}
+	event.SetEventKind(models.UpdateEvent)
+	if _, ok := makeSolutionAnalyticsEvent(event); ok {
+		t.Fatal("Expected skipped event without verdict")
+	}
+	if err := event.SetReport(&models.SolutionReport{
+		Verdict: models.Accepted,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if item, ok := makeSolutionAnalyticsEvent(event); !ok {
+		t.Fatal("Expected event")
+	} else if item.Type != VerdictAnalyticsEvent || item.Verdict != "accepted" {
+		t.Fatalf("Unexpected event: %v", item)
+	}
+	session := models.SessionEvent{}
+	session.SetEventKind(models.DeleteEvent)
+	if _, ok := makeSessionAnalyticsEvent(session); ok {
+		t.Fatal("Expected skipped event")
+	}
+}
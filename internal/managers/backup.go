@@ -0,0 +1,429 @@
+package managers
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// LastBackupTimeSetting contains key of setting with time of last
+// successful scheduled backup.
+const LastBackupTimeSetting = "backups.last_success_time"
+
+const (
+	backupManifestName = "manifest.json"
+	backupFilesName    = "files.json"
+	backupTablesDir    = "tables/"
+	backupVersion      = 1
+)
+
+// BackupManifest represents description of backup archive.
+type BackupManifest struct {
+	Version    int      `json:"version"`
+	CreateTime int64    `json:"create_time"`
+	Tables     []string `json:"tables"`
+}
+
+// BackupFile represents storage object referenced by file.
+//
+// Storage objects are not included into backup, so manifest can
+// be used to verify that storage backup is consistent with database.
+type BackupFile struct {
+	ID     int64  `json:"id"`
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"`
+	MD5    string `json:"md5,omitempty"`
+	Size   int64  `json:"size"`
+}
+
+// BackupManager represents manager for logical database backups.
+type BackupManager struct {
+	core *core.Core
+}
+
+// NewBackupManager creates a new instance of BackupManager.
+func NewBackupManager(c *core.Core) *BackupManager {
+	return &BackupManager{core: c}
+}
+
+// Backup writes logical dump of all tables into archive.
+func (m *BackupManager) Backup(ctx context.Context, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	if err := gosql.WrapTx(ctx, m.core.DB, func(tx *sql.Tx) error {
+		ctx := db.WithTx(ctx, tx)
+		tables, err := m.listTables(ctx)
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if err := m.dumpTable(ctx, archive, table); err != nil {
+				return fmt.Errorf("cannot dump table %q: %w", table, err)
+			}
+		}
+		if err := m.dumpFiles(ctx, archive); err != nil {
+			return err
+		}
+		manifest := BackupManifest{
+			Version:    backupVersion,
+			CreateTime: models.GetNow(ctx).Unix(),
+			Tables:     tables,
+		}
+		return writeBackupJSON(archive, backupManifestName, manifest)
+	}, gosql.WithReadOnly(true), gosql.WithIsolation(sql.LevelRepeatableRead)); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// Restore replaces contents of all tables with data from archive.
+//
+// Restore should be executed when servers and invokers are stopped,
+// because cached stores are not invalidated.
+func (m *BackupManager) Restore(
+	ctx context.Context, r io.ReaderAt, size int64,
+) error {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	var manifest BackupManifest
+	if err := readBackupJSON(archive, backupManifestName, &manifest); err != nil {
+		return err
+	}
+	if manifest.Version != backupVersion {
+		return fmt.Errorf("unsupported backup version: %d", manifest.Version)
+	}
+	return m.core.WrapTx(ctx, func(ctx context.Context) error {
+		// Tables are deleted in reverse order of creation, so foreign
+		// keys are not violated.
+		for i := len(manifest.Tables) - 1; i >= 0; i-- {
+			table := manifest.Tables[i]
+			query, values := m.core.DB.Build(m.core.DB.Delete(table))
+			if _, err := db.GetRunner(ctx, m.core.DB).ExecContext(ctx, query, values...); err != nil {
+				return fmt.Errorf("cannot clear table %q: %w", table, err)
+			}
+		}
+		for _, table := range manifest.Tables {
+			if err := m.restoreTable(ctx, archive, table); err != nil {
+				return fmt.Errorf("cannot restore table %q: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// BackupToDir creates backup archive in specified directory and
+// removes old archives except keepLast latest ones.
+func (m *BackupManager) BackupToDir(
+	ctx context.Context, dir string, keepLast int,
+) (string, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf(
+		"backup-%s.zip", models.GetNow(ctx).UTC().Format("20060102-150405"),
+	)
+	path := filepath.Join(dir, name)
+	if err := func() error {
+		file, err := os.Create(path + ".tmp")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		if err := m.Backup(ctx, file); err != nil {
+			return err
+		}
+		return file.Sync()
+	}(); err != nil {
+		_ = os.Remove(path + ".tmp")
+		return "", err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return "", err
+	}
+	if keepLast > 0 {
+		if err := removeOldBackups(dir, keepLast); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// Run creates scheduled backups until context is canceled.
+//
+// Task should be started as unique daemon.
+func (m *BackupManager) Run(ctx context.Context) {
+	cfg := m.core.Config.Backup
+	interval := time.Duration(cfg.Interval) * time.Second
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		if m.isBackupRequired(interval) {
+			path, err := m.BackupToDir(ctx, cfg.Dir, cfg.KeepLast)
+			if err != nil {
+				m.core.Logger().Error("Cannot create backup", err)
+			} else {
+				m.core.Logger().Info("Backup created", logs.Any("path", path))
+				if err := m.setLastBackupTime(ctx, time.Now()); err != nil {
+					m.core.Logger().Warn("Cannot update backup status", err)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetLastBackupTime returns time of last successful scheduled backup.
+func (m *BackupManager) GetLastBackupTime() (models.Option[time.Time], error) {
+	value, err := m.core.Settings.GetInt64(LastBackupTimeSetting)
+	if err != nil {
+		return models.Empty[time.Time](), err
+	}
+	if value.Empty {
+		return models.Empty[time.Time](), nil
+	}
+	return models.Value(time.Unix(value.Value, 0)), nil
+}
+
+func (m *BackupManager) isBackupRequired(interval time.Duration) bool {
+	lastTime, err := m.GetLastBackupTime()
+	if err != nil {
+		m.core.Logger().Warn("Cannot get backup status", err)
+		return false
+	}
+	return lastTime.Empty || time.Since(lastTime.Value) >= interval
+}
+
+func (m *BackupManager) setLastBackupTime(ctx context.Context, now time.Time) error {
+	value := fmt.Sprint(now.Unix())
+	setting, err := m.core.Settings.GetByKey(LastBackupTimeSetting)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		setting := models.Setting{Key: LastBackupTimeSetting, Value: value}
+		return m.core.Settings.Create(ctx, &setting)
+	}
+	setting.Value = value
+	return m.core.Settings.Update(ctx, setting)
+}
+
+// listTables returns tables in order of creation.
+func (m *BackupManager) listTables(ctx context.Context) ([]string, error) {
+	var query string
+	switch m.core.DB.Dialect() {
+	case gosql.SQLiteDialect:
+		query = `SELECT "name" FROM "sqlite_master"` +
+			` WHERE "type" = 'table' AND "name" NOT LIKE 'sqlite_%'` +
+			` ORDER BY "rowid"`
+	case gosql.PostgresDialect:
+		query = `SELECT "c"."relname" FROM "pg_class" "c"` +
+			` JOIN "pg_namespace" "n" ON "n"."oid" = "c"."relnamespace"` +
+			` WHERE "c"."relkind" = 'r' AND "n"."nspname" = current_schema()` +
+			` ORDER BY "c"."oid"`
+	default:
+		return nil, fmt.Errorf("unsupported dialect: %v", m.core.DB.Dialect())
+	}
+	rows, err := db.GetRunner(ctx, m.core.DB).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (m *BackupManager) dumpTable(
+	ctx context.Context, archive *zip.Writer, table string,
+) error {
+	query, values := m.core.DB.Build(m.core.DB.Select(table))
+	rows, err := db.GetRunner(ctx, m.core.DB).QueryContext(ctx, query, values...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	file, err := archive.Create(backupTablesDir + table + ".json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(columns); err != nil {
+		return err
+	}
+	row := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range row {
+		ptrs[i] = &row[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, value := range row {
+			// Blobs are used only for JSON columns.
+			if data, ok := value.([]byte); ok {
+				row[i] = string(data)
+			}
+		}
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (m *BackupManager) dumpFiles(ctx context.Context, archive *zip.Writer) error {
+	rows, err := m.core.Files.Objects().FindObjects(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	files := []BackupFile{}
+	for rows.Next() {
+		file := rows.Row()
+		meta, err := file.GetMeta()
+		if err != nil {
+			return err
+		}
+		files = append(files, BackupFile{
+			ID:     file.ID,
+			Path:   file.Path,
+			SHA256: string(file.SHA256),
+			MD5:    meta.MD5,
+			Size:   meta.Size,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return writeBackupJSON(archive, backupFilesName, files)
+}
+
+func (m *BackupManager) restoreTable(
+	ctx context.Context, archive *zip.Reader, table string,
+) error {
+	file, err := archive.Open(backupTablesDir + table + ".json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	var columns []string
+	if err := decoder.Decode(&columns); err != nil {
+		return err
+	}
+	runner := db.GetRunner(ctx, m.core.DB)
+	for decoder.More() {
+		var row []any
+		if err := decoder.Decode(&row); err != nil {
+			return err
+		}
+		if len(row) != len(columns) {
+			return fmt.Errorf("invalid row length: %d", len(row))
+		}
+		for i, value := range row {
+			if number, ok := value.(json.Number); ok {
+				if row[i], err = number.Int64(); err != nil {
+					return err
+				}
+			}
+		}
+		insert := m.core.DB.Insert(table)
+		insert.SetNames(columns...)
+		insert.SetValues(row...)
+		query, values := m.core.DB.Build(insert)
+		if _, err := runner.ExecContext(ctx, query, values...); err != nil {
+			return err
+		}
+	}
+	if m.core.DB.Dialect() == gosql.PostgresDialect {
+		// Sequences are not restored with rows, so they should be
+		// moved after restored identifiers.
+		for _, column := range columns {
+			if column != "id" && column != "event_id" {
+				continue
+			}
+			query := fmt.Sprintf(
+				`SELECT setval(pg_get_serial_sequence('%s', '%s'), MAX(%q)) FROM %q HAVING MAX(%q) IS NOT NULL`,
+				table, column, column, table, column,
+			)
+			if _, err := runner.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeBackupJSON(archive *zip.Writer, name string, value any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(file).Encode(value)
+}
+
+func readBackupJSON(archive *zip.Reader, name string, value any) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	return json.NewDecoder(file).Decode(value)
+}
+
+func removeOldBackups(dir string, keepLast int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "backup-") && strings.HasSuffix(name, ".zip") {
+			names = append(names, name)
+		}
+	}
+	// Names contain creation time, so sorting orders them by time.
+	sort.Strings(names)
+	for len(names) > keepLast {
+		if err := os.Remove(filepath.Join(dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
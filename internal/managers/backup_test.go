@@ -0,0 +1,116 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestBackupManager(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: filepath.Join(t.TempDir(), "db.sqlite")},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	ctx := context.Background()
+	if err := db.ApplyMigrations(ctx, c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	setting := models.Setting{Key: "test", Value: "value"}
+	if err := c.Settings.Create(ctx, &setting); err != nil {
+		t.Fatal("Error:", err)
+	}
+	task := models.Task{}
+	if err := task.SetConfig(models.JudgeSolutionTaskConfig{SolutionID: 42}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Tasks.Create(ctx, &task); err != nil {
+		t.Fatal("Error:", err)
+	}
+	manager := NewBackupManager(c)
+	var backup bytes.Buffer
+	if err := manager.Backup(ctx, &backup); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Tasks.Delete(ctx, task.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	other := models.Setting{Key: "other", Value: "value"}
+	if err := c.Settings.Create(ctx, &other); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := manager.Restore(
+		ctx, bytes.NewReader(backup.Bytes()), int64(backup.Len()),
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	restored, err := c.Tasks.Objects().FindObject(ctx, db.FindQuery{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if restored.ID != task.ID || restored.Kind != task.Kind {
+		t.Fatalf("Expected %v, got %v", task, restored)
+	}
+	var config models.JudgeSolutionTaskConfig
+	if err := restored.ScanConfig(&config); err != nil {
+		t.Fatal("Error:", err)
+	} else if config.SolutionID != 42 {
+		t.Fatalf("Expected %d, got %d", 42, config.SolutionID)
+	}
+	if _, err := c.Settings.Objects().FindObject(ctx, db.FindQuery{
+		Where: gosql.Column("key").Equal("other"),
+	}); err != sql.ErrNoRows {
+		t.Fatal("Expected removed setting, got:", err)
+	}
+}
+
+func TestBackupManagerDir(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: filepath.Join(t.TempDir(), "db.sqlite")},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	manager := NewBackupManager(c)
+	dir := t.TempDir()
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		ctx := models.WithNow(context.Background(), now.Add(time.Duration(i)*time.Hour))
+		if _, err := manager.BackupToDir(ctx, dir, 2); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(entries) != 2 {
+		t.Fatal("Expected two backups, got:", len(entries))
+	}
+	if name := entries[0].Name(); name != "backup-20200101-110000.zip" {
+		t.Fatal("Unexpected backup:", name)
+	}
+}
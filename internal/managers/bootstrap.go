@@ -0,0 +1,139 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// adminGroupRole contains name of role with all built-in roles.
+const adminGroupRole = "admin_group"
+
+// BootstrapManager creates objects that are required for usable
+// instance on first run.
+type BootstrapManager struct {
+	core  *core.Core
+	files *FileManager
+}
+
+// NewBootstrapManager creates a new instance of BootstrapManager.
+func NewBootstrapManager(c *core.Core) *BootstrapManager {
+	m := BootstrapManager{core: c}
+	if c.Config.Storage != nil {
+		m.files = NewFileManager(c)
+	}
+	return &m
+}
+
+// Bootstrap creates initial administrator and built-in compilers.
+//
+// Roles and role graph are created by data migrations, so they should
+// be applied before bootstrap. Existing objects are never modified.
+func (m *BootstrapManager) Bootstrap(ctx context.Context, cfg config.Bootstrap) error {
+	if cfg.Admin != nil {
+		if err := m.bootstrapAdmin(ctx, *cfg.Admin); err != nil {
+			return fmt.Errorf("cannot create admin: %w", err)
+		}
+	}
+	for _, compiler := range cfg.Compilers {
+		if err := m.bootstrapCompiler(ctx, compiler); err != nil {
+			return fmt.Errorf("cannot create compiler %q: %w", compiler.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *BootstrapManager) bootstrapAdmin(ctx context.Context, cfg config.BootstrapAdmin) error {
+	if cfg.Login == "" || cfg.Password == "" {
+		return fmt.Errorf("login and password should be specified")
+	}
+	for _, store := range []models.CachedStore{m.core.Users, m.core.Roles} {
+		if err := store.Sync(ctx); err != nil {
+			return err
+		}
+	}
+	if _, err := m.core.Users.GetByLogin(ctx, cfg.Login); err == nil {
+		return nil
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+	role, err := m.core.Roles.GetByName(ctx, adminGroupRole)
+	if err != nil {
+		return err
+	}
+	user := models.User{
+		Login:  cfg.Login,
+		Email:  models.NString(cfg.Email),
+		Status: models.ActiveUser,
+	}
+	if err := m.core.Users.SetPassword(&user, cfg.Password); err != nil {
+		return err
+	}
+	if err := m.core.WrapTx(ctx, func(ctx context.Context) error {
+		account := models.Account{Kind: user.AccountKind()}
+		if err := m.core.Accounts.Create(ctx, &account); err != nil {
+			return err
+		}
+		user.ID = account.ID
+		if err := m.core.Users.Create(ctx, &user); err != nil {
+			return err
+		}
+		edge := models.AccountRole{
+			AccountID: account.ID,
+			RoleID:    role.ID,
+		}
+		return m.core.AccountRoles.Create(ctx, &edge)
+	}); err != nil {
+		return err
+	}
+	m.core.Logger().Info("Admin created", logs.Any("login", user.Login))
+	return nil
+}
+
+func (m *BootstrapManager) bootstrapCompiler(ctx context.Context, cfg config.BootstrapCompiler) error {
+	if err := m.core.Compilers.Sync(ctx); err != nil {
+		return err
+	}
+	if _, err := m.core.Compilers.GetByName(cfg.Name); err == nil {
+		return nil
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+	if m.files == nil {
+		return fmt.Errorf("storage is not configured")
+	}
+	var compilerConfig models.CompilerConfig
+	if err := json.Unmarshal(cfg.Config, &compilerConfig); err != nil {
+		return err
+	}
+	compiler := models.Compiler{Name: cfg.Name}
+	if err := compiler.SetConfig(compilerConfig); err != nil {
+		return err
+	}
+	image, err := os.Open(cfg.ImageFile)
+	if err != nil {
+		return err
+	}
+	file, err := m.files.UploadFile(ctx, NewFileReader(image))
+	if err != nil {
+		return err
+	}
+	if err := m.core.WrapTx(ctx, func(ctx context.Context) error {
+		if err := m.files.ConfirmUploadFile(ctx, &file); err != nil {
+			return err
+		}
+		compiler.ImageID = file.ID
+		return m.core.Compilers.Create(ctx, &compiler)
+	}); err != nil {
+		return err
+	}
+	m.core.Logger().Info("Compiler created", logs.Any("name", compiler.Name))
+	return nil
+}
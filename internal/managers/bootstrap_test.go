@@ -0,0 +1,99 @@
+package managers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestBootstrapManager(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: filepath.Join(t.TempDir(), "db.sqlite")},
+		},
+		Security: &config.Security{PasswordSalt: "qwerty123"},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{FilesDir: t.TempDir()},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	ctx := context.Background()
+	if err := db.ApplyMigrations(ctx, c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := db.ApplyMigrations(ctx, c.DB, "solve_data", migrations.Data); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	imageFile := filepath.Join(t.TempDir(), "image.tar.gz")
+	if err := os.WriteFile(imageFile, []byte("image"), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	cfg := config.Bootstrap{
+		Admin: &config.BootstrapAdmin{
+			Login:    "admin",
+			Password: "qwerty123",
+		},
+		Compilers: []config.BootstrapCompiler{
+			{
+				Name:      "cpp",
+				Config:    []byte(`{"language":"C++","extensions":["cpp"]}`),
+				ImageFile: imageFile,
+			},
+		},
+	}
+	manager := NewBootstrapManager(c)
+	// Second bootstrap should not create duplicates.
+	for i := 0; i < 2; i++ {
+		if err := manager.Bootstrap(ctx, cfg); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := c.Users.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	user, err := c.Users.GetByLogin(ctx, "admin")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if user.Status != models.ActiveUser || !c.Users.CheckPassword(user, "qwerty123") {
+		t.Fatalf("Unexpected admin: %v", user)
+	}
+	if err := c.AccountRoles.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	rows, err := c.AccountRoles.FindByAccount(ctx, user.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	roles, err := db.CollectRows(rows)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(roles) != 1 {
+		t.Fatalf("Unexpected roles: %v", roles)
+	}
+	compiler, err := c.Compilers.GetByName("cpp")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if compiler.ImageID == 0 {
+		t.Fatalf("Unexpected compiler: %v", compiler)
+	}
+	if err := manager.Bootstrap(ctx, config.Bootstrap{
+		Admin: &config.BootstrapAdmin{Login: "other"},
+	}); err == nil {
+		t.Fatal("Expected error")
+	}
+}
@@ -3,6 +3,8 @@ package managers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/udovin/solve/internal/core"
@@ -14,17 +16,102 @@ import (
 type ContestManager struct {
 	contests     *models.ContestStore
 	participants *models.ContestParticipantStore
+	grants       *models.ContestGrantStore
+	scopes       *models.ScopeStore
 	settings     *models.SettingStore
+	cache        contestAccountCache
 }
 
 func NewContestManager(core *core.Core) *ContestManager {
 	return &ContestManager{
 		contests:     core.Contests,
 		participants: core.ContestParticipants,
+		grants:       core.ContestGrants,
+		scopes:       core.Scopes,
 		settings:     core.Settings,
+		cache: contestAccountCache{
+			entries: map[contestAccountKey]contestAccountEntry{},
+		},
 	}
 }
 
+// contestAccountCacheTTL contains lifetime of cached participants.
+const contestAccountCacheTTL = 5 * time.Second
+
+// contestAccountCacheSize contains maximal amount of cached entries.
+const contestAccountCacheSize = 4096
+
+type contestAccountKey struct {
+	AccountID int64
+	ContestID int64
+	Stage     ContestStage
+}
+
+// contestAccountData contains participants and grants of account
+// and its groups.
+type contestAccountData struct {
+	Grants       map[models.ContestGrantKind]struct{}
+	Participants []models.ContestParticipant
+	// GroupParticipants contains participants of groups in the same
+	// order as groups of account context.
+	GroupParticipants [][]models.ContestParticipant
+}
+
+type contestAccountEntry struct {
+	contestAccountData
+	GroupIDs            []int64
+	ParticipantsVersion int64
+	GrantsVersion       int64
+	ExpireTime          time.Time
+}
+
+// contestAccountCache contains short-lived participants and grants of
+// accounts, so burst of requests does not require recollection of them
+// for every contest.
+//
+// Entries are invalidated on every change of participants or grants
+// of contest.
+type contestAccountCache struct {
+	mutex   sync.Mutex
+	entries map[contestAccountKey]contestAccountEntry
+}
+
+func (c *contestAccountCache) Get(
+	key contestAccountKey, groupIDs []int64,
+	participantsVersion, grantsVersion int64, now time.Time,
+) (contestAccountData, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return contestAccountData{}, false
+	}
+	if entry.ParticipantsVersion != participantsVersion ||
+		entry.GrantsVersion != grantsVersion ||
+		!entry.ExpireTime.After(now) ||
+		!slices.Equal(entry.GroupIDs, groupIDs) {
+		delete(c.entries, key)
+		return contestAccountData{}, false
+	}
+	return entry.contestAccountData, true
+}
+
+func (c *contestAccountCache) Set(key contestAccountKey, entry contestAccountEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.entries) >= contestAccountCacheSize {
+		for key, entry := range c.entries {
+			if !entry.ExpireTime.After(time.Now()) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= contestAccountCacheSize {
+			c.entries = map[contestAccountKey]contestAccountEntry{}
+		}
+	}
+	c.entries[key] = entry
+}
+
 func addContestManagerPermissions(permissions perms.PermissionSet) {
 	permissions.AddPermission(
 		perms.ObserveContestRole,
@@ -54,13 +141,85 @@ func addContestManagerPermissions(permissions perms.PermissionSet) {
 		perms.UpdateContestMessageRole,
 		perms.DeleteContestMessageRole,
 		perms.SubmitContestQuestionRole,
+		perms.ObserveContestFeedbackRole,
+		perms.FinalizeContestRole,
+		perms.ObserveContestAppealsRole,
+		perms.ResolveContestAppealRole,
+		perms.ObserveContestScoreOverridesRole,
+		perms.CreateContestScoreOverrideRole,
+		perms.DeleteContestScoreOverrideRole,
+		perms.ObserveContestDashboardRole,
+		perms.ExportContestSolutionsRole,
+		perms.ObserveContestEditorialsRole,
+		perms.ManageContestEditorialsRole,
+	)
+}
+
+func addContestOwnerPermissions(permissions perms.PermissionSet) {
+	addContestManagerPermissions(permissions)
+	permissions.AddPermission(
+		perms.DeleteContestRole,
+		perms.ObserveContestGrantsRole,
+		perms.CreateContestGrantRole,
+		perms.DeleteContestGrantRole,
+	)
+}
+
+func addContestJuryPermissions(permissions perms.PermissionSet) {
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestProblemsRole,
+		perms.ObserveContestProblemRole,
+		perms.ObserveContestParticipantsRole,
+		perms.ObserveContestParticipantRole,
+		perms.ObserveContestSolutionsRole,
+		perms.ObserveContestSolutionRole,
+		perms.UpdateContestSolutionRole,
+		perms.ObserveContestStandingsRole,
+		perms.ObserveContestFullStandingsRole,
+		perms.ObserveSolutionReportTestNumber,
+		perms.ObserveSolutionReportCheckerLogs,
+		perms.ObserveContestMessagesRole,
+		perms.ObserveContestMessageRole,
+		perms.CreateContestMessageRole,
+		perms.UpdateContestMessageRole,
+		perms.ObserveContestAppealsRole,
+		perms.ResolveContestAppealRole,
+		perms.ObserveContestScoreOverridesRole,
+		perms.CreateContestScoreOverrideRole,
+		perms.DeleteContestScoreOverrideRole,
+		perms.ObserveContestDashboardRole,
+		perms.ExportContestSolutionsRole,
+		perms.ObserveContestEditorialsRole,
+	)
+}
+
+func addContestGrantObserverPermissions(permissions perms.PermissionSet) {
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestProblemsRole,
+		perms.ObserveContestProblemRole,
+		perms.ObserveContestParticipantsRole,
+		perms.ObserveContestParticipantRole,
+		perms.ObserveContestSolutionsRole,
+		perms.ObserveContestSolutionRole,
+		perms.ObserveContestStandingsRole,
+		perms.ObserveContestFullStandingsRole,
+		perms.ObserveSolutionReportTestNumber,
+		perms.ObserveContestMessagesRole,
+		perms.ObserveContestMessageRole,
+		perms.ObserveContestAppealsRole,
+		perms.ObserveContestEditorialsRole,
 	)
 }
 
 func addContestRegularPermissions(
 	permissions perms.PermissionSet, stage ContestStage, config *models.ContestConfig,
 ) {
-	permissions.AddPermission(perms.ObserveContestRole)
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestEditorialsRole,
+	)
 	switch stage {
 	case ContestNotStarted:
 		permissions.AddPermission(perms.DeregisterContestRole)
@@ -84,17 +243,24 @@ func addContestRegularPermissions(
 			perms.ObserveContestSolutionsRole,
 			perms.ObserveSolutionReportTestNumber,
 			perms.ObserveContestMessagesRole,
+			perms.CreateContestAppealRole,
 		)
 		if config.StandingsKind != models.DisabledStandings {
 			permissions.AddPermission(perms.ObserveContestStandingsRole)
 		}
+		if config.Feedback != nil {
+			permissions.AddPermission(perms.SubmitContestFeedbackRole)
+		}
 	}
 }
 
 func addContestVirtualPermissions(
 	permissions perms.PermissionSet, stage ContestStage, config *models.ContestConfig,
 ) {
-	permissions.AddPermission(perms.ObserveContestRole)
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestEditorialsRole,
+	)
 	switch stage {
 	case ContestNotStarted:
 		permissions.AddPermission(perms.DeregisterContestRole)
@@ -118,17 +284,24 @@ func addContestVirtualPermissions(
 			perms.ObserveContestSolutionsRole,
 			perms.ObserveSolutionReportTestNumber,
 			perms.ObserveContestMessagesRole,
+			perms.CreateContestAppealRole,
 		)
 		if config.StandingsKind != models.DisabledStandings {
 			permissions.AddPermission(perms.ObserveContestStandingsRole)
 		}
+		if config.Feedback != nil {
+			permissions.AddPermission(perms.SubmitContestFeedbackRole)
+		}
 	}
 }
 
 func addContestUpsolvingPermissions(
 	permissions perms.PermissionSet, stage ContestStage, config *models.ContestConfig,
 ) {
-	permissions.AddPermission(perms.ObserveContestRole)
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestEditorialsRole,
+	)
 	if stage == ContestFinished {
 		permissions.AddPermission(
 			perms.ObserveContestProblemsRole,
@@ -144,15 +317,43 @@ func addContestUpsolvingPermissions(
 	}
 }
 
-func addContestObserverPermissions(
+// addContestPublicObserverPermissions adds permissions for accounts
+// that observe contest without registration.
+func addContestPublicObserverPermissions(
 	permissions perms.PermissionSet, stage ContestStage, config *models.ContestConfig,
 ) {
-	permissions.AddPermission(perms.ObserveContestRole)
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestEditorialsRole,
+	)
 	switch stage {
 	case ContestStarted, ContestFinished:
 		if config.StandingsKind != models.DisabledStandings {
 			permissions.AddPermission(perms.ObserveContestStandingsRole)
 		}
+	}
+}
+
+// addContestObserverPermissions adds permissions for registered observer.
+//
+// Observer can view problems and frozen standings, but cannot submit
+// solutions.
+func addContestObserverPermissions(
+	permissions perms.PermissionSet, stage ContestStage, config *models.ContestConfig,
+) {
+	permissions.AddPermission(
+		perms.ObserveContestRole,
+		perms.ObserveContestEditorialsRole,
+	)
+	switch stage {
+	case ContestNotStarted:
+		permissions.AddPermission(perms.DeregisterContestRole)
+	case ContestStarted, ContestFinished:
+		permissions.AddPermission(
+			perms.ObserveContestProblemsRole,
+			perms.ObserveContestProblemRole,
+			perms.ObserveContestMessagesRole,
+		)
 		if config.StandingsKind != models.DisabledStandings {
 			permissions.AddPermission(perms.ObserveContestStandingsRole)
 		}
@@ -166,7 +367,17 @@ func getParticipantPermissions(
 ) perms.PermissionSet {
 	stage := getParticipantContestTime(config, participant, now).Stage()
 	permissions := perms.PermissionSet{}
-	switch participant.Kind {
+	addParticipantKindPermissions(permissions, participant.Kind, stage, config)
+	return permissions
+}
+
+func addParticipantKindPermissions(
+	permissions perms.PermissionSet,
+	kind models.ParticipantKind,
+	stage ContestStage,
+	config *models.ContestConfig,
+) {
+	switch kind {
 	case models.RegularParticipant:
 		addContestRegularPermissions(permissions, stage, config)
 	case models.UpsolvingParticipant:
@@ -178,7 +389,92 @@ func getParticipantPermissions(
 	case models.VirtualParticipant:
 		addContestVirtualPermissions(permissions, stage, config)
 	}
-	return permissions
+}
+
+// findAccountGrants returns kinds of contest grants for account and
+// its groups.
+func (m *ContestManager) findAccountGrants(
+	ctx *AccountContext, contestID int64,
+) (map[models.ContestGrantKind]struct{}, error) {
+	kinds := map[models.ContestGrantKind]struct{}{}
+	accountIDs := []int64{ctx.Account.ID}
+	for _, group := range ctx.GroupAccounts {
+		accountIDs = append(accountIDs, group.ID)
+	}
+	for _, accountID := range accountIDs {
+		rows, err := m.grants.FindByContestAccount(ctx, contestID, accountID)
+		if err != nil {
+			return nil, err
+		}
+		grants, err := db.CollectRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range grants {
+			kinds[grant.Kind] = struct{}{}
+		}
+	}
+	return kinds, nil
+}
+
+// getAccountData returns participants and grants of account for contest.
+func (m *ContestManager) getAccountData(
+	ctx *AccountContext, contestID int64, stage ContestStage,
+) (contestAccountData, error) {
+	key := contestAccountKey{
+		AccountID: ctx.Account.ID,
+		ContestID: contestID,
+		Stage:     stage,
+	}
+	var groupIDs []int64
+	for _, group := range ctx.GroupAccounts {
+		groupIDs = append(groupIDs, group.ID)
+	}
+	// Versions should be obtained before collection of objects, so
+	// concurrent changes will invalidate cached entry.
+	participantsVersion := m.participants.GetContestVersion(contestID)
+	grantsVersion := m.grants.GetContestVersion(contestID)
+	now := time.Now()
+	if data, ok := m.cache.Get(
+		key, groupIDs, participantsVersion, grantsVersion, now,
+	); ok {
+		return data, nil
+	}
+	grants, err := m.findAccountGrants(ctx, contestID)
+	if err != nil {
+		return contestAccountData{}, err
+	}
+	participantRows, err := m.participants.FindByContestAccount(ctx, contestID, ctx.Account.ID)
+	if err != nil {
+		return contestAccountData{}, err
+	}
+	participants, err := db.CollectRows(participantRows)
+	if err != nil {
+		return contestAccountData{}, err
+	}
+	data := contestAccountData{
+		Grants:       grants,
+		Participants: participants,
+	}
+	for _, groupID := range groupIDs {
+		groupParticipantRows, err := m.participants.FindByContestAccount(ctx, contestID, groupID)
+		if err != nil {
+			return contestAccountData{}, err
+		}
+		groupParticipants, err := db.CollectRows(groupParticipantRows)
+		if err != nil {
+			return contestAccountData{}, err
+		}
+		data.GroupParticipants = append(data.GroupParticipants, groupParticipants)
+	}
+	m.cache.Set(key, contestAccountEntry{
+		contestAccountData:  data,
+		GroupIDs:            groupIDs,
+		ParticipantsVersion: participantsVersion,
+		GrantsVersion:       grantsVersion,
+		ExpireTime:          now.Add(contestAccountCacheTTL),
+	})
+	return data, nil
 }
 
 func checkEffectiveParticipant(
@@ -215,22 +511,39 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 	}
 	now := c.Now.Unix()
 	stage := getParticipantContestTime(&c.ContestConfig, nil, now).Stage()
+	// Contests of scope are available only for users of this scope.
+	inScope := contest.ScopeID == 0 || ctx.IsScopeMember(int64(contest.ScopeID))
+	// Unpublished contests are hidden for accounts without grants.
+	published := config.IsPublished(now)
 	if account := ctx.Account; account != nil {
-		if contest.OwnerID != 0 && account.ID == int64(contest.OwnerID) {
-			c.Permissions.AddPermission(perms.DeleteContestRole)
+		isOwner := contest.OwnerID != 0 && account.ID == int64(contest.OwnerID)
+		if !isOwner && contest.ScopeID != 0 {
+			isOwner = IsScopeAdmin(ctx, m.scopes, int64(contest.ScopeID))
 		}
-		participantRows, err := m.participants.FindByContestAccount(ctx, contest.ID, account.ID)
+		data, err := m.getAccountData(ctx, contest.ID, stage)
 		if err != nil {
 			return nil, fmt.Errorf("unable to build contest context: %w", err)
 		}
-		participants, err := db.CollectRows(participantRows)
-		if err != nil {
-			return nil, fmt.Errorf("unable to build contest context: %w", err)
+		grants := data.Grants
+		if _, ok := grants[models.OwnerContestGrant]; ok {
+			isOwner = true
+		}
+		if _, ok := grants[models.JuryContestGrant]; ok {
+			addContestJuryPermissions(c.Permissions)
+		}
+		if _, ok := grants[models.ObserverContestGrant]; ok {
+			addContestGrantObserverPermissions(c.Permissions)
+		}
+		_, isManager := grants[models.ManagerContestGrant]
+		participants := make([]models.ContestParticipant, 0, len(data.Participants))
+		for _, participant := range data.Participants {
+			participants = append(participants, participant.Clone())
 		}
 		hasRegular := false
 		hasUpsolving := false
 		hasManager := false
 		hasVirtual := false
+		hasObserver := false
 		for _, participant := range participants {
 			for permission := range getParticipantPermissions(&config, &participant, now) {
 				c.Permissions.AddPermission(permission)
@@ -244,18 +557,12 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 				hasManager = true
 			case models.VirtualParticipant:
 				hasVirtual = true
+			case models.ObserverParticipant:
+				hasObserver = true
 			}
 		}
 		c.Participants = participants
-		for _, group := range ctx.GroupAccounts {
-			groupParticipantRows, err := m.participants.FindByContestAccount(ctx, contest.ID, group.ID)
-			if err != nil {
-				return nil, fmt.Errorf("unable to build contest context: %w", err)
-			}
-			groupParticipants, err := db.CollectRows(groupParticipantRows)
-			if err != nil {
-				return nil, fmt.Errorf("unable to build contest context: %w", err)
-			}
+		for _, groupParticipants := range data.GroupParticipants {
 			for _, groupParticipant := range groupParticipants {
 				for permission := range getParticipantPermissions(&config, &groupParticipant, now) {
 					if permission == perms.DeregisterContestRole {
@@ -299,7 +606,7 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 				}
 			}
 		}
-		if !hasManager && contest.OwnerID != 0 && account.ID == int64(contest.OwnerID) {
+		if !hasManager && (isOwner || isManager) {
 			c.Participants = append(c.Participants, models.ContestParticipant{
 				ContestID: contest.ID,
 				AccountID: account.ID,
@@ -307,8 +614,12 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 			})
 			addContestManagerPermissions(c.Permissions)
 		}
+		if isOwner {
+			addContestOwnerPermissions(c.Permissions)
+		}
 		// User can possibly register on contest.
-		canRegister := config.EnableRegistration && c.HasPermission(perms.RegisterContestsRole)
+		canRegister := config.EnableRegistration && inScope && published &&
+			c.HasPermission(perms.RegisterContestsRole)
 		if !hasRegular && stage == ContestNotStarted && canRegister {
 			c.Permissions.AddPermission(perms.ObserveContestRole)
 			c.Permissions.AddPermission(perms.RegisterContestRole)
@@ -319,6 +630,13 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 			c.Permissions.AddPermission(perms.ObserveContestRole)
 			c.Permissions.AddPermission(perms.RegisterContestVirtualRole)
 		}
+		// User can possibly observe contest without participation.
+		canObserve := config.EnableObserving && inScope && published &&
+			c.HasPermission(perms.RegisterContestsRole)
+		if !hasObserver && !hasRegular && stage != ContestFinished && canObserve {
+			c.Permissions.AddPermission(perms.ObserveContestRole)
+			c.Permissions.AddPermission(perms.RegisterContestObserverRole)
+		}
 		// User can possibly upsolve contest.
 		canUpsolving := config.EnableUpsolving && (hasRegular || canRegister)
 		if !hasUpsolving && stage == ContestFinished && canUpsolving {
@@ -330,10 +648,22 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 			})
 			addContestUpsolvingPermissions(c.Permissions, stage, &config)
 		}
+		// Published contest is visible in listings for all accounts
+		// that can register on contests.
+		if config.PublishTime != 0 && published && inScope &&
+			c.HasPermission(perms.RegisterContestsRole) {
+			c.Permissions.AddPermission(perms.ObserveContestRole)
+		}
+	}
+	if config.EnableObserving && inScope && published && len(c.Participants) == 0 {
+		addContestPublicObserverPermissions(c.Permissions, stage, &config)
 	}
-	if config.EnableObserving && len(c.Participants) == 0 {
-		addContestObserverPermissions(c.Permissions, stage, &config)
+	if c.IsFinalized() {
+		for _, permission := range finalizedContestPermissions {
+			delete(c.Permissions, permission)
+		}
 	}
+	restrictContestPermissions(&c)
 	disableUpsolving := false
 	if setting, err := m.settings.GetByKey("contests.disable_upsolving"); err == nil {
 		disableUpsolving = setting.Value == "t" || setting.Value == "1" || setting.Value == "true"
@@ -461,6 +791,18 @@ func (c *ContestContext) GetEffectiveContestTime() ContestTime {
 	)
 }
 
+// GetParticipantBeginTime returns begin time of contest for participant.
+func (c *ContestContext) GetParticipantBeginTime(
+	participant *models.ContestParticipant,
+) int64 {
+	return getParticipantBeginTime(&c.ContestConfig, participant)
+}
+
+// GetContestTime returns contest time without participant.
+func (c *ContestContext) GetContestTime() ContestTime {
+	return getParticipantContestTime(&c.ContestConfig, nil, c.Now.Unix())
+}
+
 // participant can be nil.
 func getParticipantContestTime(
 	config *models.ContestConfig,
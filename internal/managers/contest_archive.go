@@ -0,0 +1,584 @@
+package managers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+const (
+	contestArchiveManifestName     = "manifest.json"
+	contestArchiveProblemsName     = "problems.json"
+	contestArchiveParticipantsName = "participants.json"
+	contestArchiveSolutionsName    = "solutions.json"
+	contestArchiveResultsName      = "results.json"
+	contestArchiveSolutionsDir     = "solutions/"
+	contestArchiveVersion          = 1
+)
+
+// ContestArchiveManifest represents description of contest archive.
+type ContestArchiveManifest struct {
+	Version    int         `json:"version"`
+	CreateTime int64       `json:"create_time"`
+	ContestID  int64       `json:"contest_id"`
+	Title      string      `json:"title"`
+	Config     models.JSON `json:"config"`
+}
+
+// ContestArchiveProblem represents reference to problem of contest.
+//
+// Problems are not included into archive, so they should be
+// available on instance that imports archive.
+type ContestArchiveProblem struct {
+	ID           int64       `json:"id"`
+	Code         string      `json:"code"`
+	ProblemID    int64       `json:"problem_id"`
+	ProblemTitle string      `json:"problem_title"`
+	Config       models.JSON `json:"config,omitempty"`
+}
+
+// ContestArchiveParticipant represents participant of contest.
+//
+// Only participants with user accounts can be imported, they are
+// matched by login.
+type ContestArchiveParticipant struct {
+	ID          int64                  `json:"id"`
+	Kind        models.ParticipantKind `json:"kind"`
+	AccountKind models.AccountKind     `json:"account_kind"`
+	Login       string                 `json:"login,omitempty"`
+	Config      models.JSON            `json:"config,omitempty"`
+}
+
+// ContestArchiveSolution represents solution of contest.
+type ContestArchiveSolution struct {
+	ID            int64       `json:"id"`
+	ParticipantID int64       `json:"participant_id"`
+	ProblemID     int64       `json:"problem_id"`
+	Compiler      string      `json:"compiler"`
+	CreateTime    int64       `json:"create_time"`
+	Report        models.JSON `json:"report,omitempty"`
+	// Content contains name of archive entry with source file.
+	Content string `json:"content,omitempty"`
+}
+
+// ContestArchiveResults represents final results of contest.
+type ContestArchiveResults struct {
+	CreateTime int64                     `json:"create_time"`
+	Rows       []models.ContestResultRow `json:"rows"`
+}
+
+// ExportContest writes tarball with contest config, references to
+// problems, participants, solutions with source files and final
+// results of contest.
+func (m *ContestExportManager) ExportContest(
+	ctx context.Context, contestID int64, w io.Writer,
+) error {
+	contest, err := m.core.Contests.Get(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(w)
+	archive := tar.NewWriter(gzipWriter)
+	now := time.Now()
+	if err := writeArchiveJSON(archive, contestArchiveManifestName, now, ContestArchiveManifest{
+		Version:    contestArchiveVersion,
+		CreateTime: now.Unix(),
+		ContestID:  contest.ID,
+		Title:      contest.Title,
+		Config:     contest.Config,
+	}); err != nil {
+		return err
+	}
+	problems, err := m.exportProblems(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(archive, contestArchiveProblemsName, now, problems); err != nil {
+		return err
+	}
+	participants, err := m.exportParticipants(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(archive, contestArchiveParticipantsName, now, participants); err != nil {
+		return err
+	}
+	solutions, err := m.exportSolutions(ctx, archive, contest.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(archive, contestArchiveSolutionsName, now, solutions); err != nil {
+		return err
+	}
+	results, err := m.exportResults(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	if results != nil {
+		if err := writeArchiveJSON(archive, contestArchiveResultsName, now, results); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func (m *ContestExportManager) exportProblems(
+	ctx context.Context, contestID int64,
+) ([]ContestArchiveProblem, error) {
+	rows, err := m.core.ContestProblems.FindByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	contestProblems, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	problems := []ContestArchiveProblem{}
+	for _, contestProblem := range contestProblems {
+		problem, err := m.core.Problems.Get(ctx, contestProblem.ProblemID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get problem %d: %w", contestProblem.ProblemID, err)
+		}
+		problems = append(problems, ContestArchiveProblem{
+			ID:           contestProblem.ID,
+			Code:         contestProblem.Code,
+			ProblemID:    problem.ID,
+			ProblemTitle: problem.Title,
+			Config:       contestProblem.Config,
+		})
+	}
+	return problems, nil
+}
+
+func (m *ContestExportManager) exportParticipants(
+	ctx context.Context, contestID int64,
+) ([]ContestArchiveParticipant, error) {
+	rows, err := m.core.ContestParticipants.FindByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	contestParticipants, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	participants := []ContestArchiveParticipant{}
+	for _, participant := range contestParticipants {
+		resp := ContestArchiveParticipant{
+			ID:     participant.ID,
+			Kind:   participant.Kind,
+			Config: participant.Config,
+		}
+		if account, err := m.core.Accounts.Get(ctx, participant.AccountID); err == nil {
+			resp.AccountKind = account.Kind
+			resp.Login = m.getAccountName(ctx, account.ID)
+		}
+		participants = append(participants, resp)
+	}
+	return participants, nil
+}
+
+func (m *ContestExportManager) exportSolutions(
+	ctx context.Context, archive *tar.Writer, contestID int64,
+) ([]ContestArchiveSolution, error) {
+	rows, err := m.core.ContestSolutions.FindByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	contestSolutions, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	solutions := []ContestArchiveSolution{}
+	for _, contestSolution := range contestSolutions {
+		solution, err := m.core.Solutions.Get(ctx, contestSolution.ID)
+		if err != nil {
+			m.core.Logger().Warn(
+				"Cannot find solution",
+				logs.Any("id", contestSolution.ID),
+				err,
+			)
+			continue
+		}
+		resp := ContestArchiveSolution{
+			ID:            solution.ID,
+			ParticipantID: contestSolution.ParticipantID,
+			ProblemID:     contestSolution.ProblemID,
+			CreateTime:    solution.CreateTime,
+			Report:        solution.Report,
+		}
+		if compiler, err := m.core.Compilers.Get(ctx, solution.CompilerID); err == nil {
+			resp.Compiler = compiler.Name
+		}
+		content, err := m.readSolutionContent(ctx, solution)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			resp.Content = fmt.Sprintf("%s%d", contestArchiveSolutionsDir, solution.ID)
+			if err := writeArchiveFile(
+				archive, resp.Content, time.Unix(solution.CreateTime, 0), content,
+			); err != nil {
+				return nil, err
+			}
+		}
+		solutions = append(solutions, resp)
+	}
+	return solutions, nil
+}
+
+func (m *ContestExportManager) readSolutionContent(
+	ctx context.Context, solution models.Solution,
+) ([]byte, error) {
+	if solution.Content != "" {
+		return []byte(solution.Content), nil
+	}
+	if solution.ContentID == 0 || m.files == nil {
+		return nil, nil
+	}
+	file, err := m.files.DownloadFile(ctx, int64(solution.ContentID))
+	if err != nil {
+		return nil, fmt.Errorf("cannot download content of solution %d: %w", solution.ID, err)
+	}
+	defer func() { _ = file.Close() }()
+	return io.ReadAll(file)
+}
+
+func (m *ContestExportManager) exportResults(
+	ctx context.Context, contestID int64,
+) (*ContestArchiveResults, error) {
+	rows, err := m.core.ContestResults.FindByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := db.CollectRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	// Use the latest results of contest.
+	result := results[len(results)-1]
+	resultRows, err := result.GetResults()
+	if err != nil {
+		return nil, err
+	}
+	return &ContestArchiveResults{
+		CreateTime: result.CreateTime,
+		Rows:       resultRows,
+	}, nil
+}
+
+// ImportContest creates new contest from tarball created by
+// ExportContest.
+//
+// Problems are matched by ID and title or by unique title, compilers
+// are matched by name and participants are matched by login of user.
+// Solutions are imported with their reports and are not judged again.
+func (m *ContestExportManager) ImportContest(
+	ctx context.Context, r io.Reader, ownerID models.NInt64,
+) (models.Contest, error) {
+	entries, err := readContestArchive(r)
+	if err != nil {
+		return models.Contest{}, err
+	}
+	var manifest ContestArchiveManifest
+	if err := readArchiveJSON(entries, contestArchiveManifestName, &manifest); err != nil {
+		return models.Contest{}, err
+	}
+	if manifest.Version != contestArchiveVersion {
+		return models.Contest{}, fmt.Errorf("unsupported archive version: %d", manifest.Version)
+	}
+	var problems []ContestArchiveProblem
+	if err := readArchiveJSON(entries, contestArchiveProblemsName, &problems); err != nil {
+		return models.Contest{}, err
+	}
+	var participants []ContestArchiveParticipant
+	if err := readArchiveJSON(entries, contestArchiveParticipantsName, &participants); err != nil {
+		return models.Contest{}, err
+	}
+	var solutions []ContestArchiveSolution
+	if err := readArchiveJSON(entries, contestArchiveSolutionsName, &solutions); err != nil {
+		return models.Contest{}, err
+	}
+	var results *ContestArchiveResults
+	if _, ok := entries[contestArchiveResultsName]; ok {
+		results = &ContestArchiveResults{}
+		if err := readArchiveJSON(entries, contestArchiveResultsName, results); err != nil {
+			return models.Contest{}, err
+		}
+	}
+	problemIDs := map[int64]int64{}
+	for _, problem := range problems {
+		id, err := m.findArchiveProblem(ctx, problem)
+		if err != nil {
+			return models.Contest{}, err
+		}
+		problemIDs[problem.ID] = id
+	}
+	accountIDs := map[int64]int64{}
+	for _, participant := range participants {
+		if participant.AccountKind != models.UserAccountKind {
+			m.core.Logger().Warn(
+				"Skipping participant without user account",
+				logs.Any("id", participant.ID),
+			)
+			continue
+		}
+		user, err := m.core.Users.GetByLogin(ctx, participant.Login)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				m.core.Logger().Warn(
+					"Skipping participant with unknown user",
+					logs.Any("id", participant.ID),
+					logs.Any("login", participant.Login),
+				)
+				continue
+			}
+			return models.Contest{}, err
+		}
+		accountIDs[participant.ID] = user.ID
+	}
+	// Files should be uploaded before transaction.
+	contentFiles := map[int64]models.File{}
+	for _, solution := range solutions {
+		if _, ok := accountIDs[solution.ParticipantID]; !ok || solution.Content == "" {
+			continue
+		}
+		if m.files == nil {
+			continue
+		}
+		content, ok := entries[solution.Content]
+		if !ok {
+			return models.Contest{}, fmt.Errorf("archive entry %q not found", solution.Content)
+		}
+		file, err := m.files.UploadFile(ctx, &FileReader{
+			Name:   "solution.txt",
+			Size:   int64(len(content)),
+			Reader: bytes.NewReader(content),
+		})
+		if err != nil {
+			return models.Contest{}, err
+		}
+		contentFiles[solution.ID] = file
+	}
+	contest := models.Contest{
+		OwnerID: ownerID,
+		Title:   manifest.Title,
+		Config:  manifest.Config,
+	}
+	if err := m.core.WrapTx(ctx, func(ctx context.Context) error {
+		if err := m.core.Contests.Create(ctx, &contest); err != nil {
+			return err
+		}
+		contestProblemIDs := map[int64]int64{}
+		for _, problem := range problems {
+			contestProblem := models.ContestProblem{
+				ContestID: contest.ID,
+				ProblemID: problemIDs[problem.ID],
+				Code:      problem.Code,
+				Config:    problem.Config,
+			}
+			if err := m.core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+				return err
+			}
+			contestProblemIDs[problem.ID] = contestProblem.ID
+		}
+		participantIDs := map[int64]int64{}
+		for _, participant := range participants {
+			accountID, ok := accountIDs[participant.ID]
+			if !ok {
+				continue
+			}
+			contestParticipant := models.ContestParticipant{
+				ContestID: contest.ID,
+				AccountID: accountID,
+				Kind:      participant.Kind,
+				Config:    participant.Config,
+			}
+			if err := m.core.ContestParticipants.Create(ctx, &contestParticipant); err != nil {
+				return err
+			}
+			participantIDs[participant.ID] = contestParticipant.ID
+		}
+		for _, archiveSolution := range solutions {
+			participantID, ok := participantIDs[archiveSolution.ParticipantID]
+			if !ok {
+				continue
+			}
+			problemID, ok := contestProblemIDs[archiveSolution.ProblemID]
+			if !ok {
+				return fmt.Errorf("problem %d of solution %d not found", archiveSolution.ProblemID, archiveSolution.ID)
+			}
+			compiler, err := m.core.Compilers.GetByName(archiveSolution.Compiler)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("compiler %q not found", archiveSolution.Compiler)
+				}
+				return err
+			}
+			solution := models.Solution{
+				Kind:       models.ContestSolutionKind,
+				ProblemID:  problemIDs[archiveSolution.ProblemID],
+				CompilerID: compiler.ID,
+				AuthorID:   accountIDs[archiveSolution.ParticipantID],
+				Report:     archiveSolution.Report,
+				CreateTime: archiveSolution.CreateTime,
+			}
+			if file, ok := contentFiles[archiveSolution.ID]; ok {
+				if err := m.files.ConfirmUploadFile(ctx, &file); err != nil {
+					return err
+				}
+				solution.ContentID = models.NInt64(file.ID)
+			} else if archiveSolution.Content != "" {
+				solution.Content = models.NString(entries[archiveSolution.Content])
+			}
+			if err := m.core.Solutions.Create(ctx, &solution); err != nil {
+				return err
+			}
+			contestSolution := models.ContestSolution{
+				ContestID:     contest.ID,
+				ParticipantID: participantID,
+				ProblemID:     problemID,
+			}
+			contestSolution.ID = solution.ID
+			if err := m.core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+				return err
+			}
+		}
+		if results == nil {
+			return nil
+		}
+		var rows []models.ContestResultRow
+		for _, row := range results.Rows {
+			// Fake participants are not included into archive.
+			participantID, ok := participantIDs[row.ParticipantID]
+			if !ok {
+				continue
+			}
+			row.ParticipantID = participantID
+			rows = append(rows, row)
+		}
+		result := models.ContestResult{
+			ContestID:  contest.ID,
+			CreateTime: results.CreateTime,
+		}
+		if ownerID != 0 {
+			result.AuthorID = ownerID
+		}
+		if err := result.SetResults(rows); err != nil {
+			return err
+		}
+		return m.core.ContestResults.Create(ctx, &result)
+	}, gosql.WithIsolation(sql.LevelRepeatableRead)); err != nil {
+		return models.Contest{}, err
+	}
+	return contest, nil
+}
+
+// findArchiveProblem returns ID of problem that matches reference.
+func (m *ContestExportManager) findArchiveProblem(
+	ctx context.Context, reference ContestArchiveProblem,
+) (int64, error) {
+	if problem, err := m.core.Problems.Get(ctx, reference.ProblemID); err == nil {
+		if problem.Title == reference.ProblemTitle {
+			return problem.ID, nil
+		}
+	} else if err != sql.ErrNoRows {
+		return 0, err
+	}
+	rows, err := m.core.Problems.All(ctx, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	var found []int64
+	for rows.Next() {
+		if problem := rows.Row(); problem.Title == reference.ProblemTitle {
+			found = append(found, problem.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	switch len(found) {
+	case 0:
+		return 0, fmt.Errorf("problem %q not found", reference.ProblemTitle)
+	case 1:
+		return found[0], nil
+	default:
+		return 0, fmt.Errorf("problem %q is ambiguous", reference.ProblemTitle)
+	}
+}
+
+func writeArchiveJSON(archive *tar.Writer, name string, modTime time.Time, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeArchiveFile(archive, name, modTime, data)
+}
+
+func writeArchiveFile(archive *tar.Writer, name string, modTime time.Time, data []byte) error {
+	if err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := archive.Write(data)
+	return err
+}
+
+func readContestArchive(r io.Reader) (map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gzipReader.Close() }()
+	archive := tar.NewReader(gzipReader)
+	entries := map[string][]byte{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+		entries[strings.TrimPrefix(header.Name, "./")] = data
+	}
+	return entries, nil
+}
+
+func readArchiveJSON(entries map[string][]byte, name string, value any) error {
+	data, ok := entries[name]
+	if !ok {
+		return fmt.Errorf("archive entry %q not found", name)
+	}
+	return json.Unmarshal(data, value)
+}
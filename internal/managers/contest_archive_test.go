@@ -0,0 +1,181 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
+)
+
+func TestContestArchive(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: filepath.Join(t.TempDir(), "db.sqlite")},
+		},
+		Security: &config.Security{PasswordSalt: "qwerty123"},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{FilesDir: t.TempDir()},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	ctx := context.Background()
+	if err := db.ApplyMigrations(ctx, c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	account := models.Account{Kind: models.UserAccountKind}
+	if err := c.Accounts.Create(ctx, &account); err != nil {
+		t.Fatal("Error:", err)
+	}
+	user := models.User{Login: "test"}
+	user.ID = account.ID
+	if err := c.Users.Create(ctx, &user); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := c.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	files := NewFileManager(c)
+	image, err := files.UploadFile(ctx, &FileReader{
+		Name:   "image.tar.gz",
+		Size:   5,
+		Reader: bytes.NewReader([]byte("image")),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := files.ConfirmUploadFile(ctx, &image); err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "test-cpp", ImageID: image.ID}
+	if err := c.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest := models.Contest{Title: "Test contest"}
+	if err := c.Contests.Create(ctx, &contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID, ProblemID: problem.ID, Code: "A",
+	}
+	if err := c.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant := models.ContestParticipant{
+		ContestID: contest.ID, AccountID: account.ID,
+		Kind: models.RegularParticipant,
+	}
+	if err := c.ContestParticipants.Create(ctx, &participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		Kind:       models.ContestSolutionKind,
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   account.ID,
+		Content:    "int main() { return 0; }",
+	}
+	if err := solution.SetReport(&models.SolutionReport{Verdict: models.Accepted}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID: contest.ID, ParticipantID: participant.ID,
+		ProblemID: contestProblem.ID,
+	}
+	contestSolution.ID = solution.ID
+	if err := c.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	result := models.ContestResult{ContestID: contest.ID}
+	if err := result.SetResults([]models.ContestResultRow{
+		{ParticipantID: participant.ID, Place: 1, Score: 1},
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.ContestResults.Create(ctx, &result); err != nil {
+		t.Fatal("Error:", err)
+	}
+	syncStores := func() {
+		for _, store := range []models.CachedStore{
+			c.Accounts, c.Users, c.Problems, c.Compilers,
+			c.Contests, c.ContestProblems, c.ContestParticipants,
+			c.Solutions, c.ContestSolutions, c.ContestResults,
+		} {
+			if err := store.Sync(ctx); err != nil {
+				t.Fatal("Error:", err)
+			}
+		}
+	}
+	syncStores()
+	manager := NewContestExportManager(c, files)
+	var archive bytes.Buffer
+	if err := manager.ExportContest(ctx, contest.ID, &archive); err != nil {
+		t.Fatal("Error:", err)
+	}
+	imported, err := manager.ImportContest(ctx, &archive, 0)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if imported.ID == contest.ID || imported.Title != contest.Title {
+		t.Fatalf("Unexpected contest: %v", imported)
+	}
+	syncStores()
+	rows, err := c.ContestSolutions.FindByContest(ctx, imported.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	solutions, err := db.CollectRows(rows)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(solutions) != 1 {
+		t.Fatalf("Expected 1 solution, got %d", len(solutions))
+	}
+	importedSolution, err := c.Solutions.Get(ctx, solutions[0].ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if importedSolution.ContentID == 0 {
+		t.Fatal("Expected uploaded content")
+	}
+	if content, err := manager.readSolutionContent(ctx, importedSolution); err != nil {
+		t.Fatal("Error:", err)
+	} else if string(content) != string(solution.Content) {
+		t.Fatalf("Expected %q, got %q", solution.Content, content)
+	}
+	if report, err := importedSolution.GetReport(); err != nil {
+		t.Fatal("Error:", err)
+	} else if report == nil || report.Verdict != models.Accepted {
+		t.Fatalf("Unexpected report: %v", report)
+	}
+	resultRows, err := c.ContestResults.FindByContest(ctx, imported.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	results, err := db.CollectRows(resultRows)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if rows, err := results[0].GetResults(); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(rows) != 1 || rows[0].ParticipantID != solutions[0].ParticipantID {
+		t.Fatalf("Unexpected results: %v", rows)
+	}
+}
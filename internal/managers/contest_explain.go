@@ -0,0 +1,110 @@
+package managers
+
+import (
+	"slices"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// PermissionDenialReason represents source of missing permission.
+type PermissionDenialReason int
+
+const (
+	// RoleGraphDenial means that permission is not granted by roles
+	// of account.
+	RoleGraphDenial PermissionDenialReason = iota
+	// ContestStageDenial means that participant of account has
+	// permission only during other stages of contest.
+	ContestStageDenial
+	// ParticipantKindDenial means that permission is granted only to
+	// other kinds of participants during current stage of contest.
+	ParticipantKindDenial
+	// FinalizedContestDenial means that permission is revoked because
+	// contest is finalized.
+	FinalizedContestDenial
+)
+
+// String returns string representation.
+func (r PermissionDenialReason) String() string {
+	switch r {
+	case ContestStageDenial:
+		return "contest_stage"
+	case ParticipantKindDenial:
+		return "participant_kind"
+	case FinalizedContestDenial:
+		return "finalized"
+	default:
+		return "role_graph"
+	}
+}
+
+// PermissionExplanation describes why permission is missing.
+type PermissionExplanation struct {
+	Reason PermissionDenialReason
+	// Stages contains stages when participants of account would have
+	// permission.
+	Stages []ContestStage
+	// Kinds contains kinds of participants that would have permission
+	// during current stage.
+	Kinds []models.ParticipantKind
+}
+
+// PermissionExplainer represents permissions that can explain why
+// permission is missing.
+type PermissionExplainer interface {
+	perms.Permissions
+	ExplainPermission(name string) PermissionExplanation
+}
+
+var explainedContestStages = []ContestStage{
+	ContestNotStarted,
+	ContestStarted,
+	ContestFinished,
+}
+
+var explainedParticipantKinds = []models.ParticipantKind{
+	models.RegularParticipant,
+	models.UpsolvingParticipant,
+	models.VirtualParticipant,
+	models.ObserverParticipant,
+	models.ManagerParticipant,
+}
+
+// ExplainPermission recomputes participant permissions for other stages
+// and participant kinds to find why permission is missing.
+func (c *ContestContext) ExplainPermission(name string) PermissionExplanation {
+	if c.IsFinalized() && slices.Contains(finalizedContestPermissions, name) {
+		return PermissionExplanation{Reason: FinalizedContestDenial}
+	}
+	var explanation PermissionExplanation
+	for _, participant := range c.Participants {
+		for _, stage := range explainedContestStages {
+			permissions := perms.PermissionSet{}
+			addParticipantKindPermissions(permissions, participant.Kind, stage, &c.ContestConfig)
+			if permissions.HasPermission(name) && !slices.Contains(explanation.Stages, stage) {
+				explanation.Stages = append(explanation.Stages, stage)
+			}
+		}
+	}
+	if len(explanation.Stages) > 0 {
+		slices.Sort(explanation.Stages)
+		explanation.Reason = ContestStageDenial
+		return explanation
+	}
+	stage := c.GetContestTime().Stage()
+	for _, kind := range explainedParticipantKinds {
+		permissions := perms.PermissionSet{}
+		addParticipantKindPermissions(permissions, kind, stage, &c.ContestConfig)
+		if permissions.HasPermission(name) {
+			explanation.Kinds = append(explanation.Kinds, kind)
+		}
+	}
+	if len(explanation.Kinds) > 0 {
+		explanation.Reason = ParticipantKindDenial
+		return explanation
+	}
+	return PermissionExplanation{Reason: RoleGraphDenial}
+}
+
+var _ PermissionExplainer = (*ContestContext)(nil)
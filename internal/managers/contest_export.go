@@ -0,0 +1,204 @@
+package managers
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// ContestExportManager represents manager for exporting contest data.
+type ContestExportManager struct {
+	core  *core.Core
+	files *FileManager
+}
+
+// NewContestExportManager creates a new instance of
+// ContestExportManager.
+//
+// If files is nil, only solutions with inline content are exported.
+func NewContestExportManager(
+	core *core.Core, files *FileManager,
+) *ContestExportManager {
+	return &ContestExportManager{core: core, files: files}
+}
+
+// ExportSolutions writes zip archive with source files of all contest
+// solutions.
+//
+// Files are organized as "participant/problem/verdict/solution.ext".
+func (m *ContestExportManager) ExportSolutions(
+	ctx context.Context, contestID int64, w io.Writer,
+) error {
+	rows, err := m.core.ContestSolutions.FindByContest(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	// Solutions should be collected before downloading of files
+	// because rows hold lock of store.
+	solutions, err := db.CollectRows(rows)
+	if err != nil {
+		return err
+	}
+	archive := zip.NewWriter(w)
+	for _, contestSolution := range solutions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		solution, err := m.core.Solutions.Get(ctx, contestSolution.ID)
+		if err != nil {
+			m.core.Logger().Warn(
+				"Cannot find solution",
+				logs.Any("id", contestSolution.ID),
+				err,
+			)
+			continue
+		}
+		name := path.Join(
+			m.getParticipantName(ctx, contestSolution.ParticipantID),
+			m.getProblemName(ctx, contestSolution.ProblemID),
+			getSolutionVerdictName(solution),
+			fmt.Sprintf("%d%s", solution.ID, m.getSolutionExtension(ctx, solution)),
+		)
+		if err := m.writeSolution(ctx, archive, name, solution); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (m *ContestExportManager) writeSolution(
+	ctx context.Context, archive *zip.Writer, name string,
+	solution models.Solution,
+) error {
+	header := zip.FileHeader{Name: name, Method: zip.Deflate}
+	if solution.CreateTime > 0 {
+		header.Modified = time.Unix(solution.CreateTime, 0)
+	}
+	if solution.Content != "" {
+		file, err := archive.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(file, string(solution.Content))
+		return err
+	}
+	if solution.ContentID == 0 || m.files == nil {
+		return nil
+	}
+	content, err := m.files.DownloadFile(ctx, int64(solution.ContentID))
+	if err != nil {
+		m.core.Logger().Warn(
+			"Cannot download solution content",
+			logs.Any("id", solution.ID),
+			err,
+		)
+		return nil
+	}
+	defer func() { _ = content.Close() }()
+	file, err := archive.CreateHeader(&header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, content)
+	return err
+}
+
+func (m *ContestExportManager) getParticipantName(
+	ctx context.Context, participantID int64,
+) string {
+	name := "unknown"
+	if participant, err := m.core.ContestParticipants.Get(
+		ctx, participantID,
+	); err == nil {
+		name = m.getAccountName(ctx, participant.AccountID)
+	}
+	return fmt.Sprintf("%d-%s", participantID, sanitizeArchiveName(name))
+}
+
+func (m *ContestExportManager) getAccountName(
+	ctx context.Context, accountID int64,
+) string {
+	account, err := m.core.Accounts.Get(ctx, accountID)
+	if err != nil {
+		return "unknown"
+	}
+	switch account.Kind {
+	case models.UserAccountKind:
+		if user, err := m.core.Users.Get(ctx, account.ID); err == nil {
+			return user.Login
+		}
+	case models.ScopeUserAccountKind:
+		if user, err := m.core.ScopeUsers.Get(ctx, account.ID); err == nil {
+			return user.Login
+		}
+	case models.ScopeAccountKind:
+		if scope, err := m.core.Scopes.Get(ctx, account.ID); err == nil {
+			return scope.Title
+		}
+	case models.GroupAccountKind:
+		if group, err := m.core.Groups.Get(ctx, account.ID); err == nil {
+			return group.Title
+		}
+	}
+	return "unknown"
+}
+
+func (m *ContestExportManager) getProblemName(
+	ctx context.Context, problemID int64,
+) string {
+	problem, err := m.core.ContestProblems.Get(ctx, problemID)
+	if err != nil {
+		return fmt.Sprintf("problem-%d", problemID)
+	}
+	return sanitizeArchiveName(problem.Code)
+}
+
+func (m *ContestExportManager) getSolutionExtension(
+	ctx context.Context, solution models.Solution,
+) string {
+	compiler, err := m.core.Compilers.Get(ctx, solution.CompilerID)
+	if err != nil {
+		return ""
+	}
+	config, err := compiler.GetConfig()
+	if err != nil || len(config.Extensions) == 0 {
+		return ""
+	}
+	return "." + strings.TrimPrefix(config.Extensions[0], ".")
+}
+
+func getSolutionVerdictName(solution models.Solution) string {
+	report, err := solution.GetReport()
+	if err != nil || report == nil || report.Verdict == 0 {
+		return "pending"
+	}
+	return report.Verdict.String()
+}
+
+// sanitizeArchiveName replaces characters that are not allowed in
+// names of archive entries.
+func sanitizeArchiveName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,139 @@
+package managers
+
+import (
+	"context"
+	"time"
+
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+// finalizedContestPermissions contains permissions that are revoked
+// when contest is finalized.
+//
+// Submissions are still allowed, because upsolving does not
+// affect final results.
+var finalizedContestPermissions = []string{
+	perms.UpdateContestRole,
+	perms.RegisterContestRole,
+	perms.RegisterContestVirtualRole,
+	perms.RegisterContestObserverRole,
+	perms.DeregisterContestRole,
+	perms.CreateContestProblemRole,
+	perms.UpdateContestProblemRole,
+	perms.DeleteContestProblemRole,
+	perms.CreateContestParticipantRole,
+	perms.DeleteContestParticipantRole,
+	perms.CreateContestSolutionRole,
+	perms.UpdateContestSolutionRole,
+	perms.DeleteContestSolutionRole,
+	perms.CreateContestAppealRole,
+	perms.ResolveContestAppealRole,
+	perms.CreateContestScoreOverrideRole,
+	perms.DeleteContestScoreOverrideRole,
+}
+
+// IsFinalized returns true if contest results are finalized.
+func (c *ContestContext) IsFinalized() bool {
+	return c.ContestConfig.FinalizeTime != 0
+}
+
+// BuildResults computes final results of contest using unfrozen
+// official standings.
+func (m *ContestStandingsManager) BuildResults(
+	ctx *ContestContext,
+) ([]models.ContestResultRow, error) {
+	standings, err := m.BuildStandings(ctx, BuildStandingsOptions{
+		OnlyOfficial: true,
+		IgnoreFreeze: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := []models.ContestResultRow{}
+	for _, row := range standings.Rows {
+		result := models.ContestResultRow{
+			ParticipantID: row.Participant.ID,
+			Place:         row.Place,
+			Score:         row.Score,
+			Penalty:       row.Penalty,
+			Award:         getContestAward(ctx.ContestConfig.Awards, row),
+		}
+		if row.FakeParticipant != nil {
+			result.FakeParticipantID = row.FakeParticipant.ID
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// FinalizeContest saves final results of contest and marks config
+// as finalized.
+//
+// Contest config should be saved by caller in the same transaction.
+func (m *ContestStandingsManager) FinalizeContest(
+	ctx context.Context, contestCtx *ContestContext, config *models.ContestConfig,
+) (models.ContestResult, error) {
+	rows, err := m.BuildResults(contestCtx)
+	if err != nil {
+		return models.ContestResult{}, err
+	}
+	result := models.ContestResult{
+		ContestID:  contestCtx.Contest.ID,
+		CreateTime: contestCtx.Now.Unix(),
+	}
+	if account := contestCtx.Account; account != nil {
+		result.AuthorID = models.NInt64(account.ID)
+	}
+	if err := result.SetResults(rows); err != nil {
+		return models.ContestResult{}, err
+	}
+	if err := m.contestResults.Create(ctx, &result); err != nil {
+		return models.ContestResult{}, err
+	}
+	config.FinalizeTime = models.NInt64(contestCtx.Now.Unix())
+	return result, nil
+}
+
+// FinalizeContestResults finalizes contest on behalf of system.
+//
+// Used by scheduled contest actions.
+func (m *ContestStandingsManager) FinalizeContestResults(
+	ctx context.Context,
+	contest models.Contest,
+	config *models.ContestConfig,
+	now time.Time,
+) error {
+	contestCtx := &ContestContext{
+		AccountContext: &AccountContext{context: ctx},
+		Contest:        contest,
+		ContestConfig:  *config,
+		Permissions:    perms.PermissionSet{},
+		Now:            now,
+	}
+	// Final results are always built using unfrozen standings.
+	contestCtx.Permissions.AddPermission(perms.ObserveContestFullStandingsRole)
+	_, err := m.FinalizeContest(ctx, contestCtx, config)
+	return err
+}
+
+// getContestAward returns award for standings row.
+//
+// Participants without positive score never get awards.
+func getContestAward(
+	config *models.ContestAwardsConfig, row ContestStandingsRow,
+) models.ContestAward {
+	if config == nil || row.Score <= 0 || row.Score < config.MinScore {
+		return ""
+	}
+	switch {
+	case row.Place <= config.Gold:
+		return models.GoldContestAward
+	case row.Place <= config.Gold+config.Silver:
+		return models.SilverContestAward
+	case row.Place <= config.Gold+config.Silver+config.Bronze:
+		return models.BronzeContestAward
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,228 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/diff"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// defaultSimilarityThreshold contains default similarity in percents
+// that raises alert.
+const defaultSimilarityThreshold = 90
+
+// maxSimilarityContent contains maximal size of compared solution.
+const maxSimilarityContent = 64 * 1024
+
+// ContestSimilarityManager detects similar accepted solutions of
+// different participants during running contests.
+type ContestSimilarityManager struct {
+	core  *core.Core
+	files *FileManager
+}
+
+// NewContestSimilarityManager creates a new instance of
+// ContestSimilarityManager.
+//
+// If files is nil, only solutions with inline content are compared.
+func NewContestSimilarityManager(
+	core *core.Core, files *FileManager,
+) *ContestSimilarityManager {
+	return &ContestSimilarityManager{core: core, files: files}
+}
+
+// RunDetection consumes solution events and checks every newly
+// accepted contest solution.
+func (m *ContestSimilarityManager) RunDetection(ctx context.Context) {
+	beginID, err := getNextEventID(ctx, m.core.Solutions.Events())
+	if err != nil {
+		m.core.Logger().Error("Cannot start similarity detection", err)
+		return
+	}
+	consumer := db.NewEventConsumer[models.SolutionEvent](
+		m.core.Solutions.Events(), beginID,
+	)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var solutionIDs []int64
+		if err := consumer.ConsumeEvents(ctx, func(event models.SolutionEvent) error {
+			if event.Kind != models.ContestSolutionKind {
+				return nil
+			}
+			if report, err := event.GetReport(); err == nil && report != nil &&
+				report.Verdict == models.Accepted {
+				solutionIDs = append(solutionIDs, event.ID)
+			}
+			return nil
+		}); err != nil {
+			m.core.Logger().Warn("Cannot consume solution events", err)
+			continue
+		}
+		for _, id := range solutionIDs {
+			if err := m.CheckSolution(ctx, id); err != nil {
+				m.core.Logger().Warn(
+					"Cannot check solution similarity",
+					logs.Any("solution_id", id),
+					err,
+				)
+			}
+		}
+	}
+}
+
+// CheckSolution compares accepted solution with accepted solutions of
+// other participants for the same problem and creates alert message
+// when similarity exceeds threshold.
+//
+// Solutions are checked only while contest is running.
+func (m *ContestSimilarityManager) CheckSolution(ctx context.Context, id int64) error {
+	threshold, err := m.core.Settings.GetInt64("contests.similarity_threshold")
+	if err != nil {
+		return err
+	}
+	if threshold.OrElse(defaultSimilarityThreshold) <= 0 {
+		return nil
+	}
+	syncCtx := models.WithSync(ctx)
+	contestSolution, err := m.core.ContestSolutions.Get(syncCtx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	contest, err := m.core.Contests.Get(syncCtx, contestSolution.ContestID)
+	if err != nil {
+		return err
+	}
+	config, err := contest.GetConfig()
+	if err != nil {
+		return err
+	}
+	now := models.GetNow(ctx).Unix()
+	stage := getParticipantContestTime(&config, nil, now).Stage()
+	if stage != ContestStarted {
+		return nil
+	}
+	solution, err := m.core.Solutions.Get(syncCtx, id)
+	if err != nil {
+		return err
+	}
+	fingerprint := m.getFingerprint(ctx, solution)
+	if len(fingerprint) == 0 {
+		return nil
+	}
+	otherSolutions, err := m.core.ContestSolutions.FindByContest(syncCtx, contest.ID)
+	if err != nil {
+		return err
+	}
+	candidates, err := db.CollectRows(otherSolutions)
+	if err != nil {
+		return err
+	}
+	var bestID int64
+	var bestSimilarity float64
+	for _, other := range candidates {
+		if other.ID == id || other.ProblemID != contestSolution.ProblemID ||
+			other.ParticipantID == contestSolution.ParticipantID {
+			continue
+		}
+		otherSolution, err := m.core.Solutions.Get(ctx, other.ID)
+		if err != nil {
+			continue
+		}
+		report, err := otherSolution.GetReport()
+		if err != nil || report == nil || report.Verdict != models.Accepted {
+			continue
+		}
+		similarity := diff.Similarity(fingerprint, m.getFingerprint(ctx, otherSolution))
+		if similarity > bestSimilarity {
+			bestID, bestSimilarity = other.ID, similarity
+		}
+	}
+	percent := int64(bestSimilarity * 100)
+	if bestID == 0 || percent < threshold.OrElse(defaultSimilarityThreshold) {
+		return nil
+	}
+	message := models.ContestMessage{
+		ContestID:     contest.ID,
+		ParticipantID: models.NInt64(contestSolution.ParticipantID),
+		AuthorID:      solution.AuthorID,
+		Kind:          models.AlertContestMessage,
+		Title:         "Similar solutions",
+		Description: fmt.Sprintf(
+			"Solution %d is %d%% similar to solution %d.",
+			id, percent, bestID,
+		),
+		CreateTime: now,
+		ProblemID:  models.NInt64(contestSolution.ProblemID),
+	}
+	if err := m.core.ContestMessages.Create(ctx, &message); err != nil {
+		return err
+	}
+	m.core.Logger().Info(
+		"Similar solutions detected",
+		logs.Any("solution_id", id),
+		logs.Any("other_id", bestID),
+		logs.Any("similarity", percent),
+	)
+	return nil
+}
+
+func (m *ContestSimilarityManager) getFingerprint(
+	ctx context.Context, solution models.Solution,
+) []uint64 {
+	content := m.getContent(ctx, solution)
+	if content == "" {
+		return nil
+	}
+	var language string
+	if compiler, err := m.core.Compilers.Get(ctx, solution.CompilerID); err == nil {
+		if config, err := compiler.GetConfig(); err == nil {
+			language = config.Language
+		}
+	}
+	return diff.Fingerprint(content, language)
+}
+
+func (m *ContestSimilarityManager) getContent(
+	ctx context.Context, solution models.Solution,
+) string {
+	if solution.Content != "" {
+		if s := string(solution.Content); utf8.ValidString(s) {
+			return s
+		}
+		return ""
+	}
+	if solution.ContentID == 0 || m.files == nil {
+		return ""
+	}
+	file, err := m.files.DownloadFile(ctx, int64(solution.ContentID))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+	var content bytes.Buffer
+	if _, err := io.CopyN(&content, file, maxSimilarityContent); err != nil && err != io.EOF {
+		return ""
+	}
+	if s := content.String(); utf8.ValidString(s) {
+		return s
+	}
+	return ""
+}
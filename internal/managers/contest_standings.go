@@ -1,15 +1,18 @@
 package managers
 
 import (
+	"context"
 	"database/sql"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/udovin/solve/internal/core"
 	"github.com/udovin/solve/internal/db"
 	"github.com/udovin/solve/internal/models"
 	"github.com/udovin/solve/internal/perms"
+	"github.com/udovin/solve/internal/pkg/logs"
 )
 
 type ContestStandingsColumn struct {
@@ -24,6 +27,11 @@ type ContestStandingsCell struct {
 	Points  float64
 	Attempt int
 	Time    int64
+	// FrozenAttempts contains amount of attempts that are made
+	// during freeze.
+	FrozenAttempts int
+	// Frozen means that cell contains attempts with hidden verdict.
+	Frozen bool
 }
 
 type ContestStandingsRow struct {
@@ -43,33 +51,77 @@ type ContestStandings struct {
 }
 
 type ContestStandingsManager struct {
+	contests                *models.ContestStore
 	contestParticipants     *models.ContestParticipantStore
 	contestSolutions        *models.ContestSolutionStore
 	contestProblems         *models.ContestProblemStore
 	contestFakeParticipants *models.ContestFakeParticipantStore
 	contestFakeSolutions    *models.ContestFakeSolutionStore
+	contestScoreOverrides   *models.ContestScoreOverrideStore
+	contestResults          *models.ContestResultStore
 	solutions               *models.SolutionStore
+	scopeUsers              *models.ScopeUserStore
 	settings                *models.SettingStore
+	logger                  *logs.Logger
 	cache                   map[standingsCacheKey]*standingsCache
+	guestCache              map[int64]*guestStandingsCache
 	mutex                   sync.Mutex
+	aggregates              map[int64]*standingsAggregate
+	aggregatesMutex         sync.Mutex
+	consuming               atomic.Bool
 }
 
 func NewContestStandingsManager(core *core.Core) *ContestStandingsManager {
 	return &ContestStandingsManager{
+		contests:                core.Contests,
 		contestParticipants:     core.ContestParticipants,
 		contestSolutions:        core.ContestSolutions,
 		contestProblems:         core.ContestProblems,
 		contestFakeParticipants: core.ContestFakeParticipants,
 		contestFakeSolutions:    core.ContestFakeSolutions,
+		contestScoreOverrides:   core.ContestScoreOverrides,
+		contestResults:          core.ContestResults,
 		settings:                core.Settings,
 		solutions:               core.Solutions,
+		scopeUsers:              core.ScopeUsers,
+		logger:                  core.Logger(),
 		cache:                   map[standingsCacheKey]*standingsCache{},
+		guestCache:              map[int64]*guestStandingsCache{},
+		aggregates:              map[int64]*standingsAggregate{},
 	}
 }
 
 type BuildStandingsOptions struct {
 	OnlyOfficial bool
 	IgnoreFreeze bool
+	// Kind contains kind of participants that should be kept.
+	//
+	// Zero value means all participants.
+	Kind models.ParticipantKind
+	// ScopeID contains ID of scope for local standings.
+	//
+	// Zero value means all participants.
+	ScopeID int64
+	// Label contains label of participants that should be kept.
+	//
+	// Zero value means all participants.
+	Label ParticipantLabel
+}
+
+// ParticipantLabel represents label of participant with its value.
+type ParticipantLabel struct {
+	Name  string
+	Value string
+}
+
+// IsZero returns true if label is not specified.
+func (l ParticipantLabel) IsZero() bool {
+	return l.Name == ""
+}
+
+// Match returns true if participant has label.
+func (l ParticipantLabel) Match(participant models.ContestParticipant) bool {
+	return l.IsZero() || participant.HasLabel(l.Name, l.Value)
 }
 
 func (m *ContestStandingsManager) BuildStandings(
@@ -82,6 +134,55 @@ func (m *ContestStandingsManager) BuildStandings(
 	return standings, err
 }
 
+// BuildParticipantStandings returns standings that contain only row
+// of effective participant.
+//
+// Place of participant is calculated over shared cached standings, so
+// full table is not copied. If participant does not pass filters of
+// options, then row is returned without place.
+func (m *ContestStandingsManager) BuildParticipantStandings(
+	ctx *ContestContext, options BuildStandingsOptions,
+) (*ContestStandings, error) {
+	participant := ctx.GetEffectiveParticipant()
+	if participant == nil {
+		return nil, sql.ErrNoRows
+	}
+	standings, err := m.buildStandings(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	result := ContestStandings{
+		Stage:  standings.Stage,
+		Frozen: standings.Frozen,
+	}
+	for _, column := range standings.Columns {
+		result.Columns = append(result.Columns, ContestStandingsColumn{
+			Problem: column.Problem,
+		})
+	}
+	row := ContestStandingsRow{Participant: *participant}
+	found := false
+	for _, other := range standings.Rows {
+		if other.FakeParticipant == nil && other.Participant.ID == participant.ID {
+			row, found = other, true
+			break
+		}
+	}
+	// Participant without attempts is not placed in standings.
+	if found && isPlacedParticipant(row.Participant.Kind) &&
+		m.isFilteredRow(ctx, options, row) {
+		row.Place = 1
+		for _, other := range standings.Rows {
+			if isPlacedParticipant(other.Participant.Kind) &&
+				participantLess(other, row) && m.isFilteredRow(ctx, options, other) {
+				row.Place++
+			}
+		}
+	}
+	result.Rows = append(result.Rows, row)
+	return &result, nil
+}
+
 func (m *ContestStandingsManager) processStandings(
 	ctx *ContestContext, options BuildStandingsOptions, standings *ContestStandings,
 ) *ContestStandings {
@@ -96,7 +197,7 @@ func (m *ContestStandingsManager) processStandings(
 	}
 	observeFullStandings := ctx.HasPermission(perms.ObserveContestFullStandingsRole)
 	for _, row := range standings.Rows {
-		if options.OnlyOfficial && row.Participant.Kind != models.RegularParticipant {
+		if !m.isFilteredRow(ctx, options, row) {
 			continue
 		}
 		if !observeFullStandings {
@@ -121,6 +222,39 @@ func (m *ContestStandingsManager) processStandings(
 	return &processed
 }
 
+// isFilteredRow returns true if row passes filters of options.
+func (m *ContestStandingsManager) isFilteredRow(
+	ctx *ContestContext, options BuildStandingsOptions, row ContestStandingsRow,
+) bool {
+	if options.OnlyOfficial && row.Participant.Kind != models.RegularParticipant {
+		return false
+	}
+	if options.Kind != 0 && row.Participant.Kind != options.Kind {
+		return false
+	}
+	if options.ScopeID != 0 && !m.isScopeParticipant(ctx, row, options.ScopeID) {
+		return false
+	}
+	if !options.Label.IsZero() &&
+		(row.FakeParticipant != nil || !options.Label.Match(row.Participant)) {
+		return false
+	}
+	return true
+}
+
+func (m *ContestStandingsManager) isScopeParticipant(
+	ctx *ContestContext, row ContestStandingsRow, scopeID int64,
+) bool {
+	if row.FakeParticipant != nil {
+		return false
+	}
+	user, err := m.scopeUsers.Get(ctx, row.Participant.AccountID)
+	if err != nil {
+		return false
+	}
+	return user.ScopeID == scopeID
+}
+
 func (m *ContestStandingsManager) buildStandings(ctx *ContestContext, options BuildStandingsOptions) (*ContestStandings, error) {
 	useCache, err := m.settings.GetBool("standings.use_cache")
 	if err != nil || !useCache.OrElse(true) {
@@ -136,7 +270,7 @@ func (m *ContestStandingsManager) buildStandings(ctx *ContestContext, options Bu
 	if ok {
 		select {
 		case <-cache.Done:
-			if cache.Error == nil && time.Since(cache.Time) < 15*time.Second {
+			if cache.Error == nil && cache.IsFresh(ctx.Now) {
 				m.mutex.Unlock()
 				return cache.Standings, nil
 			}
@@ -148,18 +282,257 @@ func (m *ContestStandingsManager) buildStandings(ctx *ContestContext, options Bu
 	}
 	done := make(chan struct{})
 	defer close(done)
-	cache = &standingsCache{Done: done, Time: ctx.Now}
+	cache = &standingsCache{
+		Done:       done,
+		Time:       ctx.Now,
+		ExpireTime: getStandingsExpireTime(ctx, key.BeginTime),
+	}
 	m.cache[key] = cache
 	m.mutex.Unlock()
 	cache.Standings, cache.Error = m.doBuildStandings(ctx, options)
 	return cache.Standings, cache.Error
 }
 
+// standingsCacheTTL contains maximal lifetime of cached standings.
+//
+// Cached standings are invalidated by events, so TTL only protects
+// from missed events.
+const standingsCacheTTL = time.Minute
+
 type standingsCache struct {
 	Done      <-chan struct{}
 	Time      time.Time
 	Standings *ContestStandings
 	Error     error
+	// ExpireTime contains time when contest stage changes.
+	ExpireTime int64
+}
+
+func (c *standingsCache) IsFresh(now time.Time) bool {
+	return isStandingsCacheFresh(c.Time, c.ExpireTime, now)
+}
+
+func isStandingsCacheFresh(cacheTime time.Time, expireTime int64, now time.Time) bool {
+	if expireTime != 0 && now.Unix() >= expireTime {
+		return false
+	}
+	return time.Since(cacheTime) < standingsCacheTTL
+}
+
+// getStandingsExpireTime returns nearest time point when standings
+// can be changed without any events (freeze begin, contest end, etc.).
+func getStandingsExpireTime(ctx *ContestContext, beginTime int64) int64 {
+	now := ctx.Now.Unix()
+	config := ctx.ContestConfig
+	var times []int64
+	if beginTime != 0 {
+		times = append(times, beginTime, beginTime+int64(config.Duration))
+		if config.FreezeBeginDuration != 0 {
+			times = append(times, beginTime+int64(config.FreezeBeginDuration))
+		}
+	}
+	if config.FreezeEndTime != 0 {
+		times = append(times, int64(config.FreezeEndTime))
+	}
+	var expireTime int64
+	for _, t := range times {
+		if t > now && (expireTime == 0 || t < expireTime) {
+			expireTime = t
+		}
+	}
+	return expireTime
+}
+
+// InvalidateContest removes all cached standings of specified contests.
+func (m *ContestStandingsManager) InvalidateContest(contestIDs ...int64) {
+	if len(contestIDs) == 0 {
+		return
+	}
+	ids := map[int64]struct{}{}
+	for _, id := range contestIDs {
+		ids[id] = struct{}{}
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for key := range m.cache {
+		if _, ok := ids[key.ContestID]; ok {
+			delete(m.cache, key)
+		}
+	}
+	for id := range ids {
+		delete(m.guestCache, id)
+	}
+}
+
+// RunInvalidation consumes events of stores related to standings,
+// updates aggregated solutions and invalidates cached standings of
+// affected contests.
+//
+// This function blocks until context is canceled.
+func (m *ContestStandingsManager) RunInvalidation(ctx context.Context) {
+	consumers, err := m.newStandingsConsumers(ctx)
+	if err != nil {
+		m.logger.Error("Cannot start standings invalidation", err)
+		return
+	}
+	m.consuming.Store(true)
+	defer func() {
+		m.consuming.Store(false)
+		m.aggregatesMutex.Lock()
+		defer m.aggregatesMutex.Unlock()
+		m.aggregates = map[int64]*standingsAggregate{}
+	}()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := consumers.consume(ctx, m); err != nil {
+				m.logger.Warn("Cannot consume standings events", err)
+			}
+		}
+	}
+}
+
+type standingsConsumers struct {
+	contests            db.EventConsumer[models.ContestEvent, *models.ContestEvent]
+	contestProblems     db.EventConsumer[models.ContestProblemEvent, *models.ContestProblemEvent]
+	contestParticipants db.EventConsumer[models.ContestParticipantEvent, *models.ContestParticipantEvent]
+	contestSolutions    db.EventConsumer[models.ContestSolutionEvent, *models.ContestSolutionEvent]
+	solutions           db.EventConsumer[models.SolutionEvent, *models.SolutionEvent]
+	scoreOverrides      db.EventConsumer[models.ContestScoreOverrideEvent, *models.ContestScoreOverrideEvent]
+}
+
+func getNextEventID[T any](ctx context.Context, events db.EventROStore[T]) (int64, error) {
+	id, err := events.LastEventID(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 1, nil
+		}
+		return 0, err
+	}
+	return id + 1, nil
+}
+
+func newStandingsConsumer[T any, TPtr db.EventPtr[T]](
+	ctx context.Context, events db.EventROStore[T],
+) (db.EventConsumer[T, TPtr], error) {
+	beginID, err := getNextEventID(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewEventConsumer[T, TPtr](events, beginID), nil
+}
+
+func (m *ContestStandingsManager) newStandingsConsumers(
+	ctx context.Context,
+) (*standingsConsumers, error) {
+	var c standingsConsumers
+	var err error
+	if c.contests, err = newStandingsConsumer[models.ContestEvent](
+		ctx, m.contests.Events(),
+	); err != nil {
+		return nil, err
+	}
+	if c.contestProblems, err = newStandingsConsumer[models.ContestProblemEvent](
+		ctx, m.contestProblems.Events(),
+	); err != nil {
+		return nil, err
+	}
+	if c.contestParticipants, err = newStandingsConsumer[models.ContestParticipantEvent](
+		ctx, m.contestParticipants.Events(),
+	); err != nil {
+		return nil, err
+	}
+	if c.contestSolutions, err = newStandingsConsumer[models.ContestSolutionEvent](
+		ctx, m.contestSolutions.Events(),
+	); err != nil {
+		return nil, err
+	}
+	if c.solutions, err = newStandingsConsumer[models.SolutionEvent](
+		ctx, m.solutions.Events(),
+	); err != nil {
+		return nil, err
+	}
+	if c.scoreOverrides, err = newStandingsConsumer[models.ContestScoreOverrideEvent](
+		ctx, m.contestScoreOverrides.Events(),
+	); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *standingsConsumers) consume(ctx context.Context, m *ContestStandingsManager) error {
+	var contestIDs, deletedContestIDs []int64
+	if err := c.contests.ConsumeEvents(ctx, func(event models.ContestEvent) error {
+		contestIDs = append(contestIDs, event.ID)
+		if event.EventKind() == models.DeleteEvent {
+			deletedContestIDs = append(deletedContestIDs, event.ID)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := c.contestProblems.ConsumeEvents(ctx, func(event models.ContestProblemEvent) error {
+		contestIDs = append(contestIDs, event.ContestID)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := c.contestParticipants.ConsumeEvents(ctx, func(event models.ContestParticipantEvent) error {
+		contestIDs = append(contestIDs, event.ContestID)
+		return nil
+	}); err != nil {
+		return err
+	}
+	var solutionIDs []int64
+	if err := c.contestSolutions.ConsumeEvents(ctx, func(event models.ContestSolutionEvent) error {
+		contestIDs = append(contestIDs, event.ContestID)
+		solutionIDs = append(solutionIDs, event.ID)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := c.solutions.ConsumeEvents(ctx, func(event models.SolutionEvent) error {
+		if event.Kind == models.ContestSolutionKind {
+			solutionIDs = append(solutionIDs, event.ID)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := c.scoreOverrides.ConsumeEvents(ctx, func(event models.ContestScoreOverrideEvent) error {
+		contestIDs = append(contestIDs, event.ContestID)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(contestIDs) == 0 && len(solutionIDs) == 0 {
+		return nil
+	}
+	// Standings are built from cached stores, so we should be sure that
+	// stores contain consumed events before we drop the cache.
+	syncCtx := models.WithSync(ctx)
+	for _, store := range []models.CachedStore{
+		m.contests, m.contestProblems, m.contestParticipants,
+		m.contestSolutions, m.solutions, m.contestScoreOverrides,
+	} {
+		if err := store.Sync(syncCtx); err != nil {
+			return err
+		}
+	}
+	for _, id := range deletedContestIDs {
+		m.removeAggregate(id)
+	}
+	changedContestIDs, err := m.updateAggregates(ctx, solutionIDs)
+	if err != nil {
+		return err
+	}
+	contestIDs = append(contestIDs, changedContestIDs...)
+	m.InvalidateContest(contestIDs...)
+	return nil
 }
 
 type standingsCacheKey struct {
@@ -195,24 +568,9 @@ func (m *ContestStandingsManager) doBuildStandings(
 	if err != nil {
 		return nil, err
 	}
-	sortFunc(contestProblems, func(lhs, rhs models.ContestProblem) bool {
-		return lhs.Code < rhs.Code
-	})
-	solutionsByParticipant := map[int64][]models.ContestSolution{}
-	if err := func() error {
-		solutions, err := m.contestSolutions.FindByContest(ctx, ctx.Contest.ID)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = solutions.Close() }()
-		for solutions.Next() {
-			solution := solutions.Row()
-			solutionsByParticipant[solution.ParticipantID] = append(
-				solutionsByParticipant[solution.ParticipantID], solution,
-			)
-		}
-		return solutions.Err()
-	}(); err != nil {
+	models.SortContestProblems(contestProblems)
+	aggregate, err := m.getAggregate(ctx, ctx.Contest.ID)
+	if err != nil {
 		return nil, err
 	}
 	fakeSolutionsByParticipant := map[int64][]models.ContestFakeSolution{}
@@ -232,28 +590,103 @@ func (m *ContestStandingsManager) doBuildStandings(
 	}(); err != nil {
 		return nil, err
 	}
+	overrides, err := m.getScoreOverrides(ctx, ctx.Contest.ID)
+	if err != nil {
+		return nil, err
+	}
 	switch ctx.ContestConfig.StandingsKind {
 	case models.IOIStandings:
 		return m.buildIOIStandings(
 			ctx, options, contestProblems,
-			participants, solutionsByParticipant,
+			participants, aggregate, overrides,
 			fakeParticipants, fakeSolutionsByParticipant,
 		)
 	default:
 		return m.buildICPCStandings(
 			ctx, options, contestProblems,
-			participants, solutionsByParticipant,
+			participants, aggregate, overrides,
 			fakeParticipants, fakeSolutionsByParticipant,
 		)
 	}
 }
 
+// scoreOverrides contains manual overrides of standings cells.
+type scoreOverrides struct {
+	cells        map[standingsCellKey]models.ContestScoreOverrideValue
+	participants map[int64]struct{}
+}
+
+type standingsCellKey struct {
+	ParticipantID int64
+	ProblemID     int64
+}
+
+func (o scoreOverrides) HasParticipant(participantID int64) bool {
+	_, ok := o.participants[participantID]
+	return ok
+}
+
+func (o scoreOverrides) Get(participantID, problemID int64) (models.ContestScoreOverrideValue, bool) {
+	value, ok := o.cells[standingsCellKey{
+		ParticipantID: participantID,
+		ProblemID:     problemID,
+	}]
+	return value, ok
+}
+
+func (m *ContestStandingsManager) getScoreOverrides(
+	ctx context.Context, contestID int64,
+) (scoreOverrides, error) {
+	overrides := scoreOverrides{
+		cells:        map[standingsCellKey]models.ContestScoreOverrideValue{},
+		participants: map[int64]struct{}{},
+	}
+	rows, err := m.contestScoreOverrides.FindByContest(ctx, contestID)
+	if err != nil {
+		return overrides, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		override := rows.Row()
+		value, err := override.GetValue()
+		if err != nil {
+			continue
+		}
+		overrides.cells[standingsCellKey{
+			ParticipantID: override.ParticipantID,
+			ProblemID:     override.ProblemID,
+		}] = value
+		overrides.participants[override.ParticipantID] = struct{}{}
+	}
+	return overrides, rows.Err()
+}
+
+// applyScoreOverride replaces computed values of cell with manual
+// override of jury.
+func applyScoreOverride(
+	ctx *ContestContext, cell *ContestStandingsCell,
+	override models.ContestScoreOverrideValue, frozen bool,
+) {
+	// Overridden cell should be displayed even without attempts.
+	cell.Attempt = max(cell.Attempt, 1)
+	if frozen && isVerdictFrozen(ctx, cell.Time) {
+		return
+	}
+	if override.Verdict != 0 {
+		cell.Verdict = override.Verdict
+	}
+	if override.Points != nil {
+		cell.Points = *override.Points
+	}
+}
+
 func (m *ContestStandingsManager) buildICPCStandings(
 	ctx *ContestContext,
 	options BuildStandingsOptions,
 	contestProblems []models.ContestProblem,
 	participants []models.ContestParticipant,
-	solutionsByParticipant map[int64][]models.ContestSolution,
+	aggregate *standingsAggregate,
+	overrides scoreOverrides,
 	fakeParticipants []models.ContestFakeParticipant,
 	fakeSolutionsByParticipant map[int64][]models.ContestFakeSolution,
 ) (*ContestStandings, error) {
@@ -270,52 +703,30 @@ func (m *ContestStandingsManager) buildICPCStandings(
 	contestTime := ctx.GetEffectiveContestTime()
 	standings.Stage = contestTime.Stage()
 	standings.Frozen = !ignoreFreeze && isContestFrozen(ctx, contestTime)
+	penaltyTime := getPenaltyTime(ctx)
 	for _, participant := range participants {
 		beginTime := getParticipantBeginTime(&ctx.ContestConfig, &participant)
-		participantSolutions, ok := solutionsByParticipant[participant.ID]
-		if !ok {
+		if !aggregate.HasParticipant(participant.ID) &&
+			!overrides.HasParticipant(participant.ID) {
 			continue
 		}
-		solutionsByColumn := map[int][]models.Solution{}
-		for _, participantSolution := range participantSolutions {
-			solution, err := m.solutions.Get(ctx, participantSolution.ID)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					continue
-				}
-				return nil, err
-			}
-			column, ok := columnByProblem[participantSolution.ProblemID]
-			if !ok {
-				continue
-			}
-			solutionsByColumn[column] = append(solutionsByColumn[column], solution)
-		}
 		row := ContestStandingsRow{
 			Participant: participant,
 		}
-		for i := range standings.Columns {
-			solutions, ok := solutionsByColumn[i]
-			if !ok {
+		for i, column := range standings.Columns {
+			attempts := aggregate.Attempts(participant.ID, column.Problem.ID)
+			override, hasOverride := overrides.Get(participant.ID, column.Problem.ID)
+			if len(attempts) == 0 && !hasOverride {
 				continue
 			}
-			sortFunc(solutions, func(lhs, rhs models.Solution) bool {
-				if lhs.CreateTime != rhs.CreateTime {
-					return lhs.CreateTime < rhs.CreateTime
-				}
-				return lhs.ID < rhs.ID
-			})
 			cell := ContestStandingsCell{
 				Column: i,
 			}
-			for _, solution := range solutions {
-				if solution.CreateTime >= ctx.Now.Unix() {
-					continue
-				}
-				report, err := solution.GetReport()
-				if err != nil {
+			for _, attempt := range attempts {
+				if attempt.Broken || attempt.CreateTime >= ctx.Now.Unix() {
 					continue
 				}
+				report := attempt.Report
 				if report == nil {
 					cell.Attempt++
 					cell.Verdict = 0
@@ -326,20 +737,30 @@ func (m *ContestStandingsManager) buildICPCStandings(
 				}
 				cell.Attempt++
 				if beginTime != 0 {
-					cell.Time = solution.CreateTime - beginTime
+					cell.Time = attempt.CreateTime - beginTime
 					if cell.Time < 0 {
 						cell.Time = 0
 					}
 				}
 				cell.Verdict = report.Verdict
-				if standings.Frozen && isVerdictFrozen(ctx, cell.Time) {
-					cell.Verdict = 0
+				if isVerdictFrozen(ctx, cell.Time) {
+					cell.FrozenAttempts++
+					if standings.Frozen {
+						cell.Verdict = 0
+						cell.Frozen = true
+					}
 				}
 				if report.Verdict == models.Accepted {
 					break
 				}
 			}
-			if cell.Attempt > 0 {
+			if cell.Frozen && ctx.ContestConfig.HideFrozenAttempts {
+				hideFrozenAttempts(&cell)
+			}
+			if hasOverride {
+				applyScoreOverride(ctx, &cell, override, standings.Frozen)
+			}
+			if cell.Attempt > 0 || cell.Frozen {
 				row.Cells = append(row.Cells, cell)
 			}
 		}
@@ -348,7 +769,7 @@ func (m *ContestStandingsManager) buildICPCStandings(
 			column := &standings.Columns[cell.Column]
 			if cell.Verdict == models.Accepted {
 				row.Score += getProblemScore(column.Problem)
-				penalty += int64(cell.Attempt-1)*20 + cell.Time/60
+				penalty += int64(cell.Attempt-1)*penaltyTime + cell.Time/60
 			}
 		}
 		if isPlacedParticipant(participant.Kind) {
@@ -408,14 +829,21 @@ func (m *ContestStandingsManager) buildICPCStandings(
 				cell.Attempt++
 				cell.Time = solution.ContestTime
 				cell.Verdict = report.Verdict
-				if standings.Frozen && isVerdictFrozen(ctx, cell.Time) {
-					cell.Verdict = 0
+				if isVerdictFrozen(ctx, cell.Time) {
+					cell.FrozenAttempts++
+					if standings.Frozen {
+						cell.Verdict = 0
+						cell.Frozen = true
+					}
 				}
 				if report.Verdict == models.Accepted {
 					break
 				}
 			}
-			if cell.Attempt > 0 {
+			if cell.Frozen && ctx.ContestConfig.HideFrozenAttempts {
+				hideFrozenAttempts(&cell)
+			}
+			if cell.Attempt > 0 || cell.Frozen {
 				row.Cells = append(row.Cells, cell)
 			}
 		}
@@ -424,7 +852,7 @@ func (m *ContestStandingsManager) buildICPCStandings(
 			column := &standings.Columns[cell.Column]
 			if cell.Verdict == models.Accepted {
 				row.Score += getProblemScore(column.Problem)
-				penalty += int64(cell.Attempt-1)*20 + cell.Time/60
+				penalty += int64(cell.Attempt-1)*penaltyTime + cell.Time/60
 			}
 		}
 		row.Penalty = &penalty
@@ -439,7 +867,8 @@ func (m *ContestStandingsManager) buildIOIStandings(
 	options BuildStandingsOptions,
 	contestProblems []models.ContestProblem,
 	participants []models.ContestParticipant,
-	solutionsByParticipant map[int64][]models.ContestSolution,
+	aggregate *standingsAggregate,
+	overrides scoreOverrides,
 	fakeParticipants []models.ContestFakeParticipant,
 	fakeSolutionsByParticipant map[int64][]models.ContestFakeSolution,
 ) (*ContestStandings, error) {
@@ -458,50 +887,27 @@ func (m *ContestStandingsManager) buildIOIStandings(
 	standings.Frozen = !ignoreFreeze && isContestFrozen(ctx, contestTime)
 	for _, participant := range participants {
 		beginTime := getParticipantBeginTime(&ctx.ContestConfig, &participant)
-		participantSolutions, ok := solutionsByParticipant[participant.ID]
-		if !ok {
+		if !aggregate.HasParticipant(participant.ID) &&
+			!overrides.HasParticipant(participant.ID) {
 			continue
 		}
-		solutionsByColumn := map[int][]models.Solution{}
-		for _, participantSolution := range participantSolutions {
-			solution, err := m.solutions.Get(ctx, participantSolution.ID)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					continue
-				}
-				return nil, err
-			}
-			column, ok := columnByProblem[participantSolution.ProblemID]
-			if !ok {
-				continue
-			}
-			solutionsByColumn[column] = append(solutionsByColumn[column], solution)
-		}
 		row := ContestStandingsRow{
 			Participant: participant,
 		}
-		for i := range standings.Columns {
-			solutions, ok := solutionsByColumn[i]
-			if !ok {
+		for i, column := range standings.Columns {
+			attempts := aggregate.Attempts(participant.ID, column.Problem.ID)
+			override, hasOverride := overrides.Get(participant.ID, column.Problem.ID)
+			if len(attempts) == 0 && !hasOverride {
 				continue
 			}
-			sortFunc(solutions, func(lhs, rhs models.Solution) bool {
-				if lhs.CreateTime != rhs.CreateTime {
-					return lhs.CreateTime < rhs.CreateTime
-				}
-				return lhs.ID < rhs.ID
-			})
 			cell := ContestStandingsCell{
 				Column: i,
 			}
-			for _, solution := range solutions {
-				if solution.CreateTime >= ctx.Now.Unix() {
-					continue
-				}
-				report, err := solution.GetReport()
-				if err != nil {
+			for _, attempt := range attempts {
+				if attempt.Broken || attempt.CreateTime >= ctx.Now.Unix() {
 					continue
 				}
+				report := attempt.Report
 				if report == nil {
 					cell.Attempt++
 					cell.Verdict = 0
@@ -512,7 +918,7 @@ func (m *ContestStandingsManager) buildIOIStandings(
 				}
 				cell.Attempt++
 				if beginTime != 0 {
-					cell.Time = solution.CreateTime - beginTime
+					cell.Time = attempt.CreateTime - beginTime
 					if cell.Time < 0 {
 						cell.Time = 0
 					}
@@ -529,6 +935,9 @@ func (m *ContestStandingsManager) buildIOIStandings(
 					}
 				}
 			}
+			if hasOverride {
+				applyScoreOverride(ctx, &cell, override, standings.Frozen)
+			}
 			if cell.Attempt > 0 {
 				row.Cells = append(row.Cells, cell)
 			}
@@ -645,6 +1054,26 @@ func calculatePlaces(rows []ContestStandingsRow) {
 	}
 }
 
+// defaultPenaltyTime contains default penalty in minutes for rejected
+// attempt in ICPC standings.
+const defaultPenaltyTime = 20
+
+func getPenaltyTime(ctx *ContestContext) int64 {
+	if ctx.ContestConfig.PenaltyTime != nil {
+		return int64(*ctx.ContestConfig.PenaltyTime)
+	}
+	return defaultPenaltyTime
+}
+
+// hideFrozenAttempts removes attempts made during freeze from cell,
+// so only the fact of such attempts is visible.
+func hideFrozenAttempts(cell *ContestStandingsCell) {
+	cell.Attempt -= cell.FrozenAttempts
+	cell.FrozenAttempts = 0
+	// Time of last attempt is also made during freeze.
+	cell.Time = 0
+}
+
 func isVerdictFrozen(
 	ctx *ContestContext, time int64,
 ) bool {
@@ -0,0 +1,230 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+// standingsAttempt represents preprocessed contest solution.
+type standingsAttempt struct {
+	SolutionID int64
+	CreateTime int64
+	// Report contains solution report or nil if solution is not judged yet.
+	Report *models.SolutionReport
+	// Broken means that solution is missing or has invalid report.
+	Broken bool
+}
+
+func attemptLess(lhs, rhs standingsAttempt) bool {
+	if lhs.CreateTime != rhs.CreateTime {
+		return lhs.CreateTime < rhs.CreateTime
+	}
+	return lhs.SolutionID < rhs.SolutionID
+}
+
+type standingsAggregateKey struct {
+	ParticipantID int64
+	ProblemID     int64
+}
+
+// standingsAggregate contains attempts of contest participants grouped
+// by participant and problem.
+//
+// Slices of attempts are never modified in place, so returned slices
+// can be safely used without holding the lock.
+type standingsAggregate struct {
+	attempts     map[standingsAggregateKey][]standingsAttempt
+	keys         map[int64]standingsAggregateKey
+	participants map[int64]int
+	mutex        sync.RWMutex
+}
+
+func newStandingsAggregate() *standingsAggregate {
+	return &standingsAggregate{
+		attempts:     map[standingsAggregateKey][]standingsAttempt{},
+		keys:         map[int64]standingsAggregateKey{},
+		participants: map[int64]int{},
+	}
+}
+
+// HasParticipant returns true if participant has at least one solution.
+func (a *standingsAggregate) HasParticipant(participantID int64) bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.participants[participantID] > 0
+}
+
+// Attempts returns attempts of participant for specified contest problem
+// sorted by create time.
+func (a *standingsAggregate) Attempts(participantID, problemID int64) []standingsAttempt {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.attempts[standingsAggregateKey{
+		ParticipantID: participantID,
+		ProblemID:     problemID,
+	}]
+}
+
+func (a *standingsAggregate) update(key standingsAggregateKey, attempt standingsAttempt) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.removeLocked(attempt.SolutionID)
+	attempts := a.attempts[key]
+	pos := sort.Search(len(attempts), func(i int) bool {
+		return attemptLess(attempt, attempts[i])
+	})
+	newAttempts := make([]standingsAttempt, 0, len(attempts)+1)
+	newAttempts = append(newAttempts, attempts[:pos]...)
+	newAttempts = append(newAttempts, attempt)
+	newAttempts = append(newAttempts, attempts[pos:]...)
+	a.attempts[key] = newAttempts
+	a.keys[attempt.SolutionID] = key
+	a.participants[key.ParticipantID]++
+}
+
+func (a *standingsAggregate) remove(solutionID int64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.removeLocked(solutionID)
+}
+
+func (a *standingsAggregate) removeLocked(solutionID int64) {
+	key, ok := a.keys[solutionID]
+	if !ok {
+		return
+	}
+	delete(a.keys, solutionID)
+	if a.participants[key.ParticipantID]--; a.participants[key.ParticipantID] <= 0 {
+		delete(a.participants, key.ParticipantID)
+	}
+	attempts := a.attempts[key]
+	var newAttempts []standingsAttempt
+	for _, attempt := range attempts {
+		if attempt.SolutionID != solutionID {
+			newAttempts = append(newAttempts, attempt)
+		}
+	}
+	if len(newAttempts) > 0 {
+		a.attempts[key] = newAttempts
+	} else {
+		delete(a.attempts, key)
+	}
+}
+
+func (m *ContestStandingsManager) makeStandingsAttempt(
+	ctx context.Context, contestSolution models.ContestSolution,
+) (standingsAttempt, error) {
+	attempt := standingsAttempt{SolutionID: contestSolution.ID}
+	solution, err := m.solutions.Get(ctx, contestSolution.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			attempt.Broken = true
+			return attempt, nil
+		}
+		return standingsAttempt{}, err
+	}
+	attempt.CreateTime = solution.CreateTime
+	report, err := solution.GetReport()
+	if err != nil {
+		attempt.Broken = true
+		return attempt, nil
+	}
+	attempt.Report = report
+	return attempt, nil
+}
+
+func (m *ContestStandingsManager) buildAggregate(
+	ctx context.Context, contestID int64,
+) (*standingsAggregate, error) {
+	aggregate := newStandingsAggregate()
+	solutions, err := m.contestSolutions.FindByContest(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = solutions.Close() }()
+	for solutions.Next() {
+		solution := solutions.Row()
+		attempt, err := m.makeStandingsAttempt(ctx, solution)
+		if err != nil {
+			return nil, err
+		}
+		aggregate.update(standingsAggregateKey{
+			ParticipantID: solution.ParticipantID,
+			ProblemID:     solution.ProblemID,
+		}, attempt)
+	}
+	if err := solutions.Err(); err != nil {
+		return nil, err
+	}
+	return aggregate, nil
+}
+
+// getAggregate returns aggregate for specified contest.
+//
+// Aggregates are kept in memory only when events are consumed by
+// RunInvalidation, otherwise aggregate is built on every call.
+func (m *ContestStandingsManager) getAggregate(
+	ctx context.Context, contestID int64,
+) (*standingsAggregate, error) {
+	if !m.consuming.Load() {
+		return m.buildAggregate(ctx, contestID)
+	}
+	m.aggregatesMutex.Lock()
+	defer m.aggregatesMutex.Unlock()
+	if aggregate, ok := m.aggregates[contestID]; ok {
+		return aggregate, nil
+	}
+	aggregate, err := m.buildAggregate(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	m.aggregates[contestID] = aggregate
+	return aggregate, nil
+}
+
+// updateAggregates applies changes of specified solutions to aggregates.
+//
+// Returns IDs of contests with changed aggregates.
+func (m *ContestStandingsManager) updateAggregates(
+	ctx context.Context, solutionIDs []int64,
+) ([]int64, error) {
+	m.aggregatesMutex.Lock()
+	defer m.aggregatesMutex.Unlock()
+	var contestIDs []int64
+	for _, id := range solutionIDs {
+		solution, err := m.contestSolutions.Get(ctx, id)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return nil, err
+			}
+			for _, aggregate := range m.aggregates {
+				aggregate.remove(id)
+			}
+			continue
+		}
+		contestIDs = append(contestIDs, solution.ContestID)
+		aggregate, ok := m.aggregates[solution.ContestID]
+		if !ok {
+			continue
+		}
+		attempt, err := m.makeStandingsAttempt(ctx, solution)
+		if err != nil {
+			return nil, err
+		}
+		aggregate.update(standingsAggregateKey{
+			ParticipantID: solution.ParticipantID,
+			ProblemID:     solution.ProblemID,
+		}, attempt)
+	}
+	return contestIDs, nil
+}
+
+func (m *ContestStandingsManager) removeAggregate(contestID int64) {
+	m.aggregatesMutex.Lock()
+	defer m.aggregatesMutex.Unlock()
+	delete(m.aggregates, contestID)
+}
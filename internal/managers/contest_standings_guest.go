@@ -0,0 +1,99 @@
+package managers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/udovin/solve/internal/perms"
+)
+
+// GuestStandings represents pre-serialized standings that are
+// visible for any observer of contest.
+type GuestStandings struct {
+	// Data contains serialized standings.
+	Data []byte
+	// ETag contains strong entity tag of serialized standings.
+	ETag string
+	// ModifyTime contains time when standings were serialized.
+	ModifyTime time.Time
+}
+
+type guestStandingsCache struct {
+	Done       <-chan struct{}
+	Time       time.Time
+	ExpireTime int64
+	Standings  *GuestStandings
+	Error      error
+}
+
+// getGuestContext returns contest context without participants
+// and with permissions of observer.
+func getGuestContext(ctx *ContestContext) *ContestContext {
+	guestCtx := *ctx
+	guestCtx.Permissions = ctx.Permissions.Clone()
+	delete(guestCtx.Permissions, perms.ObserveContestFullStandingsRole)
+	guestCtx.Participants = nil
+	guestCtx.effectivePos = 0
+	return &guestCtx
+}
+
+// BuildGuestStandings returns cached serialized standings for contest
+// observers that do not participate in contest.
+//
+// Cache is dropped together with standings cache, so guests will see
+// changes as soon as events are consumed by RunInvalidation.
+func (m *ContestStandingsManager) BuildGuestStandings(
+	ctx *ContestContext, encode func(*ContestStandings) ([]byte, error),
+) (*GuestStandings, error) {
+	ctx = getGuestContext(ctx)
+	useCache, err := m.settings.GetBool("standings.use_cache")
+	if err != nil || !useCache.OrElse(true) {
+		return m.buildGuestStandings(ctx, encode)
+	}
+	m.mutex.Lock()
+	cache, ok := m.guestCache[ctx.Contest.ID]
+	if ok {
+		select {
+		case <-cache.Done:
+			if cache.Error == nil && isStandingsCacheFresh(cache.Time, cache.ExpireTime, ctx.Now) {
+				m.mutex.Unlock()
+				return cache.Standings, nil
+			}
+		default:
+			m.mutex.Unlock()
+			<-cache.Done
+			return cache.Standings, cache.Error
+		}
+	}
+	done := make(chan struct{})
+	defer close(done)
+	cache = &guestStandingsCache{
+		Done:       done,
+		Time:       ctx.Now,
+		ExpireTime: getStandingsExpireTime(ctx, getParticipantBeginTime(&ctx.ContestConfig, nil)),
+	}
+	m.guestCache[ctx.Contest.ID] = cache
+	m.mutex.Unlock()
+	cache.Standings, cache.Error = m.buildGuestStandings(ctx, encode)
+	return cache.Standings, cache.Error
+}
+
+func (m *ContestStandingsManager) buildGuestStandings(
+	ctx *ContestContext, encode func(*ContestStandings) ([]byte, error),
+) (*GuestStandings, error) {
+	standings, err := m.BuildStandings(ctx, BuildStandingsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, err := encode(standings)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+	return &GuestStandings{
+		Data:       data,
+		ETag:       `"` + hex.EncodeToString(hash[:16]) + `"`,
+		ModifyTime: ctx.Now.Truncate(time.Second),
+	}, nil
+}
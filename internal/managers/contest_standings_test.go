@@ -0,0 +1,362 @@
+package managers
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
+)
+
+type standingsTestEnv struct {
+	t           *testing.T
+	core        *core.Core
+	compiler    models.Compiler
+	contest     models.Contest
+	problem     models.ContestProblem
+	participant models.ContestParticipant
+}
+
+func newStandingsTestEnv(t *testing.T) *standingsTestEnv {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: filepath.Join(t.TempDir(), "db.sqlite")},
+		},
+		Security: &config.Security{PasswordSalt: "qwerty123"},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{FilesDir: t.TempDir()},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	ctx := context.Background()
+	if err := db.ApplyMigrations(ctx, c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	t.Cleanup(c.Stop)
+	e := standingsTestEnv{t: t, core: c}
+	account := models.Account{Kind: models.UserAccountKind}
+	if err := c.Accounts.Create(ctx, &account); err != nil {
+		t.Fatal("Error:", err)
+	}
+	user := models.User{Login: "test"}
+	user.ID = account.ID
+	if err := c.Users.Create(ctx, &user); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := c.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	files := NewFileManager(c)
+	image, err := files.UploadFile(ctx, &FileReader{
+		Name:   "image.tar.gz",
+		Size:   5,
+		Reader: bytes.NewReader([]byte("image")),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := files.ConfirmUploadFile(ctx, &image); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.compiler = models.Compiler{Name: "test-cpp", ImageID: image.ID}
+	if err := c.Compilers.Create(ctx, &e.compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.contest = models.Contest{Title: "Test contest"}
+	if err := e.contest.SetConfig(models.ContestConfig{
+		BeginTime: models.NInt64(time.Now().Add(-time.Minute).Unix()),
+		Duration:  int(time.Hour / time.Second),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Contests.Create(ctx, &e.contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.problem = models.ContestProblem{
+		ContestID: e.contest.ID, ProblemID: problem.ID, Code: "A",
+	}
+	if err := c.ContestProblems.Create(ctx, &e.problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.participant = models.ContestParticipant{
+		ContestID: e.contest.ID, AccountID: account.ID,
+		Kind: models.RegularParticipant,
+	}
+	if err := c.ContestParticipants.Create(ctx, &e.participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.Sync()
+	return &e
+}
+
+func (e *standingsTestEnv) Sync() {
+	ctx := models.WithSync(context.Background())
+	for _, store := range []models.CachedStore{
+		e.core.Contests, e.core.ContestProblems, e.core.ContestParticipants,
+		e.core.ContestSolutions, e.core.Solutions,
+	} {
+		if err := store.Sync(ctx); err != nil {
+			e.t.Fatal("Error:", err)
+		}
+	}
+}
+
+func (e *standingsTestEnv) Context() *ContestContext {
+	config, err := e.contest.GetConfig()
+	if err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	return &ContestContext{
+		AccountContext: &AccountContext{context: context.Background()},
+		Contest:        e.contest,
+		ContestConfig:  config,
+		Now:            time.Now(),
+	}
+}
+
+func (e *standingsTestEnv) CreateSolution(verdict models.Verdict) models.Solution {
+	ctx := context.Background()
+	solution := models.Solution{
+		Kind:       models.ContestSolutionKind,
+		ProblemID:  e.problem.ProblemID,
+		CompilerID: e.compiler.ID,
+		AuthorID:   e.participant.AccountID,
+		CreateTime: time.Now().Add(-10 * time.Second).Unix(),
+	}
+	if err := solution.SetReport(&models.SolutionReport{Verdict: verdict}); err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	if err := e.core.Solutions.Create(ctx, &solution); err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     e.contest.ID,
+		ParticipantID: e.participant.ID,
+		ProblemID:     e.problem.ID,
+	}
+	contestSolution.ID = solution.ID
+	if err := e.core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	return solution
+}
+
+func (e *standingsTestEnv) UpdateSolution(solution models.Solution, verdict models.Verdict) {
+	if err := solution.SetReport(&models.SolutionReport{Verdict: verdict}); err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	if err := e.core.Solutions.Update(context.Background(), solution); err != nil {
+		e.t.Fatal("Error:", err)
+	}
+}
+
+// BuildScore returns score of participant or zero if participant
+// has no solutions.
+func (e *standingsTestEnv) BuildScore(m *ContestStandingsManager) float64 {
+	standings, err := m.BuildStandings(e.Context(), BuildStandingsOptions{})
+	if err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	switch len(standings.Rows) {
+	case 0:
+		return 0
+	case 1:
+		return standings.Rows[0].Score
+	default:
+		e.t.Fatalf("Expected at most 1 row, got %d", len(standings.Rows))
+		return 0
+	}
+}
+
+func TestContestStandingsInvalidation(t *testing.T) {
+	e := newStandingsTestEnv(t)
+	m := NewContestStandingsManager(e.core)
+	consumers, err := m.newStandingsConsumers(context.Background())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if score := e.BuildScore(m); score != 0 {
+		t.Fatalf("Expected score 0, got %v", score)
+	}
+	solution := e.CreateSolution(models.Accepted)
+	e.Sync()
+	// Standings are cached until events are consumed.
+	if score := e.BuildScore(m); score != 0 {
+		t.Fatalf("Expected cached score 0, got %v", score)
+	}
+	if err := consumers.consume(context.Background(), m); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if score := e.BuildScore(m); score != 1 {
+		t.Fatalf("Expected score 1, got %v", score)
+	}
+	e.UpdateSolution(solution, models.WrongAnswer)
+	if err := consumers.consume(context.Background(), m); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if score := e.BuildScore(m); score != 0 {
+		t.Fatalf("Expected score 0, got %v", score)
+	}
+}
+
+func (e *standingsTestEnv) CheckAggregate(
+	m *ContestStandingsManager, aggregate *standingsAggregate, count int,
+) {
+	expected, err := m.buildAggregate(context.Background(), e.contest.ID)
+	if err != nil {
+		e.t.Fatal("Error:", err)
+	}
+	attempts := aggregate.Attempts(e.participant.ID, e.problem.ID)
+	if len(attempts) != count {
+		e.t.Fatalf("Expected %d attempts, got %d", count, len(attempts))
+	}
+	if !reflect.DeepEqual(attempts, expected.Attempts(e.participant.ID, e.problem.ID)) {
+		e.t.Fatal("Aggregate differs from full rebuild")
+	}
+}
+
+func TestContestStandingsAggregate(t *testing.T) {
+	e := newStandingsTestEnv(t)
+	m := NewContestStandingsManager(e.core)
+	ctx := context.Background()
+	consumers, err := m.newStandingsConsumers(ctx)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Aggregates are kept in memory only while events are consumed.
+	m.consuming.Store(true)
+	first := e.CreateSolution(models.WrongAnswer)
+	e.Sync()
+	aggregate, err := m.getAggregate(ctx, e.contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.CheckAggregate(m, aggregate, 1)
+	second := e.CreateSolution(models.Accepted)
+	e.UpdateSolution(first, models.CompilationError)
+	if err := consumers.consume(ctx, m); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if cached, err := m.getAggregate(ctx, e.contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if cached != aggregate {
+		t.Fatal("Expected incrementally updated aggregate")
+	}
+	e.CheckAggregate(m, aggregate, 2)
+	if err := e.core.ContestSolutions.Delete(ctx, second.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := consumers.consume(ctx, m); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.CheckAggregate(m, aggregate, 1)
+	// Without consuming of events aggregate is built on every call.
+	m.consuming.Store(false)
+	e.CreateSolution(models.Accepted)
+	e.Sync()
+	fresh, err := m.getAggregate(ctx, e.contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if fresh == aggregate {
+		t.Fatal("Expected rebuilt aggregate")
+	}
+	e.CheckAggregate(m, fresh, 2)
+	if score := e.BuildScore(m); score != 1 {
+		t.Fatalf("Expected score 1, got %v", score)
+	}
+}
+
+func TestGetStandingsExpireTime(t *testing.T) {
+	now := time.Unix(1000, 0)
+	ctx := ContestContext{
+		ContestConfig: models.ContestConfig{
+			Duration:            100,
+			FreezeBeginDuration: 50,
+		},
+		Now: now,
+	}
+	for _, test := range []struct {
+		BeginTime  int64
+		ExpireTime int64
+	}{
+		{0, 0},
+		{1100, 1100},
+		{980, 1030},
+		{920, 1020},
+		{800, 0},
+	} {
+		if v := getStandingsExpireTime(&ctx, test.BeginTime); v != test.ExpireTime {
+			t.Fatalf("Expected %d, got %d", test.ExpireTime, v)
+		}
+	}
+}
+
+func TestContestFinalizeAction(t *testing.T) {
+	e := newStandingsTestEnv(t)
+	m := NewContestStandingsManager(e.core)
+	ctx := context.Background()
+	config, err := e.contest.GetConfig()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	config.Actions = []models.ContestAction{
+		{Kind: models.FinalizeStandingsContestAction, Time: int64(config.Duration)},
+	}
+	if err := e.contest.SetConfig(config); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.core.Contests.Update(ctx, e.contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	e.CreateSolution(models.Accepted)
+	e.Sync()
+	now := time.Now().Add(2 * time.Hour)
+	if err := e.core.ExecuteContestActions(ctx, now, m); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest, err := e.core.Contests.Get(models.WithSync(ctx), e.contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if config, err := contest.GetConfig(); err != nil {
+		t.Fatal("Error:", err)
+	} else if config.FinalizeTime != models.NInt64(now.Unix()) {
+		t.Fatalf("Expected finalize time %d, got %d", now.Unix(), config.FinalizeTime)
+	} else if config.Actions[0].ExecuteTime == 0 {
+		t.Fatal("Expected executed action")
+	}
+	if err := e.core.ContestResults.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	rows, err := e.core.ContestResults.FindByContest(ctx, e.contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	results, err := db.CollectRows(rows)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if rows, err := results[0].GetResults(); err != nil {
+		t.Fatal("Error:", err)
+	} else if len(rows) != 1 || rows[0].ParticipantID != e.participant.ID || rows[0].Score != 1 {
+		t.Fatalf("Unexpected results: %v", rows)
+	}
+}
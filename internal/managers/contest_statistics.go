@@ -0,0 +1,310 @@
+package managers
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+)
+
+type ContestProblemStatistics struct {
+	Problem              models.ContestProblem
+	TotalSolutions       int
+	AcceptedSolutions    int
+	TotalParticipants    int
+	AcceptedParticipants int
+	Verdicts             map[models.Verdict]int
+}
+
+type ContestStatisticsBucket struct {
+	// BeginTime contains contest time of bucket begin in seconds.
+	BeginTime         int64
+	TotalSolutions    int
+	AcceptedSolutions int
+}
+
+type ContestLanguageStatistics struct {
+	Compiler       models.Compiler
+	TotalSolutions int
+}
+
+type ContestStatistics struct {
+	Problems       []ContestProblemStatistics
+	Verdicts       map[models.Verdict]int
+	Buckets        []ContestStatisticsBucket
+	BucketDuration int64
+	Languages      []ContestLanguageStatistics
+}
+
+type ContestStatisticsManager struct {
+	contestParticipants *models.ContestParticipantStore
+	contestSolutions    *models.ContestSolutionStore
+	contestProblems     *models.ContestProblemStore
+	solutions           *models.SolutionStore
+	compilers           *models.CompilerStore
+	settings            *models.SettingStore
+	cache               map[int64]*statisticsCache
+	mutex               sync.Mutex
+}
+
+func NewContestStatisticsManager(core *core.Core) *ContestStatisticsManager {
+	return &ContestStatisticsManager{
+		contestParticipants: core.ContestParticipants,
+		contestSolutions:    core.ContestSolutions,
+		contestProblems:     core.ContestProblems,
+		solutions:           core.Solutions,
+		compilers:           core.Compilers,
+		settings:            core.Settings,
+		cache:               map[int64]*statisticsCache{},
+	}
+}
+
+// statisticsCacheTTL contains lifetime of cached statistics.
+const statisticsCacheTTL = time.Minute
+
+// statisticsCacheSize contains maximal amount of cached contests.
+const statisticsCacheSize = 1024
+
+type statisticsCache struct {
+	Done       <-chan struct{}
+	ExpireTime time.Time
+	Statistics *ContestStatistics
+	Error      error
+}
+
+// isExpired returns true if statistics are built and expired.
+func (c *statisticsCache) isExpired(now time.Time) bool {
+	select {
+	case <-c.Done:
+		return !c.ExpireTime.After(now)
+	default:
+		return false
+	}
+}
+
+// evictStatistics removes expired statistics when cache is full.
+//
+// Should be called with locked mutex.
+func (m *ContestStatisticsManager) evictStatistics(now time.Time) {
+	if len(m.cache) < statisticsCacheSize {
+		return
+	}
+	for id, cache := range m.cache {
+		if cache.isExpired(now) {
+			delete(m.cache, id)
+		}
+	}
+	if len(m.cache) >= statisticsCacheSize {
+		m.cache = map[int64]*statisticsCache{}
+	}
+}
+
+// BuildStatisticsOptions represents options for contest statistics.
+type BuildStatisticsOptions struct {
+	// Label contains label of participants that should be kept.
+	//
+	// Zero value means all participants.
+	Label ParticipantLabel
+}
+
+// BuildStatistics returns statistics of contest solutions.
+//
+// Only solutions of regular and virtual participants sent during
+// contest are taken into account.
+func (m *ContestStatisticsManager) BuildStatistics(
+	ctx *ContestContext, options BuildStatisticsOptions,
+) (*ContestStatistics, error) {
+	// Statistics for labels are not cached, because amount of
+	// possible labels is not limited.
+	if !options.Label.IsZero() {
+		return m.doBuildStatistics(ctx, options)
+	}
+	m.mutex.Lock()
+	now := time.Now()
+	cache, ok := m.cache[ctx.Contest.ID]
+	if ok {
+		select {
+		case <-cache.Done:
+			if cache.Error == nil && cache.ExpireTime.After(now) {
+				m.mutex.Unlock()
+				return cache.Statistics, nil
+			}
+		default:
+			m.mutex.Unlock()
+			<-cache.Done
+			return cache.Statistics, cache.Error
+		}
+	}
+	m.evictStatistics(now)
+	done := make(chan struct{})
+	defer close(done)
+	cache = &statisticsCache{Done: done, ExpireTime: now.Add(statisticsCacheTTL)}
+	m.cache[ctx.Contest.ID] = cache
+	m.mutex.Unlock()
+	cache.Statistics, cache.Error = m.doBuildStatistics(ctx, options)
+	return cache.Statistics, cache.Error
+}
+
+// IsPublicStatistics returns true when contest is finished and
+// standings are unfrozen, so statistics do not reveal hidden verdicts.
+func IsPublicStatistics(ctx *ContestContext) bool {
+	contestTime := ctx.GetContestTime()
+	return contestTime.Stage() == ContestFinished && !isContestFrozen(ctx, contestTime)
+}
+
+func (m *ContestStatisticsManager) getBucketDuration() int64 {
+	duration, err := m.settings.GetInt64("contests.statistics.bucket_duration")
+	if err == nil {
+		if value := duration.OrElse(0); value > 0 {
+			return value
+		}
+	}
+	return 5 * 60
+}
+
+func (m *ContestStatisticsManager) doBuildStatistics(
+	ctx *ContestContext, options BuildStatisticsOptions,
+) (*ContestStatistics, error) {
+	contestProblemRows, err := m.contestProblems.FindByContest(ctx, ctx.Contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	contestProblems, err := db.CollectRows(contestProblemRows)
+	if err != nil {
+		return nil, err
+	}
+	models.SortContestProblems(contestProblems)
+	statistics := ContestStatistics{
+		Verdicts:       map[models.Verdict]int{},
+		BucketDuration: m.getBucketDuration(),
+	}
+	problemByID := map[int64]int{}
+	for i, problem := range contestProblems {
+		statistics.Problems = append(statistics.Problems, ContestProblemStatistics{
+			Problem:  problem,
+			Verdicts: map[models.Verdict]int{},
+		})
+		problemByID[problem.ID] = i
+	}
+	duration := int64(ctx.ContestConfig.Duration)
+	if duration > 0 {
+		statistics.Buckets = make(
+			[]ContestStatisticsBucket,
+			(duration+statistics.BucketDuration-1)/statistics.BucketDuration,
+		)
+		for i := range statistics.Buckets {
+			statistics.Buckets[i].BeginTime = int64(i) * statistics.BucketDuration
+		}
+	}
+	type participantProblem struct {
+		ParticipantID int64
+		ProblemID     int64
+	}
+	attempted := map[participantProblem]bool{}
+	languages := map[int64]int{}
+	participants := map[int64]models.ContestParticipant{}
+	if err := func() error {
+		rows, err := m.contestParticipants.FindByContest(ctx, ctx.Contest.ID)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			participant := rows.Row()
+			if isPlacedParticipant(participant.Kind) && options.Label.Match(participant) {
+				participants[participant.ID] = participant
+			}
+		}
+		return rows.Err()
+	}(); err != nil {
+		return nil, err
+	}
+	rows, err := m.contestSolutions.FindByContest(ctx, ctx.Contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		contestSolution := rows.Row()
+		participant, ok := participants[contestSolution.ParticipantID]
+		if !ok {
+			continue
+		}
+		index, ok := problemByID[contestSolution.ProblemID]
+		if !ok {
+			continue
+		}
+		solution, err := m.solutions.Get(ctx, contestSolution.ID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, err
+		}
+		beginTime := getParticipantBeginTime(&ctx.ContestConfig, &participant)
+		if beginTime == 0 {
+			continue
+		}
+		contestTime := solution.CreateTime - beginTime
+		if contestTime < 0 || (duration > 0 && contestTime >= duration) {
+			continue
+		}
+		report, err := solution.GetReport()
+		if err != nil || report == nil {
+			continue
+		}
+		problem := &statistics.Problems[index]
+		problem.TotalSolutions++
+		problem.Verdicts[report.Verdict]++
+		statistics.Verdicts[report.Verdict]++
+		languages[solution.CompilerID]++
+		key := participantProblem{
+			ParticipantID: participant.ID,
+			ProblemID:     contestSolution.ProblemID,
+		}
+		accepted, ok := attempted[key]
+		if !ok {
+			problem.TotalParticipants++
+		}
+		if report.Verdict == models.Accepted {
+			problem.AcceptedSolutions++
+			if !accepted {
+				problem.AcceptedParticipants++
+			}
+			accepted = true
+		}
+		attempted[key] = accepted
+		if bucket := contestTime / statistics.BucketDuration; bucket < int64(len(statistics.Buckets)) {
+			statistics.Buckets[bucket].TotalSolutions++
+			if report.Verdict == models.Accepted {
+				statistics.Buckets[bucket].AcceptedSolutions++
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for id, count := range languages {
+		compiler, err := m.compilers.Get(ctx, id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, err
+		}
+		statistics.Languages = append(statistics.Languages, ContestLanguageStatistics{
+			Compiler:       compiler,
+			TotalSolutions: count,
+		})
+	}
+	sortFunc(statistics.Languages, func(lhs, rhs ContestLanguageStatistics) bool {
+		if lhs.TotalSolutions != rhs.TotalSolutions {
+			return lhs.TotalSolutions > rhs.TotalSolutions
+		}
+		return lhs.Compiler.ID < rhs.Compiler.ID
+	})
+	return &statistics, nil
+}
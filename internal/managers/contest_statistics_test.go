@@ -0,0 +1,30 @@
+package managers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContestStatisticsCacheEviction(t *testing.T) {
+	m := ContestStatisticsManager{cache: map[int64]*statisticsCache{}}
+	now := time.Now()
+	done := make(chan struct{})
+	close(done)
+	for i := 0; i < statisticsCacheSize-2; i++ {
+		m.cache[int64(i)] = &statisticsCache{Done: done, ExpireTime: now.Add(-time.Second)}
+	}
+	pending := make(chan struct{})
+	defer close(pending)
+	m.cache[-1] = &statisticsCache{Done: pending, ExpireTime: now.Add(-time.Second)}
+	m.cache[-2] = &statisticsCache{Done: done, ExpireTime: now.Add(time.Second)}
+	m.evictStatistics(now)
+	if len(m.cache) != 2 {
+		t.Fatalf("Expected %d entries, got %d", 2, len(m.cache))
+	}
+	if _, ok := m.cache[-1]; !ok {
+		t.Fatal("Expected pending entry")
+	}
+	if _, ok := m.cache[-2]; !ok {
+		t.Fatal("Expected not expired entry")
+	}
+}
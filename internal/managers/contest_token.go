@@ -0,0 +1,56 @@
+package managers
+
+import (
+	"github.com/udovin/solve/internal/perms"
+)
+
+// contestTokenPermissions contains permissions that are available for
+// accounts authorized with contest API token.
+//
+// Tokens are used by read-only mirrors of contest standings, so all
+// other permissions of account are revoked.
+var contestTokenPermissions = []string{
+	perms.ObserveContestRole,
+	perms.ObserveContestProblemsRole,
+	perms.ObserveContestStandingsRole,
+	perms.ObserveContestFullStandingsRole,
+	perms.ObserveContestSolutionsRole,
+	perms.ObserveContestSolutionRole,
+}
+
+// filterPermissions returns permissions from set that are contained
+// in list of allowed permissions.
+func filterPermissions(
+	permissions perms.PermissionSet, allowed []string,
+) perms.PermissionSet {
+	result := perms.PermissionSet{}
+	for _, permission := range allowed {
+		if permissions.HasPermission(permission) {
+			result.AddPermission(permission)
+		}
+	}
+	return result
+}
+
+// RestrictToContest restricts permissions of account to observing of
+// specified contest.
+//
+// Permissions of account are used for derivation of contest
+// permissions, so only permissions of contest tokens are kept.
+func (c *AccountContext) RestrictToContest(contestID int64) {
+	c.ContestID = contestID
+	c.Permissions = filterPermissions(c.Permissions, contestTokenPermissions)
+}
+
+// restrictContestPermissions revokes permissions of contest that are
+// not available for account restricted to contest.
+func restrictContestPermissions(c *ContestContext) {
+	if c.AccountContext.ContestID == 0 {
+		return
+	}
+	if c.AccountContext.ContestID != c.Contest.ID {
+		c.Permissions = perms.PermissionSet{}
+		return
+	}
+	c.Permissions = filterPermissions(c.Permissions, contestTokenPermissions)
+}
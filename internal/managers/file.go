@@ -3,6 +3,7 @@ package managers
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
@@ -34,6 +35,14 @@ type FileStorage interface {
 	DeleteFile(context.Context, string) error
 }
 
+// FileURLSigner represents storage that can generate temporary URLs
+// for direct download of files.
+type FileURLSigner interface {
+	// SignURL should return URL of file and its lifetime or empty URL
+	// if signing is not available.
+	SignURL(context.Context, string) (string, time.Duration, error)
+}
+
 type LocalStorage struct {
 	Dir string
 }
@@ -102,6 +111,9 @@ type S3Storage struct {
 	client     *s3.Client
 	bucket     string
 	pathPrefix string
+	// presignExpires contains lifetime of signed URLs, zero value
+	// disables signing of URLs.
+	presignExpires time.Duration
 }
 
 func (s *S3Storage) GeneratePath(ctx context.Context) (string, error) {
@@ -124,6 +136,19 @@ func (s *S3Storage) ReadFile(ctx context.Context, filePath string) (io.ReadClose
 	return object.Body, nil
 }
 
+// ReadFileRange returns content of file starting from offset.
+func (s *S3Storage) ReadFileRange(ctx context.Context, filePath string, offset int64) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.pathPrefix + filePath),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-", offset)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return object.Body, nil
+}
+
 func (s *S3Storage) WriteFile(ctx context.Context, filePath string, file io.ReadSeeker) (models.FileMeta, error) {
 	meta, err := readFileMeta(file)
 	if err != nil {
@@ -156,6 +181,24 @@ func (s *S3Storage) WriteFile(ctx context.Context, filePath string, file io.Read
 	return meta, err
 }
 
+// SignURL returns signed URL of file.
+//
+// Empty URL is returned if signing of URLs is disabled.
+func (s *S3Storage) SignURL(ctx context.Context, filePath string) (string, time.Duration, error) {
+	if s.presignExpires <= 0 {
+		return "", 0, nil
+	}
+	client := s3.NewPresignClient(s.client)
+	req, err := client.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.pathPrefix + filePath),
+	}, s3.WithPresignExpires(s.presignExpires))
+	if err != nil {
+		return "", 0, err
+	}
+	return req.URL, s.presignExpires, nil
+}
+
 func (s *S3Storage) DeleteFile(ctx context.Context, filePath string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
@@ -202,9 +245,10 @@ func NewFileManager(c *core.Core) *FileManager {
 			})
 		}
 		storage = &S3Storage{
-			client:     s3.NewFromConfig(config, options...),
-			bucket:     t.Bucket,
-			pathPrefix: t.PathPrefix,
+			client:         s3.NewFromConfig(config, options...),
+			bucket:         t.Bucket,
+			pathPrefix:     t.PathPrefix,
+			presignExpires: time.Duration(t.PresignExpires) * time.Second,
 		}
 	default:
 		panic(fmt.Errorf(
@@ -251,6 +295,9 @@ func NewMultipartFileReader(file *multipart.FileHeader) (*FileReader, error) {
 
 // UploadFile adds file to file storage and starts upload.
 //
+// If file with the same content is already available, its storage
+// object will be reused instead of uploading new one.
+//
 // You shold call ConfirmUploadFile for marking file available.
 func (m *FileManager) UploadFile(
 	ctx context.Context, fileReader *FileReader,
@@ -263,6 +310,17 @@ func (m *FileManager) UploadFile(
 	if !ok {
 		deadline = time.Now().Add(m.uploadTimeout)
 	}
+	sha256, size, err := readFileSHA256(fileReader.Reader)
+	if err != nil {
+		return models.File{}, err
+	}
+	if file, ok, err := m.reuseFile(
+		ctx, fileReader.Name, sha256, size, deadline,
+	); err != nil {
+		return models.File{}, err
+	} else if ok {
+		return file, nil
+	}
 	filePath, err := m.storage.GeneratePath(ctx)
 	if err != nil {
 		return models.File{}, fmt.Errorf("cannot generate path: %w", err)
@@ -271,6 +329,7 @@ func (m *FileManager) UploadFile(
 		Status:     models.PendingFile,
 		ExpireTime: models.NInt64(deadline.Add(time.Minute).Unix()),
 		Path:       filePath,
+		SHA256:     models.NString(sha256),
 	}
 	meta := models.FileMeta{
 		Name: fileReader.Name,
@@ -294,12 +353,113 @@ func (m *FileManager) UploadFile(
 	return file, nil
 }
 
+// reuseFile creates pending file that references storage object of
+// available file with the same content.
+func (m *FileManager) reuseFile(
+	ctx context.Context, name, sha256 string, size int64, deadline time.Time,
+) (models.File, bool, error) {
+	origin, err := m.findAvailableFile(ctx, sha256, size)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.File{}, false, nil
+		}
+		return models.File{}, false, err
+	}
+	originMeta, err := origin.GetMeta()
+	if err != nil {
+		return models.File{}, false, err
+	}
+	file := models.File{
+		Status:     models.PendingFile,
+		ExpireTime: models.NInt64(deadline.Add(time.Minute).Unix()),
+		Path:       origin.Path,
+		SHA256:     origin.SHA256,
+	}
+	meta := models.FileMeta{
+		Name: name,
+		Size: originMeta.Size,
+		MD5:  originMeta.MD5,
+	}
+	if err := file.SetMeta(meta); err != nil {
+		return models.File{}, false, err
+	}
+	if err := m.files.Create(ctx, &file); err != nil {
+		return models.File{}, false, err
+	}
+	// Origin file can be deleted concurrently, so we should check
+	// that storage object is still referenced by available file.
+	referenced, err := m.hasPathReferences(ctx, file.Path, file.ID, true)
+	if err != nil {
+		return models.File{}, false, err
+	}
+	if !referenced {
+		if err := m.files.Delete(ctx, file.ID); err != nil {
+			return models.File{}, false, err
+		}
+		return models.File{}, false, nil
+	}
+	return file, true, nil
+}
+
+func (m *FileManager) findAvailableFile(
+	ctx context.Context, sha256 string, size int64,
+) (models.File, error) {
+	files, err := m.files.FindBySHA256(ctx, sha256)
+	if err != nil {
+		return models.File{}, err
+	}
+	defer func() { _ = files.Close() }()
+	for files.Next() {
+		file := files.Row()
+		if file.Status != models.AvailableFile {
+			continue
+		}
+		meta, err := file.GetMeta()
+		if err != nil || meta.Size != size {
+			continue
+		}
+		return file, nil
+	}
+	if err := files.Err(); err != nil {
+		return models.File{}, err
+	}
+	return models.File{}, sql.ErrNoRows
+}
+
+// hasPathReferences returns true if storage path is referenced by
+// files except file with specified ID.
+func (m *FileManager) hasPathReferences(
+	ctx context.Context, path string, fileID int64, onlyAvailable bool,
+) (bool, error) {
+	files, err := m.files.FindByPath(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = files.Close() }()
+	for files.Next() {
+		file := files.Row()
+		if file.ID == fileID {
+			continue
+		}
+		if onlyAvailable && file.Status != models.AvailableFile {
+			continue
+		}
+		return true, nil
+	}
+	return false, files.Err()
+}
+
 func (m *FileManager) ConfirmUploadFile(
 	ctx context.Context, file *models.File,
 ) error {
 	if file.Status != models.PendingFile {
 		return fmt.Errorf("file shoud be in pending status")
 	}
+	if meta, err := file.GetMeta(); err != nil {
+		return err
+	} else if meta.Upload != nil && !meta.Upload.Finalized {
+		return ErrFileUploadIncomplete
+	}
 	clone := file.Clone()
 	clone.Status = models.AvailableFile
 	clone.ExpireTime = 0
@@ -320,7 +480,7 @@ func (m *FileManager) DeleteFile(ctx context.Context, id int64) error {
 		deadline = time.Now().Add(5 * time.Second)
 	}
 	expireTime := time.Unix(int64(file.ExpireTime), 0)
-	if file.Status == models.PendingFile && time.Now().Before(expireTime) {
+	if file.Status == models.PendingFile && models.GetNow(ctx).Before(expireTime) {
 		return fmt.Errorf("cannot delete not uploaded file")
 	}
 	file.Status = models.PendingFile
@@ -328,9 +488,17 @@ func (m *FileManager) DeleteFile(ctx context.Context, id int64) error {
 	if err := m.files.Update(ctx, file); err != nil {
 		return err
 	}
-	if err := m.storage.DeleteFile(ctx, file.Path); err != nil {
+	// Storage object can be shared with other files that have
+	// the same content, so it is deleted with the last reference.
+	referenced, err := m.hasPathReferences(ctx, file.Path, file.ID, false)
+	if err != nil {
 		return err
 	}
+	if !referenced {
+		if err := m.storage.DeleteFile(ctx, file.Path); err != nil {
+			return err
+		}
+	}
 	return m.files.Delete(ctx, file.ID)
 }
 
@@ -344,9 +512,28 @@ func (m *FileManager) DownloadFile(
 	if err := m.waitFileAvailable(ctx, &file); err != nil {
 		return nil, err
 	}
+	if storage, ok := m.storage.(FileRangeStorage); ok {
+		if meta, err := file.GetMeta(); err == nil && meta.Size > 0 {
+			return newRangeFileReader(ctx, storage, file.Path, meta.Size), nil
+		}
+	}
 	return m.storage.ReadFile(ctx, file.Path)
 }
 
+// SignFileURL returns temporary URL for direct download of file from
+// storage and lifetime of URL.
+//
+// If storage does not support signed URLs, empty URL is returned.
+func (m *FileManager) SignFileURL(
+	ctx context.Context, file models.File,
+) (string, time.Duration, error) {
+	signer, ok := m.storage.(FileURLSigner)
+	if !ok || file.Status != models.AvailableFile {
+		return "", 0, nil
+	}
+	return signer.SignURL(ctx, file.Path)
+}
+
 func (m *FileManager) waitFileAvailable(
 	ctx context.Context, file *models.File,
 ) error {
@@ -402,3 +589,18 @@ func readFileMeta(file io.ReadSeeker) (models.FileMeta, error) {
 	}
 	return models.FileMeta{MD5: md5, Size: size}, nil
 }
+
+func readFileSHA256(file io.ReadSeeker) (string, int64, error) {
+	startOffset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, err
+	}
+	sha256, size, err := hash.CalculateSHA256(file)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := file.Seek(startOffset, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	return sha256, size, nil
+}
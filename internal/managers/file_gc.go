@@ -0,0 +1,210 @@
+package managers
+
+import (
+	"context"
+	"time"
+
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/logs"
+)
+
+// FileCollectorStats represents result of garbage collection.
+type FileCollectorStats struct {
+	// RemovedFiles contains amount of removed files.
+	RemovedFiles int
+	// ReclaimedSize contains total size of removed files.
+	//
+	// Aborted uploads are not included because their size is unknown.
+	ReclaimedSize int64
+}
+
+// FileCollector removes files that are not referenced by any object.
+type FileCollector struct {
+	core        *core.Core
+	files       *FileManager
+	gracePeriod time.Duration
+	// orphans contains time when file was first seen unreferenced.
+	orphans map[int64]time.Time
+}
+
+// NewFileCollector creates a new instance of FileCollector.
+func NewFileCollector(c *core.Core, files *FileManager) *FileCollector {
+	return &FileCollector{
+		core:        c,
+		files:       files,
+		gracePeriod: 24 * time.Hour,
+		orphans:     map[int64]time.Time{},
+	}
+}
+
+// Run collects orphaned files until context is canceled.
+//
+// Task should be started as unique daemon.
+func (m *FileCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		stats, err := m.Collect(ctx, time.Now())
+		if err != nil {
+			m.core.Logger().Warn("Cannot collect orphaned files", err)
+		} else if stats.RemovedFiles > 0 {
+			m.core.Logger().Info(
+				"Removed orphaned files",
+				logs.Any("files", stats.RemovedFiles),
+				logs.Any("size", stats.ReclaimedSize),
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect removes files that are unreferenced during grace period.
+//
+// Pending files are removed when grace period is passed after upload
+// expiration, so aborted uploads are removed too.
+func (m *FileCollector) Collect(
+	ctx context.Context, now time.Time,
+) (FileCollectorStats, error) {
+	referenced, err := m.findReferencedFiles(ctx)
+	if err != nil {
+		return FileCollectorStats{}, err
+	}
+	orphans, err := m.findOrphanedFiles(ctx, referenced, now)
+	if err != nil {
+		return FileCollectorStats{}, err
+	}
+	stats := FileCollectorStats{}
+	for _, file := range orphans {
+		if err := m.files.DeleteFile(
+			models.WithNow(models.WithSync(ctx), now), file.ID,
+		); err != nil {
+			m.core.Logger().Warn(
+				"Cannot remove orphaned file",
+				logs.Any("id", file.ID),
+				err,
+			)
+			continue
+		}
+		delete(m.orphans, file.ID)
+		stats.RemovedFiles++
+		if meta, err := file.GetMeta(); err == nil {
+			stats.ReclaimedSize += meta.Size
+		}
+	}
+	return stats, nil
+}
+
+func (m *FileCollector) findOrphanedFiles(
+	ctx context.Context, referenced map[int64]struct{}, now time.Time,
+) ([]models.File, error) {
+	rows, err := m.core.Files.Objects().FindObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	orphans := map[int64]time.Time{}
+	var files []models.File
+	for rows.Next() {
+		file := rows.Row()
+		if _, ok := referenced[file.ID]; ok {
+			continue
+		}
+		if file.Status == models.PendingFile {
+			expireTime := time.Unix(int64(file.ExpireTime), 0)
+			if now.After(expireTime.Add(m.gracePeriod)) {
+				files = append(files, file)
+			}
+			continue
+		}
+		seenTime, ok := m.orphans[file.ID]
+		if !ok {
+			seenTime = now
+		}
+		orphans[file.ID] = seenTime
+		if now.Sub(seenTime) >= m.gracePeriod {
+			files = append(files, file)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Files that became referenced again are forgotten.
+	m.orphans = orphans
+	return files, nil
+}
+
+func (m *FileCollector) findReferencedFiles(
+	ctx context.Context,
+) (map[int64]struct{}, error) {
+	ids := map[int64]struct{}{}
+	add := func(id int64) {
+		if id != 0 {
+			ids[id] = struct{}{}
+		}
+	}
+	if err := forEachObject(ctx, m.core.Solutions.Objects(), func(o models.Solution) {
+		add(int64(o.ContentID))
+	}); err != nil {
+		return nil, err
+	}
+	if err := forEachObject(ctx, m.core.Problems.Objects(), func(o models.Problem) {
+		add(int64(o.PackageID))
+		add(int64(o.CompiledID))
+	}); err != nil {
+		return nil, err
+	}
+	if err := forEachObject(ctx, m.core.ProblemResources.Objects(), func(o models.ProblemResource) {
+		add(int64(o.FileID))
+	}); err != nil {
+		return nil, err
+	}
+	if err := forEachObject(ctx, m.core.ProblemCheckers.Objects(), func(o models.ProblemChecker) {
+		add(int64(o.SourceID))
+		add(int64(o.BinaryID))
+	}); err != nil {
+		return nil, err
+	}
+	if err := forEachObject(ctx, m.core.Compilers.Objects(), func(o models.Compiler) {
+		add(o.ImageID)
+	}); err != nil {
+		return nil, err
+	}
+	if err := forEachObject(ctx, m.core.PostFiles.Objects(), func(o models.PostFile) {
+		add(o.FileID)
+	}); err != nil {
+		return nil, err
+	}
+	// Package file is referenced by task until problem is updated.
+	if err := forEachObject(ctx, m.core.Tasks.Objects(), func(o models.Task) {
+		if o.Kind != models.UpdateProblemPackageTask {
+			return
+		}
+		var config models.UpdateProblemPackageTaskConfig
+		if err := o.ScanConfig(&config); err == nil {
+			add(config.FileID)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func forEachObject[T any](
+	ctx context.Context, store db.ObjectROStore[T], fn func(T),
+) error {
+	rows, err := store.FindObjects(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		fn(rows.Row())
+	}
+	return rows.Err()
+}
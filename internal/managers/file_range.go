@@ -0,0 +1,117 @@
+package managers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// FileRangeStorage represents storage that can read file starting
+// from specified offset.
+type FileRangeStorage interface {
+	// ReadFileRange should return content of file starting from offset.
+	ReadFileRange(context.Context, string, int64) (io.ReadCloser, error)
+}
+
+// maxRangeReadRetries contains maximal amount of attempts to resume
+// reading after failure.
+const maxRangeReadRetries = 3
+
+// rangeFileReader represents seekable file that is lazily read from
+// storage using ranges.
+//
+// Reading is resumed from current offset after failures, so large
+// files can be downloaded over unstable connections.
+type rangeFileReader struct {
+	ctx     context.Context
+	storage FileRangeStorage
+	path    string
+	size    int64
+	offset  int64
+	reader  io.ReadCloser
+}
+
+func newRangeFileReader(
+	ctx context.Context, storage FileRangeStorage, path string, size int64,
+) *rangeFileReader {
+	return &rangeFileReader{
+		ctx:     ctx,
+		storage: storage,
+		path:    path,
+		size:    size,
+	}
+}
+
+// Read reads content starting from current offset.
+func (r *rangeFileReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	var lastErr error
+	for i := 0; i < maxRangeReadRetries; i++ {
+		if r.reader == nil {
+			reader, err := r.storage.ReadFileRange(r.ctx, r.path, r.offset)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			r.reader = reader
+		}
+		n, err := r.reader.Read(p)
+		r.offset += int64(n)
+		if err != nil {
+			// Next read will be started with new reader.
+			_ = r.reader.Close()
+			r.reader = nil
+		}
+		if err == nil || n > 0 {
+			return n, nil
+		}
+		if errors.Is(err, io.EOF) {
+			if r.offset < r.size {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, io.EOF
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, err
+		}
+		lastErr = err
+	}
+	return 0, lastErr
+}
+
+// Seek changes offset of next read.
+func (r *rangeFileReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset: %d", offset)
+	}
+	if offset != r.offset && r.reader != nil {
+		_ = r.reader.Close()
+		r.reader = nil
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// Close closes current reader.
+func (r *rangeFileReader) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+	err := r.reader.Close()
+	r.reader = nil
+	return err
+}
+
+var _ io.ReadSeekCloser = (*rangeFileReader)(nil)
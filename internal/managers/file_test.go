@@ -3,8 +3,16 @@ package managers
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	"errors"
+	"io"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/johannesboyne/gofakes3"
 	"github.com/johannesboyne/gofakes3/backend/s3mem"
@@ -13,6 +21,7 @@ import (
 	"github.com/udovin/solve/internal/db"
 	"github.com/udovin/solve/internal/migrations"
 	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/hash"
 )
 
 func TestFileManager(t *testing.T) {
@@ -46,11 +55,82 @@ func TestFileManager(t *testing.T) {
 	if err := manager.ConfirmUploadFile(context.Background(), &file); err != nil {
 		t.Fatal("Error:", err)
 	}
+	content, err := manager.DownloadFile(context.Background(), file.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	seeker, ok := content.(io.ReadSeeker)
+	if !ok {
+		t.Fatal("Expected seekable content")
+	}
+	if _, err := seeker.Seek(2, io.SeekStart); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if data, err := io.ReadAll(seeker); err != nil {
+		t.Fatal("Error:", err)
+	} else if string(data) != "st" {
+		t.Fatalf("Expected %q, got %q", "st", string(data))
+	}
+	_ = content.Close()
 	if err := manager.DeleteFile(models.WithSync(context.Background()), file.ID); err != nil {
 		t.Fatal("Error:", err)
 	}
 }
 
+type flakyRangeStorage struct {
+	data     string
+	failures int
+}
+
+type flakyRangeReader struct {
+	io.Reader
+}
+
+func (r flakyRangeReader) Close() error {
+	return nil
+}
+
+func (s *flakyRangeStorage) ReadFileRange(
+	ctx context.Context, path string, offset int64,
+) (io.ReadCloser, error) {
+	reader := io.Reader(strings.NewReader(s.data[offset:]))
+	if s.failures > 0 {
+		s.failures--
+		// Emulate connection failure after first byte.
+		reader = io.MultiReader(
+			io.LimitReader(reader, 1), iotest.ErrReader(io.ErrClosedPipe),
+		)
+	}
+	return flakyRangeReader{reader}, nil
+}
+
+func TestRangeFileReader(t *testing.T) {
+	storage := flakyRangeStorage{data: "test data", failures: 4}
+	reader := newRangeFileReader(context.Background(), &storage, "test", 9)
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(data) != "test data" {
+		t.Fatalf("Expected %q, got %q", "test data", string(data))
+	}
+	if _, err := reader.Seek(-4, io.SeekEnd); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if data, err := io.ReadAll(reader); err != nil {
+		t.Fatal("Error:", err)
+	} else if string(data) != "data" {
+		t.Fatalf("Expected %q, got %q", "data", string(data))
+	}
+	if _, err := reader.Seek(-1, io.SeekStart); err == nil {
+		t.Fatal("Expected error")
+	}
+	short := newRangeFileReader(context.Background(), &storage, "test", 10)
+	if _, err := io.ReadAll(short); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
 func TestFileManagerS3(t *testing.T) {
 	fakeS3Mem := s3mem.New()
 	fakeS3Mem.CreateBucket("test-bucket")
@@ -98,3 +178,301 @@ func TestFileManagerS3(t *testing.T) {
 		t.Fatal("Error:", err)
 	}
 }
+
+func TestFileManagerDeduplication(t *testing.T) {
+	filesDir := t.TempDir()
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: ":memory:"},
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: filesDir,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	manager := NewFileManager(c)
+	upload := func(content string) models.File {
+		file, err := manager.UploadFile(
+			context.Background(),
+			&FileReader{Reader: bytes.NewReader([]byte(content))},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := manager.ConfirmUploadFile(context.Background(), &file); err != nil {
+			t.Fatal("Error:", err)
+		}
+		return file
+	}
+	file1 := upload("test")
+	file2 := upload("test")
+	file3 := upload("other")
+	if file1.ID == file2.ID {
+		t.Fatal("Expected different files")
+	}
+	if file1.Path != file2.Path {
+		t.Fatalf("Expected shared path: %q != %q", file1.Path, file2.Path)
+	}
+	if file1.Path == file3.Path {
+		t.Fatal("Expected different paths")
+	}
+	if err := manager.DeleteFile(models.WithSync(context.Background()), file1.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	content, err := manager.DownloadFile(context.Background(), file2.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	data, err := io.ReadAll(content)
+	_ = content.Close()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(data) != "test" {
+		t.Fatalf("Expected %q, got %q", "test", string(data))
+	}
+	if err := manager.DeleteFile(models.WithSync(context.Background()), file2.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := os.Stat(filepath.Join(filesDir, filepath.FromSlash(file2.Path))); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("Expected deleted file, got:", err)
+	}
+}
+
+func TestFileCollector(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			// Separate database is used to count removed files.
+			Options: config.SQLiteOptions{Path: filepath.Join(t.TempDir(), "db.sqlite")},
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: t.TempDir(),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	manager := NewFileManager(c)
+	upload := func(content string) models.File {
+		file, err := manager.UploadFile(
+			context.Background(),
+			&FileReader{Reader: bytes.NewReader([]byte(content))},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		return file
+	}
+	pending := upload("pending")
+	orphan := upload("orphan")
+	if err := manager.ConfirmUploadFile(context.Background(), &orphan); err != nil {
+		t.Fatal("Error:", err)
+	}
+	image := upload("image")
+	if err := manager.ConfirmUploadFile(context.Background(), &image); err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "test", ImageID: image.ID}
+	if err := c.Compilers.Create(context.Background(), &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	collector := NewFileCollector(c, manager)
+	now := time.Now()
+	if stats, err := collector.Collect(context.Background(), now); err != nil {
+		t.Fatal("Error:", err)
+	} else if stats.RemovedFiles != 0 {
+		t.Fatal("Expected no removed files, got:", stats.RemovedFiles)
+	}
+	stats, err := collector.Collect(
+		context.Background(), now.Add(collector.gracePeriod+time.Hour),
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if stats.RemovedFiles != 2 {
+		t.Fatal("Expected two removed files, got:", stats.RemovedFiles)
+	}
+	// Size of aborted upload is unknown.
+	if stats.ReclaimedSize != int64(len("orphan")) {
+		t.Fatal("Invalid reclaimed size:", stats.ReclaimedSize)
+	}
+	for _, id := range []int64{pending.ID, orphan.ID} {
+		if _, err := c.Files.Get(models.WithSync(context.Background()), id); err != sql.ErrNoRows {
+			t.Fatal("Expected removed file, got:", err)
+		}
+	}
+	if _, err := c.Files.Get(models.WithSync(context.Background()), image.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func TestFileManagerChunkedUpload(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: ":memory:"},
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: t.TempDir(),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	manager := NewFileManager(c)
+	ctx := context.Background()
+	file, err := manager.CreateFileUpload(ctx, 1, "test.txt", 9)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	upload := func(offset int64, chunk string) error {
+		checksum, _, err := hash.CalculateSHA256(strings.NewReader(chunk))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		return manager.UploadFileChunk(
+			ctx, &file, offset, checksum, strings.NewReader(chunk),
+		)
+	}
+	if err := upload(0, "test "); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := upload(0, "test "); err != ErrFileChunkOffset {
+		t.Fatalf("Expected %v, got %v", ErrFileChunkOffset, err)
+	}
+	if err := upload(5, "data!"); err != ErrFileChunkSize {
+		t.Fatalf("Expected %v, got %v", ErrFileChunkSize, err)
+	}
+	if err := manager.UploadFileChunk(
+		ctx, &file, 5, "invalid", strings.NewReader("data"),
+	); err != ErrFileChunkChecksum {
+		t.Fatalf("Expected %v, got %v", ErrFileChunkChecksum, err)
+	}
+	if err := manager.FinalizeFileUpload(ctx, &file); err != ErrFileUploadIncomplete {
+		t.Fatalf("Expected %v, got %v", ErrFileUploadIncomplete, err)
+	}
+	if err := manager.ConfirmUploadFile(ctx, &file); err != ErrFileUploadIncomplete {
+		t.Fatalf("Expected %v, got %v", ErrFileUploadIncomplete, err)
+	}
+	if err := upload(5, "data"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := manager.FinalizeFileUpload(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Finalization should be idempotent.
+	if err := manager.FinalizeFileUpload(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := upload(9, "more"); err != ErrFileUploadFinalized {
+		t.Fatalf("Expected %v, got %v", ErrFileUploadFinalized, err)
+	}
+	if err := manager.ConfirmUploadFile(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	content, err := manager.DownloadFile(ctx, file.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = content.Close() }()
+	if data, err := io.ReadAll(content); err != nil {
+		t.Fatal("Error:", err)
+	} else if string(data) != "test data" {
+		t.Fatalf("Expected %q, got %q", "test data", string(data))
+	}
+}
+
+// truncatingStorage returns truncated content for first reads.
+type truncatingStorage struct {
+	FileStorage
+	failures int
+}
+
+func (s *truncatingStorage) ReadFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	reader, err := s.FileStorage.ReadFile(ctx, path)
+	if err != nil || s.failures == 0 {
+		return reader, err
+	}
+	s.failures--
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(io.LimitReader(reader, 2))
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestFileManagerDownloadVerifiedFile(t *testing.T) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: ":memory:"},
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: t.TempDir(),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		t.Fatal("Error:", err)
+	}
+	c.Start()
+	defer c.Stop()
+	manager := NewFileManager(c)
+	file, err := manager.UploadFile(
+		context.Background(),
+		&FileReader{Reader: bytes.NewReader([]byte("test"))},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := manager.ConfirmUploadFile(context.Background(), &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	storage := truncatingStorage{FileStorage: manager.storage, failures: 2}
+	manager.storage = &storage
+	targetPath := filepath.Join(t.TempDir(), "file")
+	if err := manager.DownloadVerifiedFile(context.Background(), file.ID, targetPath); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if data, err := os.ReadFile(targetPath); err != nil {
+		t.Fatal("Error:", err)
+	} else if string(data) != "test" {
+		t.Fatalf("Expected %q, got %q", "test", string(data))
+	}
+	storage.failures = maxVerifiedDownloadAttempts
+	if err := manager.DownloadVerifiedFile(
+		context.Background(), file.ID, targetPath,
+	); !errors.Is(err, ErrFileCorrupted) {
+		t.Fatalf("Expected %v, got %v", ErrFileCorrupted, err)
+	}
+}
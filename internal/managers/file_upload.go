@@ -0,0 +1,254 @@
+package managers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/pkg/hash"
+)
+
+var (
+	// ErrFileUploadFinalized means that chunks cannot be added to upload.
+	ErrFileUploadFinalized = fmt.Errorf("file upload already finalized")
+	// ErrFileUploadIncomplete means that not all chunks are uploaded.
+	ErrFileUploadIncomplete = fmt.Errorf("file upload is incomplete")
+	// ErrFileChunkOffset means that chunk does not continue upload.
+	ErrFileChunkOffset = fmt.Errorf("invalid file chunk offset")
+	// ErrFileChunkSize means that chunk is empty or exceeds upload size.
+	ErrFileChunkSize = fmt.Errorf("invalid file chunk size")
+	// ErrFileChunkChecksum means that chunk content is corrupted.
+	ErrFileChunkChecksum = fmt.Errorf("file chunk checksum mismatch")
+)
+
+// fileUploadTimeout contains lifetime of unfinished chunked upload.
+const fileUploadTimeout = 24 * time.Hour
+
+// CreateFileUpload starts chunked upload of file.
+//
+// Chunked upload is represented by pending file, so after finalization
+// it should be confirmed with ConfirmUploadFile like regular upload.
+func (m *FileManager) CreateFileUpload(
+	ctx context.Context, accountID int64, name string, size int64,
+) (models.File, error) {
+	if size <= 0 {
+		return models.File{}, ErrFileChunkSize
+	}
+	filePath, err := m.storage.GeneratePath(ctx)
+	if err != nil {
+		return models.File{}, fmt.Errorf("cannot generate path: %w", err)
+	}
+	file := models.File{
+		Status:     models.PendingFile,
+		ExpireTime: models.NInt64(time.Now().Add(fileUploadTimeout).Unix()),
+		Path:       filePath,
+	}
+	meta := models.FileMeta{
+		Name:   name,
+		Size:   size,
+		Upload: &models.FileUploadMeta{AccountID: accountID},
+	}
+	if err := file.SetMeta(meta); err != nil {
+		return models.File{}, err
+	}
+	if err := m.files.Create(ctx, &file); err != nil {
+		return models.File{}, err
+	}
+	return file, nil
+}
+
+// UploadFileChunk appends chunk to chunked upload.
+//
+// Chunk should start at the end of already uploaded content, so
+// interrupted upload can be resumed from the last stored chunk.
+func (m *FileManager) UploadFileChunk(
+	ctx context.Context, file *models.File, offset int64, checksum string,
+	chunk io.ReadSeeker,
+) error {
+	if tx := db.GetTx(ctx); tx != nil {
+		return fmt.Errorf("cannot upload file chunk in transaction")
+	}
+	meta, err := getFileUploadMeta(*file)
+	if err != nil {
+		return err
+	}
+	if meta.Upload.Finalized {
+		return ErrFileUploadFinalized
+	}
+	uploadedSize := meta.Upload.UploadedSize()
+	if offset != uploadedSize {
+		return ErrFileChunkOffset
+	}
+	sha256, size, err := readFileSHA256(chunk)
+	if err != nil {
+		return err
+	}
+	if size == 0 || uploadedSize+size > meta.Size {
+		return ErrFileChunkSize
+	}
+	if !strings.EqualFold(sha256, checksum) {
+		return ErrFileChunkChecksum
+	}
+	chunkPath := getFileChunkPath(file.Path, len(meta.Upload.Chunks))
+	if _, err := m.storage.WriteFile(ctx, chunkPath, chunk); err != nil {
+		return err
+	}
+	meta.Upload.Chunks = append(meta.Upload.Chunks, models.FileChunkMeta{
+		Size:   size,
+		SHA256: sha256,
+	})
+	clone := file.Clone()
+	if err := clone.SetMeta(meta); err != nil {
+		return err
+	}
+	if err := m.files.Update(ctx, clone); err != nil {
+		return err
+	}
+	*file = clone
+	return nil
+}
+
+// FinalizeFileUpload assembles uploaded chunks into file content.
+//
+// Finalization of already finalized upload does nothing, so it can
+// be safely retried.
+func (m *FileManager) FinalizeFileUpload(
+	ctx context.Context, file *models.File,
+) error {
+	if tx := db.GetTx(ctx); tx != nil {
+		return fmt.Errorf("cannot finalize file upload in transaction")
+	}
+	meta, err := getFileUploadMeta(*file)
+	if err != nil {
+		return err
+	}
+	if meta.Upload.Finalized {
+		return nil
+	}
+	if meta.Upload.UploadedSize() != meta.Size {
+		return ErrFileUploadIncomplete
+	}
+	content, err := os.CreateTemp("", "solve-upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = content.Close()
+		_ = os.Remove(content.Name())
+	}()
+	for i, chunk := range meta.Upload.Chunks {
+		if err := m.copyFileChunk(ctx, content, file.Path, i, chunk); err != nil {
+			return err
+		}
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sha256, size, err := readFileSHA256(content)
+	if err != nil {
+		return err
+	}
+	clone := file.Clone()
+	clone.SHA256 = models.NString(sha256)
+	meta.Upload.Finalized = true
+	origin, err := m.findAvailableFile(ctx, sha256, size)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		originMeta, err := origin.GetMeta()
+		if err != nil {
+			return err
+		}
+		reused := clone.Clone()
+		reused.Path = origin.Path
+		reusedMeta := meta
+		reusedMeta.MD5 = originMeta.MD5
+		if err := reused.SetMeta(reusedMeta); err != nil {
+			return err
+		}
+		if err := m.files.Update(ctx, reused); err != nil {
+			return err
+		}
+		// Origin file can be deleted concurrently, so we should check
+		// that storage object is still referenced by available file.
+		referenced, err := m.hasPathReferences(ctx, reused.Path, reused.ID, true)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			m.deleteFileChunks(ctx, file.Path, len(meta.Upload.Chunks))
+			*file = reused
+			return nil
+		}
+	}
+	stats, err := m.storage.WriteFile(ctx, file.Path, content)
+	if err != nil {
+		return err
+	}
+	meta.MD5 = stats.MD5
+	if err := clone.SetMeta(meta); err != nil {
+		return err
+	}
+	if err := m.files.Update(ctx, clone); err != nil {
+		return err
+	}
+	m.deleteFileChunks(ctx, file.Path, len(meta.Upload.Chunks))
+	*file = clone
+	return nil
+}
+
+// copyFileChunk copies content of uploaded chunk and verifies its
+// checksum.
+func (m *FileManager) copyFileChunk(
+	ctx context.Context, w io.Writer, filePath string, index int,
+	chunk models.FileChunkMeta,
+) error {
+	reader, err := m.storage.ReadFile(ctx, getFileChunkPath(filePath, index))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	sha256, size, err := hash.CalculateSHA256(io.TeeReader(reader, w))
+	if err != nil {
+		return err
+	}
+	if size != chunk.Size || sha256 != chunk.SHA256 {
+		return ErrFileChunkChecksum
+	}
+	return nil
+}
+
+func (m *FileManager) deleteFileChunks(
+	ctx context.Context, filePath string, count int,
+) {
+	for i := 0; i < count; i++ {
+		// Chunks are not referenced by files after finalization, so
+		// failed deletion only leaves garbage in storage.
+		_ = m.storage.DeleteFile(ctx, getFileChunkPath(filePath, i))
+	}
+}
+
+func getFileUploadMeta(file models.File) (models.FileMeta, error) {
+	if file.Status != models.PendingFile {
+		return models.FileMeta{}, fmt.Errorf("file shoud be in pending status")
+	}
+	meta, err := file.GetMeta()
+	if err != nil {
+		return models.FileMeta{}, err
+	}
+	if meta.Upload == nil {
+		return models.FileMeta{}, fmt.Errorf("file is not chunked upload")
+	}
+	return meta, nil
+}
+
+func getFileChunkPath(filePath string, index int) string {
+	return fmt.Sprintf("%s.chunk%d", filePath, index)
+}
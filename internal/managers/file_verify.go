@@ -0,0 +1,98 @@
+package managers
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/udovin/solve/internal/models"
+)
+
+// ErrFileCorrupted means that downloaded content does not match size
+// or hashes of file.
+var ErrFileCorrupted = fmt.Errorf("file content is corrupted")
+
+// maxVerifiedDownloadAttempts contains maximal amount of attempts to
+// download file with valid content.
+const maxVerifiedDownloadAttempts = 3
+
+// DownloadVerifiedFile downloads content of file to specified path and
+// verifies it using size and hashes stored for file.
+//
+// Content is downloaded again from storage on mismatch, so transient
+// storage failures do not produce truncated files.
+func (m *FileManager) DownloadVerifiedFile(
+	ctx context.Context, id int64, targetPath string,
+) error {
+	file, err := m.files.Get(models.WithSync(ctx), id)
+	if err != nil {
+		return err
+	}
+	if err := m.waitFileAvailable(ctx, &file); err != nil {
+		return err
+	}
+	meta, err := file.GetMeta()
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	for i := 0; i < maxVerifiedDownloadAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(i) * 100 * time.Millisecond):
+			}
+		}
+		lastErr = m.downloadVerifiedFile(ctx, file, meta, targetPath)
+		if lastErr == nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("cannot download file %d: %w", id, lastErr)
+}
+
+func (m *FileManager) downloadVerifiedFile(
+	ctx context.Context, file models.File, meta models.FileMeta, targetPath string,
+) error {
+	content, err := m.storage.ReadFile(ctx, file.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = content.Close() }()
+	target, err := os.Create(targetPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = target.Close() }()
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+	size, err := io.Copy(io.MultiWriter(target, md5Hash, sha256Hash), content)
+	if err != nil {
+		return err
+	}
+	if size != meta.Size {
+		return fmt.Errorf(
+			"%w: expected size %d, got %d", ErrFileCorrupted, meta.Size, size,
+		)
+	}
+	if sum := hex.EncodeToString(md5Hash.Sum(nil)); meta.MD5 != "" && sum != meta.MD5 {
+		return fmt.Errorf(
+			"%w: expected MD5 %q, got %q", ErrFileCorrupted, meta.MD5, sum,
+		)
+	}
+	if sum := hex.EncodeToString(sha256Hash.Sum(nil)); file.SHA256 != "" &&
+		sum != string(file.SHA256) {
+		return fmt.Errorf(
+			"%w: expected SHA-256 %q, got %q", ErrFileCorrupted, file.SHA256, sum,
+		)
+	}
+	return target.Close()
+}
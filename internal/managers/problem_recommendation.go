@@ -0,0 +1,238 @@
+package managers
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/models"
+)
+
+// ProblemDifficulty represents estimated difficulty of problem.
+type ProblemDifficulty struct {
+	// Difficulty contains estimated difficulty from 0 (easy) to 1 (hard).
+	Difficulty float64
+	// TotalAuthors contains amount of authors that tried to solve problem.
+	TotalAuthors int
+	// SolvedAuthors contains amount of authors that solved problem.
+	SolvedAuthors int
+}
+
+// ProblemRecommendation represents recommended problem.
+type ProblemRecommendation struct {
+	Problem    models.Problem
+	Difficulty ProblemDifficulty
+}
+
+// ProblemRecommendations represents recommended problems for account.
+type ProblemRecommendations struct {
+	// Skill contains estimated skill of account in units of difficulty.
+	Skill    float64
+	Problems []ProblemRecommendation
+}
+
+// RecommendProblemsOptions represents options for problem recommendations.
+type RecommendProblemsOptions struct {
+	Limit int
+	// Filter returns true for problems that can be recommended.
+	Filter func(models.Problem) bool
+}
+
+type ProblemRecommendationManager struct {
+	problems  *models.ProblemStore
+	solutions *models.SolutionStore
+	cache     *problemDifficultyCache
+	mutex     sync.Mutex
+}
+
+func NewProblemRecommendationManager(core *core.Core) *ProblemRecommendationManager {
+	return &ProblemRecommendationManager{
+		problems:  core.Problems,
+		solutions: core.Solutions,
+	}
+}
+
+const (
+	// problemDifficultyCacheTTL contains lifetime of cached difficulties.
+	problemDifficultyCacheTTL = 10 * time.Minute
+	// defaultProblemSkill contains skill of account without solved
+	// problems.
+	defaultProblemSkill = 0.3
+	// problemSkillStep contains difference between skill of account
+	// and difficulty of recommended problems.
+	problemSkillStep = 0.1
+)
+
+type problemDifficultyCache struct {
+	Done         <-chan struct{}
+	Time         time.Time
+	Difficulties map[int64]ProblemDifficulty
+	Error        error
+}
+
+// isSolutionAttempt returns true when solution is judged and can be
+// treated as attempt to solve problem.
+func isSolutionAttempt(report *models.SolutionReport) bool {
+	return report != nil && report.Verdict != 0 &&
+		report.Verdict != models.CompilationError
+}
+
+// getDifficulties returns difficulties of problems by problem ID.
+func (m *ProblemRecommendationManager) getDifficulties(
+	ctx context.Context,
+) (map[int64]ProblemDifficulty, error) {
+	now := models.GetNow(ctx)
+	m.mutex.Lock()
+	if cache := m.cache; cache != nil {
+		select {
+		case <-cache.Done:
+			if cache.Error == nil && now.Sub(cache.Time) < problemDifficultyCacheTTL {
+				m.mutex.Unlock()
+				return cache.Difficulties, nil
+			}
+		default:
+			m.mutex.Unlock()
+			<-cache.Done
+			return cache.Difficulties, cache.Error
+		}
+	}
+	done := make(chan struct{})
+	defer close(done)
+	cache := &problemDifficultyCache{Done: done, Time: now}
+	m.cache = cache
+	m.mutex.Unlock()
+	cache.Difficulties, cache.Error = m.buildDifficulties(ctx)
+	return cache.Difficulties, cache.Error
+}
+
+func (m *ProblemRecommendationManager) buildDifficulties(
+	ctx context.Context,
+) (map[int64]ProblemDifficulty, error) {
+	type authorProblem struct {
+		AuthorID  int64
+		ProblemID int64
+	}
+	// solved contains true for authors that solved problem.
+	solved := map[authorProblem]bool{}
+	rows, err := m.solutions.All(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		solution := rows.Row()
+		report, err := solution.GetReport()
+		if err != nil || !isSolutionAttempt(report) {
+			continue
+		}
+		key := authorProblem{AuthorID: solution.AuthorID, ProblemID: solution.ProblemID}
+		solved[key] = solved[key] || report.Verdict == models.Accepted
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	difficulties := map[int64]ProblemDifficulty{}
+	for key, ok := range solved {
+		difficulty := difficulties[key.ProblemID]
+		difficulty.TotalAuthors++
+		if ok {
+			difficulty.SolvedAuthors++
+		}
+		difficulties[key.ProblemID] = difficulty
+	}
+	for id, difficulty := range difficulties {
+		difficulty.Difficulty = getProblemDifficulty(
+			difficulty.TotalAuthors, difficulty.SolvedAuthors,
+		)
+		difficulties[id] = difficulty
+	}
+	return difficulties, nil
+}
+
+// getProblemDifficulty returns smoothed share of authors that failed
+// to solve problem.
+func getProblemDifficulty(total, solved int) float64 {
+	return float64(total-solved+1) / float64(total+2)
+}
+
+// RecommendProblems returns unsolved problems with difficulty close to
+// estimated skill of account.
+//
+// Skill of account is average difficulty of problems solved by account.
+func (m *ProblemRecommendationManager) RecommendProblems(
+	ctx *AccountContext, options RecommendProblemsOptions,
+) (*ProblemRecommendations, error) {
+	difficulties, err := m.getDifficulties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	solved := map[int64]struct{}{}
+	if account := ctx.Account; account != nil {
+		rows, err := m.solutions.FindByAuthor(ctx, account.ID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			solution := rows.Row()
+			report, err := solution.GetReport()
+			if err == nil && report != nil && report.Verdict == models.Accepted {
+				solved[solution.ProblemID] = struct{}{}
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	getDifficulty := func(problemID int64) ProblemDifficulty {
+		if difficulty, ok := difficulties[problemID]; ok {
+			return difficulty
+		}
+		return ProblemDifficulty{Difficulty: getProblemDifficulty(0, 0)}
+	}
+	result := ProblemRecommendations{Skill: defaultProblemSkill}
+	if len(solved) > 0 {
+		sum := 0.0
+		for problemID := range solved {
+			sum += getDifficulty(problemID).Difficulty
+		}
+		result.Skill = sum / float64(len(solved))
+	}
+	target := math.Min(result.Skill+problemSkillStep, 1)
+	rows, err := m.problems.All(ctx, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		problem := rows.Row()
+		if _, ok := solved[problem.ID]; ok {
+			continue
+		}
+		if options.Filter != nil && !options.Filter(problem) {
+			continue
+		}
+		result.Problems = append(result.Problems, ProblemRecommendation{
+			Problem:    problem,
+			Difficulty: getDifficulty(problem.ID),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result.Problems, func(i, j int) bool {
+		lhs := math.Abs(result.Problems[i].Difficulty.Difficulty - target)
+		rhs := math.Abs(result.Problems[j].Difficulty.Difficulty - target)
+		if lhs != rhs {
+			return lhs < rhs
+		}
+		return result.Problems[i].Problem.ID < result.Problems[j].Problem.ID
+	})
+	if options.Limit > 0 && len(result.Problems) > options.Limit {
+		result.Problems = result.Problems[:options.Limit]
+	}
+	return &result, nil
+}
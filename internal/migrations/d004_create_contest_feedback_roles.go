@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("004_create_contest_feedback_roles", d004{})
+}
+
+type d004 struct{}
+
+func (m d004) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveContestFeedbackRole,
+		perms.SubmitContestFeedbackRole,
+	)
+}
+
+func (m d004) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
+
+// addBuiltInRoles creates built-in roles that were added after
+// initial migration and grants them to admin group.
+func addBuiltInRoles(ctx context.Context, db *gosql.DB, names ...string) error {
+	roleStore := models.NewRoleStore(db, "solve_role", "solve_role_event")
+	roleEdgeStore := models.NewRoleEdgeStore(db, "solve_role_edge", "solve_role_edge_event")
+	adminRole, err := roleStore.FindOne(ctx, FindQuery{
+		Where: gosql.Column("name").Equal("admin_group"),
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		role, err := roleStore.FindOne(ctx, FindQuery{
+			Where: gosql.Column("name").Equal(name),
+		})
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			role = models.Role{Name: name}
+			if err := roleStore.Create(ctx, &role); err != nil {
+				return err
+			}
+		}
+		if _, err := roleEdgeStore.FindOne(ctx, FindQuery{
+			Where: gosql.Column("role_id").Equal(adminRole.ID).
+				And(gosql.Column("child_id").Equal(role.ID)),
+		}); err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			edge := models.RoleEdge{RoleID: adminRole.ID, ChildID: role.ID}
+			if err := roleEdgeStore.Create(ctx, &edge); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("005_create_finalize_contest_role", d005{})
+}
+
+type d005 struct{}
+
+func (m d005) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(ctx, db, perms.FinalizeContestRole)
+}
+
+func (m d005) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
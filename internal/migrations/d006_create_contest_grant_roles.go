@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("006_create_contest_grant_roles", d006{})
+}
+
+type d006 struct{}
+
+func (m d006) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveContestGrantsRole,
+		perms.CreateContestGrantRole,
+		perms.DeleteContestGrantRole,
+	)
+}
+
+func (m d006) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
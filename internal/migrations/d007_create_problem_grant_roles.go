@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("007_create_problem_grant_roles", d007{})
+}
+
+type d007 struct{}
+
+func (m d007) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveProblemGrantsRole,
+		perms.CreateProblemGrantRole,
+		perms.DeleteProblemGrantRole,
+	)
+}
+
+func (m d007) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("008_create_contest_appeal_roles", d008{})
+}
+
+type d008 struct{}
+
+func (m d008) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveContestAppealsRole,
+		perms.CreateContestAppealRole,
+		perms.ResolveContestAppealRole,
+	)
+}
+
+func (m d008) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
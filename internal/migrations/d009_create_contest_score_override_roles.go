@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("009_create_contest_score_override_roles", d009{})
+}
+
+type d009 struct{}
+
+func (m d009) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveContestScoreOverridesRole,
+		perms.CreateContestScoreOverrideRole,
+		perms.DeleteContestScoreOverrideRole,
+	)
+}
+
+func (m d009) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("010_create_group_role_roles", d010{})
+}
+
+type d010 struct{}
+
+func (m d010) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveGroupRolesRole,
+		perms.CreateGroupRoleRole,
+		perms.DeleteGroupRoleRole,
+	)
+}
+
+func (m d010) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("011_create_merge_accounts_role", d011{})
+}
+
+type d011 struct{}
+
+func (m d011) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(ctx, db, perms.MergeAccountsRole)
+}
+
+func (m d011) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("012_create_register_contest_observer_role", d012{})
+}
+
+type d012 struct{}
+
+func (m d012) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(ctx, db, perms.RegisterContestObserverRole)
+}
+
+func (m d012) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("013_create_contest_dashboard_role", d013{})
+}
+
+type d013 struct{}
+
+func (m d013) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(ctx, db, perms.ObserveContestDashboardRole)
+}
+
+func (m d013) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("014_create_export_contest_solutions_role", d014{})
+}
+
+type d014 struct{}
+
+func (m d014) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(ctx, db, perms.ExportContestSolutionsRole)
+}
+
+func (m d014) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("015_create_contest_editorial_roles", d015{})
+}
+
+type d015 struct{}
+
+func (m d015) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveContestEditorialsRole,
+		perms.ManageContestEditorialsRole,
+	)
+}
+
+func (m d015) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
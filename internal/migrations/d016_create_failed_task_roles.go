@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("016_create_failed_task_roles", d016{})
+}
+
+type d016 struct{}
+
+func (m d016) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(
+		ctx, db,
+		perms.ObserveFailedTasksRole,
+		perms.RequeueFailedTasksRole,
+	)
+}
+
+func (m d016) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
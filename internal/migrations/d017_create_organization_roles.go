@@ -0,0 +1,75 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("017_create_organization_roles", d017{})
+}
+
+type d017 struct{}
+
+func (m d017) Apply(ctx context.Context, db *gosql.DB) error {
+	if err := addBuiltInRoles(
+		ctx, db,
+		perms.ObserveOrganizationsRole,
+		perms.CreateOrganizationRole,
+		perms.UpdateOrganizationRole,
+		perms.DeleteOrganizationRole,
+		perms.UpdateUserCountryRole,
+		perms.UpdateUserOrganizationRole,
+	); err != nil {
+		return err
+	}
+	return addGroupRole(
+		ctx, db, perms.ObserveOrganizationsRole,
+		"guest_group",
+		"pending_user_group",
+		"active_user_group",
+		"blocked_user_group",
+		"scope_user_group",
+	)
+}
+
+func (m d017) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
+
+// addGroupRole grants existing role to specified groups.
+func addGroupRole(ctx context.Context, db *gosql.DB, name string, groups ...string) error {
+	roleStore := models.NewRoleStore(db, "solve_role", "solve_role_event")
+	roleEdgeStore := models.NewRoleEdgeStore(db, "solve_role_edge", "solve_role_edge_event")
+	role, err := roleStore.FindOne(ctx, FindQuery{
+		Where: gosql.Column("name").Equal(name),
+	})
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		groupRole, err := roleStore.FindOne(ctx, FindQuery{
+			Where: gosql.Column("name").Equal(group),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := roleEdgeStore.FindOne(ctx, FindQuery{
+			Where: gosql.Column("role_id").Equal(groupRole.ID).
+				And(gosql.Column("child_id").Equal(role.ID)),
+		}); err != nil {
+			if err != sql.ErrNoRows {
+				return err
+			}
+			edge := models.RoleEdge{RoleID: groupRole.ID, ChildID: role.ID}
+			if err := roleEdgeStore.Create(ctx, &edge); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/perms"
+)
+
+func init() {
+	Data.AddMigration("018_create_mashup_contest_role", d018{})
+}
+
+type d018 struct{}
+
+func (m d018) Apply(ctx context.Context, db *gosql.DB) error {
+	return addBuiltInRoles(ctx, db, perms.CreateMashupContestRole)
+}
+
+func (m d018) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
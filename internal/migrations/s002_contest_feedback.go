@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("002_contest_feedback", db.NewMigration(s002))
+}
+
+var s002 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_feedback",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "answers", Type: schema.JSON},
+			{Name: "create_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "participant_id", ParentTable: "solve_contest_participant", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_feedback",
+		Columns: []string{"participant_id"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_contest_feedback_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "answers", Type: schema.JSON},
+			{Name: "create_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_feedback_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("003_contest_result", db.NewMigration(s003))
+}
+
+var s003 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_result",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64, Nullable: true},
+			{Name: "results", Type: schema.JSON},
+			{Name: "create_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "author_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_result",
+		Columns: []string{"contest_id"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_contest_result_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64, Nullable: true},
+			{Name: "results", Type: schema.JSON},
+			{Name: "create_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_result_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
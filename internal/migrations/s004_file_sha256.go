@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("004_file_sha256", db.NewMigration(s004))
+}
+
+var s004 = []schema.Operation{
+	schema.AddColumn{
+		Table:  "solve_file",
+		Column: schema.Column{Name: "sha256", Type: schema.String, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_file_event",
+		Column: schema.Column{Name: "sha256", Type: schema.String, Nullable: true},
+	},
+	schema.CreateIndex{
+		Table:   "solve_file",
+		Columns: []string{"sha256"},
+	},
+	// Files with the same content share storage path, so unique
+	// index is replaced. Uniqueness is not restored on unapply
+	// because deduplicated files can already exist.
+	schema.DropIndex{
+		Table:   "solve_file",
+		Columns: []string{"path"},
+	},
+	schema.CreateIndex{
+		Table:   "solve_file",
+		Columns: []string{"path", "id"},
+	},
+}
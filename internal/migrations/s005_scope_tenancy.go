@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("005_scope_tenancy", db.NewMigration(s005))
+}
+
+var s005 = []schema.Operation{
+	schema.AddColumn{
+		Table:  "solve_contest",
+		Column: schema.Column{Name: "scope_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_contest_event",
+		Column: schema.Column{Name: "scope_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest",
+		Columns: []string{"scope_id"},
+	},
+	schema.AddColumn{
+		Table:  "solve_problem",
+		Column: schema.Column{Name: "scope_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_problem_event",
+		Column: schema.Column{Name: "scope_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.CreateIndex{
+		Table:   "solve_problem",
+		Columns: []string{"scope_id"},
+	},
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("006_contest_grant", db.NewMigration(s006))
+}
+
+var s006 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_grant",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "kind", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "account_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_grant",
+		Columns: []string{"contest_id", "account_id", "kind"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_contest_grant_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "kind", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_grant_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("008_contest_appeal", db.NewMigration(s008))
+}
+
+var s008 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_appeal",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "solution_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "status", Type: schema.Int64},
+			{Name: "message", Type: schema.String},
+			{Name: "comments", Type: schema.JSON, Nullable: true},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "resolver_id", Type: schema.Int64, Nullable: true},
+			{Name: "resolve_time", Type: schema.Int64, Nullable: true},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "solution_id", ParentTable: "solve_contest_solution", ParentColumn: "id"},
+			{Column: "participant_id", ParentTable: "solve_contest_participant", ParentColumn: "id"},
+			{Column: "author_id", ParentTable: "solve_account", ParentColumn: "id"},
+			{Column: "resolver_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_appeal",
+		Columns: []string{"contest_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_appeal_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "solution_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "status", Type: schema.Int64},
+			{Name: "message", Type: schema.String},
+			{Name: "comments", Type: schema.JSON, Nullable: true},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "resolver_id", Type: schema.Int64, Nullable: true},
+			{Name: "resolve_time", Type: schema.Int64, Nullable: true},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_appeal_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("009_contest_score_override", db.NewMigration(s009))
+}
+
+var s009 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_score_override",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "value", Type: schema.JSON},
+			{Name: "reason", Type: schema.String},
+			{Name: "create_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "participant_id", ParentTable: "solve_contest_participant", ParentColumn: "id"},
+			{Column: "problem_id", ParentTable: "solve_contest_problem", ParentColumn: "id"},
+			{Column: "author_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_score_override",
+		Columns: []string{"participant_id", "problem_id"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_contest_score_override_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "value", Type: schema.JSON},
+			{Name: "reason", Type: schema.String},
+			{Name: "create_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_score_override_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("010_problem_extra_test", db.NewMigration(s010))
+}
+
+var s010 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_problem_extra_test",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "input_id", Type: schema.Int64},
+			{Name: "answer_id", Type: schema.Int64},
+			{Name: "status", Type: schema.Int64},
+			{Name: "message", Type: schema.String},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "problem_id", ParentTable: "solve_problem", ParentColumn: "id"},
+			{Column: "input_id", ParentTable: "solve_file", ParentColumn: "id"},
+			{Column: "answer_id", ParentTable: "solve_file", ParentColumn: "id"},
+			{Column: "author_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateTable{
+		Name: "solve_problem_extra_test_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "input_id", Type: schema.Int64},
+			{Name: "answer_id", Type: schema.Int64},
+			{Name: "status", Type: schema.Int64},
+			{Name: "message", Type: schema.String},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_problem_extra_test_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("011_account_lock", db.NewMigration(s011))
+}
+
+var s011 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_account_lock",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "failed_attempts", Type: schema.Int64},
+			{Name: "attempt_time", Type: schema.Int64},
+			{Name: "expire_time", Type: schema.Int64, Nullable: true},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "account_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_account_lock",
+		Unique:  true,
+		Columns: []string{"account_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_account_lock_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "failed_attempts", Type: schema.Int64},
+			{Name: "attempt_time", Type: schema.Int64},
+			{Name: "expire_time", Type: schema.Int64, Nullable: true},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_account_lock_event",
+		Columns: []string{"id", "event_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_login_attempt",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "time", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "status", Type: schema.Int64},
+			{Name: "user_agent", Type: schema.String},
+			{Name: "real_ip", Type: schema.String},
+		},
+	},
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("012_account_merge", db.NewMigration(s012))
+}
+
+var s012 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_account_merge",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "time", Type: schema.Int64},
+			{Name: "source_id", Type: schema.Int64},
+			{Name: "target_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64, Nullable: true},
+			{Name: "summary", Type: schema.JSON},
+		},
+	},
+}
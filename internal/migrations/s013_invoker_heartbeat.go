@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("013_invoker_heartbeat", db.NewMigration(s013))
+}
+
+var s013 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_invoker_heartbeat",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "name", Type: schema.String},
+			{Name: "workers", Type: schema.Int64},
+			{Name: "ping_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_invoker_heartbeat",
+		Columns: []string{"name"},
+		Unique:  true,
+	},
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("014_contest_participant_address", db.NewMigration(s014))
+}
+
+var s014 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_participant_address",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "session_id", Type: schema.Int64, Nullable: true},
+			{Name: "real_ip", Type: schema.String},
+			{Name: "user_agent", Type: schema.String},
+			{Name: "first_time", Type: schema.Int64},
+			{Name: "last_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_participant_address",
+		Columns: []string{"contest_id"},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_participant_address",
+		Columns: []string{"participant_id"},
+	},
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("015_contest_editorial", db.NewMigration(s015))
+}
+
+var s015 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_editorial",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64, Nullable: true},
+			{Name: "locale", Type: schema.String},
+			{Name: "name", Type: schema.String},
+			{Name: "file_id", Type: schema.Int64},
+			{Name: "publish_time", Type: schema.Int64, Nullable: true},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "file_id", ParentTable: "solve_file", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_editorial",
+		Columns: []string{"contest_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_editorial_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64, Nullable: true},
+			{Name: "locale", Type: schema.String},
+			{Name: "name", Type: schema.String},
+			{Name: "file_id", Type: schema.Int64},
+			{Name: "publish_time", Type: schema.Int64, Nullable: true},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_editorial_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
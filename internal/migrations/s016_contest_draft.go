@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("016_contest_draft", db.NewMigration(s016))
+}
+
+var s016 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_draft",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "compiler_id", Type: schema.Int64, Nullable: true},
+			{Name: "content", Type: schema.String},
+			{Name: "update_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_draft",
+		Columns: []string{"participant_id", "problem_id"},
+		Unique:  true,
+	},
+}
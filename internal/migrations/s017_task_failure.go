@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("017_task_failure", db.NewMigration(s017))
+}
+
+var s017 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_task_failure",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "task_id", Type: schema.Int64},
+			{Name: "error", Type: schema.String},
+			{Name: "stack", Type: schema.String},
+			{Name: "environment", Type: schema.JSON},
+			{Name: "fail_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_task_failure",
+		Columns: []string{"task_id"},
+		Unique:  true,
+	},
+}
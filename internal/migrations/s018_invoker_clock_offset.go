@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("018_invoker_clock_offset", db.NewMigration(s018))
+}
+
+var s018 = []schema.Operation{
+	schema.AddColumn{
+		Table:  "solve_invoker_heartbeat",
+		Column: schema.Column{Name: "clock_offset", Type: schema.Int64, Nullable: true},
+	},
+}
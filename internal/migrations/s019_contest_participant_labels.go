@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("019_contest_participant_labels", db.NewMigration(s019))
+}
+
+var s019 = []schema.Operation{
+	schema.AddColumn{
+		Table:  "solve_contest_participant",
+		Column: schema.Column{Name: "labels", Type: schema.JSON, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_contest_participant_event",
+		Column: schema.Column{Name: "labels", Type: schema.JSON, Nullable: true},
+	},
+}
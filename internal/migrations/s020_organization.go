@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("020_organization", db.NewMigration(s020))
+}
+
+var s020 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_organization",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "title", Type: schema.String},
+			{Name: "country", Type: schema.String, Nullable: true},
+		},
+	},
+	schema.CreateTable{
+		Name: "solve_organization_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "title", Type: schema.String},
+			{Name: "country", Type: schema.String, Nullable: true},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_organization_event",
+		Columns: []string{"id", "event_id"},
+	},
+	schema.AddColumn{
+		Table:  "solve_user",
+		Column: schema.Column{Name: "country", Type: schema.String, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_user",
+		Column: schema.Column{Name: "organization_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_user_event",
+		Column: schema.Column{Name: "country", Type: schema.String, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_user_event",
+		Column: schema.Column{Name: "organization_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_scope_user",
+		Column: schema.Column{Name: "country", Type: schema.String, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_scope_user",
+		Column: schema.Column{Name: "organization_id", Type: schema.Int64, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_scope_user_event",
+		Column: schema.Column{Name: "country", Type: schema.String, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_scope_user_event",
+		Column: schema.Column{Name: "organization_id", Type: schema.Int64, Nullable: true},
+	},
+}
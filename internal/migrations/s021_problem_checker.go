@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("021_problem_checker", db.NewMigration(s021))
+}
+
+var s021 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_problem_checker",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "compiler_id", Type: schema.Int64},
+			{Name: "source_id", Type: schema.Int64, Nullable: true},
+			{Name: "binary_id", Type: schema.Int64, Nullable: true},
+			{Name: "status", Type: schema.Int64},
+			{Name: "message", Type: schema.String},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "problem_id", ParentTable: "solve_problem", ParentColumn: "id"},
+			{Column: "compiler_id", ParentTable: "solve_compiler", ParentColumn: "id"},
+			{Column: "source_id", ParentTable: "solve_file", ParentColumn: "id"},
+			{Column: "binary_id", ParentTable: "solve_file", ParentColumn: "id"},
+			{Column: "author_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateTable{
+		Name: "solve_problem_checker_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "compiler_id", Type: schema.Int64},
+			{Name: "source_id", Type: schema.Int64, Nullable: true},
+			{Name: "binary_id", Type: schema.Int64, Nullable: true},
+			{Name: "status", Type: schema.Int64},
+			{Name: "message", Type: schema.String},
+			{Name: "author_id", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_problem_checker_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
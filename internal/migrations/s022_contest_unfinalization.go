@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("022_contest_unfinalization", db.NewMigration(s022))
+}
+
+var s022 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_unfinalization",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "time", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64, Nullable: true},
+			{Name: "reason", Type: schema.String},
+		},
+	},
+}
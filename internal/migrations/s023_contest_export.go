@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("023_contest_export", db.NewMigration(s023))
+}
+
+var s023 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_export",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "status", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "expire_time", Type: schema.Int64},
+			{Name: "file_id", Type: schema.Int64, Nullable: true},
+			{Name: "size", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_export",
+		Columns: []string{"contest_id"},
+		Unique:  true,
+	},
+}
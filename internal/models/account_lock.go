@@ -0,0 +1,70 @@
+package models
+
+import (
+	"github.com/udovin/gosql"
+)
+
+// AccountLock represents state of failed login attempts of account.
+type AccountLock struct {
+	baseObject
+	AccountID int64 `db:"account_id"`
+	// FailedAttempts contains amount of consecutive failed login
+	// attempts.
+	FailedAttempts int64 `db:"failed_attempts"`
+	// AttemptTime contains time of last failed login attempt.
+	AttemptTime int64 `db:"attempt_time"`
+	// ExpireTime contains time until which account is locked.
+	ExpireTime NInt64 `db:"expire_time"`
+}
+
+// Clone creates copy of account lock.
+func (o AccountLock) Clone() AccountLock {
+	return o
+}
+
+// IsLocked returns true if account is locked at specified time.
+func (o AccountLock) IsLocked(now int64) bool {
+	return now < int64(o.ExpireTime)
+}
+
+// AccountLockEvent represents an account lock event.
+type AccountLockEvent struct {
+	baseEvent
+	AccountLock
+}
+
+// Object returns event account lock.
+func (e AccountLockEvent) Object() AccountLock {
+	return e.AccountLock
+}
+
+// SetObject sets event account lock.
+func (e *AccountLockEvent) SetObject(o AccountLock) {
+	e.AccountLock = o
+}
+
+// AccountLockStore represents an account lock store.
+type AccountLockStore struct {
+	cachedStore[AccountLock, AccountLockEvent, *AccountLock, *AccountLockEvent]
+	byAccount *btreeIndex[int64, AccountLock, *AccountLock]
+}
+
+// GetByAccount returns lock by account ID.
+func (s *AccountLockStore) GetByAccount(accountID int64) (AccountLock, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return btreeIndexGet(s.byAccount, s.objects.Iter(), accountID)
+}
+
+// NewAccountLockStore creates a new instance of AccountLockStore.
+func NewAccountLockStore(
+	db *gosql.DB, table, eventTable string,
+) *AccountLockStore {
+	impl := &AccountLockStore{
+		byAccount: newBTreeIndex(func(o AccountLock) (int64, bool) { return o.AccountID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[AccountLock, AccountLockEvent](
+		db, table, eventTable, impl, impl.byAccount,
+	)
+	return impl
+}
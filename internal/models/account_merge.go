@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// AccountMergeSummary contains amount of objects moved during merge.
+type AccountMergeSummary struct {
+	Solutions    int `json:"solutions,omitempty"`
+	Participants int `json:"participants,omitempty"`
+	Sessions     int `json:"sessions,omitempty"`
+	Roles        int `json:"roles,omitempty"`
+	GroupMembers int `json:"group_members,omitempty"`
+}
+
+// AccountMerge represents audit record of accounts merge.
+type AccountMerge struct {
+	ID int64 `db:"id"`
+	// Time contains time of merge.
+	Time int64 `db:"time"`
+	// SourceID contains ID of merged account.
+	SourceID int64 `db:"source_id"`
+	// TargetID contains ID of account that received objects.
+	TargetID int64 `db:"target_id"`
+	// AuthorID contains ID of account that performed merge.
+	AuthorID NInt64 `db:"author_id"`
+	// Summary contains summary of moved objects.
+	Summary JSON `db:"summary"`
+}
+
+// EventID returns ID of accounts merge.
+func (o AccountMerge) EventID() int64 {
+	return o.ID
+}
+
+// SetEventID sets ID of accounts merge.
+func (o *AccountMerge) SetEventID(id int64) {
+	o.ID = id
+}
+
+// EventTime return time of accounts merge.
+func (o AccountMerge) EventTime() time.Time {
+	return time.Unix(o.Time, 0)
+}
+
+// GetSummary returns summary of accounts merge.
+func (o AccountMerge) GetSummary() (AccountMergeSummary, error) {
+	var summary AccountMergeSummary
+	if len(o.Summary) == 0 {
+		return summary, nil
+	}
+	err := json.Unmarshal(o.Summary, &summary)
+	return summary, err
+}
+
+// SetSummary updates summary of accounts merge.
+func (o *AccountMerge) SetSummary(summary AccountMergeSummary) error {
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	o.Summary = raw
+	return nil
+}
+
+// AccountMergeStore represents store of accounts merges.
+type AccountMergeStore struct {
+	db     *gosql.DB
+	events db.EventStore[AccountMerge, *AccountMerge]
+}
+
+// Create creates a new accounts merge record.
+func (s *AccountMergeStore) Create(ctx context.Context, merge *AccountMerge) error {
+	return s.events.CreateEvent(ctx, merge)
+}
+
+// NewAccountMergeStore creates a new instance of AccountMergeStore.
+func NewAccountMergeStore(dbConn *gosql.DB, table string) *AccountMergeStore {
+	return &AccountMergeStore{
+		db:     dbConn,
+		events: db.NewEventStore[AccountMerge]("id", table, dbConn),
+	}
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"unicode/utf8"
 
 	"github.com/udovin/gosql"
 )
@@ -14,12 +17,52 @@ type CompilerCommandConfig struct {
 	Binary  *string  `json:"binary,omitempty"`
 }
 
+// CompilerNormalizeConfig represents normalization of submitted sources.
+type CompilerNormalizeConfig struct {
+	// LineEndings contains line endings of normalized source.
+	//
+	// Supported values are "lf" and "crlf".
+	LineEndings string `json:"line_endings,omitempty"`
+	// StripBOM enables removal of UTF-8 byte order mark.
+	StripBOM bool `json:"strip_bom,omitempty"`
+	// RequireUTF8 enables rejection of sources that are not valid UTF-8.
+	RequireUTF8 bool `json:"require_utf8,omitempty"`
+}
+
+// ErrInvalidUTF8 means that source is not valid UTF-8 text.
+var ErrInvalidUTF8 = fmt.Errorf("source is not valid UTF-8")
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Apply returns normalized source.
+func (c CompilerNormalizeConfig) Apply(source []byte) ([]byte, error) {
+	if c.StripBOM {
+		source = bytes.TrimPrefix(source, utf8BOM)
+	}
+	if c.RequireUTF8 && !utf8.Valid(source) {
+		return nil, ErrInvalidUTF8
+	}
+	switch c.LineEndings {
+	case "":
+	case "lf":
+		source = bytes.ReplaceAll(source, []byte("\r\n"), []byte("\n"))
+	case "crlf":
+		source = bytes.ReplaceAll(source, []byte("\r\n"), []byte("\n"))
+		source = bytes.ReplaceAll(source, []byte("\n"), []byte("\r\n"))
+	default:
+		return nil, fmt.Errorf("unsupported line endings: %q", c.LineEndings)
+	}
+	return source, nil
+}
+
 type CompilerConfig struct {
 	Language   string                 `json:"language,omitempty"`
 	Compiler   string                 `json:"compiler,omitempty"`
 	Extensions []string               `json:"extensions"`
 	Compile    *CompilerCommandConfig `json:"compile,omitempty"`
 	Execute    *CompilerCommandConfig `json:"execute,omitempty"`
+	// Normalize contains normalization of submitted sources.
+	Normalize *CompilerNormalizeConfig `json:"normalize,omitempty"`
 }
 
 // Compiler represents compiler.
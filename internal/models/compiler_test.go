@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCompilerNormalizeConfig(t *testing.T) {
+	tests := []struct {
+		Config CompilerNormalizeConfig
+		Source string
+		Result string
+		Err    error
+	}{
+		{CompilerNormalizeConfig{}, "a\r\nb\n", "a\r\nb\n", nil},
+		{CompilerNormalizeConfig{LineEndings: "lf"}, "a\r\nb\n", "a\nb\n", nil},
+		{CompilerNormalizeConfig{LineEndings: "crlf"}, "a\r\nb\n", "a\r\nb\r\n", nil},
+		{CompilerNormalizeConfig{StripBOM: true}, "\xEF\xBB\xBFa\n", "a\n", nil},
+		{CompilerNormalizeConfig{RequireUTF8: true}, "\xFFa\n", "", ErrInvalidUTF8},
+		{CompilerNormalizeConfig{RequireUTF8: true}, "привет\n", "привет\n", nil},
+	}
+	for _, test := range tests {
+		result, err := test.Config.Apply([]byte(test.Source))
+		if err != test.Err {
+			t.Fatalf("Expected %v, got %v", test.Err, err)
+		}
+		if string(result) != test.Result {
+			t.Fatalf("Expected %q, got %q", test.Result, result)
+		}
+	}
+	if _, err := (CompilerNormalizeConfig{LineEndings: "cr"}).Apply(nil); err == nil {
+		t.Fatal("Expected error")
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/udovin/gosql"
 )
@@ -46,6 +47,81 @@ func (v *StandingsKind) UnmarshalText(data []byte) error {
 	return nil
 }
 
+type ContestFeedbackQuestionKind string
+
+const (
+	// RatingFeedbackQuestion represents question with answer from 1 to 5.
+	RatingFeedbackQuestion ContestFeedbackQuestionKind = "rating"
+	// ChoiceFeedbackQuestion represents question with one of options.
+	ChoiceFeedbackQuestion ContestFeedbackQuestionKind = "choice"
+	// TextFeedbackQuestion represents question with free form answer.
+	TextFeedbackQuestion ContestFeedbackQuestionKind = "text"
+)
+
+type ContestFeedbackQuestion struct {
+	Name     string                      `json:"name"`
+	Title    string                      `json:"title"`
+	Kind     ContestFeedbackQuestionKind `json:"kind"`
+	Options  []string                    `json:"options,omitempty"`
+	Required bool                        `json:"required,omitempty"`
+}
+
+type ContestFeedbackConfig struct {
+	Questions []ContestFeedbackQuestion `json:"questions"`
+	// Anonymous means that organizers cannot see authors of feedbacks.
+	Anonymous bool `json:"anonymous,omitempty"`
+}
+
+// ContestAwardsConfig represents rules for award computation.
+//
+// Awards are given in place order, so participants with
+// the same place always get the same award.
+type ContestAwardsConfig struct {
+	// Gold contains amount of gold medals.
+	Gold int `json:"gold,omitempty"`
+	// Silver contains amount of silver medals.
+	Silver int `json:"silver,omitempty"`
+	// Bronze contains amount of bronze medals.
+	Bronze int `json:"bronze,omitempty"`
+	// MinScore contains minimal score required for any award.
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+// ContestActionKind represents kind of scheduled contest action.
+type ContestActionKind string
+
+const (
+	// AnnouncementContestAction publishes contest message.
+	AnnouncementContestAction ContestActionKind = "announcement"
+	// FreezeContestAction freezes contest standings.
+	FreezeContestAction ContestActionKind = "freeze"
+	// UnfreezeContestAction unfreezes contest standings.
+	UnfreezeContestAction ContestActionKind = "unfreeze"
+	// CloseRegistrationContestAction disables contest registration.
+	CloseRegistrationContestAction ContestActionKind = "close_registration"
+	// FinalizeStandingsContestAction makes contest standings final.
+	FinalizeStandingsContestAction ContestActionKind = "finalize_standings"
+	// SystemTestContestAction starts system testing of contest.
+	SystemTestContestAction ContestActionKind = "system_test"
+)
+
+// ContestAction represents action that should be executed
+// at specified contest time.
+type ContestAction struct {
+	Kind ContestActionKind `json:"kind"`
+	// Time contains contest time of action in seconds.
+	//
+	// Time can be greater than contest duration, so action
+	// will be executed after contest is finished.
+	Time int64 `json:"time"`
+	// Title contains title of announcement.
+	Title string `json:"title,omitempty"`
+	// Description contains description of announcement.
+	Description string `json:"description,omitempty"`
+	// ExecuteTime contains time when action was executed.
+	ExecuteTime NInt64 `json:"execute_time,omitempty"`
+}
+
 type ContestConfig struct {
 	BeginTime           NInt64        `json:"begin_time,omitempty"`
 	Duration            int           `json:"duration,omitempty"`
@@ -56,6 +132,73 @@ type ContestConfig struct {
 	FreezeBeginDuration int           `json:"freeze_begin_duration,omitempty"`
 	FreezeEndTime       NInt64        `json:"freeze_end_time,omitempty"`
 	StandingsKind       StandingsKind `json:"standings_kind,omitempty"`
+	// PenaltyTime contains penalty in minutes for every rejected
+	// attempt of solved problem in ICPC standings.
+	//
+	// Nil value means default penalty time.
+	PenaltyTime *int `json:"penalty_time,omitempty"`
+	// HideFrozenAttempts disables display of amount of attempts
+	// that are made during freeze.
+	HideFrozenAttempts bool `json:"hide_frozen_attempts,omitempty"`
+	// Feedback contains configuration of post-contest feedback.
+	Feedback *ContestFeedbackConfig `json:"feedback,omitempty"`
+	// Actions contains list of scheduled contest actions.
+	Actions []ContestAction `json:"actions,omitempty"`
+	// Awards contains rules for award computation.
+	Awards *ContestAwardsConfig `json:"awards,omitempty"`
+	// FinalizeTime contains time when contest was finalized.
+	//
+	// Finalized contest is read-only and has immutable results.
+	FinalizeTime NInt64 `json:"finalize_time,omitempty"`
+	// Locale contains default locale of problem statements.
+	Locale string `json:"locale,omitempty"`
+	// DefaultCompilers contains mapping from language to compiler ID
+	// that is used for solutions submitted without compiler.
+	DefaultCompilers map[string]int64 `json:"default_compilers,omitempty"`
+	// PublishTime contains time when contest becomes visible for all
+	// accounts that can register on contests.
+	//
+	// Before this time contest is hidden and registration is closed.
+	PublishTime NInt64 `json:"publish_time,omitempty"`
+	// Compilers contains IDs of compilers that are allowed for
+	// solutions.
+	//
+	// Empty list means that all compilers are allowed.
+	Compilers []int64 `json:"compilers,omitempty"`
+	// EnablePretests enables judgement of solutions only on pretests
+	// until system testing is started.
+	//
+	// Pretests are tests from groups marked as pretests in problem
+	// package.
+	EnablePretests bool `json:"enable_pretests,omitempty"`
+	// PretestGroup contains name of test group with pretests that
+	// overrides groups marked in problem package.
+	//
+	// When group is specified, pretests are enabled.
+	PretestGroup string `json:"pretest_group,omitempty"`
+	// SystemTestTime contains time when system testing was started.
+	SystemTestTime NInt64 `json:"system_test_time,omitempty"`
+	// SystemTestTaskID contains ID of system testing task.
+	SystemTestTaskID NInt64 `json:"system_test_task_id,omitempty"`
+}
+
+// UsePretests returns true if solutions should be judged only on
+// pretests.
+func (c ContestConfig) UsePretests() bool {
+	if c.SystemTestTime != 0 {
+		return false
+	}
+	return c.EnablePretests || c.PretestGroup != ""
+}
+
+// IsCompilerAllowed returns true if compiler can be used for solutions.
+func (c ContestConfig) IsCompilerAllowed(id int64) bool {
+	return len(c.Compilers) == 0 || slices.Contains(c.Compilers, id)
+}
+
+// IsPublished returns true if contest is published at specified time.
+func (c ContestConfig) IsPublished(now int64) bool {
+	return c.PublishTime == 0 || now >= int64(c.PublishTime)
 }
 
 // Contest represents a contest.
@@ -64,6 +207,8 @@ type Contest struct {
 	OwnerID NInt64 `db:"owner_id"`
 	Config  JSON   `db:"config"`
 	Title   string `db:"title"`
+	// ScopeID contains scope that owns contest.
+	ScopeID NInt64 `db:"scope_id"`
 }
 
 // Clone creates copy of contest.
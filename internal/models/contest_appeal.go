@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestAppealStatus represents status of contest appeal.
+type ContestAppealStatus int
+
+const (
+	// PendingContestAppeal means that appeal is waiting for resolution.
+	PendingContestAppeal ContestAppealStatus = 1
+	// AcceptedContestAppeal means that appeal is accepted by jury.
+	AcceptedContestAppeal ContestAppealStatus = 2
+	// RejectedContestAppeal means that appeal is rejected by jury.
+	RejectedContestAppeal ContestAppealStatus = 3
+)
+
+// String returns string representation.
+func (s ContestAppealStatus) String() string {
+	switch s {
+	case PendingContestAppeal:
+		return "pending"
+	case AcceptedContestAppeal:
+		return "accepted"
+	case RejectedContestAppeal:
+		return "rejected"
+	default:
+		return fmt.Sprintf("ContestAppealStatus(%d)", s)
+	}
+}
+
+func (s ContestAppealStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *ContestAppealStatus) UnmarshalText(data []byte) error {
+	switch v := string(data); v {
+	case "pending":
+		*s = PendingContestAppeal
+	case "accepted":
+		*s = AcceptedContestAppeal
+	case "rejected":
+		*s = RejectedContestAppeal
+	default:
+		return fmt.Errorf("unsupported status: %q", v)
+	}
+	return nil
+}
+
+// ContestAppealComment represents comment in appeal discussion.
+type ContestAppealComment struct {
+	AuthorID   int64  `json:"author_id"`
+	Text       string `json:"text"`
+	CreateTime int64  `json:"create_time"`
+}
+
+// ContestAppeal represents appeal of participant against solution
+// verdict.
+type ContestAppeal struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// SolutionID contains ID of appealed contest solution.
+	SolutionID int64 `db:"solution_id"`
+	// ParticipantID contains ID of participant that filed appeal.
+	ParticipantID int64 `db:"participant_id"`
+	// AuthorID contains ID of account that filed appeal.
+	AuthorID int64               `db:"author_id"`
+	Status   ContestAppealStatus `db:"status"`
+	Message  string              `db:"message"`
+	// Comments contains discussion of appeal.
+	Comments   JSON  `db:"comments"`
+	CreateTime int64 `db:"create_time"`
+	// ResolverID contains ID of account that resolved appeal.
+	ResolverID NInt64 `db:"resolver_id"`
+	// ResolveTime contains time when appeal was resolved.
+	ResolveTime NInt64 `db:"resolve_time"`
+}
+
+// Clone creates copy of contest appeal.
+func (o ContestAppeal) Clone() ContestAppeal {
+	o.Comments = o.Comments.Clone()
+	return o
+}
+
+// GetComments returns appeal comments.
+func (o ContestAppeal) GetComments() ([]ContestAppealComment, error) {
+	var comments []ContestAppealComment
+	if len(o.Comments) == 0 {
+		return comments, nil
+	}
+	err := json.Unmarshal(o.Comments, &comments)
+	return comments, err
+}
+
+// SetComments updates appeal comments.
+func (o *ContestAppeal) SetComments(comments []ContestAppealComment) error {
+	if len(comments) == 0 {
+		o.Comments = nil
+		return nil
+	}
+	raw, err := json.Marshal(comments)
+	if err != nil {
+		return err
+	}
+	o.Comments = raw
+	return nil
+}
+
+// ContestAppealEvent represents a contest appeal event.
+type ContestAppealEvent struct {
+	baseEvent
+	ContestAppeal
+}
+
+// Object returns event contest appeal.
+func (e ContestAppealEvent) Object() ContestAppeal {
+	return e.ContestAppeal
+}
+
+// SetObject sets event contest appeal.
+func (e *ContestAppealEvent) SetObject(o ContestAppeal) {
+	e.ContestAppeal = o
+}
+
+// ContestAppealStore represents a contest appeal store.
+type ContestAppealStore struct {
+	cachedStore[ContestAppeal, ContestAppealEvent, *ContestAppeal, *ContestAppealEvent]
+	byContest  *btreeIndex[int64, ContestAppeal, *ContestAppeal]
+	bySolution *btreeIndex[int64, ContestAppeal, *ContestAppeal]
+}
+
+// FindByContest returns appeals by contest ID.
+func (s *ContestAppealStore) FindByContest(
+	ctx context.Context, contestID ...int64,
+) (db.Rows[ContestAppeal], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContest,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		contestID,
+		0,
+	), nil
+}
+
+// FindBySolution returns appeals by contest solution ID.
+func (s *ContestAppealStore) FindBySolution(
+	ctx context.Context, solutionID ...int64,
+) (db.Rows[ContestAppeal], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.bySolution,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		solutionID,
+		0,
+	), nil
+}
+
+// NewContestAppealStore creates a new instance of ContestAppealStore.
+func NewContestAppealStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestAppealStore {
+	impl := &ContestAppealStore{
+		byContest:  newBTreeIndex(func(o ContestAppeal) (int64, bool) { return o.ContestID, true }, lessInt64),
+		bySolution: newBTreeIndex(func(o ContestAppeal) (int64, bool) { return o.SolutionID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ContestAppeal, ContestAppealEvent](
+		db, table, eventTable, impl, impl.byContest, impl.bySolution,
+	)
+	return impl
+}
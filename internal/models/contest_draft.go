@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestDraft represents unsubmitted solution of participant for
+// contest problem.
+//
+// Drafts are updated on every autosave of editor, so they are stored
+// without events and cache.
+type ContestDraft struct {
+	ID            int64  `db:"id"`
+	ContestID     int64  `db:"contest_id"`
+	ParticipantID int64  `db:"participant_id"`
+	ProblemID     int64  `db:"problem_id"`
+	CompilerID    NInt64 `db:"compiler_id"`
+	Content       string `db:"content"`
+	UpdateTime    int64  `db:"update_time"`
+}
+
+func (o ContestDraft) ObjectID() int64 {
+	return o.ID
+}
+
+func (o *ContestDraft) SetObjectID(id int64) {
+	o.ID = id
+}
+
+type ContestDraftStore struct {
+	store db.ObjectStore[ContestDraft, *ContestDraft]
+}
+
+func (s *ContestDraftStore) Create(ctx context.Context, object *ContestDraft) error {
+	return s.store.CreateObject(ctx, object)
+}
+
+func (s *ContestDraftStore) Update(ctx context.Context, object ContestDraft) error {
+	return s.store.UpdateObject(ctx, &object)
+}
+
+func (s *ContestDraftStore) Delete(ctx context.Context, id int64) error {
+	return s.store.DeleteObject(ctx, id)
+}
+
+// GetByParticipantProblem returns draft of participant for problem.
+//
+// Returns sql.ErrNoRows if draft does not exist.
+func (s *ContestDraftStore) GetByParticipantProblem(
+	ctx context.Context, participantID, problemID int64,
+) (ContestDraft, error) {
+	return s.store.FindObject(ctx, db.FindQuery{
+		Where: gosql.Column("participant_id").Equal(participantID).
+			And(gosql.Column("problem_id").Equal(problemID)),
+	})
+}
+
+func NewContestDraftStore(conn *gosql.DB, table string) *ContestDraftStore {
+	impl := &ContestDraftStore{
+		store: db.NewObjectStore[ContestDraft, *ContestDraft]("id", table, conn),
+	}
+	return impl
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestEditorial represents editorial document of contest or
+// contest problem.
+type ContestEditorial struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// ProblemID contains ID of contest problem.
+	//
+	// Editorial without problem describes the whole contest.
+	ProblemID NInt64 `db:"problem_id"`
+	Locale    string `db:"locale"`
+	// Name contains name of document file.
+	Name   string `db:"name"`
+	FileID int64  `db:"file_id"`
+	// PublishTime contains time of manual publication.
+	//
+	// Editorial without publish time is published when contest
+	// is finished.
+	PublishTime NInt64 `db:"publish_time"`
+}
+
+// Clone creates copy of contest editorial.
+func (o ContestEditorial) Clone() ContestEditorial {
+	return o
+}
+
+// ContestEditorialEvent represents a contest editorial event.
+type ContestEditorialEvent struct {
+	baseEvent
+	ContestEditorial
+}
+
+// Object returns event contest editorial.
+func (e ContestEditorialEvent) Object() ContestEditorial {
+	return e.ContestEditorial
+}
+
+// SetObject sets event contest editorial.
+func (e *ContestEditorialEvent) SetObject(o ContestEditorial) {
+	e.ContestEditorial = o
+}
+
+// ContestEditorialStore represents a contest editorial store.
+type ContestEditorialStore struct {
+	cachedStore[ContestEditorial, ContestEditorialEvent, *ContestEditorial, *ContestEditorialEvent]
+	byContest *btreeIndex[int64, ContestEditorial, *ContestEditorial]
+}
+
+// FindByContest returns editorials by contest ID.
+func (s *ContestEditorialStore) FindByContest(
+	ctx context.Context, contestID ...int64,
+) (db.Rows[ContestEditorial], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContest,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		contestID,
+		0,
+	), nil
+}
+
+// NewContestEditorialStore creates a new instance of
+// ContestEditorialStore.
+func NewContestEditorialStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestEditorialStore {
+	impl := &ContestEditorialStore{
+		byContest: newBTreeIndex(func(o ContestEditorial) (int64, bool) { return o.ContestID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ContestEditorial, ContestEditorialEvent](
+		db, table, eventTable, impl, impl.byContest,
+	)
+	return impl
+}
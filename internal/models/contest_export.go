@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestExportStatus represents status of contest export.
+type ContestExportStatus int
+
+const (
+	// PendingContestExport means that archive is generating.
+	PendingContestExport ContestExportStatus = 0
+	// ReadyContestExport means that archive can be downloaded.
+	ReadyContestExport ContestExportStatus = 1
+	// FailedContestExport means that archive cannot be generated.
+	FailedContestExport ContestExportStatus = 2
+)
+
+// String returns string representation.
+func (s ContestExportStatus) String() string {
+	switch s {
+	case PendingContestExport:
+		return "pending"
+	case ReadyContestExport:
+		return "ready"
+	case FailedContestExport:
+		return "failed"
+	default:
+		return fmt.Sprintf("ContestExportStatus(%d)", s)
+	}
+}
+
+// ContestExport represents archive with solutions of contest.
+//
+// Only one export is kept for every contest. Exports are shared
+// between server instances, so they are stored without events and
+// cache.
+type ContestExport struct {
+	ID         int64               `db:"id"`
+	ContestID  int64               `db:"contest_id"`
+	Status     ContestExportStatus `db:"status"`
+	CreateTime int64               `db:"create_time"`
+	// ExpireTime contains time after which export is removed.
+	//
+	// For pending export it contains deadline of generation.
+	ExpireTime int64 `db:"expire_time"`
+	// FileID contains ID of file with archive.
+	FileID NInt64 `db:"file_id"`
+	// Size contains size of archive in bytes.
+	Size int64 `db:"size"`
+}
+
+func (o ContestExport) ObjectID() int64 {
+	return o.ID
+}
+
+func (o *ContestExport) SetObjectID(id int64) {
+	o.ID = id
+}
+
+type ContestExportStore struct {
+	store db.ObjectStore[ContestExport, *ContestExport]
+}
+
+func (s *ContestExportStore) Create(ctx context.Context, object *ContestExport) error {
+	return s.store.CreateObject(ctx, object)
+}
+
+func (s *ContestExportStore) Update(ctx context.Context, object ContestExport) error {
+	return s.store.UpdateObject(ctx, &object)
+}
+
+func (s *ContestExportStore) Delete(ctx context.Context, id int64) error {
+	return s.store.DeleteObject(ctx, id)
+}
+
+// GetByContest returns export of contest.
+//
+// Returns sql.ErrNoRows if export does not exist.
+func (s *ContestExportStore) GetByContest(ctx context.Context, contestID int64) (ContestExport, error) {
+	return s.store.FindObject(ctx, db.FindQuery{
+		Where: gosql.Column("contest_id").Equal(contestID),
+	})
+}
+
+// FindExpired returns exports that are expired at specified time.
+func (s *ContestExportStore) FindExpired(ctx context.Context, now int64) (db.Rows[ContestExport], error) {
+	return s.store.FindObjects(ctx, db.FindQuery{
+		Where: gosql.Column("expire_time").LessEqual(now),
+	})
+}
+
+func NewContestExportStore(conn *gosql.DB, table string) *ContestExportStore {
+	impl := &ContestExportStore{
+		store: db.NewObjectStore[ContestExport, *ContestExport]("id", table, conn),
+	}
+	return impl
+}
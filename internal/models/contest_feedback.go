@@ -0,0 +1,117 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestFeedbackAnswers contains answers by question name.
+type ContestFeedbackAnswers map[string]string
+
+// ContestFeedback represents feedback of contest participant.
+type ContestFeedback struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// ParticipantID contains ID of participant.
+	//
+	// Participant is required to prevent duplicate feedbacks, but it
+	// is not exposed when feedback is configured as anonymous.
+	ParticipantID int64 `db:"participant_id"`
+	Answers       JSON  `db:"answers"`
+	CreateTime    int64 `db:"create_time"`
+}
+
+// Clone creates copy of contest feedback.
+func (o ContestFeedback) Clone() ContestFeedback {
+	o.Answers = o.Answers.Clone()
+	return o
+}
+
+// GetAnswers returns feedback answers.
+func (o ContestFeedback) GetAnswers() (ContestFeedbackAnswers, error) {
+	var answers ContestFeedbackAnswers
+	if len(o.Answers) == 0 {
+		return answers, nil
+	}
+	err := json.Unmarshal(o.Answers, &answers)
+	return answers, err
+}
+
+// SetAnswers updates feedback answers.
+func (o *ContestFeedback) SetAnswers(answers ContestFeedbackAnswers) error {
+	raw, err := json.Marshal(answers)
+	if err != nil {
+		return err
+	}
+	o.Answers = raw
+	return nil
+}
+
+// ContestFeedbackEvent represents a contest feedback event.
+type ContestFeedbackEvent struct {
+	baseEvent
+	ContestFeedback
+}
+
+// Object returns event contest feedback.
+func (e ContestFeedbackEvent) Object() ContestFeedback {
+	return e.ContestFeedback
+}
+
+// SetObject sets event contest feedback.
+func (e *ContestFeedbackEvent) SetObject(o ContestFeedback) {
+	e.ContestFeedback = o
+}
+
+// ContestFeedbackStore represents a contest feedback store.
+type ContestFeedbackStore struct {
+	cachedStore[ContestFeedback, ContestFeedbackEvent, *ContestFeedback, *ContestFeedbackEvent]
+	byContest     *btreeIndex[int64, ContestFeedback, *ContestFeedback]
+	byParticipant *btreeIndex[int64, ContestFeedback, *ContestFeedback]
+}
+
+// FindByContest returns feedbacks by contest ID.
+func (s *ContestFeedbackStore) FindByContest(
+	ctx context.Context, contestID ...int64,
+) (db.Rows[ContestFeedback], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContest,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		contestID,
+		0,
+	), nil
+}
+
+// FindByParticipant returns feedbacks by participant ID.
+func (s *ContestFeedbackStore) FindByParticipant(
+	ctx context.Context, participantID ...int64,
+) (db.Rows[ContestFeedback], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byParticipant,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		participantID,
+		0,
+	), nil
+}
+
+// NewContestFeedbackStore creates a new instance of ContestFeedbackStore.
+func NewContestFeedbackStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestFeedbackStore {
+	impl := &ContestFeedbackStore{
+		byContest:     newBTreeIndex(func(o ContestFeedback) (int64, bool) { return o.ContestID, true }, lessInt64),
+		byParticipant: newBTreeIndex(func(o ContestFeedback) (int64, bool) { return o.ParticipantID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ContestFeedback, ContestFeedbackEvent](
+		db, table, eventTable, impl, impl.byContest, impl.byParticipant,
+	)
+	return impl
+}
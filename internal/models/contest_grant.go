@@ -0,0 +1,168 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestGrantKind represents kind of contest role grant.
+type ContestGrantKind int
+
+const (
+	// OwnerContestGrant grants full access to contest including
+	// management of contest grants.
+	OwnerContestGrant ContestGrantKind = 1
+	// ManagerContestGrant grants the same permissions as contest
+	// manager participant has.
+	ManagerContestGrant ContestGrantKind = 2
+	// ObserverContestGrant grants read-only access to contest.
+	ObserverContestGrant ContestGrantKind = 3
+	// JuryContestGrant grants permissions for judging solutions and
+	// answering questions.
+	JuryContestGrant ContestGrantKind = 4
+)
+
+// String returns string representation.
+func (k ContestGrantKind) String() string {
+	switch k {
+	case OwnerContestGrant:
+		return "owner"
+	case ManagerContestGrant:
+		return "manager"
+	case ObserverContestGrant:
+		return "observer"
+	case JuryContestGrant:
+		return "jury"
+	default:
+		return fmt.Sprintf("ContestGrantKind(%d)", k)
+	}
+}
+
+func (k ContestGrantKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *ContestGrantKind) UnmarshalText(data []byte) error {
+	switch s := string(data); s {
+	case "owner":
+		*k = OwnerContestGrant
+	case "manager":
+		*k = ManagerContestGrant
+	case "observer":
+		*k = ObserverContestGrant
+	case "jury":
+		*k = JuryContestGrant
+	default:
+		return fmt.Errorf("unsupported kind: %q", s)
+	}
+	return nil
+}
+
+func (k ContestGrantKind) IsValid() bool {
+	switch k {
+	case OwnerContestGrant, ManagerContestGrant, ObserverContestGrant, JuryContestGrant:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContestGrant represents role of account in specific contest.
+type ContestGrant struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// AccountID contains ID of account, that can be user, scope
+	// or group.
+	AccountID int64            `db:"account_id"`
+	Kind      ContestGrantKind `db:"kind"`
+}
+
+// Clone creates copy of contest grant.
+func (o ContestGrant) Clone() ContestGrant {
+	return o
+}
+
+// ContestGrantEvent represents a contest grant event.
+type ContestGrantEvent struct {
+	baseEvent
+	ContestGrant
+}
+
+// Object returns event contest grant.
+func (e ContestGrantEvent) Object() ContestGrant {
+	return e.ContestGrant
+}
+
+// SetObject sets event contest grant.
+func (e *ContestGrantEvent) SetObject(o ContestGrant) {
+	e.ContestGrant = o
+}
+
+// ContestGrantStore represents a contest grant store.
+type ContestGrantStore struct {
+	cachedStore[ContestGrant, ContestGrantEvent, *ContestGrant, *ContestGrantEvent]
+	byContest        *btreeIndex[int64, ContestGrant, *ContestGrant]
+	byContestAccount *btreeIndex[pair[int64, int64], ContestGrant, *ContestGrant]
+	contestVersions  *versionIndex[int64, ContestGrant]
+}
+
+// FindByContest returns grants by contest ID.
+func (s *ContestGrantStore) FindByContest(
+	ctx context.Context, contestID ...int64,
+) (db.Rows[ContestGrant], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContest,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		contestID,
+		0,
+	), nil
+}
+
+// FindByContestAccount returns grants by contest ID and account ID.
+func (s *ContestGrantStore) FindByContestAccount(
+	ctx context.Context, contestID int64, accountID int64,
+) (db.Rows[ContestGrant], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContestAccount,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		[]pair[int64, int64]{makePair(contestID, accountID)},
+		0,
+	), nil
+}
+
+// GetContestVersion returns version of grants of contest.
+//
+// Version is changed on every change of grants of contest.
+func (s *ContestGrantStore) GetContestVersion(contestID int64) int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.contestVersions.Get(contestID)
+}
+
+// NewContestGrantStore creates a new instance of ContestGrantStore.
+func NewContestGrantStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestGrantStore {
+	impl := &ContestGrantStore{
+		byContest: newBTreeIndex(func(o ContestGrant) (int64, bool) { return o.ContestID, true }, lessInt64),
+		byContestAccount: newBTreeIndex(func(o ContestGrant) (pair[int64, int64], bool) {
+			return makePair(o.ContestID, o.AccountID), true
+		}, lessPairInt64),
+		contestVersions: newVersionIndex(func(o ContestGrant) (int64, bool) {
+			return o.ContestID, true
+		}),
+	}
+	impl.cachedStore = makeCachedStore[ContestGrant, ContestGrantEvent](
+		db, table, eventTable, impl, impl.byContest, impl.byContestAccount,
+		impl.contestVersions,
+	)
+	return impl
+}
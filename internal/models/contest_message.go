@@ -14,6 +14,8 @@ const (
 	RegularContestMessage  ContestMessageKind = 0
 	QuestionContestMessage ContestMessageKind = 1
 	AnswerContestMessage   ContestMessageKind = 2
+	// AlertContestMessage represents automatic alert for judges.
+	AlertContestMessage ContestMessageKind = 3
 )
 
 func (k ContestMessageKind) String() string {
@@ -24,6 +26,8 @@ func (k ContestMessageKind) String() string {
 		return "question"
 	case AnswerContestMessage:
 		return "answer"
+	case AlertContestMessage:
+		return "alert"
 	default:
 		return fmt.Sprintf("ContestMessageKind(%d)", k)
 	}
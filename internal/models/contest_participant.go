@@ -87,14 +87,51 @@ type ContestParticipant struct {
 	Kind ParticipantKind `db:"kind"`
 	// Config contains participant config.
 	Config JSON `db:"config"`
+	// Labels contains labels of participant like division or school.
+	Labels JSON `db:"labels"`
 }
 
 // Clone creates copy of contest participant.
 func (o ContestParticipant) Clone() ContestParticipant {
 	o.Config = o.Config.Clone()
+	o.Labels = o.Labels.Clone()
 	return o
 }
 
+// GetLabels returns labels of participant by label name.
+func (o ContestParticipant) GetLabels() (map[string]string, error) {
+	labels := map[string]string{}
+	if len(o.Labels) == 0 {
+		return labels, nil
+	}
+	err := json.Unmarshal(o.Labels, &labels)
+	return labels, err
+}
+
+// SetLabels sets labels of participant.
+func (o *ContestParticipant) SetLabels(labels map[string]string) error {
+	if len(labels) == 0 {
+		o.Labels = nil
+		return nil
+	}
+	raw, err := json.Marshal(labels)
+	if err != nil {
+		return err
+	}
+	o.Labels = raw
+	return nil
+}
+
+// HasLabel returns true if participant has label with specified value.
+func (o ContestParticipant) HasLabel(name, value string) bool {
+	labels, err := o.GetLabels()
+	if err != nil {
+		return false
+	}
+	label, ok := labels[name]
+	return ok && label == value
+}
+
 func (o ContestParticipant) ScanConfig(config any) error {
 	if len(o.Config) == 0 {
 		return nil
@@ -132,6 +169,8 @@ type ContestParticipantStore struct {
 	cachedStore[ContestParticipant, ContestParticipantEvent, *ContestParticipant, *ContestParticipantEvent]
 	byContest        *btreeIndex[int64, ContestParticipant, *ContestParticipant]
 	byContestAccount *btreeIndex[pair[int64, int64], ContestParticipant, *ContestParticipant]
+	byAccount        *btreeIndex[int64, ContestParticipant, *ContestParticipant]
+	contestVersions  *versionIndex[int64, ContestParticipant]
 }
 
 func (s *ContestParticipantStore) FindByContest(
@@ -161,6 +200,29 @@ func (s *ContestParticipantStore) FindByContestAccount(
 	), nil
 }
 
+// FindByAccount returns participants by account.
+func (s *ContestParticipantStore) FindByAccount(
+	ctx context.Context, accountID ...int64,
+) (db.Rows[ContestParticipant], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byAccount,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		accountID,
+		0,
+	), nil
+}
+
+// GetContestVersion returns version of participants of contest.
+//
+// Version is changed on every change of participants of contest.
+func (s *ContestParticipantStore) GetContestVersion(contestID int64) int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.contestVersions.Get(contestID)
+}
+
 // NewContestParticipantStore creates a new instance of
 // ContestParticipantStore.
 func NewContestParticipantStore(
@@ -171,9 +233,14 @@ func NewContestParticipantStore(
 		byContestAccount: newBTreeIndex(func(o ContestParticipant) (pair[int64, int64], bool) {
 			return makePair(o.ContestID, o.AccountID), true
 		}, lessPairInt64),
+		byAccount: newBTreeIndex(func(o ContestParticipant) (int64, bool) { return o.AccountID, true }, lessInt64),
+		contestVersions: newVersionIndex(func(o ContestParticipant) (int64, bool) {
+			return o.ContestID, true
+		}),
 	}
 	impl.cachedStore = makeCachedStore[ContestParticipant, ContestParticipantEvent](
 		db, table, eventTable, impl, impl.byContest, impl.byContestAccount,
+		impl.byAccount, impl.contestVersions,
 	)
 	return impl
 }
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestParticipantAddress represents address and session that were
+// used by participant during contest.
+//
+// Addresses are updated on every participant request, so they are
+// stored without events and cache.
+type ContestParticipantAddress struct {
+	ID            int64  `db:"id"`
+	ContestID     int64  `db:"contest_id"`
+	ParticipantID int64  `db:"participant_id"`
+	SessionID     NInt64 `db:"session_id"`
+	RealIP        string `db:"real_ip"`
+	UserAgent     string `db:"user_agent"`
+	FirstTime     int64  `db:"first_time"`
+	LastTime      int64  `db:"last_time"`
+}
+
+func (o ContestParticipantAddress) ObjectID() int64 {
+	return o.ID
+}
+
+func (o *ContestParticipantAddress) SetObjectID(id int64) {
+	o.ID = id
+}
+
+type ContestParticipantAddressStore struct {
+	store db.ObjectStore[ContestParticipantAddress, *ContestParticipantAddress]
+}
+
+func (s *ContestParticipantAddressStore) Create(ctx context.Context, object *ContestParticipantAddress) error {
+	return s.store.CreateObject(ctx, object)
+}
+
+func (s *ContestParticipantAddressStore) Update(ctx context.Context, object ContestParticipantAddress) error {
+	return s.store.UpdateObject(ctx, &object)
+}
+
+func (s *ContestParticipantAddressStore) FindByContest(
+	ctx context.Context, contestID int64,
+) (db.Rows[ContestParticipantAddress], error) {
+	return s.store.FindObjects(ctx, db.FindQuery{Where: gosql.Column("contest_id").Equal(contestID)})
+}
+
+func (s *ContestParticipantAddressStore) FindByParticipant(
+	ctx context.Context, participantID int64,
+) (db.Rows[ContestParticipantAddress], error) {
+	return s.store.FindObjects(ctx, db.FindQuery{Where: gosql.Column("participant_id").Equal(participantID)})
+}
+
+func NewContestParticipantAddressStore(conn *gosql.DB, table string) *ContestParticipantAddressStore {
+	impl := &ContestParticipantAddressStore{
+		store: db.NewObjectStore[ContestParticipantAddress, *ContestParticipantAddress]("id", table, conn),
+	}
+	return impl
+}
@@ -3,15 +3,68 @@ package models
 import (
 	"context"
 	"encoding/json"
+	"sort"
 
 	"github.com/udovin/gosql"
 	"github.com/udovin/solve/internal/db"
 )
 
+// FeedbackPolicy represents details of solution reports that are
+// shown to participants.
+type FeedbackPolicy string
+
+const (
+	// FullFeedback means that participants see results of every test.
+	FullFeedback FeedbackPolicy = "full"
+	// FirstFailedFeedback means that participants see only number and
+	// result of first failed test.
+	FirstFailedFeedback FeedbackPolicy = "first_failed"
+	// GroupsFeedback means that participants see only subtotals of
+	// test groups.
+	GroupsFeedback FeedbackPolicy = "groups"
+	// VerdictFeedback means that participants see only verdict.
+	VerdictFeedback FeedbackPolicy = "verdict"
+	// SamplesFeedback means that participants see only results of sample
+	// tests until contest is finished and full feedback after that.
+	SamplesFeedback FeedbackPolicy = "samples"
+)
+
+// IsValid returns true if policy is supported.
+func (p FeedbackPolicy) IsValid() bool {
+	switch p {
+	case FullFeedback, FirstFailedFeedback, GroupsFeedback, VerdictFeedback,
+		SamplesFeedback:
+		return true
+	default:
+		return false
+	}
+}
+
 type ContestProblemConfig struct {
 	Points *int `json:"points,omitempty"`
 	// Locales contains list of allowed locales.
 	Locales []string `json:"locales,omitempty"`
+	// Position contains explicit position of problem in contest.
+	Position *int `json:"position,omitempty"`
+	// Title contains display title that overrides title of problem.
+	Title *string `json:"title,omitempty"`
+	// Color contains display color of problem in "#RRGGBB" format.
+	Color *string `json:"color,omitempty"`
+	// MaxSolutions contains maximum amount of solutions per participant.
+	MaxSolutions *int `json:"max_solutions,omitempty"`
+	// SolutionInterval contains minimum interval between solutions of
+	// participant in seconds.
+	SolutionInterval *int `json:"solution_interval,omitempty"`
+	// FeedbackPolicy contains details of reports shown to participants.
+	//
+	// Empty policy means that details depend only on permissions.
+	FeedbackPolicy FeedbackPolicy `json:"feedback_policy,omitempty"`
+	// TimeLimit contains time limit in milliseconds that overrides time
+	// limits of problem package.
+	TimeLimit *int64 `json:"time_limit,omitempty"`
+	// MemoryLimit contains memory limit in bytes that overrides memory
+	// limits of problem package.
+	MemoryLimit *int64 `json:"memory_limit,omitempty"`
 }
 
 // ContestProblem represents connection for problems.
@@ -51,6 +104,109 @@ func (o ContestProblem) Clone() ContestProblem {
 	return o
 }
 
+// SortContestProblems sorts problems by explicit positions.
+//
+// Problems without position are placed after positioned ones and are
+// ordered by codes in natural order, so "A2" goes before "A10".
+func SortContestProblems(problems []ContestProblem) {
+	positions := make([]*int, len(problems))
+	for i, problem := range problems {
+		if config, err := problem.GetConfig(); err == nil {
+			positions[i] = config.Position
+		}
+	}
+	sort.Sort(contestProblemSorter{problems: problems, positions: positions})
+}
+
+type contestProblemSorter struct {
+	problems  []ContestProblem
+	positions []*int
+}
+
+func (s contestProblemSorter) Len() int {
+	return len(s.problems)
+}
+
+func (s contestProblemSorter) Less(i, j int) bool {
+	lhs, rhs := s.positions[i], s.positions[j]
+	if lhs != nil && rhs != nil && *lhs != *rhs {
+		return *lhs < *rhs
+	}
+	if (lhs == nil) != (rhs == nil) {
+		return lhs != nil
+	}
+	lhsCode, rhsCode := s.problems[i].Code, s.problems[j].Code
+	if cmp := compareNatural(lhsCode, rhsCode); cmp != 0 {
+		return cmp < 0
+	}
+	if lhsCode != rhsCode {
+		return lhsCode < rhsCode
+	}
+	return s.problems[i].ID < s.problems[j].ID
+}
+
+func (s contestProblemSorter) Swap(i, j int) {
+	s.problems[i], s.problems[j] = s.problems[j], s.problems[i]
+	s.positions[i], s.positions[j] = s.positions[j], s.positions[i]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// compareNatural compares strings treating digit sequences as numbers.
+func compareNatural(lhs, rhs string) int {
+	i, j := 0, 0
+	for i < len(lhs) && j < len(rhs) {
+		if isDigit(lhs[i]) && isDigit(rhs[j]) {
+			li, rj := i, j
+			for li < len(lhs) && lhs[li] == '0' {
+				li++
+			}
+			for rj < len(rhs) && rhs[rj] == '0' {
+				rj++
+			}
+			le, re := li, rj
+			for le < len(lhs) && isDigit(lhs[le]) {
+				le++
+			}
+			for re < len(rhs) && isDigit(rhs[re]) {
+				re++
+			}
+			if le-li != re-rj {
+				if le-li < re-rj {
+					return -1
+				}
+				return 1
+			}
+			if l, r := lhs[li:le], rhs[rj:re]; l != r {
+				if l < r {
+					return -1
+				}
+				return 1
+			}
+			i, j = le, re
+			continue
+		}
+		if lhs[i] != rhs[j] {
+			if lhs[i] < rhs[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	switch {
+	case len(lhs)-i < len(rhs)-j:
+		return -1
+	case len(lhs)-i > len(rhs)-j:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // ContestProblemEvent represents problem event.
 type ContestProblemEvent struct {
 	baseEvent
@@ -71,6 +227,7 @@ func (e *ContestProblemEvent) SetObject(o ContestProblem) {
 type ContestProblemStore struct {
 	cachedStore[ContestProblem, ContestProblemEvent, *ContestProblem, *ContestProblemEvent]
 	byContest *btreeIndex[int64, ContestProblem, *ContestProblem]
+	byProblem *btreeIndex[int64, ContestProblem, *ContestProblem]
 }
 
 // FindByContest returns problems by parent ID.
@@ -87,15 +244,30 @@ func (s *ContestProblemStore) FindByContest(
 	), nil
 }
 
+// FindByProblem returns contest problems by problem ID.
+func (s *ContestProblemStore) FindByProblem(
+	ctx context.Context, problemID ...int64,
+) (db.Rows[ContestProblem], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byProblem,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		problemID,
+		0,
+	), nil
+}
+
 // NewContestProblemStore creates a new instance of ContestProblemStore.
 func NewContestProblemStore(
 	db *gosql.DB, table, eventTable string,
 ) *ContestProblemStore {
 	impl := &ContestProblemStore{
 		byContest: newBTreeIndex(func(o ContestProblem) (int64, bool) { return o.ContestID, true }, lessInt64),
+		byProblem: newBTreeIndex(func(o ContestProblem) (int64, bool) { return o.ProblemID, true }, lessInt64),
 	}
 	impl.cachedStore = makeCachedStore[ContestProblem, ContestProblemEvent](
-		db, table, eventTable, impl, impl.byContest,
+		db, table, eventTable, impl, impl.byContest, impl.byProblem,
 	)
 	return impl
 }
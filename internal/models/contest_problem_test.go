@@ -5,6 +5,33 @@ import (
 	"testing"
 )
 
+func TestSortContestProblems(t *testing.T) {
+	withPosition := func(code string, position int) ContestProblem {
+		problem := ContestProblem{Code: code}
+		if err := problem.SetConfig(ContestProblemConfig{
+			Position: &position,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		return problem
+	}
+	problems := []ContestProblem{
+		{Code: "A10"},
+		{Code: "B"},
+		{Code: "A2"},
+		withPosition("C", 2),
+		{Code: "A02"},
+		withPosition("D", 1),
+	}
+	SortContestProblems(problems)
+	expected := []string{"D", "C", "A02", "A2", "A10", "B"}
+	for i, problem := range problems {
+		if problem.Code != expected[i] {
+			t.Fatalf("Expected %q at %d, got %q", expected[i], i, problem.Code)
+		}
+	}
+}
+
 type contestProblemStoreTest struct{}
 
 func (t *contestProblemStoreTest) prepareDB(tx *sql.Tx) error {
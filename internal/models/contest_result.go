@@ -0,0 +1,120 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestAward represents award of contest participant.
+type ContestAward string
+
+const (
+	// GoldContestAward represents gold medal.
+	GoldContestAward ContestAward = "gold"
+	// SilverContestAward represents silver medal.
+	SilverContestAward ContestAward = "silver"
+	// BronzeContestAward represents bronze medal.
+	BronzeContestAward ContestAward = "bronze"
+)
+
+// ContestResultRow represents final result of contest participant.
+type ContestResultRow struct {
+	ParticipantID     int64        `json:"participant_id,omitempty"`
+	FakeParticipantID int64        `json:"fake_participant_id,omitempty"`
+	Place             int          `json:"place"`
+	Score             float64      `json:"score"`
+	Penalty           *int64       `json:"penalty,omitempty"`
+	Award             ContestAward `json:"award,omitempty"`
+}
+
+// ContestResult represents final results of contest.
+//
+// Results are never updated, contest should be unfinalized and
+// finalized again to change results.
+type ContestResult struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// AuthorID contains ID of account that finalized contest.
+	AuthorID   NInt64 `db:"author_id"`
+	Results    JSON   `db:"results"`
+	CreateTime int64  `db:"create_time"`
+}
+
+// Clone creates copy of contest result.
+func (o ContestResult) Clone() ContestResult {
+	o.Results = o.Results.Clone()
+	return o
+}
+
+// GetResults returns rows of final results.
+func (o ContestResult) GetResults() ([]ContestResultRow, error) {
+	var results []ContestResultRow
+	if len(o.Results) == 0 {
+		return results, nil
+	}
+	err := json.Unmarshal(o.Results, &results)
+	return results, err
+}
+
+// SetResults updates rows of final results.
+func (o *ContestResult) SetResults(results []ContestResultRow) error {
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	o.Results = raw
+	return nil
+}
+
+// ContestResultEvent represents a contest result event.
+type ContestResultEvent struct {
+	baseEvent
+	ContestResult
+}
+
+// Object returns event contest result.
+func (e ContestResultEvent) Object() ContestResult {
+	return e.ContestResult
+}
+
+// SetObject sets event contest result.
+func (e *ContestResultEvent) SetObject(o ContestResult) {
+	e.ContestResult = o
+}
+
+// ContestResultStore represents a contest result store.
+type ContestResultStore struct {
+	cachedStore[ContestResult, ContestResultEvent, *ContestResult, *ContestResultEvent]
+	byContest *btreeIndex[int64, ContestResult, *ContestResult]
+}
+
+// FindByContest returns results by contest ID.
+func (s *ContestResultStore) FindByContest(
+	ctx context.Context, contestID ...int64,
+) (db.Rows[ContestResult], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContest,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		contestID,
+		0,
+	), nil
+}
+
+// NewContestResultStore creates a new instance of ContestResultStore.
+func NewContestResultStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestResultStore {
+	impl := &ContestResultStore{
+		byContest: newBTreeIndex(func(o ContestResult) (int64, bool) { return o.ContestID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ContestResult, ContestResultEvent](
+		db, table, eventTable, impl, impl.byContest,
+	)
+	return impl
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestScoreOverrideValue contains values that replace computed
+// standings cell.
+type ContestScoreOverrideValue struct {
+	// Verdict contains verdict of cell.
+	//
+	// Zero value means that verdict is not overridden.
+	Verdict Verdict `json:"verdict,omitempty"`
+	// Points contains points of cell.
+	Points *float64 `json:"points,omitempty"`
+}
+
+// ContestScoreOverride represents manual override of standings cell
+// for pair of participant and contest problem.
+type ContestScoreOverride struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// ParticipantID contains ID of participant.
+	ParticipantID int64 `db:"participant_id"`
+	// ProblemID contains ID of contest problem.
+	ProblemID int64 `db:"problem_id"`
+	// AuthorID contains ID of account that created override.
+	AuthorID   int64  `db:"author_id"`
+	Value      JSON   `db:"value"`
+	Reason     string `db:"reason"`
+	CreateTime int64  `db:"create_time"`
+}
+
+// Clone creates copy of contest score override.
+func (o ContestScoreOverride) Clone() ContestScoreOverride {
+	o.Value = o.Value.Clone()
+	return o
+}
+
+// GetValue returns override value.
+func (o ContestScoreOverride) GetValue() (ContestScoreOverrideValue, error) {
+	var value ContestScoreOverrideValue
+	if len(o.Value) == 0 {
+		return value, nil
+	}
+	err := json.Unmarshal(o.Value, &value)
+	return value, err
+}
+
+// SetValue updates override value.
+func (o *ContestScoreOverride) SetValue(value ContestScoreOverrideValue) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	o.Value = raw
+	return nil
+}
+
+// ContestScoreOverrideEvent represents a contest score override event.
+type ContestScoreOverrideEvent struct {
+	baseEvent
+	ContestScoreOverride
+}
+
+// Object returns event contest score override.
+func (e ContestScoreOverrideEvent) Object() ContestScoreOverride {
+	return e.ContestScoreOverride
+}
+
+// SetObject sets event contest score override.
+func (e *ContestScoreOverrideEvent) SetObject(o ContestScoreOverride) {
+	e.ContestScoreOverride = o
+}
+
+// ContestScoreOverrideStore represents a contest score override store.
+type ContestScoreOverrideStore struct {
+	cachedStore[ContestScoreOverride, ContestScoreOverrideEvent, *ContestScoreOverride, *ContestScoreOverrideEvent]
+	byContest *btreeIndex[int64, ContestScoreOverride, *ContestScoreOverride]
+}
+
+// FindByContest returns overrides by contest ID.
+func (s *ContestScoreOverrideStore) FindByContest(
+	ctx context.Context, contestID ...int64,
+) (db.Rows[ContestScoreOverride], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byContest,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		contestID,
+		0,
+	), nil
+}
+
+// NewContestScoreOverrideStore creates a new instance of
+// ContestScoreOverrideStore.
+func NewContestScoreOverrideStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestScoreOverrideStore {
+	impl := &ContestScoreOverrideStore{
+		byContest: newBTreeIndex(func(o ContestScoreOverride) (int64, bool) { return o.ContestID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ContestScoreOverride, ContestScoreOverrideEvent](
+		db, table, eventTable, impl, impl.byContest,
+	)
+	return impl
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ContestUnfinalization represents audit record of contest
+// unfinalization.
+type ContestUnfinalization struct {
+	ID int64 `db:"id"`
+	// Time contains time of unfinalization.
+	Time int64 `db:"time"`
+	// ContestID contains ID of unfinalized contest.
+	ContestID int64 `db:"contest_id"`
+	// AuthorID contains ID of account that performed unfinalization.
+	AuthorID NInt64 `db:"author_id"`
+	// Reason contains reason of unfinalization.
+	Reason string `db:"reason"`
+}
+
+// EventID returns ID of contest unfinalization.
+func (o ContestUnfinalization) EventID() int64 {
+	return o.ID
+}
+
+// SetEventID sets ID of contest unfinalization.
+func (o *ContestUnfinalization) SetEventID(id int64) {
+	o.ID = id
+}
+
+// EventTime return time of contest unfinalization.
+func (o ContestUnfinalization) EventTime() time.Time {
+	return time.Unix(o.Time, 0)
+}
+
+// ContestUnfinalizationStore represents store of contest
+// unfinalizations.
+type ContestUnfinalizationStore struct {
+	db     *gosql.DB
+	events db.EventStore[ContestUnfinalization, *ContestUnfinalization]
+}
+
+// Create creates a new contest unfinalization record.
+func (s *ContestUnfinalizationStore) Create(
+	ctx context.Context, unfinalization *ContestUnfinalization,
+) error {
+	return s.events.CreateEvent(ctx, unfinalization)
+}
+
+// FindByContest returns unfinalization records of specified contest.
+func (s *ContestUnfinalizationStore) FindByContest(
+	ctx context.Context, contestID int64,
+) ([]ContestUnfinalization, error) {
+	rows, err := s.events.LoadEvents(ctx, []db.EventRange{{}})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var unfinalizations []ContestUnfinalization
+	for rows.Next() {
+		if row := rows.Row(); row.ContestID == contestID {
+			unfinalizations = append(unfinalizations, row)
+		}
+	}
+	return unfinalizations, rows.Err()
+}
+
+// NewContestUnfinalizationStore creates a new instance of
+// ContestUnfinalizationStore.
+func NewContestUnfinalizationStore(
+	dbConn *gosql.DB, table string,
+) *ContestUnfinalizationStore {
+	return &ContestUnfinalizationStore{
+		db:     dbConn,
+		events: db.NewEventStore[ContestUnfinalization]("id", table, dbConn),
+	}
+}
@@ -1,10 +1,12 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
 )
 
 type FileStatus int
@@ -30,6 +32,31 @@ type FileMeta struct {
 	Name string `json:"name,omitempty"`
 	Size int64  `json:"size"`
 	MD5  string `json:"md5"`
+	// Upload contains state of chunked upload.
+	Upload *FileUploadMeta `json:"upload,omitempty"`
+}
+
+// FileUploadMeta represents state of chunked file upload.
+type FileUploadMeta struct {
+	AccountID int64           `json:"account_id"`
+	Chunks    []FileChunkMeta `json:"chunks,omitempty"`
+	Finalized bool            `json:"finalized,omitempty"`
+}
+
+// UploadedSize returns total size of uploaded chunks.
+func (m FileUploadMeta) UploadedSize() int64 {
+	var size int64
+	for _, chunk := range m.Chunks {
+		size += chunk.Size
+	}
+	return size
+}
+
+// FileChunkMeta represents uploaded chunk of file.
+type FileChunkMeta struct {
+	Size int64 `json:"size"`
+	// SHA256 contains hex encoded SHA-256 of chunk content.
+	SHA256 string `json:"sha256"`
 }
 
 // File represents a file.
@@ -39,6 +66,10 @@ type File struct {
 	ExpireTime NInt64     `db:"expire_time"`
 	Path       string     `db:"path"`
 	Meta       JSON       `db:"meta"`
+	// SHA256 contains hex encoded SHA-256 of file content.
+	//
+	// Files with equal SHA256 share the same Path in storage.
+	SHA256 NString `db:"sha256"`
 }
 
 // Clone creates copy of file.
@@ -83,16 +114,52 @@ func (e *FileEvent) SetObject(o File) {
 
 type FileStore interface {
 	Store[File, FileEvent]
+	// FindByPath returns files that reference specified storage path.
+	FindByPath(ctx context.Context, path string) (db.Rows[File], error)
+	// FindBySHA256 returns files with specified content hash.
+	FindBySHA256(ctx context.Context, hash string) (db.Rows[File], error)
 }
 
 type fileStore struct {
 	baseStore[File, FileEvent, *File, *FileEvent]
 }
 
+// FindByPath returns files that reference specified storage path.
+func (s *fileStore) FindByPath(
+	ctx context.Context, path string,
+) (db.Rows[File], error) {
+	return s.Find(ctx, db.FindQuery{Where: gosql.Column("path").Equal(path)})
+}
+
+// FindBySHA256 returns files with specified content hash.
+func (s *fileStore) FindBySHA256(
+	ctx context.Context, hash string,
+) (db.Rows[File], error) {
+	return s.Find(ctx, db.FindQuery{Where: gosql.Column("sha256").Equal(hash)})
+}
+
 type cachedFileStore struct {
 	cachedStore[File, FileEvent, *File, *FileEvent]
 }
 
+// FindByPath returns files that reference specified storage path.
+//
+// Files are fetched from database, so result is always up to date.
+func (s *cachedFileStore) FindByPath(
+	ctx context.Context, path string,
+) (db.Rows[File], error) {
+	return s.Find(ctx, db.FindQuery{Where: gosql.Column("path").Equal(path)})
+}
+
+// FindBySHA256 returns files with specified content hash.
+//
+// Files are fetched from database, so result is always up to date.
+func (s *cachedFileStore) FindBySHA256(
+	ctx context.Context, hash string,
+) (db.Rows[File], error) {
+	return s.Find(ctx, db.FindQuery{Where: gosql.Column("sha256").Equal(hash)})
+}
+
 // newFileStore creates a new instance of FileStore.
 func NewFileStore(
 	db *gosql.DB, table, eventTable string,
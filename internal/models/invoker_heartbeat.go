@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// InvokerHeartbeat represents last ping of invoker host.
+//
+// Heartbeats are updated frequently, so they are stored without
+// events and cache.
+type InvokerHeartbeat struct {
+	ID       int64  `db:"id"`
+	Name     string `db:"name"`
+	Workers  int64  `db:"workers"`
+	PingTime int64  `db:"ping_time"`
+	// ClockOffset contains offset of invoker clock relative to clock
+	// of database in milliseconds.
+	ClockOffset NInt64 `db:"clock_offset"`
+}
+
+func (o InvokerHeartbeat) ObjectID() int64 {
+	return o.ID
+}
+
+func (o *InvokerHeartbeat) SetObjectID(id int64) {
+	o.ID = id
+}
+
+type InvokerHeartbeatStore struct {
+	store db.ObjectStore[InvokerHeartbeat, *InvokerHeartbeat]
+}
+
+func (s *InvokerHeartbeatStore) Create(ctx context.Context, object *InvokerHeartbeat) error {
+	return s.store.CreateObject(ctx, object)
+}
+
+func (s *InvokerHeartbeatStore) Update(ctx context.Context, object InvokerHeartbeat) error {
+	return s.store.UpdateObject(ctx, &object)
+}
+
+func (s *InvokerHeartbeatStore) GetByName(ctx context.Context, name string) (InvokerHeartbeat, error) {
+	return s.store.FindObject(ctx, db.FindQuery{Where: gosql.Column("name").Equal(name)})
+}
+
+func (s *InvokerHeartbeatStore) All(ctx context.Context) (db.Rows[InvokerHeartbeat], error) {
+	return s.store.LoadObjects(ctx)
+}
+
+func NewInvokerHeartbeatStore(conn *gosql.DB, table string) *InvokerHeartbeatStore {
+	impl := &InvokerHeartbeatStore{
+		store: db.NewObjectStore[InvokerHeartbeat, *InvokerHeartbeat]("id", table, conn),
+	}
+	return impl
+}
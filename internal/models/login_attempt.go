@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// LoginAttemptStatus represents status of login attempt.
+type LoginAttemptStatus int
+
+const (
+	// SucceededLoginAttempt means that account is logged in.
+	SucceededLoginAttempt LoginAttemptStatus = 1
+	// FailedLoginAttempt means that invalid password is provided.
+	FailedLoginAttempt LoginAttemptStatus = 2
+	// LockedLoginAttempt means that attempt is rejected because of lock.
+	LockedLoginAttempt LoginAttemptStatus = 3
+)
+
+// String returns string representation.
+func (s LoginAttemptStatus) String() string {
+	switch s {
+	case SucceededLoginAttempt:
+		return "succeeded"
+	case FailedLoginAttempt:
+		return "failed"
+	case LockedLoginAttempt:
+		return "locked"
+	default:
+		return fmt.Sprintf("LoginAttemptStatus(%d)", s)
+	}
+}
+
+// LoginAttempt represents login attempt.
+type LoginAttempt struct {
+	ID        int64              `db:"id"`
+	Time      int64              `db:"time"`
+	AccountID int64              `db:"account_id"`
+	Status    LoginAttemptStatus `db:"status"`
+	UserAgent string             `db:"user_agent"`
+	RealIP    string             `db:"real_ip"`
+}
+
+// EventID returns ID of login attempt.
+func (o LoginAttempt) EventID() int64 {
+	return o.ID
+}
+
+// SetEventID sets ID of login attempt.
+func (o *LoginAttempt) SetEventID(id int64) {
+	o.ID = id
+}
+
+// EventTime return time of login attempt.
+func (o LoginAttempt) EventTime() time.Time {
+	return time.Unix(o.Time, 0)
+}
+
+// LoginAttemptStore represents login attempt store.
+type LoginAttemptStore struct {
+	db     *gosql.DB
+	events db.EventStore[LoginAttempt, *LoginAttempt]
+}
+
+// Create creates a new login attempt in the events.
+func (s *LoginAttemptStore) Create(ctx context.Context, attempt *LoginAttempt) error {
+	return s.events.CreateEvent(ctx, attempt)
+}
+
+// NewLoginAttemptStore creates a new instance of LoginAttemptStore.
+func NewLoginAttemptStore(dbConn *gosql.DB, table string) *LoginAttemptStore {
+	return &LoginAttemptStore{
+		db:     dbConn,
+		events: db.NewEventStore[LoginAttempt]("id", table, dbConn),
+	}
+}
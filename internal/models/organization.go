@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/udovin/gosql"
+)
+
+// Organization represents organization of users like university or
+// school.
+type Organization struct {
+	baseObject
+	Title string `db:"title"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country NString `db:"country"`
+}
+
+// Clone creates copy of organization.
+func (o Organization) Clone() Organization {
+	return o
+}
+
+// OrganizationEvent represents organization event.
+type OrganizationEvent struct {
+	baseEvent
+	Organization
+}
+
+// Object returns event organization.
+func (e OrganizationEvent) Object() Organization {
+	return e.Organization
+}
+
+// SetObject sets event organization.
+func (e *OrganizationEvent) SetObject(o Organization) {
+	e.Organization = o
+}
+
+// OrganizationStore represents store for organizations.
+type OrganizationStore struct {
+	cachedStore[Organization, OrganizationEvent, *Organization, *OrganizationEvent]
+}
+
+// NewOrganizationStore creates a new instance of OrganizationStore.
+func NewOrganizationStore(
+	db *gosql.DB, table, eventTable string,
+) *OrganizationStore {
+	impl := &OrganizationStore{}
+	impl.cachedStore = makeCachedStore[Organization, OrganizationEvent](
+		db, table, eventTable, impl,
+	)
+	return impl
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// PasswordHashing contains parameters of password hashing.
+type PasswordHashing struct {
+	// Time contains amount of Argon2id passes.
+	Time uint32
+	// Memory contains amount of memory in KiB.
+	Memory uint32
+	// Threads contains amount of parallel threads.
+	Threads uint8
+	// KeyLength contains length of hash in bytes.
+	KeyLength uint32
+	// DisableLegacy disables verification of passwords hashed with
+	// legacy salted SHA3 scheme.
+	DisableLegacy bool
+}
+
+// DefaultPasswordHashing contains default parameters of password hashing.
+var DefaultPasswordHashing = PasswordHashing{
+	Time:      2,
+	Memory:    19 * 1024,
+	Threads:   1,
+	KeyLength: 32,
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// argon2idHash represents parsed Argon2id hash in PHC string format.
+type argon2idHash struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	Salt    []byte
+	Key     []byte
+}
+
+func (h argon2idHash) String() string {
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(h.Salt),
+		base64.RawStdEncoding.EncodeToString(h.Key),
+	)
+}
+
+func parseArgon2idHash(hash string) (argon2idHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return argon2idHash{}, fmt.Errorf("invalid argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return argon2idHash{}, err
+	}
+	if version != argon2.Version {
+		return argon2idHash{}, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	var result argon2idHash
+	if _, err := fmt.Sscanf(
+		parts[3], "m=%d,t=%d,p=%d",
+		&result.Memory, &result.Time, &result.Threads,
+	); err != nil {
+		return argon2idHash{}, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argon2idHash{}, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return argon2idHash{}, err
+	}
+	if result.Time == 0 || result.Threads == 0 || len(key) == 0 {
+		return argon2idHash{}, fmt.Errorf("invalid argon2id hash")
+	}
+	result.Salt, result.Key = salt, key
+	return result, nil
+}
+
+// passwordHasher implements hashing of passwords with Argon2id and
+// verification of legacy salted hashes.
+//
+// Global salt is mixed into Argon2id salt and is not stored in hash.
+type passwordHasher struct {
+	salt    string
+	hashing PasswordHashing
+}
+
+func (h passwordHasher) argon2idKey(
+	password string, salt []byte, time, memory uint32, threads uint8, keyLen uint32,
+) []byte {
+	fullSalt := make([]byte, 0, len(salt)+len(h.salt))
+	fullSalt = append(fullSalt, salt...)
+	fullSalt = append(fullSalt, h.salt...)
+	return argon2.IDKey([]byte(password), fullSalt, time, memory, threads, keyLen)
+}
+
+// hash returns new password hash with random salt stored inside.
+func (h passwordHasher) hash(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	params := h.hashing
+	result := argon2idHash{
+		Time:    params.Time,
+		Memory:  params.Memory,
+		Threads: params.Threads,
+		Salt:    salt,
+	}
+	result.Key = h.argon2idKey(
+		password, salt, params.Time, params.Memory, params.Threads,
+		params.KeyLength,
+	)
+	return result.String(), nil
+}
+
+// check checks that password matches specified hash and salt.
+func (h passwordHasher) check(password, hash, salt string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		if h.hashing.DisableLegacy {
+			return false
+		}
+		passwordHash := hashPassword(password, salt, h.salt)
+		return subtle.ConstantTimeCompare(
+			[]byte(passwordHash), []byte(hash),
+		) == 1
+	}
+	parsed, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+	key := h.argon2idKey(
+		password, parsed.Salt, parsed.Time, parsed.Memory, parsed.Threads,
+		uint32(len(parsed.Key)),
+	)
+	return subtle.ConstantTimeCompare(key, parsed.Key) == 1
+}
+
+// needsRehash returns true if hash uses legacy scheme or outdated
+// parameters.
+func (h passwordHasher) needsRehash(hash string) bool {
+	parsed, err := parseArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	params := h.hashing
+	return parsed.Time != params.Time ||
+		parsed.Memory != params.Memory ||
+		parsed.Threads != params.Threads ||
+		uint32(len(parsed.Key)) != params.KeyLength ||
+		len(parsed.Salt) != 16
+}
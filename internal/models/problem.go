@@ -19,6 +19,8 @@ type Problem struct {
 	Title      string `db:"title"`
 	PackageID  NInt64 `db:"package_id"`
 	CompiledID NInt64 `db:"compiled_id"`
+	// ScopeID contains scope that owns problem.
+	ScopeID NInt64 `db:"scope_id"`
 }
 
 func (o Problem) GetConfig() (ProblemConfig, error) {
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ProblemCheckerStatus represents status of problem checker.
+type ProblemCheckerStatus int
+
+const (
+	// PendingProblemChecker means that checker is waiting for compilation.
+	PendingProblemChecker ProblemCheckerStatus = 1
+	// CompiledProblemChecker means that checker can be used for judging.
+	CompiledProblemChecker ProblemCheckerStatus = 2
+	// InvalidProblemChecker means that checker can not be compiled.
+	InvalidProblemChecker ProblemCheckerStatus = 3
+)
+
+// String returns string representation.
+func (s ProblemCheckerStatus) String() string {
+	switch s {
+	case PendingProblemChecker:
+		return "pending"
+	case CompiledProblemChecker:
+		return "compiled"
+	case InvalidProblemChecker:
+		return "invalid"
+	default:
+		return fmt.Sprintf("ProblemCheckerStatus(%d)", s)
+	}
+}
+
+func (s ProblemCheckerStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ProblemChecker represents checker that is uploaded to problem by jury
+// and replaces checker from problem package.
+//
+// Every upload creates new version of checker, the latest compiled
+// version is used for judging.
+type ProblemChecker struct {
+	baseObject
+	// ProblemID contains ID of problem.
+	ProblemID int64 `db:"problem_id"`
+	// CompilerID contains ID of compiler that is used for compilation
+	// and execution of checker.
+	CompilerID int64 `db:"compiler_id"`
+	// SourceID contains ID of file with source of checker.
+	SourceID NInt64 `db:"source_id"`
+	// BinaryID contains ID of file with compiled checker.
+	BinaryID NInt64               `db:"binary_id"`
+	Status   ProblemCheckerStatus `db:"status"`
+	// Message contains compilation log of invalid checker.
+	Message string `db:"message"`
+	// AuthorID contains ID of account that uploaded checker.
+	AuthorID   int64 `db:"author_id"`
+	CreateTime int64 `db:"create_time"`
+}
+
+// Clone creates copy of problem checker.
+func (o ProblemChecker) Clone() ProblemChecker {
+	return o
+}
+
+// ProblemCheckerEvent represents a problem checker event.
+type ProblemCheckerEvent struct {
+	baseEvent
+	ProblemChecker
+}
+
+// Object returns event problem checker.
+func (e ProblemCheckerEvent) Object() ProblemChecker {
+	return e.ProblemChecker
+}
+
+// SetObject sets event problem checker.
+func (e *ProblemCheckerEvent) SetObject(o ProblemChecker) {
+	e.ProblemChecker = o
+}
+
+// ProblemCheckerStore represents a problem checker store.
+type ProblemCheckerStore struct {
+	cachedStore[ProblemChecker, ProblemCheckerEvent, *ProblemChecker, *ProblemCheckerEvent]
+	byProblem *btreeIndex[int64, ProblemChecker, *ProblemChecker]
+}
+
+// FindByProblem returns checkers by problem ID.
+func (s *ProblemCheckerStore) FindByProblem(
+	ctx context.Context, problemID ...int64,
+) (db.Rows[ProblemChecker], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byProblem,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		problemID,
+		0,
+	), nil
+}
+
+// GetCompiledByProblem returns the latest compiled checker of problem.
+func (s *ProblemCheckerStore) GetCompiledByProblem(
+	ctx context.Context, problemID int64,
+) (ProblemChecker, error) {
+	rows, err := s.FindByProblem(ctx, problemID)
+	if err != nil {
+		return ProblemChecker{}, err
+	}
+	checkers, err := db.CollectRows(rows)
+	if err != nil {
+		return ProblemChecker{}, err
+	}
+	var result ProblemChecker
+	for _, checker := range checkers {
+		if checker.Status == CompiledProblemChecker && checker.ID > result.ID {
+			result = checker
+		}
+	}
+	if result.ID == 0 {
+		return ProblemChecker{}, sql.ErrNoRows
+	}
+	return result, nil
+}
+
+// NewProblemCheckerStore creates a new instance of ProblemCheckerStore.
+func NewProblemCheckerStore(
+	db *gosql.DB, table, eventTable string,
+) *ProblemCheckerStore {
+	impl := &ProblemCheckerStore{
+		byProblem: newBTreeIndex(func(o ProblemChecker) (int64, bool) { return o.ProblemID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ProblemChecker, ProblemCheckerEvent](
+		db, table, eventTable, impl, impl.byProblem,
+	)
+	return impl
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ProblemExtraTestStatus represents status of extra test.
+type ProblemExtraTestStatus int
+
+const (
+	// PendingProblemExtraTest means that test is waiting for validation.
+	PendingProblemExtraTest ProblemExtraTestStatus = 1
+	// ActiveProblemExtraTest means that test is used for judging.
+	ActiveProblemExtraTest ProblemExtraTestStatus = 2
+	// InvalidProblemExtraTest means that test is rejected by validator.
+	InvalidProblemExtraTest ProblemExtraTestStatus = 3
+)
+
+// String returns string representation.
+func (s ProblemExtraTestStatus) String() string {
+	switch s {
+	case PendingProblemExtraTest:
+		return "pending"
+	case ActiveProblemExtraTest:
+		return "active"
+	case InvalidProblemExtraTest:
+		return "invalid"
+	default:
+		return fmt.Sprintf("ProblemExtraTestStatus(%d)", s)
+	}
+}
+
+func (s ProblemExtraTestStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ProblemExtraTest represents test that is added to problem by jury
+// in addition to tests from problem package.
+type ProblemExtraTest struct {
+	baseObject
+	// ProblemID contains ID of problem.
+	ProblemID int64 `db:"problem_id"`
+	// InputID contains ID of file with test input.
+	InputID int64 `db:"input_id"`
+	// AnswerID contains ID of file with test answer.
+	AnswerID int64                  `db:"answer_id"`
+	Status   ProblemExtraTestStatus `db:"status"`
+	// Message contains message of validator for invalid test.
+	Message string `db:"message"`
+	// AuthorID contains ID of account that added test.
+	AuthorID   int64 `db:"author_id"`
+	CreateTime int64 `db:"create_time"`
+}
+
+// Clone creates copy of problem extra test.
+func (o ProblemExtraTest) Clone() ProblemExtraTest {
+	return o
+}
+
+// ProblemExtraTestEvent represents a problem extra test event.
+type ProblemExtraTestEvent struct {
+	baseEvent
+	ProblemExtraTest
+}
+
+// Object returns event problem extra test.
+func (e ProblemExtraTestEvent) Object() ProblemExtraTest {
+	return e.ProblemExtraTest
+}
+
+// SetObject sets event problem extra test.
+func (e *ProblemExtraTestEvent) SetObject(o ProblemExtraTest) {
+	e.ProblemExtraTest = o
+}
+
+// ProblemExtraTestStore represents a problem extra test store.
+type ProblemExtraTestStore struct {
+	cachedStore[ProblemExtraTest, ProblemExtraTestEvent, *ProblemExtraTest, *ProblemExtraTestEvent]
+	byProblem *btreeIndex[int64, ProblemExtraTest, *ProblemExtraTest]
+}
+
+// FindByProblem returns extra tests by problem ID.
+func (s *ProblemExtraTestStore) FindByProblem(
+	ctx context.Context, problemID ...int64,
+) (db.Rows[ProblemExtraTest], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byProblem,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		problemID,
+		0,
+	), nil
+}
+
+// NewProblemExtraTestStore creates a new instance of
+// ProblemExtraTestStore.
+func NewProblemExtraTestStore(
+	db *gosql.DB, table, eventTable string,
+) *ProblemExtraTestStore {
+	impl := &ProblemExtraTestStore{
+		byProblem: newBTreeIndex(func(o ProblemExtraTest) (int64, bool) { return o.ProblemID, true }, lessInt64),
+	}
+	impl.cachedStore = makeCachedStore[ProblemExtraTest, ProblemExtraTestEvent](
+		db, table, eventTable, impl, impl.byProblem,
+	)
+	return impl
+}
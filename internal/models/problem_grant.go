@@ -0,0 +1,140 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// ProblemGrantKind represents kind of problem access grant.
+type ProblemGrantKind int
+
+const (
+	// ReadProblemGrant grants access for observing problem.
+	ReadProblemGrant ProblemGrantKind = 1
+	// WriteProblemGrant grants access for observing and updating
+	// problem including uploading of new package.
+	WriteProblemGrant ProblemGrantKind = 2
+)
+
+// String returns string representation.
+func (k ProblemGrantKind) String() string {
+	switch k {
+	case ReadProblemGrant:
+		return "read"
+	case WriteProblemGrant:
+		return "write"
+	default:
+		return fmt.Sprintf("ProblemGrantKind(%d)", k)
+	}
+}
+
+func (k ProblemGrantKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *ProblemGrantKind) UnmarshalText(data []byte) error {
+	switch s := string(data); s {
+	case "read":
+		*k = ReadProblemGrant
+	case "write":
+		*k = WriteProblemGrant
+	default:
+		return fmt.Errorf("unsupported kind: %q", s)
+	}
+	return nil
+}
+
+func (k ProblemGrantKind) IsValid() bool {
+	switch k {
+	case ReadProblemGrant, WriteProblemGrant:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProblemGrant represents access of account to specific problem.
+type ProblemGrant struct {
+	baseObject
+	// ProblemID contains ID of problem.
+	ProblemID int64 `db:"problem_id"`
+	// AccountID contains ID of account, that can be user, scope
+	// or group.
+	AccountID int64            `db:"account_id"`
+	Kind      ProblemGrantKind `db:"kind"`
+}
+
+// Clone creates copy of problem grant.
+func (o ProblemGrant) Clone() ProblemGrant {
+	return o
+}
+
+// ProblemGrantEvent represents a problem grant event.
+type ProblemGrantEvent struct {
+	baseEvent
+	ProblemGrant
+}
+
+// Object returns event problem grant.
+func (e ProblemGrantEvent) Object() ProblemGrant {
+	return e.ProblemGrant
+}
+
+// SetObject sets event problem grant.
+func (e *ProblemGrantEvent) SetObject(o ProblemGrant) {
+	e.ProblemGrant = o
+}
+
+// ProblemGrantStore represents a problem grant store.
+type ProblemGrantStore struct {
+	cachedStore[ProblemGrant, ProblemGrantEvent, *ProblemGrant, *ProblemGrantEvent]
+	byProblem        *btreeIndex[int64, ProblemGrant, *ProblemGrant]
+	byProblemAccount *btreeIndex[pair[int64, int64], ProblemGrant, *ProblemGrant]
+}
+
+// FindByProblem returns grants by problem ID.
+func (s *ProblemGrantStore) FindByProblem(
+	ctx context.Context, problemID ...int64,
+) (db.Rows[ProblemGrant], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byProblem,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		problemID,
+		0,
+	), nil
+}
+
+// FindByProblemAccount returns grants by problem ID and account ID.
+func (s *ProblemGrantStore) FindByProblemAccount(
+	ctx context.Context, problemID int64, accountID int64,
+) (db.Rows[ProblemGrant], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byProblemAccount,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		[]pair[int64, int64]{makePair(problemID, accountID)},
+		0,
+	), nil
+}
+
+// NewProblemGrantStore creates a new instance of ProblemGrantStore.
+func NewProblemGrantStore(
+	db *gosql.DB, table, eventTable string,
+) *ProblemGrantStore {
+	impl := &ProblemGrantStore{
+		byProblem: newBTreeIndex(func(o ProblemGrant) (int64, bool) { return o.ProblemID, true }, lessInt64),
+		byProblemAccount: newBTreeIndex(func(o ProblemGrant) (pair[int64, int64], bool) {
+			return makePair(o.ProblemID, o.AccountID), true
+		}, lessPairInt64),
+	}
+	impl.cachedStore = makeCachedStore[ProblemGrant, ProblemGrantEvent](
+		db, table, eventTable, impl, impl.byProblem, impl.byProblemAccount,
+	)
+	return impl
+}
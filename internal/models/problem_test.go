@@ -17,7 +17,8 @@ func (t *problemStoreTest) prepareDB(tx *sql.Tx) error {
 			`"config" text NOT NULL,` +
 			`"title" VARCHAR(255) NOT NULL,` +
 			`"package_id" integer,` +
-			`"compiled_id" integer)`,
+			`"compiled_id" integer,` +
+			`"scope_id" integer)`,
 	); err != nil {
 		log.Println("Error", err)
 		return err
@@ -33,7 +34,8 @@ func (t *problemStoreTest) prepareDB(tx *sql.Tx) error {
 			`"config" text NOT NULL,` +
 			`"title" VARCHAR(255) NOT NULL,` +
 			`"package_id" integer,` +
-			`"compiled_id" integer)`,
+			`"compiled_id" integer,` +
+			`"scope_id" integer)`,
 	)
 	log.Println("Error", err)
 	return err
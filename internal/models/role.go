@@ -39,7 +39,8 @@ func (e *RoleEvent) SetObject(o Role) {
 // RoleStore represents a role store.
 type RoleStore struct {
 	cachedStore[Role, RoleEvent, *Role, *RoleEvent]
-	byName *btreeIndex[string, Role, *Role]
+	byName   *btreeIndex[string, Role, *Role]
+	versions *versionIndex[struct{}, Role]
 }
 
 // GetByName returns role by name.
@@ -52,6 +53,15 @@ func (s *RoleStore) GetByName(ctx context.Context, name string) (Role, error) {
 	return btreeIndexGet(s.byName, s.objects.Iter(), name)
 }
 
+// GetVersion returns version of roles.
+//
+// Version is changed on every change of roles.
+func (s *RoleStore) GetVersion() int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.versions.Get(struct{}{})
+}
+
 // NewRoleStore creates a new instance of RoleStore.
 func NewRoleStore(
 	db *gosql.DB, table, eventTable string,
@@ -61,9 +71,12 @@ func NewRoleStore(
 			func(o Role) (string, bool) { return o.Name, true },
 			lessString,
 		),
+		versions: newVersionIndex(func(o Role) (struct{}, bool) {
+			return struct{}{}, true
+		}),
 	}
 	impl.cachedStore = makeCachedStore[Role, RoleEvent](
-		db, table, eventTable, impl, impl.byName,
+		db, table, eventTable, impl, impl.byName, impl.versions,
 	)
 	return impl
 }
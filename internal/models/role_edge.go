@@ -40,7 +40,8 @@ func (e *RoleEdgeEvent) SetObject(o RoleEdge) {
 // RoleEdgeStore represents a role edge store.
 type RoleEdgeStore struct {
 	cachedStore[RoleEdge, RoleEdgeEvent, *RoleEdge, *RoleEdgeEvent]
-	byRole *btreeIndex[int64, RoleEdge, *RoleEdge]
+	byRole   *btreeIndex[int64, RoleEdge, *RoleEdge]
+	versions *versionIndex[struct{}, RoleEdge]
 }
 
 // FindByRole returns edges by parent ID.
@@ -55,6 +56,15 @@ func (s *RoleEdgeStore) FindByRole(ctx context.Context, roleID ...int64) (db.Row
 	), nil
 }
 
+// GetVersion returns version of role edges.
+//
+// Version is changed on every change of role edges.
+func (s *RoleEdgeStore) GetVersion() int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.versions.Get(struct{}{})
+}
+
 // NewRoleEdgeStore creates a new instance of RoleEdgeStore.
 func NewRoleEdgeStore(
 	db *gosql.DB, table, eventTable string,
@@ -64,9 +74,12 @@ func NewRoleEdgeStore(
 			func(o RoleEdge) (int64, bool) { return o.RoleID, true },
 			lessInt64,
 		),
+		versions: newVersionIndex(func(o RoleEdge) (struct{}, bool) {
+			return struct{}{}, true
+		}),
 	}
 	impl.cachedStore = makeCachedStore[RoleEdge, RoleEdgeEvent](
-		db, table, eventTable, impl, impl.byRole,
+		db, table, eventTable, impl, impl.byRole, impl.versions,
 	)
 	return impl
 }
@@ -1,7 +1,6 @@
 package models
 
 import (
-	"crypto/rand"
 	"strings"
 
 	"github.com/udovin/gosql"
@@ -16,6 +15,10 @@ type ScopeUser struct {
 	PasswordHash string  `db:"password_hash"`
 	PasswordSalt string  `db:"password_salt"`
 	Title        NString `db:"title"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country NString `db:"country"`
+	// OrganizationID contains ID of organization.
+	OrganizationID NInt64 `db:"organization_id"`
 }
 
 // AccountKind returns ScopeUserAccount kind.
@@ -49,7 +52,7 @@ type ScopeUserStore struct {
 	cachedStore[ScopeUser, ScopeUserEvent, *ScopeUser, *ScopeUserEvent]
 	byScope      *btreeIndex[int64, ScopeUser, *ScopeUser]
 	byScopeLogin *btreeIndex[pair[int64, string], ScopeUser, *ScopeUser]
-	salt         string
+	hasher       passwordHasher
 }
 
 // FindByScope returns scope users by scope.
@@ -77,24 +80,36 @@ func (s *ScopeUserStore) GetByScopeLogin(scopeID int64, login string) (ScopeUser
 
 // SetPassword modifies PasswordHash and PasswordSalt fields.
 //
-// PasswordSalt will be replaced with random 16 byte string
-// and PasswordHash will be calculated using password, salt
-// and global salt.
+// PasswordHash will be calculated using Argon2id with random 16 byte
+// salt and global salt. PasswordSalt will be cleared because salt is
+// stored inside PasswordHash.
 func (s *ScopeUserStore) SetPassword(user *ScopeUser, password string) error {
-	saltBytes := make([]byte, 16)
-	_, err := rand.Read(saltBytes)
+	hash, err := s.hasher.hash(password)
 	if err != nil {
 		return err
 	}
-	user.PasswordSalt = encodeBase64(saltBytes)
-	user.PasswordHash = hashPassword(password, user.PasswordSalt, s.salt)
+	user.PasswordHash = hash
+	user.PasswordSalt = ""
 	return nil
 }
 
 // CheckPassword checks that passwords are the same.
+//
+// Passwords hashed with legacy salted scheme are also accepted
+// unless legacy hashes are disabled.
 func (s *ScopeUserStore) CheckPassword(user ScopeUser, password string) bool {
-	passwordHash := hashPassword(password, user.PasswordSalt, s.salt)
-	return passwordHash == user.PasswordHash
+	return s.hasher.check(password, user.PasswordHash, user.PasswordSalt)
+}
+
+// NeedsRehash returns true if password of user should be hashed again
+// with current parameters.
+func (s *ScopeUserStore) NeedsRehash(user ScopeUser) bool {
+	return s.hasher.needsRehash(user.PasswordHash)
+}
+
+// SetPasswordHashing sets parameters of password hashing.
+func (s *ScopeUserStore) SetPasswordHashing(hashing PasswordHashing) {
+	s.hasher.hashing = hashing
 }
 
 // NewScopeUserStore creates new instance of scope user store.
@@ -109,7 +124,7 @@ func NewScopeUserStore(
 			},
 			lessPairInt64String,
 		),
-		salt: salt,
+		hasher: passwordHasher{salt: salt, hashing: DefaultPasswordHashing},
 	}
 	impl.cachedStore = makeCachedManualStore[ScopeUser, ScopeUserEvent](
 		db, table, eventTable, impl, impl.byScope, impl.byScopeLogin,
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/udovin/gosql"
 	"github.com/udovin/solve/internal/db"
@@ -42,6 +44,22 @@ const (
 	Failed Verdict = 10
 )
 
+// MinCustomVerdict contains minimal value of custom verdict.
+//
+// Custom verdicts are defined in settings, so they are represented
+// in text as "custom_<id>", where id is offset from MinCustomVerdict.
+const MinCustomVerdict Verdict = 1000
+
+// CustomVerdict returns custom verdict with specified ID.
+func CustomVerdict(id int) Verdict {
+	return MinCustomVerdict + Verdict(id)
+}
+
+// IsCustom returns true if verdict is defined in settings.
+func (v Verdict) IsCustom() bool {
+	return v >= MinCustomVerdict
+}
+
 func (v Verdict) String() string {
 	switch v {
 	case Accepted:
@@ -65,6 +83,9 @@ func (v Verdict) String() string {
 	case Failed:
 		return "failed"
 	default:
+		if v.IsCustom() {
+			return fmt.Sprintf("custom_%d", v-MinCustomVerdict)
+		}
 		return fmt.Sprintf("Verdict(%d)", v)
 	}
 }
@@ -96,6 +117,12 @@ func (v *Verdict) UnmarshalText(data []byte) error {
 	case "failed":
 		*v = Failed
 	default:
+		if id, ok := strings.CutPrefix(s, "custom_"); ok {
+			if n, err := strconv.Atoi(id); err == nil && n >= 0 {
+				*v = CustomVerdict(n)
+				return nil
+			}
+		}
 		return fmt.Errorf("unsupported kind: %q", s)
 	}
 	return nil
@@ -111,12 +138,33 @@ type ExecuteReport struct {
 	Log   string      `json:"log"`
 }
 
+// TestArtifacts contains IDs of compressed files that are produced
+// during test run.
+type TestArtifacts struct {
+	// OutputID contains ID of file with solution output.
+	OutputID int64 `json:"output_id,omitempty"`
+	// CheckerLogID contains ID of file with checker or interactor log.
+	CheckerLogID int64 `json:"checker_log_id,omitempty"`
+}
+
 type TestReport struct {
 	Verdict    Verdict        `json:"verdict"`
 	Usage      UsageReport    `json:"usage"`
 	Checker    *ExecuteReport `json:"checker,omitempty"`
 	Interactor *ExecuteReport `json:"interactor,omitempty"`
 	Points     *float64       `json:"points,omitempty"`
+	// Artifacts contains files of failed test.
+	Artifacts *TestArtifacts `json:"artifacts,omitempty"`
+	// Runs contains amount of solution runs on test when test is
+	// repeated because of time near to limit.
+	Runs int `json:"runs,omitempty"`
+	// Group contains name of test group.
+	Group string `json:"group,omitempty"`
+	// Sample is true for tests that are shown in statement.
+	Sample bool `json:"sample,omitempty"`
+	// TimeLimit contains effective time limit of test in milliseconds
+	// scaled by time factor of invoker.
+	TimeLimit int64 `json:"time_limit,omitempty"`
 }
 
 type SolutionReport struct {
@@ -125,6 +173,9 @@ type SolutionReport struct {
 	Compiler *ExecuteReport `json:"compiler,omitempty"`
 	Tests    []TestReport   `json:"tests,omitempty"`
 	Points   *float64       `json:"points,omitempty"`
+	// Pretests means that solution is judged only on pretests and
+	// verdict is preliminary until system testing.
+	Pretests bool `json:"pretests,omitempty"`
 }
 
 // Solution represents a solution.
@@ -191,6 +242,7 @@ func (e *SolutionEvent) SetObject(o Solution) {
 type SolutionStore struct {
 	cachedStore[Solution, SolutionEvent, *Solution, *SolutionEvent]
 	byProblem *btreeIndex[int64, Solution, *Solution]
+	byAuthor  *btreeIndex[int64, Solution, *Solution]
 }
 
 func (s *SolutionStore) FindByProblem(ctx context.Context, problemID ...int64) (db.Rows[Solution], error) {
@@ -204,6 +256,33 @@ func (s *SolutionStore) FindByProblem(ctx context.Context, problemID ...int64) (
 	), nil
 }
 
+// FindByAuthor returns solutions by author ID.
+func (s *SolutionStore) FindByAuthor(ctx context.Context, authorID ...int64) (db.Rows[Solution], error) {
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byAuthor,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		authorID,
+		0,
+	), nil
+}
+
+// ReverseFindByAuthorFrom returns solutions by author ID in reverse order
+// starting from specified solution ID.
+func (s *SolutionStore) ReverseFindByAuthorFrom(
+	ctx context.Context, authorID []int64, beginID int64,
+) (db.Rows[Solution], error) {
+	s.mutex.RLock()
+	return btreeIndexReverseFind(
+		s.byAuthor,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		authorID,
+		beginID,
+	), nil
+}
+
 // NewSolutionStore creates a new instance of SolutionStore.
 func NewSolutionStore(
 	db *gosql.DB, table, eventTable string,
@@ -213,9 +292,13 @@ func NewSolutionStore(
 			func(o Solution) (int64, bool) { return o.ProblemID, true },
 			lessInt64,
 		),
+		byAuthor: newBTreeIndex(
+			func(o Solution) (int64, bool) { return o.AuthorID, true },
+			lessInt64,
+		),
 	}
 	impl.cachedStore = makeCachedStore[Solution, SolutionEvent](
-		db, table, eventTable, impl, impl.byProblem,
+		db, table, eventTable, impl, impl.byProblem, impl.byAuthor,
 	)
 	return impl
 }
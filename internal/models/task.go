@@ -22,6 +22,9 @@ const (
 	// SucceededTask means that task is processed with success.
 	SucceededTask TaskStatus = 2
 	// FailedTask means that task is processed with failure.
+	//
+	// Failed tasks are kept in dead-letter queue until they are
+	// requeued manually.
 	FailedTask TaskStatus = 3
 )
 
@@ -54,6 +57,22 @@ const (
 	JudgeSolutionTask TaskKind = 1
 	// UpdateProblemPackageTask represents task for update problem package.
 	UpdateProblemPackageTask TaskKind = 2
+	// StressProblemTask represents task for stress-testing of solution.
+	StressProblemTask TaskKind = 3
+	// ValidateProblemTestTask represents task for validation of test.
+	ValidateProblemTestTask TaskKind = 4
+	// BuildCompilerImageTask represents task for building compiler image.
+	BuildCompilerImageTask TaskKind = 5
+	// SelfTestInvokerTask represents diagnostic task for invoker host.
+	SelfTestInvokerTask TaskKind = 6
+	// RetestSolutionTask represents task for running solution on single test.
+	RetestSolutionTask TaskKind = 7
+	// SystemTestContestTask represents task for judging of contest
+	// solutions on full tests after contest.
+	SystemTestContestTask TaskKind = 8
+	// CompileProblemCheckerTask represents task for compilation of
+	// checker that is uploaded to problem.
+	CompileProblemCheckerTask TaskKind = 9
 )
 
 // String returns string representation.
@@ -63,6 +82,20 @@ func (t TaskKind) String() string {
 		return "judge_solution"
 	case UpdateProblemPackageTask:
 		return "update_problem_package"
+	case StressProblemTask:
+		return "stress_problem"
+	case ValidateProblemTestTask:
+		return "validate_problem_test"
+	case BuildCompilerImageTask:
+		return "build_compiler_image"
+	case SelfTestInvokerTask:
+		return "self_test_invoker"
+	case RetestSolutionTask:
+		return "retest_solution"
+	case SystemTestContestTask:
+		return "system_test_contest"
+	case CompileProblemCheckerTask:
+		return "compile_problem_checker"
 	default:
 		return fmt.Sprintf("TaskKind(%d)", t)
 	}
@@ -77,6 +110,8 @@ func (t TaskKind) MarshalText() ([]byte, error) {
 type JudgeSolutionTaskConfig struct {
 	SolutionID   int64 `json:"solution_id"`
 	EnablePoints bool  `json:"enable_points,omitempty"`
+	// ParticipantID contains ID of contest participant that owns solution.
+	ParticipantID int64 `json:"participant_id,omitempty"`
 }
 
 func (c JudgeSolutionTaskConfig) TaskKind() TaskKind {
@@ -86,6 +121,8 @@ func (c JudgeSolutionTaskConfig) TaskKind() TaskKind {
 type JudgeSolutionTaskState struct {
 	Stage string `json:"stage,omitempty"`
 	Test  int    `json:"test,omitempty"`
+	// TotalTests contains total amount of tests in problem.
+	TotalTests int `json:"total_tests,omitempty"`
 }
 
 // UpdateProblemPackageTaskConfig represets config for JudgeSolution.
@@ -93,6 +130,9 @@ type UpdateProblemPackageTaskConfig struct {
 	ProblemID int64 `json:"problem_id"`
 	FileID    int64 `json:"file_id"`
 	Compile   bool  `json:"compile"`
+	// Rejudge enables rejudge of solutions which verdict can be changed
+	// after package update.
+	Rejudge bool `json:"rejudge,omitempty"`
 }
 
 func (c UpdateProblemPackageTaskConfig) TaskKind() TaskKind {
@@ -101,6 +141,202 @@ func (c UpdateProblemPackageTaskConfig) TaskKind() TaskKind {
 
 type UpdateProblemPackageTaskState struct {
 	Error string `json:"error,omitempty"`
+	// RejudgeTasks contains IDs of enqueued judge solution tasks.
+	RejudgeTasks []int64 `json:"rejudge_tasks,omitempty"`
+}
+
+// StressSource represents source code of program for stress-testing.
+type StressSource struct {
+	CompilerID int64  `json:"compiler_id"`
+	Content    string `json:"content"`
+}
+
+// StressProblemTaskConfig represents config for StressProblem.
+//
+// Generator is called with test number as single argument and should
+// print test input to stdout. Answer of model solution is used for
+// checking output of solution with problem checker.
+type StressProblemTaskConfig struct {
+	ProblemID     int64        `json:"problem_id"`
+	Generator     StressSource `json:"generator"`
+	ModelSolution StressSource `json:"model_solution"`
+	Solution      StressSource `json:"solution"`
+	Tests         int          `json:"tests"`
+}
+
+func (c StressProblemTaskConfig) TaskKind() TaskKind {
+	return StressProblemTask
+}
+
+// StressMismatch represents test on which solution is failed.
+type StressMismatch struct {
+	Test       int     `json:"test"`
+	Input      string  `json:"input"`
+	Answer     string  `json:"answer,omitempty"`
+	Output     string  `json:"output,omitempty"`
+	Verdict    Verdict `json:"verdict"`
+	CheckerLog string  `json:"checker_log,omitempty"`
+}
+
+type StressProblemTaskState struct {
+	Stage string `json:"stage,omitempty"`
+	Test  int    `json:"test,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Mismatch contains first test on which solution is failed.
+	Mismatch *StressMismatch `json:"mismatch,omitempty"`
+}
+
+// ValidateProblemTestTaskConfig represents config for ValidateProblemTest.
+type ValidateProblemTestTaskConfig struct {
+	ProblemID int64 `json:"problem_id"`
+	// InputID contains ID of file with test input.
+	InputID int64 `json:"input_id"`
+	// TestID contains ID of extra test that will be activated after
+	// successful validation.
+	TestID int64 `json:"test_id,omitempty"`
+}
+
+func (c ValidateProblemTestTaskConfig) TaskKind() TaskKind {
+	return ValidateProblemTestTask
+}
+
+type ValidateProblemTestTaskState struct {
+	Valid bool `json:"valid"`
+	// Message contains message of validator for invalid test.
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// RejudgeTasks contains IDs of enqueued judge solution tasks.
+	RejudgeTasks []int64 `json:"rejudge_tasks,omitempty"`
+}
+
+// BuildCompilerImageTaskConfig represents config for BuildCompilerImage.
+//
+// Exactly one of Image and Dockerfile should be specified.
+type BuildCompilerImageTaskConfig struct {
+	CompilerID int64 `json:"compiler_id"`
+	// Image contains reference to OCI image.
+	Image string `json:"image,omitempty"`
+	// Dockerfile contains content of Dockerfile.
+	Dockerfile string `json:"dockerfile,omitempty"`
+}
+
+func (c BuildCompilerImageTaskConfig) TaskKind() TaskKind {
+	return BuildCompilerImageTask
+}
+
+type BuildCompilerImageTaskState struct {
+	Stage string `json:"stage,omitempty"`
+	Error string `json:"error,omitempty"`
+	// ImageID contains ID of file with built compiler image.
+	ImageID int64 `json:"image_id,omitempty"`
+}
+
+// SelfTestInvokerTaskConfig represents config for SelfTestInvoker.
+//
+// Self-test runs known-good solution of built-in A+B problem with
+// specified compiler.
+type SelfTestInvokerTaskConfig struct {
+	CompilerID int64 `json:"compiler_id"`
+	// Invoker contains name of invoker that should run self-test.
+	//
+	// Empty invoker means that self-test can be run by any invoker.
+	Invoker string `json:"invoker,omitempty"`
+	// Source contains solution of A+B problem.
+	//
+	// Built-in solution for language of compiler is used if empty.
+	Source string `json:"source,omitempty"`
+	Tests  int    `json:"tests"`
+	// Reference means that invoker is used as reference machine, so
+	// measured run time is saved as reference time.
+	Reference bool `json:"reference,omitempty"`
+	// Calibrate enables update of invoker time factor by comparison of
+	// measured run time with reference time.
+	Calibrate bool `json:"calibrate,omitempty"`
+}
+
+func (c SelfTestInvokerTaskConfig) TaskKind() TaskKind {
+	return SelfTestInvokerTask
+}
+
+type SelfTestInvokerTaskState struct {
+	Stage string `json:"stage,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Invoker contains name of invoker that has run self-test.
+	Invoker string `json:"invoker,omitempty"`
+	// CompileTime contains time of compilation in milliseconds.
+	CompileTime int64 `json:"compile_time,omitempty"`
+	// Tests contains amount of passed tests.
+	Tests int `json:"tests,omitempty"`
+	// MinTime contains minimal time of solution run in milliseconds.
+	MinTime int64 `json:"min_time,omitempty"`
+	// MaxTime contains maximal time of solution run in milliseconds.
+	MaxTime int64 `json:"max_time,omitempty"`
+	// AvgTime contains average time of solution run in milliseconds.
+	AvgTime int64 `json:"avg_time,omitempty"`
+	// MaxMemory contains maximal memory usage of solution in bytes.
+	MaxMemory int64 `json:"max_memory,omitempty"`
+	// TimeFactor contains saved time factor of invoker in per mille.
+	TimeFactor int64 `json:"time_factor,omitempty"`
+}
+
+// RetestSolutionTaskConfig represents config for RetestSolution.
+type RetestSolutionTaskConfig struct {
+	SolutionID int64 `json:"solution_id"`
+	// Test contains number of test starting from 1.
+	Test int `json:"test"`
+	// UpdateReport enables replacement of test in solution report.
+	//
+	// Verdict of solution is not recalculated, so full rejudge should
+	// be used when verdict of solution is affected.
+	UpdateReport bool `json:"update_report,omitempty"`
+}
+
+func (c RetestSolutionTaskConfig) TaskKind() TaskKind {
+	return RetestSolutionTask
+}
+
+type RetestSolutionTaskState struct {
+	Stage string `json:"stage,omitempty"`
+	Error string `json:"error,omitempty"`
+	// CompileLog contains log of failed solution compilation.
+	CompileLog string      `json:"compile_log,omitempty"`
+	Report     *TestReport `json:"report,omitempty"`
+	// Output contains prefix of solution output.
+	Output     string `json:"output,omitempty"`
+	CheckerLog string `json:"checker_log,omitempty"`
+}
+
+// SystemTestContestTaskConfig represents config for SystemTestContest.
+type SystemTestContestTaskConfig struct {
+	ContestID int64 `json:"contest_id"`
+}
+
+func (c SystemTestContestTaskConfig) TaskKind() TaskKind {
+	return SystemTestContestTask
+}
+
+type SystemTestContestTaskState struct {
+	Error string `json:"error,omitempty"`
+	// RejudgeTasks contains IDs of enqueued judge solution tasks.
+	RejudgeTasks []int64 `json:"rejudge_tasks,omitempty"`
+}
+
+// CompileProblemCheckerTaskConfig represents config for
+// CompileProblemChecker.
+type CompileProblemCheckerTaskConfig struct {
+	ProblemID int64 `json:"problem_id"`
+	// CheckerID contains ID of problem checker.
+	CheckerID int64 `json:"checker_id"`
+}
+
+func (c CompileProblemCheckerTaskConfig) TaskKind() TaskKind {
+	return CompileProblemCheckerTask
+}
+
+type CompileProblemCheckerTaskState struct {
+	Error string `json:"error,omitempty"`
+	// RejudgeTasks contains IDs of enqueued judge solution tasks.
+	RejudgeTasks []int64 `json:"rejudge_tasks,omitempty"`
 }
 
 type TaskConfig interface {
@@ -173,6 +409,7 @@ type TaskStore struct {
 	cachedStore[Task, TaskEvent, *Task, *TaskEvent]
 	bySolution *btreeIndex[int64, Task, *Task]
 	byProblem  *btreeIndex[int64, Task, *Task]
+	byStatus   *btreeIndex[int64, Task, *Task]
 }
 
 // FindBySolution returns a list of tasks by specified solution.
@@ -198,19 +435,41 @@ func (s *TaskStore) FindByProblem(ctx context.Context, problemID ...int64) (db.R
 	), nil
 }
 
+// FindByStatus returns a list of tasks with specified statuses.
+func (s *TaskStore) FindByStatus(ctx context.Context, status ...TaskStatus) (db.Rows[Task], error) {
+	keys := make([]int64, 0, len(status))
+	for _, value := range status {
+		keys = append(keys, int64(value))
+	}
+	s.mutex.RLock()
+	return btreeIndexFind(
+		s.byStatus,
+		s.objects.Iter(),
+		s.mutex.RLocker(),
+		keys,
+		0,
+	), nil
+}
+
 // PopQueued pops queued action from the events and sets running status.
 //
+// If participantLimit is positive, then judge tasks of contest participants
+// that already have participantLimit running tasks are skipped. Queued
+// tasks are read in batches, so busy participants at the head of queue
+// do not block other tasks.
+//
 // Note that events is not synchronized after tasks is popped.
 func (s *TaskStore) PopQueued(
 	ctx context.Context,
 	duration time.Duration,
-	filter func(TaskKind) bool,
+	participantLimit int,
+	filter func(Task) bool,
 ) (Task, error) {
 	tx := db.GetTx(ctx)
 	if tx == nil {
 		var task Task
 		err := gosql.WrapTx(ctx, s.db, func(tx *sql.Tx) (err error) {
-			task, err = s.PopQueued(db.WithTx(ctx, tx), duration, filter)
+			task, err = s.PopQueued(db.WithTx(ctx, tx), duration, participantLimit, filter)
 			return err
 		}, sqlRepeatableRead)
 		return task, err
@@ -218,33 +477,101 @@ func (s *TaskStore) PopQueued(
 	if err := s.lockStore(tx); err != nil {
 		return Task{}, err
 	}
+	var running map[int64]int
+	if participantLimit > 0 {
+		var err error
+		running, err = s.countRunningParticipantTasks(ctx)
+		if err != nil {
+			return Task{}, err
+		}
+	}
+	var lastID int64
+	for {
+		tasks, err := s.findQueuedTasks(ctx, lastID)
+		if err != nil {
+			return Task{}, err
+		}
+		for _, task := range tasks {
+			lastID = task.ID
+			if filter != nil && !filter(task) {
+				continue
+			}
+			if task.Status != QueuedTask {
+				return Task{}, fmt.Errorf("unexpected status: %s", task.Status)
+			}
+			if participantLimit > 0 {
+				if id := getTaskParticipantID(task); id != 0 && running[id] >= participantLimit {
+					continue
+				}
+			}
+			task.Status = RunningTask
+			task.ExpireTime = NInt64(time.Now().Add(duration).Unix())
+			if err := s.Update(ctx, task); err != nil {
+				return Task{}, err
+			}
+			return task, nil
+		}
+		// Tasks of busy participants can occupy the head of the queue,
+		// so next batches are checked only if participants are limited.
+		if participantLimit <= 0 || len(tasks) < popQueuedBatchSize {
+			return Task{}, sql.ErrNoRows
+		}
+	}
+}
+
+// popQueuedBatchSize contains amount of queued tasks that are read
+// at once by PopQueued.
+const popQueuedBatchSize = 10
+
+// findQueuedTasks returns batch of queued tasks with ID greater than
+// specified one.
+func (s *TaskStore) findQueuedTasks(ctx context.Context, afterID int64) ([]Task, error) {
+	rows, err := s.Find(ctx, db.FindQuery{
+		Where: gosql.Column("status").Equal(QueuedTask).
+			And(gosql.Column("id").Greater(afterID)),
+		Limit:   popQueuedBatchSize,
+		OrderBy: []any{gosql.Ascending("id")},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db.CollectRows(rows)
+}
+
+// countRunningParticipantTasks returns amount of running judge tasks
+// for each contest participant.
+func (s *TaskStore) countRunningParticipantTasks(ctx context.Context) (map[int64]int, error) {
 	reader, err := s.Find(ctx, db.FindQuery{
-		Where: gosql.Column("status").Equal(QueuedTask),
-		Limit: 10,
+		Where: gosql.Column("status").Equal(RunningTask),
 	})
 	if err != nil {
-		return Task{}, err
+		return nil, err
 	}
-	defer reader.Close()
+	defer func() { _ = reader.Close() }()
+	now := time.Now().Unix()
+	counts := map[int64]int{}
 	for reader.Next() {
 		task := reader.Row()
-		if filter != nil && !filter(task.Kind) {
+		if int64(task.ExpireTime) < now {
+			// Expired tasks are abandoned by invokers.
 			continue
 		}
-		if task.Status != QueuedTask {
-			return Task{}, fmt.Errorf("unexpected status: %s", task.Status)
-		}
-		if err := reader.Close(); err != nil {
-			return Task{}, err
+		if id := getTaskParticipantID(task); id != 0 {
+			counts[id]++
 		}
-		task.Status = RunningTask
-		task.ExpireTime = NInt64(time.Now().Add(duration).Unix())
-		if err := s.Update(ctx, task); err != nil {
-			return Task{}, err
-		}
-		return task, nil
 	}
-	return Task{}, sql.ErrNoRows
+	return counts, reader.Err()
+}
+
+func getTaskParticipantID(task Task) int64 {
+	if task.Kind != JudgeSolutionTask {
+		return 0
+	}
+	var config JudgeSolutionTaskConfig
+	if err := task.ScanConfig(&config); err != nil {
+		return 0
+	}
+	return config.ParticipantID
 }
 
 // NewTaskStore creates a new instance of TaskStore.
@@ -259,6 +586,11 @@ func NewTaskStore(
 				if err := o.ScanConfig(&config); err == nil {
 					return config.SolutionID, true
 				}
+			case RetestSolutionTask:
+				var config RetestSolutionTaskConfig
+				if err := o.ScanConfig(&config); err == nil {
+					return config.SolutionID, true
+				}
 			}
 			return 0, false
 		}, lessInt64),
@@ -269,12 +601,30 @@ func NewTaskStore(
 				if err := o.ScanConfig(&config); err == nil {
 					return config.ProblemID, true
 				}
+			case StressProblemTask:
+				var config StressProblemTaskConfig
+				if err := o.ScanConfig(&config); err == nil {
+					return config.ProblemID, true
+				}
+			case ValidateProblemTestTask:
+				var config ValidateProblemTestTaskConfig
+				if err := o.ScanConfig(&config); err == nil {
+					return config.ProblemID, true
+				}
+			case CompileProblemCheckerTask:
+				var config CompileProblemCheckerTaskConfig
+				if err := o.ScanConfig(&config); err == nil {
+					return config.ProblemID, true
+				}
 			}
 			return 0, false
 		}, lessInt64),
 	}
+	impl.byStatus = newBTreeIndex(func(o Task) (int64, bool) {
+		return int64(o.Status), true
+	}, lessInt64)
 	impl.cachedStore = makeCachedStore[Task, TaskEvent](
-		db, table, eventTable, impl, impl.bySolution, impl.byProblem,
+		db, table, eventTable, impl, impl.bySolution, impl.byProblem, impl.byStatus,
 	)
 	return impl
 }
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/internal/db"
+)
+
+// TaskFailureEnvironment represents environment of invoker that
+// failed task.
+type TaskFailureEnvironment struct {
+	Hostname  string `json:"hostname,omitempty"`
+	Version   string `json:"version,omitempty"`
+	GoVersion string `json:"go_version,omitempty"`
+	OS        string `json:"os,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+}
+
+// TaskFailure represents captured failure of task.
+//
+// Failed tasks are kept in dead-letter queue until they are requeued,
+// so failure is stored without events and cache.
+type TaskFailure struct {
+	ID     int64 `db:"id"`
+	TaskID int64 `db:"task_id"`
+	// Error contains text of task error.
+	Error string `db:"error"`
+	// Stack contains stack trace of panic if task is panicked.
+	Stack       string `db:"stack"`
+	Environment JSON   `db:"environment"`
+	FailTime    int64  `db:"fail_time"`
+}
+
+func (o TaskFailure) ObjectID() int64 {
+	return o.ID
+}
+
+func (o *TaskFailure) SetObjectID(id int64) {
+	o.ID = id
+}
+
+func (o TaskFailure) GetEnvironment() (TaskFailureEnvironment, error) {
+	var env TaskFailureEnvironment
+	if len(o.Environment) == 0 {
+		return env, nil
+	}
+	err := json.Unmarshal(o.Environment, &env)
+	return env, err
+}
+
+func (o *TaskFailure) SetEnvironment(env TaskFailureEnvironment) error {
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	o.Environment = raw
+	return nil
+}
+
+type TaskFailureStore struct {
+	store db.ObjectStore[TaskFailure, *TaskFailure]
+}
+
+func (s *TaskFailureStore) Create(ctx context.Context, object *TaskFailure) error {
+	return s.store.CreateObject(ctx, object)
+}
+
+func (s *TaskFailureStore) Update(ctx context.Context, object TaskFailure) error {
+	return s.store.UpdateObject(ctx, &object)
+}
+
+func (s *TaskFailureStore) Delete(ctx context.Context, id int64) error {
+	return s.store.DeleteObject(ctx, id)
+}
+
+// GetByTask returns failure of specified task.
+//
+// Returns sql.ErrNoRows if failure does not exist.
+func (s *TaskFailureStore) GetByTask(ctx context.Context, taskID int64) (TaskFailure, error) {
+	return s.store.FindObject(ctx, db.FindQuery{
+		Where: gosql.Column("task_id").Equal(taskID),
+	})
+}
+
+func NewTaskFailureStore(conn *gosql.DB, table string) *TaskFailureStore {
+	impl := &TaskFailureStore{
+		store: db.NewObjectStore[TaskFailure, *TaskFailure]("id", table, conn),
+	}
+	return impl
+}
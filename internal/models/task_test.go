@@ -1,9 +1,12 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 )
 
 type taskStoreTest struct{}
@@ -118,3 +121,87 @@ func TestTaskStore(t *testing.T) {
 	tester := CachedStoreTester{&taskStoreTest{}}
 	tester.Test(t)
 }
+
+func TestTaskStore_PopQueued(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	tester := CachedStoreTester{&taskStoreTest{}}
+	tester.prepareDB(t)
+	store := NewTaskStore(testDB, "task", "task_event")
+	ctx := context.Background()
+	for i, participantID := range []int64{1, 1, 1, 2} {
+		task := Task{Status: QueuedTask}
+		if err := task.SetConfig(JudgeSolutionTaskConfig{
+			SolutionID:    int64(i + 1),
+			ParticipantID: participantID,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := store.Create(ctx, &task); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	var popped []int64
+	for {
+		task, err := store.PopQueued(ctx, time.Minute, 2, nil)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		popped = append(popped, task.ID)
+	}
+	if !reflect.DeepEqual(popped, []int64{1, 2, 4}) {
+		t.Fatalf("Expected %v, got %v", []int64{1, 2, 4}, popped)
+	}
+	task, err := store.PopQueued(ctx, time.Minute, 0, nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if task.ID != 3 {
+		t.Fatalf("Expected %d, got %d", 3, task.ID)
+	}
+}
+
+func TestTaskStore_PopQueuedBatches(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	tester := CachedStoreTester{&taskStoreTest{}}
+	tester.prepareDB(t)
+	store := NewTaskStore(testDB, "task", "task_event")
+	ctx := context.Background()
+	// Tasks of busy participant fill more than one batch.
+	participants := make([]int64, 2*popQueuedBatchSize+1)
+	for i := range participants {
+		participants[i] = 1
+	}
+	participants[len(participants)-1] = 2
+	for i, participantID := range participants {
+		task := Task{Status: QueuedTask}
+		if err := task.SetConfig(JudgeSolutionTaskConfig{
+			SolutionID:    int64(i + 1),
+			ParticipantID: participantID,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := store.Create(ctx, &task); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	var popped []int64
+	for {
+		task, err := store.PopQueued(ctx, time.Minute, 2, nil)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		popped = append(popped, task.ID)
+	}
+	expected := []int64{1, 2, int64(len(participants))}
+	if !reflect.DeepEqual(popped, expected) {
+		t.Fatalf("Expected %v, got %v", expected, popped)
+	}
+}
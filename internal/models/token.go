@@ -13,8 +13,12 @@ import (
 type TokenKind int
 
 const (
-	ConfirmEmailToken  TokenKind = 1
-	ResetPasswordToken TokenKind = 2
+	ConfirmEmailToken   TokenKind = 1
+	ResetPasswordToken  TokenKind = 2
+	ScopeUserLoginToken TokenKind = 3
+	// ContestAPIToken represents token that allows only reading of
+	// single contest.
+	ContestAPIToken TokenKind = 4
 )
 
 type TokenConfig interface {
@@ -35,6 +39,28 @@ func (c ResetPasswordTokenConfig) TokenKind() TokenKind {
 	return ResetPasswordToken
 }
 
+// ScopeUserLoginTokenConfig represents config of one-time login token
+// for scope user.
+type ScopeUserLoginTokenConfig struct {
+	// ContestID contains contest for which token is issued.
+	ContestID int64 `json:"contest_id,omitempty"`
+}
+
+func (c ScopeUserLoginTokenConfig) TokenKind() TokenKind {
+	return ScopeUserLoginToken
+}
+
+// ContestAPITokenConfig represents config of API token that is
+// restricted to single contest.
+type ContestAPITokenConfig struct {
+	ContestID int64  `json:"contest_id"`
+	Title     string `json:"title,omitempty"`
+}
+
+func (c ContestAPITokenConfig) TokenKind() TokenKind {
+	return ContestAPIToken
+}
+
 // Token represents a token.
 type Token struct {
 	baseObject
@@ -2,7 +2,6 @@ package models
 
 import (
 	"context"
-	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"strings"
@@ -45,6 +44,10 @@ type User struct {
 	FirstName    NString    `db:"first_name"`
 	LastName     NString    `db:"last_name"`
 	MiddleName   NString    `db:"middle_name"`
+	// Country contains ISO 3166-1 alpha-2 code of country.
+	Country NString `db:"country"`
+	// OrganizationID contains ID of organization.
+	OrganizationID NInt64 `db:"organization_id"`
 }
 
 // AccountKind returns UserAccount kind.
@@ -77,7 +80,7 @@ func (e *UserEvent) SetObject(o User) {
 type UserStore struct {
 	cachedStore[User, UserEvent, *User, *UserEvent]
 	byLogin *btreeIndex[string, User, *User]
-	salt    string
+	hasher  passwordHasher
 }
 
 // GetByLogin returns user by login.
@@ -89,24 +92,36 @@ func (s *UserStore) GetByLogin(ctx context.Context, login string) (User, error)
 
 // SetPassword modifies PasswordHash and PasswordSalt fields.
 //
-// PasswordSalt will be replaced with random 16 byte string
-// and PasswordHash will be calculated using password, salt
-// and global salt.
+// PasswordHash will be calculated using Argon2id with random 16 byte
+// salt and global salt. PasswordSalt will be cleared because salt is
+// stored inside PasswordHash.
 func (s *UserStore) SetPassword(user *User, password string) error {
-	saltBytes := make([]byte, 16)
-	_, err := rand.Read(saltBytes)
+	hash, err := s.hasher.hash(password)
 	if err != nil {
 		return err
 	}
-	user.PasswordSalt = encodeBase64(saltBytes)
-	user.PasswordHash = hashPassword(password, user.PasswordSalt, s.salt)
+	user.PasswordHash = hash
+	user.PasswordSalt = ""
 	return nil
 }
 
 // CheckPassword checks that passwords are the same.
+//
+// Passwords hashed with legacy salted scheme are also accepted
+// unless legacy hashes are disabled.
 func (s *UserStore) CheckPassword(user User, password string) bool {
-	passwordHash := hashPassword(password, user.PasswordSalt, s.salt)
-	return passwordHash == user.PasswordHash
+	return s.hasher.check(password, user.PasswordHash, user.PasswordSalt)
+}
+
+// NeedsRehash returns true if password of user should be hashed again
+// with current parameters.
+func (s *UserStore) NeedsRehash(user User) bool {
+	return s.hasher.needsRehash(user.PasswordHash)
+}
+
+// SetPasswordHashing sets parameters of password hashing.
+func (s *UserStore) SetPasswordHashing(hashing PasswordHashing) {
+	s.hasher.hashing = hashing
 }
 
 // NewUserStore creates new instance of user store.
@@ -118,7 +133,7 @@ func NewUserStore(
 			func(o User) (string, bool) { return strings.ToLower(o.Login), true },
 			lessString,
 		),
-		salt: salt,
+		hasher: passwordHasher{salt: salt, hashing: DefaultPasswordHashing},
 	}
 	impl.cachedStore = makeCachedManualStore[User, UserEvent](
 		db, table, eventTable, impl, impl.byLogin,
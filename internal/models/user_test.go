@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
 )
 
@@ -20,7 +21,9 @@ func (t *userStoreTest) prepareDB(tx *sql.Tx) error {
 			`"email" varchar(255),` +
 			`"first_name" varchar(255),` +
 			`"last_name" varchar(255),` +
-			`"middle_name" varchar(255))`,
+			`"middle_name" varchar(255),` +
+			`"country" varchar(2),` +
+			`"organization_id" integer)`,
 	); err != nil {
 		return err
 	}
@@ -38,7 +41,9 @@ func (t *userStoreTest) prepareDB(tx *sql.Tx) error {
 			`"email" varchar(255),` +
 			`"first_name" varchar(255),` +
 			`"last_name" varchar(255),` +
-			`"middle_name" varchar(255))`,
+			`"middle_name" varchar(255),` +
+			`"country" varchar(2),` +
+			`"organization_id" integer)`,
 	)
 	return err
 }
@@ -83,3 +88,63 @@ func TestUserStore(t *testing.T) {
 	tester := CachedStoreTester{&userStoreTest{}}
 	tester.Test(t)
 }
+
+func TestUserStorePassword(t *testing.T) {
+	store := NewUserStore(testDB, "user", "user_event", "global")
+	store.SetPasswordHashing(PasswordHashing{
+		Time: 1, Memory: 64, Threads: 1, KeyLength: 16,
+	})
+	legacySalt := encodeBase64([]byte("legacy"))
+	user := User{
+		PasswordSalt: legacySalt,
+		PasswordHash: hashPassword("qwerty123", legacySalt, "global"),
+	}
+	if !store.CheckPassword(user, "qwerty123") {
+		t.Fatal("Legacy password should be accepted")
+	}
+	if store.CheckPassword(user, "qwerty124") {
+		t.Fatal("Invalid password should not be accepted")
+	}
+	if !store.NeedsRehash(user) {
+		t.Fatal("Legacy password should be rehashed")
+	}
+	if err := store.SetPassword(&user, "qwerty123"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !strings.HasPrefix(user.PasswordHash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("Unexpected hash: %q", user.PasswordHash)
+	}
+	if user.PasswordSalt != "" {
+		t.Fatalf("Unexpected salt: %q", user.PasswordSalt)
+	}
+	if !store.CheckPassword(user, "qwerty123") {
+		t.Fatal("Password should be accepted")
+	}
+	if store.CheckPassword(user, "qwerty124") {
+		t.Fatal("Invalid password should not be accepted")
+	}
+	if store.NeedsRehash(user) {
+		t.Fatal("Password should not be rehashed")
+	}
+	other := NewUserStore(testDB, "user", "user_event", "other")
+	if other.CheckPassword(user, "qwerty123") {
+		t.Fatal("Password with different global salt should not be accepted")
+	}
+	store.SetPasswordHashing(PasswordHashing{
+		Time: 2, Memory: 64, Threads: 1, KeyLength: 16,
+	})
+	if !store.CheckPassword(user, "qwerty123") {
+		t.Fatal("Password should be accepted")
+	}
+	if !store.NeedsRehash(user) {
+		t.Fatal("Password with outdated parameters should be rehashed")
+	}
+	store.SetPasswordHashing(PasswordHashing{
+		Time: 1, Memory: 64, Threads: 1, KeyLength: 16, DisableLegacy: true,
+	})
+	user.PasswordSalt = legacySalt
+	user.PasswordHash = hashPassword("qwerty123", legacySalt, "global")
+	if store.CheckPassword(user, "qwerty123") {
+		t.Fatal("Legacy password should not be accepted")
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+// versionIndex tracks version of objects with the same key.
+//
+// Version of key is increased on every change of objects with this key,
+// so it can be used for invalidation of derived caches.
+type versionIndex[K comparable, T any] struct {
+	key      func(T) (K, bool)
+	last     int64
+	reset    int64
+	versions map[K]int64
+}
+
+func newVersionIndex[K comparable, T any](key func(T) (K, bool)) *versionIndex[K, T] {
+	return &versionIndex[K, T]{key: key}
+}
+
+// Get returns current version of key.
+func (i *versionIndex[K, T]) Get(key K) int64 {
+	if version, ok := i.versions[key]; ok {
+		return version
+	}
+	return i.reset
+}
+
+// Reset invalidates versions of all keys.
+func (i *versionIndex[K, T]) Reset() {
+	i.last++
+	i.reset = i.last
+	i.versions = map[K]int64{}
+}
+
+func (i *versionIndex[K, T]) Register(object T) {
+	i.update(object)
+}
+
+func (i *versionIndex[K, T]) Deregister(object T) {
+	i.update(object)
+}
+
+func (i *versionIndex[K, T]) update(object T) {
+	key, ok := i.key(object)
+	if !ok {
+		return
+	}
+	i.last++
+	i.versions[key] = i.last
+}
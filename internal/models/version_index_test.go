@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestVersionIndex(t *testing.T) {
+	index := newVersionIndex(func(o testObject) (int, bool) {
+		return o.Int, o.Int != 0
+	})
+	index.Reset()
+	initial := index.Get(1)
+	if index.Get(2) != initial {
+		t.Fatal("Versions of untouched keys should be equal")
+	}
+	index.Register(testObject{ID: 1, testObjectBase: testObjectBase{Int: 1}})
+	version := index.Get(1)
+	if version == initial {
+		t.Fatal("Version should be changed after register")
+	}
+	if index.Get(2) != initial {
+		t.Fatal("Version of other key should not be changed")
+	}
+	index.Register(testObject{ID: 2, testObjectBase: testObjectBase{Int: 0}})
+	if index.Get(0) != initial {
+		t.Fatal("Version of skipped object should not be changed")
+	}
+	index.Deregister(testObject{ID: 1, testObjectBase: testObjectBase{Int: 1}})
+	if index.Get(1) == version {
+		t.Fatal("Version should be changed after deregister")
+	}
+	index.Reset()
+	if index.Get(1) == initial || index.Get(2) == initial {
+		t.Fatal("Versions should be changed after reset")
+	}
+}
@@ -39,6 +39,12 @@ const (
 	CreateUserRoleRole = "create_user_role"
 	// DeleteUserRoleRole represents name of role for detaching role from user.
 	DeleteUserRoleRole = "delete_user_role"
+	// ObserveGroupRolesRole represents name of role for observing group roles.
+	ObserveGroupRolesRole = "observe_group_roles"
+	// CreateGroupRoleRole represents name of role for attaching role to group.
+	CreateGroupRoleRole = "create_group_role"
+	// DeleteGroupRoleRole represents name of role for detaching role from group.
+	DeleteGroupRoleRole = "delete_group_role"
 	// ObserveUserRole represents name of role for observing user.
 	ObserveUserRole = "observe_user"
 	// UpdateUserRole represents name of role for updating user.
@@ -94,6 +100,15 @@ const (
 	UpdateProblemOwnerRole = "update_problem_owner"
 	// DeleteProblemRole represents role for deleting problem.
 	DeleteProblemRole = "delete_problem"
+	// ObserveProblemGrantsRole represents role for observing
+	// problem grants.
+	ObserveProblemGrantsRole = "observe_problem_grants"
+	// CreateProblemGrantRole represents role for creating
+	// problem grant.
+	CreateProblemGrantRole = "create_problem_grant"
+	// DeleteProblemGrantRole represents role for deleting
+	// problem grant.
+	DeleteProblemGrantRole = "delete_problem_grant"
 	// ObserveCompilersRole represents role for observing compiler list.
 	ObserveCompilersRole = "observe_compilers"
 	// ObserveCompilerRole represents role for observing compiler.
@@ -190,6 +205,54 @@ const (
 	// SubmitContestQuestionRole represents role for submitting
 	// contest question.
 	SubmitContestQuestionRole = "submit_contest_question"
+	// ObserveContestFeedbackRole represents role for observing
+	// contest feedback summary.
+	ObserveContestFeedbackRole = "observe_contest_feedback"
+	// SubmitContestFeedbackRole represents role for submitting
+	// contest feedback.
+	SubmitContestFeedbackRole = "submit_contest_feedback"
+	// FinalizeContestRole represents role for finalizing and
+	// unfinalizing contest results.
+	FinalizeContestRole = "finalize_contest"
+	// ObserveContestGrantsRole represents role for observing
+	// contest grants.
+	ObserveContestGrantsRole = "observe_contest_grants"
+	// CreateContestGrantRole represents role for creating
+	// contest grant.
+	CreateContestGrantRole = "create_contest_grant"
+	// DeleteContestGrantRole represents role for deleting
+	// contest grant.
+	DeleteContestGrantRole = "delete_contest_grant"
+	// ObserveContestAppealsRole represents role for observing
+	// all contest appeals.
+	ObserveContestAppealsRole = "observe_contest_appeals"
+	// CreateContestAppealRole represents role for filing
+	// contest appeal.
+	CreateContestAppealRole = "create_contest_appeal"
+	// ResolveContestAppealRole represents role for discussing and
+	// resolving contest appeals.
+	ResolveContestAppealRole = "resolve_contest_appeal"
+	// ObserveContestScoreOverridesRole represents role for observing
+	// manual overrides of contest standings.
+	ObserveContestScoreOverridesRole = "observe_contest_score_overrides"
+	// CreateContestScoreOverrideRole represents role for creating
+	// manual override of contest standings cell.
+	CreateContestScoreOverrideRole = "create_contest_score_override"
+	// DeleteContestScoreOverrideRole represents role for deleting
+	// manual override of contest standings cell.
+	DeleteContestScoreOverrideRole = "delete_contest_score_override"
+	// ObserveContestDashboardRole represents role for observing
+	// jury dashboard of contest.
+	ObserveContestDashboardRole = "observe_contest_dashboard"
+	// ExportContestSolutionsRole represents role for exporting
+	// all solutions of contest.
+	ExportContestSolutionsRole = "export_contest_solutions"
+	// ObserveContestEditorialsRole represents role for observing
+	// published contest editorials.
+	ObserveContestEditorialsRole = "observe_contest_editorials"
+	// ManageContestEditorialsRole represents role for attaching,
+	// publishing and deleting contest editorials.
+	ManageContestEditorialsRole = "manage_contest_editorials"
 	// CreateContestRole represents role for creating contest.
 	CreateContestRole = "create_contest"
 	// UpdateContestRole represents role for updating contest.
@@ -204,6 +267,8 @@ const (
 	RegisterContestRole = "register_contest"
 	// VirtualRegisterContest represents role for virtual register to contest.
 	RegisterContestVirtualRole = "register_contest_virtual"
+	// RegisterContestObserverRole represents role for observer register to contest.
+	RegisterContestObserverRole = "register_contest_observer"
 	// DeregisterContestRole represents role for deregister from contest.
 	DeregisterContestRole = "deregister_contest"
 	// ObserveFileContentRole represents role for observing file content.
@@ -232,6 +297,8 @@ const (
 	ConsumeTokenRole = "consume_token"
 	// ObserveAccountsRole represents role for observing accounts.
 	ObserveAccountsRole = "observe_accounts"
+	// MergeAccountsRole represents role for merging accounts.
+	MergeAccountsRole = "merge_accounts"
 	// ObserveGroupsRole represents role for observing groups.
 	ObserveGroupsRole = "observe_groups"
 	// ObserveGroupRole represents role for observing group.
@@ -264,6 +331,28 @@ const (
 	UpdatePostOwnerRole = "update_post_owner"
 	// DeletePostRole represents role for deleting post.
 	DeletePostRole = "delete_post"
+	// ObserveFailedTasksRole represents role for observing failed tasks.
+	ObserveFailedTasksRole = "observe_failed_tasks"
+	// RequeueFailedTasksRole represents role for requeueing failed tasks.
+	RequeueFailedTasksRole = "requeue_failed_tasks"
+	// ObserveOrganizationsRole represents role for observing
+	// organization list.
+	ObserveOrganizationsRole = "observe_organizations"
+	// CreateOrganizationRole represents role for creating organization.
+	CreateOrganizationRole = "create_organization"
+	// UpdateOrganizationRole represents role for updating organization.
+	UpdateOrganizationRole = "update_organization"
+	// DeleteOrganizationRole represents role for deleting organization.
+	DeleteOrganizationRole = "delete_organization"
+	// UpdateUserCountryRole represents name of role for updating
+	// user country.
+	UpdateUserCountryRole = "update_user_country"
+	// UpdateUserOrganizationRole represents name of role for updating
+	// user organization.
+	UpdateUserOrganizationRole = "update_user_organization"
+	// CreateMashupContestRole represents role for creating contest
+	// from archive problems.
+	CreateMashupContestRole = "create_mashup_contest"
 )
 
 var builtInRoles = map[string]struct{}{
@@ -284,6 +373,9 @@ var builtInRoles = map[string]struct{}{
 	ObserveUserRolesRole:             {},
 	CreateUserRoleRole:               {},
 	DeleteUserRoleRole:               {},
+	ObserveGroupRolesRole:            {},
+	CreateGroupRoleRole:              {},
+	DeleteGroupRoleRole:              {},
 	ObserveUserRole:                  {},
 	UpdateUserRole:                   {},
 	ObserveUserEmailRole:             {},
@@ -306,6 +398,9 @@ var builtInRoles = map[string]struct{}{
 	UpdateProblemRole:                {},
 	UpdateProblemOwnerRole:           {},
 	DeleteProblemRole:                {},
+	ObserveProblemGrantsRole:         {},
+	CreateProblemGrantRole:           {},
+	DeleteProblemGrantRole:           {},
 	ObserveCompilersRole:             {},
 	ObserveCompilerRole:              {},
 	CreateCompilerRole:               {},
@@ -340,6 +435,22 @@ var builtInRoles = map[string]struct{}{
 	UpdateContestMessageRole:         {},
 	DeleteContestMessageRole:         {},
 	SubmitContestQuestionRole:        {},
+	ObserveContestFeedbackRole:       {},
+	SubmitContestFeedbackRole:        {},
+	FinalizeContestRole:              {},
+	ObserveContestGrantsRole:         {},
+	CreateContestGrantRole:           {},
+	DeleteContestGrantRole:           {},
+	ObserveContestAppealsRole:        {},
+	CreateContestAppealRole:          {},
+	ResolveContestAppealRole:         {},
+	ObserveContestScoreOverridesRole: {},
+	CreateContestScoreOverrideRole:   {},
+	DeleteContestScoreOverrideRole:   {},
+	ObserveContestDashboardRole:      {},
+	ExportContestSolutionsRole:       {},
+	ObserveContestEditorialsRole:     {},
+	ManageContestEditorialsRole:      {},
 	ObserveContestsRole:              {},
 	CreateContestRole:                {},
 	UpdateContestRole:                {},
@@ -349,6 +460,7 @@ var builtInRoles = map[string]struct{}{
 	RegisterContestsRole:             {},
 	RegisterContestRole:              {},
 	RegisterContestVirtualRole:       {},
+	RegisterContestObserverRole:      {},
 	DeregisterContestRole:            {},
 	ObserveFileContentRole:           {},
 	ObserveScopesRole:                {},
@@ -363,6 +475,7 @@ var builtInRoles = map[string]struct{}{
 	DeleteScopeUserRole:              {},
 	ConsumeTokenRole:                 {},
 	ObserveAccountsRole:              {},
+	MergeAccountsRole:                {},
 	ObserveGroupsRole:                {},
 	ObserveGroupRole:                 {},
 	CreateGroupRole:                  {},
@@ -379,6 +492,15 @@ var builtInRoles = map[string]struct{}{
 	UpdatePostRole:                   {},
 	UpdatePostOwnerRole:              {},
 	DeletePostRole:                   {},
+	ObserveFailedTasksRole:           {},
+	RequeueFailedTasksRole:           {},
+	ObserveOrganizationsRole:         {},
+	CreateOrganizationRole:           {},
+	UpdateOrganizationRole:           {},
+	DeleteOrganizationRole:           {},
+	UpdateUserCountryRole:            {},
+	UpdateUserOrganizationRole:       {},
+	CreateMashupContestRole:          {},
 }
 
 // GetBuildInRoles returns all built-in roles.
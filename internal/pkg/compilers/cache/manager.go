@@ -3,7 +3,6 @@ package cache
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -80,11 +79,6 @@ func (m *CompilerImageManager) LoadSync(
 func (m *CompilerImageManager) load(
 	ctx context.Context, fileID int64,
 ) (cache.Resource[CompilerImage], error) {
-	file, err := m.files.DownloadFile(ctx, fileID)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = file.Close() }()
 	img, err := m.newImage()
 	if err != nil {
 		return nil, err
@@ -103,23 +97,9 @@ func (m *CompilerImageManager) load(
 	if err := os.RemoveAll(tempPath); err != nil {
 		return nil, err
 	}
-	if v, ok := file.(*os.File); ok {
-		tempPath = v.Name()
-	} else {
-		tempFile, err := os.Create(tempPath)
-		if err != nil {
-			return nil, err
-		}
-		defer func() {
-			_ = tempFile.Close()
-			_ = os.RemoveAll(tempPath)
-		}()
-		if _, err := io.Copy(tempFile, file); err != nil {
-			return nil, err
-		}
-		if err := tempFile.Close(); err != nil {
-			return nil, err
-		}
+	defer func() { _ = os.RemoveAll(tempPath) }()
+	if err := m.files.DownloadVerifiedFile(ctx, fileID, tempPath); err != nil {
+		return nil, err
 	}
 	if err := archives.ExtractTarGz(tempPath, targetPath); err != nil {
 		return nil, fmt.Errorf("cannot extract image: %w", err)
@@ -0,0 +1,220 @@
+// Package diff implements line based diff of text files.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxEditDistance contains maximal amount of edits that are searched
+// for minimal diff, otherwise texts are considered as fully different.
+const maxEditDistance = 2000
+
+// Lines splits text into lines without line endings.
+func Lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+type editKind int
+
+const (
+	equalEdit editKind = iota
+	deleteEdit
+	insertEdit
+)
+
+type edit struct {
+	Kind editKind
+	// Old contains index of line in old text.
+	Old int
+	// New contains index of line in new text.
+	New int
+}
+
+// Unified returns unified diff of two texts with specified amount
+// of context lines around changes.
+//
+// Empty string is returned when texts are equal.
+func Unified(oldName, newName string, oldLines, newLines []string, context int) string {
+	edits := computeEdits(oldLines, newLines)
+	var result strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].Kind == equalEdit {
+			i++
+			continue
+		}
+		begin := max(i-context, 0)
+		end := i
+		// Merge changes that have small amount of equal lines between.
+		for j := i; j < len(edits); j++ {
+			if edits[j].Kind != equalEdit {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(end+context, len(edits))
+		if result.Len() == 0 {
+			fmt.Fprintf(&result, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		writeHunk(&result, oldLines, newLines, edits[begin:end])
+		i = end
+	}
+	return result.String()
+}
+
+func writeHunk(w *strings.Builder, oldLines, newLines []string, edits []edit) {
+	oldBegin, newBegin := edits[0].Old, edits[0].New
+	oldCount, newCount := 0, 0
+	for _, e := range edits {
+		switch e.Kind {
+		case equalEdit:
+			oldCount++
+			newCount++
+		case deleteEdit:
+			oldCount++
+		case insertEdit:
+			newCount++
+		}
+	}
+	fmt.Fprintf(
+		w, "@@ -%s +%s @@\n",
+		formatRange(oldBegin, oldCount), formatRange(newBegin, newCount),
+	)
+	for _, e := range edits {
+		switch e.Kind {
+		case equalEdit:
+			fmt.Fprintf(w, " %s\n", oldLines[e.Old])
+		case deleteEdit:
+			fmt.Fprintf(w, "-%s\n", oldLines[e.Old])
+		case insertEdit:
+			fmt.Fprintf(w, "+%s\n", newLines[e.New])
+		}
+	}
+}
+
+func formatRange(begin, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", begin)
+	}
+	if count == 1 {
+		return fmt.Sprint(begin + 1)
+	}
+	return fmt.Sprintf("%d,%d", begin+1, count)
+}
+
+// computeEdits returns shortest edit script using Myers algorithm.
+func computeEdits(a, b []string) []edit {
+	n, m := len(a), len(b)
+	// Common prefix and suffix do not affect edit script.
+	prefix := 0
+	for prefix < n && prefix < m && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix &&
+		a[n-suffix-1] == b[m-suffix-1] {
+		suffix++
+	}
+	var edits []edit
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{Kind: equalEdit, Old: i, New: i})
+	}
+	edits = append(edits, computeMiddleEdits(
+		a[prefix:n-suffix], b[prefix:m-suffix], prefix,
+	)...)
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, edit{
+			Kind: equalEdit, Old: n - suffix + i, New: m - suffix + i,
+		})
+	}
+	return edits
+}
+
+func computeMiddleEdits(a, b []string, offset int) []edit {
+	n, m := len(a), len(b)
+	trace, ok := searchPath(a, b)
+	if !ok {
+		edits := make([]edit, 0, n+m)
+		for i := 0; i < n; i++ {
+			edits = append(edits, edit{Kind: deleteEdit, Old: offset + i, New: offset})
+		}
+		for i := 0; i < m; i++ {
+			edits = append(edits, edit{Kind: insertEdit, Old: offset + n, New: offset + i})
+		}
+		return edits
+	}
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		get := func(k int) int { return v[k+d] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			edits = append(edits, edit{Kind: equalEdit, Old: offset + x, New: offset + y})
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{Kind: insertEdit, Old: offset + x, New: offset + y})
+		} else {
+			x--
+			edits = append(edits, edit{Kind: deleteEdit, Old: offset + x, New: offset + y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x, y = x-1, y-1
+		edits = append(edits, edit{Kind: equalEdit, Old: offset + x, New: offset + y})
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// searchPath returns snapshots of furthest reaching paths for every
+// edit distance, where snapshot for distance d contains paths for
+// diagonals from -d to d.
+func searchPath(a, b []string) ([][]int, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEditDistance)
+	offset := limit + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return trace, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		Old, New string
+		Diff     string
+	}{
+		{"a\nb\nc\n", "a\nb\nc\n", ""},
+		{"", "a\n", "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n"},
+		{"a\n", "", "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n"},
+		{
+			"a\nb\nc\nd\n", "a\nx\nc\nd\ny\n",
+			"--- old\n+++ new\n@@ -1,4 +1,5 @@\n a\n-b\n+x\n c\n d\n+y\n",
+		},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n", "0\n2\n3\n4\n5\n6\n7\n8\n0\n",
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n-1\n+0\n 2\n@@ -8,2 +8,2 @@\n 8\n-9\n+0\n",
+		},
+		{"a\r\nb\r\n", "a\nb\n", ""},
+	}
+	for _, test := range tests {
+		diff := Unified("old", "new", Lines(test.Old), Lines(test.New), 1)
+		if diff != test.Diff {
+			t.Fatalf("Expected %q, got %q", test.Diff, diff)
+		}
+	}
+}
+
+func TestUnifiedLarge(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 3*maxEditDistance; i++ {
+		a = append(a, "a")
+		b = append(b, "b")
+	}
+	diff := Unified("old", "new", a, b, 3)
+	if len(diff) == 0 {
+		t.Fatal("Expected non-empty diff")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		Source, Language string
+		Result           string
+	}{
+		{
+			"int main() {\n\t// comment\n\n  return  0; /* end */\n}\n", "C++",
+			"int main() {\nreturn 0;\n}\n",
+		},
+		{"puts(\"// not comment\");\n", "C++", "puts(\"// not comment\");\n"},
+		{"print(1)  # comment\n", "Python 3", "print(1)\n"},
+		{"print(1)  # comment\n", "Unknown", "print(1) # comment\n"},
+	}
+	for _, test := range tests {
+		result := Normalize(test.Source, test.Language)
+		if result != test.Result {
+			t.Fatalf("Expected %q, got %q", test.Result, result)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	source := "#include <cstdio>\nint main() {\n\tint a, b;\n\tscanf(\"%d%d\", &a, &b);\n\tprintf(\"%d\\n\", a + b);\n}\n"
+	formatted := "#include <cstdio>\n// solution\nint main()\n{\n  int a, b;\n  scanf(\"%d%d\", &a, &b);\n  printf(\"%d\\n\", a + b);\n}\n"
+	other := "n = int(input())\nprint(sum(range(n)))\n"
+	if v := Similarity(Fingerprint(source, "C++"), Fingerprint(formatted, "C++")); v < 0.9 {
+		t.Fatalf("Expected similar sources, got %f", v)
+	}
+	if v := Similarity(Fingerprint(source, "C++"), Fingerprint(other, "Python 3")); v > 0.1 {
+		t.Fatalf("Expected different sources, got %f", v)
+	}
+	if v := Similarity(nil, Fingerprint(source, "C++")); v != 0 {
+		t.Fatalf("Expected zero similarity, got %f", v)
+	}
+}
@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"hash/fnv"
+	"sort"
+	"unicode"
+)
+
+const (
+	// fingerprintGram contains amount of tokens in hashed sequence.
+	fingerprintGram = 5
+	// fingerprintWindow contains size of winnowing window.
+	fingerprintWindow = 4
+)
+
+// Fingerprint returns sorted set of hashes that represents normalized
+// source code.
+//
+// Fingerprint is built using winnowing of token k-grams, so it is
+// tolerant to formatting changes, comments and local edits.
+func Fingerprint(source, language string) []uint64 {
+	tokens := tokenize(Normalize(source, language))
+	if len(tokens) < fingerprintGram {
+		if len(tokens) == 0 {
+			return nil
+		}
+		return []uint64{hashTokens(tokens)}
+	}
+	hashes := make([]uint64, 0, len(tokens)-fingerprintGram+1)
+	for i := 0; i+fingerprintGram <= len(tokens); i++ {
+		hashes = append(hashes, hashTokens(tokens[i:i+fingerprintGram]))
+	}
+	selected := map[uint64]struct{}{}
+	for i := 0; i < len(hashes); i++ {
+		end := min(i+fingerprintWindow, len(hashes))
+		best := hashes[i]
+		for _, hash := range hashes[i:end] {
+			best = min(best, hash)
+		}
+		selected[best] = struct{}{}
+		if end == len(hashes) {
+			break
+		}
+	}
+	result := make([]uint64, 0, len(selected))
+	for hash := range selected {
+		result = append(result, hash)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// Similarity returns Jaccard index of two fingerprints.
+func Similarity(lhs, rhs []uint64) float64 {
+	if len(lhs) == 0 || len(rhs) == 0 {
+		return 0
+	}
+	common := 0
+	for i, j := 0, 0; i < len(lhs) && j < len(rhs); {
+		switch {
+		case lhs[i] < rhs[j]:
+			i++
+		case lhs[i] > rhs[j]:
+			j++
+		default:
+			common++
+			i++
+			j++
+		}
+	}
+	return float64(common) / float64(len(lhs)+len(rhs)-common)
+}
+
+func tokenize(source string) []string {
+	var tokens []string
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) ||
+				unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens
+}
+
+func hashTokens(tokens []string) uint64 {
+	hash := fnv.New64a()
+	for _, token := range tokens {
+		_, _ = hash.Write([]byte(token))
+		_, _ = hash.Write([]byte{0})
+	}
+	return hash.Sum64()
+}
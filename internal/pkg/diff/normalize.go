@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"strings"
+)
+
+type commentSyntax struct {
+	Line       string
+	BlockBegin string
+	BlockEnd   string
+}
+
+var (
+	cSyntax      = commentSyntax{Line: "//", BlockBegin: "/*", BlockEnd: "*/"}
+	hashSyntax   = commentSyntax{Line: "#"}
+	pascalSyntax = commentSyntax{Line: "//", BlockBegin: "{", BlockEnd: "}"}
+)
+
+var languageSyntax = map[string]commentSyntax{
+	"c":          cSyntax,
+	"c++":        cSyntax,
+	"c#":         cSyntax,
+	"d":          cSyntax,
+	"go":         cSyntax,
+	"java":       cSyntax,
+	"javascript": cSyntax,
+	"kotlin":     cSyntax,
+	"rust":       cSyntax,
+	"scala":      cSyntax,
+	"swift":      cSyntax,
+	"typescript": cSyntax,
+	"bash":       hashSyntax,
+	"perl":       hashSyntax,
+	"python":     hashSyntax,
+	"ruby":       hashSyntax,
+	"pascal":     pascalSyntax,
+}
+
+func getCommentSyntax(language string) (commentSyntax, bool) {
+	fields := strings.Fields(strings.ToLower(language))
+	if len(fields) == 0 {
+		return commentSyntax{}, false
+	}
+	syntax, ok := languageSyntax[fields[0]]
+	return syntax, ok
+}
+
+// Normalize removes differences in source code that do not affect
+// program: comments, indentation, whitespaces and empty lines.
+//
+// Comments are removed only for known languages.
+func Normalize(source, language string) string {
+	if syntax, ok := getCommentSyntax(language); ok {
+		source = removeComments(source, syntax)
+	}
+	var result strings.Builder
+	for _, line := range Lines(source) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		result.WriteString(strings.Join(fields, " "))
+		result.WriteByte('\n')
+	}
+	return result.String()
+}
+
+func removeComments(source string, syntax commentSyntax) string {
+	var result strings.Builder
+	for i := 0; i < len(source); {
+		switch c := source[i]; {
+		case c == '"' || c == '\'':
+			// String literals are copied as is.
+			j := i + 1
+			for j < len(source) && source[j] != c && source[j] != '\n' {
+				if source[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(source))
+			result.WriteString(source[i:j])
+			i = j
+		case syntax.Line != "" && strings.HasPrefix(source[i:], syntax.Line):
+			j := strings.IndexByte(source[i:], '\n')
+			if j < 0 {
+				return result.String()
+			}
+			i += j
+		case syntax.BlockBegin != "" && strings.HasPrefix(source[i:], syntax.BlockBegin):
+			j := strings.Index(source[i+len(syntax.BlockBegin):], syntax.BlockEnd)
+			if j < 0 {
+				return result.String()
+			}
+			// Block comment separates tokens.
+			result.WriteByte(' ')
+			i += len(syntax.BlockBegin) + j + len(syntax.BlockEnd)
+		default:
+			result.WriteByte(c)
+			i++
+		}
+	}
+	return result.String()
+}
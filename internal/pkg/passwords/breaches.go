@@ -0,0 +1,58 @@
+package passwords
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultBreachesURL contains URL of Pwned Passwords API.
+const DefaultBreachesURL = "https://api.pwnedpasswords.com"
+
+// CountBreaches returns amount of password occurrences in breaches.
+//
+// Only first 5 characters of SHA-1 hash of password are sent to API,
+// so password itself is never disclosed (k-anonymity model).
+func CountBreaches(
+	ctx context.Context, client *http.Client, baseURL, password string,
+) (int64, error) {
+	hash := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(hash[:]))
+	prefix, suffix := digest[:5], digest[5:]
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		strings.TrimSuffix(baseURL, "/")+"/range/"+prefix, nil,
+	)
+	if err != nil {
+		return 0, err
+	}
+	// Padding hides real amount of matched suffixes.
+	req.Header.Set("Add-Padding", "true")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineSuffix, count, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(lineSuffix, suffix) {
+			continue
+		}
+		value, err := strconv.ParseInt(count, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid count: %w", err)
+		}
+		return value, nil
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,53 @@
+package passwords
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	for _, test := range []struct {
+		Password string
+		Inputs   []string
+		Score    int
+	}{
+		{"123456", nil, 0},
+		{"password", nil, 0},
+		{"P@ssw0rd", nil, 0},
+		{"aaaaaa", nil, 0},
+		{"abcdefgh", nil, 1},
+		{"johnsmith1", []string{"johnsmith"}, 0},
+		{"correct horse battery", nil, 4},
+		{"Tr0ub4dour&3x", nil, 4},
+	} {
+		if score := Score(test.Password, test.Inputs...); score != test.Score {
+			t.Errorf("Expected score %d for %q, got %d", test.Score, test.Password, score)
+		}
+	}
+}
+
+func TestCountBreaches(t *testing.T) {
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/range/5BAA6" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "003D68EB55068C33ACE09247EE4C639306B:3\r\n")
+		fmt.Fprint(w, "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n")
+	}))
+	defer server.Close()
+	count, err := CountBreaches(context.Background(), server.Client(), server.URL, "password")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if count != 9545824 {
+		t.Fatalf("Expected %d, got %d", 9545824, count)
+	}
+	if _, err := CountBreaches(context.Background(), server.Client(), server.URL+"/invalid", "password"); err == nil {
+		t.Fatal("Expected error")
+	}
+}
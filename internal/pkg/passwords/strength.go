@@ -0,0 +1,127 @@
+// Package passwords implements checks of password strength.
+package passwords
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords contains most popular passwords and their parts.
+var commonPasswords = []string{
+	"password", "qwerty", "123456", "12345678", "111111", "abc123",
+	"letmein", "welcome", "monkey", "dragon", "master", "login",
+	"admin", "iloveyou", "sunshine", "princess", "football", "baseball",
+	"shadow", "superman", "trustno1", "passw0rd", "qwertyuiop",
+	"asdfgh", "zxcvbn", "1q2w3e", "solve",
+}
+
+// leetReplacer replaces common character substitutions.
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+)
+
+// Score returns strength score of password from 0 to 4.
+//
+// Score is based on estimated amount of guesses required for password
+// in the same way as in zxcvbn: 0 means too guessable and 4 means very
+// unguessable. Inputs contain user specific words like login or email
+// that are treated as dictionary words.
+func Score(password string, inputs ...string) int {
+	guesses := GuessesLog10(password, inputs...)
+	switch {
+	case guesses < 3:
+		return 0
+	case guesses < 6:
+		return 1
+	case guesses < 8:
+		return 2
+	case guesses < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// GuessesLog10 returns estimated log10 of amount of guesses for password.
+func GuessesLog10(password string, inputs ...string) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+	lower := strings.ToLower(password)
+	words := commonPasswords
+	for _, input := range inputs {
+		if input = strings.ToLower(input); len(input) >= 3 {
+			words = append(words, input)
+		}
+	}
+	// Dictionary words are replaced by single placeholder character.
+	dictionaryWords := 0
+	for _, variant := range []string{lower, leetReplacer.Replace(lower)} {
+		count := 0
+		for _, word := range words {
+			if strings.Contains(variant, word) {
+				variant = strings.ReplaceAll(variant, word, "\x00")
+				count++
+			}
+		}
+		if count > dictionaryWords {
+			dictionaryWords = count
+			runes = []rune(variant)
+		}
+	}
+	length := 0.0
+	var prev rune
+	for i, c := range runes {
+		switch {
+		case c == 0:
+			// Placeholder is counted separately.
+		case i > 0 && (c == prev || c == prev+1 || c == prev-1):
+			// Repeats and sequences are easily guessable.
+			length += 0.2
+		default:
+			length++
+		}
+		prev = c
+	}
+	bits := length*math.Log2(float64(getCharsetSize(runes))) +
+		float64(dictionaryWords)*math.Log2(float64(len(words)))
+	return bits * math.Log10(2)
+}
+
+func getCharsetSize(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, c := range runes {
+		switch {
+		case c == 0:
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digit = true
+		case c < unicode.MaxASCII && unicode.IsPrint(c):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	size := 1
+	if lower {
+		size += 26
+	}
+	if upper {
+		size += 26
+	}
+	if digit {
+		size += 10
+	}
+	if symbol {
+		size += 33
+	}
+	if other {
+		size += 100
+	}
+	return size
+}
@@ -66,6 +66,11 @@ type Interactor struct {
 	Binary *Resource `xml:"binary"`
 }
 
+type Validator struct {
+	Source *Resource `xml:"source"`
+	Binary *Resource `xml:"binary"`
+}
+
 type Solution struct {
 	Tag    string    `xml:"tag,attr"`
 	Source *Resource `xml:"source"`
@@ -75,6 +80,7 @@ type Solution struct {
 type ProblemAssets struct {
 	Checker    *Checker    `xml:"checker"`
 	Interactor *Interactor `xml:"interactor"`
+	Validators []Validator `xml:"validators>validator"`
 	Solutions  []Solution  `xml:"solutions>solution"`
 }
 
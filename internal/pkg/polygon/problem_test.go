@@ -22,7 +22,9 @@ func TestProblem(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	_ = problem
+	if problem.Assets == nil || len(problem.Assets.Validators) != 1 {
+		t.Fatal("Expected validator")
+	}
 	properties, err := ReadProblemStatementConfig(filepath.Join(
 		dir, "statements", "english", "problem-properties.json",
 	))
@@ -21,11 +21,13 @@ type problemTestConfig struct {
 	Answer string  `json:"answer"`
 	Points float64 `json:"points,omitempty"`
 	Group  string  `json:"group,omitempty"`
+	Sample bool    `json:"sample,omitempty"`
 }
 
 type problemTestGroupConfig struct {
 	Name         string `json:"name"`
 	PointsPolicy string `json:"points_policy"`
+	Pretests     bool   `json:"pretests,omitempty"`
 }
 
 type problemTestSetConfig struct {
@@ -151,6 +153,7 @@ func BuildCompiledProblem(ctx context.Context, compilers problems.CompileContext
 				Answer: testName + ".ans",
 				Points: test.Points(),
 				Group:  test.Group(),
+				Sample: test.Sample(),
 			}
 			if err := func() error {
 				inputFile, err := test.OpenInput()
@@ -196,6 +199,7 @@ func BuildCompiledProblem(ctx context.Context, compilers problems.CompileContext
 			testSetConfig.Groups = append(testSetConfig.Groups, problemTestGroupConfig{
 				Name:         group.Name(),
 				PointsPolicy: string(group.PointsPolicy()),
+				Pretests:     group.Pretests(),
 			})
 		}
 		config.TestSets = append(config.TestSets, testSetConfig)
@@ -324,6 +328,7 @@ func (g *compiledProblemTestSet) GetGroups() ([]problems.ProblemTestGroup, error
 		groups = append(groups, problemTestGroup{
 			name:         group.Name,
 			pointsPolicy: problems.ProblemPointsPolicy(group.PointsPolicy),
+			pretests:     group.Pretests,
 		})
 	}
 	return groups, nil
@@ -337,6 +342,7 @@ func (g *compiledProblemTestSet) GetTests() ([]problems.ProblemTest, error) {
 			answerPath: filepath.Join(g.path, test.Answer),
 			points:     test.Points,
 			group:      test.Group,
+			sample:     test.Sample,
 		})
 	}
 	return tests, nil
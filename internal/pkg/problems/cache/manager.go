@@ -3,7 +3,6 @@ package cache
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -68,11 +67,6 @@ func (m *ProblemPackageManager) LoadSync(
 func (m *ProblemPackageManager) load(
 	ctx context.Context, fileID int64, kind problems.ProblemKind,
 ) (cache.Resource[problems.Problem], error) {
-	file, err := m.files.DownloadFile(ctx, fileID)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = file.Close() }()
 	pkg, err := m.newPackage()
 	if err != nil {
 		return nil, err
@@ -91,23 +85,9 @@ func (m *ProblemPackageManager) load(
 	if err := os.RemoveAll(tempPath); err != nil {
 		return nil, err
 	}
-	if v, ok := file.(*os.File); ok {
-		tempPath = v.Name()
-	} else {
-		tempFile, err := os.Create(tempPath)
-		if err != nil {
-			return nil, err
-		}
-		defer func() {
-			_ = tempFile.Close()
-			_ = os.RemoveAll(tempPath)
-		}()
-		if _, err := io.Copy(tempFile, file); err != nil {
-			return nil, err
-		}
-		if err := tempFile.Close(); err != nil {
-			return nil, err
-		}
+	defer func() { _ = os.RemoveAll(tempPath) }()
+	if err := m.files.DownloadVerifiedFile(ctx, fileID, tempPath); err != nil {
+		return nil, err
 	}
 	problem, err := extractProblem(kind, targetPath, tempPath)
 	if err != nil {
@@ -146,6 +126,14 @@ func (s problemPackageManagerStorage) Load(
 	return s.ProblemPackageManager.load(ctx, key.ID, key.Kind)
 }
 
+// ExtractProblem extracts problem package of specified kind from
+// archive at source path to target directory without caching.
+func ExtractProblem(
+	kind problems.ProblemKind, targetPath, sourcePath string,
+) (problems.Problem, error) {
+	return extractProblem(kind, targetPath, sourcePath)
+}
+
 func extractProblem(
 	kind problems.ProblemKind, targetPath, sourcePath string,
 ) (problems.Problem, error) {
@@ -163,6 +163,13 @@ func (p *polygonProblem) Compile(ctx context.Context, manager problems.CompileCo
 				return err
 			}
 		}
+		if e := p.getValidator(); e != nil {
+			if _, err := p.compileExecutable(
+				ctx, manager, executables, e.Source.Type, e.Source.Path, resources,
+			); err != nil {
+				return err
+			}
+		}
 	}
 	var mainSolution polygon.Solution
 	for _, solution := range p.config.Assets.Solutions {
@@ -378,9 +385,33 @@ func (p *polygonProblem) GetExecutables() ([]problems.ProblemExecutable, error)
 			compiler:   interactor.Source.Type,
 		})
 	}
+	if validator := p.getValidator(); validator != nil {
+		source := validator.Source.Path
+		target := strings.TrimSuffix(source, filepath.Ext(source))
+		targetPath := filepath.Join(p.path, target)
+		executables = append(executables, polygonProblemExecutable{
+			name:       "validator",
+			kind:       problems.TestlibValidator,
+			binaryPath: targetPath,
+			compiler:   validator.Source.Type,
+		})
+	}
 	return executables, nil
 }
 
+// getValidator returns first validator of problem with source.
+func (p *polygonProblem) getValidator() *polygon.Validator {
+	if p.config.Assets == nil {
+		return nil
+	}
+	for i, validator := range p.config.Assets.Validators {
+		if validator.Source != nil {
+			return &p.config.Assets.Validators[i]
+		}
+	}
+	return nil
+}
+
 type polygonProblemExecutable struct {
 	name       string
 	kind       problems.ProblemExecutableKind
@@ -455,11 +486,20 @@ func (g *polygonProblemTestSet) GetGroups() ([]problems.ProblemTestGroup, error)
 		groups = append(groups, problemTestGroup{
 			name:         group.Name,
 			pointsPolicy: getPolygonPointsPolicy(group.PointsPolicy),
+			pretests:     isPolygonPretestGroup(group.Name),
 		})
 	}
 	return groups, nil
 }
 
+// isPolygonPretestGroup returns true for groups with pretests.
+//
+// Polygon has no flag for pretests, so groups are marked as pretests
+// by name like on Codeforces.
+func isPolygonPretestGroup(name string) bool {
+	return strings.EqualFold(name, "pretests") || strings.EqualFold(name, "pretest")
+}
+
 func getPolygonPointsPolicy(policy string) problems.ProblemPointsPolicy {
 	switch policy {
 	case "each-test":
@@ -481,6 +521,7 @@ func (g *polygonProblemTestSet) GetTests() ([]problems.ProblemTest, error) {
 			answerPath: filepath.Join(g.problem.path, answer),
 			points:     g.config.Tests[i].Points,
 			group:      g.config.Tests[i].Group,
+			sample:     g.config.Tests[i].Sample,
 		})
 	}
 	return tests, nil
@@ -489,6 +530,7 @@ func (g *polygonProblemTestSet) GetTests() ([]problems.ProblemTest, error) {
 type problemTestGroup struct {
 	name         string
 	pointsPolicy problems.ProblemPointsPolicy
+	pretests     bool
 }
 
 func (g problemTestGroup) Name() string {
@@ -499,11 +541,16 @@ func (g problemTestGroup) PointsPolicy() problems.ProblemPointsPolicy {
 	return g.pointsPolicy
 }
 
+func (g problemTestGroup) Pretests() bool {
+	return g.pretests
+}
+
 type problemTest struct {
 	inputPath  string
 	answerPath string
 	points     float64
 	group      string
+	sample     bool
 }
 
 func (t problemTest) OpenInput() (*os.File, error) {
@@ -522,6 +569,10 @@ func (t problemTest) Group() string {
 	return t.group
 }
 
+func (t problemTest) Sample() bool {
+	return t.sample
+}
+
 type polygonProblemStatement struct {
 	problem  *polygonProblem
 	language string
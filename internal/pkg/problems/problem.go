@@ -21,6 +21,8 @@ type ProblemTest interface {
 	OpenAnswer() (*os.File, error)
 	Points() float64
 	Group() string
+	// Sample returns true for tests that are shown in statement.
+	Sample() bool
 }
 
 type ProblemExecutableKind string
@@ -28,6 +30,7 @@ type ProblemExecutableKind string
 const (
 	TestlibChecker    ProblemExecutableKind = "testlib_checker"
 	TestlibInteractor ProblemExecutableKind = "testlib_interactor"
+	TestlibValidator  ProblemExecutableKind = "testlib_validator"
 )
 
 type ProblemExecutable interface {
@@ -47,6 +50,9 @@ const (
 type ProblemTestGroup interface {
 	Name() string
 	PointsPolicy() ProblemPointsPolicy
+	// Pretests returns true for groups with tests that are used for
+	// judgement of solutions during contest before system testing.
+	Pretests() bool
 }
 
 type ProblemTestSet interface {
@@ -48,7 +48,7 @@ func (m *Manager) Create(ctx context.Context, config ProcessConfig) (*Process, e
 		// This should never have happened.
 		panic(fmt.Errorf("path %q is not absolute", workdir))
 	}
-	process, err := m.prepareProcess()
+	process, err := m.prepareProcess(getPartition(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -122,13 +122,13 @@ func (m *Manager) createProcessName() (string, error) {
 	return "", fmt.Errorf("cannot prepare process")
 }
 
-func (m *Manager) prepareProcess() (*Process, error) {
+func (m *Manager) prepareProcess(partition string) (*Process, error) {
 	name, err := m.createProcessName()
 	if err != nil {
 		return nil, err
 	}
 	path := filepath.Join(m.executionPath, name)
-	cgroupPath := filepath.Join(m.cgroupPath, name)
+	cgroupPath := filepath.Join(m.cgroupPath, partition, name)
 	if err := syscall.Rmdir(cgroupPath); err != nil {
 		if errno, ok := err.(syscall.Errno); !ok || errno != syscall.ENOENT {
 			return nil, err
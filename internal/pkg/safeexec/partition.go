@@ -0,0 +1,76 @@
+package safeexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Partition represents dedicated resources for processes.
+//
+// Processes of partition are placed into separate cgroup, so they
+// do not share CPU cores with processes of other partitions.
+type Partition struct {
+	// Name contains name of partition cgroup.
+	Name string
+	// CPUs contains list of CPU cores in cpuset format, for example "2-3,6".
+	//
+	// Empty value means that all CPU cores of parent cgroup are used.
+	CPUs string
+	// Memory contains total memory limit of partition in bytes.
+	//
+	// Zero value means that memory is not limited.
+	Memory int64
+}
+
+// SetupPartition creates cgroup for specified partition.
+func (m *Manager) SetupPartition(partition Partition) error {
+	if partition.Name == "" || partition.Name != filepath.Base(partition.Name) ||
+		strings.HasPrefix(partition.Name, ".") {
+		return fmt.Errorf("invalid partition name: %q", partition.Name)
+	}
+	cgroupPath := filepath.Join(m.cgroupPath, partition.Name)
+	if err := setupCgroup(cgroupPath); err != nil {
+		return err
+	}
+	if partition.CPUs != "" {
+		if err := writeCgroupFile(
+			cgroupPath, "cpuset.cpus", partition.CPUs,
+		); err != nil {
+			return fmt.Errorf("cannot setup partition cpuset: %w", err)
+		}
+	}
+	if partition.Memory > 0 {
+		if err := writeCgroupFile(
+			cgroupPath, "memory.max", fmt.Sprint(partition.Memory),
+		); err != nil {
+			return fmt.Errorf("cannot setup partition memory: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeCgroupFile(cgroupPath, name, value string) error {
+	file, err := os.OpenFile(filepath.Join(cgroupPath, name), os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	_, err = file.WriteString(value)
+	return err
+}
+
+type partitionKey struct{}
+
+// WithPartition returns context for processes that should be placed
+// into specified partition.
+func WithPartition(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, partitionKey{}, name)
+}
+
+func getPartition(ctx context.Context) string {
+	name, _ := ctx.Value(partitionKey{}).(string)
+	return name
+}
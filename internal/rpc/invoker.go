@@ -0,0 +1,290 @@
+// Package rpc implements gRPC services of Solve.
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/udovin/gosql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/rpc/invokerpb"
+)
+
+const (
+	// leaseDuration contains duration of task lease.
+	leaseDuration = 20 * time.Second
+	// defaultParticipantTasksLimit represents default limit of running
+	// judge tasks for single contest participant.
+	defaultParticipantTasksLimit = 2
+	// fileChunkSize contains maximal size of file chunk in stream.
+	fileChunkSize = 64 * 1024
+)
+
+// InvokerServer implements InvokerService.
+type InvokerServer struct {
+	invokerpb.UnimplementedInvokerServiceServer
+	core  *core.Core
+	files *managers.FileManager
+}
+
+// NewInvokerServer creates a new instance of InvokerServer.
+func NewInvokerServer(core *core.Core) *InvokerServer {
+	s := InvokerServer{
+		core: core,
+	}
+	if core.Config.Storage != nil {
+		s.files = managers.NewFileManager(core)
+	}
+	return &s
+}
+
+// NewServer creates gRPC server with registered invoker service.
+//
+// All calls require token from config.
+func NewServer(core *core.Core, cfg config.InvokerService) *grpc.Server {
+	auth := tokenAuth(cfg.Token)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unary),
+		grpc.StreamInterceptor(auth.stream),
+	)
+	invokerpb.RegisterInvokerServiceServer(srv, NewInvokerServer(core))
+	return srv
+}
+
+// LeaseTask pops queued task that is supported by invoker.
+func (s *InvokerServer) LeaseTask(
+	ctx context.Context, req *invokerpb.LeaseTaskRequest,
+) (*invokerpb.LeaseTaskResponse, error) {
+	kinds := map[models.TaskKind]struct{}{}
+	for _, kind := range req.GetKinds() {
+		kinds[models.TaskKind(kind)] = struct{}{}
+	}
+	filter := func(task models.Task) bool {
+		if _, ok := kinds[task.Kind]; !ok {
+			return false
+		}
+		if task.Kind == models.SelfTestInvokerTask {
+			var config models.SelfTestInvokerTaskConfig
+			if err := task.ScanConfig(&config); err != nil {
+				return false
+			}
+			return config.Invoker == "" || config.Invoker == req.GetInvoker()
+		}
+		return true
+	}
+	limit := int64(defaultParticipantTasksLimit)
+	if value, err := s.core.Settings.GetInt64("invoker.participant_tasks_limit"); err == nil {
+		limit = value.OrElse(limit)
+	}
+	task, err := s.core.Tasks.PopQueued(ctx, leaseDuration, int(max(limit, 0)), filter)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Error(codes.NotFound, "no queued tasks")
+		}
+		return nil, err
+	}
+	return &invokerpb.LeaseTaskResponse{
+		Task: &invokerpb.Task{
+			Id:         task.ID,
+			Kind:       int32(task.Kind),
+			Config:     task.Config,
+			State:      task.State,
+			ExpireTime: int64(task.ExpireTime),
+		},
+	}, nil
+}
+
+// ExtendTask extends lease of running task.
+func (s *InvokerServer) ExtendTask(
+	ctx context.Context, req *invokerpb.ExtendTaskRequest,
+) (*invokerpb.ExtendTaskResponse, error) {
+	var expireTime int64
+	if err := s.core.WrapTx(ctx, func(ctx context.Context) error {
+		task, err := s.getLeasedTask(ctx, req.GetTaskId())
+		if err != nil {
+			return err
+		}
+		task.ExpireTime = models.NInt64(time.Now().Add(leaseDuration).Unix())
+		if err := s.core.Tasks.Update(ctx, task); err != nil {
+			return err
+		}
+		expireTime = int64(task.ExpireTime)
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return nil, err
+	}
+	return &invokerpb.ExtendTaskResponse{ExpireTime: expireTime}, nil
+}
+
+// FetchFile streams content of file.
+func (s *InvokerServer) FetchFile(
+	req *invokerpb.FetchFileRequest, stream invokerpb.InvokerService_FetchFileServer,
+) error {
+	if s.files == nil {
+		return status.Error(codes.Unavailable, "storage is not configured")
+	}
+	ctx := stream.Context()
+	file, err := s.files.DownloadFile(ctx, req.GetFileId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Error(codes.NotFound, "file not found")
+		}
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	buf := make([]byte, fileChunkSize)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&invokerpb.FetchFileResponse{
+				Data: buf[:n],
+			}); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// SubmitReport updates state and status of running task.
+//
+// Report of solution is updated in the same transaction with task.
+func (s *InvokerServer) SubmitReport(
+	ctx context.Context, req *invokerpb.SubmitReportRequest,
+) (*invokerpb.SubmitReportResponse, error) {
+	taskStatus := models.TaskStatus(req.GetStatus())
+	switch taskStatus {
+	case models.RunningTask, models.SucceededTask, models.FailedTask:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid status: %s", taskStatus)
+	}
+	if state := req.GetState(); len(state) > 0 && !json.Valid(state) {
+		return nil, status.Error(codes.InvalidArgument, "invalid state")
+	}
+	if report := req.GetSolutionReport(); len(report) > 0 && !json.Valid(report) {
+		return nil, status.Error(codes.InvalidArgument, "invalid solution report")
+	}
+	if err := s.core.WrapTx(ctx, func(ctx context.Context) error {
+		task, err := s.getLeasedTask(ctx, req.GetTaskId())
+		if err != nil {
+			return err
+		}
+		if solutionID := req.GetSolutionId(); solutionID != 0 {
+			if err := s.updateSolutionReport(ctx, task, solutionID, req.GetSolutionReport()); err != nil {
+				return err
+			}
+		}
+		task.Status = taskStatus
+		if state := req.GetState(); len(state) > 0 {
+			task.State = state
+		}
+		return s.core.Tasks.Update(ctx, task)
+	}, sqlRepeatableRead); err != nil {
+		return nil, err
+	}
+	return &invokerpb.SubmitReportResponse{}, nil
+}
+
+func (s *InvokerServer) updateSolutionReport(
+	ctx context.Context, task models.Task, solutionID int64, report []byte,
+) error {
+	if task.Kind != models.JudgeSolutionTask {
+		return status.Error(codes.InvalidArgument, "task does not judge solution")
+	}
+	var config models.JudgeSolutionTaskConfig
+	if err := task.ScanConfig(&config); err != nil {
+		return err
+	}
+	if config.SolutionID != solutionID {
+		return status.Error(codes.InvalidArgument, "task judges another solution")
+	}
+	solution, err := s.core.Solutions.FindOne(ctx, db.FindQuery{
+		Where: gosql.Column("id").Equal(solutionID),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Error(codes.NotFound, "solution not found")
+		}
+		return err
+	}
+	solution.Report = report
+	return s.core.Solutions.Update(ctx, solution)
+}
+
+// getLeasedTask returns running task with not expired lease.
+//
+// Task is read from database, so it should be called in transaction.
+func (s *InvokerServer) getLeasedTask(ctx context.Context, id int64) (models.Task, error) {
+	task, err := s.core.Tasks.FindOne(ctx, db.FindQuery{
+		Where: gosql.Column("id").Equal(id),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Task{}, status.Error(codes.NotFound, "task not found")
+		}
+		return models.Task{}, err
+	}
+	if task.Status != models.RunningTask {
+		return models.Task{}, status.Error(codes.FailedPrecondition, "task is not running")
+	}
+	if time.Now().Unix() >= int64(task.ExpireTime) {
+		return models.Task{}, status.Error(codes.FailedPrecondition, "task is expired")
+	}
+	return task, nil
+}
+
+// tokenAuth checks token from "authorization" metadata.
+type tokenAuth string
+
+func (a tokenAuth) check(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && len(a) > 0 && subtle.ConstantTimeCompare([]byte(token), []byte(a)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid token")
+}
+
+func (a tokenAuth) unary(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a tokenAuth) stream(
+	srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	if err := a.check(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+var (
+	sqlRepeatableRead = gosql.WithIsolation(sql.LevelRepeatableRead)
+)
@@ -0,0 +1,268 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/udovin/solve/internal/config"
+	"github.com/udovin/solve/internal/core"
+	"github.com/udovin/solve/internal/db"
+	"github.com/udovin/solve/internal/managers"
+	"github.com/udovin/solve/internal/migrations"
+	"github.com/udovin/solve/internal/models"
+	"github.com/udovin/solve/internal/rpc/invokerpb"
+)
+
+const testToken = "secret"
+
+func testSetup(tb testing.TB) (*core.Core, invokerpb.InvokerServiceClient) {
+	c, err := core.NewCore(config.Config{
+		DB: config.DB{
+			Options: config.SQLiteOptions{Path: ":memory:"},
+		},
+		Storage: &config.Storage{
+			Options: config.LocalStorageOptions{
+				FilesDir: tb.TempDir(),
+			},
+		},
+	})
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	c.SetupAllStores()
+	if err := db.ApplyMigrations(context.Background(), c.DB, "solve", migrations.Schema); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := c.Start(); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	tb.Cleanup(c.Stop)
+	listener := bufconn.Listen(1024 * 1024)
+	srv := NewServer(c, config.InvokerService{Token: testToken})
+	go func() { _ = srv.Serve(listener) }()
+	tb.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	tb.Cleanup(func() { _ = conn.Close() })
+	return c, invokerpb.NewInvokerServiceClient(conn)
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func expectCode(tb testing.TB, code codes.Code, err error) {
+	tb.Helper()
+	if status.Code(err) != code {
+		tb.Fatalf("Expected code %s, got: %v", code, err)
+	}
+}
+
+// testCreateJudgeTask creates solution and queued task that judges it.
+func testCreateJudgeTask(tb testing.TB, c *core.Core) (models.Solution, models.Task) {
+	ctx := context.Background()
+	account := models.Account{Kind: models.UserAccountKind}
+	if err := c.Accounts.Create(ctx, &account); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem", OwnerID: models.NInt64(account.ID)}
+	if err := c.Problems.Create(ctx, &problem); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	image := models.File{Status: models.AvailableFile, Meta: models.JSON("{}")}
+	if err := c.Files.Create(ctx, &image); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{
+		Name:    "test",
+		OwnerID: models.NInt64(account.ID),
+		Config:  models.JSON("{}"),
+		ImageID: image.ID,
+	}
+	if err := c.Compilers.Create(ctx, &compiler); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		Kind:       models.ContestSolutionKind,
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   account.ID,
+	}
+	if err := c.Solutions.Create(ctx, &solution); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	task := models.Task{}
+	if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+		SolutionID: solution.ID,
+	}); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if err := c.Tasks.Create(ctx, &task); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	return solution, task
+}
+
+func TestInvokerServiceAuth(t *testing.T) {
+	_, client := testSetup(t)
+	ctx := context.Background()
+	_, err := client.LeaseTask(ctx, &invokerpb.LeaseTaskRequest{})
+	expectCode(t, codes.Unauthenticated, err)
+	_, err = client.LeaseTask(withToken(ctx, "invalid"), &invokerpb.LeaseTaskRequest{})
+	expectCode(t, codes.Unauthenticated, err)
+	stream, err := client.FetchFile(ctx, &invokerpb.FetchFileRequest{FileId: 1})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	_, err = stream.Recv()
+	expectCode(t, codes.Unauthenticated, err)
+}
+
+func TestInvokerServiceTask(t *testing.T) {
+	c, client := testSetup(t)
+	ctx := withToken(context.Background(), testToken)
+	solution, task := testCreateJudgeTask(t, c)
+	_, err := client.LeaseTask(ctx, &invokerpb.LeaseTaskRequest{
+		Kinds: []int32{int32(models.BuildCompilerImageTask)},
+	})
+	expectCode(t, codes.NotFound, err)
+	resp, err := client.LeaseTask(ctx, &invokerpb.LeaseTaskRequest{
+		Kinds: []int32{int32(models.JudgeSolutionTask)},
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if resp.Task.Id != task.ID {
+		t.Fatalf("Expected task %d, got %d", task.ID, resp.Task.Id)
+	}
+	if resp.Task.Kind != int32(models.JudgeSolutionTask) {
+		t.Fatalf("Expected kind %d, got %d", models.JudgeSolutionTask, resp.Task.Kind)
+	}
+	_, err = client.LeaseTask(ctx, &invokerpb.LeaseTaskRequest{
+		Kinds: []int32{int32(models.JudgeSolutionTask)},
+	})
+	expectCode(t, codes.NotFound, err)
+	if _, err := client.ExtendTask(ctx, &invokerpb.ExtendTaskRequest{
+		TaskId: task.ID,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := json.Marshal(models.SolutionReport{Verdict: models.Accepted})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	_, err = client.SubmitReport(ctx, &invokerpb.SubmitReportRequest{
+		TaskId:         task.ID,
+		Status:         int32(models.SucceededTask),
+		SolutionId:     solution.ID + 1,
+		SolutionReport: report,
+	})
+	expectCode(t, codes.InvalidArgument, err)
+	if _, err := client.SubmitReport(ctx, &invokerpb.SubmitReportRequest{
+		TaskId:         task.ID,
+		Status:         int32(models.SucceededTask),
+		State:          []byte(`{"stage":"finished"}`),
+		SolutionId:     solution.ID,
+		SolutionReport: report,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Tasks.Sync(context.Background()); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Solutions.Sync(context.Background()); err != nil {
+		t.Fatal("Error:", err)
+	}
+	updatedTask, err := c.Tasks.Get(context.Background(), task.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if updatedTask.Status != models.SucceededTask {
+		t.Fatalf("Expected status %s, got %s", models.SucceededTask, updatedTask.Status)
+	}
+	var state models.JudgeSolutionTaskState
+	if err := updatedTask.ScanState(&state); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if state.Stage != "finished" {
+		t.Fatalf("Expected stage %q, got %q", "finished", state.Stage)
+	}
+	updatedSolution, err := c.Solutions.Get(context.Background(), solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	solutionReport, err := updatedSolution.GetReport()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if solutionReport == nil || solutionReport.Verdict != models.Accepted {
+		t.Fatalf("Expected verdict %s, got %v", models.Accepted, solutionReport)
+	}
+	_, err = client.SubmitReport(ctx, &invokerpb.SubmitReportRequest{
+		TaskId: task.ID,
+		Status: int32(models.FailedTask),
+	})
+	expectCode(t, codes.FailedPrecondition, err)
+	_, err = client.ExtendTask(ctx, &invokerpb.ExtendTaskRequest{TaskId: task.ID})
+	expectCode(t, codes.FailedPrecondition, err)
+}
+
+func TestInvokerServiceFetchFile(t *testing.T) {
+	c, client := testSetup(t)
+	ctx := withToken(context.Background(), testToken)
+	content := bytes.Repeat([]byte("0123456789"), fileChunkSize/5)
+	files := managers.NewFileManager(c)
+	file, err := files.UploadFile(
+		context.Background(),
+		&managers.FileReader{Reader: bytes.NewReader(content)},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := files.ConfirmUploadFile(context.Background(), &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	stream, err := client.FetchFile(ctx, &invokerpb.FetchFileRequest{FileId: file.ID})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var data []byte
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		data = append(data, resp.Data...)
+	}
+	if !bytes.Equal(data, content) {
+		t.Fatalf("Expected %d bytes, got %d", len(content), len(data))
+	}
+	stream, err = client.FetchFile(ctx, &invokerpb.FetchFileRequest{FileId: file.ID + 100})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	_, err = stream.Recv()
+	expectCode(t, codes.NotFound, err)
+}
@@ -0,0 +1,268 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: invoker.proto
+
+package invokerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	InvokerService_LeaseTask_FullMethodName    = "/solve.invoker.v1.InvokerService/LeaseTask"
+	InvokerService_ExtendTask_FullMethodName   = "/solve.invoker.v1.InvokerService/ExtendTask"
+	InvokerService_FetchFile_FullMethodName    = "/solve.invoker.v1.InvokerService/FetchFile"
+	InvokerService_SubmitReport_FullMethodName = "/solve.invoker.v1.InvokerService/SubmitReport"
+)
+
+// InvokerServiceClient is the client API for InvokerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// InvokerService provides access to tasks and files for invokers.
+//
+// Invokers that use this service do not need access to database.
+type InvokerServiceClient interface {
+	// LeaseTask pops queued task and marks it as running.
+	LeaseTask(ctx context.Context, in *LeaseTaskRequest, opts ...grpc.CallOption) (*LeaseTaskResponse, error)
+	// ExtendTask extends lease of running task.
+	ExtendTask(ctx context.Context, in *ExtendTaskRequest, opts ...grpc.CallOption) (*ExtendTaskResponse, error)
+	// FetchFile streams content of file.
+	FetchFile(ctx context.Context, in *FetchFileRequest, opts ...grpc.CallOption) (InvokerService_FetchFileClient, error)
+	// SubmitReport updates state and status of running task.
+	SubmitReport(ctx context.Context, in *SubmitReportRequest, opts ...grpc.CallOption) (*SubmitReportResponse, error)
+}
+
+type invokerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInvokerServiceClient(cc grpc.ClientConnInterface) InvokerServiceClient {
+	return &invokerServiceClient{cc}
+}
+
+func (c *invokerServiceClient) LeaseTask(ctx context.Context, in *LeaseTaskRequest, opts ...grpc.CallOption) (*LeaseTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseTaskResponse)
+	err := c.cc.Invoke(ctx, InvokerService_LeaseTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invokerServiceClient) ExtendTask(ctx context.Context, in *ExtendTaskRequest, opts ...grpc.CallOption) (*ExtendTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtendTaskResponse)
+	err := c.cc.Invoke(ctx, InvokerService_ExtendTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invokerServiceClient) FetchFile(ctx context.Context, in *FetchFileRequest, opts ...grpc.CallOption) (InvokerService_FetchFileClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InvokerService_ServiceDesc.Streams[0], InvokerService_FetchFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invokerServiceFetchFileClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvokerService_FetchFileClient interface {
+	Recv() (*FetchFileResponse, error)
+	grpc.ClientStream
+}
+
+type invokerServiceFetchFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *invokerServiceFetchFileClient) Recv() (*FetchFileResponse, error) {
+	m := new(FetchFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invokerServiceClient) SubmitReport(ctx context.Context, in *SubmitReportRequest, opts ...grpc.CallOption) (*SubmitReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitReportResponse)
+	err := c.cc.Invoke(ctx, InvokerService_SubmitReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvokerServiceServer is the server API for InvokerService service.
+// All implementations must embed UnimplementedInvokerServiceServer
+// for forward compatibility
+//
+// InvokerService provides access to tasks and files for invokers.
+//
+// Invokers that use this service do not need access to database.
+type InvokerServiceServer interface {
+	// LeaseTask pops queued task and marks it as running.
+	LeaseTask(context.Context, *LeaseTaskRequest) (*LeaseTaskResponse, error)
+	// ExtendTask extends lease of running task.
+	ExtendTask(context.Context, *ExtendTaskRequest) (*ExtendTaskResponse, error)
+	// FetchFile streams content of file.
+	FetchFile(*FetchFileRequest, InvokerService_FetchFileServer) error
+	// SubmitReport updates state and status of running task.
+	SubmitReport(context.Context, *SubmitReportRequest) (*SubmitReportResponse, error)
+	mustEmbedUnimplementedInvokerServiceServer()
+}
+
+// UnimplementedInvokerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInvokerServiceServer struct {
+}
+
+func (UnimplementedInvokerServiceServer) LeaseTask(context.Context, *LeaseTaskRequest) (*LeaseTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseTask not implemented")
+}
+func (UnimplementedInvokerServiceServer) ExtendTask(context.Context, *ExtendTaskRequest) (*ExtendTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendTask not implemented")
+}
+func (UnimplementedInvokerServiceServer) FetchFile(*FetchFileRequest, InvokerService_FetchFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method FetchFile not implemented")
+}
+func (UnimplementedInvokerServiceServer) SubmitReport(context.Context, *SubmitReportRequest) (*SubmitReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitReport not implemented")
+}
+func (UnimplementedInvokerServiceServer) mustEmbedUnimplementedInvokerServiceServer() {}
+
+// UnsafeInvokerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InvokerServiceServer will
+// result in compilation errors.
+type UnsafeInvokerServiceServer interface {
+	mustEmbedUnimplementedInvokerServiceServer()
+}
+
+func RegisterInvokerServiceServer(s grpc.ServiceRegistrar, srv InvokerServiceServer) {
+	s.RegisterService(&InvokerService_ServiceDesc, srv)
+}
+
+func _InvokerService_LeaseTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvokerServiceServer).LeaseTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvokerService_LeaseTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvokerServiceServer).LeaseTask(ctx, req.(*LeaseTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvokerService_ExtendTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvokerServiceServer).ExtendTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvokerService_ExtendTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvokerServiceServer).ExtendTask(ctx, req.(*ExtendTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvokerService_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvokerServiceServer).FetchFile(m, &invokerServiceFetchFileServer{ServerStream: stream})
+}
+
+type InvokerService_FetchFileServer interface {
+	Send(*FetchFileResponse) error
+	grpc.ServerStream
+}
+
+type invokerServiceFetchFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *invokerServiceFetchFileServer) Send(m *FetchFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvokerService_SubmitReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvokerServiceServer).SubmitReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvokerService_SubmitReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvokerServiceServer).SubmitReport(ctx, req.(*SubmitReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InvokerService_ServiceDesc is the grpc.ServiceDesc for InvokerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InvokerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "solve.invoker.v1.InvokerService",
+	HandlerType: (*InvokerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "LeaseTask",
+			Handler:    _InvokerService_LeaseTask_Handler,
+		},
+		{
+			MethodName: "ExtendTask",
+			Handler:    _InvokerService_ExtendTask_Handler,
+		},
+		{
+			MethodName: "SubmitReport",
+			Handler:    _InvokerService_SubmitReport_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchFile",
+			Handler:       _InvokerService_FetchFile_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "invoker.proto",
+}
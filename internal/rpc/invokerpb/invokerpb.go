@@ -0,0 +1,4 @@
+// Package invokerpb contains generated code of invoker service.
+package invokerpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative invoker.proto